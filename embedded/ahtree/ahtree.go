@@ -89,18 +89,20 @@ func Open(path string, opts *Options) (*AHtree, error) {
 		return nil, err
 	}
 
-	finfo, err := os.Stat(path)
-	if err != nil {
-		if !os.IsNotExist(err) {
-			return nil, err
-		}
-
-		err := os.Mkdir(path, opts.fileMode)
+	if !opts.inMemory {
+		finfo, err := os.Stat(path)
 		if err != nil {
-			return nil, err
+			if !os.IsNotExist(err) {
+				return nil, err
+			}
+
+			err := os.Mkdir(path, opts.fileMode)
+			if err != nil {
+				return nil, err
+			}
+		} else if !finfo.IsDir() {
+			return nil, fmt.Errorf("%w: '%s'", ErrorPathIsNotADirectory, path)
 		}
-	} else if !finfo.IsDir() {
-		return nil, fmt.Errorf("%w: '%s'", ErrorPathIsNotADirectory, path)
 	}
 
 	metadata := appendable.NewMetadata(nil)
@@ -49,6 +49,7 @@ type Options struct {
 	syncThld        int  // sync after appending the specified amount of values
 
 	fileMode os.FileMode
+	inMemory bool
 
 	appFactory AppFactoryFunc
 
@@ -111,6 +112,10 @@ func (opts *Options) Validate() error {
 		return fmt.Errorf("%w: invalid syncThld", ErrInvalidOptions)
 	}
 
+	if opts.inMemory && opts.appFactory == nil {
+		return fmt.Errorf("%w: an appFactory is required when inMemory is set", ErrInvalidOptions)
+	}
+
 	return nil
 }
 
@@ -178,3 +183,10 @@ func (opts *Options) WithAppFactory(appFactory AppFactoryFunc) *Options {
 	opts.appFactory = appFactory
 	return opts
 }
+
+// WithInMemory makes the tree not to create its folder, all the data is held
+// in the appendables provided by the appFactory
+func (opts *Options) WithInMemory(inMemory bool) *Options {
+	opts.inMemory = inMemory
+	return opts
+}
@@ -37,6 +37,7 @@ func TestInvalidOptions(t *testing.T) {
 		{"ReadBufferSize", DefaultOptions().WithReadBufferSize(0)},
 		{"SyncThld", DefaultOptions().WithReadOnly(false).WithSyncThld(0)},
 		{"WriteBufferSize", DefaultOptions().WithReadOnly(false).WithWriteBufferSize(0)},
+		{"InMemory", DefaultOptions().WithInMemory(true)},
 	} {
 		t.Run(d.n, func(t *testing.T) {
 			require.ErrorIs(t, d.opts.Validate(), ErrInvalidOptions)
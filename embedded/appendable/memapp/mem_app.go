@@ -0,0 +1,302 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package memapp provides appendables whose contents are only held in memory.
+// It's meant to be used when durability is not required e.g. for fast tests.
+package memapp
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/codenotary/immudb/embedded/appendable"
+	"github.com/codenotary/immudb/embedded/appendable/multiapp"
+	"github.com/codenotary/immudb/embedded/appendable/singleapp"
+)
+
+// Storage holds the contents of in-memory appendables by path, so that an appendable
+// can be reopened after being closed. Each storage is independent from any other one.
+type Storage struct {
+	mutex sync.Mutex
+	apps  map[string]*appData
+}
+
+type appData struct {
+	metadata []byte
+	data     []byte
+
+	// mutex is shared by every appendable opened on the same path
+	mutex sync.Mutex
+}
+
+func NewStorage() *Storage {
+	return &Storage{
+		apps: make(map[string]*appData),
+	}
+}
+
+// Open returns the in-memory appendable stored at the given path, it is created if
+// it does not exist. Its signature matches the one of the app factories used by the store
+// and the indexes.
+func (s *Storage) Open(rootPath, subPath string, opts *multiapp.Options) (appendable.Appendable, error) {
+	if opts == nil {
+		return nil, fmt.Errorf("%w: nil options", singleapp.ErrIllegalArguments)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	path := filepath.Join(rootPath, subPath)
+
+	app, ok := s.apps[path]
+	if !ok {
+		if opts.GetReadOnly() {
+			return nil, fmt.Errorf("%w: appendable '%s' does not exist", singleapp.ErrIllegalArguments, path)
+		}
+
+		app = &appData{metadata: opts.GetMetadata()}
+		s.apps[path] = app
+	}
+
+	return &AppendableMemory{
+		storage:  s,
+		app:      app,
+		readOnly: opts.GetReadOnly(),
+	}, nil
+}
+
+// Remove deletes every appendable stored at the given path or under it.
+func (s *Storage) Remove(rootPath, subPath string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	path := filepath.Join(rootPath, subPath)
+
+	for appPath := range s.apps {
+		if appPath == path || strings.HasPrefix(appPath, path+string(filepath.Separator)) {
+			delete(s.apps, appPath)
+		}
+	}
+
+	return nil
+}
+
+type AppendableMemory struct {
+	storage *Storage
+	app     *appData
+
+	readOnly bool
+	closed   bool
+}
+
+func (a *AppendableMemory) Metadata() []byte {
+	return a.app.metadata
+}
+
+func (a *AppendableMemory) Size() (int64, error) {
+	a.app.mutex.Lock()
+	defer a.app.mutex.Unlock()
+
+	if a.closed {
+		return 0, singleapp.ErrAlreadyClosed
+	}
+
+	return int64(len(a.app.data)), nil
+}
+
+func (a *AppendableMemory) Offset() int64 {
+	a.app.mutex.Lock()
+	defer a.app.mutex.Unlock()
+
+	return int64(len(a.app.data))
+}
+
+func (a *AppendableMemory) SetOffset(newOffset int64) error {
+	a.app.mutex.Lock()
+	defer a.app.mutex.Unlock()
+
+	if a.closed {
+		return singleapp.ErrAlreadyClosed
+	}
+
+	if a.readOnly {
+		return singleapp.ErrReadOnly
+	}
+
+	if newOffset < 0 {
+		return singleapp.ErrNegativeOffset
+	}
+
+	if newOffset > int64(len(a.app.data)) {
+		return fmt.Errorf("%w: provided offset %d is bigger than current one %d", singleapp.ErrIllegalArguments, newOffset, len(a.app.data))
+	}
+
+	a.app.data = a.app.data[:newOffset]
+
+	return nil
+}
+
+// DiscardUpto only validates the offset, as data is addressed by its offset
+// it is kept in memory until the appendable gets removed from its storage.
+func (a *AppendableMemory) DiscardUpto(off int64) error {
+	a.app.mutex.Lock()
+	defer a.app.mutex.Unlock()
+
+	if a.closed {
+		return singleapp.ErrAlreadyClosed
+	}
+
+	if int64(len(a.app.data)) < off {
+		return fmt.Errorf("%w: discard beyond existent data boundaries", singleapp.ErrIllegalArguments)
+	}
+
+	return nil
+}
+
+func (a *AppendableMemory) Append(bs []byte) (off int64, n int, err error) {
+	a.app.mutex.Lock()
+	defer a.app.mutex.Unlock()
+
+	if a.closed {
+		return 0, 0, singleapp.ErrAlreadyClosed
+	}
+
+	if a.readOnly {
+		return 0, 0, singleapp.ErrReadOnly
+	}
+
+	if len(bs) == 0 {
+		return 0, 0, singleapp.ErrIllegalArguments
+	}
+
+	off = int64(len(a.app.data))
+	a.app.data = append(a.app.data, bs...)
+
+	return off, len(bs), nil
+}
+
+func (a *AppendableMemory) Flush() error {
+	return a.checkWritable()
+}
+
+func (a *AppendableMemory) Sync() error {
+	return a.checkWritable()
+}
+
+func (a *AppendableMemory) checkWritable() error {
+	a.app.mutex.Lock()
+	defer a.app.mutex.Unlock()
+
+	if a.closed {
+		return singleapp.ErrAlreadyClosed
+	}
+
+	if a.readOnly {
+		return singleapp.ErrReadOnly
+	}
+
+	return nil
+}
+
+func (a *AppendableMemory) SwitchToReadOnlyMode() error {
+	a.app.mutex.Lock()
+	defer a.app.mutex.Unlock()
+
+	if a.closed {
+		return singleapp.ErrAlreadyClosed
+	}
+
+	if a.readOnly {
+		return singleapp.ErrReadOnly
+	}
+
+	a.readOnly = true
+
+	return nil
+}
+
+func (a *AppendableMemory) ReadAt(bs []byte, off int64) (int, error) {
+	a.app.mutex.Lock()
+	defer a.app.mutex.Unlock()
+
+	if a.closed {
+		return 0, singleapp.ErrAlreadyClosed
+	}
+
+	if bs == nil {
+		return 0, singleapp.ErrIllegalArguments
+	}
+
+	if off < 0 {
+		return 0, singleapp.ErrNegativeOffset
+	}
+
+	if off > int64(len(a.app.data)) {
+		return 0, io.EOF
+	}
+
+	n := copy(bs, a.app.data[off:])
+	if n < len(bs) {
+		return n, io.EOF
+	}
+
+	return n, nil
+}
+
+// Copy stores a copy of the contents of the appendable at dstPath of the same storage.
+func (a *AppendableMemory) Copy(dstPath string) error {
+	a.app.mutex.Lock()
+	defer a.app.mutex.Unlock()
+
+	if a.closed {
+		return singleapp.ErrAlreadyClosed
+	}
+
+	a.storage.mutex.Lock()
+	defer a.storage.mutex.Unlock()
+
+	a.storage.apps[filepath.Clean(dstPath)] = &appData{
+		metadata: a.app.metadata,
+		data:     append([]byte(nil), a.app.data...),
+	}
+
+	return nil
+}
+
+func (a *AppendableMemory) Close() error {
+	a.app.mutex.Lock()
+	defer a.app.mutex.Unlock()
+
+	if a.closed {
+		return singleapp.ErrAlreadyClosed
+	}
+
+	a.closed = true
+
+	return nil
+}
+
+// CompressionFormat returns NoCompression as data is held as is in memory
+func (a *AppendableMemory) CompressionFormat() int {
+	return appendable.NoCompression
+}
+
+func (a *AppendableMemory) CompressionLevel() int {
+	return appendable.DefaultCompressionLevel
+}
@@ -0,0 +1,158 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package memapp
+
+import (
+	"io"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/appendable/multiapp"
+	"github.com/codenotary/immudb/embedded/appendable/singleapp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppendableMemory(t *testing.T) {
+	storage := NewStorage()
+
+	_, err := storage.Open("root", "app", nil)
+	require.ErrorIs(t, err, singleapp.ErrIllegalArguments)
+
+	_, err = storage.Open("root", "app", multiapp.DefaultOptions().WithReadOnly(true))
+	require.ErrorIs(t, err, singleapp.ErrIllegalArguments)
+
+	app, err := storage.Open("root", "app", multiapp.DefaultOptions().WithMetadata([]byte{1, 2, 3}))
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 2, 3}, app.Metadata())
+	require.Equal(t, 0, app.CompressionFormat())
+
+	_, _, err = app.Append(nil)
+	require.ErrorIs(t, err, singleapp.ErrIllegalArguments)
+
+	off, n, err := app.Append([]byte("hello"))
+	require.NoError(t, err)
+	require.Zero(t, off)
+	require.Equal(t, 5, n)
+
+	off, _, err = app.Append([]byte(" world"))
+	require.NoError(t, err)
+	require.EqualValues(t, 5, off)
+	require.EqualValues(t, 11, app.Offset())
+
+	bs := make([]byte, 5)
+	_, err = app.ReadAt(bs, 6)
+	require.NoError(t, err)
+	require.Equal(t, []byte("world"), bs)
+
+	n, err = app.ReadAt(bs, 8)
+	require.ErrorIs(t, err, io.EOF)
+	require.Equal(t, 3, n)
+
+	_, err = app.ReadAt(bs, -1)
+	require.ErrorIs(t, err, singleapp.ErrNegativeOffset)
+
+	err = app.SetOffset(12)
+	require.ErrorIs(t, err, singleapp.ErrIllegalArguments)
+
+	err = app.SetOffset(5)
+	require.NoError(t, err)
+
+	size, err := app.Size()
+	require.NoError(t, err)
+	require.EqualValues(t, 5, size)
+
+	err = app.DiscardUpto(6)
+	require.ErrorIs(t, err, singleapp.ErrIllegalArguments)
+
+	require.NoError(t, app.DiscardUpto(5))
+	require.NoError(t, app.Flush())
+	require.NoError(t, app.Sync())
+
+	err = app.Copy("root/copy")
+	require.NoError(t, err)
+
+	err = app.Close()
+	require.NoError(t, err)
+
+	err = app.Close()
+	require.ErrorIs(t, err, singleapp.ErrAlreadyClosed)
+
+	_, _, err = app.Append([]byte("x"))
+	require.ErrorIs(t, err, singleapp.ErrAlreadyClosed)
+
+	t.Run("data is kept after reopening", func(t *testing.T) {
+		app, err := storage.Open("root", "app", multiapp.DefaultOptions().WithReadOnly(true))
+		require.NoError(t, err)
+		defer app.Close()
+
+		_, _, err = app.Append([]byte("x"))
+		require.ErrorIs(t, err, singleapp.ErrReadOnly)
+
+		err = app.Flush()
+		require.ErrorIs(t, err, singleapp.ErrReadOnly)
+
+		bs := make([]byte, 5)
+		_, err = app.ReadAt(bs, 0)
+		require.NoError(t, err)
+		require.Equal(t, []byte("hello"), bs)
+	})
+
+	t.Run("copies are independent", func(t *testing.T) {
+		cp, err := storage.Open("root", "copy", multiapp.DefaultOptions())
+		require.NoError(t, err)
+		defer cp.Close()
+
+		_, _, err = cp.Append([]byte("!"))
+		require.NoError(t, err)
+		require.EqualValues(t, 6, cp.Offset())
+
+		app, err := storage.Open("root", "app", multiapp.DefaultOptions())
+		require.NoError(t, err)
+		defer app.Close()
+
+		require.EqualValues(t, 5, app.Offset())
+	})
+
+	t.Run("storages are isolated", func(t *testing.T) {
+		_, err := NewStorage().Open("root", "app", multiapp.DefaultOptions().WithReadOnly(true))
+		require.ErrorIs(t, err, singleapp.ErrIllegalArguments)
+	})
+
+	t.Run("remove deletes nested appendables", func(t *testing.T) {
+		_, err := storage.Open("root", "other", multiapp.DefaultOptions())
+		require.NoError(t, err)
+
+		err = storage.Remove("root", "")
+		require.NoError(t, err)
+
+		for _, subPath := range []string{"app", "copy", "other"} {
+			_, err = storage.Open("root", subPath, multiapp.DefaultOptions().WithReadOnly(true))
+			require.ErrorIs(t, err, singleapp.ErrIllegalArguments)
+		}
+	})
+
+	t.Run("switch to read-only mode", func(t *testing.T) {
+		app, err := storage.Open("root", "app", multiapp.DefaultOptions())
+		require.NoError(t, err)
+		defer app.Close()
+
+		require.NoError(t, app.SwitchToReadOnlyMode())
+		require.ErrorIs(t, app.SwitchToReadOnlyMode(), singleapp.ErrReadOnly)
+
+		err = app.SetOffset(0)
+		require.ErrorIs(t, err, singleapp.ErrReadOnly)
+	})
+}
@@ -177,3 +177,11 @@ func (opts *Options) GetWriteBufferSize() int {
 func (opts *Options) GetPrealloc() bool {
 	return opts.prealloc
 }
+
+func (opts *Options) GetReadOnly() bool {
+	return opts.readOnly
+}
+
+func (opts *Options) GetMetadata() []byte {
+	return opts.metadata
+}
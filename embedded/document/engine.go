@@ -1246,9 +1246,7 @@ func (e *Engine) getKeyForDocument(ctx context.Context, sqlTx *sql.SQLTx, collec
 
 	searchKey = sql.MapKey(
 		e.sqlEngine.GetPrefix(),
-		sql.MappedPrefix,
-		sql.EncodeID(table.ID()),
-		sql.EncodeID(table.PrimaryIndex().ID()),
+		string(e.sqlEngine.KeyEncoder().IndexPrefix(table.ID(), table.PrimaryIndex().ID())),
 		pkEncVals,
 		pkEncVals,
 	)
@@ -16,7 +16,11 @@ limitations under the License.
 
 package sql
 
-import "strconv"
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
 
 type AggregatedValue interface {
 	TypedValue
@@ -99,6 +103,10 @@ func (v *CountValue) substitute(params map[string]interface{}) (ValueExp, error)
 }
 
 func (v *CountValue) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return v.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (v *CountValue) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
 	return nil, ErrUnexpected
 }
 
@@ -119,8 +127,9 @@ func (v *CountValue) selectorRanges(table *Table, asTable string, params map[str
 }
 
 type SumValue struct {
-	val TypedValue
-	sel string
+	val      TypedValue
+	sel      string
+	overflow IntegerOverflow
 }
 
 func (v *SumValue) Selector() string {
@@ -167,7 +176,7 @@ func (v *SumValue) updateWith(val TypedValue) error {
 		return nil
 	}
 
-	newVal, err := applyNumOperator(ADDOP, v.val, val)
+	newVal, err := applyNumOperator(ADDOP, v.val, val, v.overflow)
 	if err != nil {
 		return err
 	}
@@ -199,6 +208,10 @@ func (v *SumValue) substitute(params map[string]interface{}) (ValueExp, error) {
 }
 
 func (v *SumValue) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return v.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (v *SumValue) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
 	return nil, ErrUnexpected
 }
 
@@ -306,6 +319,10 @@ func (v *MinValue) substitute(params map[string]interface{}) (ValueExp, error) {
 }
 
 func (v *MinValue) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return v.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (v *MinValue) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
 	return nil, ErrUnexpected
 }
 
@@ -413,6 +430,10 @@ func (v *MaxValue) substitute(params map[string]interface{}) (ValueExp, error) {
 }
 
 func (v *MaxValue) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return v.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (v *MaxValue) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
 	return nil, ErrUnexpected
 }
 
@@ -433,9 +454,10 @@ func (v *MaxValue) selectorRanges(table *Table, asTable string, params map[strin
 }
 
 type AVGValue struct {
-	s   TypedValue
-	c   int64
-	sel string
+	s        TypedValue
+	c        int64
+	sel      string
+	overflow IntegerOverflow
 }
 
 func (v *AVGValue) Selector() string {
@@ -463,7 +485,7 @@ func (v *AVGValue) calculate() TypedValue {
 		return nil
 	}
 
-	val, err := applyNumOperator(DIVOP, v.s, &Integer{val: v.c})
+	val, err := applyNumOperator(DIVOP, v.s, &Integer{val: v.c}, v.overflow)
 	if err != nil {
 		return &NullValue{t: AnyType}
 	}
@@ -496,7 +518,7 @@ func (v *AVGValue) updateWith(val TypedValue) error {
 		return nil
 	}
 
-	newVal, err := applyNumOperator(ADDOP, v.s, val)
+	newVal, err := applyNumOperator(ADDOP, v.s, val, v.overflow)
 	if err != nil {
 		return err
 	}
@@ -529,6 +551,10 @@ func (v *AVGValue) substitute(params map[string]interface{}) (ValueExp, error) {
 }
 
 func (v *AVGValue) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return v.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (v *AVGValue) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
 	return nil, ErrUnexpected
 }
 
@@ -547,3 +573,192 @@ func (v *AVGValue) isConstant() bool {
 func (v *AVGValue) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
 	return nil
 }
+
+// AggregateFunc describes a user-defined aggregation which can be registered
+// by calling Engine.RegisterAggregate and then used as any built-in aggregation.
+type AggregateFunc struct {
+	// ArgType is the type of the aggregated column, AnyType accepts columns of any type
+	ArgType SQLValueType
+	// ReturnType is the type of the values returned by Finalize
+	ReturnType SQLValueType
+	// Init returns the initial state of a new aggregation
+	Init func() interface{}
+	// Accumulate returns the state resulting from adding a value to the aggregation. NULL values are skipped
+	Accumulate func(state interface{}, val TypedValue) (interface{}, error)
+	// Merge returns the state resulting from combining two partial aggregations
+	Merge func(state, other interface{}) (interface{}, error)
+	// Finalize calculates the result of the aggregation from its state
+	Finalize func(state interface{}) (TypedValue, error)
+}
+
+func (f *AggregateFunc) validate() error {
+	if f == nil || f.Init == nil || f.Accumulate == nil || f.Merge == nil || f.Finalize == nil {
+		return fmt.Errorf("%w: aggregate init, accumulate, merge and finalize must be specified", ErrIllegalArguments)
+	}
+
+	if !isValidFunctionType(f.ReturnType) || f.ReturnType == AnyType {
+		return fmt.Errorf("%w: invalid return type '%s'", ErrIllegalArguments, f.ReturnType)
+	}
+
+	if !isValidFunctionType(f.ArgType) {
+		return fmt.Errorf("%w: invalid argument type '%s'", ErrIllegalArguments, f.ArgType)
+	}
+	return nil
+}
+
+type userAggregate struct {
+	name string
+	spec AggregateFunc
+}
+
+func (agg *userAggregate) newValue(sel string) *UserAggregateValue {
+	return &UserAggregateValue{
+		agg:   agg,
+		state: agg.spec.Init(),
+		sel:   sel,
+	}
+}
+
+// UserAggregateValue holds the state of a user-defined aggregation.
+// Its value is only available once the aggregation has been finalized.
+type UserAggregateValue struct {
+	agg   *userAggregate
+	state interface{}
+	sel   string
+
+	val TypedValue
+}
+
+func (v *UserAggregateValue) Selector() string {
+	return v.sel
+}
+
+func (v *UserAggregateValue) ColBounded() bool {
+	return true
+}
+
+func (v *UserAggregateValue) value() TypedValue {
+	if v.val == nil {
+		return NewNull(v.agg.spec.ReturnType)
+	}
+	return v.val
+}
+
+func (v *UserAggregateValue) Type() SQLValueType {
+	return v.agg.spec.ReturnType
+}
+
+func (v *UserAggregateValue) IsNull() bool {
+	return v.value().IsNull()
+}
+
+func (v *UserAggregateValue) String() string {
+	return v.value().String()
+}
+
+func (v *UserAggregateValue) RawValue() interface{} {
+	return v.value().RawValue()
+}
+
+func (v *UserAggregateValue) Compare(val TypedValue) (int, error) {
+	return v.value().Compare(val)
+}
+
+func (v *UserAggregateValue) updateWith(val TypedValue) error {
+	if val.IsNull() {
+		// Skip NULL values
+		return nil
+	}
+
+	if v.agg.spec.ArgType != AnyType && val.Type() != v.agg.spec.ArgType {
+		return fmt.Errorf("%w: '%s' aggregate expects values of type %s but %s was provided", ErrInvalidTypes, v.agg.name, v.agg.spec.ArgType, val.Type())
+	}
+
+	state, err := v.agg.spec.Accumulate(v.state, val)
+	if err != nil {
+		return fmt.Errorf("%s: %w", v.agg.name, err)
+	}
+
+	v.state = state
+
+	return nil
+}
+
+func (v *UserAggregateValue) mergeWith(other *UserAggregateValue) error {
+	if other.agg != v.agg {
+		return fmt.Errorf("%w: '%s' and '%s' aggregations can not be merged", ErrIllegalArguments, v.agg.name, other.agg.name)
+	}
+
+	state, err := v.agg.spec.Merge(v.state, other.state)
+	if err != nil {
+		return fmt.Errorf("%s: %w", v.agg.name, err)
+	}
+
+	v.state = state
+
+	return nil
+}
+
+func (v *UserAggregateValue) finalize() (TypedValue, error) {
+	val, err := v.agg.spec.Finalize(v.state)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", v.agg.name, err)
+	}
+
+	if val == nil {
+		val = NewNull(v.agg.spec.ReturnType)
+	}
+
+	if !val.IsNull() && v.agg.spec.ReturnType != AnyType && val.Type() != v.agg.spec.ReturnType {
+		return nil, fmt.Errorf("%w: '%s' aggregate returned a value of type %s but %s was expected", ErrInvalidTypes, v.agg.name, val.Type(), v.agg.spec.ReturnType)
+	}
+
+	v.val = val
+
+	return val, nil
+}
+
+// ValueExp
+
+func (v *UserAggregateValue) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return v.agg.spec.ReturnType, nil
+}
+
+func (v *UserAggregateValue) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != v.agg.spec.ReturnType {
+		return ErrNotComparableValues
+	}
+	return nil
+}
+
+func (v *UserAggregateValue) jointColumnTo(col *Column, tableAlias string) (*ColSelector, error) {
+	return nil, ErrUnexpected
+}
+
+func (v *UserAggregateValue) substitute(params map[string]interface{}) (ValueExp, error) {
+	return nil, ErrUnexpected
+}
+
+func (v *UserAggregateValue) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return v.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (v *UserAggregateValue) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return nil, ErrUnexpected
+}
+
+func (v *UserAggregateValue) selectors() []Selector {
+	return nil
+}
+
+func (v *UserAggregateValue) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	return nil
+}
+
+func (v *UserAggregateValue) isConstant() bool {
+	return false
+}
+
+func (v *UserAggregateValue) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
+}
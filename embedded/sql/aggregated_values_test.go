@@ -342,3 +342,111 @@ func TestAVGValue(t *testing.T) {
 
 	require.Nil(t, cval.selectorRanges(nil, "", nil, nil))
 }
+
+func productAggregate() AggregateFunc {
+	return AggregateFunc{
+		ArgType:    IntegerType,
+		ReturnType: IntegerType,
+		Init: func() interface{} {
+			return int64(1)
+		},
+		Accumulate: func(state interface{}, val TypedValue) (interface{}, error) {
+			return state.(int64) * val.RawValue().(int64), nil
+		},
+		Merge: func(state, other interface{}) (interface{}, error) {
+			return state.(int64) * other.(int64), nil
+		},
+		Finalize: func(state interface{}) (TypedValue, error) {
+			return NewInteger(state.(int64)), nil
+		},
+	}
+}
+
+func TestUserAggregateValue(t *testing.T) {
+	agg := &userAggregate{name: "PRODUCT", spec: productAggregate()}
+
+	cval := agg.newValue("(table1.amount)")
+	require.Equal(t, "(table1.amount)", cval.Selector())
+	require.True(t, cval.ColBounded())
+	require.Equal(t, IntegerType, cval.Type())
+	require.True(t, cval.IsNull())
+
+	err := cval.updateWith(&Integer{val: 2})
+	require.NoError(t, err)
+
+	err = cval.updateWith(&NullValue{t: IntegerType})
+	require.NoError(t, err)
+
+	err = cval.updateWith(&Varchar{val: "3"})
+	require.ErrorIs(t, err, ErrInvalidTypes)
+
+	err = cval.updateWith(&Integer{val: 3})
+	require.NoError(t, err)
+
+	partial := agg.newValue("(table1.amount)")
+
+	err = partial.updateWith(&Integer{val: 5})
+	require.NoError(t, err)
+
+	err = cval.mergeWith(partial)
+	require.NoError(t, err)
+
+	err = cval.mergeWith((&userAggregate{name: "OTHER", spec: productAggregate()}).newValue(""))
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	val, err := cval.finalize()
+	require.NoError(t, err)
+	require.Equal(t, int64(30), val.RawValue())
+	require.Equal(t, "30", cval.String())
+	require.False(t, cval.IsNull())
+
+	cmp, err := cval.Compare(&Integer{val: 30})
+	require.NoError(t, err)
+	require.Equal(t, 0, cmp)
+
+	t.Run("invalid finalization", func(t *testing.T) {
+		spec := productAggregate()
+		spec.Finalize = func(state interface{}) (TypedValue, error) {
+			return NewVarchar("30"), nil
+		}
+
+		_, err := (&userAggregate{name: "PRODUCT", spec: spec}).newValue("").finalize()
+		require.ErrorIs(t, err, ErrInvalidTypes)
+
+		spec.Finalize = func(state interface{}) (TypedValue, error) {
+			return nil, nil
+		}
+
+		val, err := (&userAggregate{name: "PRODUCT", spec: spec}).newValue("").finalize()
+		require.NoError(t, err)
+		require.True(t, val.IsNull())
+		require.Equal(t, IntegerType, val.Type())
+	})
+
+	// ValueExp
+
+	sqlt, err := cval.inferType(nil, nil, "table1")
+	require.NoError(t, err)
+	require.Equal(t, IntegerType, sqlt)
+
+	err = cval.requiresType(IntegerType, nil, nil, "table1")
+	require.NoError(t, err)
+
+	err = cval.requiresType(BooleanType, nil, nil, "table1")
+	require.ErrorIs(t, err, ErrNotComparableValues)
+
+	_, err = cval.jointColumnTo(nil, "table1")
+	require.ErrorIs(t, err, ErrUnexpected)
+
+	_, err = cval.substitute(nil)
+	require.ErrorIs(t, err, ErrUnexpected)
+
+	_, err = cval.reduce(nil, nil, "table1")
+	require.ErrorIs(t, err, ErrUnexpected)
+
+	require.Nil(t, cval.reduceSelectors(nil, "table1"))
+
+	require.False(t, cval.isConstant())
+
+	require.Nil(t, cval.selectorRanges(nil, "", nil, nil))
+}
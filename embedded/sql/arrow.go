@@ -0,0 +1,475 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	arrowMetadataV5 = 4
+
+	arrowMessageSchema      = 1
+	arrowMessageRecordBatch = 3
+
+	arrowTypeNull            = 1
+	arrowTypeInt             = 2
+	arrowTypeFloatingPoint   = 3
+	arrowTypeBinary          = 4
+	arrowTypeUtf8            = 5
+	arrowTypeBool            = 6
+	arrowTypeTimestamp       = 10
+	arrowTypeStruct          = 13
+	arrowTypeFixedSizeBinary = 15
+
+	arrowPrecisionDouble = 2
+	arrowTimeUnitMicros  = 2
+
+	arrowContinuation = 0xFFFFFFFF
+)
+
+// ArrowExportOpts determines how query results are written by ExportArrow
+type ArrowExportOpts struct {
+	// BatchSize is the maximum number of rows of each record batch
+	BatchSize int
+}
+
+func DefaultArrowExportOpts() ArrowExportOpts {
+	return ArrowExportOpts{
+		BatchSize: 1024,
+	}
+}
+
+func (opts ArrowExportOpts) Validate() error {
+	if opts.BatchSize < 1 {
+		return fmt.Errorf("%w: invalid BatchSize", ErrIllegalArguments)
+	}
+
+	return nil
+}
+
+// ExportArrow writes the rows of the reader to w in the Arrow IPC streaming format: a schema message
+// followed by record batches of up to BatchSize rows each and the end-of-stream marker.
+// Columns are mapped to Arrow types as follows:
+//
+//	INTEGER   -> int64
+//	FLOAT     -> float64
+//	BOOLEAN   -> bool
+//	VARCHAR   -> utf8
+//	BLOB      -> binary
+//	TIMESTAMP -> timestamp[us, tz=UTC]
+//	UUID      -> fixed_size_binary[16], with the arrow.uuid extension name
+//	JSON      -> utf8 holding the JSON text, with the arrow.json extension name
+//	POINT     -> struct<lat: float64, lon: float64>
+//
+// Columns of type ANY take the type of the first value in the first batch, and are exported
+// as null columns when no such value is found. The reader is not closed.
+func ExportArrow(ctx context.Context, reader RowReader, w io.Writer, opts ArrowExportOpts) error {
+	err := opts.Validate()
+	if err != nil {
+		return err
+	}
+
+	if reader == nil || w == nil {
+		return ErrIllegalArguments
+	}
+
+	cols, err := reader.Columns(ctx)
+	if err != nil {
+		return err
+	}
+
+	cols = append([]ColDescriptor(nil), cols...)
+
+	batch := make([][]TypedValue, 0, opts.BatchSize)
+	schemaWritten := false
+
+	flush := func() error {
+		if !schemaWritten {
+			resolveArrowColumnTypes(cols, batch)
+
+			err := writeArrowMessage(w, arrowMessageSchema, arrowSchema(cols), nil)
+			if err != nil {
+				return err
+			}
+			schemaWritten = true
+		}
+
+		if len(batch) == 0 {
+			return nil
+		}
+
+		err := writeArrowRecordBatch(w, cols, batch)
+		if err != nil {
+			return err
+		}
+
+		batch = batch[:0]
+		return nil
+	}
+
+	for {
+		row, err := reader.Read(ctx)
+		if errors.Is(err, ErrNoMoreRows) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(row.ValuesByPosition) != len(cols) {
+			return fmt.Errorf("%w: rows do not match the columns of the reader", ErrUnexpected)
+		}
+
+		batch = append(batch, row.ValuesByPosition)
+
+		if len(batch) == opts.BatchSize {
+			err := flush()
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	err = flush()
+	if err != nil {
+		return err
+	}
+
+	var eos [8]byte
+	binary.LittleEndian.PutUint32(eos[:], arrowContinuation)
+
+	_, err = w.Write(eos[:])
+	return err
+}
+
+func resolveArrowColumnTypes(cols []ColDescriptor, batch [][]TypedValue) {
+	for i := range cols {
+		if cols[i].Type != AnyType {
+			continue
+		}
+
+		for _, row := range batch {
+			if !row[i].IsNull() {
+				cols[i].Type = row[i].Type()
+				break
+			}
+		}
+	}
+}
+
+func arrowSchema(cols []ColDescriptor) []*fbField {
+	return []*fbField{
+		fbScalar(2, 0), // little endian
+		fbRef(func(b *fbBuilder) int {
+			return b.tables(len(cols), func(b *fbBuilder, i int) int {
+				return b.table(arrowField(cols[i].Column, cols[i].Type, cols[i].Nullable))
+			})
+		}),
+	}
+}
+
+func arrowField(name string, colType SQLValueType, nullable bool) []*fbField {
+	typeID, typeFields := arrowType(colType)
+
+	var children []*fbField
+	var extension string
+
+	switch colType {
+	case UUIDType:
+		extension = "arrow.uuid"
+	case JSONType:
+		extension = "arrow.json"
+	case PointType:
+		children = []*fbField{
+			fbRef(func(b *fbBuilder) int { return b.table(arrowField("lat", Float64Type, false)) }),
+			fbRef(func(b *fbBuilder) int { return b.table(arrowField("lon", Float64Type, false)) }),
+		}
+	}
+
+	var metadata *fbField
+	if extension != "" {
+		metadata = fbRef(func(b *fbBuilder) int {
+			return b.tables(1, func(b *fbBuilder, _ int) int {
+				return b.table([]*fbField{
+					fbRef(func(b *fbBuilder) int { return b.string("ARROW:extension:name") }),
+					fbRef(func(b *fbBuilder) int { return b.string(extension) }),
+				})
+			})
+		})
+	}
+
+	return []*fbField{
+		fbRef(func(b *fbBuilder) int { return b.string(name) }),
+		fbScalar(1, boolToUint(nullable || colType == AnyType)),
+		fbScalar(1, typeID),
+		fbRef(func(b *fbBuilder) int { return b.table(typeFields) }),
+		nil, // dictionary
+		fbRef(func(b *fbBuilder) int {
+			return b.tables(len(children), func(b *fbBuilder, i int) int {
+				return children[i].obj(b)
+			})
+		}),
+		metadata,
+	}
+}
+
+func arrowType(colType SQLValueType) (uint64, []*fbField) {
+	switch colType {
+	case IntegerType:
+		return arrowTypeInt, []*fbField{fbScalar(4, 64), fbScalar(1, 1)}
+	case Float64Type:
+		return arrowTypeFloatingPoint, []*fbField{fbScalar(2, arrowPrecisionDouble)}
+	case BooleanType:
+		return arrowTypeBool, nil
+	case VarcharType, JSONType:
+		return arrowTypeUtf8, nil
+	case BLOBType:
+		return arrowTypeBinary, nil
+	case TimestampType:
+		return arrowTypeTimestamp, []*fbField{
+			fbScalar(2, arrowTimeUnitMicros),
+			fbRef(func(b *fbBuilder) int { return b.string("UTC") }),
+		}
+	case UUIDType:
+		return arrowTypeFixedSizeBinary, []*fbField{fbScalar(4, 16)}
+	case PointType:
+		return arrowTypeStruct, nil
+	}
+	return arrowTypeNull, nil
+}
+
+func boolToUint(b bool) uint64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// arrowBody accumulates the field nodes and buffers of a record batch
+type arrowBody struct {
+	nodes   [][2]int64
+	buffers [][2]int64
+	data    []byte
+}
+
+func (body *arrowBody) addNode(length, nullCount int) {
+	body.nodes = append(body.nodes, [2]int64{int64(length), int64(nullCount)})
+}
+
+// addBuffer appends the buffer to the body, padded to a multiple of 8 bytes
+func (body *arrowBody) addBuffer(buf []byte) {
+	body.buffers = append(body.buffers, [2]int64{int64(len(body.data)), int64(len(buf))})
+	body.data = append(body.data, buf...)
+
+	for len(body.data)%8 != 0 {
+		body.data = append(body.data, 0)
+	}
+}
+
+func writeArrowRecordBatch(w io.Writer, cols []ColDescriptor, batch [][]TypedValue) error {
+	body := &arrowBody{}
+
+	values := make([]TypedValue, len(batch))
+
+	for i, col := range cols {
+		for j, row := range batch {
+			values[j] = row[i]
+		}
+
+		err := body.addColumn(col, values)
+		if err != nil {
+			return err
+		}
+	}
+
+	header := []*fbField{
+		fbScalar(8, uint64(len(batch))),
+		fbRef(func(b *fbBuilder) int { return b.structs(body.nodes) }),
+		fbRef(func(b *fbBuilder) int { return b.structs(body.buffers) }),
+	}
+	return writeArrowMessage(w, arrowMessageRecordBatch, header, body.data)
+}
+
+func (body *arrowBody) addColumn(col ColDescriptor, values []TypedValue) error {
+	n := len(values)
+
+	validity := make([]byte, (n+7)/8)
+	nullCount := 0
+
+	for i, v := range values {
+		if v.IsNull() {
+			nullCount++
+			continue
+		}
+
+		if v.Type() != col.Type && !(v.Type() == IntegerType && col.Type == Float64Type) {
+			return fmt.Errorf("%w: value of type %s can not be exported in column '%s' of type %s",
+				ErrInvalidTypes, v.Type(), col.Column, col.Type)
+		}
+
+		validity[i/8] |= 1 << (i % 8)
+	}
+
+	body.addNode(n, nullCount)
+
+	if col.Type == AnyType {
+		// null arrays have no buffers
+		return nil
+	}
+
+	body.addBuffer(validity)
+
+	switch col.Type {
+	case IntegerType, Float64Type, TimestampType:
+		buf := make([]byte, 8*n)
+
+		for i, v := range values {
+			if v.IsNull() {
+				continue
+			}
+
+			var bits uint64
+			switch raw := v.RawValue().(type) {
+			case int64:
+				if col.Type == Float64Type {
+					bits = math.Float64bits(float64(raw))
+				} else {
+					bits = uint64(raw)
+				}
+			case float64:
+				bits = math.Float64bits(raw)
+			case time.Time:
+				bits = uint64(raw.UnixMicro())
+			}
+
+			binary.LittleEndian.PutUint64(buf[8*i:], bits)
+		}
+
+		body.addBuffer(buf)
+	case BooleanType:
+		buf := make([]byte, (n+7)/8)
+
+		for i, v := range values {
+			if !v.IsNull() && v.RawValue().(bool) {
+				buf[i/8] |= 1 << (i % 8)
+			}
+		}
+
+		body.addBuffer(buf)
+	case VarcharType, JSONType, BLOBType:
+		offsets := make([]byte, 4*(n+1))
+		var data []byte
+
+		for i, v := range values {
+			if !v.IsNull() {
+				switch raw := v.RawValue().(type) {
+				case string:
+					if col.Type == JSONType {
+						data = append(data, v.String()...)
+					} else {
+						data = append(data, raw...)
+					}
+				case []byte:
+					data = append(data, raw...)
+				default:
+					data = append(data, v.String()...)
+				}
+			}
+
+			if len(data) > math.MaxInt32 {
+				return fmt.Errorf("%w: values of column '%s' exceed the maximum size of a record batch", ErrIllegalArguments, col.Column)
+			}
+
+			binary.LittleEndian.PutUint32(offsets[4*(i+1):], uint32(len(data)))
+		}
+
+		body.addBuffer(offsets)
+		body.addBuffer(data)
+	case UUIDType:
+		buf := make([]byte, 16*n)
+
+		for i, v := range values {
+			if !v.IsNull() {
+				u := v.RawValue().(uuid.UUID)
+				copy(buf[16*i:], u[:])
+			}
+		}
+
+		body.addBuffer(buf)
+	case PointType:
+		// children are never null, the slots of null points hold zeros
+		allValid := make([]byte, (n+7)/8)
+		for i := 0; i < n; i++ {
+			allValid[i/8] |= 1 << (i % 8)
+		}
+
+		lats := make([]byte, 8*n)
+		lons := make([]byte, 8*n)
+
+		for i, v := range values {
+			if !v.IsNull() {
+				p := v.RawValue().(GeoPoint)
+				binary.LittleEndian.PutUint64(lats[8*i:], math.Float64bits(p.Lat))
+				binary.LittleEndian.PutUint64(lons[8*i:], math.Float64bits(p.Lon))
+			}
+		}
+
+		body.addNode(n, 0)
+		body.addBuffer(allValid)
+		body.addBuffer(lats)
+
+		body.addNode(n, 0)
+		body.addBuffer(allValid)
+		body.addBuffer(lons)
+	}
+	return nil
+}
+
+// writeArrowMessage writes an encapsulated message: the continuation marker, the size of the
+// metadata, the metadata and the body, with both the metadata and the body padded to 8 bytes
+func writeArrowMessage(w io.Writer, headerType uint64, header []*fbField, body []byte) error {
+	b := &fbBuilder{}
+
+	metadata := b.finish([]*fbField{
+		fbScalar(2, arrowMetadataV5),
+		fbScalar(1, headerType),
+		fbRef(func(b *fbBuilder) int { return b.table(header) }),
+		fbScalar(8, uint64(len(body))),
+	})
+
+	// the prefix is 8 bytes long, thus the body starts aligned to 8 bytes
+	var prefix [8]byte
+	binary.LittleEndian.PutUint32(prefix[:], arrowContinuation)
+	binary.LittleEndian.PutUint32(prefix[4:], uint32(len(metadata)))
+
+	for _, buf := range [][]byte{prefix[:], metadata, body} {
+		_, err := w.Write(buf)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
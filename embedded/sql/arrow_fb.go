@@ -0,0 +1,167 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import "encoding/binary"
+
+// fbBuilder writes the flatbuffers holding the metadata of Arrow IPC messages.
+// Objects are written front to back, each one after the object referring to it,
+// as offsets to objects are unsigned and relative to the position of the offset itself.
+type fbBuilder struct {
+	buf []byte
+}
+
+// fbField is a field of a table, either a scalar value or a reference to an object
+type fbField struct {
+	size int
+	val  uint64
+	obj  func(b *fbBuilder) int // writes the referenced object and returns its position
+}
+
+func fbScalar(size int, val uint64) *fbField {
+	return &fbField{size: size, val: val}
+}
+
+func fbRef(obj func(b *fbBuilder) int) *fbField {
+	return &fbField{size: 4, obj: obj}
+}
+
+// finish writes the root table and returns the flatbuffer, padded to a multiple of 8 bytes
+func (b *fbBuilder) finish(root []*fbField) []byte {
+	b.buf = make([]byte, 4)
+
+	pos := b.table(root)
+	binary.LittleEndian.PutUint32(b.buf, uint32(pos))
+
+	b.pad(8)
+	return b.buf
+}
+
+func (b *fbBuilder) pad(alignment int) {
+	for len(b.buf)%alignment != 0 {
+		b.buf = append(b.buf, 0)
+	}
+}
+
+func (b *fbBuilder) grow(n int) int {
+	pos := len(b.buf)
+	b.buf = append(b.buf, make([]byte, n)...)
+	return pos
+}
+
+func (b *fbBuilder) putUint(pos, size int, v uint64) {
+	switch size {
+	case 1:
+		b.buf[pos] = byte(v)
+	case 2:
+		binary.LittleEndian.PutUint16(b.buf[pos:], uint16(v))
+	case 4:
+		binary.LittleEndian.PutUint32(b.buf[pos:], uint32(v))
+	case 8:
+		binary.LittleEndian.PutUint64(b.buf[pos:], v)
+	}
+}
+
+// table writes the vtable followed by the table, nil fields are omitted
+func (b *fbBuilder) table(fields []*fbField) int {
+	// the table starts with the offset to its vtable, fields are aligned to their size
+	offsets := make([]int, len(fields))
+	size := 4
+
+	for i, f := range fields {
+		if f == nil {
+			continue
+		}
+
+		for size%f.size != 0 {
+			size++
+		}
+
+		offsets[i] = size
+		size += f.size
+	}
+
+	b.pad(2)
+	vpos := b.grow(4 + 2*len(fields))
+
+	b.putUint(vpos, 2, uint64(4+2*len(fields)))
+	b.putUint(vpos+2, 2, uint64(size))
+
+	for i, off := range offsets {
+		b.putUint(vpos+4+2*i, 2, uint64(off))
+	}
+
+	b.pad(8)
+	tpos := b.grow(size)
+
+	b.putUint(tpos, 4, uint64(tpos-vpos))
+
+	for i, f := range fields {
+		if f != nil && f.obj == nil {
+			b.putUint(tpos+offsets[i], f.size, f.val)
+		}
+	}
+
+	for i, f := range fields {
+		if f != nil && f.obj != nil {
+			fpos := tpos + offsets[i]
+			b.putUint(fpos, 4, uint64(f.obj(b)-fpos))
+		}
+	}
+	return tpos
+}
+
+func (b *fbBuilder) string(s string) int {
+	b.pad(4)
+	pos := b.grow(4)
+	b.putUint(pos, 4, uint64(len(s)))
+
+	b.buf = append(b.buf, s...)
+	b.buf = append(b.buf, 0)
+	return pos
+}
+
+// tables writes a vector of n tables, as written by the table function
+func (b *fbBuilder) tables(n int, table func(b *fbBuilder, i int) int) int {
+	b.pad(4)
+	pos := b.grow(4 + 4*n)
+	b.putUint(pos, 4, uint64(n))
+
+	for i := 0; i < n; i++ {
+		slot := pos + 4 + 4*i
+		b.putUint(slot, 4, uint64(table(b, i)-slot))
+	}
+	return pos
+}
+
+// structs writes a vector of structs made of two 64-bit integers each
+func (b *fbBuilder) structs(elems [][2]int64) int {
+	// elements are aligned to 8 bytes, right after the length of the vector
+	b.pad(4)
+	if len(b.buf)%8 == 0 {
+		b.grow(4)
+	}
+
+	pos := b.grow(4 + 16*len(elems))
+	b.putUint(pos, 4, uint64(len(elems)))
+
+	for i, e := range elems {
+		b.putUint(pos+4+16*i, 8, uint64(e[0]))
+		b.putUint(pos+4+16*i+8, 8, uint64(e[1]))
+	}
+	return pos
+}
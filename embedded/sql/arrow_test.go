@@ -0,0 +1,565 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// fbTable reads the tables of the flatbuffers written by fbBuilder
+type fbTable struct {
+	buf []byte
+	pos int
+}
+
+func (t fbTable) u16(pos int) int { return int(binary.LittleEndian.Uint16(t.buf[pos:])) }
+func (t fbTable) u32(pos int) int { return int(binary.LittleEndian.Uint32(t.buf[pos:])) }
+
+func (t fbTable) field(i int) int {
+	vt := t.pos - int(int32(binary.LittleEndian.Uint32(t.buf[t.pos:])))
+	if 4+2*i >= t.u16(vt) {
+		return 0
+	}
+
+	off := t.u16(vt + 4 + 2*i)
+	if off == 0 {
+		return 0
+	}
+	return t.pos + off
+}
+
+func (t fbTable) scalar(i, size int) uint64 {
+	pos := t.field(i)
+	if pos == 0 {
+		return 0
+	}
+
+	switch size {
+	case 1:
+		return uint64(t.buf[pos])
+	case 2:
+		return uint64(binary.LittleEndian.Uint16(t.buf[pos:]))
+	case 4:
+		return uint64(binary.LittleEndian.Uint32(t.buf[pos:]))
+	}
+	return binary.LittleEndian.Uint64(t.buf[pos:])
+}
+
+func (t fbTable) ref(i int) int {
+	pos := t.field(i)
+	if pos == 0 {
+		return 0
+	}
+	return pos + t.u32(pos)
+}
+
+func (t fbTable) table(i int) fbTable {
+	return fbTable{buf: t.buf, pos: t.ref(i)}
+}
+
+func (t fbTable) string(i int) string {
+	pos := t.ref(i)
+	if pos == 0 {
+		return ""
+	}
+	return string(t.buf[pos+4 : pos+4+t.u32(pos)])
+}
+
+func (t fbTable) tables(i int) []fbTable {
+	pos := t.ref(i)
+	if pos == 0 {
+		return nil
+	}
+
+	tables := make([]fbTable, t.u32(pos))
+	for j := range tables {
+		slot := pos + 4 + 4*j
+		tables[j] = fbTable{buf: t.buf, pos: slot + t.u32(slot)}
+	}
+	return tables
+}
+
+func (t fbTable) structs(i int) [][2]int64 {
+	pos := t.ref(i)
+	if pos == 0 {
+		return nil
+	}
+
+	elems := make([][2]int64, t.u32(pos))
+	for j := range elems {
+		elems[j][0] = int64(binary.LittleEndian.Uint64(t.buf[pos+4+16*j:]))
+		elems[j][1] = int64(binary.LittleEndian.Uint64(t.buf[pos+4+16*j+8:]))
+	}
+	return elems
+}
+
+type arrowTestField struct {
+	name      string
+	dataType  string
+	nullable  bool
+	extension string
+	children  []arrowTestField
+}
+
+func decodeArrowTestField(t *testing.T, f fbTable) arrowTestField {
+	field := arrowTestField{
+		name:     f.string(0),
+		nullable: f.scalar(1, 1) == 1,
+	}
+
+	for _, kv := range f.tables(6) {
+		if kv.string(0) == "ARROW:extension:name" {
+			field.extension = kv.string(1)
+		}
+	}
+
+	for _, child := range f.tables(5) {
+		field.children = append(field.children, decodeArrowTestField(t, child))
+	}
+
+	typ := f.table(3)
+	require.NotZero(t, typ.pos)
+
+	switch f.scalar(2, 1) {
+	case arrowTypeNull:
+		field.dataType = "null"
+	case arrowTypeInt:
+		require.Equal(t, uint64(64), typ.scalar(0, 4))
+		require.Equal(t, uint64(1), typ.scalar(1, 1))
+		field.dataType = "int64"
+	case arrowTypeFloatingPoint:
+		require.Equal(t, uint64(arrowPrecisionDouble), typ.scalar(0, 2))
+		field.dataType = "float64"
+	case arrowTypeBinary:
+		field.dataType = "binary"
+	case arrowTypeUtf8:
+		field.dataType = "utf8"
+	case arrowTypeBool:
+		field.dataType = "bool"
+	case arrowTypeTimestamp:
+		require.Equal(t, uint64(arrowTimeUnitMicros), typ.scalar(0, 2))
+		field.dataType = "timestamp[us, tz=" + typ.string(1) + "]"
+	case arrowTypeStruct:
+		field.dataType = "struct"
+	case arrowTypeFixedSizeBinary:
+		require.Equal(t, uint64(16), typ.scalar(0, 4))
+		field.dataType = "fixed_size_binary[16]"
+	default:
+		require.Fail(t, "unexpected type")
+	}
+	return field
+}
+
+type arrowTestBatch struct {
+	length int
+	// columns hold the values of each column, nil for null values
+	columns [][]interface{}
+}
+
+// arrowTestBatchReader consumes the field nodes and buffers of a record batch, in order
+type arrowTestBatchReader struct {
+	t       *testing.T
+	nodes   [][2]int64
+	buffers [][2]int64
+	body    []byte
+}
+
+func (r *arrowTestBatchReader) node() (int, int) {
+	require.NotEmpty(r.t, r.nodes)
+
+	n := r.nodes[0]
+	r.nodes = r.nodes[1:]
+	return int(n[0]), int(n[1])
+}
+
+func (r *arrowTestBatchReader) buffer() []byte {
+	require.NotEmpty(r.t, r.buffers)
+
+	b := r.buffers[0]
+	r.buffers = r.buffers[1:]
+
+	require.Zero(r.t, b[0]%8, "buffers must be aligned to 8 bytes")
+	return r.body[b[0] : b[0]+b[1]]
+}
+
+func (r *arrowTestBatchReader) column(field arrowTestField) []interface{} {
+	n, nullCount := r.node()
+	values := make([]interface{}, n)
+
+	if field.dataType == "null" {
+		require.Equal(r.t, n, nullCount)
+		return values
+	}
+
+	validity := r.buffer()
+	valid := func(i int) bool {
+		return validity[i/8]&(1<<(i%8)) != 0
+	}
+
+	nulls := 0
+	for i := 0; i < n; i++ {
+		if !valid(i) {
+			nulls++
+		}
+	}
+	require.Equal(r.t, nullCount, nulls)
+
+	switch field.dataType {
+	case "int64", "float64", "timestamp[us, tz=UTC]":
+		buf := r.buffer()
+
+		for i := range values {
+			if !valid(i) {
+				continue
+			}
+
+			bits := binary.LittleEndian.Uint64(buf[8*i:])
+
+			switch field.dataType {
+			case "int64":
+				values[i] = int64(bits)
+			case "float64":
+				values[i] = math.Float64frombits(bits)
+			default:
+				values[i] = time.UnixMicro(int64(bits)).UTC()
+			}
+		}
+	case "bool":
+		buf := r.buffer()
+
+		for i := range values {
+			if valid(i) {
+				values[i] = buf[i/8]&(1<<(i%8)) != 0
+			}
+		}
+	case "utf8", "binary":
+		offsets := r.buffer()
+		data := r.buffer()
+
+		for i := range values {
+			start := binary.LittleEndian.Uint32(offsets[4*i:])
+			end := binary.LittleEndian.Uint32(offsets[4*(i+1):])
+
+			if !valid(i) {
+				require.Equal(r.t, start, end)
+				continue
+			}
+
+			if field.dataType == "utf8" {
+				values[i] = string(data[start:end])
+			} else {
+				values[i] = append([]byte{}, data[start:end]...)
+			}
+		}
+	case "fixed_size_binary[16]":
+		buf := r.buffer()
+
+		for i := range values {
+			if valid(i) {
+				var u uuid.UUID
+				copy(u[:], buf[16*i:])
+				values[i] = u
+			}
+		}
+	case "struct":
+		lats := r.column(field.children[0])
+		lons := r.column(field.children[1])
+
+		for i := range values {
+			if valid(i) {
+				values[i] = GeoPoint{Lat: lats[i].(float64), Lon: lons[i].(float64)}
+			}
+		}
+	}
+	return values
+}
+
+// decodeArrowTestStream decodes an Arrow IPC stream, checking the framing of each message
+func decodeArrowTestStream(t *testing.T, data []byte) ([]arrowTestField, []arrowTestBatch) {
+	var fields []arrowTestField
+	var batches []arrowTestBatch
+
+	for {
+		require.GreaterOrEqual(t, len(data), 8)
+		require.Equal(t, uint32(arrowContinuation), binary.LittleEndian.Uint32(data))
+
+		size := int(binary.LittleEndian.Uint32(data[4:]))
+		data = data[8:]
+
+		if size == 0 {
+			require.Empty(t, data, "no data is expected after the end-of-stream marker")
+			return fields, batches
+		}
+
+		require.Zero(t, size%8, "metadata must be padded to 8 bytes")
+
+		buf := data[:size]
+		msg := fbTable{buf: buf, pos: int(binary.LittleEndian.Uint32(buf))}
+		data = data[size:]
+
+		require.Equal(t, uint64(arrowMetadataV5), msg.scalar(0, 2))
+
+		bodyLen := int(msg.scalar(3, 8))
+		require.Zero(t, bodyLen%8, "body must be padded to 8 bytes")
+
+		body := data[:bodyLen]
+		data = data[bodyLen:]
+
+		header := msg.table(2)
+
+		switch msg.scalar(1, 1) {
+		case arrowMessageSchema:
+			require.Nil(t, fields, "a single schema is expected")
+			require.Nil(t, batches, "the schema is expected before record batches")
+			require.Zero(t, bodyLen)
+
+			fields = []arrowTestField{}
+			for _, f := range header.tables(1) {
+				fields = append(fields, decodeArrowTestField(t, f))
+			}
+		case arrowMessageRecordBatch:
+			require.NotNil(t, fields)
+
+			r := &arrowTestBatchReader{
+				t:       t,
+				nodes:   header.structs(1),
+				buffers: header.structs(2),
+				body:    body,
+			}
+
+			batch := arrowTestBatch{length: int(header.scalar(0, 8))}
+			for _, f := range fields {
+				values := r.column(f)
+				require.Len(t, values, batch.length)
+
+				batch.columns = append(batch.columns, values)
+			}
+
+			require.Empty(t, r.nodes)
+			require.Empty(t, r.buffers)
+
+			batches = append(batches, batch)
+		default:
+			require.Fail(t, "unexpected message")
+		}
+	}
+}
+
+func exportArrowTest(t *testing.T, engine *Engine, sql string, params map[string]interface{}, opts ArrowExportOpts) ([]arrowTestField, []arrowTestBatch) {
+	r, err := engine.Query(context.Background(), nil, sql, params)
+	require.NoError(t, err)
+	defer r.Close()
+
+	var buf bytes.Buffer
+	err = ExportArrow(context.Background(), r, &buf, opts)
+	require.NoError(t, err)
+
+	return decodeArrowTestStream(t, buf.Bytes())
+}
+
+type failingWriter struct {
+	writes int
+}
+
+func (w *failingWriter) Write(p []byte) (int, error) {
+	if w.writes == 0 {
+		return 0, errors.New("write failed")
+	}
+	w.writes--
+	return len(p), nil
+}
+
+func TestExportArrow(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, `
+		CREATE TABLE t (
+			id INTEGER,
+			f FLOAT,
+			b BOOLEAN,
+			s VARCHAR,
+			bl BLOB,
+			ts TIMESTAMP,
+			u UUID,
+			j JSON,
+			p POINT,
+			PRIMARY KEY id
+		);
+	`, nil)
+	require.NoError(t, err)
+
+	u := uuid.New()
+	ts := time.Date(2025, 3, 17, 13, 45, 30, 123456000, time.UTC)
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		INSERT INTO t (id, f, b, s, bl, ts, u, j, p) VALUES
+			(1, 1.5, true, 'one', x'0102', @ts, CAST(@u AS UUID), '{"a": [1, "x"]}'::JSON, POINT(41.9028, 12.4964)),
+			(2, NULL, false, '', x'', NULL, NULL, '"text"'::JSON, NULL),
+			(3, -2.25, NULL, NULL, NULL, @ts, CAST(@u AS UUID), NULL, POINT(-33.8688, 151.2093)),
+			(4, 0, true, 'four', x'ff', @ts, NULL, '4'::JSON, POINT(0, 0)),
+			(5, 5, false, 'five', NULL, NULL, CAST(@u AS UUID), '[true]'::JSON, NULL)
+	`, map[string]interface{}{"ts": ts, "u": u.String()})
+	require.NoError(t, err)
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		r, err := engine.Query(context.Background(), nil, "SELECT * FROM t", nil)
+		require.NoError(t, err)
+		defer r.Close()
+
+		err = ExportArrow(context.Background(), r, &bytes.Buffer{}, ArrowExportOpts{})
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		err = ExportArrow(context.Background(), nil, &bytes.Buffer{}, DefaultArrowExportOpts())
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		err = ExportArrow(context.Background(), r, nil, DefaultArrowExportOpts())
+		require.ErrorIs(t, err, ErrIllegalArguments)
+	})
+
+	t.Run("types are mapped to arrow types", func(t *testing.T) {
+		fields, _ := exportArrowTest(t, engine, "SELECT * FROM t", nil, DefaultArrowExportOpts())
+
+		child := func(name string) arrowTestField {
+			return arrowTestField{name: name, dataType: "float64"}
+		}
+
+		require.Equal(t, []arrowTestField{
+			{name: "id", dataType: "int64"},
+			{name: "f", dataType: "float64", nullable: true},
+			{name: "b", dataType: "bool", nullable: true},
+			{name: "s", dataType: "utf8", nullable: true},
+			{name: "bl", dataType: "binary", nullable: true},
+			{name: "ts", dataType: "timestamp[us, tz=UTC]", nullable: true},
+			{name: "u", dataType: "fixed_size_binary[16]", nullable: true, extension: "arrow.uuid"},
+			{name: "j", dataType: "utf8", nullable: true, extension: "arrow.json"},
+			{name: "p", dataType: "struct", nullable: true, children: []arrowTestField{child("lat"), child("lon")}},
+		}, fields)
+	})
+
+	t.Run("values and nulls are preserved", func(t *testing.T) {
+		_, batches := exportArrowTest(t, engine, "SELECT * FROM t", nil, ArrowExportOpts{BatchSize: 2})
+		require.Len(t, batches, 3)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT * FROM t", nil)
+		require.NoError(t, err)
+
+		i := 0
+		for _, batch := range batches {
+			for j := 0; j < batch.length; j++ {
+				row := rows[i]
+				i++
+
+				for c, v := range row.ValuesByPosition {
+					expected := v.RawValue()
+
+					switch {
+					case v.IsNull():
+						expected = nil
+					case v.Type() == JSONType:
+						expected = v.String()
+					}
+
+					require.Equal(t, expected, batch.columns[c][j], "row %d, column %d", i, c)
+				}
+			}
+		}
+		require.Equal(t, len(rows), i)
+
+		require.Equal(t, `{"a":[1,"x"]}`, batches[0].columns[7][0])
+		require.Equal(t, `"text"`, batches[0].columns[7][1])
+		require.Equal(t, `[true]`, batches[2].columns[7][0])
+		require.Equal(t, ts, batches[0].columns[5][0])
+	})
+
+	t.Run("rows are split into batches", func(t *testing.T) {
+		for batchSize, lengths := range map[int][]int{
+			1:    {1, 1, 1, 1, 1},
+			2:    {2, 2, 1},
+			5:    {5},
+			1024: {5},
+		} {
+			_, batches := exportArrowTest(t, engine, "SELECT id FROM t ORDER BY id", nil, ArrowExportOpts{BatchSize: batchSize})
+
+			var ids []interface{}
+			var actual []int
+
+			for _, b := range batches {
+				actual = append(actual, b.length)
+				ids = append(ids, b.columns[0]...)
+			}
+
+			require.Equal(t, lengths, actual, batchSize)
+			require.Equal(t, []interface{}{int64(1), int64(2), int64(3), int64(4), int64(5)}, ids)
+		}
+	})
+
+	t.Run("empty results produce a schema without batches", func(t *testing.T) {
+		fields, batches := exportArrowTest(t, engine, "SELECT id, s FROM t WHERE id > 10", nil, DefaultArrowExportOpts())
+		require.Len(t, fields, 2)
+		require.Empty(t, batches)
+	})
+
+	t.Run("integer values are widened in float columns", func(t *testing.T) {
+		fields, batches := exportArrowTest(t, engine, "SELECT COALESCE(f, id) AS v FROM t ORDER BY id", nil, DefaultArrowExportOpts())
+		require.Equal(t, "float64", fields[0].dataType)
+		require.Equal(t, []interface{}{1.5, float64(2), -2.25, float64(0), float64(5)}, batches[0].columns[0])
+	})
+
+	t.Run("columns of type ANY", func(t *testing.T) {
+		fields, batches := exportArrowTest(t, engine, "SELECT NULL AS n FROM t", nil, DefaultArrowExportOpts())
+		require.Equal(t, []arrowTestField{{name: "n", dataType: "null", nullable: true}}, fields)
+		require.Equal(t, []interface{}{nil, nil, nil, nil, nil}, batches[0].columns[0])
+
+		fields, batches = exportArrowTest(t, engine, "SELECT @p AS v FROM t WHERE id < 3", map[string]interface{}{"p": "x"}, DefaultArrowExportOpts())
+		require.Equal(t, "v", fields[0].name)
+		require.Equal(t, "utf8", fields[0].dataType)
+		require.Equal(t, []interface{}{"x", "x"}, batches[0].columns[0])
+	})
+
+	t.Run("write errors are returned", func(t *testing.T) {
+		for writes := 0; writes < 6; writes++ {
+			r, err := engine.Query(context.Background(), nil, "SELECT * FROM t", nil)
+			require.NoError(t, err)
+
+			err = ExportArrow(context.Background(), r, &failingWriter{writes: writes}, ArrowExportOpts{BatchSize: 2})
+			require.ErrorContains(t, err, "write failed")
+
+			require.NoError(t, r.Close())
+		}
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		r, err := engine.Query(context.Background(), nil, "SELECT * FROM t", nil)
+		require.NoError(t, err)
+		defer r.Close()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		err = ExportArrow(ctx, r, &bytes.Buffer{}, DefaultArrowExportOpts())
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
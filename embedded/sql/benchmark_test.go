@@ -107,3 +107,57 @@ func TestPerformanceComparison(t *testing.T) {
 	fmt.Printf("Processed %d rows in %v\n", rowCount, duration)
 	fmt.Printf("Throughput: %.2f rows/sec\n", float64(rowCount)/duration.Seconds())
 }
+
+// BenchmarkConstantCondition compares reading rows filtered by a condition evaluated for each row
+// with reading them filtered by conditions reduced to a constant, which are not evaluated per row
+func BenchmarkConstantCondition(b *testing.B) {
+	st, err := store.Open(b.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(b, err)
+	defer st.Close()
+
+	engine, err := NewEngine(st, DefaultOptions().WithPrefix([]byte{2}))
+	require.NoError(b, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE bench_table (id INTEGER, val INTEGER, PRIMARY KEY id)", nil)
+	require.NoError(b, err)
+
+	for i := 0; i < 1000; i++ {
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO bench_table (id, val) VALUES (@id, @val)", map[string]interface{}{
+			"id":  i,
+			"val": i,
+		})
+		require.NoError(b, err)
+	}
+
+	for _, d := range []struct {
+		name   string
+		where  string
+		params map[string]interface{}
+	}{
+		{"per-row", "val >= 0", nil},
+		{"constant", "1 = 1", nil},
+		{"constant-param", "@flag", map[string]interface{}{"flag": true}},
+	} {
+		b.Run(d.name, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				r, err := engine.Query(context.Background(), nil, "SELECT id FROM bench_table WHERE "+d.where, d.params)
+				require.NoError(b, err)
+
+				count := 0
+				for {
+					_, err := r.Read(context.Background())
+					if err == ErrNoMoreRows {
+						break
+					}
+					require.NoError(b, err)
+					count++
+				}
+				require.Equal(b, 1000, count)
+
+				r.Close()
+			}
+		})
+	}
+}
@@ -0,0 +1,545 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// rows encoded by EncodeRows are preceded by a header made of the magic bytes,
+// the number of columns and the description of each column:
+//
+//	magic | uvarint(#cols) | (type, flags, aggFn, table, column)*
+//
+// followed by a frame per row and the end frame:
+//
+//	frameRow | uvarint(len(payload)) | payload
+//	frameEnd
+//
+// The payload of each row holds a bitmap of its NULL values and the remaining values
+// in column order, each prefixed by its type when the column is of type ANY.
+// Strings are prefixed by their length as uvarint.
+var binaryRowsMagic = []byte{'I', 'M', 'R', 1}
+
+const (
+	binaryRowsFrameEnd = 0
+	binaryRowsFrameRow = 1
+
+	binaryRowsColNullable = 1
+)
+
+var binaryRowsTypeCodes = []SQLValueType{
+	AnyType,
+	IntegerType,
+	BooleanType,
+	VarcharType,
+	UUIDType,
+	BLOBType,
+	Float64Type,
+	TimestampType,
+	JSONType,
+	PointType,
+}
+
+func binaryRowsTypeCode(t SQLValueType) (byte, error) {
+	for i, ct := range binaryRowsTypeCodes {
+		if ct == t {
+			return byte(i), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: type %s can not be encoded", ErrInvalidTypes, t)
+}
+
+// EncodeRows writes the rows of the reader to w in a compact binary format, describing
+// the columns once before the rows. Rows can be read back with a RowDecoder.
+// The reader is not closed.
+func EncodeRows(ctx context.Context, w io.Writer, reader RowReader) error {
+	if reader == nil || w == nil {
+		return ErrIllegalArguments
+	}
+
+	cols, err := reader.Columns(ctx)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+
+	err = writeBinaryRowsHeader(bw, cols)
+	if err != nil {
+		return err
+	}
+
+	var payload []byte
+	var lenBuf [binary.MaxVarintLen64]byte
+
+	for {
+		row, err := reader.Read(ctx)
+		if errors.Is(err, ErrNoMoreRows) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(row.ValuesByPosition) != len(cols) {
+			return fmt.Errorf("%w: rows do not match the columns of the reader", ErrUnexpected)
+		}
+
+		payload, err = appendBinaryRow(payload[:0], cols, row.ValuesByPosition)
+		if err != nil {
+			return err
+		}
+
+		bw.WriteByte(binaryRowsFrameRow)
+		bw.Write(lenBuf[:binary.PutUvarint(lenBuf[:], uint64(len(payload)))])
+		bw.Write(payload)
+	}
+
+	bw.WriteByte(binaryRowsFrameEnd)
+	return bw.Flush()
+}
+
+func writeBinaryRowsHeader(w *bufio.Writer, cols []ColDescriptor) error {
+	w.Write(binaryRowsMagic)
+
+	var buf []byte
+	buf = binary.AppendUvarint(buf, uint64(len(cols)))
+
+	for _, col := range cols {
+		code, err := binaryRowsTypeCode(col.Type)
+		if err != nil {
+			return err
+		}
+
+		var flags byte
+		if col.Nullable {
+			flags |= binaryRowsColNullable
+		}
+
+		buf = append(buf, code, flags)
+		buf = appendBinaryString(buf, col.AggFn)
+		buf = appendBinaryString(buf, col.Table)
+		buf = appendBinaryString(buf, col.Column)
+	}
+
+	_, err := w.Write(buf)
+	return err
+}
+
+func appendBinaryString(buf []byte, s string) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(s)))
+	return append(buf, s...)
+}
+
+func appendBinaryRow(buf []byte, cols []ColDescriptor, values []TypedValue) ([]byte, error) {
+	bitmapOff := len(buf)
+	buf = append(buf, make([]byte, (len(cols)+7)/8)...)
+
+	for i, v := range values {
+		if v.IsNull() {
+			buf[bitmapOff+i/8] |= 1 << (i % 8)
+			continue
+		}
+
+		t := cols[i].Type
+
+		if t == AnyType {
+			code, err := binaryRowsTypeCode(v.Type())
+			if err != nil {
+				return nil, err
+			}
+			buf = append(buf, code)
+
+			t = v.Type()
+		} else if v.Type() != t && !(v.Type() == IntegerType && t == Float64Type) {
+			return nil, fmt.Errorf("%w: value of type %s can not be encoded in column '%s' of type %s",
+				ErrInvalidTypes, v.Type(), cols[i].Column, t)
+		}
+
+		var err error
+
+		buf, err = appendBinaryValue(buf, t, v)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return buf, nil
+}
+
+func appendBinaryValue(buf []byte, t SQLValueType, v TypedValue) ([]byte, error) {
+	switch t {
+	case IntegerType:
+		return binary.AppendVarint(buf, v.RawValue().(int64)), nil
+	case Float64Type:
+		f, isFloat := v.RawValue().(float64)
+		if !isFloat {
+			f = float64(v.RawValue().(int64))
+		}
+		return binary.BigEndian.AppendUint64(buf, math.Float64bits(f)), nil
+	case BooleanType:
+		if v.RawValue().(bool) {
+			return append(buf, 1), nil
+		}
+		return append(buf, 0), nil
+	case VarcharType:
+		return appendBinaryString(buf, v.RawValue().(string)), nil
+	case BLOBType:
+		b := v.RawValue().([]byte)
+		buf = binary.AppendUvarint(buf, uint64(len(b)))
+		return append(buf, b...), nil
+	case TimestampType:
+		return binary.AppendVarint(buf, TimeToInt64(v.RawValue().(time.Time))), nil
+	case UUIDType:
+		u := v.RawValue().(uuid.UUID)
+		return append(buf, u[:]...), nil
+	case JSONType:
+		data, err := json.Marshal(v.RawValue())
+		if err != nil {
+			return nil, err
+		}
+		buf = binary.AppendUvarint(buf, uint64(len(data)))
+		return append(buf, data...), nil
+	case PointType:
+		p := v.RawValue().(GeoPoint)
+		buf = binary.BigEndian.AppendUint64(buf, math.Float64bits(p.Lat))
+		return binary.BigEndian.AppendUint64(buf, math.Float64bits(p.Lon)), nil
+	}
+	return nil, fmt.Errorf("%w: type %s can not be encoded", ErrInvalidTypes, t)
+}
+
+// RowDecoder reads the rows written by EncodeRows
+type RowDecoder struct {
+	r       *bufio.Reader
+	cols    []ColDescriptor
+	payload bytes.Buffer
+	done    bool
+}
+
+// NewRowDecoder reads the header of the encoded rows, returning ErrCorruptedData
+// when the input is not in the format written by EncodeRows
+func NewRowDecoder(r io.Reader) (*RowDecoder, error) {
+	if r == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	d := &RowDecoder{r: bufio.NewReader(r)}
+
+	magic := make([]byte, len(binaryRowsMagic))
+
+	_, err := io.ReadFull(d.r, magic)
+	if err != nil {
+		return nil, binaryRowsReadErr(err)
+	}
+
+	if !bytes.Equal(magic, binaryRowsMagic) {
+		return nil, fmt.Errorf("%w: unexpected header", ErrCorruptedData)
+	}
+
+	n, err := d.readUvarint()
+	if err != nil {
+		return nil, err
+	}
+
+	// columns are not preallocated as their number is not trusted
+	for i := uint64(0); i < n; i++ {
+		col, err := d.readColDescriptor()
+		if err != nil {
+			return nil, err
+		}
+		d.cols = append(d.cols, col)
+	}
+	return d, nil
+}
+
+func (d *RowDecoder) readColDescriptor() (ColDescriptor, error) {
+	var hdr [2]byte
+
+	_, err := io.ReadFull(d.r, hdr[:])
+	if err != nil {
+		return ColDescriptor{}, binaryRowsReadErr(err)
+	}
+
+	if int(hdr[0]) >= len(binaryRowsTypeCodes) {
+		return ColDescriptor{}, fmt.Errorf("%w: unknown column type", ErrCorruptedData)
+	}
+
+	col := ColDescriptor{
+		Type:     binaryRowsTypeCodes[hdr[0]],
+		Nullable: hdr[1]&binaryRowsColNullable != 0,
+	}
+
+	for _, s := range []*string{&col.AggFn, &col.Table, &col.Column} {
+		*s, err = d.readString()
+		if err != nil {
+			return ColDescriptor{}, err
+		}
+	}
+	return col, nil
+}
+
+func (d *RowDecoder) readString() (string, error) {
+	err := d.readBytes()
+	if err != nil {
+		return "", err
+	}
+	return d.payload.String(), nil
+}
+
+// readBytes reads a length-prefixed sequence of bytes into the payload buffer,
+// which grows as bytes are read so an invalid length doesn't cause a large allocation
+func (d *RowDecoder) readBytes() error {
+	n, err := d.readUvarint()
+	if err != nil {
+		return err
+	}
+
+	if n > math.MaxInt64 {
+		return fmt.Errorf("%w: invalid length", ErrCorruptedData)
+	}
+
+	d.payload.Reset()
+
+	copied, err := io.CopyN(&d.payload, d.r, int64(n))
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+
+	if copied < int64(n) {
+		return binaryRowsReadErr(io.ErrUnexpectedEOF)
+	}
+	return nil
+}
+
+func (d *RowDecoder) readUvarint() (uint64, error) {
+	var x uint64
+
+	for i := 0; i < binary.MaxVarintLen64; i++ {
+		b, err := d.r.ReadByte()
+		if err != nil {
+			return 0, binaryRowsReadErr(err)
+		}
+
+		if i == binary.MaxVarintLen64-1 && b > 1 {
+			break
+		}
+
+		x |= uint64(b&0x7f) << (7 * i)
+
+		if b < 0x80 {
+			return x, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: invalid length", ErrCorruptedData)
+}
+
+func binaryRowsReadErr(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("%w: %v", ErrCorruptedData, io.ErrUnexpectedEOF)
+	}
+	return err
+}
+
+// Columns returns the columns described by the header
+func (d *RowDecoder) Columns() []ColDescriptor {
+	return d.cols
+}
+
+// Read returns the next row, or ErrNoMoreRows once all the rows have been read.
+// Truncated or malformed input results in ErrCorruptedData.
+func (d *RowDecoder) Read() (*Row, error) {
+	if d.done {
+		return nil, ErrNoMoreRows
+	}
+
+	frame, err := d.r.ReadByte()
+	if err != nil {
+		return nil, binaryRowsReadErr(err)
+	}
+
+	switch frame {
+	case binaryRowsFrameEnd:
+		d.done = true
+		return nil, ErrNoMoreRows
+	case binaryRowsFrameRow:
+	default:
+		return nil, fmt.Errorf("%w: unknown frame", ErrCorruptedData)
+	}
+
+	err = d.readBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := decodeBinaryRow(d.payload.Bytes(), d.cols)
+	if err != nil {
+		return nil, err
+	}
+
+	row := &Row{
+		ValuesByPosition: values,
+		ValuesBySelector: make(map[string]TypedValue, len(d.cols)),
+	}
+
+	for i, col := range d.cols {
+		row.ValuesBySelector[col.Selector()] = values[i]
+	}
+	return row, nil
+}
+
+func decodeBinaryRow(payload []byte, cols []ColDescriptor) ([]TypedValue, error) {
+	bitmapLen := (len(cols) + 7) / 8
+	if len(payload) < bitmapLen {
+		return nil, fmt.Errorf("%w: invalid row", ErrCorruptedData)
+	}
+
+	bitmap, data := payload[:bitmapLen], payload[bitmapLen:]
+
+	values := make([]TypedValue, len(cols))
+
+	for i, col := range cols {
+		if bitmap[i/8]&(1<<(i%8)) != 0 {
+			values[i] = NewNull(col.Type)
+			continue
+		}
+
+		t := col.Type
+
+		if t == AnyType {
+			if len(data) == 0 || data[0] == 0 || int(data[0]) >= len(binaryRowsTypeCodes) {
+				return nil, fmt.Errorf("%w: invalid value type", ErrCorruptedData)
+			}
+
+			t = binaryRowsTypeCodes[data[0]]
+			data = data[1:]
+		}
+
+		v, n, err := decodeBinaryValue(data, t)
+		if err != nil {
+			return nil, err
+		}
+
+		values[i] = v
+		data = data[n:]
+	}
+
+	if len(data) > 0 {
+		return nil, fmt.Errorf("%w: invalid row", ErrCorruptedData)
+	}
+	return values, nil
+}
+
+func decodeBinaryValue(data []byte, t SQLValueType) (TypedValue, int, error) {
+	errInvalidValue := fmt.Errorf("%w: invalid %s value", ErrCorruptedData, t)
+
+	fixedLen := func(n int) error {
+		if len(data) < n {
+			return errInvalidValue
+		}
+		return nil
+	}
+
+	// returns the bytes of a length-prefixed value and the number of bytes it takes
+	prefixed := func() ([]byte, int, error) {
+		l, n := binary.Uvarint(data)
+		if n <= 0 || l > uint64(len(data)-n) {
+			return nil, 0, errInvalidValue
+		}
+		return data[n : n+int(l)], n + int(l), nil
+	}
+
+	switch t {
+	case IntegerType:
+		v, n := binary.Varint(data)
+		if n <= 0 {
+			return nil, 0, errInvalidValue
+		}
+		return NewInteger(v), n, nil
+	case Float64Type:
+		if err := fixedLen(8); err != nil {
+			return nil, 0, err
+		}
+		return NewFloat64(math.Float64frombits(binary.BigEndian.Uint64(data))), 8, nil
+	case BooleanType:
+		if len(data) == 0 || data[0] > 1 {
+			return nil, 0, errInvalidValue
+		}
+		return NewBool(data[0] == 1), 1, nil
+	case VarcharType:
+		b, n, err := prefixed()
+		if err != nil {
+			return nil, 0, err
+		}
+		return NewVarchar(string(b)), n, nil
+	case BLOBType:
+		b, n, err := prefixed()
+		if err != nil {
+			return nil, 0, err
+		}
+		return NewBlob(append([]byte{}, b...)), n, nil
+	case TimestampType:
+		v, n := binary.Varint(data)
+		if n <= 0 {
+			return nil, 0, errInvalidValue
+		}
+		return NewTimestamp(TimeFromInt64(v)), n, nil
+	case UUIDType:
+		if err := fixedLen(16); err != nil {
+			return nil, 0, err
+		}
+		u, _ := uuid.FromBytes(data[:16])
+		return NewUUID(u), 16, nil
+	case JSONType:
+		b, n, err := prefixed()
+		if err != nil {
+			return nil, 0, err
+		}
+
+		var val interface{}
+
+		err = json.Unmarshal(b, &val)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: %v", errInvalidValue, err)
+		}
+		return NewJson(val), n, nil
+	case PointType:
+		if err := fixedLen(16); err != nil {
+			return nil, 0, err
+		}
+
+		p, err := NewPoint(
+			math.Float64frombits(binary.BigEndian.Uint64(data)),
+			math.Float64frombits(binary.BigEndian.Uint64(data[8:])),
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("%w: %v", errInvalidValue, err)
+		}
+		return p, 16, nil
+	}
+	return nil, 0, errInvalidValue
+}
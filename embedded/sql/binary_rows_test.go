@@ -0,0 +1,246 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+)
+
+func setupBinaryRowsTest(t testing.TB) *Engine {
+	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+
+	engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		CREATE TABLE values_table (
+			id INTEGER AUTO_INCREMENT,
+			n INTEGER,
+			f FLOAT,
+			b BOOLEAN,
+			s VARCHAR,
+			bl BLOB,
+			ts TIMESTAMP,
+			u UUID,
+			j JSON,
+			p POINT,
+			PRIMARY KEY id
+		)`, nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		INSERT INTO values_table (n, f, b, s, bl, ts, u, j, p) VALUES
+			(-12345678901, 3.25, true, 'immudb', x'00ff10', CAST('2025-01-02 03:04:05.123456' AS TIMESTAMP), CAST('19bb3a41-5c6a-4e2c-9f1e-2f1d6c0b7a10' AS UUID), '{"a": [1, "b", null]}', POINT(41.9, 12.5)),
+			(0, -0.5, false, '', x'', CAST('1970-01-01 00:00:00' AS TIMESTAMP), CAST('00000000-0000-0000-0000-000000000000' AS UUID), '[true]', POINT(-90, 180)),
+			(NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL, NULL)
+	`, nil)
+	require.NoError(t, err)
+
+	return engine
+}
+
+type columnsRowReader struct {
+	mockRowReader
+	cols []ColDescriptor
+}
+
+func (r *columnsRowReader) Columns(ctx context.Context) ([]ColDescriptor, error) {
+	return r.cols, nil
+}
+
+func encodeRowsTest(t testing.TB, engine *Engine, sql string) []byte {
+	r, err := engine.Query(context.Background(), nil, sql, nil)
+	require.NoError(t, err)
+	defer r.Close()
+
+	var buf bytes.Buffer
+
+	err = EncodeRows(context.Background(), &buf, r)
+	require.NoError(t, err)
+
+	return buf.Bytes()
+}
+
+func decodeRowsTest(data []byte) ([]ColDescriptor, []*Row, error) {
+	d, err := NewRowDecoder(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var rows []*Row
+
+	for {
+		row, err := d.Read()
+		if errors.Is(err, ErrNoMoreRows) {
+			return d.Columns(), rows, nil
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		rows = append(rows, row)
+	}
+}
+
+func TestEncodeRows(t *testing.T) {
+	engine := setupBinaryRowsTest(t)
+
+	t.Run("values round-trip", func(t *testing.T) {
+		q := "SELECT id, n, f, b, s, bl, ts, u, j, p FROM values_table"
+
+		expected, err := engine.queryAll(context.Background(), nil, q, nil)
+		require.NoError(t, err)
+
+		r, err := engine.Query(context.Background(), nil, q, nil)
+		require.NoError(t, err)
+		expectedCols, err := r.Columns(context.Background())
+		require.NoError(t, err)
+		require.NoError(t, r.Close())
+
+		cols, rows, err := decodeRowsTest(encodeRowsTest(t, engine, q))
+		require.NoError(t, err)
+
+		require.Len(t, cols, len(expectedCols))
+		for i, col := range cols {
+			require.Equal(t, expectedCols[i].Selector(), col.Selector())
+			require.Equal(t, expectedCols[i].Type, col.Type)
+			require.Equal(t, expectedCols[i].Nullable, col.Nullable)
+		}
+
+		require.Len(t, rows, len(expected))
+
+		for i, row := range rows {
+			require.Len(t, row.ValuesByPosition, len(cols))
+
+			for j, v := range row.ValuesByPosition {
+				ev := expected[i].ValuesByPosition[j]
+
+				require.Equal(t, ev.IsNull(), v.IsNull())
+				require.Equal(t, ev.Type(), v.Type())
+				require.Equal(t, ev.RawValue(), v.RawValue(), cols[j].Column)
+				require.Same(t, v, row.ValuesBySelector[cols[j].Selector()])
+			}
+		}
+
+		// empty values are not confused with NULL
+		require.Equal(t, "", rows[1].ValuesByPosition[4].RawValue())
+		require.Equal(t, []byte{}, rows[1].ValuesByPosition[5].RawValue())
+		require.True(t, rows[2].ValuesByPosition[5].IsNull())
+	})
+
+	t.Run("columns of type ANY", func(t *testing.T) {
+		cols, rows, err := decodeRowsTest(encodeRowsTest(t, engine, "SELECT NULL AS x FROM values_table"))
+		require.NoError(t, err)
+		require.Len(t, cols, 1)
+		require.Equal(t, AnyType, cols[0].Type)
+		require.Len(t, rows, 3)
+
+		for _, row := range rows {
+			require.True(t, row.ValuesByPosition[0].IsNull())
+		}
+
+		// values of different types in the same column
+		reader := &columnsRowReader{
+			cols: []ColDescriptor{{Column: "x", Type: AnyType}},
+			mockRowReader: mockRowReader{rows: []*Row{
+				{ValuesByPosition: []TypedValue{NewInteger(1)}},
+				{ValuesByPosition: []TypedValue{NewVarchar("a")}},
+				{ValuesByPosition: []TypedValue{NewNull(AnyType)}},
+			}},
+		}
+
+		var buf bytes.Buffer
+		require.NoError(t, EncodeRows(context.Background(), &buf, reader))
+
+		_, rows, err = decodeRowsTest(buf.Bytes())
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		require.Equal(t, int64(1), rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, "a", rows[1].ValuesByPosition[0].RawValue())
+		require.True(t, rows[2].ValuesByPosition[0].IsNull())
+	})
+
+	t.Run("empty results", func(t *testing.T) {
+		cols, rows, err := decodeRowsTest(encodeRowsTest(t, engine, "SELECT id, s FROM values_table WHERE id > 10"))
+		require.NoError(t, err)
+		require.Len(t, cols, 2)
+		require.Empty(t, rows)
+	})
+
+	t.Run("values not matching the column type", func(t *testing.T) {
+		reader := &columnsRowReader{
+			cols:          []ColDescriptor{{Column: "x", Type: IntegerType}},
+			mockRowReader: mockRowReader{rows: []*Row{{ValuesByPosition: []TypedValue{NewVarchar("a")}}}},
+		}
+
+		var buf bytes.Buffer
+		require.ErrorIs(t, EncodeRows(context.Background(), &buf, reader), ErrInvalidTypes)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		require.ErrorIs(t, EncodeRows(context.Background(), nil, &mockRowReader{}), ErrIllegalArguments)
+		require.ErrorIs(t, EncodeRows(context.Background(), &bytes.Buffer{}, nil), ErrIllegalArguments)
+
+		_, err := NewRowDecoder(nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+	})
+
+	t.Run("truncated input", func(t *testing.T) {
+		data := encodeRowsTest(t, engine, "SELECT id, n, f, b, s, bl, ts, u, j, p FROM values_table")
+
+		for i := 0; i < len(data); i++ {
+			_, _, err := decodeRowsTest(data[:i])
+			require.ErrorIs(t, err, ErrCorruptedData)
+		}
+
+		_, _, err := decodeRowsTest(append(data[:4:4], 0xff))
+		require.ErrorIs(t, err, ErrCorruptedData)
+
+		_, _, err = decodeRowsTest([]byte("not encoded rows"))
+		require.ErrorIs(t, err, ErrCorruptedData)
+	})
+}
+
+func FuzzDecodeRows(f *testing.F) {
+	engine := setupBinaryRowsTest(f)
+
+	f.Add(encodeRowsTest(f, engine, "SELECT id, n, f, b, s, bl, ts, u, j, p FROM values_table"))
+	f.Add(encodeRowsTest(f, engine, "SELECT NULL AS x, s FROM values_table"))
+	f.Add(encodeRowsTest(f, engine, "SELECT id FROM values_table WHERE id > 10"))
+	f.Add([]byte{})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		_, rows, err := decodeRowsTest(data)
+		if err != nil {
+			require.ErrorIs(t, err, ErrCorruptedData)
+			return
+		}
+
+		for _, row := range rows {
+			for _, v := range row.ValuesByPosition {
+				require.NotNil(t, v)
+			}
+		}
+	})
+}
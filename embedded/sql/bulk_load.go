@@ -0,0 +1,206 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"iter"
+)
+
+// BulkLoadOpts determines how rows are loaded by Engine.BulkLoad
+type BulkLoadOpts struct {
+	// Columns are the columns the values of each row are assigned to, in order.
+	// When not specified, rows must hold a value for each non-generated column of the table
+	Columns []string
+	// BatchSize is the number of rows inserted per transaction
+	BatchSize int
+	// ProgressInterval is the minimum number of rows loaded between consecutive calls to OnProgress
+	ProgressInterval int
+	// OnProgress is called with the rows loaded so far once a batch is committed,
+	// and when loading completes. Calls are made from the goroutine loading the rows
+	OnProgress func(LoadResult)
+}
+
+func DefaultBulkLoadOpts() BulkLoadOpts {
+	return BulkLoadOpts{
+		BatchSize: 1000,
+	}
+}
+
+func (opts BulkLoadOpts) Validate() error {
+	if opts.BatchSize < 1 {
+		return fmt.Errorf("%w: invalid BatchSize", ErrIllegalArguments)
+	}
+
+	if opts.ProgressInterval < 0 {
+		return fmt.Errorf("%w: invalid ProgressInterval", ErrIllegalArguments)
+	}
+
+	return nil
+}
+
+// LoadResult describes the rows loaded by Engine.BulkLoad
+type LoadResult struct {
+	// Rows is the number of rows committed
+	Rows int64
+	// Bytes is the encoded size of the values of the rows committed
+	Bytes int64
+	// Batches is the number of transactions committed
+	Batches int
+}
+
+// BulkLoad inserts the rows into the table, in transactions of up to BatchSize rows each.
+// Values are taken by position from each row. Loading stops at the first error or once the
+// context is cancelled, in which case the batch being filled is discarded while the batches
+// already committed are kept, as described by the returned result.
+func (e *Engine) BulkLoad(ctx context.Context, table string, rows iter.Seq[*Row], opts BulkLoadOpts) (LoadResult, error) {
+	var res LoadResult
+
+	err := opts.Validate()
+	if err != nil {
+		return res, err
+	}
+
+	if rows == nil {
+		return res, fmt.Errorf("%w: no rows to load", ErrIllegalArguments)
+	}
+
+	cols := opts.Columns
+	if len(cols) == 0 {
+		cols, err = e.tableColumnNames(ctx, table)
+		if err != nil {
+			return res, err
+		}
+	}
+
+	var reported int64
+
+	report := func(force bool) {
+		if opts.OnProgress == nil || res.Rows == reported {
+			return
+		}
+
+		if force || res.Rows-reported >= int64(opts.ProgressInterval) {
+			reported = res.Rows
+			opts.OnProgress(res)
+		}
+	}
+
+	batch := make([]*RowSpec, 0, opts.BatchSize)
+	var batchBytes int64
+
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		// a cancelled load does not commit the rows read after the last committed batch
+		err := ctx.Err()
+		if err != nil {
+			return err
+		}
+
+		stmt := &UpsertIntoStmt{
+			isInsert: true,
+			tableRef: &tableRef{table: table},
+			cols:     cols,
+			ds:       &valuesDataSource{rows: batch},
+		}
+
+		_, _, _, err = e.execPreparedStmts(ctx, nil, []SQLStmt{stmt}, nil)
+		if err != nil {
+			return err
+		}
+
+		res.Rows += int64(len(batch))
+		res.Bytes += batchBytes
+		res.Batches++
+
+		batch = make([]*RowSpec, 0, opts.BatchSize)
+		batchBytes = 0
+
+		report(false)
+
+		return nil
+	}
+
+	for row := range rows {
+		err := ctx.Err()
+		if err != nil {
+			return res, err
+		}
+
+		if row == nil || len(row.ValuesByPosition) != len(cols) {
+			return res, fmt.Errorf("%w: row %d", ErrInvalidNumberOfValues, res.Rows+int64(len(batch)))
+		}
+
+		values := make([]ValueExp, len(row.ValuesByPosition))
+
+		for i, v := range row.ValuesByPosition {
+			if v == nil {
+				v = NewNull(AnyType)
+			}
+
+			encVal, err := EncodeNullableValue(v, v.Type(), -1)
+			if err != nil {
+				return res, err
+			}
+
+			values[i] = v
+			batchBytes += int64(len(encVal))
+		}
+
+		batch = append(batch, &RowSpec{Values: values})
+
+		if len(batch) == opts.BatchSize {
+			err := flush()
+			if err != nil {
+				return res, err
+			}
+		}
+	}
+
+	err = flush()
+	if err != nil {
+		return res, err
+	}
+
+	report(true)
+
+	return res, nil
+}
+
+func (e *Engine) tableColumnNames(ctx context.Context, table string) ([]string, error) {
+	catalog, err := e.Catalog(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	tbl, err := catalog.GetTableByName(table)
+	if err != nil {
+		return nil, err
+	}
+
+	cols := make([]string, 0, len(tbl.Cols()))
+	for _, col := range tbl.Cols() {
+		if !col.IsGenerated() {
+			cols = append(cols, col.Name())
+		}
+	}
+	return cols, nil
+}
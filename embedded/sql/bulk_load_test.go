@@ -0,0 +1,187 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func generateRows(n int, fn func(i int) *Row) iter.Seq[*Row] {
+	return func(yield func(*Row) bool) {
+		for i := 0; i < n; i++ {
+			if !yield(fn(i)) {
+				return
+			}
+		}
+	}
+}
+
+func entryRow(i int) *Row {
+	return &Row{
+		ValuesByPosition: []TypedValue{
+			&Integer{val: int64(i)},
+			&Varchar{val: fmt.Sprintf("name%d", i)},
+		},
+	}
+}
+
+func TestBulkLoad(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, "CREATE TABLE entries (id INTEGER, name VARCHAR, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	countRows := func(t *testing.T) int64 {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT COUNT(*) FROM entries", nil)
+		require.NoError(t, err)
+		return rows[0].ValuesByPosition[0].RawValue().(int64)
+	}
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		_, err := engine.BulkLoad(context.Background(), "entries", generateRows(1, entryRow), BulkLoadOpts{})
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = engine.BulkLoad(context.Background(), "entries", nil, DefaultBulkLoadOpts())
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		opts := DefaultBulkLoadOpts()
+		opts.ProgressInterval = -1
+
+		_, err = engine.BulkLoad(context.Background(), "entries", generateRows(1, entryRow), opts)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = engine.BulkLoad(context.Background(), "missing", generateRows(1, entryRow), DefaultBulkLoadOpts())
+		require.ErrorIs(t, err, ErrTableDoesNotExist)
+
+		_, err = engine.BulkLoad(context.Background(), "entries", generateRows(1, func(i int) *Row {
+			return &Row{ValuesByPosition: []TypedValue{&Integer{val: int64(i)}}}
+		}), DefaultBulkLoadOpts())
+		require.ErrorIs(t, err, ErrInvalidNumberOfValues)
+	})
+
+	t.Run("full load", func(t *testing.T) {
+		opts := DefaultBulkLoadOpts()
+		opts.BatchSize = 300
+
+		res, err := engine.BulkLoad(context.Background(), "entries", generateRows(1000, entryRow), opts)
+		require.NoError(t, err)
+		require.Equal(t, int64(1000), res.Rows)
+		require.Equal(t, 4, res.Batches)
+		require.Greater(t, res.Bytes, int64(1000*(8+5)))
+
+		require.Equal(t, int64(1000), countRows(t))
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT name FROM entries WHERE id = 999", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, "name999", rows[0].ValuesByPosition[0].RawValue())
+	})
+
+	t.Run("load into some columns", func(t *testing.T) {
+		opts := DefaultBulkLoadOpts()
+		opts.Columns = []string{"id"}
+
+		res, err := engine.BulkLoad(context.Background(), "entries", generateRows(10, func(i int) *Row {
+			return &Row{ValuesByPosition: []TypedValue{&Integer{val: int64(2000 + i)}}}
+		}), opts)
+		require.NoError(t, err)
+		require.Equal(t, int64(10), res.Rows)
+		require.Equal(t, 1, res.Batches)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT COUNT(*) FROM entries WHERE name IS NULL", nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(10), rows[0].ValuesByPosition[0].RawValue())
+	})
+
+	t.Run("a failing batch stops the load", func(t *testing.T) {
+		opts := DefaultBulkLoadOpts()
+		opts.BatchSize = 100
+
+		// rows from 1000 on do not exist yet, but the second batch includes existing rows
+		res, err := engine.BulkLoad(context.Background(), "entries", generateRows(300, func(i int) *Row {
+			if i < 100 {
+				return entryRow(3000 + i)
+			}
+			return entryRow(i)
+		}), opts)
+		require.Error(t, err)
+		require.Equal(t, int64(100), res.Rows)
+		require.Equal(t, 1, res.Batches)
+
+		require.Equal(t, int64(1110), countRows(t))
+	})
+
+	t.Run("cancelled load", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		opts := DefaultBulkLoadOpts()
+		opts.BatchSize = 100
+
+		res, err := engine.BulkLoad(ctx, "entries", generateRows(1000, func(i int) *Row {
+			if i == 250 {
+				cancel()
+			}
+			return entryRow(10000 + i)
+		}), opts)
+		require.ErrorIs(t, err, context.Canceled)
+
+		// committed batches are kept
+		require.Equal(t, int64(200), res.Rows)
+		require.Equal(t, 2, res.Batches)
+
+		require.Equal(t, int64(1310), countRows(t))
+	})
+
+	t.Run("progress is reported", func(t *testing.T) {
+		var progress []LoadResult
+
+		opts := DefaultBulkLoadOpts()
+		opts.BatchSize = 100
+		opts.ProgressInterval = 250
+		opts.OnProgress = func(res LoadResult) {
+			progress = append(progress, res)
+		}
+
+		res, err := engine.BulkLoad(context.Background(), "entries", generateRows(1000, func(i int) *Row {
+			return entryRow(20000 + i)
+		}), opts)
+		require.NoError(t, err)
+		require.Len(t, progress, 4)
+
+		for i, rows := range []int64{300, 600, 900, 1000} {
+			require.Equal(t, rows, progress[i].Rows)
+			require.Equal(t, int(rows/100), progress[i].Batches)
+			require.Greater(t, progress[i].Bytes, int64(0))
+		}
+		require.Equal(t, res, progress[3])
+
+		progress = nil
+		opts.ProgressInterval = 0
+
+		_, err = engine.BulkLoad(context.Background(), "entries", generateRows(1000, func(i int) *Row {
+			return entryRow(30000 + i)
+		}), opts)
+		require.NoError(t, err)
+		require.Len(t, progress, 10)
+	})
+}
@@ -24,6 +24,8 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -34,10 +36,13 @@ import (
 // Catalog represents a database catalog containing metadata for all tables in the database.
 type Catalog struct {
 	enginePrefix []byte
+	keyEncoder   KeyEncoder
+	rowChecksums *rowChecksums
 
 	tables       []*Table
 	tablesByID   map[uint32]*Table
 	tablesByName map[string]*Table
+	viewsByName  map[string]*View
 
 	maxTableID uint32 // The maxTableID variable is used to assign unique ids to new tables as they are created.
 }
@@ -62,21 +67,37 @@ type Table struct {
 	indexes          []*Index
 	indexesByName    map[string]*Index
 	indexesByColID   map[uint32][]*Index
+	fullTextIndexes  []*Index
 	checkConstraints map[string]CheckConstraint
 	primaryIndex     *Index
 	autoIncrementPK  bool
 	maxPK            int64
+	countsRows       bool // the number of rows is kept along with them
+	comment          string
+	triggersByName   map[string]*Trigger
 
 	maxColID   uint32
 	maxIndexID uint32
 }
 
+// View is a named query expanded every time the view is referenced.
+type View struct {
+	name string
+	sql  string
+}
+
+// Index entries are keyed by the values of the indexed columns followed by the primary key of
+// the row, so rows with equal values are always scanned in primary key order, or in reverse order
+// when scanning in descending order.
 type Index struct {
-	table    *Table
-	id       uint32
-	unique   bool
-	cols     []*Column
-	colsByID map[uint32]*Column
+	table         *Table
+	id            uint32
+	unique        bool
+	nullsDistinct bool
+	canonicalKeys bool // values comparing as equal share the same key, see encodeRawValueAsKey
+	fullText      bool
+	cols          []*Column
+	colsByID      map[uint32]*Column
 }
 
 type Column struct {
@@ -87,13 +108,17 @@ type Column struct {
 	maxLen        int
 	autoIncrement bool
 	notNull       bool
+	generatedExp  ValueExp // expression computing the value of a generated column
+	comment       string
 }
 
 func newCatalog(enginePrefix []byte) *Catalog {
 	ctlg := &Catalog{
 		enginePrefix: enginePrefix,
+		keyEncoder:   defaultKeyEncoder{},
 		tablesByID:   make(map[uint32]*Table),
 		tablesByName: make(map[string]*Table),
+		viewsByName:  make(map[string]*View),
 	}
 
 	pgTypeTable := &Table{
@@ -163,6 +188,32 @@ func (catlg *Catalog) GetTableByName(name string) (*Table, error) {
 	return table, nil
 }
 
+func (catlg *Catalog) ExistView(view string) bool {
+	_, exists := catlg.viewsByName[view]
+	return exists
+}
+
+func (catlg *Catalog) GetViews() []*View {
+	vs := make([]*View, 0, len(catlg.viewsByName))
+
+	for _, v := range catlg.viewsByName {
+		vs = append(vs, v)
+	}
+
+	sort.Slice(vs, func(i, j int) bool {
+		return vs[i].name < vs[j].name
+	})
+	return vs
+}
+
+func (catlg *Catalog) GetViewByName(name string) (*View, error) {
+	view, exists := catlg.viewsByName[name]
+	if !exists {
+		return nil, fmt.Errorf("%w (%s)", ErrViewDoesNotExist, name)
+	}
+	return view, nil
+}
+
 func (catlg *Catalog) GetTableByID(id uint32) (*Table, error) {
 	table, exists := catlg.tablesByID[id]
 	if !exists {
@@ -197,6 +248,11 @@ func (t *Table) Name() string {
 	return t.name
 }
 
+// Comment returns the comment set by COMMENT ON TABLE, if any
+func (t *Table) Comment() string {
+	return t.comment
+}
+
 func (t *Table) PrimaryIndex() *Index {
 	return t.primaryIndex
 }
@@ -206,7 +262,7 @@ func (t *Table) IsIndexed(colName string) (indexed bool, err error) {
 	if err != nil {
 		return false, err
 	}
-	return len(t.indexesByColID[col.id]) > 0, nil
+	return len(t.indexesByColID[col.id]) > 0 || t.fullTextIndexByColID(col.id) != nil, nil
 }
 
 func (t *Table) GetColumnByName(name string) (*Column, error) {
@@ -237,6 +293,24 @@ func (t *Table) GetIndexes() []*Index {
 	return idxs
 }
 
+// GetFullTextIndexes returns the full-text indexes of the table.
+func (t *Table) GetFullTextIndexes() []*Index {
+	idxs := make([]*Index, 0, len(t.fullTextIndexes))
+
+	idxs = append(idxs, t.fullTextIndexes...)
+
+	return idxs
+}
+
+func (t *Table) fullTextIndexByColID(colID uint32) *Index {
+	for _, idx := range t.fullTextIndexes {
+		if idx.cols[0].id == colID {
+			return idx
+		}
+	}
+	return nil
+}
+
 func (t *Table) GetIndexesByColID(colID uint32) []*Index {
 	idxs := make([]*Index, 0, len(t.indexes))
 
@@ -257,6 +331,17 @@ func (i *Index) IsUnique() bool {
 	return i.unique
 }
 
+// NullsDistinct returns whether entries of the unique index holding a NULL value in any of its
+// columns never conflict with other entries. Unique indexes are created this way unless NULLS NOT
+// DISTINCT is specified, while the ones created by previous releases treat NULL values as equal.
+func (i *Index) NullsDistinct() bool {
+	return i.nullsDistinct
+}
+
+func (i *Index) IsFullText() bool {
+	return i.fullText
+}
+
 func (i *Index) Cols() []*Column {
 	return i.cols
 }
@@ -282,9 +367,10 @@ func ordExpsHaveSameDirection(exps []*OrdExp) bool {
 		return true
 	}
 
+	// index entries place NULL values as the smallest ones
 	desc := exps[0].descOrder
-	for _, e := range exps[1:] {
-		if e.descOrder != desc {
+	for _, e := range exps {
+		if e.descOrder != desc || e.nullsFirst() == desc {
 			return false
 		}
 	}
@@ -347,6 +433,9 @@ func (i *Index) sortableUsing(columns []*OrdExp, rangesByColID map[uint32]*typed
 }
 
 func (i *Index) Name() string {
+	if i.fullText {
+		return "fulltext:" + indexName(i.table.name, i.cols)
+	}
 	return indexName(i.table.name, i.cols)
 }
 
@@ -398,6 +487,10 @@ func (catlg *Catalog) newTable(name string, colsSpec map[uint32]*ColSpec, checkC
 		return nil, fmt.Errorf("%w (%s)", ErrTableAlreadyExists, name)
 	}
 
+	if catlg.ExistView(name) {
+		return nil, fmt.Errorf("%w (%s)", ErrViewAlreadyExists, name)
+	}
+
 	// Generate a new ID for the table by incrementing the 'maxTableID' variable of the 'catalog' instance.
 	id := (catlg.maxTableID + 1)
 
@@ -419,6 +512,7 @@ func (catlg *Catalog) newTable(name string, colsSpec map[uint32]*ColSpec, checkC
 		indexesByName:    make(map[string]*Index),
 		indexesByColID:   make(map[uint32][]*Index),
 		checkConstraints: checkConstraints,
+		triggersByName:   make(map[string]*Trigger),
 		maxColID:         maxColID,
 	}
 
@@ -454,6 +548,7 @@ func (catlg *Catalog) newTable(name string, colsSpec map[uint32]*ColSpec, checkC
 			maxLen:        cs.maxLen,
 			autoIncrement: cs.autoIncrement,
 			notNull:       cs.notNull,
+			generatedExp:  cs.generatedExp,
 		}
 
 		table.cols = append(table.cols, col)
@@ -494,6 +589,74 @@ func (catlg *Catalog) deleteTable(table *Table) error {
 	return nil
 }
 
+func (catlg *Catalog) newView(name, sql string) (*View, error) {
+	if len(name) == 0 || len(sql) == 0 {
+		return nil, ErrIllegalArguments
+	}
+
+	if catlg.ExistTable(name) {
+		return nil, fmt.Errorf("%w (%s)", ErrTableAlreadyExists, name)
+	}
+
+	if catlg.ExistView(name) {
+		return nil, fmt.Errorf("%w (%s)", ErrViewAlreadyExists, name)
+	}
+
+	view := &View{name: name, sql: sql}
+	catlg.viewsByName[name] = view
+
+	return view, nil
+}
+
+func (catlg *Catalog) deleteView(view *View) error {
+	if !catlg.ExistView(view.name) {
+		return ErrViewDoesNotExist
+	}
+
+	delete(catlg.viewsByName, view.name)
+	return nil
+}
+
+func (v *View) Name() string {
+	return v.name
+}
+
+// SQL returns the text of the query the view expands to.
+func (v *View) SQL() string {
+	return v.sql
+}
+
+// newFullTextIndex creates an inverted index over the terms of a single VARCHAR column.
+// Full-text indexes share the id space of regular indexes but are kept apart from them,
+// as they can not be used to scan the table in any specific order.
+func (t *Table) newFullTextIndex(colID uint32) (*Index, error) {
+	col, err := t.GetColumnByID(colID)
+	if err != nil {
+		return nil, err
+	}
+
+	if col.colType != VarcharType {
+		return nil, fmt.Errorf("%w: full-text indexes can only be created on %s columns", ErrInvalidTypes, VarcharType)
+	}
+
+	if t.fullTextIndexByColID(colID) != nil {
+		return nil, ErrIndexAlreadyExists
+	}
+
+	index := &Index{
+		id:       t.maxIndexID,
+		table:    t,
+		fullText: true,
+		cols:     []*Column{col},
+		colsByID: map[uint32]*Column{col.id: col},
+	}
+
+	t.fullTextIndexes = append(t.fullTextIndexes, index)
+	t.maxIndexID++
+
+	return index, nil
+}
+
 func (t *Table) newIndex(unique bool, colIDs []uint32) (index *Index, err error) {
 	if len(colIDs) < 1 {
 		return nil, ErrIllegalArguments
@@ -565,6 +728,11 @@ func (t *Table) newColumn(spec *ColSpec) (*Column, error) {
 		return nil, fmt.Errorf("%w (%s)", ErrNewColumnMustBeNullable, spec.colName)
 	}
 
+	if spec.generatedExp != nil {
+		// values of the existing rows would not be computed
+		return nil, fmt.Errorf("%w: generated column '%s' can not be added to an existing table", ErrIllegalArguments, spec.colName)
+	}
+
 	if !validMaxLenForType(spec.maxLen, spec.colType) {
 		return nil, fmt.Errorf("%w (%s)", ErrLimitedMaxLen, spec.colName)
 	}
@@ -608,6 +776,10 @@ func (ctlg *Catalog) renameTable(oldName, newName string) (*Table, error) {
 		return nil, fmt.Errorf("%w (%s)", ErrTableAlreadyExists, newName)
 	}
 
+	if ctlg.ExistView(newName) {
+		return nil, fmt.Errorf("%w (%s)", ErrViewAlreadyExists, newName)
+	}
+
 	t.name = newName
 
 	delete(ctlg.tablesByName, oldName)
@@ -635,6 +807,10 @@ func (t *Table) renameColumn(oldName, newName string) (*Column, error) {
 		return nil, fmt.Errorf("%w (%s)", ErrColumnAlreadyExists, newName)
 	}
 
+	if genCol := t.generatedColumnUsing(col); genCol != nil {
+		return nil, fmt.Errorf("%w: column '%s' is used by generated column '%s'", ErrIllegalArguments, oldName, genCol.colName)
+	}
+
 	col.colName = newName
 
 	delete(t.colsByName, oldName)
@@ -679,6 +855,11 @@ func (t *Table) deleteCheck(name string) (uint32, error) {
 }
 
 func (t *Table) deleteIndex(index *Index) error {
+	if index.IsFullText() {
+		t.fullTextIndexes = slices.DeleteFunc(t.fullTextIndexes, func(i *Index) bool { return i.id == index.id })
+		return nil
+	}
+
 	if index.IsPrimary() {
 		return fmt.Errorf("%w: primary key index can NOT be deleted", ErrIllegalArguments)
 	}
@@ -706,6 +887,11 @@ func (c *Column) Name() string {
 	return c.colName
 }
 
+// Comment returns the comment set by COMMENT ON COLUMN, if any
+func (c *Column) Comment() string {
+	return c.comment
+}
+
 func (c *Column) Type() SQLValueType {
 	return c.colType
 }
@@ -722,6 +908,8 @@ func (c *Column) MaxLen() int {
 		return 8
 	case UUIDType:
 		return 16
+	case PointType:
+		return 16
 	}
 
 	return c.maxLen
@@ -735,6 +923,11 @@ func (c *Column) IsAutoIncremental() bool {
 	return c.autoIncrement
 }
 
+// IsGenerated returns true when the values of the column are computed from the rest of the columns
+func (c *Column) IsGenerated() bool {
+	return c.generatedExp != nil
+}
+
 func validMaxLenForType(maxLen int, sqlType SQLValueType) bool {
 	switch sqlType {
 	case BooleanType:
@@ -747,6 +940,8 @@ func validMaxLenForType(maxLen int, sqlType SQLValueType) bool {
 		return maxLen == 0 || maxLen == 8
 	case UUIDType:
 		return maxLen == 0 || maxLen == 16
+	case PointType:
+		return maxLen == 0 || maxLen == 16
 	}
 
 	return maxLen >= 0
@@ -757,6 +952,37 @@ func (catlg *Catalog) load(ctx context.Context, tx *store.OngoingTx) error {
 }
 
 func (catlg *Catalog) loadCatalog(ctx context.Context, tx *store.OngoingTx, copyToTx bool) error {
+	if err := catlg.loadTables(ctx, tx, copyToTx); err != nil {
+		return err
+	}
+	return catlg.loadViews(ctx, tx, copyToTx)
+}
+
+func (catlg *Catalog) loadViews(ctx context.Context, tx *store.OngoingTx, copyToTx bool) error {
+	prefix := MapKey(catlg.enginePrefix, catalogViewPrefix, EncodeID(DatabaseID))
+
+	return iteratePrefix(ctx, tx, prefix, func(key, value []byte, deleted bool) error {
+		if deleted {
+			return nil
+		}
+
+		name, err := unmapViewName(catlg.enginePrefix, key)
+		if err != nil {
+			return err
+		}
+
+		if _, err := catlg.newView(name, string(value)); err != nil {
+			return err
+		}
+
+		if copyToTx {
+			return tx.Set(key, nil, value)
+		}
+		return nil
+	})
+}
+
+func (catlg *Catalog) loadTables(ctx context.Context, tx *store.OngoingTx, copyToTx bool) error {
 	prefix := MapKey(catlg.enginePrefix, catalogTablePrefix, EncodeID(1))
 
 	return iteratePrefix(ctx, tx, prefix, func(key, value []byte, deleted bool) error {
@@ -798,13 +1024,21 @@ func (catlg *Catalog) loadCatalog(ctx context.Context, tx *store.OngoingTx, copy
 				return err
 			}
 		}
+
+		if err := table.loadComments(ctx, catlg.enginePrefix, tx, copyToTx); err != nil {
+			return err
+		}
+
+		if err := table.loadTriggers(ctx, catlg.enginePrefix, tx, copyToTx); err != nil {
+			return err
+		}
 		return table.loadIndexes(ctx, catlg.enginePrefix, tx, copyToTx)
 	})
 }
 
 func loadMaxPK(ctx context.Context, sqlPrefix []byte, tx *store.OngoingTx, table *Table) ([]byte, error) {
 	pkReaderSpec := store.KeyReaderSpec{
-		Prefix:    MapKey(sqlPrefix, MappedPrefix, EncodeID(table.id), EncodeID(table.primaryIndex.id)),
+		Prefix:    MapKey(sqlPrefix, string(table.keyEncoder().IndexPrefix(table.id, table.primaryIndex.id))),
 		DescOrder: true,
 	}
 
@@ -865,13 +1099,34 @@ func loadColSpec(sqlPrefix, key, value []byte, tableID uint32) (*ColSpec, uint32
 		return nil, 0, ErrCorruptedData
 	}
 
-	return &ColSpec{
+	spec := &ColSpec{
 		colName:       string(value[5:]),
 		colType:       colType,
 		maxLen:        int(binary.BigEndian.Uint32(value[1:])),
 		autoIncrement: value[0]&autoIncrementFlag != 0,
 		notNull:       value[0]&nullableFlag != 0,
-	}, colID, nil
+	}
+
+	if value[0]&generatedFlag != 0 {
+		if len(value) < 9 {
+			return nil, 0, ErrCorruptedData
+		}
+
+		nameLen := int(binary.BigEndian.Uint32(value[5:]))
+		if len(value) < 9+nameLen {
+			return nil, 0, ErrCorruptedData
+		}
+
+		spec.colName = string(value[9 : 9+nameLen])
+
+		exp, err := parseStoredExp(string(value[9+nameLen:]))
+		if err != nil {
+			return nil, 0, err
+		}
+		spec.generatedExp = exp
+	}
+
+	return spec, colID, nil
 }
 
 func loadCheckConstraints(ctx context.Context, dbID, tableID uint32, tx *store.OngoingTx, sqlPrefix []byte, copyToTx bool) (map[string]CheckConstraint, error) {
@@ -937,11 +1192,28 @@ func (table *Table) loadIndexes(ctx context.Context, sqlPrefix []byte, tx *store
 				colIDs = append(colIDs, colID)
 			}
 
-			index, err := table.newIndex(value[0] > 0, colIDs)
+			var index *Index
+
+			if value[0]&fullTextIndexFlag != 0 {
+				if len(colIDs) != 1 {
+					return ErrCorruptedData
+				}
+
+				index, err = table.newFullTextIndex(colIDs[0])
+			} else {
+				index, err = table.newIndex(value[0]&uniqueIndexFlag != 0, colIDs)
+			}
 			if err != nil {
 				return err
 			}
 
+			index.nullsDistinct = value[0]&nullsDistinctIndexFlag != 0
+			index.canonicalKeys = value[0]&canonicalKeysIndexFlag != 0
+
+			if index.IsPrimary() {
+				table.countsRows = value[0]&rowCountIndexFlag != 0
+			}
+
 			if indexID != index.id {
 				return ErrCorruptedData
 			}
@@ -976,6 +1248,18 @@ func unmapTableID(prefix, mkey []byte) (dbID, tableID uint32, err error) {
 	return
 }
 
+func unmapViewName(prefix, mkey []byte) (string, error) {
+	enc, err := trimPrefix(prefix, mkey, []byte(catalogViewPrefix))
+	if err != nil {
+		return "", err
+	}
+
+	if len(enc) <= EncIDLen || binary.BigEndian.Uint32(enc) != DatabaseID {
+		return "", ErrCorruptedData
+	}
+	return string(enc[EncIDLen:]), nil
+}
+
 func unmapCheckID(prefix, mkey []byte) (uint32, error) {
 	encID, err := trimPrefix(prefix, mkey, []byte(catalogCheckPrefix))
 	if err != nil {
@@ -997,7 +1281,7 @@ func parseCheckConstraint(prefix, key, value []byte) (*CheckConstraint, error) {
 	nameLen := value[0] + 1
 	name := string(value[1 : 1+nameLen])
 
-	exp, err := ParseExpFromString(string(value[1+nameLen:]))
+	exp, err := parseStoredExp(string(value[1+nameLen:]))
 	if err != nil {
 		return nil, err
 	}
@@ -1040,7 +1324,8 @@ func asType(t string) (SQLValueType, error) {
 		UUIDType,
 		BLOBType,
 		TimestampType,
-		JSONType:
+		JSONType,
+		PointType:
 		return t, nil
 	}
 	return t, ErrCorruptedData
@@ -1068,27 +1353,15 @@ func unmapIndexEntry(index *Index, sqlPrefix, mkey []byte) (encPKVals []byte, er
 		return nil, ErrIllegalArguments
 	}
 
-	enc, err := trimPrefix(sqlPrefix, mkey, []byte(MappedPrefix))
-	if err != nil {
-		return nil, ErrCorruptedData
-	}
+	prefix := MapKey(sqlPrefix, string(index.table.keyEncoder().IndexPrefix(index.table.id, index.id)))
 
-	if len(enc) <= EncIDLen*2 {
+	if !bytes.HasPrefix(mkey, prefix) || len(mkey) == len(prefix) {
 		return nil, ErrCorruptedData
 	}
 
+	enc := mkey[len(prefix):]
 	off := 0
 
-	tableID := binary.BigEndian.Uint32(enc[off:])
-	off += EncIDLen
-
-	indexID := binary.BigEndian.Uint32(enc[off:])
-	off += EncIDLen
-
-	if tableID != index.table.id || indexID != index.id {
-		return nil, ErrCorruptedData
-	}
-
 	//read index values
 	for _, col := range index.cols {
 		if enc[off] == KeyValPrefixNull {
@@ -1160,12 +1433,35 @@ const (
 	KeyValPrefixUpperBound byte = 0xFF
 )
 
+// timestamps are encoded in keys as nanoseconds since the unix epoch, so only the ones
+// within this range, with microsecond precision, can be indexed
+var (
+	minKeyTimestamp = time.Unix(0, math.MinInt64).Truncate(time.Microsecond).Add(time.Microsecond).UTC()
+	maxKeyTimestamp = time.Unix(0, math.MaxInt64).Truncate(time.Microsecond).UTC()
+)
+
 func EncodeValueAsKey(val TypedValue, colType SQLValueType, maxLen int) ([]byte, int, error) {
 	return EncodeRawValueAsKey(val.RawValue(), colType, maxLen)
 }
 
-// EncodeRawValueAsKey encodes a value in a b-tree meaningful way.
+// EncodeRawValueAsKey encodes a value in a b-tree meaningful way, as the primary keys of rows
+// and the entries of the indexes created by previous releases are encoded.
 func EncodeRawValueAsKey(val interface{}, colType SQLValueType, maxLen int) ([]byte, int, error) {
+	return encodeRawValueAsKey(val, colType, maxLen, false)
+}
+
+// encodeValueAsKey encodes the value of a column of the index as the entries of the index do
+func (i *Index) encodeValueAsKey(val TypedValue, col *Column) ([]byte, int, error) {
+	return encodeRawValueAsKey(val.RawValue(), col.colType, col.MaxLen(), i.canonicalKeys)
+}
+
+// encodeRawValueAsKey encodes a value in a b-tree meaningful way. Canonical keys of the values
+// of a column are ordered as the values are by Compare, NULL being the smallest one, which range
+// scans over indexes rely on, and values which compare as equal share the same key. Otherwise,
+// keys are encoded as by previous releases: negative zero and negative NaNs have keys of their own,
+// the latter being smaller than any other number, and timestamps out of the range of indexed
+// values have keys which are not ordered as the timestamps are.
+func encodeRawValueAsKey(val interface{}, colType SQLValueType, maxLen int, canonical bool) ([]byte, int, error) {
 	if maxLen <= 0 {
 		return nil, 0, ErrInvalidValue
 	}
@@ -1286,6 +1582,10 @@ func EncodeRawValueAsKey(val interface{}, colType SQLValueType, maxLen int) ([]b
 				return nil, 0, fmt.Errorf("value is not a timestamp: %w", ErrInvalidValue)
 			}
 
+			if canonical && (timeVal.Before(minKeyTimestamp) || timeVal.After(maxKeyTimestamp)) {
+				return nil, 0, fmt.Errorf("%w: timestamp %s is out of the range of indexed values", ErrInvalidValue, timeVal.Format(time.RFC3339))
+			}
+
 			// v
 			var encv [9]byte
 			encv[0] = KeyValPrefixNotNull
@@ -1302,6 +1602,10 @@ func EncodeRawValueAsKey(val interface{}, colType SQLValueType, maxLen int) ([]b
 				return nil, 0, fmt.Errorf("value is not a float: %w", ErrInvalidValue)
 			}
 
+			if canonical {
+				floatVal = canonicalFloat(floatVal)
+			}
+
 			// Apart form the sign bit, bit representation of float64
 			// can be sorted lexicographically
 			floatBits := math.Float64bits(floatVal)
@@ -1326,11 +1630,49 @@ func EncodeRawValueAsKey(val interface{}, colType SQLValueType, maxLen int) ([]b
 
 			return encv[:], 8, nil
 		}
+	case PointType:
+		{
+			pointVal, ok := convVal.(GeoPoint)
+			if !ok {
+				return nil, 0, fmt.Errorf("value is not a point: %w", ErrInvalidValue)
+			}
+
+			encLat, _, err := encodeRawValueAsKey(pointVal.Lat, Float64Type, 8, canonical)
+			if err != nil {
+				return nil, 0, err
+			}
+
+			encLon, _, err := encodeRawValueAsKey(pointVal.Lon, Float64Type, 8, canonical)
+			if err != nil {
+				return nil, 0, err
+			}
+
+			// notnull + lat + lon
+			var encv [17]byte
+			encv[0] = KeyValPrefixNotNull
+			copy(encv[1:], encLat[1:])
+			copy(encv[9:], encLon[1:])
+
+			return encv[:], 16, nil
+		}
 	}
 
 	return nil, 0, ErrInvalidValue
 }
 
+// canonicalFloat returns the value encoded in keys for the given float, so that floats which
+// compare as equal share the same key: negative zero is encoded as zero, and any NaN as the
+// positive one, which is greater than any other number.
+func canonicalFloat(f float64) float64 {
+	if f == 0 {
+		return 0
+	}
+	if math.IsNaN(f) {
+		return math.NaN()
+	}
+	return f
+}
+
 func getEncodeRawValue(val TypedValue, colType SQLValueType) (interface{}, error) {
 	if colType != JSONType || val.Type() == JSONType {
 		return val.RawValue(), nil
@@ -1482,6 +1824,22 @@ func EncodeRawValue(val interface{}, colType SQLValueType, maxLen int, nullable
 			binary.BigEndian.PutUint32(encv[:], uint32(16))
 			copy(encv[EncLenLen:], uuidVal[:])
 
+			return encv[:], nil
+		}
+	case PointType:
+		{
+			pointVal, ok := convVal.(GeoPoint)
+			if !ok {
+				return nil, fmt.Errorf("value is not a point: %w", ErrInvalidValue)
+			}
+
+			encPoint := encodePoint(pointVal)
+
+			// len(v) + v
+			var encv [EncLenLen + 16]byte
+			binary.BigEndian.PutUint32(encv[:], uint32(16))
+			copy(encv[EncLenLen:], encPoint[:])
+
 			return encv[:], nil
 		}
 	case TimestampType:
@@ -1612,6 +1970,21 @@ func decodeValue(b []byte, colType SQLValueType, nullable bool) (TypedValue, int
 
 			return &UUID{val: u}, voff, nil
 		}
+	case PointType:
+		{
+			if vlen != 16 {
+				return nil, 0, ErrCorruptedData
+			}
+
+			p, err := decodePoint(b[voff : voff+16])
+			if err != nil {
+				return nil, 0, err
+			}
+
+			voff += vlen
+
+			return &Point{val: p}, voff, nil
+		}
 	case TimestampType:
 		{
 			if vlen != 8 {
@@ -17,11 +17,16 @@ limitations under the License.
 package sql
 
 import (
+	"bytes"
 	"context"
+	"math"
+	"math/rand"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/codenotary/immudb/embedded/store"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 )
 
@@ -148,6 +153,344 @@ func TestEncodeRawValueAsKey(t *testing.T) {
 	})
 }
 
+func randomKeyValue(rnd *rand.Rand, colType SQLValueType, maxLen int) TypedValue {
+	if rnd.Intn(20) == 0 {
+		return NewNull(colType)
+	}
+
+	randomFloat := func() float64 {
+		edges := []float64{
+			0, math.Copysign(0, -1), 1, -1, 0.5, -0.5, math.MaxFloat64, -math.MaxFloat64,
+			math.SmallestNonzeroFloat64, -math.SmallestNonzeroFloat64, math.Inf(1), math.Inf(-1),
+			math.NaN(), math.Float64frombits(0xfff8000000000001), 1 << 53, -(1 << 53),
+		}
+		if rnd.Intn(3) == 0 {
+			return edges[rnd.Intn(len(edges))]
+		}
+		if rnd.Intn(2) == 0 {
+			return math.Float64frombits(rnd.Uint64())
+		}
+		return (rnd.Float64() - 0.5) * math.Pow(10, float64(rnd.Intn(20)))
+	}
+
+	randomBytes := func() []byte {
+		b := make([]byte, rnd.Intn(maxLen+1))
+		for i := range b {
+			// small alphabets make common prefixes and trailing zeros likely
+			b[i] = []byte{0, 1, 'a', 'b', 0xff}[rnd.Intn(5)]
+		}
+		return b
+	}
+
+	switch colType {
+	case IntegerType:
+		edges := []int64{0, 1, -1, math.MaxInt64, math.MinInt64, math.MaxInt32, math.MinInt32}
+		if rnd.Intn(3) == 0 {
+			return NewInteger(edges[rnd.Intn(len(edges))])
+		}
+		return NewInteger(int64(rnd.Uint64()))
+	case Float64Type:
+		return NewFloat64(randomFloat())
+	case BooleanType:
+		return NewBool(rnd.Intn(2) == 0)
+	case VarcharType:
+		return NewVarchar(string(randomBytes()))
+	case BLOBType:
+		return NewBlob(randomBytes())
+	case UUIDType:
+		var u uuid.UUID
+		copy(u[:], bytes.Repeat(randomBytes(), 16))
+		u[rnd.Intn(16)] = byte(rnd.Intn(256))
+		return NewUUID(u)
+	case TimestampType:
+		// timestamps are kept with microsecond precision
+		usecs := rnd.Int63n(TimeToInt64(maxKeyTimestamp)-TimeToInt64(minKeyTimestamp)+1) + TimeToInt64(minKeyTimestamp)
+		return NewTimestamp(TimeFromInt64(usecs))
+	case PointType:
+		lat := []float64{-90, 90, 0, math.Copysign(0, -1), (rnd.Float64() - 0.5) * 180}[rnd.Intn(5)]
+		lon := []float64{-180, 180, 0, math.Copysign(0, -1), (rnd.Float64() - 0.5) * 360}[rnd.Intn(5)]
+		return &Point{val: GeoPoint{Lat: lat, Lon: lon}}
+	}
+	panic("unexpected type")
+}
+
+func TestKeyEncodingPreservesOrder(t *testing.T) {
+	rnd := rand.New(rand.NewSource(440))
+
+	for _, d := range []struct {
+		colType SQLValueType
+		maxLen  int
+	}{
+		{IntegerType, 8},
+		{Float64Type, 8},
+		{BooleanType, 1},
+		{VarcharType, 4},
+		{BLOBType, 4},
+		{UUIDType, 16},
+		{TimestampType, 8},
+		{PointType, 16},
+	} {
+		t.Run(d.colType, func(t *testing.T) {
+			for i := 0; i < 10_000; i++ {
+				v1 := randomKeyValue(rnd, d.colType, d.maxLen)
+				v2 := randomKeyValue(rnd, d.colType, d.maxLen)
+
+				enc1, _, err := encodeRawValueAsKey(v1.RawValue(), d.colType, d.maxLen, true)
+				require.NoError(t, err)
+
+				enc2, _, err := encodeRawValueAsKey(v2.RawValue(), d.colType, d.maxLen, true)
+				require.NoError(t, err)
+
+				cmp, err := v1.Compare(v2)
+				require.NoError(t, err)
+				require.Equal(t, cmp, bytes.Compare(enc1, enc2), "%s cmp %s", v1, v2)
+			}
+		})
+	}
+
+	// range scans use the key of the bound converted to the type of the column, and are
+	// inclusive, so the conversion only needs to preserve the order of the values
+	t.Run("bounds of another type", func(t *testing.T) {
+		for _, d := range []struct {
+			colType   SQLValueType
+			boundType SQLValueType
+		}{
+			{Float64Type, IntegerType},
+			{IntegerType, Float64Type},
+		} {
+			for i := 0; i < 10_000; i++ {
+				val := randomKeyValue(rnd, d.colType, 8)
+				bound := randomKeyValue(rnd, d.boundType, 8)
+
+				if val.IsNull() || bound.IsNull() {
+					continue
+				}
+
+				encBound, _, err := encodeRawValueAsKey(bound.RawValue(), d.colType, 8, true)
+				if err != nil {
+					// the bound can not be a value of the column
+					require.ErrorIs(t, err, ErrInvalidValue)
+					continue
+				}
+
+				encVal, _, err := encodeRawValueAsKey(val.RawValue(), d.colType, 8, true)
+				require.NoError(t, err)
+
+				cmp, err := val.Compare(bound)
+				require.NoError(t, err)
+
+				if cmp < 0 {
+					require.LessOrEqual(t, bytes.Compare(encVal, encBound), 0, "%s cmp %s", val, bound)
+				}
+				if cmp > 0 {
+					require.GreaterOrEqual(t, bytes.Compare(encVal, encBound), 0, "%s cmp %s", val, bound)
+				}
+			}
+		}
+	})
+}
+
+func TestIndexRangesOfEdgeValues(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, `
+		CREATE TABLE items (id INTEGER AUTO_INCREMENT, f FLOAT, n INTEGER, ts TIMESTAMP, PRIMARY KEY id);
+		CREATE INDEX ON items (f);
+		CREATE INDEX ON items (n);
+		CREATE INDEX ON items (ts);
+	`, nil)
+	require.NoError(t, err)
+
+	for _, row := range []struct {
+		f  float64
+		n  int64
+		ts time.Time
+	}{
+		{math.Copysign(0, -1), math.MinInt64, maxKeyTimestamp},
+		{0, -1, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{math.NaN(), 0, minKeyTimestamp},
+		{math.Float64frombits(0xfff8000000000001), 2, time.Unix(0, 0)},
+		{1 << 53, math.MaxInt64, time.Date(2025, 1, 1, 0, 0, 0, 1000, time.UTC)},
+		{1<<53 + 2, 1, time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC).Add(-time.Microsecond)},
+		{math.Inf(-1), 3, time.Date(2200, 1, 1, 0, 0, 0, 0, time.UTC)},
+	} {
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO items (f, n, ts) VALUES (@f, @n, @ts)",
+			map[string]interface{}{"f": row.f, "n": row.n, "ts": row.ts})
+		require.NoError(t, err)
+	}
+
+	_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO items (ts) VALUES (@ts)",
+		map[string]interface{}{"ts": maxKeyTimestamp.Add(time.Microsecond)})
+	require.ErrorIs(t, err, ErrInvalidValue)
+
+	ids := func(t *testing.T, sql string, params map[string]interface{}) []int64 {
+		rows, err := engine.queryAll(context.Background(), nil, sql, params)
+		require.NoError(t, err)
+
+		ids := make([]int64, len(rows))
+		for i, row := range rows {
+			ids[i] = row.ValuesByPosition[0].RawValue().(int64)
+		}
+		return ids
+	}
+
+	for _, d := range []struct {
+		index  string
+		where  string
+		params map[string]interface{}
+		empty  bool
+	}{
+		{"f", "f = 0", nil, false},
+		{"f", "f = @f", map[string]interface{}{"f": math.Copysign(0, -1)}, false},
+		{"f", "f <= 0", nil, false},
+		{"f", "f = @f", map[string]interface{}{"f": math.NaN()}, true},
+		{"f", "f >= @f", map[string]interface{}{"f": math.Inf(-1)}, false},
+		{"f", "f > 9007199254740993", nil, false},
+		{"f", "f >= 9007199254740993", nil, false},
+		{"f", "f < 9007199254740993", nil, false},
+		{"n", "n < @f", map[string]interface{}{"f": 1e30}, false},
+		{"n", "n > @f", map[string]interface{}{"f": -1e30}, false},
+		{"n", "n > 0.5 AND n < 2.5", nil, false},
+		{"n", "n <= -0.5", nil, false},
+		{"n", "n < @f", map[string]interface{}{"f": math.NaN()}, true},
+		{"n", "n < @f", map[string]interface{}{"f": math.Inf(1)}, false},
+		{"ts", "ts < CAST('3000-01-01' AS TIMESTAMP)", nil, false},
+		{"ts", "ts > CAST('1000-01-01' AS TIMESTAMP)", nil, false},
+		{"ts", "ts >= CAST('1970-01-01' AS TIMESTAMP)", nil, false},
+	} {
+		t.Run(d.where, func(t *testing.T) {
+			expected := ids(t, "SELECT id FROM items USE INDEX ON (id) WHERE "+d.where+" ORDER BY id", d.params)
+			// comparisons with NaN are false
+			require.Equal(t, d.empty, len(expected) == 0)
+
+			actual := ids(t, "SELECT id FROM items USE INDEX ON ("+d.index+") WHERE "+d.where, d.params)
+			require.ElementsMatch(t, expected, actual)
+		})
+	}
+}
+
+// withLegacyKeys rewrites the catalog entry of the index as previous releases, which encoded
+// the keys of every index as EncodeValueAsKey does, wrote it
+func withLegacyKeys(t *testing.T, engine *Engine, tableName string, colName string) {
+	tx, err := engine.NewTx(context.Background(), DefaultTxOptions())
+	require.NoError(t, err)
+
+	table, err := tx.catalog.GetTableByName(tableName)
+	require.NoError(t, err)
+
+	col, err := table.GetColumnByName(colName)
+	require.NoError(t, err)
+
+	index, err := table.GetIndexByName(indexName(table.name, []*Column{col}))
+	require.NoError(t, err)
+	require.True(t, index.canonicalKeys)
+
+	key := MapKey(tx.sqlPrefix(), catalogIndexPrefix, EncodeID(DatabaseID), EncodeID(table.id), EncodeID(index.id))
+
+	valRef, err := tx.get(context.Background(), key)
+	require.NoError(t, err)
+
+	val, err := valRef.Resolve()
+	require.NoError(t, err)
+
+	val = bytes.Clone(val)
+	val[0] &^= canonicalKeysIndexFlag
+
+	require.NoError(t, tx.set(key, nil, val))
+	require.NoError(t, tx.Commit(context.Background()))
+
+	// entries are mapped as when the store is reopened with the catalog written by previous releases
+	catalog, err := engine.Catalog(context.Background(), nil)
+	require.NoError(t, err)
+
+	table, err = catalog.GetTableByName(tableName)
+	require.NoError(t, err)
+
+	index, err = table.GetIndexByName(index.Name())
+	require.NoError(t, err)
+	require.False(t, index.canonicalKeys)
+
+	require.NoError(t, engine.store.RebuildIndex(indexSpecFor(index)))
+}
+
+func TestLegacyIndexKeys(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, `
+		CREATE TABLE items (id INTEGER AUTO_INCREMENT, f FLOAT, ts TIMESTAMP, PRIMARY KEY id);
+		CREATE INDEX ON items (f);
+		CREATE INDEX ON items (ts);
+	`, nil)
+	require.NoError(t, err)
+
+	catalog, err := engine.Catalog(context.Background(), nil)
+	require.NoError(t, err)
+
+	table, err := catalog.GetTableByName("items")
+	require.NoError(t, err)
+
+	// primary keys keep the encoding of previous releases
+	require.False(t, table.primaryIndex.canonicalKeys)
+	require.True(t, table.indexes[1].canonicalKeys)
+	require.True(t, table.indexes[2].canonicalKeys)
+
+	withLegacyKeys(t, engine, "items", "f")
+	withLegacyKeys(t, engine, "items", "ts")
+
+	negZero := math.Copysign(0, -1)
+	negNaN := math.Float64frombits(0xfff8000000000001)
+	outOfRange := maxKeyTimestamp.Add(time.Microsecond)
+
+	for _, row := range []struct {
+		f  float64
+		ts time.Time
+	}{
+		{negZero, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{0, outOfRange},
+		{negNaN, time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{1, time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)},
+	} {
+		// timestamps out of the range of indexed values are still accepted
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO items (f, ts) VALUES (@f, @ts)",
+			map[string]interface{}{"f": row.f, "ts": row.ts})
+		require.NoError(t, err)
+	}
+
+	ids := func(t *testing.T, sql string, params map[string]interface{}) []int64 {
+		rows, err := engine.queryAll(context.Background(), nil, sql, params)
+		require.NoError(t, err)
+
+		ids := make([]int64, len(rows))
+		for i, row := range rows {
+			ids[i] = row.ValuesByPosition[0].RawValue().(int64)
+		}
+		return ids
+	}
+
+	t.Run("entries are found by the values they were written with", func(t *testing.T) {
+		require.Equal(t, []int64{1}, ids(t, "SELECT id FROM items USE INDEX ON (f) WHERE f = @f", map[string]interface{}{"f": negZero}))
+		require.Equal(t, []int64{2}, ids(t, "SELECT id FROM items USE INDEX ON (f) WHERE f = 0", nil))
+		require.Equal(t, []int64{2}, ids(t, "SELECT id FROM items USE INDEX ON (ts) WHERE ts = @ts", map[string]interface{}{"ts": outOfRange}))
+
+		// negative NaNs are smaller than any other number, and negative zero smaller than zero
+		require.Equal(t, []int64{3, 1, 2, 4}, ids(t, "SELECT id FROM items USE INDEX ON (f) ORDER BY f", nil))
+	})
+
+	t.Run("updated and deleted rows leave no entries behind", func(t *testing.T) {
+		_, _, err = engine.Exec(context.Background(), nil, "UPDATE items SET f = 2.0 WHERE id = 1", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "DELETE FROM items WHERE id = 3", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "DELETE FROM items WHERE id = 2", nil)
+		require.NoError(t, err)
+
+		require.Equal(t, []int64{4, 1}, ids(t, "SELECT id FROM items USE INDEX ON (f) ORDER BY f", nil))
+		require.Equal(t, []int64{1, 4}, ids(t, "SELECT id FROM items USE INDEX ON (ts) ORDER BY ts", nil))
+	})
+}
+
 func TestCatalogTableLength(t *testing.T) {
 	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
 	require.NoError(t, err)
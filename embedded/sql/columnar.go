@@ -0,0 +1,204 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ColumnarBatch holds a batch of rows as a vector of values per column
+type ColumnarBatch struct {
+	Columns []ColDescriptor
+	Vectors []*ColumnVector
+	// Len is the number of rows of the batch
+	Len int
+}
+
+// ColumnVector holds the values of a column in the slice matching its type:
+//
+//	INTEGER   -> Ints
+//	FLOAT     -> Floats
+//	BOOLEAN   -> Bools
+//	VARCHAR   -> Strings
+//	BLOB      -> Blobs
+//	TIMESTAMP -> Timestamps
+//	UUID      -> UUIDs
+//	POINT     -> Points
+//	JSON, ANY -> Values
+//
+// NULL values are flagged in Nulls, while the slice holds the zero value of the type at their position.
+type ColumnVector struct {
+	Type  SQLValueType
+	Nulls []bool
+
+	Ints       []int64
+	Floats     []float64
+	Bools      []bool
+	Strings    []string
+	Blobs      [][]byte
+	Timestamps []time.Time
+	UUIDs      []uuid.UUID
+	Points     []GeoPoint
+	Values     []TypedValue
+}
+
+// ReadColumnar reads up to maxRows rows of the reader into a columnar batch,
+// returning ErrNoMoreRows once every row was read. The reader is not closed.
+func ReadColumnar(ctx context.Context, reader RowReader, maxRows int) (*ColumnarBatch, error) {
+	if reader == nil || maxRows < 1 {
+		return nil, ErrIllegalArguments
+	}
+
+	cols, err := reader.Columns(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := &ColumnarBatch{
+		Columns: cols,
+		Vectors: make([]*ColumnVector, len(cols)),
+	}
+
+	for i, col := range cols {
+		batch.Vectors[i] = &ColumnVector{Type: col.Type}
+	}
+
+	for batch.Len < maxRows {
+		row, err := reader.Read(ctx)
+		if errors.Is(err, ErrNoMoreRows) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(row.ValuesByPosition) != len(cols) {
+			return nil, fmt.Errorf("%w: rows do not match the columns of the reader", ErrUnexpected)
+		}
+
+		for i, v := range row.ValuesByPosition {
+			err := batch.Vectors[i].append(v)
+			if err != nil {
+				return nil, fmt.Errorf("%w in column '%s'", err, cols[i].Column)
+			}
+		}
+
+		batch.Len++
+	}
+
+	if batch.Len == 0 {
+		return nil, ErrNoMoreRows
+	}
+	return batch, nil
+}
+
+func (vec *ColumnVector) append(v TypedValue) error {
+	isNull := v.IsNull()
+
+	if !isNull && vec.Type != AnyType && v.Type() != vec.Type && !(v.Type() == IntegerType && vec.Type == Float64Type) {
+		return fmt.Errorf("%w: value of type %s can not be held by a vector of type %s", ErrInvalidTypes, v.Type(), vec.Type)
+	}
+
+	vec.Nulls = append(vec.Nulls, isNull)
+
+	switch vec.Type {
+	case IntegerType:
+		var val int64
+		if !isNull {
+			val = v.RawValue().(int64)
+		}
+		vec.Ints = append(vec.Ints, val)
+	case Float64Type:
+		var val float64
+		if !isNull {
+			switch raw := v.RawValue().(type) {
+			case int64:
+				val = float64(raw)
+			case float64:
+				val = raw
+			}
+		}
+		vec.Floats = append(vec.Floats, val)
+	case BooleanType:
+		vec.Bools = append(vec.Bools, !isNull && v.RawValue().(bool))
+	case VarcharType:
+		var val string
+		if !isNull {
+			val = v.RawValue().(string)
+		}
+		vec.Strings = append(vec.Strings, val)
+	case BLOBType:
+		var val []byte
+		if !isNull {
+			val = v.RawValue().([]byte)
+		}
+		vec.Blobs = append(vec.Blobs, val)
+	case TimestampType:
+		var val time.Time
+		if !isNull {
+			val = v.RawValue().(time.Time)
+		}
+		vec.Timestamps = append(vec.Timestamps, val)
+	case UUIDType:
+		var val uuid.UUID
+		if !isNull {
+			val = v.RawValue().(uuid.UUID)
+		}
+		vec.UUIDs = append(vec.UUIDs, val)
+	case PointType:
+		var val GeoPoint
+		if !isNull {
+			val = v.RawValue().(GeoPoint)
+		}
+		vec.Points = append(vec.Points, val)
+	default:
+		vec.Values = append(vec.Values, v)
+	}
+	return nil
+}
+
+// Value returns the value of the vector at the given row
+func (vec *ColumnVector) Value(i int) TypedValue {
+	if vec.Nulls[i] {
+		return &NullValue{t: vec.Type}
+	}
+
+	switch vec.Type {
+	case IntegerType:
+		return &Integer{val: vec.Ints[i]}
+	case Float64Type:
+		return &Float64{val: vec.Floats[i]}
+	case BooleanType:
+		return &Bool{val: vec.Bools[i]}
+	case VarcharType:
+		return &Varchar{val: vec.Strings[i]}
+	case BLOBType:
+		return &Blob{val: vec.Blobs[i]}
+	case TimestampType:
+		return &Timestamp{val: vec.Timestamps[i]}
+	case UUIDType:
+		return &UUID{val: vec.UUIDs[i]}
+	case PointType:
+		return &Point{val: vec.Points[i]}
+	}
+	return vec.Values[i]
+}
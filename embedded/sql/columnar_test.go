@@ -0,0 +1,233 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+)
+
+func readColumnarTest(t testing.TB, reader RowReader, maxRows int) []*ColumnarBatch {
+	var batches []*ColumnarBatch
+
+	for {
+		batch, err := ReadColumnar(context.Background(), reader, maxRows)
+		if errors.Is(err, ErrNoMoreRows) {
+			return batches
+		}
+		require.NoError(t, err)
+		require.LessOrEqual(t, batch.Len, maxRows)
+
+		batches = append(batches, batch)
+	}
+}
+
+func TestReadColumnar(t *testing.T) {
+	engine := setupBinaryRowsTest(t)
+
+	t.Run("values match the ones of rows", func(t *testing.T) {
+		q := "SELECT id, n, f, b, s, bl, ts, u, j, p FROM values_table"
+
+		expected, err := engine.queryAll(context.Background(), nil, q, nil)
+		require.NoError(t, err)
+		require.Len(t, expected, 3)
+
+		for _, maxRows := range []int{1, 2, 3, 100} {
+			t.Run(fmt.Sprintf("batches of %d rows", maxRows), func(t *testing.T) {
+				r, err := engine.Query(context.Background(), nil, q, nil)
+				require.NoError(t, err)
+				defer r.Close()
+
+				cols, err := r.Columns(context.Background())
+				require.NoError(t, err)
+
+				batches := readColumnarTest(t, r, maxRows)
+				require.Len(t, batches, (len(expected)+maxRows-1)/maxRows)
+
+				i := 0
+				for _, batch := range batches {
+					require.Equal(t, cols, batch.Columns)
+					require.Len(t, batch.Vectors, len(cols))
+
+					for j := 0; j < batch.Len; j, i = j+1, i+1 {
+						for c, vec := range batch.Vectors {
+							require.Equal(t, cols[c].Type, vec.Type)
+
+							ev := expected[i].ValuesByPosition[c]
+							v := vec.Value(j)
+
+							require.Equal(t, ev.IsNull(), v.IsNull(), cols[c].Column)
+							require.Equal(t, ev.Type(), v.Type(), cols[c].Column)
+							require.Equal(t, ev.RawValue(), v.RawValue(), cols[c].Column)
+						}
+					}
+				}
+				require.Equal(t, len(expected), i)
+			})
+		}
+	})
+
+	t.Run("values are held by the slice of the type of the column", func(t *testing.T) {
+		r, err := engine.Query(context.Background(), nil, "SELECT n, s, bl FROM values_table", nil)
+		require.NoError(t, err)
+		defer r.Close()
+
+		batch, err := ReadColumnar(context.Background(), r, 10)
+		require.NoError(t, err)
+		require.Equal(t, 3, batch.Len)
+
+		require.Equal(t, []int64{-12345678901, 0, 0}, batch.Vectors[0].Ints)
+		require.Equal(t, []bool{false, false, true}, batch.Vectors[0].Nulls)
+		require.Equal(t, []string{"immudb", "", ""}, batch.Vectors[1].Strings)
+		require.Equal(t, [][]byte{{0x00, 0xff, 0x10}, {}, nil}, batch.Vectors[2].Blobs)
+
+		for _, vec := range batch.Vectors {
+			require.Nil(t, vec.Floats)
+			require.Nil(t, vec.Values)
+		}
+
+		_, err = ReadColumnar(context.Background(), r, 10)
+		require.ErrorIs(t, err, ErrNoMoreRows)
+	})
+
+	t.Run("columns of type ANY", func(t *testing.T) {
+		reader := &columnsRowReader{
+			cols: []ColDescriptor{{Column: "x", Type: AnyType}},
+			mockRowReader: mockRowReader{rows: []*Row{
+				{ValuesByPosition: []TypedValue{NewInteger(1)}},
+				{ValuesByPosition: []TypedValue{NewVarchar("a")}},
+				{ValuesByPosition: []TypedValue{NewNull(AnyType)}},
+			}},
+		}
+
+		batch, err := ReadColumnar(context.Background(), reader, 10)
+		require.NoError(t, err)
+		require.Equal(t, 3, batch.Len)
+		require.Equal(t, []bool{false, false, true}, batch.Vectors[0].Nulls)
+		require.Equal(t, int64(1), batch.Vectors[0].Value(0).RawValue())
+		require.Equal(t, "a", batch.Vectors[0].Value(1).RawValue())
+		require.True(t, batch.Vectors[0].Value(2).IsNull())
+	})
+
+	t.Run("values not matching the column type", func(t *testing.T) {
+		reader := &columnsRowReader{
+			cols:          []ColDescriptor{{Column: "x", Type: IntegerType}},
+			mockRowReader: mockRowReader{rows: []*Row{{ValuesByPosition: []TypedValue{NewVarchar("a")}}}},
+		}
+
+		_, err := ReadColumnar(context.Background(), reader, 10)
+		require.ErrorIs(t, err, ErrInvalidTypes)
+		require.ErrorContains(t, err, "column 'x'")
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		_, err := ReadColumnar(context.Background(), nil, 10)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = ReadColumnar(context.Background(), &mockRowReader{}, 0)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+	})
+}
+
+func BenchmarkReadColumnar(b *testing.B) {
+	st, err := store.Open(b.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(b, err)
+	defer st.Close()
+
+	engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+	require.NoError(b, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE t (id INTEGER AUTO_INCREMENT, n INTEGER, f FLOAT, PRIMARY KEY id)", nil)
+	require.NoError(b, err)
+
+	const rows = 100_000
+
+	for i := 0; i < rows; i += 1000 {
+		tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithExplicitClose(true))
+		require.NoError(b, err)
+
+		for j := i; j < i+1000; j++ {
+			_, _, err = engine.Exec(context.Background(), tx, "INSERT INTO t (n, f) VALUES (@n, @f)", map[string]interface{}{"n": j, "f": float64(j) / 2})
+			require.NoError(b, err)
+		}
+
+		require.NoError(b, tx.Commit(context.Background()))
+	}
+
+	q := "SELECT n, f FROM t"
+
+	b.Run("rows", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			r, err := engine.Query(context.Background(), nil, q, nil)
+			require.NoError(b, err)
+
+			var n int64
+			var f float64
+			read := 0
+
+			for {
+				row, err := r.Read(context.Background())
+				if errors.Is(err, ErrNoMoreRows) {
+					break
+				}
+				require.NoError(b, err)
+
+				n += row.ValuesByPosition[0].RawValue().(int64)
+				f += row.ValuesByPosition[1].RawValue().(float64)
+				read++
+			}
+			require.Equal(b, rows, read)
+
+			r.Close()
+		}
+	})
+
+	b.Run("columnar", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			r, err := engine.Query(context.Background(), nil, q, nil)
+			require.NoError(b, err)
+
+			var n int64
+			var f float64
+			read := 0
+
+			for {
+				batch, err := ReadColumnar(context.Background(), r, 1024)
+				if errors.Is(err, ErrNoMoreRows) {
+					break
+				}
+				require.NoError(b, err)
+
+				for _, v := range batch.Vectors[0].Ints {
+					n += v
+				}
+				for _, v := range batch.Vectors[1].Floats {
+					f += v
+				}
+				read += batch.Len
+			}
+			require.Equal(b, rows, read)
+
+			r.Close()
+		}
+	})
+}
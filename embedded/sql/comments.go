@@ -0,0 +1,174 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/codenotary/immudb/embedded/store"
+)
+
+// tableCommentColID is the column id under which the comment of the table itself is kept,
+// column ids start from 1
+const tableCommentColID = 0
+
+// CommentStmt sets the comment of a table, or of one of its columns when col is specified.
+// Setting an empty comment or NULL removes it.
+type CommentStmt struct {
+	table   string
+	col     string
+	comment string
+}
+
+func NewCommentStmt(table, col, comment string) *CommentStmt {
+	return &CommentStmt{table: table, col: col, comment: comment}
+}
+
+func (stmt *CommentStmt) readOnly() bool {
+	return false
+}
+
+func (stmt *CommentStmt) requiredPrivileges() []SQLPrivilege {
+	return []SQLPrivilege{SQLPrivilegeAlter}
+}
+
+func (stmt *CommentStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
+	return nil
+}
+
+func (stmt *CommentStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
+	table, err := tx.catalog.GetTableByName(stmt.table)
+	if err != nil {
+		return nil, err
+	}
+
+	colID := uint32(tableCommentColID)
+	prevComment := table.comment
+
+	var col *Column
+	if stmt.col != "" {
+		col, err = table.GetColumnByName(stmt.col)
+		if err != nil {
+			return nil, err
+		}
+		colID = col.id
+		prevComment = col.comment
+	}
+
+	if stmt.comment == "" && prevComment == "" {
+		return tx, nil
+	}
+
+	if stmt.comment == "" {
+		err = persistCommentDeletion(ctx, tx, table.id, colID)
+	} else {
+		err = tx.set(commentKey(tx.sqlPrefix(), table.id, colID), nil, []byte(stmt.comment))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if col != nil {
+		col.comment = stmt.comment
+	} else {
+		table.comment = stmt.comment
+	}
+
+	tx.mutatedCatalog = true
+
+	return tx, nil
+}
+
+func commentKey(sqlPrefix []byte, tableID, colID uint32) []byte {
+	return MapKey(
+		sqlPrefix,
+		catalogCommentPrefix,
+		EncodeID(DatabaseID),
+		EncodeID(tableID),
+		EncodeID(colID),
+	)
+}
+
+func persistCommentDeletion(ctx context.Context, tx *SQLTx, tableID, colID uint32) error {
+	return tx.delete(ctx, commentKey(tx.sqlPrefix(), tableID, colID))
+}
+
+// deleteComments removes the comments of the table and of its columns
+func (t *Table) deleteComments(ctx context.Context, tx *SQLTx) error {
+	if t.comment != "" {
+		err := persistCommentDeletion(ctx, tx, t.id, tableCommentColID)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, col := range t.cols {
+		if col.comment == "" {
+			continue
+		}
+
+		err := persistCommentDeletion(ctx, tx, t.id, col.id)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *Table) loadComments(ctx context.Context, sqlPrefix []byte, tx *store.OngoingTx, copyToTx bool) error {
+	prefix := MapKey(sqlPrefix, catalogCommentPrefix, EncodeID(DatabaseID), EncodeID(t.id))
+
+	return iteratePrefix(ctx, tx, prefix, func(key, value []byte, deleted bool) error {
+		if deleted {
+			return nil
+		}
+
+		colID, err := unmapCommentColID(sqlPrefix, key)
+		if err != nil {
+			return err
+		}
+
+		if colID == tableCommentColID {
+			t.comment = string(value)
+		} else {
+			col, err := t.GetColumnByID(colID)
+			if err != nil {
+				return fmt.Errorf("%w: comment of unknown column %d", ErrCorruptedData, colID)
+			}
+			col.comment = string(value)
+		}
+
+		if copyToTx {
+			return tx.Set(key, nil, value)
+		}
+		return nil
+	})
+}
+
+func unmapCommentColID(prefix, mkey []byte) (uint32, error) {
+	encID, err := trimPrefix(prefix, mkey, []byte(catalogCommentPrefix))
+	if err != nil {
+		return 0, err
+	}
+
+	if len(encID) != 3*EncIDLen {
+		return 0, ErrCorruptedData
+	}
+	return binary.BigEndian.Uint32(encID[2*EncIDLen:]), nil
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseComments(t *testing.T) {
+	for _, d := range []struct {
+		sql      string
+		expected *CommentStmt
+	}{
+		{"COMMENT ON TABLE people IS 'people we know'", &CommentStmt{table: "people", comment: "people we know"}},
+		{"COMMENT ON COLUMN people.name IS 'full name'", &CommentStmt{table: "people", col: "name", comment: "full name"}},
+		{"COMMENT ON TABLE people IS NULL", &CommentStmt{table: "people"}},
+		{"COMMENT ON COLUMN people.comment IS ''", &CommentStmt{table: "people", col: "comment"}},
+	} {
+		t.Run(d.sql, func(t *testing.T) {
+			stmts, err := ParseSQLString(d.sql)
+			require.NoError(t, err)
+			require.Equal(t, []SQLStmt{d.expected}, stmts)
+		})
+	}
+
+	_, err := ParseSQLString("COMMENT ON INDEX people IS 'people'")
+	require.ErrorContains(t, err, "syntax error")
+}
+
+func TestComments(t *testing.T) {
+	dir := t.TempDir()
+
+	st, err := store.Open(dir, store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(t, err)
+
+	engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		CREATE TABLE people (id INTEGER, name VARCHAR, nickname VARCHAR, PRIMARY KEY id);
+		COMMENT ON TABLE people IS 'people we know';
+		COMMENT ON COLUMN people.name IS 'full name';
+		COMMENT ON COLUMN people.nickname IS 'how friends call them';
+	`, nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE VIEW named_people AS SELECT name FROM people", nil)
+	require.NoError(t, err)
+
+	tableComments := func(t *testing.T, e *Engine) map[string]interface{} {
+		rows, err := e.queryAll(context.Background(), nil, "SELECT table_name, comment FROM information_schema.tables", nil)
+		require.NoError(t, err)
+
+		comments := make(map[string]interface{})
+		for _, row := range rows {
+			comments[row.ValuesByPosition[0].RawValue().(string)] = row.ValuesByPosition[1].RawValue()
+		}
+		return comments
+	}
+
+	columnComments := func(t *testing.T, e *Engine, table string) map[string]interface{} {
+		rows, err := e.queryAll(context.Background(), nil, "SELECT column_name, comment FROM information_schema.columns WHERE table_name = @table", map[string]interface{}{"table": table})
+		require.NoError(t, err)
+
+		comments := make(map[string]interface{})
+		for _, row := range rows {
+			comments[row.ValuesByPosition[0].RawValue().(string)] = row.ValuesByPosition[1].RawValue()
+		}
+		return comments
+	}
+
+	require.Equal(t, map[string]interface{}{"people": "people we know", "named_people": nil}, tableComments(t, engine))
+	require.Equal(t, map[string]interface{}{"id": nil, "name": "full name", "nickname": "how friends call them"}, columnComments(t, engine, "people"))
+
+	catalog, err := engine.Catalog(context.Background(), nil)
+	require.NoError(t, err)
+
+	table, err := catalog.GetTableByName("people")
+	require.NoError(t, err)
+	require.Equal(t, "people we know", table.Comment())
+
+	t.Run("overwriting comments", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, `
+			COMMENT ON TABLE people IS 'people we met';
+			COMMENT ON COLUMN people.name IS NULL;
+			COMMENT ON COLUMN people.id IS '';
+		`, nil)
+		require.NoError(t, err)
+
+		require.Equal(t, "people we met", tableComments(t, engine)["people"])
+		require.Equal(t, map[string]interface{}{"id": nil, "name": nil, "nickname": "how friends call them"}, columnComments(t, engine, "people"))
+	})
+
+	t.Run("comments of unknown tables or columns", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "COMMENT ON TABLE friends IS 'friends'", nil)
+		require.ErrorIs(t, err, ErrTableDoesNotExist)
+
+		_, _, err = engine.Exec(context.Background(), nil, "COMMENT ON COLUMN people.age IS 'age'", nil)
+		require.ErrorIs(t, err, ErrColumnDoesNotExist)
+	})
+
+	t.Run("comments are kept on renaming", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "ALTER TABLE people RENAME COLUMN nickname TO alias", nil)
+		require.NoError(t, err)
+
+		require.Equal(t, "how friends call them", columnComments(t, engine, "people")["alias"])
+	})
+
+	require.NoError(t, st.Close())
+
+	st, err = store.Open(dir, store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(t, err)
+	defer closeStore(t, st)
+
+	engine, err = NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+	require.NoError(t, err)
+
+	t.Run("comments are persisted", func(t *testing.T) {
+		require.Equal(t, map[string]interface{}{"people": "people we met", "named_people": nil}, tableComments(t, engine))
+		require.Equal(t, map[string]interface{}{"id": nil, "name": nil, "alias": "how friends call them"}, columnComments(t, engine, "people"))
+	})
+
+	t.Run("dropping a column removes its comment", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, `
+			ALTER TABLE people DROP COLUMN alias;
+			ALTER TABLE people ADD COLUMN alias VARCHAR;
+		`, nil)
+		require.NoError(t, err)
+
+		require.Equal(t, map[string]interface{}{"id": nil, "name": nil, "alias": nil}, columnComments(t, engine, "people"))
+
+		_, err = st.Get(context.Background(), commentKey(sqlPrefix, table.id, 3))
+		require.ErrorIs(t, err, store.ErrKeyNotFound)
+	})
+
+	t.Run("dropping a table removes its comments", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, `
+			COMMENT ON COLUMN people.alias IS 'alias';
+			DROP VIEW named_people;
+			DROP TABLE people;
+		`, nil)
+		require.NoError(t, err)
+
+		for _, colID := range []uint32{tableCommentColID, 4} {
+			_, err = st.Get(context.Background(), commentKey(sqlPrefix, table.id, colID))
+			require.ErrorIs(t, err, store.ErrKeyNotFound)
+		}
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE people (id INTEGER, name VARCHAR, PRIMARY KEY id)", nil)
+		require.NoError(t, err)
+
+		require.Equal(t, map[string]interface{}{"people": nil}, tableComments(t, engine))
+		require.Equal(t, map[string]interface{}{"id": nil, "name": nil}, columnComments(t, engine, "people"))
+	})
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// commitCoalescer groups INSERT statements executed outside of a transaction,
+// so statements targeting the same table are committed in a single transaction
+type commitCoalescer struct {
+	engine *Engine
+
+	maxDelay time.Duration
+	maxBatch int
+
+	mutex   sync.Mutex
+	pending map[string]*commitBatch // batch being filled by table name
+}
+
+type commitBatch struct {
+	reqs []*coalescedReq
+	full chan struct{} // closed once maxBatch statements were received
+}
+
+type coalescedReq struct {
+	ctx    context.Context
+	stmt   *UpsertIntoStmt
+	params map[string]interface{}
+
+	nparams map[string]interface{}
+
+	committedTx *SQLTx
+	err         error
+	done        chan struct{}
+}
+
+func newCommitCoalescer(e *Engine, maxDelay time.Duration, maxBatch int) *commitCoalescer {
+	return &commitCoalescer{
+		engine:   e,
+		maxDelay: maxDelay,
+		maxBatch: maxBatch,
+		pending:  make(map[string]*commitBatch),
+	}
+}
+
+// coalescableStmt returns the statement when it consists of a single INSERT statement
+func coalescableStmt(stmts []SQLStmt) (*UpsertIntoStmt, bool) {
+	if len(stmts) != 1 {
+		return nil, false
+	}
+
+	stmt, ok := stmts[0].(*UpsertIntoStmt)
+	if !ok || !stmt.isInsert {
+		return nil, false
+	}
+
+	return stmt, true
+}
+
+func (c *commitCoalescer) exec(ctx context.Context, stmt *UpsertIntoStmt, params map[string]interface{}) (ntx *SQLTx, committedTxs []*SQLTx, err error) {
+	nparams, err := normalizeParams(params)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// permissions are checked with the context of the caller
+	if c.engine.multidbHandler != nil {
+		err := c.engine.checkUserPermissions(ctx, stmt)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	req := &coalescedReq{
+		ctx:     ctx,
+		stmt:    stmt,
+		params:  params,
+		nparams: nparams,
+		done:    make(chan struct{}),
+	}
+
+	batch, isLeader := c.enqueue(stmt.tableRef.table, req)
+
+	if isLeader {
+		timer := time.NewTimer(c.maxDelay)
+
+		select {
+		case <-batch.full:
+		case <-timer.C:
+		}
+
+		timer.Stop()
+
+		c.execBatch(ctx, c.dequeue(stmt.tableRef.table, batch))
+	}
+
+	<-req.done
+
+	if req.err != nil {
+		return nil, nil, req.err
+	}
+
+	return nil, []*SQLTx{req.committedTx}, nil
+}
+
+// enqueue adds the request to the pending batch of the table,
+// the caller starting a new batch is the one in charge of executing it
+func (c *commitCoalescer) enqueue(table string, req *coalescedReq) (batch *commitBatch, isLeader bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	batch, ok := c.pending[table]
+	if !ok {
+		batch = &commitBatch{full: make(chan struct{})}
+		c.pending[table] = batch
+	}
+
+	batch.reqs = append(batch.reqs, req)
+
+	if len(batch.reqs) == c.maxBatch {
+		// following requests are added to a new batch
+		delete(c.pending, table)
+		close(batch.full)
+	}
+
+	return batch, !ok
+}
+
+func (c *commitCoalescer) dequeue(table string, batch *commitBatch) []*coalescedReq {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if c.pending[table] == batch {
+		delete(c.pending, table)
+	}
+
+	return batch.reqs
+}
+
+func (c *commitCoalescer) execBatch(ctx context.Context, reqs []*coalescedReq) {
+	// the cancellation of the caller in charge of the batch does not affect the rest of the requests
+	ctx = context.WithoutCancel(ctx)
+
+	if len(reqs) > 1 && c.tryExecBatch(ctx, reqs) {
+		return
+	}
+
+	// statements are executed in separate transactions so each error is reported to its caller
+	for _, req := range reqs {
+		if req.err == nil {
+			var committedTxs []*SQLTx
+
+			_, committedTxs, _, req.err = c.engine.execPreparedStmts(req.ctx, nil, []SQLStmt{req.stmt}, req.params)
+			if req.err == nil && len(committedTxs) > 0 {
+				req.committedTx = committedTxs[0]
+			}
+		}
+
+		close(req.done)
+	}
+}
+
+// tryExecBatch executes all the statements in a single transaction,
+// it returns false without completing any request when any statement fails
+func (c *commitCoalescer) tryExecBatch(ctx context.Context, reqs []*coalescedReq) bool {
+	tx, err := c.engine.NewTx(ctx, DefaultTxOptions())
+	if err != nil {
+		return false
+	}
+
+	results := make([]*SQLTx, len(reqs))
+
+	for i, req := range reqs {
+		err := req.ctx.Err()
+		if err != nil {
+			// the caller is no longer waiting for the statement to be executed
+			req.err = err
+			tx.Cancel()
+			return false
+		}
+
+		// inserted PKs are tracked per statement
+		tx.lastInsertedPKs = make(map[string]int64)
+		tx.firstInsertedPKs = make(map[string]int64)
+
+		updatedRows := tx.updatedRows
+
+		_, err = req.stmt.execAt(ctx, tx, req.nparams)
+		if err != nil {
+			tx.Cancel()
+			return false
+		}
+
+		results[i] = &SQLTx{
+			engine:           c.engine,
+			opts:             tx.opts,
+			tx:               tx.tx,
+			catalog:          tx.catalog,
+			timestamp:        tx.timestamp,
+			updatedRows:      tx.updatedRows - updatedRows,
+			lastInsertedPKs:  tx.lastInsertedPKs,
+			firstInsertedPKs: tx.firstInsertedPKs,
+		}
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return false
+	}
+
+	for i, req := range reqs {
+		results[i].txHeader = tx.txHeader
+		req.committedTx = results[i]
+
+		close(req.done)
+	}
+
+	return true
+}
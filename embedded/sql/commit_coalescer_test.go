@@ -0,0 +1,205 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+)
+
+func setupCoalescingTest(t testing.TB, maxDelay time.Duration, maxBatch int) *Engine {
+	// concurrent callers are waiting for the batch to be committed
+	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true).WithMaxConcurrency(1024))
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+
+	engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix).WithCommitCoalescing(maxDelay, maxBatch))
+	require.NoError(t, err)
+
+	return engine
+}
+
+func TestCommitCoalescingOptions(t *testing.T) {
+	require.ErrorIs(t, DefaultOptions().WithCommitCoalescing(-time.Second, 10).Validate(), store.ErrInvalidOptions)
+	require.ErrorIs(t, DefaultOptions().WithCommitCoalescing(time.Second, -1).Validate(), store.ErrInvalidOptions)
+	require.NoError(t, DefaultOptions().WithCommitCoalescing(0, 0).Validate())
+}
+
+func TestCommitCoalescing(t *testing.T) {
+	const callers = 16
+
+	engine := setupCoalescingTest(t, time.Second, callers)
+
+	_, _, err := engine.Exec(context.Background(), nil, "CREATE TABLE entries (id INTEGER AUTO_INCREMENT, name VARCHAR, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE items (id INTEGER, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	t.Run("concurrent inserts should be committed together", func(t *testing.T) {
+		var wg sync.WaitGroup
+		wg.Add(callers)
+
+		txs := make([][]*SQLTx, callers)
+		errs := make([]error, callers)
+
+		for i := 0; i < callers; i++ {
+			go func(i int) {
+				defer wg.Done()
+
+				_, txs[i], errs[i] = engine.Exec(
+					context.Background(),
+					nil,
+					"INSERT INTO entries (name) VALUES (@name)",
+					map[string]interface{}{"name": fmt.Sprintf("name%d", i)},
+				)
+			}(i)
+		}
+
+		wg.Wait()
+
+		pks := make(map[int64]bool)
+
+		for i := 0; i < callers; i++ {
+			require.NoError(t, errs[i])
+			require.Len(t, txs[i], 1)
+			require.Equal(t, 1, txs[i][0].UpdatedRows())
+			require.Equal(t, txs[0][0].TxHeader().ID, txs[i][0].TxHeader().ID)
+
+			pk := txs[i][0].LastInsertedPKs()["entries"]
+			require.Equal(t, pk, txs[i][0].FirstInsertedPKs()["entries"])
+			require.False(t, pks[pk])
+			pks[pk] = true
+		}
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT COUNT(*) FROM entries", nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(callers), rows[0].ValuesByPosition[0].RawValue())
+	})
+
+	t.Run("each caller should get its own error", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "INSERT INTO items (id) VALUES (0)", nil)
+		require.NoError(t, err)
+
+		var wg sync.WaitGroup
+		wg.Add(callers)
+
+		txs := make([][]*SQLTx, callers)
+		errs := make([]error, callers)
+
+		for i := 0; i < callers; i++ {
+			go func(i int) {
+				defer wg.Done()
+
+				// every fourth caller inserts an existing row
+				id := i + 1
+				if i%4 == 0 {
+					id = 0
+				}
+
+				_, txs[i], errs[i] = engine.Exec(context.Background(), nil, "INSERT INTO items (id) VALUES (@id)", map[string]interface{}{"id": id})
+			}(i)
+		}
+
+		wg.Wait()
+
+		for i := 0; i < callers; i++ {
+			if i%4 == 0 {
+				require.ErrorIs(t, errs[i], store.ErrKeyAlreadyExists)
+				require.Empty(t, txs[i])
+				continue
+			}
+
+			require.NoError(t, errs[i])
+			require.Len(t, txs[i], 1)
+			require.Equal(t, 1, txs[i][0].UpdatedRows())
+		}
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT id FROM items ORDER BY id", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, callers-callers/4+1)
+
+		for _, row := range rows {
+			id := row.ValuesByPosition[0].RawValue().(int64)
+			require.True(t, id == 0 || (id-1)%4 != 0)
+		}
+	})
+
+	t.Run("a single insert should be committed once the delay elapses", func(t *testing.T) {
+		engine := setupCoalescingTest(t, 10*time.Millisecond, callers)
+
+		_, _, err := engine.Exec(context.Background(), nil, "CREATE TABLE items (id INTEGER, PRIMARY KEY id)", nil)
+		require.NoError(t, err)
+
+		_, txs, err := engine.Exec(context.Background(), nil, "INSERT INTO items (id) VALUES (1)", nil)
+		require.NoError(t, err)
+		require.Len(t, txs, 1)
+		require.NotNil(t, txs[0].TxHeader())
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO items (id) VALUES (1)", nil)
+		require.ErrorIs(t, err, store.ErrKeyAlreadyExists)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO missing (id) VALUES (1)", nil)
+		require.ErrorIs(t, err, ErrTableDoesNotExist)
+	})
+}
+
+func BenchmarkCommitCoalescing(b *testing.B) {
+	const workers = 32
+
+	bench := func(b *testing.B, engine *Engine) {
+		_, _, err := engine.Exec(context.Background(), nil, "CREATE TABLE bench (id INTEGER, name VARCHAR[32], PRIMARY KEY id)", nil)
+		require.NoError(b, err)
+
+		var id atomic.Int64
+
+		b.SetParallelism(workers / runtime.GOMAXPROCS(0))
+		b.ResetTimer()
+
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				n := id.Add(1)
+
+				_, _, err := engine.Exec(
+					context.Background(),
+					nil,
+					"INSERT INTO bench (id, name) VALUES (@id, @name)",
+					map[string]interface{}{"id": n, "name": fmt.Sprintf("name%d", n)},
+				)
+				if err != nil {
+					b.Error(err)
+				}
+			}
+		})
+	}
+
+	b.Run("disabled", func(b *testing.B) {
+		bench(b, setupCoalescingTest(b, 0, 0))
+	})
+
+	b.Run("coalescing", func(b *testing.B) {
+		bench(b, setupCoalescingTest(b, time.Millisecond, 64))
+	})
+}
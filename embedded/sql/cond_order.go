@@ -0,0 +1,196 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import "sort"
+
+const subqueryCost = 1000
+
+// estimatedCost approximates the relative cost of evaluating exp for a single row
+func estimatedCost(exp ValueExp) int {
+	switch e := exp.(type) {
+	case TypedValue, *Param:
+		return 0
+	case *ColSelector:
+		return 1
+	case *CmpBoolExp:
+		return 1 + estimatedCost(e.left) + estimatedCost(e.right)
+	case *BinBoolExp:
+		return 1 + estimatedCost(e.left) + estimatedCost(e.right)
+	case *DistinctBoolExp:
+		return 1 + estimatedCost(e.left) + estimatedCost(e.right)
+	case *NotBoolExp:
+		return 1 + estimatedCost(e.exp)
+	case *NumExp:
+		return 2 + estimatedCost(e.left) + estimatedCost(e.right)
+	case *Cast:
+		return 2 + estimatedCost(e.val)
+	case *InListExp:
+		cost := 1 + estimatedCost(e.val)
+		for _, v := range e.values {
+			cost += 1 + estimatedCost(v)
+		}
+		return cost
+	case *LikeBoolExp:
+		return 20 + estimatedCost(e.val) + estimatedCost(e.pattern)
+	case *FnCall:
+		cost := 10
+		for _, p := range e.params {
+			cost += estimatedCost(p)
+		}
+		return cost
+	case *CaseWhenExp:
+		cost := 2
+		if e.exp != nil {
+			cost += estimatedCost(e.exp)
+		}
+		for _, wt := range e.whenThen {
+			cost += estimatedCost(wt.when) + estimatedCost(wt.then)
+		}
+		if e.elseExp != nil {
+			cost += estimatedCost(e.elseExp)
+		}
+		return cost
+	case *ExistsBoolExp, *InSubQueryExp:
+		return subqueryCost
+	}
+	return 10
+}
+
+// selectivityRank orders comparisons by how likely they are to be satisfied,
+// from the least likely, equality, to the most likely, inequality
+func selectivityRank(exp ValueExp) int {
+	cmp, isCmp := exp.(*CmpBoolExp)
+	if !isCmp {
+		return 1
+	}
+
+	switch cmp.op {
+	case EQ:
+		return 0
+	case NE:
+		return 2
+	}
+	return 1
+}
+
+// orderConditions reorders the operands of AND and OR expressions so those which are cheaper
+// and more likely to decide the outcome are evaluated first, letting the evaluation of the most
+// expensive ones to be short-circuited. Only operands whose evaluation can not fail are moved,
+// the remaining ones keep their relative order, so no error is raised when evaluating rows
+// which wasn't raised when evaluating the condition as written.
+func orderConditions(exp ValueExp, cols map[string]ColDescriptor, implicitTable string) ValueExp {
+	switch e := exp.(type) {
+	case *NotBoolExp:
+		return &NotBoolExp{exp: orderConditions(e.exp, cols, implicitTable)}
+	case *BinBoolExp:
+		operands := flattenBinBoolExp(e.op, e, nil)
+
+		var safe, others []ValueExp
+
+		for _, op := range operands {
+			op = orderConditions(op, cols, implicitTable)
+
+			if neverFails(op, cols, implicitTable) {
+				safe = append(safe, op)
+			} else {
+				others = append(others, op)
+			}
+		}
+
+		// AND is decided by unsatisfied operands and OR by satisfied ones
+		rank := func(op ValueExp) int {
+			if e.op == Or {
+				return -selectivityRank(op)
+			}
+			return selectivityRank(op)
+		}
+
+		sort.SliceStable(safe, func(i, j int) bool {
+			ci, cj := estimatedCost(safe[i]), estimatedCost(safe[j])
+			if ci != cj {
+				return ci < cj
+			}
+			return rank(safe[i]) < rank(safe[j])
+		})
+
+		ordered := append(safe, others...)
+
+		res := ordered[0]
+		for _, op := range ordered[1:] {
+			res = &BinBoolExp{op: e.op, left: res, right: op}
+		}
+		return res
+	}
+	return exp
+}
+
+func flattenBinBoolExp(op LogicOperator, exp ValueExp, operands []ValueExp) []ValueExp {
+	bexp, isBin := exp.(*BinBoolExp)
+	if !isBin || bexp.op != op {
+		return append(operands, exp)
+	}
+
+	operands = flattenBinBoolExp(op, bexp.left, operands)
+	return flattenBinBoolExp(op, bexp.right, operands)
+}
+
+// neverFails returns true when exp evaluates to a boolean value for every row,
+// currently comparisons between columns and values of comparable types
+func neverFails(exp ValueExp, cols map[string]ColDescriptor, implicitTable string) bool {
+	switch e := exp.(type) {
+	case *Bool:
+		return true
+	case *NotBoolExp:
+		return neverFails(e.exp, cols, implicitTable)
+	case *BinBoolExp:
+		return neverFails(e.left, cols, implicitTable) && neverFails(e.right, cols, implicitTable)
+	case *CmpBoolExp:
+		lt, ok := comparableOperandType(e.left, cols, implicitTable)
+		if !ok {
+			return false
+		}
+
+		rt, ok := comparableOperandType(e.right, cols, implicitTable)
+		if !ok {
+			return false
+		}
+
+		return lt == rt || lt == AnyType || rt == AnyType || (IsNumericType(lt) && IsNumericType(rt))
+	}
+	return false
+}
+
+func comparableOperandType(exp ValueExp, cols map[string]ColDescriptor, implicitTable string) (SQLValueType, bool) {
+	switch e := exp.(type) {
+	case *NullValue:
+		return AnyType, true
+	case TypedValue:
+	case *ColSelector:
+		if e.col == revCol {
+			return AnyType, false
+		}
+	default:
+		return AnyType, false
+	}
+
+	t, err := exp.inferType(cols, nil, implicitTable)
+	if err != nil || t == JSONType || t == AnyType {
+		return AnyType, false
+	}
+	return t, true
+}
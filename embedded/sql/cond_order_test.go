@@ -0,0 +1,153 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderConditions(t *testing.T) {
+	cols := map[string]ColDescriptor{
+		EncodeSelector("", "t", "val"):  {Column: "val", Type: IntegerType},
+		EncodeSelector("", "t", "name"): {Column: "name", Type: VarcharType},
+		EncodeSelector("", "t", "doc"):  {Column: "doc", Type: JSONType},
+	}
+
+	for _, d := range []struct {
+		exp     string
+		ordered string
+	}{
+		{"LENGTH(name) > 3 AND val = 1", "((val = 1) AND (length(name) > 3))"},
+		{"val > 1 AND name = 'a' AND val <> 3", "(((name = 'a') AND (val > 1)) AND (val != 3))"},
+		{"val > 1 OR name = 'a' OR val <> 3", "(((val != 3) OR (val > 1)) OR (name = 'a'))"},
+		{"name LIKE 'a%' AND val > 1", "((val > 1) AND (name LIKE 'a%'))"},
+		{"(LENGTH(name) > 3 OR val = 2) AND val = 1", "((val = 1) AND ((val = 2) OR (length(name) > 3)))"},
+		{"NOT (LENGTH(name) > 3 AND val = 1)", "(NOT ((val = 1) AND (length(name) > 3)))"},
+		{"val IS NULL AND LENGTH(name) > 3", "((val = NULL) AND (length(name) > 3))"},
+		// operands which may fail are evaluated last, keeping their relative order
+		{"val / 0 > 1 AND val = 1", "((val = 1) AND ((val / 0) > 1))"},
+		{"name = 1 AND LENGTH(name) > 3 AND val > 1", "(((val > 1) AND (name = 1)) AND (length(name) > 3))"},
+		{"doc = 'a' AND val = 1", "((val = 1) AND (doc = 'a'))"},
+		{"unknown = 1 AND val = 1", "((val = 1) AND (unknown = 1))"},
+		{"val > 1 AND val < 10", "((val > 1) AND (val < 10))"},
+	} {
+		t.Run(d.exp, func(t *testing.T) {
+			exp, err := ParseExpFromString(d.exp)
+			require.NoError(t, err)
+
+			require.Equal(t, d.ordered, orderConditions(exp, cols, "t").String())
+		})
+	}
+}
+
+func TestEstimatedCost(t *testing.T) {
+	for _, d := range []struct {
+		cheaper   string
+		expensive string
+	}{
+		{"val = 1", "val + 1 = 2"},
+		{"val IN (1, 2)", "val IN (1, 2, 3)"},
+		{"val + 1 = 2", "name LIKE 'a%'"},
+		{"val > 1", "LENGTH(name) > 1"},
+		{"LENGTH(name) > 1", "EXISTS (SELECT id FROM t)"},
+	} {
+		cheaper, err := ParseExpFromString(d.cheaper)
+		require.NoError(t, err)
+
+		expensive, err := ParseExpFromString(d.expensive)
+		require.NoError(t, err)
+
+		require.Less(t, estimatedCost(cheaper), estimatedCost(expensive), d.expensive)
+	}
+}
+
+func TestConditionEvaluationOrder(t *testing.T) {
+	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(t, err)
+	defer closeStore(t, st)
+
+	engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+	require.NoError(t, err)
+
+	var calls int
+
+	err = engine.RegisterFunction("expensive", ScalarFunc{
+		ArgTypes:   []SQLValueType{IntegerType},
+		ReturnType: BooleanType,
+		Eval: func(args []TypedValue) (TypedValue, error) {
+			calls++
+			return NewBool(args[0].RawValue().(int64)%2 == 0), nil
+		},
+	})
+	require.NoError(t, err)
+
+	errFailing := errors.New("failing")
+
+	err = engine.RegisterFunction("failing", ScalarFunc{
+		ArgTypes:   []SQLValueType{IntegerType},
+		ReturnType: BooleanType,
+		Eval: func(args []TypedValue) (TypedValue, error) {
+			return nil, errFailing
+		},
+	})
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE t (id INTEGER AUTO_INCREMENT, n INTEGER, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	for i := 0; i < 100; i++ {
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO t (n) VALUES (@n)", map[string]interface{}{"n": i})
+		require.NoError(t, err)
+	}
+
+	query := func(t *testing.T, sql string) int {
+		calls = 0
+
+		rows, err := engine.queryAll(context.Background(), nil, sql, nil)
+		require.NoError(t, err)
+		return len(rows)
+	}
+
+	t.Run("expensive conjuncts are short-circuited by cheaper ones", func(t *testing.T) {
+		require.Equal(t, 2, query(t, "SELECT id FROM t WHERE expensive(n) AND n < 3"))
+		require.Equal(t, 3, calls)
+
+		require.Equal(t, 1, query(t, "SELECT id FROM t WHERE expensive(n) AND n > 10 AND n = 50"))
+		require.Equal(t, 1, calls)
+	})
+
+	t.Run("expensive disjuncts are short-circuited by cheaper ones", func(t *testing.T) {
+		require.Equal(t, 97, query(t, "SELECT id FROM t WHERE expensive(n) OR n > 5"))
+		require.Equal(t, 6, calls)
+	})
+
+	t.Run("conditions which may fail keep their order", func(t *testing.T) {
+		require.Zero(t, query(t, "SELECT id FROM t WHERE expensive(n) AND failing(n) AND n = 1"))
+		require.Equal(t, 1, calls)
+
+		_, err := engine.queryAll(context.Background(), nil, "SELECT id FROM t WHERE failing(n) AND expensive(n) AND n = 1", nil)
+		require.ErrorIs(t, err, errFailing)
+
+		require.Equal(t, 2, query(t, "SELECT id FROM t WHERE n IN (0, 2) AND (expensive(n) OR failing(n))"))
+		require.Equal(t, 2, calls)
+	})
+}
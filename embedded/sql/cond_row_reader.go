@@ -18,9 +18,15 @@ package sql
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"iter"
 )
 
+// conditionalRowReader filters the rows of the underlying reader. The condition is evaluated
+// synchronously by the goroutine reading the rows, thus filtering is deterministic and
+// doesn't start any additional goroutine. Conditions reduced to a constant, once parameters
+// are substituted, are not evaluated for each row.
 type conditionalRowReader struct {
 	rowReader RowReader
 
@@ -29,6 +35,8 @@ type conditionalRowReader struct {
 	// Cached substituted condition (parameters don't change per row)
 	cachedCond ValueExp
 	condCached bool
+
+	lookahead rowLookahead
 }
 
 func newConditionalRowReader(rowReader RowReader, condition ValueExp) *conditionalRowReader {
@@ -86,25 +94,64 @@ func (cr *conditionalRowReader) InferParameters(ctx context.Context, params map[
 	return err
 }
 
+func (cr *conditionalRowReader) All(ctx context.Context) iter.Seq2[*Row, error] {
+	return allRows(ctx, cr)
+}
+
 func (cr *conditionalRowReader) Read(ctx context.Context) (*Row, error) {
+	return cr.lookahead.read(ctx, cr.readRow)
+}
+
+func (cr *conditionalRowReader) Peek(ctx context.Context) (*Row, error) {
+	return cr.lookahead.peek(ctx, cr.readRow)
+}
+
+func (cr *conditionalRowReader) readRow(ctx context.Context) (*Row, error) {
 	// Cache condition substitution (parameters don't change per row)
 	if !cr.condCached {
 		cond, err := cr.condition.substitute(cr.Parameters())
 		if err != nil {
 			return nil, fmt.Errorf("%w: when evaluating WHERE clause", err)
 		}
-		cr.cachedCond = cond
+		cond = fold(cr.Tx(), cond)
+
+		cols, err := cr.colsBySelector(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		cr.cachedCond = orderConditions(cond, cols, cr.rowReader.TableAlias())
 		cr.condCached = true
 	}
 
+	// no need to scan rows when the condition can not be satisfied
+	if isAlwaysFalse(cr.cachedCond) {
+		return nil, ErrNoMoreRows
+	}
+
+	// nor to evaluate it for each row when it is satisfied by all of them
+	if isAlwaysTrue(cr.cachedCond) {
+		return cr.rowReader.Read(ctx)
+	}
+
 	for {
+		// rows may be discarded for a long time before one satisfies the condition
+		err := ctx.Err()
+		if err != nil {
+			return nil, err
+		}
+
 		row, err := cr.rowReader.Read(ctx)
 		if err != nil {
 			return nil, err
 		}
 
 		// Use cached condition instead of re-substituting
-		r, err := cr.cachedCond.reduce(cr.Tx(), row, cr.rowReader.TableAlias())
+		r, err := cr.cachedCond.reduceCtx(ctx, cr.Tx(), row, cr.rowReader.TableAlias())
+		if err != nil && cr.skipsErroredRows() && isRowError(err) {
+			cr.Tx().stats.ErrorsSkipped++
+			continue
+		}
 		if err != nil {
 			return nil, fmt.Errorf("%w: when evaluating WHERE clause", err)
 		}
@@ -125,6 +172,27 @@ func (cr *conditionalRowReader) Read(ctx context.Context) (*Row, error) {
 	}
 }
 
+func (cr *conditionalRowReader) skipsErroredRows() bool {
+	tx := cr.Tx()
+	return tx != nil && tx.engine != nil && tx.engine.skipErroredRows
+}
+
+// isRowError tells whether the error is caused by the values of a particular row, e.g. a division
+// by zero, rather than by the statement itself or by the state of the engine or of the store
+func isRowError(err error) bool {
+	if errors.Is(err, ErrResourceLimitExceeded) {
+		return false
+	}
+
+	switch ErrorCodeOf(err) {
+	case ErrCodeType, ErrCodeInvalid:
+		return true
+	}
+	return false
+}
+
 func (cr *conditionalRowReader) Close() error {
+	cr.lookahead.discard()
+
 	return cr.rowReader.Close()
 }
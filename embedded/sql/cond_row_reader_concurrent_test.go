@@ -2,6 +2,8 @@ package sql
 
 import (
 	"context"
+	"errors"
+	"iter"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -14,6 +16,10 @@ type mockRowReader struct {
 	tableAlias string
 }
 
+func (m *mockRowReader) All(ctx context.Context) iter.Seq2[*Row, error] {
+	return allRows(ctx, m)
+}
+
 func (m *mockRowReader) Read(ctx context.Context) (*Row, error) {
 	if m.curr >= len(m.rows) {
 		return nil, ErrNoMoreRows
@@ -23,6 +29,13 @@ func (m *mockRowReader) Read(ctx context.Context) (*Row, error) {
 	return row, nil
 }
 
+func (m *mockRowReader) Peek(ctx context.Context) (*Row, error) {
+	if m.curr >= len(m.rows) {
+		return nil, ErrNoMoreRows
+	}
+	return m.rows[m.curr], nil
+}
+
 func (m *mockRowReader) Close() error { return nil }
 func (m *mockRowReader) Tx() *SQLTx   { return nil }
 func (m *mockRowReader) TableAlias() string {
@@ -58,6 +71,10 @@ func (m *mockValueExp) substitute(params map[string]interface{}) (ValueExp, erro
 }
 func (m *mockValueExp) selectors() []Selector { return nil }
 func (m *mockValueExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return m.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (m *mockValueExp) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
 	pass := m.shouldPass(row)
 	return &Bool{val: pass}, nil
 }
@@ -162,3 +179,63 @@ func TestConditionalRowReader_PreservesOrder(t *testing.T) {
 		require.Equal(t, int64(i), val, "Order mismatch at index %d - expected %d, got %d", i, i, val)
 	}
 }
+
+// failingRowReader returns the given error once failAfter rows were read
+type failingRowReader struct {
+	mockRowReader
+	failAfter int
+	err       error
+}
+
+func (m *failingRowReader) Read(ctx context.Context) (*Row, error) {
+	if m.curr >= m.failAfter {
+		return nil, m.err
+	}
+	return m.mockRowReader.Read(ctx)
+}
+
+func TestConditionalRowReader_PropagatesErrors(t *testing.T) {
+	errInjected := errors.New("injected error")
+
+	rows := make([]*Row, 100)
+	for i := range rows {
+		rows[i] = &Row{ValuesByPosition: []TypedValue{&Integer{val: int64(i)}}}
+	}
+
+	condition := &mockValueExp{
+		shouldPass: func(row *Row) bool {
+			return row.ValuesByPosition[0].(*Integer).val%2 == 0
+		},
+	}
+
+	for _, failAfter := range []int{0, 1, 50, 99} {
+		reader := newConditionalRowReader(&failingRowReader{
+			mockRowReader: mockRowReader{rows: rows, tableAlias: "t1"},
+			failAfter:     failAfter,
+			err:           errInjected,
+		}, condition)
+
+		n := 0
+		for {
+			row, err := reader.Read(context.Background())
+			if err != nil {
+				// the error is returned rather than the end of the rows
+				require.ErrorIs(t, err, errInjected)
+				require.NotErrorIs(t, err, ErrNoMoreRows)
+				break
+			}
+			require.Equal(t, int64(n*2), row.ValuesByPosition[0].(*Integer).val)
+			n++
+		}
+		require.Equal(t, (failAfter+1)/2, n)
+
+		// peeking does not mask the error either
+		_, err := reader.Peek(context.Background())
+		require.ErrorIs(t, err, errInjected)
+
+		_, err = reader.Read(context.Background())
+		require.ErrorIs(t, err, errInjected)
+
+		require.NoError(t, reader.Close())
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"testing"
 
+	"github.com/codenotary/immudb/embedded/store"
 	"github.com/stretchr/testify/require"
 )
 
@@ -39,3 +40,168 @@ func TestConditionalRowReader(t *testing.T) {
 	err = rowReader.InferParameters(context.Background(), nil)
 	require.ErrorIs(t, err, errDummy)
 }
+
+func TestSkipErroredRows(t *testing.T) {
+	setup := func(t *testing.T, opts *Options) *Engine {
+		st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+		require.NoError(t, err)
+		t.Cleanup(func() { closeStore(t, st) })
+
+		engine, err := NewEngine(st, opts.WithPrefix(sqlPrefix))
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, `
+			CREATE TABLE items (id INTEGER, qty INTEGER, code VARCHAR, PRIMARY KEY id);
+			INSERT INTO items (id, qty, code) VALUES (1, 4, '10'), (2, 0, '20'), (3, 2, 'x'), (4, 0, '40'), (5, 1, '50');
+		`, nil)
+		require.NoError(t, err)
+
+		return engine
+	}
+
+	query := func(t *testing.T, engine *Engine, sql string) ([]int64, int64, error) {
+		reader, err := engine.Query(context.Background(), nil, sql, nil)
+		require.NoError(t, err)
+		defer reader.Close()
+
+		rows, err := ReadAllRows(context.Background(), reader)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		ids := make([]int64, len(rows))
+		for i, row := range rows {
+			ids[i] = row.ValuesByPosition[0].RawValue().(int64)
+		}
+		return ids, reader.Tx().Stats().ErrorsSkipped, nil
+	}
+
+	t.Run("errors are propagated by default", func(t *testing.T) {
+		engine := setup(t, DefaultOptions())
+
+		_, _, err := query(t, engine, "SELECT id FROM items WHERE 8 / qty > 2")
+		require.ErrorIs(t, err, ErrDivisionByZero)
+
+		_, _, err = query(t, engine, "SELECT id FROM items WHERE CAST(code AS INTEGER) > 10")
+		require.ErrorIs(t, err, ErrInvalidValue)
+	})
+
+	t.Run("errored rows are skipped", func(t *testing.T) {
+		engine := setup(t, DefaultOptions().WithSkipErroredRows(true))
+
+		ids, skipped, err := query(t, engine, "SELECT id FROM items WHERE 8 / qty >= 2")
+		require.NoError(t, err)
+		require.Equal(t, []int64{1, 3, 5}, ids)
+		require.Equal(t, int64(2), skipped)
+
+		ids, skipped, err = query(t, engine, "SELECT id FROM items WHERE CAST(code AS INTEGER) > 10")
+		require.NoError(t, err)
+		require.Equal(t, []int64{2, 4, 5}, ids)
+		require.Equal(t, int64(1), skipped)
+
+		ids, skipped, err = query(t, engine, "SELECT id FROM items WHERE qty > 0")
+		require.NoError(t, err)
+		require.Equal(t, []int64{1, 3, 5}, ids)
+		require.Zero(t, skipped)
+
+		// skipped rows are not updated
+		tx, _, err := engine.Exec(context.Background(), nil, "BEGIN; UPDATE items SET qty = qty + 1 WHERE 8 / qty > 3;", nil)
+		require.NoError(t, err)
+		require.Equal(t, 2, tx.UpdatedRows())
+		require.Equal(t, int64(2), tx.Stats().ErrorsSkipped)
+		require.NoError(t, tx.Cancel())
+	})
+
+	t.Run("other errors are propagated", func(t *testing.T) {
+		engine := setup(t, DefaultOptions().WithSkipErroredRows(true))
+
+		_, err := engine.queryAll(context.Background(), nil, "SELECT id FROM items WHERE unknown > 0", nil)
+		require.ErrorIs(t, err, ErrColumnDoesNotExist)
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		reader, err := engine.Query(ctx, nil, "SELECT id FROM items WHERE 8 / qty > 2", nil)
+		require.NoError(t, err)
+		defer reader.Close()
+
+		cancel()
+
+		_, err = reader.Read(ctx)
+		require.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestConstantConditions(t *testing.T) {
+	rows := make([]*Row, 10)
+	for i := range rows {
+		rows[i] = &Row{ValuesByPosition: []TypedValue{&Integer{val: int64(i)}}}
+	}
+
+	t.Run("rows are passed through when the condition is always satisfied", func(t *testing.T) {
+		for _, cond := range []ValueExp{
+			&Bool{val: true},
+			&CmpBoolExp{op: EQ, left: &Integer{val: 1}, right: &Integer{val: 1}},
+			&BinBoolExp{op: Or, left: &Bool{val: true}, right: &ColSelector{col: "unknown"}},
+		} {
+			reader := newConditionalRowReader(&mockRowReader{rows: rows, tableAlias: "t1"}, cond)
+
+			row, err := reader.Peek(context.Background())
+			require.NoError(t, err)
+			require.Same(t, rows[0], row)
+
+			for _, expected := range rows {
+				row, err := reader.Read(context.Background())
+				require.NoError(t, err)
+				require.Same(t, expected, row)
+			}
+
+			_, err = reader.Read(context.Background())
+			require.ErrorIs(t, err, ErrNoMoreRows)
+		}
+	})
+
+	t.Run("no rows are read when the condition is never satisfied", func(t *testing.T) {
+		for _, cond := range []ValueExp{
+			&Bool{val: false},
+			&NullValue{t: BooleanType},
+			&CmpBoolExp{op: EQ, left: &Integer{val: 1}, right: &Integer{val: 2}},
+		} {
+			underlying := &mockRowReader{rows: rows, tableAlias: "t1"}
+
+			_, err := newConditionalRowReader(underlying, cond).Read(context.Background())
+			require.ErrorIs(t, err, ErrNoMoreRows)
+			require.Zero(t, underlying.curr)
+		}
+	})
+
+	t.Run("conditions made constant by their parameters", func(t *testing.T) {
+		engine := setupCommonTest(t)
+
+		_, _, err := engine.Exec(context.Background(), nil, `
+			CREATE TABLE items (id INTEGER AUTO_INCREMENT, qty INTEGER, PRIMARY KEY id);
+			INSERT INTO items (qty) VALUES (1), (0), (NULL);
+		`, nil)
+		require.NoError(t, err)
+
+		all, err := engine.queryAll(context.Background(), nil, "SELECT id, qty FROM items", nil)
+		require.NoError(t, err)
+		require.Len(t, all, 3)
+
+		for _, d := range []struct {
+			sql    string
+			params map[string]interface{}
+			rows   []*Row
+		}{
+			{"SELECT id, qty FROM items WHERE TRUE", nil, all},
+			{"SELECT id, qty FROM items WHERE 1 = 1", nil, all},
+			{"SELECT id, qty FROM items WHERE @flag", map[string]interface{}{"flag": true}, all},
+			{"SELECT id, qty FROM items WHERE @flag OR 8 / qty > 2", map[string]interface{}{"flag": true}, all},
+			{"SELECT id, qty FROM items WHERE @flag", map[string]interface{}{"flag": false}, nil},
+			{"SELECT id, qty FROM items WHERE @n > 0", map[string]interface{}{"n": 0}, nil},
+		} {
+			rows, err := engine.queryAll(context.Background(), nil, d.sql, d.params)
+			require.NoError(t, err, d.sql)
+			require.Equal(t, d.rows, rows, d.sql)
+		}
+	})
+}
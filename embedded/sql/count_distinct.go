@@ -0,0 +1,364 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"bufio"
+	"bytes"
+	"container/heap"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	defaultCountDistinctMemoryBudget = 16 << 20 // 16MB
+
+	// distinctKeyOverhead approximates the memory required to hold a key in the set besides the key itself
+	distinctKeyOverhead = 48
+)
+
+// newCountDistinctSelector returns the selector of COUNT(DISTINCT ...) over the given columns.
+// Columns other than the first one are included in the name of the aggregation,
+// so each combination of columns gets its own selector e.g. COUNT[DISTINCT,b](t.a)
+func newCountDistinctSelector(aggFn AggregateFn, cols []*ColSelector) (*AggColSelector, error) {
+	if aggFn != COUNT {
+		return nil, fmt.Errorf("%w: DISTINCT can only be used with %s", ErrIllegalArguments, COUNT)
+	}
+
+	var fn strings.Builder
+
+	fn.WriteString(COUNT + "[DISTINCT")
+	for _, col := range cols[1:] {
+		fn.WriteString(",")
+		if col.table != "" {
+			fn.WriteString(col.table + ".")
+		}
+		fn.WriteString(col.col)
+	}
+	fn.WriteString("]")
+
+	return &AggColSelector{
+		aggFn:    fn.String(),
+		table:    cols[0].table,
+		col:      cols[0].col,
+		distinct: cols,
+	}, nil
+}
+
+// CountDistinctValue counts the distinct combinations of non-null values of a set of columns.
+// Rows holding a NULL value in any of the columns are not counted.
+// Its value is only available once the aggregation has been finalized.
+type CountDistinctValue struct {
+	CountValue
+
+	sels []string
+	set  *distinctSet
+	key  []byte
+}
+
+func newCountDistinctValue(tx *SQLTx, sels []string) *CountDistinctValue {
+	budget := defaultCountDistinctMemoryBudget
+	if tx != nil {
+		budget = tx.engine.countDistinctMemoryBudget
+	}
+
+	return &CountDistinctValue{
+		CountValue: CountValue{sel: sels[0]},
+		sels:       sels,
+		set:        newDistinctSet(tx, budget),
+	}
+}
+
+func (v *CountDistinctValue) ColBounded() bool {
+	return true
+}
+
+func (v *CountDistinctValue) updateWith(val TypedValue) error {
+	if len(v.sels) > 1 {
+		return fmt.Errorf("%w: %d values expected", ErrIllegalArguments, len(v.sels))
+	}
+
+	if val.IsNull() {
+		// Skip NULL values
+		return nil
+	}
+
+	key, err := distinctKey(v.key[:0], val)
+	if err != nil {
+		return err
+	}
+	v.key = key
+
+	return v.set.add(key)
+}
+
+func (v *CountDistinctValue) updateWithRow(row *Row) error {
+	key := v.key[:0]
+
+	for _, sel := range v.sels {
+		val, ok := row.ValuesBySelector[sel]
+		if !ok {
+			return fmt.Errorf("%w (%s)", ErrColumnDoesNotExist, sel)
+		}
+
+		if val.IsNull() {
+			return nil
+		}
+
+		var err error
+
+		key, err = distinctKey(key, val)
+		if err != nil {
+			return err
+		}
+	}
+
+	v.key = key
+
+	return v.set.add(key)
+}
+
+func (v *CountDistinctValue) finalize() (TypedValue, error) {
+	n, err := v.set.count()
+	if err != nil {
+		return nil, err
+	}
+
+	v.c = n
+	v.set = nil
+
+	return &Integer{val: n}, nil
+}
+
+// distinctKey appends the canonical encoding of the value to the key. Encoded values are
+// prefixed with their length, thus the encodings of several values can be concatenated
+func distinctKey(key []byte, val TypedValue) ([]byte, error) {
+	if f, ok := val.RawValue().(float64); ok && f == 0 {
+		// -0 and 0 are the same value
+		val = &Float64{}
+	}
+
+	encVal, err := EncodeValue(val, val.Type(), -1)
+	if err != nil {
+		return nil, err
+	}
+	return append(key, encVal...), nil
+}
+
+// distinctSet holds a set of keys in memory until the memory budget is exceeded. From then on,
+// the keys are sorted and written to a temporary file each time the budget is exceeded, and the
+// distinct keys are counted by merging the sorted runs once all the keys were added
+type distinctSet struct {
+	tx     *SQLTx
+	budget int
+
+	keys    map[string]struct{}
+	memSize int
+
+	file     *os.File
+	writer   *bufio.Writer
+	fileSize int64
+	runs     []sortedChunk
+}
+
+func newDistinctSet(tx *SQLTx, budget int) *distinctSet {
+	return &distinctSet{
+		tx:     tx,
+		budget: budget,
+		keys:   make(map[string]struct{}),
+	}
+}
+
+func (s *distinctSet) add(key []byte) error {
+	if _, ok := s.keys[string(key)]; ok {
+		return nil
+	}
+
+	s.keys[string(key)] = struct{}{}
+	s.memSize += len(key) + distinctKeyOverhead
+
+	if s.memSize > s.budget && s.tx != nil {
+		return s.spill()
+	}
+	return nil
+}
+
+func (s *distinctSet) spill() error {
+	if s.writer == nil {
+		file, err := s.tx.createTempFile()
+		if err != nil {
+			return err
+		}
+		s.file = file
+		s.writer = bufio.NewWriter(file)
+	}
+
+	keys := make([]string, 0, len(s.keys))
+	for k := range s.keys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var runSize int64
+	var lenBuf [4]byte
+
+	for _, k := range keys {
+		binary.BigEndian.PutUint32(lenBuf[:], uint32(len(k)))
+
+		_, err := s.writer.Write(lenBuf[:])
+		if err != nil {
+			return err
+		}
+
+		_, err = s.writer.WriteString(k)
+		if err != nil {
+			return err
+		}
+
+		runSize += int64(len(lenBuf) + len(k))
+	}
+
+	s.runs = append(s.runs, sortedChunk{
+		offset: uint64(s.fileSize),
+		size:   uint64(runSize),
+	})
+	s.fileSize += runSize
+
+	s.keys = make(map[string]struct{})
+	s.memSize = 0
+
+	return nil
+}
+
+func (s *distinctSet) count() (int64, error) {
+	if len(s.runs) == 0 {
+		return int64(len(s.keys)), nil
+	}
+
+	if len(s.keys) > 0 {
+		err := s.spill()
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	err := s.writer.Flush()
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := s.countMergingRuns()
+	if err != nil {
+		return 0, err
+	}
+
+	// the file is removed once the transaction is closed
+	return n, s.file.Truncate(0)
+}
+
+func (s *distinctSet) countMergingRuns() (int64, error) {
+	h := make(runHeap, 0, len(s.runs))
+
+	for _, run := range s.runs {
+		r := &runReader{
+			reader: bufio.NewReader(io.NewSectionReader(s.file, int64(run.offset), int64(run.size))),
+		}
+
+		ok, err := r.next()
+		if err != nil {
+			return 0, err
+		}
+		if ok {
+			h = append(h, r)
+		}
+	}
+
+	heap.Init(&h)
+
+	var n int64
+	var last []byte
+
+	for len(h) > 0 {
+		r := h[0]
+
+		if n == 0 || !bytes.Equal(last, r.key) {
+			last = append(last[:0], r.key...)
+			n++
+		}
+
+		ok, err := r.next()
+		if err != nil {
+			return 0, err
+		}
+
+		if ok {
+			heap.Fix(&h, 0)
+		} else {
+			heap.Pop(&h)
+		}
+	}
+
+	return n, nil
+}
+
+type runReader struct {
+	reader *bufio.Reader
+	key    []byte
+}
+
+func (r *runReader) next() (bool, error) {
+	var lenBuf [4]byte
+
+	_, err := io.ReadFull(r.reader, lenBuf[:])
+	if err == io.EOF {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	size := binary.BigEndian.Uint32(lenBuf[:])
+
+	if cap(r.key) < int(size) {
+		r.key = make([]byte, size)
+	}
+	r.key = r.key[:size]
+
+	_, err = io.ReadFull(r.reader, r.key)
+	return err == nil, err
+}
+
+type runHeap []*runReader
+
+func (h runHeap) Len() int           { return len(h) }
+func (h runHeap) Less(i, j int) bool { return bytes.Compare(h[i].key, h[j].key) < 0 }
+func (h runHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+
+func (h *runHeap) Push(x interface{}) {
+	*h = append(*h, x.(*runReader))
+}
+
+func (h *runHeap) Pop() interface{} {
+	old := *h
+	r := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return r
+}
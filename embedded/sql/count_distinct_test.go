@@ -0,0 +1,192 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountDistinct(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, "CREATE TABLE events (id INTEGER, user_id INTEGER, kind VARCHAR, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	const (
+		events    = 20000
+		users     = 5000
+		batchSize = 1000
+	)
+
+	userIDs := make(map[int]bool)
+	pairs := make(map[string]bool)
+
+	for b := 0; b < events/batchSize; b++ {
+		values := make([]string, batchSize)
+
+		for i := range values {
+			id := b*batchSize + i
+
+			kind := "click"
+			if id%3 == 0 {
+				kind = "view"
+			}
+
+			userID := (id * 7919) % users
+
+			userIDs[userID] = true
+			pairs[fmt.Sprintf("%s-%d", kind, userID)] = true
+
+			values[i] = fmt.Sprintf("(%d, %d, '%s')", id, userID, kind)
+		}
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO events (id, user_id, kind) VALUES "+strings.Join(values, ", "), nil)
+		require.NoError(t, err)
+	}
+
+	// NULL values are not counted
+	_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO events (id, kind) VALUES (-1, 'click'), (-2, 'other')", nil)
+	require.NoError(t, err)
+
+	t.Run("count over the whole table", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT COUNT(DISTINCT user_id), count(distinct kind), COUNT(*) FROM events", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+
+		require.Equal(t, int64(len(userIDs)), rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, int64(3), rows[0].ValuesByPosition[1].RawValue())
+		require.Equal(t, int64(events+2), rows[0].ValuesByPosition[2].RawValue())
+	})
+
+	t.Run("count over multiple columns", func(t *testing.T) {
+		// each aggregation is queried on its own, so that no other one requires its columns
+		for _, sql := range []string{
+			"SELECT COUNT(DISTINCT kind, user_id) FROM events",
+			"SELECT COUNT(DISTINCT events.user_id, kind) FROM events",
+		} {
+			rows, err := engine.queryAll(context.Background(), nil, sql, nil)
+			require.NoError(t, err, sql)
+			require.Len(t, rows, 1, sql)
+
+			// rows holding a NULL value in any of the columns are not counted
+			require.Equal(t, int64(len(pairs)), rows[0].ValuesByPosition[0].RawValue(), sql)
+		}
+
+		_, _, err := engine.Exec(context.Background(), nil, `
+			CREATE TABLE pairs (id INTEGER AUTO_INCREMENT, v INTEGER, s VARCHAR, PRIMARY KEY id);
+			INSERT INTO pairs (v, s) VALUES (10, 'a'), (NULL, 'b'), (30, NULL), (10, 'd');
+		`, nil)
+		require.NoError(t, err)
+
+		for _, sql := range []string{
+			"SELECT COUNT(DISTINCT v, s) FROM pairs",
+			"SELECT COUNT(DISTINCT s, v) FROM pairs",
+		} {
+			rows, err := engine.queryAll(context.Background(), nil, sql, nil)
+			require.NoError(t, err, sql)
+			require.Len(t, rows, 1, sql)
+
+			// rows holding a NULL value in only one of the columns are not counted either
+			require.Equal(t, int64(2), rows[0].ValuesByPosition[0].RawValue(), sql)
+		}
+	})
+
+	t.Run("count by group", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT kind, COUNT(DISTINCT user_id) AS users FROM events GROUP BY kind HAVING COUNT(DISTINCT user_id) > 0 ORDER BY kind", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+
+		for _, row := range rows {
+			kind := row.ValuesByPosition[0].RawValue().(string)
+
+			expected := 0
+			for pair := range pairs {
+				if strings.HasPrefix(pair, kind+"-") {
+					expected++
+				}
+			}
+			require.Equal(t, int64(expected), row.ValuesByPosition[1].RawValue())
+		}
+	})
+
+	t.Run("count over no rows", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT COUNT(DISTINCT user_id, kind) FROM events WHERE id < -10", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, int64(0), rows[0].ValuesByPosition[0].RawValue())
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT COUNT(DISTINCT user_id) FROM events WHERE id < 0", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, int64(0), rows[0].ValuesByPosition[0].RawValue())
+	})
+
+	t.Run("distinct values are counted after spilling to disk", func(t *testing.T) {
+		_, err := NewEngine(engine.store, DefaultOptions().WithPrefix(sqlPrefix).WithCountDistinctMemoryBudget(0))
+		require.ErrorIs(t, err, store.ErrInvalidOptions)
+
+		spillingEngine, err := NewEngine(engine.store, DefaultOptions().WithPrefix(sqlPrefix).WithCountDistinctMemoryBudget(4096))
+		require.NoError(t, err)
+
+		rows, err := spillingEngine.queryAll(context.Background(), nil, "SELECT COUNT(DISTINCT user_id), COUNT(DISTINCT id), COUNT(DISTINCT kind, user_id) FROM events", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+
+		require.Equal(t, int64(len(userIDs)), rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, int64(events+2), rows[0].ValuesByPosition[1].RawValue())
+		require.Equal(t, int64(len(pairs)), rows[0].ValuesByPosition[2].RawValue())
+
+		rows, err = spillingEngine.queryAll(context.Background(), nil, "SELECT kind, COUNT(DISTINCT user_id) FROM events GROUP BY kind ORDER BY kind", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		require.Equal(t, int64(0), rows[1].ValuesByPosition[1].RawValue())
+	})
+
+	t.Run("sorted runs are merged", func(t *testing.T) {
+		tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithReadOnly(true))
+		require.NoError(t, err)
+		defer tx.Cancel()
+
+		set := newDistinctSet(tx, 1024)
+
+		for i := 0; i < 10000; i++ {
+			require.NoError(t, set.add([]byte(fmt.Sprintf("key%d", i%3000))))
+		}
+		require.Greater(t, len(set.runs), 1)
+
+		n, err := set.count()
+		require.NoError(t, err)
+		require.Equal(t, int64(3000), n)
+	})
+
+	t.Run("distinct can only be used when counting", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, "SELECT SUM(DISTINCT user_id) FROM events", nil)
+		require.ErrorIs(t, err, ErrParsingError)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT COUNT(DISTINCT missing) FROM events", nil)
+		require.ErrorIs(t, err, ErrColumnDoesNotExist)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT COUNT(DISTINCT user_id, missing) FROM events", nil)
+		require.ErrorIs(t, err, ErrColumnDoesNotExist)
+	})
+}
@@ -19,13 +19,24 @@ package sql
 import (
 	"context"
 	"crypto/sha256"
+	"iter"
 )
 
+// distinctRowReader skips the rows already returned, which are kept track of by their digest.
+// When identical rows are known to be read one after the other, only the digest of the last
+// returned row is kept.
 type distinctRowReader struct {
 	rowReader RowReader
 	cols      []ColDescriptor
 
 	readRows map[[sha256.Size]byte]struct{}
+
+	sorted  bool
+	lastRow *[sha256.Size]byte
+
+	resources *resourceTracker
+
+	lookahead rowLookahead
 }
 
 func newDistinctRowReader(ctx context.Context, rowReader RowReader) (*distinctRowReader, error) {
@@ -38,9 +49,23 @@ func newDistinctRowReader(ctx context.Context, rowReader RowReader) (*distinctRo
 		rowReader: rowReader,
 		cols:      cols,
 		readRows:  make(map[[sha256.Size]byte]struct{}),
+		resources: rowReader.Tx().stmtResources(),
 	}, nil
 }
 
+// newSortedDistinctRowReader returns a reader which skips identical rows read one after the other,
+// thus rowReader must be sorted by expressions equal for identical rows.
+func newSortedDistinctRowReader(ctx context.Context, rowReader RowReader) (*distinctRowReader, error) {
+	dr, err := newDistinctRowReader(ctx, rowReader)
+	if err != nil {
+		return nil, err
+	}
+
+	dr.sorted = true
+
+	return dr, nil
+}
+
 func (dr *distinctRowReader) onClose(callback func()) {
 	dr.rowReader.onClose(callback)
 }
@@ -77,7 +102,19 @@ func (dr *distinctRowReader) InferParameters(ctx context.Context, params map[str
 	return dr.rowReader.InferParameters(ctx, params)
 }
 
+func (dr *distinctRowReader) All(ctx context.Context) iter.Seq2[*Row, error] {
+	return allRows(ctx, dr)
+}
+
 func (dr *distinctRowReader) Read(ctx context.Context) (*Row, error) {
+	return dr.lookahead.read(ctx, dr.readRow)
+}
+
+func (dr *distinctRowReader) Peek(ctx context.Context) (*Row, error) {
+	return dr.lookahead.peek(ctx, dr.readRow)
+}
+
+func (dr *distinctRowReader) readRow(ctx context.Context) (*Row, error) {
 	for {
 		if len(dr.readRows) == dr.rowReader.Tx().distinctLimit() {
 			return nil, ErrTooManyRows
@@ -93,11 +130,26 @@ func (dr *distinctRowReader) Read(ctx context.Context) (*Row, error) {
 			return nil, err
 		}
 
+		if dr.sorted {
+			if dr.lastRow != nil && *dr.lastRow == digest {
+				continue
+			}
+
+			dr.lastRow = &digest
+
+			return row, nil
+		}
+
 		_, ok := dr.readRows[digest]
 		if ok {
 			continue
 		}
 
+		err = dr.resources.allocate(distinctEntrySize)
+		if err != nil {
+			return nil, err
+		}
+
 		dr.readRows[digest] = struct{}{}
 
 		return row, nil
@@ -105,5 +157,7 @@ func (dr *distinctRowReader) Read(ctx context.Context) (*Row, error) {
 }
 
 func (dr *distinctRowReader) Close() error {
+	dr.lookahead.discard()
+
 	return dr.rowReader.Close()
 }
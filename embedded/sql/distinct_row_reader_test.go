@@ -18,6 +18,7 @@ package sql
 
 import (
 	"context"
+	"sort"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -57,3 +58,106 @@ func TestDistinctRowReader(t *testing.T) {
 	err = rowReader.InferParameters(context.Background(), nil)
 	require.ErrorIs(t, err, errDummy)
 }
+
+func distinctValues(t testing.TB, engine *Engine, sql string) ([]int64, *distinctRowReader) {
+	r, err := engine.Query(context.Background(), nil, sql, nil)
+	require.NoError(t, err)
+	defer r.Close()
+
+	dr, ok := r.(*distinctRowReader)
+	require.True(t, ok)
+
+	var values []int64
+	for row, err := range r.All(context.Background()) {
+		require.NoError(t, err)
+		values = append(values, row.ValuesByPosition[0].RawValue().(int64))
+	}
+	return values, dr
+}
+
+func TestSortedDistinctRowReader(t *testing.T) {
+	const groups, rowsPerGroup = 100, 10
+
+	engine := setupGroupsTest(t, groups, rowsPerGroup)
+
+	expected := make([]int64, groups)
+	for i := range expected {
+		expected[i] = int64(i)
+	}
+
+	for _, sql := range []string{
+		"SELECT DISTINCT grp FROM events ORDER BY grp",
+		"SELECT DISTINCT grp FROM events USE INDEX ON (grp) WHERE grp >= 0",
+		"SELECT DISTINCT grp FROM events ORDER BY grp DESC",
+		"SELECT DISTINCT other FROM events ORDER BY other",
+		"SELECT DISTINCT grp, other FROM events ORDER BY other, grp",
+	} {
+		t.Run(sql, func(t *testing.T) {
+			values, dr := distinctValues(t, engine, sql)
+			require.True(t, dr.sorted)
+
+			// only the last row is kept track of
+			require.Empty(t, dr.readRows)
+
+			sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+			require.Equal(t, expected, values)
+		})
+	}
+
+	t.Run("rows not sorted by the selected columns", func(t *testing.T) {
+		for _, sql := range []string{
+			"SELECT DISTINCT grp FROM events",
+			"SELECT DISTINCT grp FROM events ORDER BY other",
+			"SELECT DISTINCT grp, amount % 1 FROM events ORDER BY grp",
+			"SELECT DISTINCT grp, other FROM events ORDER BY grp, amount, other",
+		} {
+			t.Run(sql, func(t *testing.T) {
+				values, dr := distinctValues(t, engine, sql)
+				require.False(t, dr.sorted)
+				require.Len(t, dr.readRows, groups)
+
+				sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+				require.Equal(t, expected, values)
+			})
+		}
+	})
+
+	t.Run("rows sorted by the selected columns are not limited", func(t *testing.T) {
+		engine.distinctLimit = groups / 2
+		defer func() { engine.distinctLimit = defaultDistinctLimit }()
+
+		values, _ := distinctValues(t, engine, "SELECT DISTINCT grp FROM events ORDER BY grp")
+		require.Len(t, values, groups)
+
+		r, err := engine.Query(context.Background(), nil, "SELECT DISTINCT grp FROM events", nil)
+		require.NoError(t, err)
+		defer r.Close()
+
+		for _, err := range r.All(context.Background()) {
+			if err != nil {
+				require.ErrorIs(t, err, ErrTooManyRows)
+				return
+			}
+		}
+		require.Fail(t, "the number of distinct rows is expected to be limited")
+	})
+}
+
+func BenchmarkDistinct(b *testing.B) {
+	const groups, rowsPerGroup = 10_000, 2
+
+	engine := setupGroupsTest(b, groups, rowsPerGroup)
+
+	for name, sql := range map[string]string{
+		"sorted": "SELECT DISTINCT grp FROM events ORDER BY grp",
+		"hash":   "SELECT DISTINCT grp FROM events",
+	} {
+		b.Run(name, func(b *testing.B) {
+			for b.Loop() {
+				rows, err := engine.queryAll(context.Background(), nil, sql, nil)
+				require.NoError(b, err)
+				require.Len(b, rows, groups)
+			}
+		})
+	}
+}
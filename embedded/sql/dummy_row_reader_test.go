@@ -19,6 +19,7 @@ package sql
 import (
 	"context"
 	"errors"
+	"iter"
 )
 
 var errDummy = errors.New("dummy error")
@@ -49,10 +50,18 @@ func (r *dummyRowReader) TableAlias() string {
 	return "table1"
 }
 
+func (r *dummyRowReader) All(ctx context.Context) iter.Seq2[*Row, error] {
+	return allRows(ctx, r)
+}
+
 func (r *dummyRowReader) Read(ctx context.Context) (*Row, error) {
 	return nil, errDummy
 }
 
+func (r *dummyRowReader) Peek(ctx context.Context) (*Row, error) {
+	return nil, errDummy
+}
+
 func (r *dummyRowReader) Close() error {
 	if r.recordClose {
 		if r.closed {
@@ -22,83 +22,105 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/codenotary/immudb/embedded/store"
 )
 
 var (
-	ErrNoSupported                            = errors.New("not supported")
+	ErrNoSupported                            = newSQLError(ErrCodeUnsupported, "not supported")
 	ErrIllegalArguments                       = store.ErrIllegalArguments
 	ErrMultiIndexingNotEnabled                = fmt.Errorf("%w: multi-indexing must be enabled", store.ErrIllegalState)
-	ErrParsingError                           = errors.New("parsing error")
-	ErrDDLorDMLTxOnly                         = errors.New("transactions can NOT combine DDL and DML statements")
+	ErrParsingError                           = newSQLError(ErrCodeSyntax, "parsing error")
+	ErrDDLorDMLTxOnly                         = newSQLError(ErrCodeTransaction, "transactions can NOT combine DDL and DML statements")
 	ErrUnspecifiedMultiDBHandler              = fmt.Errorf("%w: unspecified multidbHanlder", store.ErrIllegalState)
-	ErrDatabaseDoesNotExist                   = errors.New("database does not exist")
-	ErrDatabaseAlreadyExists                  = errors.New("database already exists")
-	ErrTableAlreadyExists                     = errors.New("table already exists")
-	ErrTableDoesNotExist                      = errors.New("table does not exist")
-	ErrColumnDoesNotExist                     = errors.New("column does not exist")
-	ErrColumnAlreadyExists                    = errors.New("column already exists")
-	ErrCannotDropColumn                       = errors.New("cannot drop column")
-	ErrSameOldAndNewNames                     = errors.New("same old and new names")
-	ErrColumnNotIndexed                       = errors.New("column is not indexed")
-	ErrFunctionDoesNotExist                   = errors.New("function does not exist")
-	ErrLimitedKeyType                         = errors.New("indexed key of unsupported type or exceeded length")
-	ErrLimitedAutoIncrement                   = errors.New("only INTEGER single-column primary keys can be set as auto incremental")
-	ErrLimitedMaxLen                          = errors.New("only VARCHAR and BLOB types support max length")
-	ErrDuplicatedColumn                       = errors.New("duplicated column")
-	ErrInvalidColumn                          = errors.New("invalid column")
-	ErrInvalidCheckConstraint                 = errors.New("invalid check constraint")
-	ErrCheckConstraintViolation               = errors.New("check constraint violation")
-	ErrReservedWord                           = errors.New("reserved word")
-	ErrNoPrimaryKey                           = errors.New("no primary key specified")
-	ErrPKCanNotBeNull                         = errors.New("primary key can not be null")
-	ErrPKCanNotBeUpdated                      = errors.New("primary key can not be updated")
-	ErrMultiplePrimaryKeys                    = errors.New("multiple primary keys are not allowed")
-	ErrNotNullableColumnCannotBeNull          = errors.New("not nullable column can not be null")
-	ErrNewColumnMustBeNullable                = errors.New("new column must be nullable")
-	ErrIndexAlreadyExists                     = errors.New("index already exists")
-	ErrMaxNumberOfColumnsInIndexExceeded      = errors.New("number of columns in multi-column index exceeded")
-	ErrIndexNotFound                          = errors.New("index not found")
-	ErrConstraintNotFound                     = errors.New("constraint not found")
-	ErrInvalidNumberOfValues                  = errors.New("invalid number of values provided")
-	ErrInvalidValue                           = errors.New("invalid value provided")
-	ErrInferredMultipleTypes                  = errors.New("inferred multiple types")
-	ErrExpectingDQLStmt                       = errors.New("illegal statement. DQL statement expected")
-	ErrColumnMustAppearInGroupByOrAggregation = errors.New("must appear in the group by clause or be used in an aggregated function")
-	ErrIllegalMappedKey                       = errors.New("error illegal mapped key")
+	ErrDatabaseDoesNotExist                   = newSQLError(ErrCodeNotFound, "database does not exist")
+	ErrDatabaseAlreadyExists                  = newSQLError(ErrCodeAlreadyExists, "database already exists")
+	ErrTableAlreadyExists                     = newSQLError(ErrCodeAlreadyExists, "table already exists")
+	ErrTableDoesNotExist                      = newSQLError(ErrCodeNotFound, "table does not exist")
+	ErrCannotDropTable                        = newSQLError(ErrCodeInvalid, "cannot drop table")
+	ErrSchemaMismatch                         = newSQLError(ErrCodeInvalid, "schema mismatch")
+	ErrViewAlreadyExists                      = newSQLError(ErrCodeAlreadyExists, "view already exists")
+	ErrViewDoesNotExist                       = newSQLError(ErrCodeNotFound, "view does not exist")
+	ErrMaxViewNestingExceeded                 = newSQLError(ErrCodeInvalid, "max view nesting level exceeded")
+	ErrTriggerAlreadyExists                   = newSQLError(ErrCodeAlreadyExists, "trigger already exists")
+	ErrTriggerDoesNotExist                    = newSQLError(ErrCodeNotFound, "trigger does not exist")
+	ErrMaxTriggerDepthExceeded                = newSQLError(ErrCodeInvalid, "max trigger depth exceeded")
+	ErrColumnDoesNotExist                     = newSQLError(ErrCodeNotFound, "column does not exist")
+	ErrColumnAlreadyExists                    = newSQLError(ErrCodeAlreadyExists, "column already exists")
+	ErrCannotDropColumn                       = newSQLError(ErrCodeInvalid, "cannot drop column")
+	ErrSameOldAndNewNames                     = newSQLError(ErrCodeInvalid, "same old and new names")
+	ErrColumnNotIndexed                       = newSQLError(ErrCodeInvalid, "column is not indexed")
+	ErrFunctionDoesNotExist                   = newSQLError(ErrCodeNotFound, "function does not exist")
+	ErrFunctionAlreadyExists                  = newSQLError(ErrCodeAlreadyExists, "function already exists")
+	ErrLimitedKeyType                         = newSQLError(ErrCodeUnsupported, "indexed key of unsupported type or exceeded length")
+	ErrLimitedAutoIncrement                   = newSQLError(ErrCodeUnsupported, "only INTEGER single-column primary keys can be set as auto incremental")
+	ErrLimitedMaxLen                          = newSQLError(ErrCodeUnsupported, "only VARCHAR and BLOB types support max length")
+	ErrDuplicatedColumn                       = newSQLError(ErrCodeInvalid, "duplicated column")
+	ErrInvalidColumn                          = newSQLError(ErrCodeInvalid, "invalid column")
+	ErrInvalidCheckConstraint                 = newSQLError(ErrCodeInvalid, "invalid check constraint")
+	ErrCheckConstraintViolation               = newSQLError(ErrCodeConstraint, "check constraint violation")
+	ErrReservedWord                           = newSQLError(ErrCodeSyntax, "reserved word")
+	ErrNoPrimaryKey                           = newSQLError(ErrCodeInvalid, "no primary key specified")
+	ErrPKCanNotBeNull                         = newSQLError(ErrCodeConstraint, "primary key can not be null")
+	ErrPKCanNotBeUpdated                      = newSQLError(ErrCodeConstraint, "primary key can not be updated")
+	ErrGeneratedColumnCanNotBeSet             = newSQLError(ErrCodeConstraint, "generated column can not be set")
+	ErrMultiplePrimaryKeys                    = newSQLError(ErrCodeInvalid, "multiple primary keys are not allowed")
+	ErrNotNullableColumnCannotBeNull          = newSQLError(ErrCodeConstraint, "not nullable column can not be null")
+	ErrNewColumnMustBeNullable                = newSQLError(ErrCodeInvalid, "new column must be nullable")
+	ErrIndexAlreadyExists                     = newSQLError(ErrCodeAlreadyExists, "index already exists")
+	ErrMaxNumberOfColumnsInIndexExceeded      = newSQLError(ErrCodeInvalid, "number of columns in multi-column index exceeded")
+	ErrIndexNotFound                          = newSQLError(ErrCodeNotFound, "index not found")
+	ErrConstraintNotFound                     = newSQLError(ErrCodeNotFound, "constraint not found")
+	ErrInvalidNumberOfValues                  = newSQLError(ErrCodeInvalid, "invalid number of values provided")
+	ErrInvalidValue                           = newSQLError(ErrCodeType, "invalid value provided")
+	ErrInferredMultipleTypes                  = newSQLError(ErrCodeType, "inferred multiple types")
+	ErrExpectingDQLStmt                       = newSQLError(ErrCodeInvalid, "illegal statement. DQL statement expected")
+	ErrColumnMustAppearInGroupByOrAggregation = newSQLError(ErrCodeInvalid, "must appear in the group by clause or be used in an aggregated function")
+	ErrIllegalMappedKey                       = newSQLError(ErrCodeInternal, "error illegal mapped key")
 	ErrCorruptedData                          = store.ErrCorruptedData
 	ErrBrokenCatalogColSpecExpirable          = fmt.Errorf("%w: catalog column entry set as expirable", ErrCorruptedData)
 	ErrBrokenCatalogCheckConstraintExpirable  = fmt.Errorf("%w: catalog check constraint set as expirable", ErrCorruptedData)
+	ErrRowIntegrity                           = fmt.Errorf("%w: row checksum mismatch", ErrCorruptedData)
 	ErrNoMoreRows                             = store.ErrNoMoreEntries
-	ErrInvalidTypes                           = errors.New("invalid types")
-	ErrUnsupportedJoinType                    = errors.New("unsupported join type")
-	ErrInvalidCondition                       = errors.New("invalid condition")
-	ErrHavingClauseRequiresGroupClause        = errors.New("having clause requires group clause")
-	ErrNotComparableValues                    = errors.New("values are not comparable")
-	ErrNumericTypeExpected                    = errors.New("numeric type expected")
-	ErrUnexpected                             = errors.New("unexpected error")
-	ErrMaxKeyLengthExceeded                   = errors.New("max key length exceeded")
-	ErrMaxLengthExceeded                      = errors.New("max length exceeded")
-	ErrColumnIsNotAnAggregation               = errors.New("column is not an aggregation")
-	ErrLimitedCount                           = errors.New("only unbounded counting is supported i.e. COUNT(*)")
-	ErrTxDoesNotExist                         = errors.New("tx does not exist")
-	ErrNestedTxNotSupported                   = errors.New("nested tx are not supported")
-	ErrNoOngoingTx                            = errors.New("no ongoing transaction")
-	ErrNonTransactionalStmt                   = errors.New("non transactional statement")
-	ErrDivisionByZero                         = errors.New("division by zero")
-	ErrMissingParameter                       = errors.New("missing parameter")
-	ErrUnsupportedParameter                   = errors.New("unsupported parameter")
-	ErrDuplicatedParameters                   = errors.New("duplicated parameters")
-	ErrLimitedIndexCreation                   = errors.New("unique index creation is only supported on empty tables")
-	ErrTooManyRows                            = errors.New("too many rows")
+	ErrInvalidTypes                           = newSQLError(ErrCodeType, "invalid types")
+	ErrUnsupportedJoinType                    = newSQLError(ErrCodeUnsupported, "unsupported join type")
+	ErrInvalidCondition                       = newSQLError(ErrCodeType, "invalid condition")
+	ErrHavingClauseRequiresGroupClause        = newSQLError(ErrCodeInvalid, "having clause requires group clause")
+	ErrWithTiesRequiresOrderBy                = newSQLError(ErrCodeInvalid, "with ties requires order by clause")
+	ErrNotComparableValues                    = newSQLError(ErrCodeType, "values are not comparable")
+	ErrNumericTypeExpected                    = newSQLError(ErrCodeType, "numeric type expected")
+	ErrUnexpected                             = newSQLError(ErrCodeInternal, "unexpected error")
+	ErrMaxKeyLengthExceeded                   = newSQLError(ErrCodeConstraint, "max key length exceeded")
+	ErrMaxLengthExceeded                      = newSQLError(ErrCodeConstraint, "max length exceeded")
+	ErrColumnIsNotAnAggregation               = newSQLError(ErrCodeInvalid, "column is not an aggregation")
+	ErrLimitedCount                           = newSQLError(ErrCodeUnsupported, "only unbounded counting is supported i.e. COUNT(*)")
+	ErrTxDoesNotExist                         = newSQLError(ErrCodeNotFound, "tx does not exist")
+	ErrNestedTxNotSupported                   = newSQLError(ErrCodeTransaction, "nested tx are not supported")
+	ErrNoOngoingTx                            = newSQLError(ErrCodeTransaction, "no ongoing transaction")
+	ErrNonTransactionalStmt                   = newSQLError(ErrCodeTransaction, "non transactional statement")
+	ErrTxNotClosed                            = newSQLError(ErrCodeTransaction, "transaction not closed")
+	ErrDivisionByZero                         = newSQLError(ErrCodeInvalid, "division by zero")
+	ErrIntegerOverflow                        = newSQLError(ErrCodeInvalid, "integer out of range")
+	ErrMissingParameter                       = newSQLError(ErrCodeInvalid, "missing parameter")
+	ErrUnsupportedParameter                   = newSQLError(ErrCodeInvalid, "unsupported parameter")
+	ErrDuplicatedParameters                   = newSQLError(ErrCodeInvalid, "duplicated parameters")
+	ErrLimitedIndexCreation                   = newSQLError(ErrCodeUnsupported, "unique index creation is only supported on empty tables")
+	ErrTooManyRows                            = newSQLError(ErrCodeInvalid, "too many rows")
 	ErrAlreadyClosed                          = store.ErrAlreadyClosed
-	ErrAmbiguousSelector                      = errors.New("ambiguous selector")
+	ErrReaderClosed                           = fmt.Errorf("%w: reader closed", ErrAlreadyClosed)
+	ErrReadTimeout                            = newSQLError(ErrCodeNotFound, "no row read within the timeout")
+	ErrResourceLimitExceeded                  = newSQLError(ErrCodeInvalid, "resource limit exceeded")
+	ErrMaxMemoryExceeded                      = fmt.Errorf("%w: max memory", ErrResourceLimitExceeded)
+	ErrMaxRowsScannedExceeded                 = fmt.Errorf("%w: max rows scanned", ErrResourceLimitExceeded)
+	ErrMaxExecutionTimeExceeded               = fmt.Errorf("%w: max execution time", ErrResourceLimitExceeded)
+	ErrAmbiguousSelector                      = newSQLError(ErrCodeInvalid, "ambiguous selector")
 	ErrUnsupportedCast                        = fmt.Errorf("%w: unsupported cast", ErrInvalidValue)
-	ErrColumnMismatchInUnionStmt              = errors.New("column mismatch in union statement")
-	ErrCannotIndexJson                        = errors.New("cannot index column of type JSON")
-	ErrInvalidTxMetadata                      = errors.New("invalid transaction metadata")
-	ErrAccessDenied                           = errors.New("access denied")
+	ErrColumnMismatchInUnionStmt              = newSQLError(ErrCodeInvalid, "column mismatch in union statement")
+	ErrCannotIndexJson                        = newSQLError(ErrCodeUnsupported, "cannot index column of type JSON")
+	ErrInvalidTxMetadata                      = newSQLError(ErrCodeInvalid, "invalid transaction metadata")
+	ErrAccessDenied                           = newSQLError(ErrCodeAccessDenied, "access denied")
+	ErrReadOnly                               = newSQLError(ErrCodeAccessDenied, "engine is in read-only mode")
 )
 
 var MaxKeyLen = 512
@@ -110,6 +132,10 @@ const (
 
 const MaxNumberOfColumnsInIndex = 8
 
+const maxViewNesting = 32
+
+const maxTriggerDepth = 16
+
 type Engine struct {
 	store *store.ImmuStore
 
@@ -118,9 +144,29 @@ type Engine struct {
 	sortBufferSize                int
 	autocommit                    bool
 	lazyIndexConstraintValidation bool
+	readOnly                      bool
+	stableOrdering                bool
+	nullsOrder                    NullsOrder
+	validateIfNotExists           bool
+	clock                         func() time.Time
+	rand                          *lockedRand
 	parseTxMetadata               func([]byte) (map[string]interface{}, error)
 	multidbHandler                MultiDBHandler
 	tableResolvers                map[string]TableResolver
+	functions                     *functionRegistry
+	rowMiddleware                 *rowMiddleware
+	lazyDecoding                  bool
+	coalescer                     *commitCoalescer
+	countDistinctMemoryBudget     int
+	resourceLimits                ResourceLimits
+	keyEncoder                    KeyEncoder
+	rowChecksums                  *rowChecksums
+	skipErroredRows               bool
+	integerOverflow               IntegerOverflow
+	rowCounts                     bool
+	scanShards                    int
+	workers                       *workerPool
+	caseSensitiveIds              bool
 }
 
 type MultiDBHandler interface {
@@ -169,8 +215,45 @@ func NewEngine(st *store.ImmuStore, opts *Options) (*Engine, error) {
 		sortBufferSize:                opts.sortBufferSize,
 		autocommit:                    opts.autocommit,
 		lazyIndexConstraintValidation: opts.lazyIndexConstraintValidation,
+		readOnly:                      opts.readOnly,
+		stableOrdering:                opts.stableOrdering,
+		nullsOrder:                    opts.nullsOrder,
+		validateIfNotExists:           opts.validateIfNotExists,
+		clock:                         opts.clock,
 		parseTxMetadata:               opts.parseTxMetadata,
 		multidbHandler:                opts.multidbHandler,
+		lazyDecoding:                  opts.lazyDecoding,
+		countDistinctMemoryBudget:     opts.countDistinctMemoryBudget,
+		resourceLimits:                opts.resourceLimits,
+		keyEncoder:                    opts.keyEncoder,
+		skipErroredRows:               opts.skipErroredRows,
+		integerOverflow:               opts.integerOverflow,
+		rowCounts:                     opts.rowCounts,
+		scanShards:                    opts.scanShards,
+		caseSensitiveIds:              opts.caseSensitiveIds,
+		functions:                     newFunctionRegistry(),
+		rowMiddleware:                 newRowMiddleware(),
+	}
+
+	if opts.rowChecksums {
+		e.rowChecksums = &rowChecksums{key: opts.rowChecksumKey}
+	}
+
+	if opts.randSource != nil {
+		e.rand = newLockedRand(opts.randSource)
+	}
+
+	if opts.workerPoolSize > 0 {
+		e.workers = newWorkerPool(opts.workerPoolSize)
+	}
+
+	if opts.coalescingMaxBatch > 1 {
+		e.coalescer = newCommitCoalescer(e, opts.coalescingMaxDelay, opts.coalescingMaxBatch)
+	}
+
+	err = e.functions.registerAggregate(newApproxCountDistinct(uint8(opts.hllPrecision)))
+	if err != nil {
+		return nil, err
 	}
 
 	copy(e.prefix, opts.prefix)
@@ -184,6 +267,19 @@ func NewEngine(st *store.ImmuStore, opts *Options) (*Engine, error) {
 		return nil, err
 	}
 
+	err = st.InitIndexing(&store.IndexSpec{
+		SourcePrefix:     append(e.prefix, []byte(FullTextPrefix)...),
+		TargetPrefix:     append(e.prefix, []byte(FullTextPrefix)...),
+		InjectiveMapping: true,
+	})
+	if err != nil && !errors.Is(err, store.ErrIndexAlreadyInitialized) {
+		return nil, err
+	}
+
+	for _, r := range informationSchemaResolvers() {
+		e.registerTableResolver(r.Table(), r)
+	}
+
 	for _, r := range opts.tableResolvers {
 		e.registerTableResolver(r.Table(), r)
 	}
@@ -201,17 +297,24 @@ func (e *Engine) NewTx(ctx context.Context, opts *TxOptions) (*SQLTx, error) {
 	}
 
 	var mode store.TxMode
-	if opts.ReadOnly {
+	if opts.ReadOnly || e.readOnly {
 		mode = store.ReadOnlyTx
 	} else {
 		mode = store.ReadWriteTx
 	}
 
+	// read-only transactions don't observe the transactions committed after they begin
+	var snapshotTxID uint64
+	if mode == store.ReadOnlyTx {
+		snapshotTxID = e.store.LastCommittedTxID()
+	}
+
 	txOpts := &store.TxOptions{
 		Mode:                    mode,
 		SnapshotMustIncludeTxID: opts.SnapshotMustIncludeTxID,
 		SnapshotRenewalPeriod:   opts.SnapshotRenewalPeriod,
 		UnsafeMVCC:              opts.UnsafeMVCC,
+		SortedEntries:           opts.SortedWrites,
 	}
 
 	tx, err := e.store.NewTx(ctx, txOpts)
@@ -229,7 +332,7 @@ func (e *Engine) NewTx(ctx context.Context, opts *TxOptions) (*SQLTx, error) {
 		tx.WithMetadata(txmd)
 	}
 
-	catalog := newCatalog(e.prefix)
+	catalog := e.newCatalog()
 
 	err = catalog.load(ctx, tx)
 	if err != nil {
@@ -237,31 +340,7 @@ func (e *Engine) NewTx(ctx context.Context, opts *TxOptions) (*SQLTx, error) {
 	}
 
 	for _, table := range catalog.GetTables() {
-		primaryIndex := table.primaryIndex
-
-		rowEntryPrefix := MapKey(
-			e.prefix,
-			RowPrefix,
-			EncodeID(DatabaseID),
-			EncodeID(table.id),
-			EncodeID(primaryIndex.id),
-		)
-
-		mappedPKEntryPrefix := MapKey(
-			e.prefix,
-			MappedPrefix,
-			EncodeID(table.id),
-			EncodeID(primaryIndex.id),
-		)
-
-		err = e.store.InitIndexing(&store.IndexSpec{
-			SourcePrefix: rowEntryPrefix,
-
-			TargetEntryMapper: indexEntryMapperFor(primaryIndex, primaryIndex),
-			TargetPrefix:      mappedPKEntryPrefix,
-
-			InjectiveMapping: true,
-		})
+		err = e.store.InitIndexing(indexSpecFor(table.primaryIndex))
 		if err != nil && !errors.Is(err, store.ErrIndexAlreadyInitialized) {
 			return nil, err
 		}
@@ -271,21 +350,7 @@ func (e *Engine) NewTx(ctx context.Context, opts *TxOptions) (*SQLTx, error) {
 				continue
 			}
 
-			mappedEntryPrefix := MapKey(
-				e.prefix,
-				MappedPrefix,
-				EncodeID(table.id),
-				EncodeID(index.id),
-			)
-
-			err = e.store.InitIndexing(&store.IndexSpec{
-				SourcePrefix:      rowEntryPrefix,
-				SourceEntryMapper: indexEntryMapperFor(primaryIndex, primaryIndex),
-				TargetEntryMapper: indexEntryMapperFor(index, primaryIndex),
-				TargetPrefix:      mappedEntryPrefix,
-
-				InjectiveMapping: true,
-			})
+			err = e.store.InitIndexing(indexSpecFor(index))
 			if errors.Is(err, store.ErrIndexAlreadyInitialized) {
 				continue
 			}
@@ -318,16 +383,43 @@ func (e *Engine) NewTx(ctx context.Context, opts *TxOptions) (*SQLTx, error) {
 		}
 	}
 
+	var ts time.Time
+	if e.clock != nil {
+		ts = e.clock()
+	}
+
 	return &SQLTx{
 		engine:           e,
 		opts:             opts,
 		tx:               tx,
+		timestamp:        ts,
 		catalog:          catalog,
+		snapshotTxID:     snapshotTxID,
 		lastInsertedPKs:  make(map[string]int64),
 		firstInsertedPKs: make(map[string]int64),
 	}, nil
 }
 
+// indexSpecFor returns the spec of the store index keeping the entries of the index,
+// which are mapped from the rows of its table
+func indexSpecFor(index *Index) *store.IndexSpec {
+	primaryIndex := index.table.primaryIndex
+
+	spec := &store.IndexSpec{
+		SourcePrefix: index.table.rowsPrefix(),
+
+		TargetEntryMapper: indexEntryMapperFor(index, primaryIndex),
+		TargetPrefix:      index.entriesPrefix(),
+
+		InjectiveMapping: true,
+	}
+
+	if !index.IsPrimary() {
+		spec.SourceEntryMapper = indexEntryMapperFor(primaryIndex, primaryIndex)
+	}
+	return spec
+}
+
 func indexEntryMapperFor(index, primaryIndex *Index) store.EntryMapper {
 	// value={count (colID valLen val)+})
 	// key=M.{tableID}{indexID}({null}({val}{padding}{valLen})?)+({pkVal}{padding}{pkValLen})+
@@ -369,9 +461,7 @@ func indexEntryMapperFor(index, primaryIndex *Index) store.EntryMapper {
 	}
 
 	return func(key, value []byte) ([]byte, error) {
-		encodedValues := make([][]byte, 2+len(index.cols)+1)
-		encodedValues[0] = EncodeID(index.table.id)
-		encodedValues[1] = EncodeID(index.id)
+		encodedValues := make([][]byte, len(index.cols)+1)
 
 		valuesByColID := make(map[uint32]TypedValue, len(index.cols))
 
@@ -385,12 +475,12 @@ func indexEntryMapperFor(index, primaryIndex *Index) store.EntryMapper {
 		}
 
 		for i, col := range index.cols {
-			encKey, _, err := EncodeValueAsKey(valuesByColID[col.id], col.Type(), col.MaxLen())
+			encKey, _, err := index.encodeValueAsKey(valuesByColID[col.id], col)
 			if err != nil {
 				return nil, err
 			}
 
-			encodedValues[2+i] = encKey
+			encodedValues[i] = encKey
 		}
 
 		pkEncVals, err := encodedKey(primaryIndex, valuesByColID)
@@ -400,12 +490,12 @@ func indexEntryMapperFor(index, primaryIndex *Index) store.EntryMapper {
 
 		encodedValues[len(encodedValues)-1] = pkEncVals
 
-		return MapKey(index.enginePrefix(), MappedPrefix, encodedValues...), nil
+		return index.entryKey(encodedValues...), nil
 	}
 }
 
 func (e *Engine) Exec(ctx context.Context, tx *SQLTx, sql string, params map[string]interface{}) (ntx *SQLTx, committedTxs []*SQLTx, err error) {
-	stmts, err := ParseSQL(strings.NewReader(sql))
+	stmts, err := e.parseSQL(sql)
 	if err != nil {
 		return nil, nil, fmt.Errorf("%w: %v", ErrParsingError, err)
 	}
@@ -414,6 +504,12 @@ func (e *Engine) Exec(ctx context.Context, tx *SQLTx, sql string, params map[str
 }
 
 func (e *Engine) ExecPreparedStmts(ctx context.Context, tx *SQLTx, stmts []SQLStmt, params map[string]interface{}) (ntx *SQLTx, committedTxs []*SQLTx, err error) {
+	if tx == nil && e.coalescer != nil {
+		if stmt, ok := coalescableStmt(stmts); ok && !e.readOnly {
+			return e.coalescer.exec(ctx, stmt, params)
+		}
+	}
+
 	ntx, ctxs, pendingStmts, err := e.execPreparedStmts(ctx, tx, stmts, params)
 	if err != nil {
 		return ntx, ctxs, err
@@ -447,6 +543,14 @@ func (e *Engine) execPreparedStmts(ctx context.Context, tx *SQLTx, stmts []SQLSt
 		return nil, nil, stmts, ErrIllegalArguments
 	}
 
+	if e.readOnly {
+		for _, stmt := range stmts {
+			if stmt != nil && !stmt.readOnly() {
+				return nil, nil, stmts, ErrReadOnly
+			}
+		}
+	}
+
 	nparams, err := normalizeParams(params)
 	if err != nil {
 		return nil, nil, stmts, err
@@ -499,6 +603,12 @@ func (e *Engine) execPreparedStmts(ctx context.Context, tx *SQLTx, stmts []SQLSt
 			}
 		}
 
+		currTx.resources, err = e.newResourceTracker(ctx)
+		if err != nil {
+			currTx.Cancel()
+			return nil, committedTxs, stmts[execStmts:], err
+		}
+
 		ntx, err := stmt.execAt(ctx, currTx, nparams)
 		if err != nil {
 			currTx.Cancel()
@@ -586,7 +696,7 @@ func (e *Engine) queryAll(ctx context.Context, tx *SQLTx, sql string, params map
 }
 
 func (e *Engine) Query(ctx context.Context, tx *SQLTx, sql string, params map[string]interface{}) (RowReader, error) {
-	stmts, err := ParseSQL(strings.NewReader(sql))
+	stmts, err := e.parseSQL(sql)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrParsingError, err)
 	}
@@ -632,6 +742,11 @@ func (e *Engine) QueryPreparedStmt(ctx context.Context, tx *SQLTx, stmt DataSour
 		}
 	}
 
+	qtx.resources, err = e.newResourceTracker(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	_, err = stmt.execAt(ctx, qtx, nparams)
 	if err != nil {
 		return nil, err
@@ -646,6 +761,9 @@ func (e *Engine) QueryPreparedStmt(ctx context.Context, tx *SQLTx, stmt DataSour
 		r.onClose(func() {
 			qtx.Cancel()
 		})
+	} else {
+		// readers left open are closed when the transaction ends
+		qtx.trackReader(r)
 	}
 
 	return r, nil
@@ -666,7 +784,7 @@ func (e *Engine) Catalog(ctx context.Context, tx *SQLTx) (catalog *Catalog, err
 }
 
 func (e *Engine) InferParameters(ctx context.Context, tx *SQLTx, sql string) (params map[string]SQLValueType, err error) {
-	stmts, err := ParseSQL(strings.NewReader(sql))
+	stmts, err := e.parseSQL(sql)
 	if err != nil {
 		return nil, fmt.Errorf("%w: %v", ErrParsingError, err)
 	}
@@ -723,7 +841,7 @@ func (e *Engine) CopyCatalogToTx(ctx context.Context, tx *store.OngoingTx) error
 		return ErrIllegalArguments
 	}
 
-	catalog := newCatalog(e.prefix)
+	catalog := e.newCatalog()
 
 	err := catalog.addSchemaToTx(ctx, tx)
 	if err != nil {
@@ -733,6 +851,11 @@ func (e *Engine) CopyCatalogToTx(ctx context.Context, tx *store.OngoingTx) error
 	return nil
 }
 
+// ReadOnly returns true when the engine was opened in read-only mode
+func (e *Engine) ReadOnly() bool {
+	return e.readOnly
+}
+
 func (e *Engine) GetStore() *store.ImmuStore {
 	return e.store
 }
@@ -741,6 +864,70 @@ func (e *Engine) GetPrefix() []byte {
 	return e.prefix
 }
 
+// KeyEncoder returns the encoder of the keys of table rows and index entries
+func (e *Engine) KeyEncoder() KeyEncoder {
+	return e.keyEncoder
+}
+
+func (e *Engine) newCatalog() *Catalog {
+	catalog := newCatalog(e.prefix)
+	catalog.keyEncoder = e.keyEncoder
+	catalog.rowChecksums = e.rowChecksums
+	return catalog
+}
+
+// RegisterFunction makes a user-defined scalar function available to the statements
+// executed by the engine. Function names are case-insensitive and can not shadow
+// built-in functions nor previously registered ones.
+func (e *Engine) RegisterFunction(name string, fn ScalarFunc) error {
+	if name == "" {
+		return fmt.Errorf("%w: empty function name", ErrIllegalArguments)
+	}
+
+	err := fn.validate()
+	if err != nil {
+		return err
+	}
+
+	fnName := strings.ToUpper(name)
+
+	if _, isBuiltin := builtinFunctions[fnName]; isBuiltin {
+		return fmt.Errorf("%w (%s)", ErrFunctionAlreadyExists, fnName)
+	}
+
+	return e.functions.register(fnName, &userFunction{name: fnName, spec: fn})
+}
+
+// RegisterAggregate makes a user-defined aggregation available to the statements
+// executed by the engine. Aggregate names are case-insensitive and share the same
+// namespace as functions, thus they can not shadow any built-in or registered one.
+func (e *Engine) RegisterAggregate(name string, agg AggregateFunc) error {
+	if name == "" {
+		return fmt.Errorf("%w: empty aggregate name", ErrIllegalArguments)
+	}
+
+	err := agg.validate()
+	if err != nil {
+		return err
+	}
+
+	aggName := strings.ToUpper(name)
+
+	_, isBuiltinFn := builtinFunctions[aggName]
+	_, isBuiltinAgg := aggregateFns[aggName]
+	_, isKeyword := keywords[aggName]
+
+	if isBuiltinFn || isBuiltinAgg || isKeyword {
+		return fmt.Errorf("%w (%s)", ErrFunctionAlreadyExists, aggName)
+	}
+
+	return e.functions.registerAggregate(&userAggregate{name: aggName, spec: agg})
+}
+
+func (e *Engine) parseSQL(sql string) ([]SQLStmt, error) {
+	return parseSQL(strings.NewReader(sql), e.functions, e.caseSensitiveIds)
+}
+
 func (e *Engine) tableResolveFor(tableName string) TableResolver {
 	if e.tableResolvers == nil {
 		return nil
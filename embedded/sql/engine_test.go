@@ -17,6 +17,7 @@ limitations under the License.
 package sql
 
 import (
+	"bytes"
 	"context"
 	"encoding/hex"
 	"encoding/json"
@@ -25,10 +26,13 @@ import (
 	"math"
 	"math/rand"
 	"os"
+	"runtime"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -553,6 +557,84 @@ func TestUUIDNonPK(t *testing.T) {
 
 }
 
+func TestUUIDLiterals(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, `
+		CREATE TABLE devices (id UUID, name VARCHAR, PRIMARY KEY id);
+		CREATE TABLE readings (id INTEGER AUTO_INCREMENT, device UUID, val INTEGER, PRIMARY KEY id);
+	`, nil)
+	require.NoError(t, err)
+
+	id := uuid.MustParse("19bb3a41-5c6a-4e2c-9f1e-2f1d6c0b7a10")
+
+	t.Run("uuid strings are parsed when inserted", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, `
+			INSERT INTO devices (id, name) VALUES ('19bb3a41-5c6a-4e2c-9f1e-2f1d6c0b7a10', 'sensor');
+			INSERT INTO devices (id, name) VALUES (GEN_RANDOM_UUID(), 'probe');
+			INSERT INTO readings (device, val) VALUES ('19BB3A41-5C6A-4E2C-9F1E-2F1D6C0B7A10', 1), (RANDOM_UUID(), 2);
+		`, nil)
+		require.NoError(t, err)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT id, id::VARCHAR FROM devices WHERE name = 'sensor'", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, id, rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, id.String(), rows[0].ValuesByPosition[1].RawValue())
+	})
+
+	t.Run("generated uuids are random", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT GEN_RANDOM_UUID(), GEN_RANDOM_UUID() FROM devices", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+
+		u := rows[0].ValuesByPosition[0].RawValue().(uuid.UUID)
+		require.Equal(t, uuid.Version(4), u.Version())
+		require.NotEqual(t, u, rows[0].ValuesByPosition[1].RawValue())
+		require.NotEqual(t, u, rows[1].ValuesByPosition[0].RawValue())
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT GEN_RANDOM_UUID(1) FROM devices", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+	})
+
+	t.Run("uuids are compared with uuid strings", func(t *testing.T) {
+		for _, sql := range []string{
+			"SELECT name FROM devices WHERE id = '19bb3a41-5c6a-4e2c-9f1e-2f1d6c0b7a10'",
+			"SELECT name FROM devices WHERE '19BB3A41-5C6A-4E2C-9F1E-2F1D6C0B7A10' = id",
+			"SELECT name FROM devices WHERE id = CAST('19bb3a41-5c6a-4e2c-9f1e-2f1d6c0b7a10' AS UUID)",
+			"SELECT name FROM devices WHERE name <> 'probe' AND id <= '19bb3a41-5c6a-4e2c-9f1e-2f1d6c0b7a10'",
+		} {
+			rows, err := engine.queryAll(context.Background(), nil, sql, nil)
+			require.NoError(t, err, sql)
+			require.Len(t, rows, 1, sql)
+			require.Equal(t, "sensor", rows[0].ValuesByPosition[0].RawValue(), sql)
+		}
+	})
+
+	t.Run("uuids are compared in joins", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT d.name, r.val FROM readings AS r INNER JOIN devices AS d ON d.id = r.device", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, "sensor", rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, int64(1), rows[0].ValuesByPosition[1].RawValue())
+	})
+
+	t.Run("malformed uuids are rejected", func(t *testing.T) {
+		for _, sql := range []string{
+			"INSERT INTO devices (id, name) VALUES ('not-a-uuid', 'x')",
+			"INSERT INTO devices (id, name) VALUES ('19bb3a41-5c6a-4e2c-9f1e-2f1d6c0b7a1', 'x')",
+			"INSERT INTO readings (device, val) VALUES ('19bb3a41-5c6a-4e2c-9f1e-2f1d6c0b7a1z', 1)",
+			"INSERT INTO readings (device, val) VALUES (CAST('19bb3a41' AS UUID), 1)",
+		} {
+			_, _, err := engine.Exec(context.Background(), nil, sql, nil)
+			require.ErrorIs(t, err, ErrInvalidValue, sql)
+		}
+
+		_, err := engine.queryAll(context.Background(), nil, "SELECT name FROM devices WHERE name = 'sensor' AND id = 'bad'", nil)
+		require.ErrorIs(t, err, ErrInvalidValue)
+	})
+}
+
 func TestFloatType(t *testing.T) {
 	engine := setupCommonTest(t)
 
@@ -825,6 +907,9 @@ func TestFloatIndexOnNegatives(t *testing.T) {
 		nil)
 	require.NoError(t, err)
 
+	// floats are ordered as indexes created by previous releases encode them
+	withLegacyKeys(t, engine, "float_index", "ft")
+
 	var z float64
 	floatSerie := []float64{
 		z,      /*0*/
@@ -1612,6 +1697,152 @@ func TestCreateIndex(t *testing.T) {
 	require.NoError(t, err)
 }
 
+func TestUniqueIndexNulls(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, `
+		CREATE TABLE accounts (id INTEGER AUTO_INCREMENT, email VARCHAR[64], phone VARCHAR[16], country VARCHAR[2], PRIMARY KEY id);
+		CREATE UNIQUE INDEX ON accounts (email);
+		CREATE UNIQUE INDEX ON accounts (phone) NULLS NOT DISTINCT;
+		CREATE UNIQUE INDEX ON accounts (country, email) NULLS DISTINCT;
+	`, nil)
+	require.NoError(t, err)
+
+	catalog, err := engine.Catalog(context.Background(), nil)
+	require.NoError(t, err)
+
+	table, err := catalog.GetTableByName("accounts")
+	require.NoError(t, err)
+	require.False(t, table.PrimaryIndex().NullsDistinct())
+
+	for name, nullsDistinct := range map[string]bool{
+		"accounts(email)":         true,
+		"accounts(phone)":         false,
+		"accounts(country,email)": true,
+	} {
+		index, err := table.GetIndexByName(name)
+		require.NoError(t, err)
+		require.Equal(t, nullsDistinct, index.NullsDistinct(), name)
+	}
+
+	t.Run("NULL values are distinct by default", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, `
+			INSERT INTO accounts (email, phone) VALUES (NULL, '555-0001');
+			INSERT INTO accounts (email, phone) VALUES (NULL, '555-0002');
+			INSERT INTO accounts (phone) VALUES ('555-0003');
+		`, nil)
+		require.NoError(t, err)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT id FROM accounts WHERE email IS NULL", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO accounts (email, phone) VALUES ('a@example.com', '555-0004')", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO accounts (email, phone) VALUES ('a@example.com', '555-0005')", nil)
+		require.ErrorIs(t, err, store.ErrKeyAlreadyExists)
+
+		// updating a row to a NULL value does not conflict with the other rows
+		_, _, err = engine.Exec(context.Background(), nil, "UPDATE accounts SET email = NULL WHERE phone = '555-0004'", nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("a second NULL value is rejected when NULL values are not distinct", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "INSERT INTO accounts (email, phone) VALUES ('b@example.com', NULL)", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO accounts (email, phone) VALUES ('c@example.com', NULL)", nil)
+		require.ErrorIs(t, err, store.ErrKeyAlreadyExists)
+
+		_, _, err = engine.Exec(context.Background(), nil, "UPDATE accounts SET phone = NULL WHERE phone = '555-0001'", nil)
+		require.ErrorIs(t, err, store.ErrKeyAlreadyExists)
+
+		// once the NULL value is gone, another row can take it
+		_, _, err = engine.Exec(context.Background(), nil, "DELETE FROM accounts WHERE email = 'b@example.com'", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO accounts (email, phone) VALUES ('c@example.com', NULL)", nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("composite unique indexes with partial NULL values", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, `
+			CREATE TABLE members (id INTEGER AUTO_INCREMENT, team VARCHAR[16], nick VARCHAR[16], PRIMARY KEY id);
+			CREATE UNIQUE INDEX ON members (team, nick);
+			CREATE TABLE players (id INTEGER AUTO_INCREMENT, team VARCHAR[16], nick VARCHAR[16], PRIMARY KEY id);
+			CREATE UNIQUE INDEX ON players (team, nick) NULLS NOT DISTINCT;
+		`, nil)
+		require.NoError(t, err)
+
+		for _, sql := range []string{
+			"INSERT INTO %s (team, nick) VALUES ('red', 'ace')",
+			"INSERT INTO %s (team, nick) VALUES ('red', NULL)",
+			"INSERT INTO %s (team, nick) VALUES (NULL, 'ace')",
+			"INSERT INTO %s (team, nick) VALUES (NULL, NULL)",
+			"INSERT INTO %s (team, nick) VALUES ('blue', NULL)",
+		} {
+			_, _, err = engine.Exec(context.Background(), nil, fmt.Sprintf(sql, "members"), nil)
+			require.NoError(t, err)
+
+			_, _, err = engine.Exec(context.Background(), nil, fmt.Sprintf(sql, "players"), nil)
+			require.NoError(t, err)
+		}
+
+		// rows equal in the non-NULL values are only rejected when NULL values are not distinct
+		for _, sql := range []string{
+			"INSERT INTO %s (team, nick) VALUES ('red', NULL)",
+			"INSERT INTO %s (team, nick) VALUES (NULL, 'ace')",
+			"INSERT INTO %s (team, nick) VALUES (NULL, NULL)",
+		} {
+			_, _, err = engine.Exec(context.Background(), nil, fmt.Sprintf(sql, "members"), nil)
+			require.NoError(t, err)
+
+			_, _, err = engine.Exec(context.Background(), nil, fmt.Sprintf(sql, "players"), nil)
+			require.ErrorIs(t, err, store.ErrKeyAlreadyExists)
+		}
+
+		// rows without NULL values are always unique
+		for _, table := range []string{"members", "players"} {
+			_, _, err = engine.Exec(context.Background(), nil, fmt.Sprintf("INSERT INTO %s (team, nick) VALUES ('red', 'ace')", table), nil)
+			require.ErrorIs(t, err, store.ErrKeyAlreadyExists)
+		}
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT id FROM members WHERE team = 'red' AND nick IS NULL", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT id FROM players WHERE team = 'red' AND nick IS NULL", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+	})
+
+	t.Run("validating existing indexes", func(t *testing.T) {
+		st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+		require.NoError(t, err)
+		defer closeStore(t, st)
+
+		engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix).WithIfNotExistsValidation(true))
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, `
+			CREATE TABLE accounts (id INTEGER AUTO_INCREMENT, email VARCHAR[64], phone VARCHAR[16], PRIMARY KEY id);
+			CREATE UNIQUE INDEX ON accounts (email);
+			CREATE UNIQUE INDEX ON accounts (phone) NULLS NOT DISTINCT;
+		`, nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, `
+			CREATE UNIQUE INDEX IF NOT EXISTS ON accounts (email) NULLS DISTINCT;
+			CREATE UNIQUE INDEX IF NOT EXISTS ON accounts (phone) NULLS NOT DISTINCT;
+		`, nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE UNIQUE INDEX IF NOT EXISTS ON accounts (email) NULLS NOT DISTINCT", nil)
+		require.ErrorIs(t, err, ErrSchemaMismatch)
+	})
+}
+
 func TestUpsertInto(t *testing.T) {
 	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
 	require.NoError(t, err)
@@ -3202,6 +3433,196 @@ func TestQuery(t *testing.T) {
 	})
 }
 
+func TestValuesTableConstructor(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, `
+		CREATE TABLE people (id INTEGER, name VARCHAR, PRIMARY KEY id);
+		INSERT INTO people (id, name) VALUES (1, 'alice'), (2, 'bob'), (3, 'carol');
+	`, nil)
+	require.NoError(t, err)
+
+	rawValues := func(t *testing.T, sql string, params map[string]interface{}) [][]interface{} {
+		rows, err := engine.queryAll(context.Background(), nil, sql, params)
+		require.NoError(t, err)
+
+		values := make([][]interface{}, len(rows))
+		for i, row := range rows {
+			for _, v := range row.ValuesByPosition {
+				values[i] = append(values[i], v.RawValue())
+			}
+		}
+		return values
+	}
+
+	columns := func(t *testing.T, sql string) []ColDescriptor {
+		r, err := engine.Query(context.Background(), nil, sql, nil)
+		require.NoError(t, err)
+		defer r.Close()
+
+		cols, err := r.Columns(context.Background())
+		require.NoError(t, err)
+		return cols
+	}
+
+	t.Run("standalone VALUES", func(t *testing.T) {
+		require.Equal(t, [][]interface{}{{int64(1)}, {int64(2)}, {int64(3)}}, rawValues(t, "VALUES (1), (2), (3)", nil))
+
+		require.Equal(t,
+			[][]interface{}{{int64(1), "a"}, {int64(2), "b"}},
+			rawValues(t, "VALUES (1, 'a'), (@id, @name)", map[string]interface{}{"id": 2, "name": "b"}),
+		)
+
+		cols := columns(t, "VALUES (1, 'a')")
+		require.Equal(t, "col0", cols[0].Column)
+		require.Equal(t, IntegerType, cols[0].Type)
+		require.Equal(t, "col1", cols[1].Column)
+		require.Equal(t, VarcharType, cols[1].Type)
+	})
+
+	t.Run("VALUES in FROM with table and column aliases", func(t *testing.T) {
+		q := "SELECT t.name, id FROM (VALUES (1, 'a'), (2, 'b')) AS t(id, name) WHERE t.id > 1"
+		require.Equal(t, [][]interface{}{{"b", int64(2)}}, rawValues(t, q, nil))
+
+		cols := columns(t, "SELECT * FROM (VALUES (1, 'a')) t(id, name)")
+		require.Equal(t, "t", cols[0].Table)
+		require.Equal(t, "id", cols[0].Column)
+		require.Equal(t, "name", cols[1].Column)
+
+		// columns keep their default names when only the table is named
+		require.Equal(t, [][]interface{}{{int64(1)}}, rawValues(t, "SELECT v.col0 FROM (VALUES (1)) AS v", nil))
+	})
+
+	t.Run("VALUES joined to a table", func(t *testing.T) {
+		q := `
+			SELECT people.name, r.role
+			FROM people
+			INNER JOIN (VALUES (1, 'admin'), (3, 'viewer'), (4, 'nobody')) AS r(id, role) ON r.id = people.id
+			ORDER BY people.id DESC`
+
+		require.Equal(t, [][]interface{}{{"carol", "viewer"}, {"alice", "admin"}}, rawValues(t, q, nil))
+
+		q = `
+			SELECT people.name
+			FROM (VALUES ('carol'), ('alice')) AS wanted(name)
+			INNER JOIN people ON people.name = wanted.name
+			ORDER BY people.name`
+
+		require.Equal(t, [][]interface{}{{"alice"}, {"carol"}}, rawValues(t, q, nil))
+	})
+
+	t.Run("type inference across rows", func(t *testing.T) {
+		q := "SELECT * FROM (VALUES (1, NULL), (2.5, 'x'), (NULL, 'y')) AS t(n, s)"
+
+		cols := columns(t, q)
+		require.Equal(t, Float64Type, cols[0].Type)
+		require.Equal(t, VarcharType, cols[1].Type)
+
+		rows, err := engine.queryAll(context.Background(), nil, q, nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+
+		// integers are converted to the common type of the column
+		require.Equal(t, Float64Type, rows[0].ValuesByPosition[0].Type())
+		require.Equal(t, 1.0, rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, 2.5, rows[1].ValuesByPosition[0].RawValue())
+		require.True(t, rows[2].ValuesByPosition[0].IsNull())
+		require.True(t, rows[0].ValuesByPosition[1].IsNull())
+
+		require.Equal(t, [][]interface{}{{4.5}}, rawValues(t, "SELECT SUM(n) FROM (VALUES (1), (@f), (2)) AS t(n)", map[string]interface{}{"f": 1.5}))
+
+		_, err = engine.queryAll(context.Background(), nil, "VALUES (1), ('a')", nil)
+		require.ErrorContains(t, err, "cannot match types INTEGER and VARCHAR")
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT * FROM (VALUES (1.5), (true))", nil)
+		require.ErrorContains(t, err, "cannot match types FLOAT and BOOLEAN")
+	})
+
+	t.Run("invalid column names", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, "SELECT * FROM (VALUES (1, 2)) AS t(a)", nil)
+		require.ErrorIs(t, err, ErrInvalidNumberOfValues)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT * FROM (VALUES (1, 2)) AS t(a, a)", nil)
+		require.ErrorIs(t, err, ErrDuplicatedColumn)
+
+		_, err = engine.queryAll(context.Background(), nil, "VALUES (1, 2), (3)", nil)
+		require.ErrorIs(t, err, ErrInvalidNumberOfValues)
+	})
+}
+
+func TestSelectWithoutFrom(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	query := func(t *testing.T, sql string, params map[string]interface{}) []*Row {
+		rows, err := engine.queryAll(context.Background(), nil, sql, params)
+		require.NoError(t, err)
+		return rows
+	}
+
+	t.Run("arithmetic expressions", func(t *testing.T) {
+		r, err := engine.Query(context.Background(), nil, "SELECT 1 + 1 AS two, 7 % 3, 2.5 * 2 AS five", nil)
+		require.NoError(t, err)
+
+		cols, err := r.Columns(context.Background())
+		require.NoError(t, err)
+		require.Len(t, cols, 3)
+		require.Equal(t, "two", cols[0].Column)
+		require.Equal(t, IntegerType, cols[0].Type)
+		require.Equal(t, Float64Type, cols[2].Type)
+
+		rows, err := ReadAllRows(context.Background(), r)
+		require.NoError(t, err)
+		require.NoError(t, r.Close())
+		require.Len(t, rows, 1)
+		require.Equal(t, int64(2), rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, int64(1), rows[0].ValuesByPosition[1].RawValue())
+		require.Equal(t, 5.0, rows[0].ValuesByPosition[2].RawValue())
+	})
+
+	t.Run("function calls", func(t *testing.T) {
+		rows := query(t, "SELECT NOW() AS t, UPPER('immudb') AS name, LENGTH('abc')", nil)
+		require.Len(t, rows, 1)
+		require.IsType(t, time.Time{}, rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, "IMMUDB", rows[0].ValuesByPosition[1].RawValue())
+		require.Equal(t, int64(3), rows[0].ValuesByPosition[2].RawValue())
+
+		// aggregations are computed over the single row
+		rows = query(t, "SELECT COUNT(*)", nil)
+		require.Len(t, rows, 1)
+		require.Equal(t, int64(1), rows[0].ValuesByPosition[0].RawValue())
+	})
+
+	t.Run("parameterized expressions", func(t *testing.T) {
+		rows := query(t, "SELECT @x * 2 AS y, UPPER(@s) AS greeting", map[string]interface{}{"x": 21, "s": "hi"})
+		require.Len(t, rows, 1)
+		require.Equal(t, int64(42), rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, "HI", rows[0].ValuesByPosition[1].RawValue())
+	})
+
+	t.Run("filtering and limiting the single row", func(t *testing.T) {
+		require.Len(t, query(t, "SELECT 1 AS one WHERE @p > 0", map[string]interface{}{"p": 1}), 1)
+		require.Empty(t, query(t, "SELECT 1 AS one WHERE @p > 0", map[string]interface{}{"p": 0}))
+		require.Len(t, query(t, "SELECT 1 AS one LIMIT 5", nil), 1)
+		require.Empty(t, query(t, "SELECT 1 AS one LIMIT 1 OFFSET 1", nil))
+	})
+
+	t.Run("in subqueries and set operations", func(t *testing.T) {
+		rows := query(t, "SELECT two + 1 FROM (SELECT 1 + 1 AS two)", nil)
+		require.Len(t, rows, 1)
+		require.Equal(t, int64(3), rows[0].ValuesByPosition[0].RawValue())
+
+		require.Len(t, query(t, "SELECT 1 UNION ALL SELECT 2 WHERE false UNION ALL SELECT 3", nil), 2)
+	})
+
+	t.Run("invalid queries", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, "SELECT 1 ORDER BY 1", nil)
+		require.ErrorIs(t, err, ErrParsingError)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT 1 WHERE id > 0", nil)
+		require.ErrorIs(t, err, ErrColumnDoesNotExist)
+	})
+}
+
 func TestExtractFromTimestamp(t *testing.T) {
 	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
 	require.NoError(t, err)
@@ -3860,7 +4281,8 @@ func TestQueryDistinct(t *testing.T) {
 	})
 
 	t.Run("should return too many rows error", func(t *testing.T) {
-		r, err := engine.Query(context.Background(), nil, "SELECT DISTINCT id FROM table1", nil)
+		// rows sorted by the selected columns are deduplicated without keeping track of them
+		r, err := engine.Query(context.Background(), nil, "SELECT DISTINCT id FROM table1 ORDER BY title", nil)
 		require.NoError(t, err)
 
 		cols, err := r.Columns(context.Background())
@@ -5225,6 +5647,86 @@ func TestQueryWithInClause(t *testing.T) {
 	})
 }
 
+func TestQueryWithInListParameter(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, "CREATE TABLE table1 (id INTEGER, title VARCHAR[50], data BLOB, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO table1 (id, title, data) VALUES (@id, @title, @data)", map[string]interface{}{
+			"id":    i,
+			"title": fmt.Sprintf("title%d", i),
+			"data":  []byte{byte(i)},
+		})
+		require.NoError(t, err)
+	}
+
+	queryIDs := func(t *testing.T, ctx context.Context, sql string, params map[string]interface{}) []int64 {
+		rows, err := engine.queryAll(ctx, nil, sql, params)
+		require.NoError(t, err)
+
+		ids := make([]int64, len(rows))
+		for i, row := range rows {
+			ids[i] = row.ValuesByPosition[0].RawValue().(int64)
+		}
+		return ids
+	}
+
+	t.Run("slice of integers", func(t *testing.T) {
+		require.Equal(t, []int64{1, 3, 8}, queryIDs(t, context.Background(), "SELECT id FROM table1 WHERE id IN (@ids)", map[string]interface{}{"ids": []int64{8, 1, 3}}))
+		require.Equal(t, []int64{2, 7}, queryIDs(t, context.Background(), "SELECT id FROM table1 WHERE id IN (@ids)", map[string]interface{}{"ids": []int{7, 2, 42}}))
+		require.Equal(t, []int64{4, 5, 6}, queryIDs(t, context.Background(), "SELECT id FROM table1 WHERE id IN (@ids, 6)", map[string]interface{}{"ids": []interface{}{4, int64(5)}}))
+		require.Equal(t, []int64{0, 2, 4, 5, 6, 9}, queryIDs(t, context.Background(), "SELECT id FROM table1 WHERE id NOT IN (@ids)", map[string]interface{}{"ids": []uint64{1, 3, 7, 8}}))
+
+		// only the rows with the given ids are read
+		ctx := ContextWithResourceLimits(context.Background(), ResourceLimits{MaxRowsScanned: 2})
+		require.Equal(t, []int64{2, 9}, queryIDs(t, ctx, "SELECT id FROM table1 WHERE id IN (@ids)", map[string]interface{}{"ids": []int64{9, 2}}))
+	})
+
+	t.Run("empty slice", func(t *testing.T) {
+		require.Empty(t, queryIDs(t, context.Background(), "SELECT id FROM table1 WHERE id IN (@ids)", map[string]interface{}{"ids": []int64{}}))
+		require.Empty(t, queryIDs(t, context.Background(), "SELECT id FROM table1 WHERE title IN (@ids)", map[string]interface{}{"ids": []string(nil)}))
+		require.Len(t, queryIDs(t, context.Background(), "SELECT id FROM table1 WHERE id NOT IN (@ids)", map[string]interface{}{"ids": []int64{}}), 10)
+	})
+
+	t.Run("slice of strings", func(t *testing.T) {
+		require.Equal(t, []int64{0, 5}, queryIDs(t, context.Background(), "SELECT id FROM table1 WHERE title IN (@titles)", map[string]interface{}{"titles": []string{"title5", "title0", "title"}}))
+
+		_, err := engine.queryAll(context.Background(), nil, "SELECT id FROM table1 WHERE id IN (@ids)", map[string]interface{}{"ids": []string{"title1"}})
+		require.ErrorIs(t, err, ErrNotComparableValues)
+	})
+
+	t.Run("byte slices are not expanded", func(t *testing.T) {
+		require.Equal(t, []int64{3}, queryIDs(t, context.Background(), "SELECT id FROM table1 WHERE data IN (@data)", map[string]interface{}{"data": []byte{3}}))
+		require.Equal(t, []int64{1, 2}, queryIDs(t, context.Background(), "SELECT id FROM table1 WHERE data IN (@data)", map[string]interface{}{"data": [][]byte{{1}, {2}}}))
+	})
+
+	t.Run("statements modifying rows", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "UPDATE table1 SET title = 'updated' WHERE id IN (@ids)", map[string]interface{}{"ids": []int{1, 2}})
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "DELETE FROM table1 WHERE title IN (@titles)", map[string]interface{}{"titles": []string{"updated", "title9"}})
+		require.NoError(t, err)
+		require.Equal(t, []int64{0, 3, 4, 5, 6, 7, 8}, queryIDs(t, context.Background(), "SELECT id FROM table1", nil))
+	})
+
+	t.Run("unsupported values", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, "SELECT id FROM table1 WHERE id IN (@ids)", map[string]interface{}{"ids": []struct{}{{}}})
+		require.ErrorIs(t, err, ErrUnsupportedParameter)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT id FROM table1 WHERE id IN (@ids)", map[string]interface{}{"ids": [][]int{{1}}})
+		require.ErrorIs(t, err, ErrUnsupportedParameter)
+
+		// slices are only expanded within IN lists
+		_, err = engine.queryAll(context.Background(), nil, "SELECT id FROM table1 WHERE id = @ids", map[string]interface{}{"ids": []int{1}})
+		require.ErrorIs(t, err, ErrUnsupportedParameter)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT id FROM table1 WHERE id IN (@ids)", nil)
+		require.ErrorIs(t, err, ErrMissingParameter)
+	})
+}
+
 func TestAggregations(t *testing.T) {
 	engine := setupCommonTest(t)
 
@@ -6915,11 +7417,13 @@ func TestUnmapIndex(t *testing.T) {
 func TestUnmapIndexEntry(t *testing.T) {
 	e := Engine{prefix: []byte("e-prefix.")}
 
-	encPKVals, err := unmapIndexEntry(&Index{id: PKIndexID, unique: true}, e.prefix, nil)
+	pkIndex := &Index{table: &Table{id: 1}, id: PKIndexID, unique: true}
+
+	encPKVals, err := unmapIndexEntry(pkIndex, e.prefix, nil)
 	require.ErrorIs(t, err, ErrCorruptedData)
 	require.Nil(t, encPKVals)
 
-	encPKVals, err = unmapIndexEntry(&Index{id: PKIndexID, unique: true}, e.prefix, []byte(
+	encPKVals, err = unmapIndexEntry(pkIndex, e.prefix, []byte(
 		"e-prefix.M.\x80a",
 	))
 	require.ErrorIs(t, err, ErrCorruptedData)
@@ -7012,24 +7516,243 @@ func TestEncodeAsKeyEdgeCases(t *testing.T) {
 	})
 }
 
-func TestIndexingNullableColumns(t *testing.T) {
+func TestIndexScanOrderOfEqualValues(t *testing.T) {
 	engine := setupCommonTest(t)
 
-	exec := func(t *testing.T, stmt string) *SQLTx {
-		ret, _, err := engine.Exec(context.Background(), nil, stmt, nil)
-		require.NoError(t, err)
-		return ret
+	_, _, err := engine.Exec(context.Background(), nil, `
+		CREATE TABLE t (id INTEGER, region VARCHAR[8], category VARCHAR[8], PRIMARY KEY (region, id));
+		CREATE INDEX ON t (category);
+	`, nil)
+	require.NoError(t, err)
+
+	type pk struct {
+		region string
+		id     int64
 	}
 
-	query := func(t *testing.T, stmt string, expectedRows ...*Row) {
-		reader, err := engine.Query(context.Background(), nil, stmt, nil)
-		require.NoError(t, err)
+	var pks []pk
+	for _, region := range []string{"", "b", "ab", "a"} {
+		for _, id := range []int64{-5, 0, 3, 12, 100, 7} {
+			pks = append(pks, pk{region: region, id: id})
+		}
+	}
 
-		for _, expectedRow := range expectedRows {
-			row, err := reader.Read(context.Background())
-			require.NoError(t, err)
+	rand.New(rand.NewSource(1)).Shuffle(len(pks), func(i, j int) { pks[i], pks[j] = pks[j], pks[i] })
 
-			require.EqualValues(t, expectedRow, row)
+	for i, pk := range pks {
+		category := "x"
+		if i%3 == 0 {
+			category = "y"
+		}
+
+		params := map[string]interface{}{"id": pk.id, "region": pk.region, "category": category}
+		if i%5 == 0 {
+			params["category"] = nil
+		}
+
+		_, _, err := engine.Exec(context.Background(), nil, "INSERT INTO t (id, region, category) VALUES (@id, @region, @category)", params)
+		require.NoError(t, err)
+	}
+
+	// the rows of each category sorted by primary key
+	sorted := func(category interface{}) []pk {
+		var res []pk
+
+		for i, pk := range pks {
+			c := interface{}("x")
+			if i%3 == 0 {
+				c = "y"
+			}
+			if i%5 == 0 {
+				c = nil
+			}
+
+			if c == category {
+				res = append(res, pk)
+			}
+		}
+
+		sort.Slice(res, func(i, j int) bool {
+			if res[i].region != res[j].region {
+				return res[i].region < res[j].region
+			}
+			return res[i].id < res[j].id
+		})
+		return res
+	}
+
+	scan := func(t *testing.T, sql string) []pk {
+		rows, err := engine.queryAll(context.Background(), nil, sql, nil)
+		require.NoError(t, err)
+
+		res := make([]pk, len(rows))
+		for i, row := range rows {
+			res[i] = pk{region: row.ValuesByPosition[0].RawValue().(string), id: row.ValuesByPosition[1].RawValue().(int64)}
+		}
+		return res
+	}
+
+	all := append(append(sorted(nil), sorted("x")...), sorted("y")...)
+	require.Len(t, all, len(pks))
+
+	reversed := slices.Clone(all)
+	slices.Reverse(reversed)
+
+	for _, d := range []struct {
+		sql      string
+		expected []pk
+	}{
+		{"SELECT region, id FROM t USE INDEX ON (category) WHERE category = 'x'", sorted("x")},
+		{"SELECT region, id FROM t WHERE category = 'y' ORDER BY category", sorted("y")},
+		{"SELECT region, id FROM t WHERE category IS NULL ORDER BY category", sorted(nil)},
+		{"SELECT region, id FROM t USE INDEX ON (category)", all},
+		{"SELECT region, id FROM t ORDER BY category DESC", reversed},
+	} {
+		t.Run(d.sql, func(t *testing.T) {
+			for i := 0; i < 3; i++ {
+				require.Equal(t, d.expected, scan(t, d.sql))
+			}
+		})
+	}
+}
+
+func TestBlobComparison(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, `
+		CREATE TABLE files (id INTEGER, hash BLOB[4], PRIMARY KEY id);
+		CREATE INDEX ON files (hash);
+		CREATE TABLE signatures (hash BLOB[4], signer VARCHAR, PRIMARY KEY hash);
+	`, nil)
+	require.NoError(t, err)
+
+	// values sharing prefixes, with trailing zeros and of different lengths
+	hashes := [][]byte{
+		{0xff, 0x00}, {0x00}, {}, {0x01}, {0x00, 0xff}, {0xff}, {0x00, 0x00}, {0x7f, 0xff, 0xff, 0xff},
+	}
+
+	for i, h := range hashes {
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO files (id, hash) VALUES (@id, @hash)", map[string]interface{}{"id": i, "hash": h})
+		require.NoError(t, err)
+	}
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		INSERT INTO files (id, hash) VALUES (100, NULL);
+		INSERT INTO signatures (hash, signer) VALUES (x'00ff', 'alice'), (x'ff', 'bob'), (x'0000', 'carol'), (x'00ff00', 'dave');
+	`, nil)
+	require.NoError(t, err)
+
+	sorted := make([][]byte, len(hashes))
+	copy(sorted, hashes)
+	slices.SortFunc(sorted, bytes.Compare)
+
+	queryHashes := func(t *testing.T, sql string, params map[string]interface{}) [][]byte {
+		rows, err := engine.queryAll(context.Background(), nil, sql, params)
+		require.NoError(t, err)
+
+		res := make([][]byte, len(rows))
+		for i, row := range rows {
+			res[i] = row.ValuesByPosition[0].RawValue().([]byte)
+		}
+		return res
+	}
+
+	t.Run("equality", func(t *testing.T) {
+		for _, h := range hashes {
+			require.Equal(t, [][]byte{h}, queryHashes(t, "SELECT hash FROM files WHERE hash = @hash", map[string]interface{}{"hash": h}))
+			require.Equal(t, [][]byte{h}, queryHashes(t, "SELECT hash FROM files USE INDEX ON (id) WHERE hash = @hash", map[string]interface{}{"hash": h}))
+			require.Len(t, queryHashes(t, "SELECT hash FROM files WHERE hash <> @hash AND hash IS NOT NULL", map[string]interface{}{"hash": h}), len(hashes)-1)
+		}
+
+		require.Empty(t, queryHashes(t, "SELECT hash FROM files WHERE hash = x'0000ff'", nil))
+	})
+
+	t.Run("ordering", func(t *testing.T) {
+		// rows are sorted in memory when not read through the index
+		require.Equal(t, sorted, queryHashes(t, "SELECT hash FROM files USE INDEX ON (id) WHERE hash IS NOT NULL ORDER BY hash", nil))
+		require.Equal(t, sorted, queryHashes(t, "SELECT hash FROM files USE INDEX ON (hash) WHERE hash IS NOT NULL ORDER BY hash", nil))
+
+		desc := slices.Clone(sorted)
+		slices.Reverse(desc)
+
+		require.Equal(t, desc, queryHashes(t, "SELECT hash FROM files USE INDEX ON (id) WHERE hash IS NOT NULL ORDER BY hash DESC", nil))
+		require.Equal(t, desc, queryHashes(t, "SELECT hash FROM files USE INDEX ON (hash) WHERE hash IS NOT NULL ORDER BY hash DESC", nil))
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT hash FROM files ORDER BY hash", nil)
+		require.NoError(t, err)
+		require.True(t, rows[0].ValuesByPosition[0].IsNull())
+	})
+
+	t.Run("index range scans in the order of the comparison", func(t *testing.T) {
+		for _, bound := range hashes {
+			for _, op := range []string{"<", "<=", ">", ">="} {
+				var expected [][]byte
+				for _, h := range sorted {
+					c := bytes.Compare(h, bound)
+					if (op == "<" && c < 0) || (op == "<=" && c <= 0) || (op == ">" && c > 0) || (op == ">=" && c >= 0) {
+						expected = append(expected, h)
+					}
+				}
+
+				params := map[string]interface{}{"bound": bound}
+
+				got := queryHashes(t, "SELECT hash FROM files USE INDEX ON (hash) WHERE hash "+op+" @bound AND hash IS NOT NULL", params)
+				require.Equal(t, len(expected), len(got), "hash %s %x", op, bound)
+				if len(expected) > 0 {
+					require.Equal(t, expected, got, "hash %s %x", op, bound)
+				}
+
+				scanned := queryHashes(t, "SELECT hash FROM files USE INDEX ON (id) WHERE hash "+op+" @bound AND hash IS NOT NULL ORDER BY hash", params)
+				require.Equal(t, got, scanned, "hash %s %x", op, bound)
+			}
+		}
+
+		require.Equal(t,
+			[][]byte{{0x00, 0x00}, {0x00, 0xff}, {0x01}},
+			queryHashes(t, "SELECT hash FROM files WHERE hash > x'00' AND hash < x'7f' ORDER BY hash", nil),
+		)
+	})
+
+	t.Run("joins on blob columns", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, `
+			SELECT f.id, s.signer
+			FROM files AS f
+			INNER JOIN signatures AS s ON f.hash = s.hash
+			ORDER BY s.signer`, nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+
+		var signers []string
+		for _, row := range rows {
+			signers = append(signers, row.ValuesByPosition[1].RawValue().(string))
+		}
+		require.Equal(t, []string{"alice", "bob", "carol"}, signers)
+	})
+
+	t.Run("blobs are not comparable with other types", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, "SELECT hash FROM files USE INDEX ON (id) WHERE hash > 1", nil)
+		require.ErrorIs(t, err, ErrNotComparableValues)
+	})
+}
+
+func TestIndexingNullableColumns(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	exec := func(t *testing.T, stmt string) *SQLTx {
+		ret, _, err := engine.Exec(context.Background(), nil, stmt, nil)
+		require.NoError(t, err)
+		return ret
+	}
+
+	query := func(t *testing.T, stmt string, expectedRows ...*Row) {
+		reader, err := engine.Query(context.Background(), nil, stmt, nil)
+		require.NoError(t, err)
+
+		for _, expectedRow := range expectedRows {
+			row, err := reader.Read(context.Background())
+			require.NoError(t, err)
+
+			require.EqualValues(t, expectedRow, row)
 		}
 
 		_, err = reader.Read(context.Background())
@@ -7194,7 +7917,7 @@ func TestIndexingNullableColumns(t *testing.T) {
 		`)
 
 		exec(t, "CREATE INDEX ON table2(v1, v2)")
-		exec(t, "CREATE UNIQUE INDEX ON table2(v3, v4)")
+		exec(t, "CREATE UNIQUE INDEX ON table2(v3, v4) NULLS NOT DISTINCT")
 
 		query(t, "SELECT * FROM table2 USE INDEX ON(v3,v4)")
 
@@ -9692,6 +10415,118 @@ func TestFunctions(t *testing.T) {
 		})
 	})
 
+	t.Run("hash functions", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, "SELECT MD5(1) FROM mytable", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT SHA256('a', 'b') FROM mytable", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT MD5(NULL), SHA256(NULL), MD5_RAW(NULL), SHA256_RAW(NULL) FROM mytable", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+
+		for _, v := range rows[0].ValuesByPosition {
+			require.True(t, v.IsNull())
+		}
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT MD5(''), SHA256(''), MD5('abc'), SHA256('abc'), SHA256(x'616263'), SHA256_RAW('abc') FROM mytable", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+
+		require.Equal(t, "d41d8cd98f00b204e9800998ecf8427e", rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855", rows[0].ValuesByPosition[1].RawValue())
+		require.Equal(t, "900150983cd24fb0d6963f7d28e17f72", rows[0].ValuesByPosition[2].RawValue())
+		require.Equal(t, "ba7816bf8f01cfea414140de5dae2223b00361a396177a9cb410ff61f20015ad", rows[0].ValuesByPosition[3].RawValue())
+
+		// strings and blobs with the same bytes have the same digest
+		require.Equal(t, rows[0].ValuesByPosition[3].RawValue(), rows[0].ValuesByPosition[4].RawValue())
+
+		rawDigest, err := hex.DecodeString(rows[0].ValuesByPosition[3].RawValue().(string))
+		require.NoError(t, err)
+		require.Equal(t, rawDigest, rows[0].ValuesByPosition[5].RawValue())
+
+		// digests are deterministic, so identical contents can be found by their digest
+		_, _, err = engine.Exec(context.Background(), nil, `
+			CREATE TABLE documents (
+				id INTEGER AUTO_INCREMENT,
+				content VARCHAR,
+				digest VARCHAR[64] GENERATED ALWAYS AS (SHA256(content)) STORED,
+				PRIMARY KEY id
+			);
+			CREATE INDEX ON documents (digest);
+			INSERT INTO documents (content) VALUES ('immudb'), ('other'), ('immudb'), (NULL);
+		`, nil)
+		require.NoError(t, err)
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT digest, COUNT(*) AS copies FROM documents WHERE digest IS NOT NULL GROUP BY digest ORDER BY 2 DESC", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		require.Equal(t, int64(2), rows[0].ValuesByPosition[1].RawValue())
+		require.Equal(t, int64(1), rows[1].ValuesByPosition[1].RawValue())
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT id FROM documents WHERE digest = SHA256('immudb')", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		require.Equal(t, int64(1), rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, int64(3), rows[1].ValuesByPosition[0].RawValue())
+	})
+
+	t.Run("encoding functions", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, "SELECT ENCODE(x'00', 'base32') FROM mytable", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+		require.ErrorContains(t, err, "'ENCODE' function does not support the encoding 'base32'")
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT DECODE('AA==') FROM mytable", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT DECODE(x'00', 'hex') FROM mytable", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT DECODE('not base64!', 'base64') FROM mytable", nil)
+		require.ErrorIs(t, err, ErrInvalidValue)
+		require.ErrorContains(t, err, "'DECODE' function can not decode invalid base64 input")
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT DECODE('0g', 'hex') FROM mytable", nil)
+		require.ErrorIs(t, err, ErrInvalidValue)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT ENCODE(NULL, 'hex'), DECODE(NULL, 'base64'), ENCODE(x'00', NULL) FROM mytable", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.True(t, rows[0].ValuesByPosition[0].IsNull())
+		require.True(t, rows[0].ValuesByPosition[1].IsNull())
+		require.True(t, rows[0].ValuesByPosition[2].IsNull())
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT ENCODE(x'00ff10', 'base64'), ENCODE(x'00ff10', 'HEX'), ENCODE('immudb', 'base64'), DECODE('aW1tdWRi', 'Base64') FROM mytable", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, "AP8Q", rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, "00ff10", rows[0].ValuesByPosition[1].RawValue())
+		require.Equal(t, "aW1tdWRi", rows[0].ValuesByPosition[2].RawValue())
+		require.Equal(t, []byte("immudb"), rows[0].ValuesByPosition[3].RawValue())
+
+		_, _, err = engine.Exec(context.Background(), nil, `
+			CREATE TABLE blobs (id INTEGER AUTO_INCREMENT, data BLOB, PRIMARY KEY id);
+			INSERT INTO blobs (data) VALUES (x''), (x'00'), (x'00ff10fe'), (x'66616b65');
+		`, nil)
+		require.NoError(t, err)
+
+		for _, encoding := range []string{"base64", "hex"} {
+			rows, err := engine.queryAll(
+				context.Background(),
+				nil,
+				fmt.Sprintf("SELECT data, DECODE(ENCODE(data, '%[1]s'), '%[1]s') FROM blobs", encoding),
+				nil,
+			)
+			require.NoError(t, err)
+			require.Len(t, rows, 4)
+
+			for _, row := range rows {
+				require.Equal(t, row.ValuesByPosition[0].RawValue(), row.ValuesByPosition[1].RawValue())
+			}
+		}
+	})
+
 	t.Run("json functions", func(t *testing.T) {
 		_, err := engine.queryAll(context.Background(), nil, "SELECT JSON_TYPEOF(true) FROM mytable", nil)
 		require.ErrorIs(t, err, ErrIllegalArguments)
@@ -9786,3 +10621,3174 @@ func (r *mockTableResolver) Resolve(ctx context.Context, tx *SQLTx, alias string
 		r.values,
 	)
 }
+
+func TestReadOnlyEngine(t *testing.T) {
+	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(t, err)
+	defer closeStore(t, st)
+
+	engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE table1 (id INTEGER, val INTEGER, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	for i := 1; i <= 10; i++ {
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO table1 (id, val) VALUES (@id, @val)", map[string]interface{}{"id": i, "val": i * 100})
+		require.NoError(t, err)
+	}
+
+	engine, err = NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix).WithReadOnly(true))
+	require.NoError(t, err)
+	require.True(t, engine.ReadOnly())
+
+	t.Run("queries should succeed", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT id, val FROM table1 WHERE val > 500 ORDER BY id", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 5)
+		require.Equal(t, int64(6), rows[0].ValuesByPosition[0].RawValue())
+
+		_, _, err = engine.Exec(context.Background(), nil, "SELECT * FROM table1", nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("explicit transactions with queries should succeed", func(t *testing.T) {
+		tx, _, err := engine.Exec(context.Background(), nil, "BEGIN TRANSACTION", nil)
+		require.NoError(t, err)
+
+		rows, err := engine.queryAll(context.Background(), tx, "SELECT COUNT(*) FROM table1 WHERE id >= 3", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, int64(8), rows[0].ValuesByPosition[0].RawValue())
+
+		_, _, err = engine.Exec(context.Background(), tx, "COMMIT", nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("statements requiring write capability should be rejected", func(t *testing.T) {
+		for _, stmt := range []string{
+			"INSERT INTO table1 (id, val) VALUES (11, 1100)",
+			"UPSERT INTO table1 (id, val) VALUES (1, 1100)",
+			"UPDATE table1 SET val = 0 WHERE id = 1",
+			"DELETE FROM table1 WHERE id = 1",
+			"CREATE TABLE table2 (id INTEGER, PRIMARY KEY id)",
+			"CREATE INDEX ON table1 (val)",
+			"ALTER TABLE table1 ADD COLUMN title VARCHAR",
+			"DROP TABLE table1",
+			"BEGIN; INSERT INTO table1 (id, val) VALUES (11, 1100); COMMIT;",
+		} {
+			_, _, err := engine.Exec(context.Background(), nil, stmt, nil)
+			require.ErrorIs(t, err, ErrReadOnly, stmt)
+		}
+	})
+
+	rows, err := engine.queryAll(context.Background(), nil, "SELECT * FROM table1", nil)
+	require.NoError(t, err)
+	require.Len(t, rows, 10)
+}
+
+func TestRegisterFunction(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	manhattan := ScalarFunc{
+		ArgTypes:   []SQLValueType{IntegerType, IntegerType, IntegerType, IntegerType},
+		ReturnType: IntegerType,
+		Eval: func(args []TypedValue) (TypedValue, error) {
+			for _, arg := range args {
+				if arg.IsNull() {
+					return NewNull(IntegerType), nil
+				}
+			}
+
+			abs := func(v int64) int64 {
+				if v < 0 {
+					return -v
+				}
+				return v
+			}
+
+			x1, y1 := args[0].RawValue().(int64), args[1].RawValue().(int64)
+			x2, y2 := args[2].RawValue().(int64), args[3].RawValue().(int64)
+
+			return NewInteger(abs(x1-x2) + abs(y1-y2)), nil
+		},
+	}
+
+	t.Run("invalid registrations should fail", func(t *testing.T) {
+		err := engine.RegisterFunction("", manhattan)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		err = engine.RegisterFunction("fn", ScalarFunc{ReturnType: IntegerType})
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		err = engine.RegisterFunction("fn", ScalarFunc{ReturnType: "POINT", Eval: manhattan.Eval})
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		err = engine.RegisterFunction("fn", ScalarFunc{ArgTypes: []SQLValueType{"POINT"}, ReturnType: IntegerType, Eval: manhattan.Eval})
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		err = engine.RegisterFunction("length", manhattan)
+		require.ErrorIs(t, err, ErrFunctionAlreadyExists)
+	})
+
+	err := engine.RegisterFunction("manhattan", manhattan)
+	require.NoError(t, err)
+
+	err = engine.RegisterFunction("MANHATTAN", manhattan)
+	require.ErrorIs(t, err, ErrFunctionAlreadyExists)
+
+	err = engine.RegisterFunction("shout", ScalarFunc{
+		ArgTypes:   []SQLValueType{VarcharType},
+		ReturnType: VarcharType,
+		Eval: func(args []TypedValue) (TypedValue, error) {
+			if args[0].IsNull() {
+				return nil, nil
+			}
+			return NewVarchar(strings.ToUpper(args[0].RawValue().(string)) + "!"), nil
+		},
+	})
+	require.NoError(t, err)
+
+	err = engine.RegisterFunction("broken", ScalarFunc{
+		ReturnType: IntegerType,
+		Eval: func(args []TypedValue) (TypedValue, error) {
+			return NewVarchar("not an integer"), nil
+		},
+	})
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE places (id INTEGER AUTO_INCREMENT, name VARCHAR, x INTEGER, y INTEGER, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		INSERT INTO places (name, x, y) VALUES ('home', 0, 0), ('office', 3, 4), ('gym', -10, 2), ('unknown', NULL, NULL)
+	`, nil)
+	require.NoError(t, err)
+
+	t.Run("custom function in select", func(t *testing.T) {
+		reader, err := engine.Query(context.Background(), nil, "SELECT name, manhattan(x, y, 1, 1) AS dist, SHOUT(name) FROM places ORDER BY id", nil)
+		require.NoError(t, err)
+		defer reader.Close()
+
+		cols, err := reader.Columns(context.Background())
+		require.NoError(t, err)
+		require.Len(t, cols, 3)
+		require.Equal(t, IntegerType, cols[1].Type)
+		require.Equal(t, VarcharType, cols[2].Type)
+
+		rows, err := ReadAllRows(context.Background(), reader)
+		require.NoError(t, err)
+		require.Len(t, rows, 4)
+
+		require.Equal(t, int64(2), rows[0].ValuesByPosition[1].RawValue())
+		require.Equal(t, "HOME!", rows[0].ValuesByPosition[2].RawValue())
+		require.Equal(t, int64(5), rows[1].ValuesByPosition[1].RawValue())
+		require.Equal(t, int64(12), rows[2].ValuesByPosition[1].RawValue())
+		require.True(t, rows[3].ValuesByPosition[1].IsNull())
+	})
+
+	t.Run("custom function in where", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT name FROM places WHERE x IS NOT NULL AND manhattan(x, y, @x, @y) <= @dist ORDER BY id", map[string]interface{}{"x": 0, "y": 0, "dist": 7})
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		require.Equal(t, "home", rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, "office", rows[1].ValuesByPosition[0].RawValue())
+	})
+
+	t.Run("parameter types should be inferred from the function signature", func(t *testing.T) {
+		params, err := engine.InferParameters(context.Background(), nil, "SELECT shout(@s) FROM places WHERE manhattan(x, y, @x, @y) > 0")
+		require.NoError(t, err)
+		require.Equal(t, map[string]SQLValueType{"s": VarcharType, "x": IntegerType, "y": IntegerType}, params)
+	})
+
+	t.Run("invalid calls should fail", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, "SELECT manhattan(x, y) FROM places", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT manhattan(name, y, 0, 0) FROM places", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT broken() FROM places", nil)
+		require.ErrorIs(t, err, ErrInvalidTypes)
+	})
+
+	t.Run("functions should be resolved through the engine for pre-parsed statements", func(t *testing.T) {
+		stmts, err := ParseSQLString("SELECT shout(name) FROM places WHERE id = 1")
+		require.NoError(t, err)
+
+		reader, err := engine.QueryPreparedStmt(context.Background(), nil, stmts[0].(DataSource), nil)
+		require.NoError(t, err)
+		defer reader.Close()
+
+		row, err := reader.Read(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "HOME!", row.ValuesByPosition[0].RawValue())
+	})
+
+	t.Run("functions should not be shared between engines", func(t *testing.T) {
+		otherEngine := setupCommonTest(t)
+
+		_, err := otherEngine.queryAll(context.Background(), nil, "SELECT shout('hi')", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+	})
+}
+
+func TestRegisterAggregate(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	t.Run("invalid registrations should fail", func(t *testing.T) {
+		err := engine.RegisterAggregate("", productAggregate())
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		err = engine.RegisterAggregate("product", AggregateFunc{ReturnType: IntegerType})
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		spec := productAggregate()
+		spec.ReturnType = AnyType
+
+		err = engine.RegisterAggregate("product", spec)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		spec = productAggregate()
+		spec.ArgType = "POINT"
+
+		err = engine.RegisterAggregate("product", spec)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		err = engine.RegisterAggregate("max", productAggregate())
+		require.ErrorIs(t, err, ErrFunctionAlreadyExists)
+
+		err = engine.RegisterAggregate("upper", productAggregate())
+		require.ErrorIs(t, err, ErrFunctionAlreadyExists)
+
+		err = engine.RegisterAggregate("select", productAggregate())
+		require.ErrorIs(t, err, ErrFunctionAlreadyExists)
+	})
+
+	err := engine.RegisterAggregate("product", productAggregate())
+	require.NoError(t, err)
+
+	err = engine.RegisterAggregate("PRODUCT", productAggregate())
+	require.ErrorIs(t, err, ErrFunctionAlreadyExists)
+
+	err = engine.RegisterFunction("product", ScalarFunc{ReturnType: IntegerType, Eval: func(args []TypedValue) (TypedValue, error) { return nil, nil }})
+	require.ErrorIs(t, err, ErrFunctionAlreadyExists)
+
+	err = engine.RegisterAggregate("longest", AggregateFunc{
+		ArgType:    VarcharType,
+		ReturnType: VarcharType,
+		Init: func() interface{} {
+			return ""
+		},
+		Accumulate: func(state interface{}, val TypedValue) (interface{}, error) {
+			if s := val.RawValue().(string); len(s) > len(state.(string)) {
+				return s, nil
+			}
+			return state, nil
+		},
+		Merge: func(state, other interface{}) (interface{}, error) {
+			if len(other.(string)) > len(state.(string)) {
+				return other, nil
+			}
+			return state, nil
+		},
+		Finalize: func(state interface{}) (TypedValue, error) {
+			if state.(string) == "" {
+				return nil, nil
+			}
+			return NewVarchar(state.(string)), nil
+		},
+	})
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE items (id INTEGER AUTO_INCREMENT, category VARCHAR[32], name VARCHAR, qty INTEGER, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE INDEX ON items(category)", nil)
+	require.NoError(t, err)
+
+	t.Run("aggregations over empty tables", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT PRODUCT(qty), LONGEST(name) FROM items", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, int64(1), rows[0].ValuesByPosition[0].RawValue())
+		require.True(t, rows[0].ValuesByPosition[1].IsNull())
+	})
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		INSERT INTO items (category, name, qty) VALUES
+			('fruit', 'apple', 2),
+			('fruit', 'banana', 3),
+			('fruit', 'kiwi', NULL),
+			('vegetable', 'carrot', 5),
+			('vegetable', 'pea', 7),
+			('grain', 'rice', 11)
+	`, nil)
+	require.NoError(t, err)
+
+	t.Run("aggregation over the whole table", func(t *testing.T) {
+		reader, err := engine.Query(context.Background(), nil, "SELECT PRODUCT(qty) AS p, longest(name), COUNT(*) FROM items", nil)
+		require.NoError(t, err)
+		defer reader.Close()
+
+		cols, err := reader.Columns(context.Background())
+		require.NoError(t, err)
+		require.Len(t, cols, 3)
+		require.Equal(t, IntegerType, cols[0].Type)
+		require.Equal(t, VarcharType, cols[1].Type)
+
+		rows, err := ReadAllRows(context.Background(), reader)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, int64(2*3*5*7*11), rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, "banana", rows[0].ValuesByPosition[1].RawValue())
+		require.Equal(t, int64(6), rows[0].ValuesByPosition[2].RawValue())
+	})
+
+	t.Run("aggregation with group by", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT category, PRODUCT(qty), LONGEST(name) FROM items GROUP BY category ORDER BY category", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+
+		expected := []struct {
+			category string
+			product  int64
+			longest  string
+		}{
+			{"fruit", 6, "banana"},
+			{"grain", 11, "rice"},
+			{"vegetable", 35, "carrot"},
+		}
+
+		for i, e := range expected {
+			require.Equal(t, e.category, rows[i].ValuesByPosition[0].RawValue())
+			require.Equal(t, e.product, rows[i].ValuesByPosition[1].RawValue())
+			require.Equal(t, e.longest, rows[i].ValuesByPosition[2].RawValue())
+		}
+	})
+
+	t.Run("aggregation in having and order by", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT category, PRODUCT(qty) FROM items GROUP BY category HAVING PRODUCT(qty) > @min ORDER BY PRODUCT(qty) DESC", map[string]interface{}{"min": 10})
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		require.Equal(t, "vegetable", rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, int64(35), rows[0].ValuesByPosition[1].RawValue())
+		require.Equal(t, "grain", rows[1].ValuesByPosition[0].RawValue())
+		require.Equal(t, int64(11), rows[1].ValuesByPosition[1].RawValue())
+	})
+
+	t.Run("invalid aggregations should fail", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, "SELECT PRODUCT(name) FROM items", nil)
+		require.ErrorIs(t, err, ErrInvalidTypes)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT PRODUCT(*) FROM items", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT category, PRODUCT(qty) FROM items GROUP BY category HAVING PRODUCT(qty) = 'abc'", nil)
+		require.ErrorIs(t, err, ErrNotComparableValues)
+	})
+}
+
+func TestStableOrdering(t *testing.T) {
+	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(t, err)
+	defer closeStore(t, st)
+
+	engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix).WithSortBufferSize(4).WithStableOrdering(true))
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		CREATE TABLE players (
+			id INTEGER,
+			nickname VARCHAR[32] NOT NULL,
+			score INTEGER,
+			PRIMARY KEY id
+		);
+		CREATE UNIQUE INDEX ON players(nickname);
+	`, nil)
+	require.NoError(t, err)
+
+	const numRows = 50
+
+	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	for _, i := range rnd.Perm(numRows) {
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO players (id, nickname, score) VALUES (@id, @nickname, @score)", map[string]interface{}{
+			"id":       i,
+			"nickname": fmt.Sprintf("player%d", numRows-i),
+			"score":    i % 3,
+		})
+		require.NoError(t, err)
+	}
+
+	paginate := func(t *testing.T, query string, pageSize int) []int64 {
+		ids := make([]int64, 0, numRows)
+
+		for offset := 0; ; offset += pageSize {
+			rows, err := engine.queryAll(context.Background(), nil, query, map[string]interface{}{"limit": pageSize, "offset": offset})
+			require.NoError(t, err)
+
+			for _, row := range rows {
+				require.Len(t, row.ValuesByPosition, 2)
+				ids = append(ids, row.ValuesByPosition[0].RawValue().(int64))
+			}
+
+			if len(rows) < pageSize {
+				return ids
+			}
+		}
+	}
+
+	t.Run("ties should be broken by primary key", func(t *testing.T) {
+		ids := paginate(t, "SELECT id, score FROM players ORDER BY score DESC LIMIT @limit OFFSET @offset", 7)
+		require.Len(t, ids, numRows)
+
+		require.True(t, sort.SliceIsSorted(ids, func(i, j int) bool {
+			si, sj := ids[i]%3, ids[j]%3
+			if si != sj {
+				return si > sj
+			}
+			return ids[i] < ids[j]
+		}))
+
+		require.Equal(t, ids, paginate(t, "SELECT id, score FROM players ORDER BY score DESC LIMIT @limit OFFSET @offset", 7))
+		require.Equal(t, ids, paginate(t, "SELECT id, score FROM players ORDER BY score DESC LIMIT @limit OFFSET @offset", 11))
+	})
+
+	t.Run("ties should be broken by the remaining primary key columns", func(t *testing.T) {
+		ids := paginate(t, "SELECT id, score FROM players WHERE score > 0 ORDER BY score, id DESC LIMIT @limit OFFSET @offset", 5)
+
+		require.True(t, sort.SliceIsSorted(ids, func(i, j int) bool {
+			si, sj := ids[i]%3, ids[j]%3
+			if si != sj {
+				return si < sj
+			}
+			return ids[i] > ids[j]
+		}))
+	})
+
+	t.Run("unique orderings should not be extended", func(t *testing.T) {
+		tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithReadOnly(true))
+		require.NoError(t, err)
+		defer tx.Cancel()
+
+		stmts, err := ParseSQLString("SELECT id, score FROM players ORDER BY score, nickname")
+		require.NoError(t, err)
+
+		scanSpecs, err := stmts[0].(*SelectStmt).genScanSpecs(tx, nil)
+		require.NoError(t, err)
+		require.Len(t, scanSpecs.orderBySortExps, 2)
+
+		stmts, err = ParseSQLString("SELECT id, score FROM players ORDER BY score")
+		require.NoError(t, err)
+
+		scanSpecs, err = stmts[0].(*SelectStmt).genScanSpecs(tx, nil)
+		require.NoError(t, err)
+		require.Len(t, scanSpecs.orderBySortExps, 2)
+		require.Equal(t, "id", scanSpecs.orderBySortExps[1].exp.String())
+	})
+}
+
+func TestCustomClockAndRandSource(t *testing.T) {
+	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(t, err)
+	defer closeStore(t, st)
+
+	now := time.Date(2021, time.March, 4, 10, 20, 30, 123456000, time.UTC)
+
+	var clockMutex sync.Mutex
+	clock := func() time.Time {
+		clockMutex.Lock()
+		defer clockMutex.Unlock()
+
+		return now
+	}
+
+	advanceClock := func(d time.Duration) {
+		clockMutex.Lock()
+		defer clockMutex.Unlock()
+
+		now = now.Add(d)
+	}
+
+	newEngine := func(t *testing.T, seed int64) *Engine {
+		engine, err := NewEngine(st, DefaultOptions().
+			WithPrefix(sqlPrefix).
+			WithClock(clock).
+			WithRandSource(rand.NewSource(seed)),
+		)
+		require.NoError(t, err)
+		return engine
+	}
+
+	engine := newEngine(t, 42)
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE events (id INTEGER AUTO_INCREMENT, ts TIMESTAMP, weight FLOAT, u UUID, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	t.Run("NOW() should return the time of the injected clock", func(t *testing.T) {
+		row, err := engine.queryAll(context.Background(), nil, "SELECT NOW()", nil)
+		require.NoError(t, err)
+		require.Len(t, row, 1)
+		require.Equal(t, now, row[0].ValuesByPosition[0].RawValue())
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO events (ts) VALUES (NOW())", nil)
+		require.NoError(t, err)
+
+		advanceClock(time.Hour)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT ts FROM events WHERE ts < NOW()", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, now.Add(-time.Hour), rows[0].ValuesByPosition[0].RawValue())
+	})
+
+	t.Run("NOW() should be stable within a transaction", func(t *testing.T) {
+		tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithExplicitClose(true))
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), tx, "INSERT INTO events (ts) VALUES (NOW())", nil)
+		require.NoError(t, err)
+
+		advanceClock(time.Minute)
+
+		_, _, err = engine.Exec(context.Background(), tx, "INSERT INTO events (ts) VALUES (NOW())", nil)
+		require.NoError(t, err)
+
+		err = tx.Commit(context.Background())
+		require.NoError(t, err)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT COUNT(*) FROM events WHERE ts = @ts", map[string]interface{}{"ts": now.Add(-time.Minute)})
+		require.NoError(t, err)
+		require.Equal(t, int64(2), rows[0].ValuesByPosition[0].RawValue())
+	})
+
+	randomValues := func(t *testing.T, engine *Engine) []TypedValue {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT RANDOM(), RANDOM_UUID() FROM events", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+
+		values := make([]TypedValue, 0, len(rows)*2)
+		for _, row := range rows {
+			require.Equal(t, Float64Type, row.ValuesByPosition[0].Type())
+			require.GreaterOrEqual(t, row.ValuesByPosition[0].RawValue(), float64(0))
+			require.Less(t, row.ValuesByPosition[0].RawValue(), float64(1))
+			require.Equal(t, UUIDType, row.ValuesByPosition[1].Type())
+
+			values = append(values, row.ValuesByPosition...)
+		}
+		return values
+	}
+
+	t.Run("a fixed seed should reproduce random values", func(t *testing.T) {
+		values := randomValues(t, newEngine(t, 1))
+
+		require.Equal(t, values, randomValues(t, newEngine(t, 1)))
+		require.NotEqual(t, values, randomValues(t, newEngine(t, 2)))
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, "SELECT RANDOM(1)", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT id FROM events WHERE RANDOM() = 'a'", nil)
+		require.ErrorIs(t, err, ErrInvalidValue)
+	})
+}
+
+func TestReadRowsBatchCancellation(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, "CREATE TABLE table1 (id INTEGER AUTO_INCREMENT, title VARCHAR, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO table1 (title) VALUES ('a'), ('b'), ('c'), ('d'), ('e')", nil)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// sorted rows are read from the sorter buffer once the underlying reader is consumed
+	reader, err := engine.Query(ctx, nil, "SELECT id FROM table1 ORDER BY title DESC", nil)
+	require.NoError(t, err)
+	defer reader.Close()
+
+	batches := 0
+
+	err = ReadRowsBatch(ctx, reader, 2, func(rows []*Row) error {
+		require.Len(t, rows, 2)
+
+		batches++
+		cancel()
+		return nil
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Equal(t, 1, batches)
+}
+
+func TestWhereConstantFolding(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, "CREATE TABLE table1 (id INTEGER AUTO_INCREMENT, val INTEGER, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO table1 (val) VALUES (@val)", map[string]interface{}{"val": i * 1000})
+		require.NoError(t, err)
+	}
+
+	for _, d := range []struct {
+		where string
+		count int64
+	}{
+		{"1 = 1 AND val > 5000", 4},
+		{"val > 2000 + 3000", 4},
+		{"val > @a * @b", 4},
+		{"1 = 2 OR val < 1000 * 2", 2},
+		{"val >= 0 AND NOT (1 > 0)", 0},
+		{"false", 0},
+		{"true", 10},
+	} {
+		t.Run(d.where, func(t *testing.T) {
+			rows, err := engine.queryAll(context.Background(), nil, "SELECT COUNT(*) FROM table1 WHERE "+d.where, map[string]interface{}{"a": 1000, "b": 5})
+			require.NoError(t, err)
+			require.Equal(t, d.count, rows[0].ValuesByPosition[0].RawValue())
+		})
+	}
+
+	t.Run("errors should not be raised when no row gets evaluated", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT id FROM table1 WHERE 1 / 0 > 1 AND 1 = 0", nil)
+		require.NoError(t, err)
+		require.Empty(t, rows)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT id FROM table1 WHERE 1 / 0 > 1", nil)
+		require.ErrorIs(t, err, ErrDivisionByZero)
+	})
+}
+
+func TestPredicatePushdownIntoSubqueries(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, `
+		CREATE TABLE t (id INTEGER AUTO_INCREMENT, val INTEGER, title VARCHAR, PRIMARY KEY id);
+		CREATE INDEX ON t(val);
+	`, nil)
+	require.NoError(t, err)
+
+	for i := 1; i <= 100; i++ {
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO t (val, title) VALUES (@val, @title)", map[string]interface{}{
+			"val":   i * 100,
+			"title": fmt.Sprintf("title%d", i),
+		})
+		require.NoError(t, err)
+	}
+
+	valCol := func(t *testing.T) *Column {
+		tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithReadOnly(true))
+		require.NoError(t, err)
+		defer tx.Cancel()
+
+		table, err := tx.catalog.GetTableByName("t")
+		require.NoError(t, err)
+
+		col, err := table.GetColumnByName("val")
+		require.NoError(t, err)
+		return col
+	}(t)
+
+	queryWithSpecs := func(t *testing.T, query string, params map[string]interface{}) ([]*Row, *ScanSpecs) {
+		reader, err := engine.Query(context.Background(), nil, query, params)
+		require.NoError(t, err)
+		defer reader.Close()
+
+		rows, err := ReadAllRows(context.Background(), reader)
+		require.NoError(t, err)
+
+		return rows, reader.ScanSpecs()
+	}
+
+	for _, query := range []string{
+		"SELECT id, title FROM (SELECT * FROM t) s WHERE s.val > @min",
+		"SELECT id, title FROM (SELECT * FROM t) AS s WHERE val > @min AND id > 0",
+		"SELECT id, title FROM (SELECT id, val AS v, title FROM t) s WHERE s.v > @min",
+		"SELECT id, title FROM (SELECT id, val AS v, title FROM t WHERE id > 0) s WHERE s.v > @min AND title LIKE 'title.*'",
+		"SELECT id, title FROM (SELECT * FROM (SELECT * FROM t) s1) s2 WHERE s2.val > @min",
+	} {
+		t.Run(query, func(t *testing.T) {
+			rows, scanSpecs := queryWithSpecs(t, query, map[string]interface{}{"min": 9500})
+			require.Len(t, rows, 5)
+
+			for i, row := range rows {
+				require.Equal(t, int64(96+i), row.ValuesByPosition[0].RawValue())
+			}
+
+			// the filter must be used to narrow the scan of the inner table
+			require.False(t, scanSpecs.Index.IsPrimary())
+			require.Equal(t, "val", scanSpecs.Index.cols[0].colName)
+			require.Contains(t, scanSpecs.rangesByColID, valCol.id)
+		})
+	}
+
+	t.Run("predicates should not be pushed when filtering does not commute", func(t *testing.T) {
+		for _, query := range []string{
+			"SELECT id FROM (SELECT * FROM t ORDER BY id LIMIT 96) s WHERE s.val > @min",
+			"SELECT id FROM (SELECT * FROM t LIMIT 3 OFFSET 93) s WHERE s.val > @min",
+		} {
+			rows, scanSpecs := queryWithSpecs(t, query, map[string]interface{}{"min": 9500})
+			require.Len(t, rows, 1)
+			require.Equal(t, int64(96), rows[0].ValuesByPosition[0].RawValue())
+			require.True(t, scanSpecs.Index.IsPrimary())
+		}
+
+		rows, scanSpecs := queryWithSpecs(t, "SELECT v, c FROM (SELECT val AS v, COUNT(*) AS c FROM t GROUP BY val) s WHERE s.v > @min", map[string]interface{}{"min": 9500})
+		require.Len(t, rows, 5)
+		require.Empty(t, scanSpecs.rangesByColID)
+	})
+
+	t.Run("non-deterministic or unmapped predicates should not be pushed", func(t *testing.T) {
+		rows, scanSpecs := queryWithSpecs(t, "SELECT id FROM (SELECT id, val + 1 AS v FROM t) s WHERE s.v > @min", map[string]interface{}{"min": 9500})
+		require.Len(t, rows, 6)
+		require.True(t, scanSpecs.Index.IsPrimary())
+
+		_, scanSpecs = queryWithSpecs(t, "SELECT id FROM (SELECT * FROM t) s WHERE s.val > RANDOM()", nil)
+		require.True(t, scanSpecs.Index.IsPrimary())
+	})
+
+	t.Run("predicates referring to joined tables should not be pushed", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, `
+			SELECT s.id, t2.id
+			FROM (SELECT * FROM t) s
+			INNER JOIN t AS t2 ON t2.id = s.id + 1
+			WHERE s.val > @min AND t2.val < 10000`,
+			map[string]interface{}{"min": 9500},
+		)
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+	})
+}
+
+func TestViews(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, `
+		CREATE TABLE users (
+			id INTEGER AUTO_INCREMENT,
+			name VARCHAR[32],
+			age INTEGER,
+			active BOOLEAN,
+			PRIMARY KEY id
+		);
+		CREATE INDEX ON users(age);
+	`, nil)
+	require.NoError(t, err)
+
+	for i := 1; i <= 50; i++ {
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO users (name, age, active) VALUES (@name, @age, @active)", map[string]interface{}{
+			"name":   fmt.Sprintf("user%d", i),
+			"age":    i,
+			"active": i%2 == 0,
+		})
+		require.NoError(t, err)
+	}
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		CREATE VIEW active_users AS SELECT * FROM users WHERE active = true;
+		CREATE VIEW adults AS SELECT id, name AS username, age FROM active_users WHERE age >= 18;
+	`, nil)
+	require.NoError(t, err)
+
+	t.Run("selecting from a view", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT id, active_users.name, active FROM active_users", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 25)
+
+		for i, row := range rows {
+			require.Equal(t, int64(2*(i+1)), row.ValuesByPosition[0].RawValue())
+			require.Equal(t, fmt.Sprintf("user%d", 2*(i+1)), row.ValuesByPosition[1].RawValue())
+			require.Equal(t, true, row.ValuesByPosition[2].RawValue())
+		}
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT COUNT(*) FROM active_users v", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, int64(25), rows[0].ValuesByPosition[0].RawValue())
+	})
+
+	t.Run("conditions of the query should be merged into the ones of the view", func(t *testing.T) {
+		reader, err := engine.Query(context.Background(), nil, "SELECT id, age FROM active_users WHERE age > @age", map[string]interface{}{"age": 40})
+		require.NoError(t, err)
+
+		rows, err := ReadAllRows(context.Background(), reader)
+		require.NoError(t, err)
+		require.Len(t, rows, 5)
+
+		for i, row := range rows {
+			require.Equal(t, int64(42+2*i), row.ValuesByPosition[1].RawValue())
+		}
+
+		scanSpecs := reader.ScanSpecs()
+		require.NoError(t, reader.Close())
+
+		require.False(t, scanSpecs.Index.IsPrimary())
+		require.Equal(t, "age", scanSpecs.Index.cols[0].colName)
+	})
+
+	t.Run("selecting from a nested view", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT username, age FROM adults WHERE age < 24 ORDER BY age DESC", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+
+		for i, row := range rows {
+			age := int64(22 - 2*i)
+			require.Equal(t, fmt.Sprintf("user%d", age), row.ValuesByPosition[0].RawValue())
+			require.Equal(t, age, row.ValuesByPosition[1].RawValue())
+		}
+
+		rows, err = engine.queryAll(context.Background(), nil, `
+			SELECT u.id, a.username
+			FROM users u
+			INNER JOIN adults a ON a.id = u.id
+			WHERE u.age <= 22`,
+			nil,
+		)
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+	})
+
+	t.Run("views should be persisted in the catalog", func(t *testing.T) {
+		tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithReadOnly(true))
+		require.NoError(t, err)
+		defer tx.Cancel()
+
+		views := tx.Catalog().GetViews()
+		require.Len(t, views, 2)
+		require.Equal(t, "active_users", views[0].Name())
+		require.Equal(t, "SELECT * FROM users WHERE active = true", views[0].SQL())
+		require.Equal(t, "adults", views[1].Name())
+	})
+
+	t.Run("view names should not clash with tables", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "CREATE VIEW users AS SELECT * FROM users", nil)
+		require.ErrorIs(t, err, ErrTableAlreadyExists)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE adults (id INTEGER, PRIMARY KEY id)", nil)
+		require.ErrorIs(t, err, ErrViewAlreadyExists)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE users RENAME TO adults", nil)
+		require.ErrorIs(t, err, ErrViewAlreadyExists)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE VIEW adults AS SELECT * FROM users", nil)
+		require.ErrorIs(t, err, ErrViewAlreadyExists)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE VIEW IF NOT EXISTS adults AS SELECT * FROM users", nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid view definitions should be rejected", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "CREATE VIEW v AS SELECT * FROM missing", nil)
+		require.ErrorIs(t, err, ErrTableDoesNotExist)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT * FROM (HISTORY OF adults)", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, _, err = engine.Exec(context.Background(), nil, `
+			CREATE VIEW v1 AS SELECT * FROM users;
+			CREATE VIEW v2 AS SELECT * FROM v1;
+			DROP VIEW v1;
+		`, nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE VIEW v1 AS SELECT * FROM v2", nil)
+		require.ErrorIs(t, err, ErrMaxViewNestingExceeded)
+	})
+
+	t.Run("dropped views should not be usable", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "DROP VIEW adults", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "DROP VIEW adults", nil)
+		require.ErrorIs(t, err, ErrViewDoesNotExist)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT * FROM adults", nil)
+		require.ErrorIs(t, err, ErrTableDoesNotExist)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE VIEW adults AS SELECT * FROM users WHERE age >= 18", nil)
+		require.NoError(t, err)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT * FROM adults", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 33)
+	})
+}
+
+func TestConflictGranularity(t *testing.T) {
+	setup := func(t *testing.T) *Engine {
+		engine := setupCommonTest(t)
+
+		_, _, err := engine.Exec(context.Background(), nil, `
+			CREATE TABLE accounts (id INTEGER, owner VARCHAR[16], balance INTEGER, PRIMARY KEY id);
+			CREATE TABLE audit (id INTEGER, total INTEGER, PRIMARY KEY id);
+			CREATE TABLE notes (id INTEGER, note VARCHAR, PRIMARY KEY id);
+
+			INSERT INTO accounts (id, owner, balance) VALUES (1, 'alice', 10), (2, 'alice', 20), (3, 'bob', 30), (4, 'bob', 40);
+		`, nil)
+		require.NoError(t, err)
+
+		return engine
+	}
+
+	type outcome struct {
+		row, predicate, table bool // true when a conflict is expected
+	}
+
+	testCases := []struct {
+		description string
+		concurrent  string
+		conflicts   outcome
+	}{
+		{
+			description: "changes to rows not satisfying the conditions",
+			concurrent:  "UPDATE accounts SET balance = balance + 1 WHERE id = 3",
+			conflicts:   outcome{row: true, predicate: false, table: true},
+		},
+		{
+			description: "new rows not satisfying the conditions",
+			concurrent:  "INSERT INTO accounts (id, owner, balance) VALUES (5, 'carol', 50)",
+			conflicts:   outcome{row: true, predicate: false, table: true},
+		},
+		{
+			description: "changes to rows satisfying the conditions",
+			concurrent:  "UPDATE accounts SET balance = balance + 1 WHERE id = 2",
+			conflicts:   outcome{row: true, predicate: true, table: true},
+		},
+		{
+			description: "rows no longer satisfying the conditions",
+			concurrent:  "DELETE FROM accounts WHERE id = 1",
+			conflicts:   outcome{row: true, predicate: true, table: true},
+		},
+		{
+			description: "rows starting to satisfy the conditions",
+			concurrent:  "UPDATE accounts SET owner = 'alice' WHERE id = 4",
+			conflicts:   outcome{row: true, predicate: true, table: true},
+		},
+		{
+			description: "new rows satisfying the conditions",
+			concurrent:  "INSERT INTO accounts (id, owner, balance) VALUES (6, 'alice', 60)",
+			conflicts:   outcome{row: true, predicate: true, table: true},
+		},
+		{
+			description: "changes to other tables",
+			concurrent:  "INSERT INTO notes (id, note) VALUES (1, 'unrelated')",
+			conflicts:   outcome{row: false, predicate: false, table: false},
+		},
+	}
+
+	granularities := []struct {
+		name        string
+		granularity ConflictGranularity
+		expected    func(outcome) bool
+	}{
+		{"row", RowConflicts, func(o outcome) bool { return o.row }},
+		{"predicate", PredicateConflicts, func(o outcome) bool { return o.predicate }},
+		{"table", TableConflicts, func(o outcome) bool { return o.table }},
+	}
+
+	for _, g := range granularities {
+		for _, tc := range testCases {
+			t.Run(fmt.Sprintf("%s granularity with %s", g.name, tc.description), func(t *testing.T) {
+				engine := setup(t)
+
+				tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithExplicitClose(true).WithConflictGranularity(g.granularity))
+				require.NoError(t, err)
+
+				rows, err := engine.queryAll(context.Background(), tx, "SELECT SUM(balance) FROM accounts WHERE owner = 'alice'", nil)
+				require.NoError(t, err)
+				require.Len(t, rows, 1)
+
+				_, _, err = engine.Exec(context.Background(), tx, "INSERT INTO audit (id, total) VALUES (1, @total)", map[string]interface{}{"total": rows[0].ValuesByPosition[0].RawValue()})
+				require.NoError(t, err)
+
+				_, _, err = engine.Exec(context.Background(), nil, tc.concurrent, nil)
+				require.NoError(t, err)
+
+				err = tx.Commit(context.Background())
+				if g.expected(tc.conflicts) {
+					require.ErrorIs(t, err, store.ErrTxReadConflict)
+				} else {
+					require.NoError(t, err)
+				}
+			})
+		}
+	}
+
+	t.Run("predicate granularity should detect concurrent updates of updated rows", func(t *testing.T) {
+		engine := setup(t)
+
+		tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithExplicitClose(true).WithConflictGranularity(PredicateConflicts))
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), tx, "UPDATE accounts SET balance = balance - 5 WHERE owner = 'bob'", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "UPDATE accounts SET balance = 0 WHERE id = 3", nil)
+		require.NoError(t, err)
+
+		err = tx.Commit(context.Background())
+		require.ErrorIs(t, err, store.ErrTxReadConflict)
+	})
+
+	t.Run("predicate granularity should only consider the rows read before a limit is reached", func(t *testing.T) {
+		engine := setup(t)
+
+		tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithExplicitClose(true).WithConflictGranularity(PredicateConflicts))
+		require.NoError(t, err)
+
+		rows, err := engine.queryAll(context.Background(), tx, "SELECT id FROM accounts WHERE owner = 'alice' ORDER BY id LIMIT 1", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+
+		_, _, err = engine.Exec(context.Background(), tx, "INSERT INTO audit (id, total) VALUES (1, 0)", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO accounts (id, owner, balance) VALUES (7, 'alice', 70)", nil)
+		require.NoError(t, err)
+
+		err = tx.Commit(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("invalid granularity should be rejected", func(t *testing.T) {
+		engine := setup(t)
+
+		_, err := engine.NewTx(context.Background(), DefaultTxOptions().WithConflictGranularity(ConflictGranularity(10)))
+		require.ErrorIs(t, err, store.ErrInvalidOptions)
+	})
+}
+
+func TestRangeDelete(t *testing.T) {
+	setup := func(t *testing.T) *Engine {
+		engine := setupCommonTest(t)
+
+		_, _, err := engine.Exec(context.Background(), nil, `
+			CREATE TABLE items (id INTEGER, title VARCHAR[32], active BOOLEAN, PRIMARY KEY id);
+			CREATE INDEX ON items (title);
+		`, nil)
+		require.NoError(t, err)
+
+		tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithExplicitClose(true))
+		require.NoError(t, err)
+
+		for i := 1; i <= 300; i++ {
+			_, _, err = engine.Exec(
+				context.Background(),
+				tx,
+				"INSERT INTO items (id, title, active) VALUES (@id, @title, true)",
+				map[string]interface{}{"id": i, "title": fmt.Sprintf("title%d", i%10)},
+			)
+			require.NoError(t, err)
+		}
+
+		err = tx.Commit(context.Background())
+		require.NoError(t, err)
+
+		return engine
+	}
+
+	countRows := func(t *testing.T, engine *Engine, query string) int {
+		rows, err := engine.queryAll(context.Background(), nil, query, nil)
+		require.NoError(t, err)
+		return len(rows)
+	}
+
+	testCases := []struct {
+		description string
+		where       string
+		params      map[string]interface{}
+		deleted     int
+		fastPath    bool
+	}{
+		{description: "between", where: "id BETWEEN 100 AND 200", deleted: 101, fastPath: true},
+		{description: "exclusive bounds", where: "id > 10 AND id < 20", deleted: 9, fastPath: true},
+		{description: "bounds on the left side", where: "10 < id AND 20 >= id", deleted: 10, fastPath: true},
+		{description: "open range", where: "id >= 290", deleted: 11, fastPath: true},
+		{description: "single key", where: "id = @id", params: map[string]interface{}{"id": 7}, deleted: 1, fastPath: true},
+		{description: "empty range", where: "id > 5 AND id < 3", deleted: 0, fastPath: true},
+		{description: "non-key conditions", where: "id BETWEEN 100 AND 200 AND title = 'title1'", deleted: 10},
+		{description: "disjunctions", where: "id < 10 OR id > 290", deleted: 19},
+		{description: "non-equality", where: "id != 1", deleted: 299},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			engine := setup(t)
+
+			stmts, err := ParseSQL(strings.NewReader("DELETE FROM items WHERE " + tc.where))
+			require.NoError(t, err)
+
+			tx, err := engine.NewTx(context.Background(), DefaultTxOptions())
+			require.NoError(t, err)
+
+			_, _, fastPath := stmts[0].(*DeleteFromStmt).primaryKeyRange(tx, tc.params)
+			require.Equal(t, tc.fastPath, fastPath)
+
+			err = tx.Cancel()
+			require.NoError(t, err)
+
+			_, ctxs, err := engine.ExecPreparedStmts(context.Background(), nil, stmts, tc.params)
+			require.NoError(t, err)
+			require.Len(t, ctxs, 1)
+			require.Equal(t, tc.deleted, ctxs[0].UpdatedRows())
+
+			require.Equal(t, 300-tc.deleted, countRows(t, engine, "SELECT * FROM items"))
+
+			// secondary index entries of deleted rows must be removed as well
+			require.Equal(t, 300-tc.deleted, countRows(t, engine, "SELECT * FROM items USE INDEX ON (title)"))
+
+			deletedRows, err := engine.queryAll(
+				context.Background(),
+				nil,
+				"SELECT * FROM items WHERE "+tc.where,
+				tc.params,
+			)
+			require.NoError(t, err)
+			require.Empty(t, deletedRows)
+		})
+	}
+
+	t.Run("range delete should require fewer allocations than row-by-row deletion", func(t *testing.T) {
+		engine := setup(t)
+
+		allocsFor := func(where string) float64 {
+			stmts, err := ParseSQL(strings.NewReader("DELETE FROM items WHERE " + where))
+			require.NoError(t, err)
+
+			return testing.AllocsPerRun(5, func() {
+				tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithExplicitClose(true))
+				require.NoError(t, err)
+
+				_, ctxs, err := engine.ExecPreparedStmts(context.Background(), tx, stmts, nil)
+				require.NoError(t, err)
+				require.Empty(t, ctxs)
+				require.Equal(t, 101, tx.UpdatedRows())
+
+				err = tx.Cancel()
+				require.NoError(t, err)
+			})
+		}
+
+		rangeAllocs := allocsFor("id BETWEEN 100 AND 200")
+		rowByRowAllocs := allocsFor("id BETWEEN 100 AND 200 AND active")
+
+		require.Less(t, rangeAllocs, rowByRowAllocs)
+	})
+}
+
+func TestIdempotentDDL(t *testing.T) {
+	t.Run("statements should be a no-op when the table or index already exists or is absent", func(t *testing.T) {
+		engine := setupCommonTest(t)
+
+		_, _, err := engine.Exec(context.Background(), nil, `
+			CREATE TABLE IF NOT EXISTS table1 (id INTEGER, title VARCHAR[32], PRIMARY KEY id);
+			CREATE TABLE IF NOT EXISTS table1 (id INTEGER, title VARCHAR[32], PRIMARY KEY id);
+
+			CREATE INDEX IF NOT EXISTS ON table1 (title);
+			CREATE INDEX IF NOT EXISTS ON table1 (title);
+
+			DROP TABLE IF EXISTS table2;
+		`, nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "DROP TABLE table2", nil)
+		require.ErrorIs(t, err, ErrTableDoesNotExist)
+
+		// schema differences are ignored unless validation is enabled
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE IF NOT EXISTS table1 (id VARCHAR[16], PRIMARY KEY id)", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE UNIQUE INDEX IF NOT EXISTS ON table1 (title)", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, `
+			DROP TABLE IF EXISTS table1;
+			DROP TABLE IF EXISTS table1;
+		`, nil)
+		require.NoError(t, err)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT * FROM table1", nil)
+		require.ErrorIs(t, err, ErrTableDoesNotExist)
+	})
+
+	t.Run("dropping a view as a table should fail even if it exists", func(t *testing.T) {
+		engine := setupCommonTest(t)
+
+		_, _, err := engine.Exec(context.Background(), nil, "CREATE TABLE table1 (id INTEGER, PRIMARY KEY id)", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE VIEW vv AS SELECT id FROM table1", nil)
+		require.NoError(t, err)
+
+		for _, sql := range []string{"DROP TABLE vv", "DROP TABLE IF EXISTS vv"} {
+			_, _, err = engine.Exec(context.Background(), nil, sql, nil)
+			require.ErrorIs(t, err, ErrTableDoesNotExist, sql)
+			require.ErrorContains(t, err, "vv is a view", sql)
+		}
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT * FROM vv", nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("statements should fail when the existing table or index does not match", func(t *testing.T) {
+		st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+		require.NoError(t, err)
+		t.Cleanup(func() { closeStore(t, st) })
+
+		engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix).WithIfNotExistsValidation(true))
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, `
+			CREATE TABLE IF NOT EXISTS table1 (id INTEGER AUTO_INCREMENT, title VARCHAR[32] NOT NULL, active BOOLEAN, PRIMARY KEY id);
+			CREATE TABLE IF NOT EXISTS table1 (id INTEGER AUTO_INCREMENT, title VARCHAR[32] NOT NULL, active BOOLEAN, PRIMARY KEY id);
+
+			CREATE INDEX IF NOT EXISTS ON table1 (title);
+			CREATE INDEX IF NOT EXISTS ON table1 (title);
+
+			CREATE TABLE IF NOT EXISTS table2 (a INTEGER, b INTEGER, PRIMARY KEY (a, b));
+			CREATE TABLE IF NOT EXISTS table2 (a INTEGER, b INTEGER, PRIMARY KEY (a, b));
+		`, nil)
+		require.NoError(t, err)
+
+		mismatches := []string{
+			"CREATE TABLE IF NOT EXISTS table1 (id INTEGER AUTO_INCREMENT, title VARCHAR[32] NOT NULL, PRIMARY KEY id)",
+			"CREATE TABLE IF NOT EXISTS table1 (id INTEGER AUTO_INCREMENT, title VARCHAR[32] NOT NULL, active BOOLEAN, extra INTEGER, PRIMARY KEY id)",
+			"CREATE TABLE IF NOT EXISTS table1 (id INTEGER AUTO_INCREMENT, title VARCHAR[64] NOT NULL, active BOOLEAN, PRIMARY KEY id)",
+			"CREATE TABLE IF NOT EXISTS table1 (id INTEGER AUTO_INCREMENT, title VARCHAR[32], active BOOLEAN, PRIMARY KEY id)",
+			"CREATE TABLE IF NOT EXISTS table1 (id INTEGER, title VARCHAR[32] NOT NULL, active BOOLEAN, PRIMARY KEY id)",
+			"CREATE TABLE IF NOT EXISTS table1 (id INTEGER AUTO_INCREMENT, title VARCHAR[32] NOT NULL, active INTEGER, PRIMARY KEY id)",
+			"CREATE TABLE IF NOT EXISTS table1 (id INTEGER AUTO_INCREMENT, name VARCHAR[32] NOT NULL, active BOOLEAN, PRIMARY KEY id)",
+			"CREATE TABLE IF NOT EXISTS table2 (a INTEGER, b INTEGER, PRIMARY KEY (b, a))",
+			"CREATE TABLE IF NOT EXISTS table2 (a INTEGER, b INTEGER, PRIMARY KEY a)",
+			"CREATE UNIQUE INDEX IF NOT EXISTS ON table1 (title)",
+			"CREATE INDEX IF NOT EXISTS ON table1 (id)",
+		}
+
+		for _, stmt := range mismatches {
+			_, _, err = engine.Exec(context.Background(), nil, stmt, nil)
+			require.ErrorIs(t, err, ErrSchemaMismatch, stmt)
+		}
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE UNIQUE INDEX IF NOT EXISTS ON table1 (id)", nil)
+		require.NoError(t, err)
+	})
+}
+
+func TestInMemoryStore(t *testing.T) {
+	opts := store.DefaultOptions().WithInMemory(true)
+
+	engine, _ := setupCommonTestWithOptions(t, opts)
+
+	_, _, err := engine.Exec(context.Background(), nil, `
+		CREATE TABLE table1 (
+			id INTEGER,
+			title VARCHAR[50],
+			active BOOLEAN,
+			PRIMARY KEY id
+		);
+
+		CREATE INDEX ON table1 (title);
+	`, nil)
+	require.NoError(t, err)
+
+	for i := 1; i <= 10; i++ {
+		_, _, err = engine.Exec(
+			context.Background(),
+			nil,
+			"INSERT INTO table1 (id, title, active) VALUES (@id, @title, @active)",
+			map[string]interface{}{"id": i, "title": fmt.Sprintf("title%d", i), "active": i%2 == 0},
+		)
+		require.NoError(t, err)
+	}
+
+	_, _, err = engine.Exec(context.Background(), nil, "UPDATE table1 SET title = 'updated' WHERE id = 1", nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "DELETE FROM table1 WHERE active", nil)
+	require.NoError(t, err)
+
+	rows, err := engine.queryAll(context.Background(), nil, "SELECT id, title FROM table1 ORDER BY title", nil)
+	require.NoError(t, err)
+	require.Len(t, rows, 5)
+	require.Equal(t, "title3", rows[0].ValuesByPosition[1].RawValue())
+	require.Equal(t, "updated", rows[4].ValuesByPosition[1].RawValue())
+	require.Equal(t, int64(1), rows[4].ValuesByPosition[0].RawValue())
+
+	t.Run("each store holds its own data", func(t *testing.T) {
+		otherEngine, _ := setupCommonTestWithOptions(t, opts)
+
+		_, err := otherEngine.queryAll(context.Background(), nil, "SELECT * FROM table1", nil)
+		require.ErrorIs(t, err, ErrTableDoesNotExist)
+
+		_, _, err = otherEngine.Exec(context.Background(), nil, "CREATE TABLE table1 (id INTEGER, PRIMARY KEY id)", nil)
+		require.NoError(t, err)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT * FROM table1", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 5)
+	})
+}
+
+func TestRowReaderAll(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, "CREATE TABLE table1 (id INTEGER, amount INTEGER, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	for i := 1; i <= 10; i++ {
+		_, _, err = engine.Exec(
+			context.Background(),
+			nil,
+			"INSERT INTO table1 (id, amount) VALUES (@id, @amount)",
+			map[string]interface{}{"id": i, "amount": 5 - i},
+		)
+		require.NoError(t, err)
+	}
+
+	t.Run("iterate over all rows", func(t *testing.T) {
+		reader, err := engine.Query(context.Background(), nil, "SELECT id FROM table1 WHERE id > 2", nil)
+		require.NoError(t, err)
+
+		var ids []int64
+		for row, err := range reader.All(context.Background()) {
+			require.NoError(t, err)
+			ids = append(ids, row.ValuesByPosition[0].RawValue().(int64))
+		}
+		require.Equal(t, []int64{3, 4, 5, 6, 7, 8, 9, 10}, ids)
+
+		err = reader.Close()
+		require.ErrorIs(t, err, tbtree.ErrAlreadyClosed)
+	})
+
+	t.Run("reader is closed on early break", func(t *testing.T) {
+		tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithReadOnly(true))
+		require.NoError(t, err)
+		defer tx.Cancel()
+
+		reader, err := engine.Query(context.Background(), tx, "SELECT id FROM table1 ORDER BY id DESC", nil)
+		require.NoError(t, err)
+
+		n := 0
+		for row, err := range reader.All(context.Background()) {
+			require.NoError(t, err)
+			require.Equal(t, int64(10), row.ValuesByPosition[0].RawValue())
+
+			n++
+			break
+		}
+		require.Equal(t, 1, n)
+
+		err = reader.Close()
+		require.ErrorIs(t, err, tbtree.ErrAlreadyClosed)
+
+		// the transaction can still be used once the reader got closed
+		rows, err := engine.queryAll(context.Background(), tx, "SELECT COUNT(*) FROM table1", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, int64(10), rows[0].ValuesByPosition[0].RawValue())
+	})
+
+	t.Run("errors are yielded and end the iteration", func(t *testing.T) {
+		reader, err := engine.Query(context.Background(), nil, "SELECT id, 100 / amount FROM table1", nil)
+		require.NoError(t, err)
+
+		var ids []int64
+		var iterErr error
+
+		for row, err := range reader.All(context.Background()) {
+			if err != nil {
+				require.Nil(t, row)
+				iterErr = err
+				continue
+			}
+			ids = append(ids, row.ValuesByPosition[0].RawValue().(int64))
+		}
+		require.ErrorIs(t, iterErr, ErrDivisionByZero)
+		require.Equal(t, []int64{1, 2, 3, 4}, ids)
+
+		err = reader.Close()
+		require.ErrorIs(t, err, tbtree.ErrAlreadyClosed)
+	})
+}
+
+func TestFetchFirstWithTies(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, "CREATE TABLE scores (id INTEGER, score INTEGER, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	for id, score := range []int{50, 90, 70, 90, 70, 70, 30, 70} {
+		_, _, err = engine.Exec(
+			context.Background(),
+			nil,
+			"INSERT INTO scores (id, score) VALUES (@id, @score)",
+			map[string]interface{}{"id": id + 1, "score": score},
+		)
+		require.NoError(t, err)
+	}
+
+	queryIDs := func(t *testing.T, query string) []int64 {
+		rows, err := engine.queryAll(context.Background(), nil, query, nil)
+		require.NoError(t, err)
+
+		ids := make([]int64, len(rows))
+		for i, row := range rows {
+			ids[i] = row.ValuesByPosition[0].RawValue().(int64)
+		}
+		return ids
+	}
+
+	t.Run("without ties rows are cut off at the limit", func(t *testing.T) {
+		ids := queryIDs(t, "SELECT id FROM scores ORDER BY score DESC, id FETCH FIRST 3 ROWS ONLY")
+		require.Equal(t, []int64{2, 4, 3}, ids)
+
+		ids = queryIDs(t, "SELECT id FROM scores ORDER BY score DESC LIMIT 3")
+		require.Len(t, ids, 3)
+	})
+
+	t.Run("with ties all the rows tying at the limit are returned", func(t *testing.T) {
+		ids := queryIDs(t, "SELECT id FROM scores ORDER BY score DESC FETCH FIRST 3 ROWS WITH TIES")
+		require.Len(t, ids, 6)
+		require.ElementsMatch(t, []int64{2, 4}, ids[:2])
+		require.ElementsMatch(t, []int64{3, 5, 6, 8}, ids[2:])
+	})
+
+	t.Run("with ties and no rows tying at the limit", func(t *testing.T) {
+		ids := queryIDs(t, "SELECT id FROM scores ORDER BY score DESC FETCH FIRST 2 ROWS WITH TIES")
+		require.ElementsMatch(t, []int64{2, 4}, ids)
+
+		ids = queryIDs(t, "SELECT id FROM scores ORDER BY score FETCH FIRST ROW WITH TIES")
+		require.Equal(t, []int64{7}, ids)
+	})
+
+	t.Run("with ties after an offset", func(t *testing.T) {
+		ids := queryIDs(t, "SELECT id FROM scores ORDER BY score OFFSET 1 FETCH FIRST 2 ROWS WITH TIES")
+		require.Len(t, ids, 5)
+		require.Equal(t, int64(1), ids[0])
+		require.ElementsMatch(t, []int64{3, 5, 6, 8}, ids[1:])
+	})
+
+	t.Run("with ties after limit", func(t *testing.T) {
+		ids := queryIDs(t, "SELECT id FROM scores ORDER BY score DESC LIMIT 3 WITH TIES")
+		require.Len(t, ids, 6)
+		require.ElementsMatch(t, []int64{2, 4}, ids[:2])
+		require.ElementsMatch(t, []int64{3, 5, 6, 8}, ids[2:])
+
+		ids = queryIDs(t, "SELECT id FROM scores ORDER BY score LIMIT 2 OFFSET 1 WITH TIES")
+		require.Len(t, ids, 5)
+		require.Equal(t, int64(1), ids[0])
+		require.ElementsMatch(t, []int64{3, 5, 6, 8}, ids[1:])
+	})
+
+	t.Run("with ties over an ordering satisfied by an index", func(t *testing.T) {
+		ids := queryIDs(t, "SELECT id FROM scores ORDER BY id FETCH FIRST 3 ROWS WITH TIES")
+		require.Equal(t, []int64{1, 2, 3}, ids)
+	})
+
+	t.Run("with ties over an aggregation", func(t *testing.T) {
+		rows, err := engine.queryAll(
+			context.Background(),
+			nil,
+			"SELECT score, COUNT(*) AS c FROM scores GROUP BY score ORDER BY COUNT(*) FETCH FIRST 1 ROWS WITH TIES",
+			nil,
+		)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+
+		for _, row := range rows {
+			require.Equal(t, int64(1), row.ValuesByPosition[1].RawValue())
+		}
+	})
+
+	t.Run("with ties requires order by", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, "SELECT id FROM scores FETCH FIRST 3 ROWS WITH TIES", nil)
+		require.ErrorIs(t, err, ErrWithTiesRequiresOrderBy)
+	})
+
+	t.Run("with ties can not be combined with distinct", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, "SELECT DISTINCT score FROM scores ORDER BY score FETCH FIRST 3 ROWS WITH TIES", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+	})
+}
+
+func TestApproxCountDistinct(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, "CREATE TABLE events (id INTEGER, user_id INTEGER, kind VARCHAR, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	const (
+		events    = 20000
+		users     = 5000
+		batchSize = 1000
+		hllError  = 1.04 / 128 // standard error with the default precision
+	)
+
+	for b := 0; b < events/batchSize; b++ {
+		values := make([]string, batchSize)
+
+		for i := range values {
+			id := b*batchSize + i
+
+			kind := "click"
+			if id%2 == 0 {
+				kind = "view"
+			}
+
+			values[i] = fmt.Sprintf("(%d, %d, '%s')", id, (id*7919)%users, kind)
+		}
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO events (id, user_id, kind) VALUES "+strings.Join(values, ", "), nil)
+		require.NoError(t, err)
+	}
+
+	requireWithinBound := func(t *testing.T, expected, estimate int64) {
+		// three standard errors
+		require.InDelta(t, expected, estimate, 3*hllError*float64(expected))
+	}
+
+	t.Run("estimate over the whole table", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT APPROX_COUNT_DISTINCT(user_id), approx_count_distinct(id), APPROX_COUNT_DISTINCT(kind) FROM events", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+
+		requireWithinBound(t, users, rows[0].ValuesByPosition[0].RawValue().(int64))
+		requireWithinBound(t, events, rows[0].ValuesByPosition[1].RawValue().(int64))
+		require.Equal(t, int64(2), rows[0].ValuesByPosition[2].RawValue())
+	})
+
+	t.Run("estimate by group", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT kind, APPROX_COUNT_DISTINCT(user_id) FROM events GROUP BY kind ORDER BY kind", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+
+		for _, row := range rows {
+			requireWithinBound(t, users/2, row.ValuesByPosition[1].RawValue().(int64))
+		}
+	})
+
+	t.Run("null values are skipped", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT APPROX_COUNT_DISTINCT(user_id) FROM events WHERE id < 0", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, int64(0), rows[0].ValuesByPosition[0].RawValue())
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO events (id, kind) VALUES (-1, 'click'), (-2, 'view')", nil)
+		require.NoError(t, err)
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT APPROX_COUNT_DISTINCT(user_id), COUNT(*) FROM events WHERE id < 0", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, int64(0), rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, int64(2), rows[0].ValuesByPosition[1].RawValue())
+	})
+
+	t.Run("partial aggregations can be merged", func(t *testing.T) {
+		agg := engine.functions.aggregate(approxCountDistinctFnName)
+		require.NotNil(t, agg)
+
+		v1 := agg.newValue("(events.user_id)")
+		v2 := agg.newValue("(events.user_id)")
+
+		for i := 0; i < 3000; i++ {
+			require.NoError(t, v1.updateWith(&Integer{val: int64(i)}))
+			require.NoError(t, v2.updateWith(&Integer{val: int64(i + 1000)}))
+		}
+
+		err := v1.mergeWith(v2)
+		require.NoError(t, err)
+
+		val, err := v1.finalize()
+		require.NoError(t, err)
+		requireWithinBound(t, 4000, val.RawValue().(int64))
+
+		other := newApproxCountDistinct(MinHLLPrecision).newValue("(events.user_id)")
+
+		_, err = v1.agg.spec.Merge(v1.state, other.state)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+	})
+
+	t.Run("precision is configurable", func(t *testing.T) {
+		_, err := NewEngine(engine.store, DefaultOptions().WithPrefix(sqlPrefix).WithHLLPrecision(MaxHLLPrecision+1))
+		require.ErrorIs(t, err, store.ErrInvalidOptions)
+
+		lowPrecisionEngine, err := NewEngine(engine.store, DefaultOptions().WithPrefix(sqlPrefix).WithHLLPrecision(MinHLLPrecision))
+		require.NoError(t, err)
+
+		rows, err := lowPrecisionEngine.queryAll(context.Background(), nil, "SELECT APPROX_COUNT_DISTINCT(user_id) FROM events", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+
+		// only 16 registers are used, thus the estimate is far less accurate
+		require.InDelta(t, users, rows[0].ValuesByPosition[0].RawValue().(int64), 5*(1.04/4)*users)
+	})
+
+	t.Run("the aggregation can not be redefined", func(t *testing.T) {
+		err := engine.RegisterAggregate("approx_count_distinct", productAggregate())
+		require.ErrorIs(t, err, ErrFunctionAlreadyExists)
+	})
+}
+
+func TestPercentileAggregates(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, `
+		CREATE TABLE requests (
+			id INTEGER,
+			svc VARCHAR,
+			latency INTEGER,
+			duration FLOAT,
+			PRIMARY KEY id
+		)`, nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		INSERT INTO requests (id, svc, latency, duration) VALUES
+			(1, 'odd', 50, 5.0),
+			(2, 'odd', 10, 1.0),
+			(3, 'odd', 40, 4.0),
+			(4, 'odd', 20, 2.0),
+			(5, 'odd', 30, 3.0),
+			(6, 'even', 40, 4.0),
+			(7, 'even', 10, 1.0),
+			(8, 'even', 30, 3.0),
+			(9, 'even', 20, 2.0),
+			(10, 'even', NULL, NULL)
+	`, nil)
+	require.NoError(t, err)
+
+	t.Run("median of odd and even counts", func(t *testing.T) {
+		rows, err := engine.queryAll(
+			context.Background(),
+			nil,
+			`SELECT svc,
+				PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY latency),
+				PERCENTILE_DISC(0.5) WITHIN GROUP (ORDER BY latency),
+				PERCENTILE_DISC(0.5) WITHIN GROUP (ORDER BY duration)
+			FROM requests
+			GROUP BY svc
+			ORDER BY svc`,
+			nil,
+		)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+
+		// even: continuous percentiles get interpolated between the two central values
+		require.Equal(t, "even", rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, 25.0, rows[0].ValuesByPosition[1].RawValue())
+		require.Equal(t, int64(20), rows[0].ValuesByPosition[2].RawValue())
+		require.Equal(t, 2.0, rows[0].ValuesByPosition[3].RawValue())
+
+		require.Equal(t, "odd", rows[1].ValuesByPosition[0].RawValue())
+		require.Equal(t, 30.0, rows[1].ValuesByPosition[1].RawValue())
+		require.Equal(t, int64(30), rows[1].ValuesByPosition[2].RawValue())
+		require.Equal(t, 3.0, rows[1].ValuesByPosition[3].RawValue())
+	})
+
+	t.Run("several percentiles over the same column", func(t *testing.T) {
+		reader, err := engine.Query(
+			context.Background(),
+			nil,
+			`SELECT
+				PERCENTILE_CONT(0) WITHIN GROUP (ORDER BY latency) AS p0,
+				PERCENTILE_CONT(0.9) WITHIN GROUP (ORDER BY latency) AS p90,
+				PERCENTILE_CONT(0.1) WITHIN GROUP (ORDER BY latency DESC) AS p90_desc,
+				PERCENTILE_DISC(1) WITHIN GROUP (ORDER BY latency) AS p100
+			FROM requests
+			WHERE svc = 'odd'`,
+			nil,
+		)
+		require.NoError(t, err)
+
+		cols, err := reader.Columns(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, Float64Type, cols[0].Type)
+		require.Equal(t, Float64Type, cols[1].Type)
+		require.Equal(t, IntegerType, cols[3].Type)
+
+		rows, err := ReadAllRows(context.Background(), reader)
+		require.NoError(t, err)
+		require.NoError(t, reader.Close())
+		require.Len(t, rows, 1)
+
+		require.Equal(t, 10.0, rows[0].ValuesByPosition[0].RawValue())
+		require.InDelta(t, 46.0, rows[0].ValuesByPosition[1].RawValue(), 1e-9)
+		require.InDelta(t, 46.0, rows[0].ValuesByPosition[2].RawValue(), 1e-9)
+		require.Equal(t, int64(50), rows[0].ValuesByPosition[3].RawValue())
+	})
+
+	t.Run("percentiles of empty groups are null", func(t *testing.T) {
+		rows, err := engine.queryAll(
+			context.Background(),
+			nil,
+			"SELECT PERCENTILE_CONT(0.5) WITHIN GROUP (ORDER BY latency), APPROX_PERCENTILE(latency, 0.5) FROM requests WHERE id > 100",
+			nil,
+		)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.True(t, rows[0].ValuesByPosition[0].IsNull())
+		require.True(t, rows[0].ValuesByPosition[1].IsNull())
+	})
+
+	t.Run("invalid percentiles", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, "SELECT PERCENTILE_CONT(1.5) WITHIN GROUP (ORDER BY latency) FROM requests", nil)
+		require.ErrorIs(t, err, ErrParsingError)
+		require.ErrorContains(t, err, "percentile must be between 0 and 1")
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT APPROX_PERCENTILE(latency, 2) FROM requests", nil)
+		require.ErrorIs(t, err, ErrParsingError)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT PERCENTILE_DISC(0.5) WITHIN GROUP (ORDER BY svc) FROM requests", nil)
+		require.ErrorIs(t, err, ErrInvalidTypes)
+	})
+
+	t.Run("approximate percentiles", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "CREATE TABLE samples (id INTEGER, val FLOAT, PRIMARY KEY id)", nil)
+		require.NoError(t, err)
+
+		const n = 10000
+
+		rnd := rand.New(rand.NewSource(1))
+		perm := rnd.Perm(n)
+
+		for b := 0; b < n/1000; b++ {
+			values := make([]string, 1000)
+			for i := range values {
+				id := b*1000 + i
+				values[i] = fmt.Sprintf("(%d, %d.0)", id, perm[id]+1)
+			}
+
+			_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO samples (id, val) VALUES "+strings.Join(values, ", "), nil)
+			require.NoError(t, err)
+		}
+
+		rows, err := engine.queryAll(
+			context.Background(),
+			nil,
+			`SELECT
+				APPROX_PERCENTILE(val, 0.5),
+				APPROX_PERCENTILE(val, 0.9),
+				APPROX_PERCENTILE(val, 0.99),
+				PERCENTILE_CONT(0.99) WITHIN GROUP (ORDER BY val)
+			FROM samples`,
+			nil,
+		)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+
+		// values are uniformly distributed within [1, n]
+		require.InDelta(t, 0.5*n, rows[0].ValuesByPosition[0].RawValue(), 0.01*n)
+		require.InDelta(t, 0.9*n, rows[0].ValuesByPosition[1].RawValue(), 0.01*n)
+		require.InDelta(t, 0.99*n, rows[0].ValuesByPosition[2].RawValue(), 0.005*n)
+		require.InDelta(t, 0.99*n, rows[0].ValuesByPosition[3].RawValue(), 1)
+	})
+
+	t.Run("approximate partial aggregations can be merged", func(t *testing.T) {
+		sel, err := newPercentileSelector(APPROX_PERCENTILE, 0.5, &ColSelector{col: "val"}, false)
+		require.NoError(t, err)
+
+		v1 := sel.aggregate.newValue("(samples.val)")
+		v2 := sel.aggregate.newValue("(samples.val)")
+
+		for i := 1; i <= 5000; i++ {
+			require.NoError(t, v1.updateWith(&Float64{val: float64(i)}))
+			require.NoError(t, v2.updateWith(&Float64{val: float64(i + 5000)}))
+		}
+
+		require.NoError(t, v1.mergeWith(v2))
+
+		val, err := v1.finalize()
+		require.NoError(t, err)
+		require.InDelta(t, 5000, val.RawValue(), 100)
+	})
+}
+
+func TestReservoirSampleAggregate(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, "CREATE TABLE events (id INTEGER AUTO_INCREMENT, kind VARCHAR, val INTEGER, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	values := make([]string, 20)
+	for i := range values {
+		values[i] = fmt.Sprintf("('k%d', %d)", i%2, i)
+	}
+
+	_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO events (kind, val) VALUES "+strings.Join(values, ", ")+", ('k0', NULL)", nil)
+	require.NoError(t, err)
+
+	sample := func(t *testing.T, query string) []interface{} {
+		rows, err := engine.queryAll(context.Background(), nil, query, nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+
+		if rows[0].ValuesByPosition[0].IsNull() {
+			return nil
+		}
+		return rows[0].ValuesByPosition[0].RawValue().([]interface{})
+	}
+
+	t.Run("sample size", func(t *testing.T) {
+		require.Len(t, sample(t, "SELECT RESERVOIR_SAMPLE(val, 5) FROM events"), 5)
+		require.Len(t, sample(t, "SELECT RESERVOIR_SAMPLE(val, 1) FROM events WHERE val < 3"), 1)
+
+		// NULL values are not sampled
+		require.ElementsMatch(t, []interface{}{int64(0), int64(2), int64(4)}, sample(t, "SELECT RESERVOIR_SAMPLE(val, 5) FROM events WHERE kind = 'k0' AND (val < 5 OR val IS NULL)"))
+		require.Len(t, sample(t, "SELECT RESERVOIR_SAMPLE(val, 100) FROM events"), 20)
+
+		require.Nil(t, sample(t, "SELECT RESERVOIR_SAMPLE(val, 5) FROM events WHERE id > 100"))
+	})
+
+	t.Run("samples per group", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT kind, RESERVOIR_SAMPLE(val, 3, 7), RESERVOIR_SAMPLE(kind, 2, 7) FROM events GROUP BY kind ORDER BY kind", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+
+		for i, row := range rows {
+			vals := row.ValuesByPosition[1].RawValue().([]interface{})
+			require.Len(t, vals, 3)
+
+			for _, v := range vals {
+				require.Equal(t, int64(i), v.(int64)%2)
+			}
+			require.Equal(t, []interface{}{row.ValuesByPosition[0].RawValue(), row.ValuesByPosition[0].RawValue()}, row.ValuesByPosition[2].RawValue())
+		}
+	})
+
+	t.Run("seeded samples", func(t *testing.T) {
+		require.Equal(t,
+			sample(t, "SELECT RESERVOIR_SAMPLE(val, 5, 42) FROM events"),
+			sample(t, "SELECT RESERVOIR_SAMPLE(val, 5, 42) FROM events"),
+		)
+
+		counts := make(map[int64]int)
+
+		for seed := 0; seed < 400; seed++ {
+			for _, v := range sample(t, fmt.Sprintf("SELECT RESERVOIR_SAMPLE(val, 5, %d) FROM events", seed)) {
+				counts[v.(int64)]++
+			}
+		}
+
+		// each value is expected to be sampled 400*5/20 = 100 times, with a standard deviation of ~8.7
+		require.Len(t, counts, 20)
+		for v, c := range counts {
+			require.InDelta(t, 100, c, 40, "value %d sampled %d times", v, c)
+		}
+	})
+
+	t.Run("invalid sample sizes", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, "SELECT RESERVOIR_SAMPLE(val, 0) FROM events", nil)
+		require.ErrorIs(t, err, ErrParsingError)
+		require.ErrorContains(t, err, "sample size must be between 1 and")
+	})
+}
+
+func TestNaturalJoinAndJoinUsing(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(
+		context.Background(),
+		nil,
+		`
+		CREATE TABLE employees (id INTEGER, name VARCHAR, dept_id INTEGER, PRIMARY KEY id);
+		CREATE TABLE depts (dept_id INTEGER, dept_name VARCHAR, PRIMARY KEY dept_id);
+		CREATE TABLE shifts (shift VARCHAR[16], PRIMARY KEY shift);
+
+		INSERT INTO employees (id, name, dept_id) VALUES (1, 'alice', 1), (2, 'bob', 2), (3, 'carol', 3);
+		INSERT INTO depts (dept_id, dept_name) VALUES (1, 'sales'), (2, 'engineering');
+		INSERT INTO shifts (shift) VALUES ('day'), ('night');
+		`,
+		nil,
+	)
+	require.NoError(t, err)
+
+	columnNames := func(t *testing.T, query string) []string {
+		reader, err := engine.Query(context.Background(), nil, query, nil)
+		require.NoError(t, err)
+		defer reader.Close()
+
+		cols, err := reader.Columns(context.Background())
+		require.NoError(t, err)
+
+		names := make([]string, len(cols))
+		for i, col := range cols {
+			names[i] = col.Column
+		}
+		return names
+	}
+
+	t.Run("join using", func(t *testing.T) {
+		query := "SELECT * FROM employees JOIN depts USING (dept_id) ORDER BY id"
+
+		require.Equal(t, []string{"id", "name", "dept_id", "dept_name"}, columnNames(t, query))
+
+		rows, err := engine.queryAll(context.Background(), nil, query, nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+
+		require.Len(t, rows[0].ValuesByPosition, 4)
+		require.Equal(t, "alice", rows[0].ValuesByPosition[1].RawValue())
+		require.Equal(t, int64(1), rows[0].ValuesByPosition[2].RawValue())
+		require.Equal(t, "sales", rows[0].ValuesByPosition[3].RawValue())
+		require.Equal(t, "engineering", rows[1].ValuesByPosition[3].RawValue())
+	})
+
+	t.Run("left join using", func(t *testing.T) {
+		rows, err := engine.queryAll(
+			context.Background(),
+			nil,
+			"SELECT id, dept_id, depts.dept_id, depts.dept_name FROM employees LEFT JOIN depts USING (dept_id) ORDER BY id",
+			nil,
+		)
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+
+		require.Equal(t, int64(3), rows[2].ValuesByPosition[1].RawValue())
+		require.True(t, rows[2].ValuesByPosition[2].IsNull())
+		require.True(t, rows[2].ValuesByPosition[3].IsNull())
+	})
+
+	t.Run("natural join", func(t *testing.T) {
+		query := "SELECT * FROM employees NATURAL JOIN depts ORDER BY id"
+
+		require.Equal(t, []string{"id", "name", "dept_id", "dept_name"}, columnNames(t, query))
+
+		rows, err := engine.queryAll(context.Background(), nil, query, nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		require.Equal(t, "bob", rows[1].ValuesByPosition[1].RawValue())
+		require.Equal(t, "engineering", rows[1].ValuesByPosition[3].RawValue())
+	})
+
+	t.Run("natural join with no common columns is a cross join", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT * FROM employees NATURAL JOIN shifts", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 6)
+		require.Len(t, rows[0].ValuesByPosition, 4)
+	})
+
+	t.Run("join using an unknown column", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, "SELECT * FROM employees JOIN depts USING (dept_name)", nil)
+		require.ErrorIs(t, err, ErrColumnDoesNotExist)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT * FROM employees JOIN depts USING (dept_id, dept_id)", nil)
+		require.ErrorIs(t, err, ErrDuplicatedColumn)
+	})
+}
+
+func TestLateralJoins(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(
+		context.Background(),
+		nil,
+		`
+		CREATE TABLE users (id INTEGER, name VARCHAR, PRIMARY KEY id);
+		CREATE TABLE posts (id INTEGER AUTO_INCREMENT, user_id INTEGER, title VARCHAR, created INTEGER, PRIMARY KEY id);
+		CREATE INDEX ON posts (user_id);
+
+		INSERT INTO users (id, name) VALUES (1, 'alice'), (2, 'bob'), (3, 'carol');
+		INSERT INTO posts (user_id, title, created) VALUES (1, 'a1', 10), (1, 'a2', 20), (2, 'b1', 5), (1, 'a0', 1);
+		`,
+		nil,
+	)
+	require.NoError(t, err)
+
+	rawValues := func(t *testing.T, query string, params map[string]interface{}) [][]interface{} {
+		rows, err := engine.queryAll(context.Background(), nil, query, params)
+		require.NoError(t, err)
+
+		values := make([][]interface{}, len(rows))
+		for i, row := range rows {
+			for _, v := range row.ValuesByPosition {
+				values[i] = append(values[i], v.RawValue())
+			}
+		}
+		return values
+	}
+
+	t.Run("top-1 per row", func(t *testing.T) {
+		require.Equal(t, [][]interface{}{
+			{int64(1), "a2"},
+			{int64(2), "b1"},
+		}, rawValues(t, "SELECT u.id, top.title FROM users u, LATERAL (SELECT title FROM posts p WHERE p.user_id = u.id ORDER BY p.created DESC LIMIT 1) top", nil))
+
+		require.Equal(t, [][]interface{}{
+			{"alice", "a2"},
+			{"alice", "a1"},
+			{"bob", "b1"},
+			{"carol", nil},
+		}, rawValues(t, "SELECT u.name, top.title FROM users u LEFT JOIN LATERAL (SELECT title FROM posts p WHERE p.user_id = u.id ORDER BY p.created DESC LIMIT 2) AS top ON true", nil))
+	})
+
+	t.Run("aggregations per row", func(t *testing.T) {
+		require.Equal(t, [][]interface{}{
+			{"alice", int64(3)},
+			{"bob", int64(1)},
+			{"carol", int64(0)},
+		}, rawValues(t, "SELECT u.name, c.n FROM users u, LATERAL (SELECT COUNT(*) AS n FROM posts WHERE posts.user_id = u.id) c", nil))
+	})
+
+	t.Run("correlation resolution", func(t *testing.T) {
+		// unqualified columns refer to the data sources of the subquery
+		require.Equal(t, [][]interface{}{
+			{"alice", int64(2), "a2"},
+			{"bob", int64(3), "b1"},
+		}, rawValues(t, "SELECT u.name, p.id, p.title FROM users u, LATERAL (SELECT id, title FROM posts WHERE user_id = u.id AND created > @since) p WHERE p.id > 1", map[string]interface{}{"since": 4}))
+
+		// later subqueries may refer to the preceding ones
+		require.Equal(t, [][]interface{}{
+			{"alice", "a1"},
+			{"bob", nil},
+		}, rawValues(t, `
+			SELECT u.name, prev.title
+			FROM users u
+			JOIN LATERAL (SELECT created FROM posts WHERE posts.user_id = u.id ORDER BY created DESC LIMIT 1) AS last ON true
+			LEFT JOIN LATERAL (SELECT title FROM posts WHERE posts.user_id = u.id AND posts.created < last.created ORDER BY created DESC LIMIT 1) AS prev ON true
+			WHERE u.id < 3`, nil))
+
+		_, err := engine.queryAll(context.Background(), nil, "SELECT * FROM users u, LATERAL (SELECT title FROM posts WHERE posts.user_id = x.id) p", nil)
+		require.ErrorIs(t, err, ErrColumnDoesNotExist)
+
+		// only LATERAL subqueries may refer to the preceding data sources
+		_, err = engine.queryAll(context.Background(), nil, "SELECT * FROM users u JOIN (SELECT title FROM posts WHERE posts.user_id = u.id) p ON true", nil)
+		require.ErrorIs(t, err, ErrColumnDoesNotExist)
+	})
+}
+
+func TestFullTextSearch(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(
+		context.Background(),
+		nil,
+		`
+		CREATE TABLE docs (id INTEGER, body VARCHAR, amount INTEGER, PRIMARY KEY id);
+
+		INSERT INTO docs (id, body) VALUES
+			(1, 'The quick brown fox'),
+			(2, 'jumps over the lazy dog'),
+			(3, 'A QUICK, brown dog!');
+		`,
+		nil,
+	)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE FULLTEXT INDEX ON docs(body)", nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO docs (id, body) VALUES (4, 'brown bread'), (5, NULL)", nil)
+	require.NoError(t, err)
+
+	matchingIDs := func(t *testing.T, query string) []int64 {
+		params := map[string]interface{}{"query": query}
+
+		reader, err := engine.Query(context.Background(), nil, "SELECT id FROM docs WHERE body MATCH @query", params)
+		require.NoError(t, err)
+		require.NotNil(t, reader.ScanSpecs().fullTextMatch)
+
+		rows, err := ReadAllRows(context.Background(), reader)
+		require.NoError(t, err)
+
+		err = reader.Close()
+		require.NoError(t, err)
+
+		ids := make([]int64, len(rows))
+		for i, row := range rows {
+			ids[i] = row.ValuesByPosition[0].RawValue().(int64)
+		}
+		return ids
+	}
+
+	t.Run("single term", func(t *testing.T) {
+		require.Equal(t, []int64{1, 3, 4}, matchingIDs(t, "brown"))
+		require.Equal(t, []int64{2, 3}, matchingIDs(t, "dog"))
+		require.Empty(t, matchingIDs(t, "cat"))
+	})
+
+	t.Run("multiple terms", func(t *testing.T) {
+		require.Equal(t, []int64{1, 3}, matchingIDs(t, "quick brown"))
+		require.Equal(t, []int64{3}, matchingIDs(t, "Dog, quick"))
+		require.Empty(t, matchingIDs(t, "quick lazy"))
+		require.Empty(t, matchingIDs(t, "  "))
+	})
+
+	t.Run("combined with other conditions", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT id FROM docs WHERE body MATCH 'brown' AND id > 1 ORDER BY id DESC", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		require.Equal(t, int64(4), rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, int64(3), rows[1].ValuesByPosition[0].RawValue())
+	})
+
+	t.Run("index maintenance on update", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "UPDATE docs SET body = 'slow brown turtle' WHERE id = 1", nil)
+		require.NoError(t, err)
+
+		require.Equal(t, []int64{3}, matchingIDs(t, "quick"))
+		require.Equal(t, []int64{1}, matchingIDs(t, "turtle"))
+		require.Equal(t, []int64{1, 3, 4}, matchingIDs(t, "brown"))
+
+		_, _, err = engine.Exec(context.Background(), nil, "UPDATE docs SET amount = 10 WHERE id = 4", nil)
+		require.NoError(t, err)
+
+		require.Equal(t, []int64{4}, matchingIDs(t, "bread"))
+
+		_, _, err = engine.Exec(context.Background(), nil, "UPSERT INTO docs (id, body) VALUES (5, 'fresh bread')", nil)
+		require.NoError(t, err)
+
+		require.Equal(t, []int64{4, 5}, matchingIDs(t, "bread"))
+	})
+
+	t.Run("index maintenance on delete", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "DELETE FROM docs WHERE id = 3", nil)
+		require.NoError(t, err)
+
+		require.Empty(t, matchingIDs(t, "quick"))
+		require.Equal(t, []int64{1, 4}, matchingIDs(t, "brown"))
+
+		_, _, err = engine.Exec(context.Background(), nil, "DELETE FROM docs WHERE id >= 4", nil)
+		require.NoError(t, err)
+
+		require.Empty(t, matchingIDs(t, "bread"))
+	})
+
+	t.Run("invalid indexes", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "CREATE FULLTEXT INDEX ON docs(body)", nil)
+		require.ErrorIs(t, err, ErrIndexAlreadyExists)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE FULLTEXT INDEX IF NOT EXISTS ON docs(body)", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE FULLTEXT INDEX ON docs(amount)", nil)
+		require.ErrorIs(t, err, ErrInvalidTypes)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE docs DROP COLUMN body", nil)
+		require.ErrorIs(t, err, ErrCannotDropColumn)
+	})
+
+	t.Run("index created along with the table", func(t *testing.T) {
+		_, _, err := engine.Exec(
+			context.Background(),
+			nil,
+			`
+			CREATE TABLE notes (id INTEGER, content VARCHAR, PRIMARY KEY id);
+			CREATE FULLTEXT INDEX ON notes(content);
+			INSERT INTO notes (id, content) VALUES (1, 'lorem ipsum'), (2, 'dolor sit amet');
+			`,
+			nil,
+		)
+		require.NoError(t, err)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT id FROM notes WHERE content MATCH 'AMET'", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, int64(2), rows[0].ValuesByPosition[0].RawValue())
+	})
+
+	t.Run("drop index", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "DROP FULLTEXT INDEX ON docs(body)", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "DROP FULLTEXT INDEX ON docs(body)", nil)
+		require.ErrorIs(t, err, ErrIndexNotFound)
+
+		reader, err := engine.Query(context.Background(), nil, "SELECT id FROM docs WHERE body MATCH 'turtle'", nil)
+		require.NoError(t, err)
+		require.Nil(t, reader.ScanSpecs().fullTextMatch)
+
+		rows, err := ReadAllRows(context.Background(), reader)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+
+		err = reader.Close()
+		require.NoError(t, err)
+	})
+}
+
+func TestPointWithinBox(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(
+		context.Background(),
+		nil,
+		`
+		CREATE TABLE places (id INTEGER, name VARCHAR, loc POINT, PRIMARY KEY id);
+
+		INSERT INTO places (id, name, loc) VALUES
+			(1, 'rome', POINT(41.9028, 12.4964)),
+			(2, 'sydney', POINT(-33.8688, 151.2093)),
+			(3, 'suva', POINT(-18.1416, 178.4419)),
+			(4, 'apia', POINT(-13.8507, -171.7514)),
+			(5, 'corner', POINT(10, 20)),
+			(6, 'unknown', NULL),
+			(7, 'origin', POINT(0, 0)),
+			(8, 'east', POINT(0, 180)),
+			(9, 'west', POINT(0, -180));
+		`,
+		nil,
+	)
+	require.NoError(t, err)
+
+	withinBox := func(t *testing.T, minLat, minLon, maxLat, maxLon float64) []int64 {
+		params := map[string]interface{}{
+			"minLat": minLat,
+			"minLon": minLon,
+			"maxLat": maxLat,
+			"maxLon": maxLon,
+		}
+
+		rows, err := engine.queryAll(
+			context.Background(),
+			nil,
+			"SELECT id FROM places WHERE loc WITHIN BOX(@minLat, @minLon, @maxLat, @maxLon)",
+			params,
+		)
+		require.NoError(t, err)
+
+		ids := make([]int64, len(rows))
+		for i, row := range rows {
+			ids[i] = row.ValuesByPosition[0].RawValue().(int64)
+		}
+		return ids
+	}
+
+	t.Run("points inside and outside the box", func(t *testing.T) {
+		require.Equal(t, []int64{1, 5}, withinBox(t, 5, 5, 45, 25))
+		require.Equal(t, []int64{2}, withinBox(t, -40, 140, -30, 160))
+		require.Empty(t, withinBox(t, 60, 0, 70, 10))
+	})
+
+	t.Run("boundaries are part of the box", func(t *testing.T) {
+		require.Equal(t, []int64{1, 5, 7}, withinBox(t, 0, 0, 41.9028, 20))
+		require.Equal(t, []int64{5}, withinBox(t, 10, 20, 10, 20))
+		require.Equal(t, []int64{7, 8, 9}, withinBox(t, 0, -180, 0, 180))
+	})
+
+	t.Run("boxes crossing the antimeridian", func(t *testing.T) {
+		// the minimum longitude is greater than the maximum one
+		require.Equal(t, []int64{3, 4, 8, 9}, withinBox(t, -20, 170, 0, -170))
+		require.Equal(t, []int64{3, 4}, withinBox(t, -20, 170, -10, -170))
+		require.Equal(t, []int64{3}, withinBox(t, -20, 175, -10, -175))
+		require.Equal(t, []int64{8, 9}, withinBox(t, 0, 180, 0, -180))
+	})
+
+	t.Run("null points are never inside the box", func(t *testing.T) {
+		rows, err := engine.queryAll(
+			context.Background(),
+			nil,
+			"SELECT id FROM places WHERE NOT loc WITHIN BOX(-90, -180, 0, 180) ORDER BY id",
+			nil,
+		)
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		require.Equal(t, int64(1), rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, int64(5), rows[1].ValuesByPosition[0].RawValue())
+		require.Equal(t, int64(6), rows[2].ValuesByPosition[0].RawValue())
+	})
+
+	t.Run("points should be returned and compared", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT loc FROM places WHERE id = 1", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, PointType, rows[0].ValuesByPosition[0].Type())
+		require.Equal(t, GeoPoint{Lat: 41.9028, Lon: 12.4964}, rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, "POINT(41.9028, 12.4964)", rows[0].ValuesByPosition[0].String())
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT id FROM places WHERE loc = POINT(0, 180)", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, int64(8), rows[0].ValuesByPosition[0].RawValue())
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT id FROM places WHERE id <> 6 ORDER BY loc", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 8)
+
+		ids := make([]int64, len(rows))
+		for i, row := range rows {
+			ids[i] = row.ValuesByPosition[0].RawValue().(int64)
+		}
+		require.Equal(t, []int64{2, 3, 4, 9, 7, 8, 5, 1}, ids)
+	})
+
+	t.Run("points should be indexable", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "CREATE INDEX ON places(loc)", nil)
+		require.NoError(t, err)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT id FROM places USE INDEX ON (loc) WHERE id <> 6", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 8)
+
+		ids := make([]int64, len(rows))
+		for i, row := range rows {
+			ids[i] = row.ValuesByPosition[0].RawValue().(int64)
+		}
+		require.Equal(t, []int64{2, 3, 4, 9, 7, 8, 5, 1}, ids)
+	})
+
+	t.Run("points should be accepted as parameters", func(t *testing.T) {
+		_, _, err := engine.Exec(
+			context.Background(),
+			nil,
+			"INSERT INTO places (id, name, loc) VALUES (10, 'oslo', @loc), (11, 'lima', POINT(@lat, @lon))",
+			map[string]interface{}{
+				"loc": GeoPoint{Lat: 59.9139, Lon: 10.7522},
+				"lat": -12.0464,
+				"lon": -77.0428,
+			},
+		)
+		require.NoError(t, err)
+
+		require.Equal(t, []int64{10}, withinBox(t, 50, 0, 60, 20))
+		require.Equal(t, []int64{11}, withinBox(t, -15, -80, -10, -70))
+	})
+
+	t.Run("invalid points and boxes should fail", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "INSERT INTO places (id, loc) VALUES (20, POINT(91, 0))", nil)
+		require.ErrorIs(t, err, ErrInvalidValue)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO places (id, loc) VALUES (20, POINT(0, -180.5))", nil)
+		require.ErrorIs(t, err, ErrInvalidValue)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO places (id, loc) VALUES (20, POINT('north', 0))", nil)
+		require.ErrorIs(t, err, ErrInvalidTypes)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO places (id, loc) VALUES (20, 'POINT(0, 0)')", nil)
+		require.ErrorIs(t, err, ErrInvalidValue)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT id FROM places WHERE loc WITHIN BOX(10, 0, -10, 10)", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT id FROM places WHERE loc WITHIN BOX(-100, 0, 10, 10)", nil)
+		require.ErrorIs(t, err, ErrInvalidValue)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT id FROM places WHERE id WITHIN BOX(0, 0, 10, 10)", nil)
+		require.ErrorIs(t, err, ErrInvalidTypes)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT id FROM places WHERE loc WITHIN BOX(0, 0, 'ten', 10)", nil)
+		require.ErrorIs(t, err, ErrInvalidTypes)
+	})
+}
+
+func TestIsDistinctFrom(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(
+		context.Background(),
+		nil,
+		`
+		CREATE TABLE pairs (id INTEGER, a INTEGER, b INTEGER, PRIMARY KEY id);
+
+		INSERT INTO pairs (id, a, b) VALUES
+			(1, NULL, NULL),
+			(2, NULL, 1),
+			(3, 1, NULL),
+			(4, 1, 1),
+			(5, 1, 2);
+		`,
+		nil,
+	)
+	require.NoError(t, err)
+
+	t.Run("truth table", func(t *testing.T) {
+		rows, err := engine.queryAll(
+			context.Background(),
+			nil,
+			"SELECT id, a IS DISTINCT FROM b, a IS NOT DISTINCT FROM b FROM pairs ORDER BY id",
+			nil,
+		)
+		require.NoError(t, err)
+		require.Len(t, rows, 5)
+
+		expected := []struct {
+			distinct    bool
+			notDistinct bool
+		}{
+			{distinct: false, notDistinct: true}, // NULL, NULL
+			{distinct: true, notDistinct: false}, // NULL, value
+			{distinct: true, notDistinct: false}, // value, NULL
+			{distinct: false, notDistinct: true}, // equal values
+			{distinct: true, notDistinct: false}, // different values
+		}
+
+		for i, row := range rows {
+			require.Equal(t, BooleanType, row.ValuesByPosition[1].Type())
+			require.False(t, row.ValuesByPosition[1].IsNull())
+			require.Equal(t, expected[i].distinct, row.ValuesByPosition[1].RawValue(), "row %d", i+1)
+
+			require.Equal(t, BooleanType, row.ValuesByPosition[2].Type())
+			require.False(t, row.ValuesByPosition[2].IsNull())
+			require.Equal(t, expected[i].notDistinct, row.ValuesByPosition[2].RawValue(), "row %d", i+1)
+		}
+	})
+
+	t.Run("literal operands", func(t *testing.T) {
+		rows, err := engine.queryAll(
+			context.Background(),
+			nil,
+			"SELECT NULL IS NOT DISTINCT FROM NULL, NULL IS DISTINCT FROM 1, 1 IS DISTINCT FROM 1.0, @p IS NOT DISTINCT FROM NULL FROM pairs WHERE id = 1",
+			map[string]interface{}{"p": nil},
+		)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, true, rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, true, rows[0].ValuesByPosition[1].RawValue())
+		require.Equal(t, false, rows[0].ValuesByPosition[2].RawValue())
+		require.Equal(t, true, rows[0].ValuesByPosition[3].RawValue())
+	})
+
+	t.Run("in where clauses", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT id FROM pairs WHERE a IS NOT DISTINCT FROM b ORDER BY id", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		require.Equal(t, int64(1), rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, int64(4), rows[1].ValuesByPosition[0].RawValue())
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT id FROM pairs WHERE NOT (a IS DISTINCT FROM @v) ORDER BY id", map[string]interface{}{"v": 1})
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		require.Equal(t, int64(3), rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, int64(4), rows[1].ValuesByPosition[0].RawValue())
+		require.Equal(t, int64(5), rows[2].ValuesByPosition[0].RawValue())
+	})
+
+	t.Run("null-safe joins", func(t *testing.T) {
+		rows, err := engine.queryAll(
+			context.Background(),
+			nil,
+			"SELECT p1.id, p2.id, p2.b FROM pairs AS p1 INNER JOIN pairs AS p2 ON p1.a IS NOT DISTINCT FROM p2.b WHERE p1.a IS NULL",
+			nil,
+		)
+		require.NoError(t, err)
+		require.Len(t, rows, 4)
+
+		for _, row := range rows {
+			require.Contains(t, []int64{1, 2}, row.ValuesByPosition[0].RawValue())
+			require.Contains(t, []int64{1, 3}, row.ValuesByPosition[1].RawValue())
+			require.True(t, row.ValuesByPosition[2].IsNull())
+		}
+	})
+
+	t.Run("incompatible operands should fail", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, "SELECT id FROM pairs WHERE a IS DISTINCT FROM 'one'", nil)
+		require.ErrorIs(t, err, ErrNotComparableValues)
+	})
+}
+
+func TestSortedWritesUnderContention(t *testing.T) {
+	const (
+		accounts      = 16
+		workers       = 8
+		txsPerWorker  = 10
+		rowsPerUpdate = 4
+	)
+
+	contend := func(t *testing.T, sortedWrites bool) (committed, aborted int) {
+		engine, st := setupCommonTestWithOptions(t, store.DefaultOptions().WithMaxConcurrency(workers))
+
+		_, _, err := engine.Exec(context.Background(), nil, "CREATE TABLE accounts (id INTEGER, balance INTEGER, PRIMARY KEY id)", nil)
+		require.NoError(t, err)
+
+		for id := 0; id < accounts; id++ {
+			_, _, err := engine.Exec(context.Background(), nil, "INSERT INTO accounts (id, balance) VALUES (@id, 0)", map[string]interface{}{"id": id})
+			require.NoError(t, err)
+		}
+
+		var mu sync.Mutex
+		var committedTxIDs []uint64
+
+		var wg sync.WaitGroup
+
+		for w := 0; w < workers; w++ {
+			wg.Add(1)
+
+			go func(seed int64) {
+				defer wg.Done()
+
+				rnd := rand.New(rand.NewSource(seed))
+
+				for i := 0; i < txsPerWorker; i++ {
+					opts := DefaultTxOptions().WithExplicitClose(true).WithSortedWrites(sortedWrites)
+
+					tx, err := engine.NewTx(context.Background(), opts)
+					require.NoError(t, err)
+
+					// rows are updated in a different order by each transaction
+					for _, id := range rnd.Perm(accounts)[:rowsPerUpdate] {
+						_, _, err = engine.Exec(context.Background(), tx, "UPDATE accounts SET balance = balance + 1 WHERE id = @id", map[string]interface{}{"id": id})
+						require.NoError(t, err)
+					}
+
+					err = tx.Commit(context.Background())
+
+					mu.Lock()
+					if errors.Is(err, store.ErrTxReadConflict) {
+						aborted++
+					} else {
+						require.NoError(t, err)
+						committed++
+						committedTxIDs = append(committedTxIDs, tx.TxHeader().ID)
+					}
+					mu.Unlock()
+				}
+			}(int64(w))
+		}
+
+		wg.Wait()
+
+		// aborted transactions must not leave partial updates
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT SUM(balance) FROM accounts", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, int64(committed*rowsPerUpdate), rows[0].ValuesByPosition[0].RawValue())
+
+		txHolder := store.NewTx(st.MaxTxEntries(), st.MaxKeyLen())
+
+		for _, txID := range committedTxIDs {
+			err := st.ReadTx(txID, false, txHolder)
+			require.NoError(t, err)
+
+			entries := txHolder.Entries()
+			require.Len(t, entries, rowsPerUpdate)
+
+			isSorted := slices.IsSortedFunc(entries, func(e1, e2 *store.TxEntry) int {
+				return bytes.Compare(e1.Key(), e2.Key())
+			})
+			if sortedWrites {
+				require.True(t, isSorted)
+			}
+		}
+
+		return committed, aborted
+	}
+
+	for _, sortedWrites := range []bool{false, true} {
+		t.Run(fmt.Sprintf("sorted writes %v", sortedWrites), func(t *testing.T) {
+			committed, aborted := contend(t, sortedWrites)
+			require.Equal(t, workers*txsPerWorker, committed+aborted)
+			require.Positive(t, committed)
+
+			t.Logf("sorted writes %v: %d committed, %d aborted (%.1f%% abort rate)",
+				sortedWrites, committed, aborted, 100*float64(aborted)/float64(committed+aborted))
+		})
+	}
+}
+
+func TestColumnsMetadata(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(
+		context.Background(),
+		nil,
+		`
+		CREATE TABLE products (
+			id INTEGER AUTO_INCREMENT,
+			sku VARCHAR[16] NOT NULL,
+			name VARCHAR,
+			price FLOAT NOT NULL,
+			picture BLOB[64],
+			PRIMARY KEY id
+		);
+
+		CREATE TABLE stock (
+			warehouse VARCHAR[8],
+			sku VARCHAR[16],
+			amount INTEGER,
+			PRIMARY KEY (warehouse, sku)
+		);
+		`,
+		nil,
+	)
+	require.NoError(t, err)
+
+	columns := func(t *testing.T, sql string) []ColDescriptor {
+		reader, err := engine.Query(context.Background(), nil, sql, nil)
+		require.NoError(t, err)
+		defer reader.Close()
+
+		cols, err := reader.Columns(context.Background())
+		require.NoError(t, err)
+		return cols
+	}
+
+	t.Run("table columns", func(t *testing.T) {
+		require.Equal(t, []ColDescriptor{
+			{Table: "products", Column: "id", Type: IntegerType, PrimaryKey: true},
+			{Table: "products", Column: "sku", Type: VarcharType, MaxLen: 16},
+			{Table: "products", Column: "name", Type: VarcharType, Nullable: true},
+			{Table: "products", Column: "price", Type: Float64Type},
+			{Table: "products", Column: "picture", Type: BLOBType, MaxLen: 64, Nullable: true},
+		}, columns(t, "SELECT * FROM products"))
+
+		require.Equal(t, []ColDescriptor{
+			{Table: "s", Column: "warehouse", Type: VarcharType, MaxLen: 8, PrimaryKey: true},
+			{Table: "s", Column: "sku", Type: VarcharType, MaxLen: 16, PrimaryKey: true},
+			{Table: "s", Column: "amount", Type: IntegerType, Nullable: true},
+		}, columns(t, "SELECT * FROM stock AS s"))
+	})
+
+	t.Run("projected columns", func(t *testing.T) {
+		require.Equal(t, []ColDescriptor{
+			{Table: "products", Column: "code", Type: VarcharType, MaxLen: 16},
+			{Table: "products", Column: "id", Type: IntegerType, PrimaryKey: true},
+			{Table: "products", Column: "col2", Type: Float64Type, Nullable: true},
+		}, columns(t, "SELECT sku AS code, id, price * 2 FROM products"))
+	})
+
+	t.Run("aggregated columns", func(t *testing.T) {
+		require.Equal(t, []ColDescriptor{
+			{Table: "stock", Column: "warehouse", Type: VarcharType, MaxLen: 8, PrimaryKey: true},
+			{Table: "stock", Column: "col1", Type: IntegerType},
+			{Table: "stock", Column: "col2", Type: IntegerType, Nullable: true},
+		}, columns(t, "SELECT warehouse, COUNT(*), SUM(amount) FROM stock GROUP BY warehouse"))
+	})
+
+	t.Run("joined columns", func(t *testing.T) {
+		require.Equal(t, []ColDescriptor{
+			{Table: "p", Column: "name", Type: VarcharType, Nullable: true},
+			{Table: "s", Column: "sku", Type: VarcharType, MaxLen: 16, PrimaryKey: true},
+			{Table: "s", Column: "amount", Type: IntegerType, Nullable: true},
+		}, columns(t, "SELECT p.name, s.sku, s.amount FROM products AS p INNER JOIN stock AS s ON p.sku = s.sku"))
+	})
+
+	t.Run("values", func(t *testing.T) {
+		require.Equal(t, []ColDescriptor{
+			{Table: "values", Column: "col0", Type: IntegerType, Nullable: true},
+		}, columns(t, "SELECT * FROM (VALUES (1), (2))"))
+	})
+}
+
+func TestInformationSchema(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(
+		context.Background(),
+		nil,
+		`
+		CREATE TABLE accounts (
+			id INTEGER AUTO_INCREMENT,
+			owner VARCHAR[64] NOT NULL,
+			balance FLOAT,
+			notes VARCHAR,
+			PRIMARY KEY id
+		);
+		`,
+		nil,
+	)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(
+		context.Background(),
+		nil,
+		`
+		CREATE UNIQUE INDEX ON accounts(owner);
+
+		CREATE VIEW rich_accounts AS SELECT * FROM accounts WHERE balance > 1000;
+		`,
+		nil,
+	)
+	require.NoError(t, err)
+
+	t.Run("columns", func(t *testing.T) {
+		rows, err := engine.queryAll(
+			context.Background(),
+			nil,
+			"SELECT column_name, data_type, is_nullable, character_maximum_length, is_primary_key, columns.is_auto_increment FROM information_schema.columns WHERE table_name = @table ORDER BY ordinal_position",
+			map[string]interface{}{"table": "accounts"},
+		)
+		require.NoError(t, err)
+		require.Len(t, rows, 4)
+
+		type colInfo struct {
+			name, typ, nullable string
+			maxLen              interface{}
+			pk, autoIncrement   bool
+		}
+
+		expected := []colInfo{
+			{"id", IntegerType, "NO", nil, true, true},
+			{"owner", VarcharType, "NO", int64(64), false, false},
+			{"balance", Float64Type, "YES", nil, false, false},
+			{"notes", VarcharType, "YES", nil, false, false},
+		}
+
+		for i, row := range rows {
+			vals := row.ValuesByPosition
+
+			require.Equal(t, expected[i].name, vals[0].RawValue())
+			require.Equal(t, expected[i].typ, vals[1].RawValue())
+			require.Equal(t, expected[i].nullable, vals[2].RawValue())
+			require.Equal(t, expected[i].maxLen, vals[3].RawValue())
+			require.Equal(t, expected[i].pk, vals[4].RawValue())
+			require.Equal(t, expected[i].autoIncrement, vals[5].RawValue())
+		}
+	})
+
+	t.Run("tables", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT table_name, table_type FROM information_schema.tables t ORDER BY t.table_name", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+
+		require.Equal(t, "accounts", rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, "BASE TABLE", rows[0].ValuesByPosition[1].RawValue())
+		require.Equal(t, "rich_accounts", rows[1].ValuesByPosition[0].RawValue())
+		require.Equal(t, "VIEW", rows[1].ValuesByPosition[1].RawValue())
+	})
+
+	t.Run("indexes", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT index_name, column_names, is_unique, is_primary FROM information_schema.indexes WHERE table_name = 'accounts'", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+
+		require.Equal(t, "accounts(id)", rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, "id", rows[0].ValuesByPosition[1].RawValue())
+		require.Equal(t, true, rows[0].ValuesByPosition[2].RawValue())
+		require.Equal(t, true, rows[0].ValuesByPosition[3].RawValue())
+
+		require.Equal(t, "accounts(owner)", rows[1].ValuesByPosition[0].RawValue())
+		require.Equal(t, "owner", rows[1].ValuesByPosition[1].RawValue())
+		require.Equal(t, true, rows[1].ValuesByPosition[2].RawValue())
+		require.Equal(t, false, rows[1].ValuesByPosition[3].RawValue())
+	})
+
+	t.Run("the catalog of the transaction should be exposed", func(t *testing.T) {
+		tx, _, err := engine.Exec(context.Background(), nil, "BEGIN TRANSACTION; CREATE TABLE pending (id INTEGER, PRIMARY KEY id);", nil)
+		require.NoError(t, err)
+		defer tx.Cancel()
+
+		rows, err := engine.queryAll(context.Background(), tx, "SELECT COUNT(*) FROM information_schema.columns WHERE table_name = 'pending'", nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), rows[0].ValuesByPosition[0].RawValue())
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT COUNT(*) FROM information_schema.columns WHERE table_name = 'pending'", nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(0), rows[0].ValuesByPosition[0].RawValue())
+	})
+
+	t.Run("unknown views should fail", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, "SELECT * FROM information_schema.sequences", nil)
+		require.ErrorIs(t, err, ErrTableDoesNotExist)
+	})
+}
+
+func TestFilteredQueryDoesNotStartGoroutines(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, "CREATE TABLE items (id INTEGER AUTO_INCREMENT, n INTEGER, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithExplicitClose(true))
+	require.NoError(t, err)
+
+	for i := 0; i < 1000; i++ {
+		_, _, err = engine.Exec(context.Background(), tx, "INSERT INTO items (n) VALUES (@n)", map[string]interface{}{"n": i})
+		require.NoError(t, err)
+	}
+
+	err = tx.Commit(context.Background())
+	require.NoError(t, err)
+
+	r, err := engine.Query(context.Background(), nil, "SELECT id FROM items WHERE n % 3 = 0 AND n > @min", map[string]interface{}{"min": 10})
+	require.NoError(t, err)
+	defer r.Close()
+
+	// WHERE conditions are evaluated by the goroutine reading the rows
+	baseline := runtime.NumGoroutine()
+
+	var count int
+
+	for {
+		_, err := r.Read(context.Background())
+		if errors.Is(err, ErrNoMoreRows) {
+			break
+		}
+		require.NoError(t, err)
+
+		require.LessOrEqual(t, runtime.NumGoroutine(), baseline)
+		count++
+	}
+
+	require.Equal(t, 330, count)
+}
+
+func TestConditionEvaluationCancellation(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, "CREATE TABLE items (id INTEGER AUTO_INCREMENT, n INTEGER, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithExplicitClose(true))
+	require.NoError(t, err)
+
+	for i := 0; i < 1000; i++ {
+		_, _, err = engine.Exec(context.Background(), tx, "INSERT INTO items (n) VALUES (@n)", map[string]interface{}{"n": i})
+		require.NoError(t, err)
+	}
+
+	err = tx.Commit(context.Background())
+	require.NoError(t, err)
+
+	var evaluations atomic.Int64
+
+	err = engine.RegisterFunction("expensive", ScalarFunc{
+		ArgTypes:   []SQLValueType{IntegerType},
+		ReturnType: IntegerType,
+		Eval: func(args []TypedValue) (TypedValue, error) {
+			evaluations.Add(1)
+			time.Sleep(time.Millisecond)
+			return args[0], nil
+		},
+	})
+	require.NoError(t, err)
+
+	t.Run("evaluation should not start with a done context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		exp := &FnCall{fn: "expensive", params: []ValueExp{&Integer{val: 1}}, functions: engine.functions}
+
+		_, err := exp.reduceCtx(ctx, nil, nil, "")
+		require.ErrorIs(t, err, context.Canceled)
+		require.Zero(t, evaluations.Load())
+
+		v, err := exp.reduce(nil, nil, "")
+		require.NoError(t, err)
+		require.Equal(t, int64(1), v.RawValue())
+	})
+
+	t.Run("filtering should be interrupted once the context is done", func(t *testing.T) {
+		evaluations.Store(0)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		// no row satisfies the condition, a full evaluation takes about a second
+		r, err := engine.Query(ctx, nil, "SELECT id FROM items WHERE expensive(n) < 0", nil)
+		require.NoError(t, err)
+		defer r.Close()
+
+		start := time.Now()
+
+		_, err = r.Read(ctx)
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Less(t, time.Since(start), 500*time.Millisecond)
+		require.Less(t, evaluations.Load(), int64(1000))
+	})
+}
+
+func TestQuotedIdentifiers(t *testing.T) {
+	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(t, err)
+	defer closeStore(t, st)
+
+	engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix).WithCaseSensitiveIdentifiers(true))
+	require.NoError(t, err)
+
+	defaultEngine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(
+		context.Background(),
+		nil,
+		`
+		CREATE TABLE "MyTable" (
+			"Id" INTEGER,
+			"MyCol" VARCHAR,
+			"select" INTEGER,
+			"first name" VARCHAR,
+			mycol VARCHAR,
+			CHECK ("select" >= 0 AND "MyCol" <> 'forbidden'),
+			PRIMARY KEY "Id"
+		);
+
+		CREATE TABLE mytable (id INTEGER, PRIMARY KEY id);
+
+		INSERT INTO "MyTable" ("Id", "MyCol", "select", "first name", mycol) VALUES (1, 'quoted', 10, 'John', 'unquoted');
+		`,
+		nil,
+	)
+	require.NoError(t, err)
+
+	t.Run("quoted identifiers should be case-sensitive", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, `SELECT "MyCol", mycol, "select", "first name" FROM "MyTable" WHERE "Id" = 1`, nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, "quoted", rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, "unquoted", rows[0].ValuesByPosition[1].RawValue())
+		require.Equal(t, int64(10), rows[0].ValuesByPosition[2].RawValue())
+		require.Equal(t, "John", rows[0].ValuesByPosition[3].RawValue())
+
+		r, err := engine.Query(context.Background(), nil, `SELECT "MyCol", "first name" FROM "MyTable"`, nil)
+		require.NoError(t, err)
+		defer r.Close()
+
+		cols, err := r.Columns(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, "MyTable", cols[0].Table)
+		require.Equal(t, "MyCol", cols[0].Column)
+		require.Equal(t, "first name", cols[1].Column)
+	})
+
+	t.Run("unquoted identifiers should be folded to lower case", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, `SELECT MyCol FROM "MyTable"`, nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, "unquoted", rows[0].ValuesByPosition[0].RawValue())
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT * FROM MyTable", nil)
+		require.NoError(t, err)
+		require.Empty(t, rows)
+
+		_, err = engine.queryAll(context.Background(), nil, `SELECT Id FROM "MyTable"`, nil)
+		require.ErrorIs(t, err, ErrColumnDoesNotExist)
+
+		_, err = engine.queryAll(context.Background(), nil, `SELECT * FROM "MYTABLE"`, nil)
+		require.ErrorIs(t, err, ErrTableDoesNotExist)
+	})
+
+	t.Run("check constraints on quoted columns should survive reloading the catalog", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, `INSERT INTO "MyTable" ("Id", "MyCol", "select") VALUES (2, 'forbidden', 1)`, nil)
+		require.ErrorIs(t, err, ErrCheckConstraintViolation)
+
+		_, _, err = engine.Exec(context.Background(), nil, `INSERT INTO "MyTable" ("Id", "MyCol", "select") VALUES (2, 'allowed', -1)`, nil)
+		require.ErrorIs(t, err, ErrCheckConstraintViolation)
+
+		_, _, err = engine.Exec(context.Background(), nil, `INSERT INTO "MyTable" ("Id", "MyCol", "select") VALUES (2, 'allowed', 1)`, nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("quoted identifiers should be folded to lower case by default", func(t *testing.T) {
+		_, _, err := defaultEngine.Exec(context.Background(), nil, `
+			CREATE TABLE "Legacy" ("Id" INTEGER, "select" INTEGER, PRIMARY KEY "Id");
+			INSERT INTO "Legacy" ("Id", "select") VALUES (1, 10);
+		`, nil)
+		require.NoError(t, err)
+
+		for _, sql := range []string{
+			`SELECT "Id", "select" FROM "Legacy"`,
+			`SELECT "ID", "select" FROM "LEGACY"`,
+			`SELECT id, "select" FROM legacy`,
+		} {
+			rows, err := defaultEngine.queryAll(context.Background(), nil, sql, nil)
+			require.NoError(t, err, sql)
+			require.Len(t, rows, 1, sql)
+			require.Equal(t, int64(10), rows[0].ValuesByPosition[1].RawValue())
+		}
+
+		// objects named with lower case letters are found by both engines
+		rows, err := engine.queryAll(context.Background(), nil, `SELECT id FROM legacy`, nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+
+		rows, err = defaultEngine.queryAll(context.Background(), nil, `SELECT * FROM "MyTable"`, nil)
+		require.NoError(t, err)
+		require.Empty(t, rows)
+	})
+}
+
+func TestDeepOffset(t *testing.T) {
+	const rowCount = 2000
+
+	engine, _ := setupCommonTestWithOptions(t, store.DefaultOptions().WithMaxTxEntries(rowCount))
+
+	_, _, err := engine.Exec(context.Background(), nil, "CREATE TABLE items (id INTEGER AUTO_INCREMENT, n INTEGER, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithExplicitClose(true))
+	require.NoError(t, err)
+
+	for i := 0; i < rowCount; i++ {
+		_, _, err = engine.Exec(context.Background(), tx, "INSERT INTO items (n) VALUES (@n)", map[string]interface{}{"n": i})
+		require.NoError(t, err)
+	}
+
+	err = tx.Commit(context.Background())
+	require.NoError(t, err)
+
+	var projectedRows int
+
+	err = engine.RegisterFunction("projected", ScalarFunc{
+		ArgTypes:   []SQLValueType{IntegerType},
+		ReturnType: IntegerType,
+		Eval: func(args []TypedValue) (TypedValue, error) {
+			projectedRows++
+			return args[0], nil
+		},
+	})
+	require.NoError(t, err)
+
+	queryIDs := func(t *testing.T, sql string) []int64 {
+		projectedRows = 0
+
+		rows, err := engine.queryAll(context.Background(), nil, sql, nil)
+		require.NoError(t, err)
+
+		ids := make([]int64, len(rows))
+		for i, row := range rows {
+			ids[i] = row.ValuesByPosition[0].RawValue().(int64)
+		}
+		return ids
+	}
+
+	expected := []int64{1501, 1502, 1503, 1504, 1505}
+
+	t.Run("offset over an index scan should skip index entries", func(t *testing.T) {
+		ids := queryIDs(t, "SELECT projected(id) FROM items ORDER BY id LIMIT 5 OFFSET 1500")
+		require.Equal(t, expected, ids)
+		require.Equal(t, len(expected), projectedRows)
+
+		ids = queryIDs(t, "SELECT projected(id) FROM items ORDER BY id DESC LIMIT 2 OFFSET 1998")
+		require.Equal(t, []int64{2, 1}, ids)
+		require.Equal(t, 2, projectedRows)
+
+		ids = queryIDs(t, "SELECT projected(id) FROM items ORDER BY id OFFSET 5000")
+		require.Empty(t, ids)
+		require.Zero(t, projectedRows)
+	})
+
+	t.Run("skipped rows should not be projected when filtering", func(t *testing.T) {
+		ids := queryIDs(t, "SELECT projected(id) FROM items WHERE n >= 0 ORDER BY id LIMIT 5 OFFSET 1500")
+		require.Equal(t, expected, ids)
+		require.Equal(t, len(expected), projectedRows)
+	})
+
+	t.Run("skipped rows should be projected when selecting distinct rows", func(t *testing.T) {
+		ids := queryIDs(t, "SELECT DISTINCT projected(id) FROM items ORDER BY id LIMIT 5 OFFSET 1500")
+		require.Equal(t, expected, ids)
+		require.Equal(t, 1500+len(expected), projectedRows)
+	})
+
+	t.Run("offset should skip rows of the selected period", func(t *testing.T) {
+		ids := queryIDs(t, fmt.Sprintf("SELECT id FROM items SINCE TX %d ORDER BY id LIMIT 5 OFFSET 1500", tx.txHeader.ID))
+		require.Equal(t, expected, ids)
+	})
+}
+
+func TestRangeUnionScan(t *testing.T) {
+	const rowCount = 1000
+
+	engine, _ := setupCommonTestWithOptions(t, store.DefaultOptions().WithMaxTxEntries(2*rowCount))
+
+	_, _, err := engine.Exec(context.Background(), nil, "CREATE TABLE items (id INTEGER AUTO_INCREMENT, category VARCHAR[8], amount INTEGER, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE INDEX ON items(category, amount)", nil)
+	require.NoError(t, err)
+
+	tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithExplicitClose(true))
+	require.NoError(t, err)
+
+	for i := 0; i < rowCount; i++ {
+		_, _, err = engine.Exec(
+			context.Background(),
+			tx,
+			"INSERT INTO items (category, amount) VALUES (@category, @amount)",
+			map[string]interface{}{"category": fmt.Sprintf("c%d", i%4), "amount": i % 100},
+		)
+		require.NoError(t, err)
+	}
+
+	err = tx.Commit(context.Background())
+	require.NoError(t, err)
+
+	var scannedRows int
+
+	err = engine.RegisterFunction("scanned", ScalarFunc{
+		ArgTypes:   []SQLValueType{IntegerType},
+		ReturnType: BooleanType,
+		Eval: func(args []TypedValue) (TypedValue, error) {
+			scannedRows++
+			return NewBool(true), nil
+		},
+	})
+	require.NoError(t, err)
+
+	queryIDs := func(t *testing.T, sql string, params map[string]interface{}) []int64 {
+		scannedRows = 0
+
+		rows, err := engine.queryAll(context.Background(), nil, sql, params)
+		require.NoError(t, err)
+
+		ids := make([]int64, len(rows))
+		for i, row := range rows {
+			ids[i] = row.ValuesByPosition[0].RawValue().(int64)
+		}
+		return ids
+	}
+
+	t.Run("IN and OR of equalities should only read the matching keys", func(t *testing.T) {
+		ids := queryIDs(t, "SELECT id FROM items WHERE scanned(id) AND id IN (500, 1, 2)", nil)
+		require.Equal(t, []int64{1, 2, 500}, ids)
+		require.Equal(t, 3, scannedRows)
+
+		ids = queryIDs(t, "SELECT id FROM items WHERE scanned(id) AND (id = 1 OR id = 2 OR id = 500)", nil)
+		require.Equal(t, []int64{1, 2, 500}, ids)
+		require.Equal(t, 3, scannedRows)
+
+		ids = queryIDs(t, "SELECT id FROM items WHERE scanned(id) AND id IN (@a, @b, 2000)", map[string]interface{}{"a": 999, "b": 10})
+		require.Equal(t, []int64{10, 999}, ids)
+		require.Equal(t, 2, scannedRows)
+
+		ids = queryIDs(t, "SELECT id FROM items WHERE scanned(id) AND (id = 1 OR id = 2 OR id = 500) ORDER BY id DESC", nil)
+		require.Equal(t, []int64{500, 2, 1}, ids)
+		require.Equal(t, 3, scannedRows)
+	})
+
+	t.Run("OR of ranges should only read the keys within each range", func(t *testing.T) {
+		ids := queryIDs(t, "SELECT id FROM items WHERE scanned(id) AND (id <= 2 OR id >= 999 OR (id >= 500 AND id <= 501))", nil)
+		require.Equal(t, []int64{1, 2, 500, 501, 999, 1000}, ids)
+		require.Equal(t, 6, scannedRows)
+
+		// overlapping ranges are read once
+		ids = queryIDs(t, "SELECT id FROM items WHERE scanned(id) AND (id IN (6, 5, 6) OR (id >= 5 AND id <= 7))", nil)
+		require.Equal(t, []int64{5, 6, 7}, ids)
+		require.Equal(t, 3, scannedRows)
+
+		ids = queryIDs(t, "SELECT id FROM items WHERE scanned(id) AND (id = 5 OR (id >= 7 AND id <= 9)) AND id > 3", nil)
+		require.Equal(t, []int64{5, 7, 8, 9}, ids)
+		require.Equal(t, 4, scannedRows)
+
+		ids = queryIDs(t, "SELECT id FROM items WHERE scanned(id) AND id IN (1, 2, 3) AND id IN (2, 3, 4)", nil)
+		require.Equal(t, []int64{2, 3}, ids)
+		require.Equal(t, 2, scannedRows)
+	})
+
+	t.Run("OR over a secondary index should only read the matching keys", func(t *testing.T) {
+		ids := queryIDs(t, "SELECT id FROM items WHERE scanned(id) AND category = 'c1' AND (amount = 1 OR amount = 5)", nil)
+		require.Len(t, ids, 20)
+		require.Equal(t, 20, scannedRows)
+
+		// rows are read following the order of the index
+		for i, id := range ids {
+			amount := (id - 1) % 100
+			require.Equal(t, i >= 10, amount == 5)
+			require.Contains(t, []int64{1, 5}, amount)
+		}
+	})
+
+	t.Run("OR involving different columns should scan the table", func(t *testing.T) {
+		r, err := engine.Query(context.Background(), nil, "SELECT id FROM items WHERE scanned(id) AND (id = 1 OR amount = 5)", nil)
+		require.NoError(t, err)
+
+		// the function is only evaluated for the rows satisfying the cheaper OR condition, thus reads are counted instead
+		cr := r.(*projectedRowReader).rowReader.(*conditionalRowReader)
+		counter := &countingRowReader{RowReader: cr.rowReader}
+		cr.rowReader = counter
+
+		rows, err := ReadAllRows(context.Background(), r)
+		require.NoError(t, err)
+		require.Len(t, rows, 11)
+		require.Equal(t, rowCount, counter.reads)
+		require.NoError(t, r.Close())
+
+		ids := queryIDs(t, "SELECT id FROM items WHERE scanned(id) AND id NOT IN (1, 2) LIMIT 1", nil)
+		require.Equal(t, []int64{3}, ids)
+	})
+}
@@ -0,0 +1,92 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"errors"
+
+	"github.com/codenotary/immudb/embedded/store"
+)
+
+// ErrorCode is the category of an SQL error
+type ErrorCode string
+
+const (
+	ErrCodeUnknown       ErrorCode = "unknown"
+	ErrCodeSyntax        ErrorCode = "syntax"
+	ErrCodeType          ErrorCode = "type"
+	ErrCodeConstraint    ErrorCode = "constraint"
+	ErrCodeConflict      ErrorCode = "conflict"
+	ErrCodeNotFound      ErrorCode = "not_found"
+	ErrCodeAlreadyExists ErrorCode = "already_exists"
+	ErrCodeInvalid       ErrorCode = "invalid"
+	ErrCodeUnsupported   ErrorCode = "unsupported"
+	ErrCodeTransaction   ErrorCode = "transaction"
+	ErrCodeAccessDenied  ErrorCode = "access_denied"
+	ErrCodeInternal      ErrorCode = "internal"
+)
+
+// SQLError is the type of the errors defined by the SQL engine, thus the category of an
+// error can be obtained with errors.As, while errors.Is still compares against each error
+//
+//	var sqlErr *SQLError
+//	if errors.As(err, &sqlErr) && sqlErr.Code == ErrCodeConstraint {
+//		...
+//	}
+type SQLError struct {
+	Code ErrorCode
+	msg  string
+}
+
+func newSQLError(code ErrorCode, msg string) error {
+	return &SQLError{Code: code, msg: msg}
+}
+
+func (err *SQLError) Error() string {
+	return err.msg
+}
+
+// ErrorCodeOf returns the category of the error, it includes the errors of the underlying
+// store returned by the engine, such as conflicts between concurrent transactions
+func ErrorCodeOf(err error) ErrorCode {
+	if err == nil {
+		return ""
+	}
+
+	var sqlErr *SQLError
+	if errors.As(err, &sqlErr) {
+		return sqlErr.Code
+	}
+
+	switch {
+	case errors.Is(err, store.ErrTxReadConflict):
+		return ErrCodeConflict
+	case errors.Is(err, store.ErrKeyAlreadyExists):
+		// duplicated primary or unique keys
+		return ErrCodeConstraint
+	case errors.Is(err, store.ErrKeyNotFound), errors.Is(err, ErrNoMoreRows):
+		return ErrCodeNotFound
+	case errors.Is(err, ErrIllegalArguments), errors.Is(err, store.ErrIllegalState):
+		return ErrCodeInvalid
+	case errors.Is(err, ErrAlreadyClosed):
+		return ErrCodeTransaction
+	case errors.Is(err, ErrCorruptedData):
+		return ErrCodeInternal
+	}
+
+	return ErrCodeUnknown
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLErrorCodes(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(
+		context.Background(),
+		nil,
+		`
+		CREATE TABLE accounts (
+			id INTEGER,
+			owner VARCHAR NOT NULL,
+			balance INTEGER,
+			CHECK (balance >= 0),
+			PRIMARY KEY id
+		);
+
+		INSERT INTO accounts (id, owner, balance) VALUES (1, 'admin', 100);
+		`,
+		nil,
+	)
+	require.NoError(t, err)
+
+	testCases := []struct {
+		sql          string
+		expectedErr  error
+		expectedCode ErrorCode
+	}{
+		{"SELECT * FROM", ErrParsingError, ErrCodeSyntax},
+		{"SELECT * FROM accounts WHERE owner > 1", ErrNotComparableValues, ErrCodeType},
+		{"SELECT * FROM accounts WHERE id + 1", ErrInvalidCondition, ErrCodeType},
+		{"INSERT INTO accounts (id, owner, balance) VALUES (2, 'guest', -1)", ErrCheckConstraintViolation, ErrCodeConstraint},
+		{"INSERT INTO accounts (id, balance) VALUES (2, 1)", ErrNotNullableColumnCannotBeNull, ErrCodeConstraint},
+		{"INSERT INTO accounts (id, owner, balance) VALUES (1, 'duplicated', 0)", store.ErrKeyAlreadyExists, ErrCodeConstraint},
+		{"SELECT * FROM payments", ErrTableDoesNotExist, ErrCodeNotFound},
+		{"SELECT amount FROM accounts", ErrColumnDoesNotExist, ErrCodeNotFound},
+		{"CREATE TABLE accounts (id INTEGER, PRIMARY KEY id)", ErrTableAlreadyExists, ErrCodeAlreadyExists},
+		{"COMMIT", ErrNoOngoingTx, ErrCodeTransaction},
+		{"SELECT id FROM accounts WHERE EXISTS (SELECT id FROM accounts)", ErrNoSupported, ErrCodeUnsupported},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.sql, func(t *testing.T) {
+			_, err := engine.queryAll(context.Background(), nil, tc.sql, map[string]interface{}{"id": 1})
+			if errors.Is(err, ErrExpectingDQLStmt) {
+				_, _, err = engine.Exec(context.Background(), nil, tc.sql, nil)
+			}
+			require.ErrorIs(t, err, tc.expectedErr)
+			require.Equal(t, tc.expectedCode, ErrorCodeOf(err))
+
+			var sqlErr *SQLError
+			if errors.As(tc.expectedErr, &sqlErr) {
+				require.ErrorAs(t, err, &sqlErr)
+				require.Equal(t, tc.expectedCode, sqlErr.Code)
+			}
+		})
+	}
+
+	t.Run("conflicts", func(t *testing.T) {
+		tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithExplicitClose(true))
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), tx, "UPDATE accounts SET balance = balance + 1 WHERE id = 1", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "UPDATE accounts SET balance = balance - 1 WHERE id = 1", nil)
+		require.NoError(t, err)
+
+		err = tx.Commit(context.Background())
+		require.ErrorIs(t, err, store.ErrTxReadConflict)
+		require.Equal(t, ErrCodeConflict, ErrorCodeOf(err))
+	})
+
+	t.Run("wrapped errors", func(t *testing.T) {
+		require.Empty(t, ErrorCodeOf(nil))
+		require.Equal(t, ErrCodeUnknown, ErrorCodeOf(errors.New("custom error")))
+		require.Equal(t, ErrCodeType, ErrorCodeOf(ErrUnsupportedCast))
+		require.Equal(t, ErrCodeInvalid, ErrorCodeOf(fmt.Errorf("%w: invalid", ErrIllegalArguments)))
+		require.Equal(t, ErrCodeNotFound, ErrorCodeOf(ErrNoMoreRows))
+
+		err := fmt.Errorf("%w (%s)", ErrTableDoesNotExist, "payments")
+		require.Equal(t, "table does not exist (payments)", err.Error())
+		require.Equal(t, ErrCodeNotFound, ErrorCodeOf(err))
+	})
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+)
+
+// RetryOpts determines how statements failing due to a conflict with a concurrent transaction are retried
+type RetryOpts struct {
+	// MaxAttempts is the maximum number of times statements are executed, including the first attempt
+	MaxAttempts int
+	// Backoff is the delay before the first retry, it's doubled after each subsequent attempt
+	Backoff time.Duration
+}
+
+func DefaultRetryOpts() RetryOpts {
+	return RetryOpts{
+		MaxAttempts: 3,
+		Backoff:     10 * time.Millisecond,
+	}
+}
+
+func (opts RetryOpts) Validate() error {
+	if opts.MaxAttempts < 1 {
+		return fmt.Errorf("%w: invalid MaxAttempts", ErrIllegalArguments)
+	}
+
+	if opts.Backoff < 0 {
+		return fmt.Errorf("%w: invalid Backoff", ErrIllegalArguments)
+	}
+
+	return nil
+}
+
+func (opts RetryOpts) delayAfter(attempt int) time.Duration {
+	return opts.Backoff << (attempt - 1)
+}
+
+// isRetryableErr reports whether an execution failure may not happen again when the
+// statements are run in a fresh transaction.
+func isRetryableErr(err error) bool {
+	return errors.Is(err, store.ErrTxReadConflict)
+}
+
+// ExecWithRetry executes the statements in a fresh transaction, and runs them again in a new one
+// if committing fails due to a conflict with a concurrent transaction. Any other error, such as a
+// constraint violation, is returned without retrying. Statements are not retried once a transaction
+// started by them was committed, e.g. when they explicitly include several transactions.
+func (e *Engine) ExecWithRetry(ctx context.Context, sql string, params map[string]interface{}, opts RetryOpts) (ntx *SQLTx, committedTxs []*SQLTx, err error) {
+	err = opts.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stmts, err := e.parseSQL(sql)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrParsingError, err)
+	}
+
+	for attempt := 1; ; attempt++ {
+		ntx, committedTxs, err = e.ExecPreparedStmts(ctx, nil, stmts, params)
+		if err == nil || !isRetryableErr(err) || len(committedTxs) > 0 || attempt == opts.MaxAttempts {
+			return ntx, committedTxs, err
+		}
+
+		timer := time.NewTimer(opts.delayAfter(attempt))
+
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
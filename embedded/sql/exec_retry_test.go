@@ -0,0 +1,134 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecWithRetry(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(
+		context.Background(),
+		nil,
+		`
+		CREATE TABLE counters (id INTEGER, n INTEGER, CHECK (n >= 0), PRIMARY KEY id);
+
+		INSERT INTO counters (id, n) VALUES (1, 0);
+		`,
+		nil,
+	)
+	require.NoError(t, err)
+
+	var attempts, conflictingAttempts int
+
+	// each evaluation counts an attempt, the first conflictingAttempts ones concurrently
+	// update the counter so that committing the transaction evaluating it fails
+	err = engine.RegisterFunction("attempt", ScalarFunc{
+		ReturnType: IntegerType,
+		Eval: func(args []TypedValue) (TypedValue, error) {
+			attempts++
+
+			if attempts <= conflictingAttempts {
+				_, _, err := engine.Exec(context.Background(), nil, "UPDATE counters SET n = n + 10 WHERE id = 1", nil)
+				if err != nil {
+					return nil, err
+				}
+			}
+			return NewInteger(1), nil
+		},
+	})
+	require.NoError(t, err)
+
+	counter := func(t *testing.T) int64 {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT n FROM counters WHERE id = 1", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		return rows[0].ValuesByPosition[0].RawValue().(int64)
+	}
+
+	opts := RetryOpts{MaxAttempts: 3, Backoff: time.Millisecond}
+
+	t.Run("invalid options should fail", func(t *testing.T) {
+		_, _, err := engine.ExecWithRetry(context.Background(), "UPDATE counters SET n = n + 1", nil, RetryOpts{})
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, _, err = engine.ExecWithRetry(context.Background(), "UPDATE counters SET n = n + 1", nil, RetryOpts{MaxAttempts: 1, Backoff: -1})
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, _, err = engine.ExecWithRetry(context.Background(), "UPDATE counters SET", nil, opts)
+		require.ErrorIs(t, err, ErrParsingError)
+	})
+
+	t.Run("conflicts should be retried in a fresh transaction", func(t *testing.T) {
+		attempts, conflictingAttempts = 0, 1
+
+		_, _, err := engine.Exec(context.Background(), nil, "UPDATE counters SET n = n + attempt() WHERE id = 1", nil)
+		require.ErrorIs(t, err, store.ErrTxReadConflict)
+		require.Equal(t, 1, attempts)
+		require.Equal(t, int64(10), counter(t))
+
+		attempts = 0
+
+		_, committedTxs, err := engine.ExecWithRetry(context.Background(), "UPDATE counters SET n = n + attempt() WHERE id = 1", nil, opts)
+		require.NoError(t, err)
+		require.Len(t, committedTxs, 1)
+		require.Equal(t, 2, attempts)
+		require.Equal(t, int64(21), counter(t))
+	})
+
+	t.Run("retries should be bounded", func(t *testing.T) {
+		attempts, conflictingAttempts = 0, opts.MaxAttempts
+
+		_, committedTxs, err := engine.ExecWithRetry(context.Background(), "UPDATE counters SET n = n + attempt() WHERE id = 1", nil, opts)
+		require.ErrorIs(t, err, store.ErrTxReadConflict)
+		require.Empty(t, committedTxs)
+		require.Equal(t, opts.MaxAttempts, attempts)
+		require.Equal(t, int64(21+10*opts.MaxAttempts), counter(t))
+	})
+
+	t.Run("non-retryable errors should fail immediately", func(t *testing.T) {
+		attempts, conflictingAttempts = 0, 0
+
+		_, _, err := engine.ExecWithRetry(context.Background(), "UPDATE counters SET n = -attempt() WHERE id = 1", nil, opts)
+		require.ErrorIs(t, err, ErrCheckConstraintViolation)
+		require.Equal(t, 1, attempts)
+
+		attempts = 0
+
+		_, _, err = engine.ExecWithRetry(context.Background(), "INSERT INTO counters (id, n) VALUES (1, attempt())", nil, opts)
+		require.ErrorIs(t, err, store.ErrKeyAlreadyExists)
+		require.Equal(t, 1, attempts)
+	})
+
+	t.Run("waiting for a retry should be cancellable", func(t *testing.T) {
+		attempts, conflictingAttempts = 0, 1
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		_, _, err := engine.ExecWithRetry(ctx, "UPDATE counters SET n = n + attempt() WHERE id = 1", nil, RetryOpts{MaxAttempts: 2, Backoff: time.Hour})
+		require.ErrorIs(t, err, context.DeadlineExceeded)
+		require.Equal(t, 1, attempts)
+	})
+}
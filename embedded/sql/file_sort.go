@@ -40,6 +40,9 @@ type fileSorter struct {
 	sortBuf     []*Row
 	nextIdx     int
 
+	resources *resourceTracker
+	bufMem    int64 // memory accounted for the rows in the buffer
+
 	tempFile     *os.File
 	writer       *bufio.Writer
 	tempFileSize uint64
@@ -56,6 +59,12 @@ func (s *fileSorter) update(r *Row) error {
 		s.nextIdx = 0
 	}
 
+	size, err := s.resources.allocateRow(r)
+	s.bufMem += size
+	if err != nil {
+		return err
+	}
+
 	s.sortBuf[s.nextIdx] = r
 	s.nextIdx++
 
@@ -376,6 +385,11 @@ func (s *fileSorter) flushBuffer() error {
 		size:   chunkSize,
 	})
 	s.tempFileSize += chunkSize
+
+	// flushed rows are no longer held in memory
+	s.resources.release(s.bufMem)
+	s.bufMem = 0
+
 	return nil
 }
 
@@ -0,0 +1,90 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+// fold simplifies exp before it gets evaluated for each row: constant sub-expressions
+// are replaced by their values and AND/OR branches with a known outcome are eliminated.
+// Sub-expressions which can not be evaluated are kept as they are, so any error is
+// reported, as usual, when evaluating rows.
+func fold(tx *SQLTx, exp ValueExp) ValueExp {
+	switch e := exp.(type) {
+	case TypedValue:
+		return e
+	case *NumExp:
+		exp = &NumExp{op: e.op, left: fold(tx, e.left), right: fold(tx, e.right)}
+	case *CmpBoolExp:
+		exp = &CmpBoolExp{op: e.op, left: fold(tx, e.left), right: fold(tx, e.right)}
+	case *NotBoolExp:
+		exp = &NotBoolExp{exp: fold(tx, e.exp)}
+	case *Cast:
+		exp = &Cast{val: fold(tx, e.val), t: e.t}
+	case *BinBoolExp:
+		return foldBinBoolExp(tx, e)
+	}
+
+	if !exp.isConstant() {
+		return exp
+	}
+
+	v, err := exp.reduce(tx, nil, "")
+	if err != nil {
+		return exp
+	}
+	return v
+}
+
+func foldBinBoolExp(tx *SQLTx, bexp *BinBoolExp) ValueExp {
+	left := fold(tx, bexp.left)
+	right := fold(tx, bexp.right)
+
+	// the outcome is determined by either side e.g. false AND x
+	short := bexp.op == Or
+
+	for _, side := range []ValueExp{left, right} {
+		if b, isBool := side.(*Bool); isBool && b.val == short {
+			return &Bool{val: short}
+		}
+	}
+
+	// the outcome is determined by the other side e.g. true AND x
+	if b, isBool := left.(*Bool); isBool && b.val != short {
+		return right
+	}
+
+	if b, isBool := right.(*Bool); isBool && b.val != short {
+		return left
+	}
+
+	return &BinBoolExp{op: bexp.op, left: left, right: right}
+}
+
+// isAlwaysTrue returns true when exp is known to be satisfied by every row
+func isAlwaysTrue(exp ValueExp) bool {
+	b, isBool := exp.(*Bool)
+	return isBool && b.val
+}
+
+// isAlwaysFalse returns true when exp is known to not be satisfied by any row
+func isAlwaysFalse(exp ValueExp) bool {
+	switch v := exp.(type) {
+	case *Bool:
+		return !v.val
+	case *NullValue:
+		return v.Type() == BooleanType || v.Type() == AnyType
+	}
+	return false
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFold(t *testing.T) {
+	for _, d := range []struct {
+		exp    string
+		folded string
+	}{
+		{"val > 2 + 3", "(val > 5)"},
+		{"val > 2 * (3 + 4) - 1", "(val > 13)"},
+		{"1 = 1 AND val > 5000", "(val > 5000)"},
+		{"val > 5000 AND 1 = 1", "(val > 5000)"},
+		{"1 = 2 AND val > 5000", "false"},
+		{"val > 5000 AND 1 = 2", "false"},
+		{"1 = 1 OR val > 5000", "true"},
+		{"val > 5000 OR 1 = 1", "true"},
+		{"1 = 2 OR val > 5000", "(val > 5000)"},
+		{"NOT (1 > 2)", "true"},
+		{"NOT (val > 1 + 1)", "(NOT (val > 2))"},
+		{"(1 = 1 AND 2 = 2) OR val = 0", "true"},
+		{"val = 1 AND (val = 2 OR 3 < 2)", "((val = 1) AND (val = 2))"},
+		{"CAST('10' AS INTEGER) < val", "(10 < val)"},
+		{"val / 0 > 1 / 0", "((val / 0) > (1 / 0))"},
+		{"val > 1 AND NOW() > NOW()", "((val > 1) AND (now() > now()))"},
+	} {
+		t.Run(d.exp, func(t *testing.T) {
+			exp, err := ParseExpFromString(d.exp)
+			require.NoError(t, err)
+
+			require.Equal(t, d.folded, fold(nil, exp).String())
+		})
+	}
+}
+
+func TestConditionalRowReaderWithFalseCondition(t *testing.T) {
+	for _, cond := range []string{"false", "1 = 2", "val > 1 AND 1 > 2", "NULL"} {
+		t.Run(cond, func(t *testing.T) {
+			exp, err := ParseExpFromString(cond)
+			require.NoError(t, err)
+
+			reader := &mockRowReader{
+				rows:       []*Row{{ValuesByPosition: []TypedValue{&Integer{val: 1}}}},
+				tableAlias: "t1",
+			}
+
+			cr := newConditionalRowReader(reader, exp)
+
+			_, err = cr.Read(context.Background())
+			require.ErrorIs(t, err, ErrNoMoreRows)
+			require.Zero(t, reader.curr)
+		})
+	}
+}
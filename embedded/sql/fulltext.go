@@ -0,0 +1,539 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/codenotary/immudb/embedded/store"
+)
+
+// MaxFullTextTermLen is the maximum length in bytes of an indexed term,
+// longer terms are truncated both when indexing and when matching.
+const MaxFullTextTermLen = 64
+
+// fullTextTerms splits a text into its distinct terms, in order of appearance.
+// Terms are maximal sequences of letters and digits, compared case-insensitively.
+func fullTextTerms(text string) []string {
+	var terms []string
+
+	seen := make(map[string]struct{})
+
+	for _, field := range strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		term := truncateTerm(strings.ToLower(field))
+
+		if _, ok := seen[term]; ok {
+			continue
+		}
+		seen[term] = struct{}{}
+
+		terms = append(terms, term)
+	}
+	return terms
+}
+
+func truncateTerm(term string) string {
+	if len(term) <= MaxFullTextTermLen {
+		return term
+	}
+
+	n := MaxFullTextTermLen
+	for n > 0 && !utf8.RuneStart(term[n]) {
+		n--
+	}
+	return term[:n]
+}
+
+func fullTextValueTerms(val TypedValue) []string {
+	if val == nil || val.IsNull() {
+		return nil
+	}
+
+	s, ok := val.RawValue().(string)
+	if !ok {
+		return nil
+	}
+	return fullTextTerms(s)
+}
+
+func fullTextTermPrefix(sqlPrefix []byte, index *Index, term string) ([]byte, error) {
+	encTerm, _, err := EncodeValueAsKey(&Varchar{val: term}, VarcharType, MaxFullTextTermLen)
+	if err != nil {
+		return nil, err
+	}
+	return MapKey(sqlPrefix, FullTextPrefix, EncodeID(index.table.id), EncodeID(index.id), encTerm), nil
+}
+
+// updateFullTextEntries updates the entries of the full-text indexes of the table for the row
+// identified by pkEncVals, given its current values (nil for new rows) and its new values
+// (nil for deleted rows). Only the entries of terms being added or removed are written.
+func (tx *SQLTx) updateFullTextEntries(pkEncVals []byte, table *Table, currValuesByColID, newValuesByColID map[uint32]TypedValue) error {
+	for _, index := range table.fullTextIndexes {
+		colID := index.cols[0].id
+
+		err := tx.updateFullTextIndexEntries(index, pkEncVals, currValuesByColID[colID], newValuesByColID[colID])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (tx *SQLTx) updateFullTextIndexEntries(index *Index, pkEncVals []byte, currVal, newVal TypedValue) error {
+	currTerms := fullTextValueTerms(currVal)
+	newTerms := fullTextValueTerms(newVal)
+
+	newTermSet := make(map[string]struct{}, len(newTerms))
+	for _, term := range newTerms {
+		newTermSet[term] = struct{}{}
+	}
+
+	currTermSet := make(map[string]struct{}, len(currTerms))
+
+	for _, term := range currTerms {
+		currTermSet[term] = struct{}{}
+
+		if _, kept := newTermSet[term]; kept {
+			continue
+		}
+
+		key, err := fullTextTermPrefix(tx.sqlPrefix(), index, term)
+		if err != nil {
+			return err
+		}
+
+		md := store.NewKVMetadata()
+
+		md.AsDeleted(true)
+
+		err = tx.set(append(key, pkEncVals...), md, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, term := range newTerms {
+		if _, exists := currTermSet[term]; exists {
+			continue
+		}
+
+		key, err := fullTextTermPrefix(tx.sqlPrefix(), index, term)
+		if err != nil {
+			return err
+		}
+
+		err = tx.set(append(key, pkEncVals...), nil, nil)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (stmt *CreateIndexStmt) createFullTextIndex(ctx context.Context, tx *SQLTx, table *Table) (*SQLTx, error) {
+	if len(stmt.cols) != 1 {
+		return nil, fmt.Errorf("%w: full-text indexes are created over a single column", ErrIllegalArguments)
+	}
+
+	col, err := table.GetColumnByName(stmt.cols[0])
+	if err != nil {
+		return nil, err
+	}
+
+	index, err := table.newFullTextIndex(col.id)
+	if errors.Is(err, ErrIndexAlreadyExists) && stmt.ifNotExists {
+		return tx, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// v={fulltext {colID}(ASC|DESC)}
+	encodedValues := make([]byte, 1+EncIDLen+1)
+	encodedValues[0] = fullTextIndexFlag
+	copy(encodedValues[1:], EncodeID(col.id))
+
+	mappedKey := MapKey(tx.sqlPrefix(), catalogIndexPrefix, EncodeID(DatabaseID), EncodeID(table.id), EncodeID(index.id))
+
+	err = tx.set(mappedKey, nil, encodedValues)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.indexFullTextRows(ctx, index)
+	if err != nil {
+		return nil, err
+	}
+
+	tx.mutatedCatalog = true
+
+	return tx, nil
+}
+
+// indexFullTextRows creates the entries of a new full-text index for the rows already in the table
+func (tx *SQLTx) indexFullTextRows(ctx context.Context, index *Index) error {
+	table := index.table
+
+	tx.storedReads++
+	rowReader, err := (&SelectStmt{ds: &tableRef{table: table.name}}).Resolve(ctx, tx, nil, nil)
+	tx.storedReads--
+	if errors.Is(err, store.ErrIndexNotFound) {
+		// the table was created within the same transaction, thus it's empty
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer rowReader.Close()
+
+	valSel := EncodeSelector("", table.name, index.cols[0].colName)
+
+	for row, err := range rowReader.All(ctx) {
+		if err != nil {
+			return err
+		}
+
+		valuesByColID := make(map[uint32]TypedValue, len(table.primaryIndex.cols))
+		for _, pkCol := range table.primaryIndex.cols {
+			valuesByColID[pkCol.id] = row.ValuesBySelector[EncodeSelector("", table.name, pkCol.colName)]
+		}
+
+		pkEncVals, err := encodedKey(table.primaryIndex, valuesByColID)
+		if err != nil {
+			return err
+		}
+
+		err = tx.updateFullTextIndexEntries(index, pkEncVals, nil, row.ValuesBySelector[valSel])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fullTextMatch holds the terms a scan over a full-text index must contain
+type fullTextMatch struct {
+	index *Index
+	terms []string
+}
+
+// fullTextMatchFor returns the full-text match that can be used to scan the table,
+// given a MATCH condition over a column with a full-text index is part of the WHERE clause.
+// The WHERE clause is still evaluated over the rows being returned.
+func (stmt *SelectStmt) fullTextMatchFor(tx *SQLTx, table *Table, asTable string, params map[string]interface{}) *fullTextMatch {
+	if stmt.where == nil || len(table.fullTextIndexes) == 0 {
+		return nil
+	}
+
+	for _, conjunct := range conjuncts(stmt.where) {
+		mexp, isMatch := conjunct.(*MatchBoolExp)
+		if !isMatch {
+			continue
+		}
+
+		sel, isSel := mexp.val.(*ColSelector)
+		if !isSel {
+			continue
+		}
+
+		aggFn, t, colName := sel.resolve(asTable)
+		if aggFn != "" || t != asTable {
+			continue
+		}
+
+		col, err := table.GetColumnByName(colName)
+		if err != nil {
+			continue
+		}
+
+		index := table.fullTextIndexByColID(col.id)
+		if index == nil {
+			continue
+		}
+
+		query, err := mexp.query.substitute(params)
+		if err != nil || !query.isConstant() {
+			continue
+		}
+
+		val, err := query.reduce(tx, nil, asTable)
+		if err != nil || val.Type() != VarcharType {
+			continue
+		}
+
+		return &fullTextMatch{
+			index: index,
+			terms: fullTextValueTerms(val),
+		}
+	}
+	return nil
+}
+
+// fullTextKeyReader returns the rows including all the terms of a full-text match,
+// in primary key order, by intersecting the posting lists of its terms
+type fullTextKeyReader struct {
+	tx    *SQLTx
+	match *fullTextMatch
+
+	pkEncVals [][]byte
+	loaded    bool
+	pos       int
+}
+
+func newFullTextKeyReader(tx *SQLTx, match *fullTextMatch) *fullTextKeyReader {
+	return &fullTextKeyReader{
+		tx:    tx,
+		match: match,
+	}
+}
+
+func (r *fullTextKeyReader) postings(ctx context.Context, term string) ([][]byte, error) {
+	prefix, err := fullTextTermPrefix(r.tx.sqlPrefix(), r.match.index, term)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := r.tx.newKeyReader(store.KeyReaderSpec{
+		Prefix:  prefix,
+		Filters: []store.FilterFn{store.IgnoreExpired, store.IgnoreDeleted},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var pkEncVals [][]byte
+
+	for {
+		key, _, err := reader.Read(ctx)
+		if errors.Is(err, store.ErrNoMoreEntries) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		pkEncVals = append(pkEncVals, key[len(prefix):])
+	}
+	return pkEncVals, nil
+}
+
+func (r *fullTextKeyReader) load(ctx context.Context) error {
+	for i, term := range r.match.terms {
+		postings, err := r.postings(ctx, term)
+		if err != nil {
+			return err
+		}
+
+		if i == 0 {
+			r.pkEncVals = postings
+		} else {
+			r.pkEncVals = intersectPostings(r.pkEncVals, postings)
+		}
+
+		if len(r.pkEncVals) == 0 {
+			break
+		}
+	}
+
+	r.loaded = true
+
+	return nil
+}
+
+// intersectPostings returns the entries included in both lists, which are sorted in key order
+func intersectPostings(a, b [][]byte) [][]byte {
+	var res [][]byte
+
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch c := strings.Compare(string(a[i]), string(b[j])); {
+		case c == 0:
+			res = append(res, a[i])
+			i++
+			j++
+		case c < 0:
+			i++
+		default:
+			j++
+		}
+	}
+	return res
+}
+
+func (r *fullTextKeyReader) Read(ctx context.Context) (key []byte, val store.ValueRef, err error) {
+	if !r.loaded {
+		err := r.load(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	table := r.match.index.table
+
+	for r.pos < len(r.pkEncVals) {
+		pkEncVals := r.pkEncVals[r.pos]
+		r.pos++
+
+		// primary index entries are mapped as {indexPrefix}{pkVal}{pkVal}
+		mkey := table.primaryIndex.entryKey(pkEncVals, pkEncVals)
+
+		vref, err := r.tx.get(ctx, mkey)
+		if errors.Is(err, store.ErrKeyNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return mkey, vref, nil
+	}
+	return nil, nil, store.ErrNoMoreEntries
+}
+
+func (r *fullTextKeyReader) ReadBetween(ctx context.Context, initialTxID uint64, finalTxID uint64) (key []byte, val store.ValueRef, err error) {
+	return nil, nil, fmt.Errorf("%w: full-text scans are supported over the current state of the table", ErrIllegalArguments)
+}
+
+func (r *fullTextKeyReader) Reset() error {
+	r.pos = 0
+	return nil
+}
+
+func (r *fullTextKeyReader) Close() error {
+	return nil
+}
+
+// MatchBoolExp is satisfied when a text contains all the terms of the query
+type MatchBoolExp struct {
+	val   ValueExp
+	query ValueExp
+}
+
+func NewMatchBoolExp(val, query ValueExp) *MatchBoolExp {
+	return &MatchBoolExp{
+		val:   val,
+		query: query,
+	}
+}
+
+func (bexp *MatchBoolExp) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	err := bexp.val.requiresType(VarcharType, cols, params, implicitTable)
+	if err != nil {
+		return AnyType, fmt.Errorf("error in 'MATCH' clause: %w", err)
+	}
+
+	err = bexp.query.requiresType(VarcharType, cols, params, implicitTable)
+	if err != nil {
+		return AnyType, fmt.Errorf("error in 'MATCH' clause: %w", err)
+	}
+
+	return BooleanType, nil
+}
+
+func (bexp *MatchBoolExp) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != BooleanType {
+		return fmt.Errorf("error using the value of the MATCH operator as %s: %w", t, ErrInvalidTypes)
+	}
+
+	_, err := bexp.inferType(cols, params, implicitTable)
+	return err
+}
+
+func (bexp *MatchBoolExp) substitute(params map[string]interface{}) (ValueExp, error) {
+	val, err := bexp.val.substitute(params)
+	if err != nil {
+		return nil, fmt.Errorf("error in 'MATCH' clause: %w", err)
+	}
+
+	query, err := bexp.query.substitute(params)
+	if err != nil {
+		return nil, fmt.Errorf("error in 'MATCH' clause: %w", err)
+	}
+
+	return &MatchBoolExp{val: val, query: query}, nil
+}
+
+func (bexp *MatchBoolExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return bexp.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (bexp *MatchBoolExp) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	rval, err := bexp.val.reduceCtx(ctx, tx, row, implicitTable)
+	if err != nil {
+		return nil, fmt.Errorf("error in 'MATCH' clause: %w", err)
+	}
+
+	rquery, err := bexp.query.reduceCtx(ctx, tx, row, implicitTable)
+	if err != nil {
+		return nil, fmt.Errorf("error in 'MATCH' clause: %w", err)
+	}
+
+	if rval.IsNull() || rquery.IsNull() {
+		return &Bool{val: false}, nil
+	}
+
+	if rval.Type() != VarcharType || rquery.Type() != VarcharType {
+		return nil, fmt.Errorf("error in 'MATCH' clause: %w (expecting %s)", ErrInvalidTypes, VarcharType)
+	}
+
+	queryTerms := fullTextValueTerms(rquery)
+	if len(queryTerms) == 0 {
+		return &Bool{val: false}, nil
+	}
+
+	terms := make(map[string]struct{})
+	for _, term := range fullTextValueTerms(rval) {
+		terms[term] = struct{}{}
+	}
+
+	for _, term := range queryTerms {
+		if _, ok := terms[term]; !ok {
+			return &Bool{val: false}, nil
+		}
+	}
+	return &Bool{val: true}, nil
+}
+
+func (bexp *MatchBoolExp) selectors() []Selector {
+	return append(bexp.val.selectors(), bexp.query.selectors()...)
+}
+
+func (bexp *MatchBoolExp) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	return &MatchBoolExp{
+		val:   bexp.val.reduceSelectors(row, implicitTable),
+		query: bexp.query.reduceSelectors(row, implicitTable),
+	}
+}
+
+func (bexp *MatchBoolExp) isConstant() bool {
+	return false
+}
+
+func (bexp *MatchBoolExp) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
+}
+
+func (bexp *MatchBoolExp) String() string {
+	return fmt.Sprintf("(%s MATCH %s)", bexp.val.String(), bexp.query.String())
+}
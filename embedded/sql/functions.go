@@ -17,8 +17,15 @@ limitations under the License.
 package sql
 
 import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
+	"hash"
+	"math/rand"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
@@ -33,7 +40,11 @@ const (
 	UpperFnCall              string = "UPPER"
 	TrimFnCall               string = "TRIM"
 	NowFnCall                string = "NOW"
+	CurrentTxIDFnCall        string = "CURRENT_TX_ID"
+	DateTruncFnCall          string = "DATE_TRUNC"
 	UUIDFnCall               string = "RANDOM_UUID"
+	GenRandomUUIDFnCall      string = "GEN_RANDOM_UUID"
+	RandomFnCall             string = "RANDOM"
 	DatabasesFnCall          string = "DATABASES"
 	TablesFnCall             string = "TABLES"
 	TableFnCall              string = "TABLE"
@@ -42,6 +53,12 @@ const (
 	IndexesFnCall            string = "INDEXES"
 	GrantsFnCall             string = "GRANTS"
 	JSONTypeOfFnCall         string = "JSON_TYPEOF"
+	MD5FnCall                string = "MD5"
+	MD5RawFnCall             string = "MD5_RAW"
+	SHA256FnCall             string = "SHA256"
+	SHA256RawFnCall          string = "SHA256_RAW"
+	EncodeFnCall             string = "ENCODE"
+	DecodeFnCall             string = "DECODE"
 	PGGetUserByIDFnCall      string = "PG_GET_USERBYID"
 	PgTableIsVisibleFnCall   string = "PG_TABLE_IS_VISIBLE"
 	PgShobjDescriptionFnCall string = "SHOBJ_DESCRIPTION"
@@ -56,8 +73,18 @@ var builtinFunctions = map[string]Function{
 	UpperFnCall:              &LowerUpperFnc{isUpper: true},
 	TrimFnCall:               &TrimFnc{},
 	NowFnCall:                &NowFn{},
-	UUIDFnCall:               &UUIDFn{},
+	CurrentTxIDFnCall:        &CurrentTxIDFn{},
+	DateTruncFnCall:          &DateTruncFn{},
+	UUIDFnCall:               &UUIDFn{name: UUIDFnCall},
+	GenRandomUUIDFnCall:      &UUIDFn{name: GenRandomUUIDFnCall},
+	RandomFnCall:             &RandomFn{},
 	JSONTypeOfFnCall:         &JsonTypeOfFn{},
+	MD5FnCall:                &HashFn{name: MD5FnCall, hash: md5.New},
+	MD5RawFnCall:             &HashFn{name: MD5RawFnCall, hash: md5.New, raw: true},
+	SHA256FnCall:             &HashFn{name: SHA256FnCall, hash: sha256.New},
+	SHA256RawFnCall:          &HashFn{name: SHA256RawFnCall, hash: sha256.New, raw: true},
+	EncodeFnCall:             &EncodeFn{},
+	DecodeFnCall:             &DecodeFn{},
 	PGGetUserByIDFnCall:      &pgGetUserByIDFunc{},
 	PgTableIsVisibleFnCall:   &pgTableIsVisible{},
 	PgShobjDescriptionFnCall: &pgShobjDescription{},
@@ -69,6 +96,189 @@ type Function interface {
 	Apply(tx *SQLTx, params []TypedValue) (TypedValue, error)
 }
 
+// ScalarFunc describes a user-defined scalar function which can be registered
+// by calling Engine.RegisterFunction and then used as any built-in function.
+type ScalarFunc struct {
+	// ArgTypes holds the type of each argument, AnyType accepts arguments of any type
+	ArgTypes []SQLValueType
+	// ReturnType is the type of the values returned by Eval
+	ReturnType SQLValueType
+	// Eval calculates the result of the function. NULL arguments are provided as typed NULL values
+	Eval func(args []TypedValue) (TypedValue, error)
+}
+
+func (f *ScalarFunc) validate() error {
+	if f == nil || f.Eval == nil {
+		return fmt.Errorf("%w: function evaluation must be specified", ErrIllegalArguments)
+	}
+
+	if !isValidFunctionType(f.ReturnType) || f.ReturnType == AnyType {
+		return fmt.Errorf("%w: invalid return type '%s'", ErrIllegalArguments, f.ReturnType)
+	}
+
+	for _, t := range f.ArgTypes {
+		if !isValidFunctionType(t) {
+			return fmt.Errorf("%w: invalid argument type '%s'", ErrIllegalArguments, t)
+		}
+	}
+	return nil
+}
+
+func isValidFunctionType(t SQLValueType) bool {
+	switch t {
+	case IntegerType, BooleanType, VarcharType, UUIDType, BLOBType, Float64Type, TimestampType, JSONType, AnyType:
+		return true
+	}
+	return false
+}
+
+type userFunction struct {
+	name string
+	spec ScalarFunc
+}
+
+func (f *userFunction) InferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return f.spec.ReturnType, nil
+}
+
+func (f *userFunction) RequiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != f.spec.ReturnType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, f.spec.ReturnType, t)
+	}
+	return nil
+}
+
+func (f *userFunction) inferArgTypes(args []ValueExp, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if len(args) != len(f.spec.ArgTypes) {
+		return fmt.Errorf("%w: '%s' function expects %d arguments but %d were provided", ErrIllegalArguments, f.name, len(f.spec.ArgTypes), len(args))
+	}
+
+	for i, arg := range args {
+		if f.spec.ArgTypes[i] == AnyType {
+			continue
+		}
+
+		err := arg.requiresType(f.spec.ArgTypes[i], cols, params, implicitTable)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (f *userFunction) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
+	if len(params) != len(f.spec.ArgTypes) {
+		return nil, fmt.Errorf("%w: '%s' function expects %d arguments but %d were provided", ErrIllegalArguments, f.name, len(f.spec.ArgTypes), len(params))
+	}
+
+	for i, p := range params {
+		t := f.spec.ArgTypes[i]
+
+		if t == AnyType || p.IsNull() || p.Type() == t {
+			continue
+		}
+
+		if IsNumericType(t) && IsNumericType(p.Type()) {
+			conv, err := getConverter(p.Type(), t)
+			if err != nil {
+				return nil, err
+			}
+
+			params[i], err = conv(p)
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		return nil, fmt.Errorf("%w: '%s' function expects an argument of type %s at position %d", ErrIllegalArguments, f.name, t, i+1)
+	}
+
+	v, err := f.spec.Eval(params)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", f.name, err)
+	}
+
+	if v == nil {
+		return NewNull(f.spec.ReturnType), nil
+	}
+
+	if !v.IsNull() && v.Type() != f.spec.ReturnType {
+		return nil, fmt.Errorf("%w: '%s' function returned a value of type %s but %s was expected", ErrInvalidTypes, f.name, v.Type(), f.spec.ReturnType)
+	}
+	return v, nil
+}
+
+// functionRegistry holds the user-defined functions of an engine.
+// It's safe for concurrent use, registrations are visible to queries parsed afterwards.
+type functionRegistry struct {
+	mutex sync.RWMutex
+	fns   map[string]Function
+	aggs  map[string]*userAggregate
+}
+
+func newFunctionRegistry() *functionRegistry {
+	return &functionRegistry{
+		fns:  make(map[string]Function),
+		aggs: make(map[string]*userAggregate),
+	}
+}
+
+func (r *functionRegistry) exists(name string) bool {
+	_, isFn := r.fns[name]
+	_, isAgg := r.aggs[name]
+	return isFn || isAgg
+}
+
+func (r *functionRegistry) register(name string, fn Function) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.exists(name) {
+		return fmt.Errorf("%w (%s)", ErrFunctionAlreadyExists, name)
+	}
+
+	r.fns[name] = fn
+
+	return nil
+}
+
+func (r *functionRegistry) registerAggregate(agg *userAggregate) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.exists(agg.name) {
+		return fmt.Errorf("%w (%s)", ErrFunctionAlreadyExists, agg.name)
+	}
+
+	r.aggs[agg.name] = agg
+
+	return nil
+}
+
+func (r *functionRegistry) aggregate(name string) *userAggregate {
+	if r == nil {
+		return nil
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	return r.aggs[name]
+}
+
+func (r *functionRegistry) get(name string) (Function, bool) {
+	if r == nil {
+		return nil, false
+	}
+
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	fn, exists := r.fns[name]
+	return fn, exists
+}
+
 type CoalesceFn struct{}
 
 func (f *CoalesceFn) InferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
@@ -82,13 +292,16 @@ func (f *CoalesceFn) RequiresType(t SQLValueType, cols map[string]ColDescriptor,
 func (f *CoalesceFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
 	t := AnyType
 
+	// typed NULLs are taken into account as any other argument
 	for _, p := range params {
-		if !p.IsNull() {
-			if t == AnyType {
-				t = p.Type()
-			} else if p.Type() != t && !(IsNumericType(t) && IsNumericType(p.Type())) {
-				return nil, fmt.Errorf("coalesce: %w", ErrInvalidTypes)
-			}
+		if p.Type() == AnyType {
+			continue
+		}
+
+		if t == AnyType {
+			t = p.Type()
+		} else if p.Type() != t && !(IsNumericType(t) && IsNumericType(p.Type())) {
+			return nil, fmt.Errorf("coalesce: %w", ErrInvalidTypes)
 		}
 	}
 
@@ -318,6 +531,107 @@ func (f *NowFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
 	return &Timestamp{val: tx.Timestamp().Truncate(time.Microsecond).UTC()}, nil
 }
 
+// CurrentTxIDFn returns the id of the last committed transaction, i.e. the one read by read-only
+// transactions, as single queries are, or the last one committed in the store when called within
+// read-write transactions. Statements are committed in the transactions returned by Exec.
+type CurrentTxIDFn struct{}
+
+func (f *CurrentTxIDFn) InferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return IntegerType, nil
+}
+
+func (f *CurrentTxIDFn) RequiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != IntegerType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, IntegerType, t)
+	}
+	return nil
+}
+
+func (f *CurrentTxIDFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
+	if len(params) > 0 {
+		return nil, fmt.Errorf("%w: '%s' function does not expect any argument but %d were provided", ErrIllegalArguments, CurrentTxIDFnCall, len(params))
+	}
+	return &Integer{val: int64(tx.currentTxID())}, nil
+}
+
+// DateTruncFn truncates a timestamp to the precision given by its first argument,
+// one of 'year', 'month', 'day', 'hour', 'minute' or 'second'
+type DateTruncFn struct{}
+
+func (f *DateTruncFn) InferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return TimestampType, nil
+}
+
+func (f *DateTruncFn) RequiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != TimestampType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, TimestampType, t)
+	}
+	return nil
+}
+
+func (f *DateTruncFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
+	if len(params) != 2 {
+		return nil, fmt.Errorf("%w: '%s' function expects two arguments but %d were provided", ErrIllegalArguments, DateTruncFnCall, len(params))
+	}
+
+	if params[0].IsNull() || params[1].IsNull() {
+		return &NullValue{t: TimestampType}, nil
+	}
+
+	unit, ok := params[0].RawValue().(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: '%s' function expects a precision of type %v", ErrInvalidTypes, DateTruncFnCall, VarcharType)
+	}
+
+	t, ok := params[1].RawValue().(time.Time)
+	if !ok {
+		return nil, fmt.Errorf("%w: '%s' function expects an argument of type %v", ErrInvalidTypes, DateTruncFnCall, TimestampType)
+	}
+
+	truncated, err := truncateTimestamp(t, unit)
+	if err != nil {
+		return nil, err
+	}
+	return &Timestamp{val: truncated}, nil
+}
+
+func truncateTimestamp(t time.Time, unit string) (time.Time, error) {
+	t = t.UTC()
+
+	switch strings.ToLower(unit) {
+	case "year":
+		return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC), nil
+	case "month":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC), nil
+	case "day":
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), nil
+	case "hour":
+		return t.Truncate(time.Hour), nil
+	case "minute":
+		return t.Truncate(time.Minute), nil
+	case "second":
+		return t.Truncate(time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("%w: unsupported precision '%s' for '%s' function", ErrIllegalArguments, unit, DateTruncFnCall)
+}
+
+// nextTruncatedTimestamp returns the timestamp following t, which is already truncated, at the given precision
+func nextTruncatedTimestamp(t time.Time, unit string) time.Time {
+	switch strings.ToLower(unit) {
+	case "year":
+		return t.AddDate(1, 0, 0)
+	case "month":
+		return t.AddDate(0, 1, 0)
+	case "day":
+		return t.AddDate(0, 0, 1)
+	case "hour":
+		return t.Add(time.Hour)
+	case "minute":
+		return t.Add(time.Minute)
+	}
+	return t.Add(time.Second)
+}
+
 // -------------------------------------
 // JSON Functions
 // -------------------------------------
@@ -356,7 +670,11 @@ func (f *JsonTypeOfFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error)
 // UUID Functions
 // -------------------------------------
 
-type UUIDFn struct{}
+// UUIDFn generates random (version 4) UUIDs, it's available both as RANDOM_UUID() and,
+// as in PostgreSQL, GEN_RANDOM_UUID()
+type UUIDFn struct {
+	name string
+}
 
 func (f *UUIDFn) InferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
 	return UUIDType, nil
@@ -369,13 +687,244 @@ func (f *UUIDFn) RequiresType(t SQLValueType, cols map[string]ColDescriptor, par
 	return nil
 }
 
-func (f *UUIDFn) Apply(_ *SQLTx, params []TypedValue) (TypedValue, error) {
+func (f *UUIDFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
 	if len(params) > 0 {
-		return nil, fmt.Errorf("%w: '%s' function does not expect any argument but %d were provided", ErrIllegalArguments, UUIDFnCall, len(params))
+		return nil, fmt.Errorf("%w: '%s' function does not expect any argument but %d were provided", ErrIllegalArguments, f.name, len(params))
+	}
+
+	if tx != nil && tx.engine.rand != nil {
+		u, err := uuid.NewRandomFromReader(tx.engine.rand)
+		if err != nil {
+			return nil, err
+		}
+		return &UUID{val: u}, nil
 	}
 	return &UUID{val: uuid.New()}, nil
 }
 
+// -------------------------------------
+// Hash Functions
+// -------------------------------------
+
+// HashFn calculates the digest of a VARCHAR or BLOB value, returned as a lowercase
+// hexadecimal VARCHAR, as by MD5 and SHA256, or as a BLOB, as by MD5_RAW and SHA256_RAW.
+// The digest of a VARCHAR is calculated over its UTF-8 bytes.
+type HashFn struct {
+	name string
+	hash func() hash.Hash
+	raw  bool
+}
+
+func (f *HashFn) returnType() SQLValueType {
+	if f.raw {
+		return BLOBType
+	}
+	return VarcharType
+}
+
+func (f *HashFn) InferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return f.returnType(), nil
+}
+
+func (f *HashFn) RequiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != f.returnType() {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, f.returnType(), t)
+	}
+	return nil
+}
+
+func (f *HashFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
+	if len(params) != 1 {
+		return nil, fmt.Errorf("%w: '%s' function expects %d arguments but %d were provided", ErrIllegalArguments, f.name, 1, len(params))
+	}
+
+	v := params[0]
+	if v.IsNull() {
+		return &NullValue{t: f.returnType()}, nil
+	}
+
+	h := f.hash()
+
+	switch v.Type() {
+	case VarcharType:
+		h.Write([]byte(v.RawValue().(string)))
+	case BLOBType:
+		h.Write(v.RawValue().([]byte))
+	default:
+		return nil, fmt.Errorf("%w: '%s' function expects an argument of type %s or %s", ErrIllegalArguments, f.name, VarcharType, BLOBType)
+	}
+
+	digest := h.Sum(nil)
+
+	if f.raw {
+		return &Blob{val: digest}, nil
+	}
+	return &Varchar{val: hex.EncodeToString(digest)}, nil
+}
+
+// -------------------------------------
+// Encoding Functions
+// -------------------------------------
+
+// binary-to-text encodings supported by ENCODE and DECODE, named case-insensitively
+var binaryEncodings = map[string]struct {
+	encode func([]byte) string
+	decode func(string) ([]byte, error)
+}{
+	"BASE64": {base64.StdEncoding.EncodeToString, base64.StdEncoding.DecodeString},
+	"HEX":    {hex.EncodeToString, hex.DecodeString},
+}
+
+// encodingOf validates the arguments of ENCODE and DECODE, returning the name of the encoding,
+// or an empty name when it's NULL
+func encodingOf(fn string, params []TypedValue) (string, error) {
+	if len(params) != 2 {
+		return "", fmt.Errorf("%w: '%s' function expects %d arguments but %d were provided", ErrIllegalArguments, fn, 2, len(params))
+	}
+
+	format := params[1]
+	if format.IsNull() {
+		return "", nil
+	}
+
+	if format.Type() != VarcharType {
+		return "", fmt.Errorf("%w: '%s' function expects the name of the encoding as %s", ErrIllegalArguments, fn, VarcharType)
+	}
+
+	name := strings.ToUpper(format.RawValue().(string))
+	if _, supported := binaryEncodings[name]; !supported {
+		return "", fmt.Errorf("%w: '%s' function does not support the encoding '%s'", ErrIllegalArguments, fn, format.RawValue())
+	}
+	return name, nil
+}
+
+// EncodeFn converts a BLOB into text in the given encoding, either 'base64' or 'hex'.
+// VARCHAR values are encoded as their UTF-8 bytes.
+type EncodeFn struct{}
+
+func (f *EncodeFn) InferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return VarcharType, nil
+}
+
+func (f *EncodeFn) RequiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != VarcharType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, VarcharType, t)
+	}
+	return nil
+}
+
+func (f *EncodeFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
+	encoding, err := encodingOf(EncodeFnCall, params)
+	if err != nil {
+		return nil, err
+	}
+
+	v := params[0]
+	if v.IsNull() || encoding == "" {
+		return &NullValue{t: VarcharType}, nil
+	}
+
+	var b []byte
+
+	switch v.Type() {
+	case BLOBType:
+		b = v.RawValue().([]byte)
+	case VarcharType:
+		b = []byte(v.RawValue().(string))
+	default:
+		return nil, fmt.Errorf("%w: '%s' function expects an argument of type %s or %s", ErrIllegalArguments, EncodeFnCall, BLOBType, VarcharType)
+	}
+	return &Varchar{val: binaryEncodings[encoding].encode(b)}, nil
+}
+
+// DecodeFn converts text in the given encoding, either 'base64' or 'hex', into a BLOB.
+type DecodeFn struct{}
+
+func (f *DecodeFn) InferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return BLOBType, nil
+}
+
+func (f *DecodeFn) RequiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != BLOBType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, BLOBType, t)
+	}
+	return nil
+}
+
+func (f *DecodeFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
+	encoding, err := encodingOf(DecodeFnCall, params)
+	if err != nil {
+		return nil, err
+	}
+
+	v := params[0]
+	if v.IsNull() || encoding == "" {
+		return &NullValue{t: BLOBType}, nil
+	}
+
+	if v.Type() != VarcharType {
+		return nil, fmt.Errorf("%w: '%s' function expects an argument of type %s", ErrIllegalArguments, DecodeFnCall, VarcharType)
+	}
+
+	b, err := binaryEncodings[encoding].decode(v.RawValue().(string))
+	if err != nil {
+		return nil, fmt.Errorf("%w: '%s' function can not decode invalid %s input: %v", ErrInvalidValue, DecodeFnCall, strings.ToLower(encoding), err)
+	}
+	return &Blob{val: b}, nil
+}
+
+// -------------------------------------
+// Random Functions
+// -------------------------------------
+
+type RandomFn struct{}
+
+func (f *RandomFn) InferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return Float64Type, nil
+}
+
+func (f *RandomFn) RequiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != Float64Type {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, Float64Type, t)
+	}
+	return nil
+}
+
+func (f *RandomFn) Apply(tx *SQLTx, params []TypedValue) (TypedValue, error) {
+	if len(params) > 0 {
+		return nil, fmt.Errorf("%w: '%s' function does not expect any argument but %d were provided", ErrIllegalArguments, RandomFnCall, len(params))
+	}
+
+	if tx != nil && tx.engine.rand != nil {
+		return &Float64{val: tx.engine.rand.Float64()}, nil
+	}
+	return &Float64{val: rand.Float64()}, nil
+}
+
+// lockedRand makes a user-provided source safe for concurrent use
+type lockedRand struct {
+	mutex sync.Mutex
+	rnd   *rand.Rand
+}
+
+func newLockedRand(src rand.Source) *lockedRand {
+	return &lockedRand{rnd: rand.New(src)}
+}
+
+func (r *lockedRand) Float64() float64 {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.rnd.Float64()
+}
+
+func (r *lockedRand) Read(p []byte) (int, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.rnd.Read(p)
+}
+
 // pg functions
 
 type pgGetUserByIDFunc struct{}
@@ -16,6 +16,7 @@ limitations under the License.
 package sql
 
 import (
+	"context"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -84,3 +85,124 @@ func TestPGFunctions(t *testing.T) {
 		require.Empty(t, v.RawValue())
 	})
 }
+
+func TestHashFunctions(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		returnType SQLValueType
+		input      TypedValue
+		digest     interface{}
+	}{
+		{MD5FnCall, VarcharType, NewVarchar("The quick brown fox jumps over the lazy dog"), "9e107d9d372bb6826bd81d3542a419d6"},
+		{MD5RawFnCall, BLOBType, NewBlob([]byte{}), []byte{0xd4, 0x1d, 0x8c, 0xd9, 0x8f, 0x00, 0xb2, 0x04, 0xe9, 0x80, 0x09, 0x98, 0xec, 0xf8, 0x42, 0x7e}},
+		{SHA256FnCall, VarcharType, NewVarchar("The quick brown fox jumps over the lazy dog"), "d7a8fbb307d7809469ca9abcb0082e4f8d5651e46d3cdb762d02d0bf37c9e592"},
+		{SHA256RawFnCall, BLOBType, NewVarchar(""), []byte{
+			0xe3, 0xb0, 0xc4, 0x42, 0x98, 0xfc, 0x1c, 0x14, 0x9a, 0xfb, 0xf4, 0xc8, 0x99, 0x6f, 0xb9, 0x24,
+			0x27, 0xae, 0x41, 0xe4, 0x64, 0x9b, 0x93, 0x4c, 0xa4, 0x95, 0x99, 0x1b, 0x78, 0x52, 0xb8, 0x55,
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			f := builtinFunctions[tc.name]
+
+			funcType, err := f.InferType(nil, nil, "")
+			require.NoError(t, err)
+			require.Equal(t, tc.returnType, funcType)
+
+			require.NoError(t, f.RequiresType(tc.returnType, nil, nil, ""))
+			require.ErrorIs(t, f.RequiresType(IntegerType, nil, nil, ""), ErrInvalidTypes)
+
+			v, err := f.Apply(nil, []TypedValue{tc.input})
+			require.NoError(t, err)
+			require.Equal(t, tc.returnType, v.Type())
+			require.Equal(t, tc.digest, v.RawValue())
+
+			v, err = f.Apply(nil, []TypedValue{NewNull(AnyType)})
+			require.NoError(t, err)
+			require.True(t, v.IsNull())
+			require.Equal(t, tc.returnType, v.Type())
+
+			_, err = f.Apply(nil, []TypedValue{NewInteger(1)})
+			require.ErrorIs(t, err, ErrIllegalArguments)
+
+			_, err = f.Apply(nil, nil)
+			require.ErrorIs(t, err, ErrIllegalArguments)
+		})
+	}
+}
+
+func TestCurrentTxIDFunction(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	currentTxID := func(t *testing.T, tx *SQLTx) int64 {
+		rows, err := engine.queryAll(context.Background(), tx, "SELECT CURRENT_TX_ID()", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		return rows[0].ValuesByPosition[0].RawValue().(int64)
+	}
+
+	_, txs, err := engine.Exec(context.Background(), nil, "CREATE TABLE t (id INTEGER AUTO_INCREMENT, name VARCHAR, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+	require.Len(t, txs, 1)
+	require.EqualValues(t, txs[0].TxHeader().ID, currentTxID(t, nil))
+
+	for i := 0; i < 3; i++ {
+		before := currentTxID(t, nil)
+
+		_, txs, err := engine.Exec(context.Background(), nil, "INSERT INTO t (name) VALUES ('a')", nil)
+		require.NoError(t, err)
+		require.Len(t, txs, 1)
+
+		after := currentTxID(t, nil)
+		require.Greater(t, after, before)
+		require.EqualValues(t, txs[0].TxHeader().ID, after)
+	}
+
+	t.Run("in an expression", func(t *testing.T) {
+		id := currentTxID(t, nil)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT id FROM t WHERE current_tx_id() > id AND CURRENT_TX_ID() - 1 = @id", map[string]interface{}{"id": id - 1})
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+	})
+
+	t.Run("within transactions", func(t *testing.T) {
+		roTx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithReadOnly(true).WithExplicitClose(true))
+		require.NoError(t, err)
+		defer roTx.Cancel()
+
+		id := currentTxID(t, roTx)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO t (name) VALUES ('b')", nil)
+		require.NoError(t, err)
+
+		// read-only transactions keep reading the same transaction
+		require.Equal(t, id, currentTxID(t, roTx))
+		require.Equal(t, id+1, currentTxID(t, nil))
+
+		rwTx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithExplicitClose(true))
+		require.NoError(t, err)
+		defer rwTx.Cancel()
+
+		_, _, err = engine.Exec(context.Background(), rwTx, "INSERT INTO t (name) VALUES ('c')", nil)
+		require.NoError(t, err)
+		require.Equal(t, id+1, currentTxID(t, rwTx))
+
+		err = rwTx.Commit(context.Background())
+		require.NoError(t, err)
+		require.EqualValues(t, rwTx.TxHeader().ID, currentTxID(t, nil))
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		var f CurrentTxIDFn
+
+		funcType, err := f.InferType(nil, nil, "")
+		require.NoError(t, err)
+		require.Equal(t, IntegerType, funcType)
+
+		require.NoError(t, f.RequiresType(IntegerType, nil, nil, ""))
+		require.ErrorIs(t, f.RequiresType(VarcharType, nil, nil, ""), ErrInvalidTypes)
+
+		_, err = f.Apply(nil, []TypedValue{NewInteger(1)})
+		require.ErrorIs(t, err, ErrIllegalArguments)
+	})
+}
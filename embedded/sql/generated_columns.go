@@ -0,0 +1,163 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import "fmt"
+
+// validateGeneratedColumns checks the expressions of the generated columns only refer to
+// non-generated columns of the table and result in values of the type of the column
+func validateGeneratedColumns(table *Table) error {
+	cols := make(map[string]ColDescriptor, len(table.cols))
+
+	for _, col := range table.cols {
+		if col.generatedExp != nil {
+			continue
+		}
+
+		des := ColDescriptor{Table: table.name, Column: col.colName, Type: col.colType}
+		cols[des.Selector()] = des
+	}
+
+	for _, col := range table.cols {
+		if col.generatedExp == nil {
+			continue
+		}
+
+		if col.autoIncrement || table.primaryIndex.IncludesCol(col.id) {
+			return fmt.Errorf("%w: generated column '%s' can not be part of the primary key", ErrIllegalArguments, col.colName)
+		}
+
+		for _, sel := range col.generatedExp.selectors() {
+			colSel, ok := sel.(*ColSelector)
+			if !ok {
+				return fmt.Errorf("%w: generated column '%s' can only be computed from columns of the same row", ErrIllegalArguments, col.colName)
+			}
+
+			if _, ok := cols[EncodeSelector(colSel.resolve(table.name))]; !ok {
+				return fmt.Errorf("%w: generated column '%s' refers to '%s' which is not a non-generated column of table '%s'", ErrIllegalArguments, col.colName, colSel.col, table.name)
+			}
+		}
+
+		params := make(map[string]SQLValueType)
+
+		t, err := col.generatedExp.inferType(cols, params, table.name)
+		if err != nil {
+			return fmt.Errorf("%w: generated column '%s'", err, col.colName)
+		}
+
+		if len(params) > 0 {
+			return fmt.Errorf("%w: generated column '%s' can not be computed from parameters", ErrIllegalArguments, col.colName)
+		}
+
+		if t != AnyType && t != col.colType {
+			return fmt.Errorf("%w: generated column '%s' is of type %s but its expression is of type %s", ErrInvalidTypes, col.colName, col.colType, t)
+		}
+	}
+	return nil
+}
+
+func sameGeneratedExp(exp1, exp2 ValueExp) bool {
+	if exp1 == nil || exp2 == nil {
+		return exp1 == exp2
+	}
+	return exp1.String() == exp2.String()
+}
+
+// generatedColumnUsing returns a generated column computed from the given column, if any
+func (t *Table) generatedColumnUsing(col *Column) *Column {
+	for _, c := range t.cols {
+		if c.generatedExp == nil {
+			continue
+		}
+
+		for _, sel := range c.generatedExp.selectors() {
+			_, _, colName := sel.resolve(t.name)
+			if colName == col.colName {
+				return c
+			}
+		}
+	}
+	return nil
+}
+
+// computeGeneratedColumns evaluates the expressions of the generated columns over the row,
+// which holds the values of every column of the table by position and selector
+func computeGeneratedColumns(tx *SQLTx, table *Table, row *Row, valuesByColID map[uint32]TypedValue) error {
+	for i, col := range table.cols {
+		if col.generatedExp == nil {
+			continue
+		}
+
+		nullOperand, err := hasNullOperand(tx, col.generatedExp, row, table.name)
+		if err != nil {
+			return fmt.Errorf("%w: generated column '%s'", err, col.colName)
+		}
+
+		var val TypedValue = NewNull(AnyType)
+
+		if !nullOperand {
+			val, err = col.generatedExp.reduce(tx, row, table.name)
+			if err != nil {
+				return fmt.Errorf("%w: generated column '%s'", err, col.colName)
+			}
+		}
+
+		if val.IsNull() {
+			if col.notNull {
+				return fmt.Errorf("%w (%s)", ErrNotNullableColumnCannotBeNull, col.colName)
+			}
+			val = NewNull(AnyType)
+		} else if val.Type() != col.colType {
+			return fmt.Errorf("%w: generated column '%s' is of type %s but a value of type %s was computed", ErrInvalidTypes, col.colName, col.colType, val.Type())
+		}
+
+		valuesByColID[col.id] = val
+
+		row.ValuesByPosition[i] = val
+		row.ValuesBySelector[EncodeSelector("", table.name, col.colName)] = val
+	}
+	return nil
+}
+
+// hasNullOperand reports whether an operand of the arithmetic expression is NULL,
+// in which case the generated value is NULL as well
+func hasNullOperand(tx *SQLTx, exp ValueExp, row *Row, implicitTable string) (bool, error) {
+	numExp, ok := exp.(*NumExp)
+	if !ok {
+		return false, nil
+	}
+
+	for _, operand := range []ValueExp{numExp.left, numExp.right} {
+		if _, ok := operand.(*NumExp); ok {
+			isNull, err := hasNullOperand(tx, operand, row, implicitTable)
+			if err != nil || isNull {
+				return isNull, err
+			}
+			continue
+		}
+
+		val, err := operand.reduce(tx, row, implicitTable)
+		if err != nil {
+			return false, err
+		}
+
+		if val.IsNull() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,217 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeneratedColumns(t *testing.T) {
+	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(t, err)
+	defer st.Close()
+
+	engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		CREATE TABLE orders (
+			id INTEGER AUTO_INCREMENT,
+			price INTEGER,
+			qty INTEGER,
+			item VARCHAR,
+			total INTEGER GENERATED ALWAYS AS (price * qty) STORED,
+			label VARCHAR[32] GENERATED ALWAYS AS (UPPER(item)) STORED NOT NULL,
+			PRIMARY KEY id
+		)`, nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE INDEX ON orders(total)", nil)
+	require.NoError(t, err)
+
+	queryTotals := func(t *testing.T, e *Engine) [][]interface{} {
+		rows, err := e.queryAll(context.Background(), nil, "SELECT id, total, label FROM orders ORDER BY id", nil)
+		require.NoError(t, err)
+
+		values := make([][]interface{}, len(rows))
+		for i, row := range rows {
+			values[i] = []interface{}{
+				row.ValuesByPosition[0].RawValue(),
+				row.ValuesByPosition[1].RawValue(),
+				row.ValuesByPosition[2].RawValue(),
+			}
+		}
+		return values
+	}
+
+	t.Run("values are computed on insert", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "INSERT INTO orders (price, qty, item) VALUES (10, 2, 'pen'), (5, 7, 'ink'), (2, 3, 'cup')", nil)
+		require.NoError(t, err)
+
+		require.Equal(t, [][]interface{}{
+			{int64(1), int64(20), "PEN"},
+			{int64(2), int64(35), "INK"},
+			{int64(3), int64(6), "CUP"},
+		}, queryTotals(t, engine))
+
+		// generated columns are stored thus they can be indexed
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT id FROM orders USE INDEX ON (total) WHERE total > 30", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, int64(2), rows[0].ValuesByPosition[0].RawValue())
+
+		_, _, err = engine.Exec(context.Background(), nil, "UPSERT INTO orders (id, price, qty, item) VALUES (3, 1, 3, 'mug')", nil)
+		require.NoError(t, err)
+
+		require.Equal(t, []interface{}{int64(3), int64(3), "MUG"}, queryTotals(t, engine)[2])
+	})
+
+	t.Run("generated columns can not be written", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "INSERT INTO orders (price, qty, item, total) VALUES (1, 1, 'pen', 100)", nil)
+		require.ErrorIs(t, err, ErrGeneratedColumnCanNotBeSet)
+
+		_, _, err = engine.Exec(context.Background(), nil, "UPSERT INTO orders (id, total) VALUES (1, 100)", nil)
+		require.ErrorIs(t, err, ErrGeneratedColumnCanNotBeSet)
+
+		_, _, err = engine.Exec(context.Background(), nil, "UPDATE orders SET total = 100 WHERE id = 1", nil)
+		require.ErrorIs(t, err, ErrGeneratedColumnCanNotBeSet)
+
+		// a NULL value can not be computed for a NOT NULL generated column
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO orders (price, qty) VALUES (1, 1)", nil)
+		require.ErrorIs(t, err, ErrNotNullableColumnCannotBeNull)
+
+	})
+
+	t.Run("values are computed again on update", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "UPDATE orders SET qty = qty + 1 WHERE id <= 2", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "UPDATE orders SET item = 'jar' WHERE id = 3", nil)
+		require.NoError(t, err)
+
+		require.Equal(t, [][]interface{}{
+			{int64(1), int64(30), "PEN"},
+			{int64(2), int64(40), "INK"},
+			{int64(3), int64(3), "JAR"},
+		}, queryTotals(t, engine))
+
+		_, _, err = engine.Exec(context.Background(), nil, "UPDATE orders SET item = NULL WHERE id = 3", nil)
+		require.ErrorIs(t, err, ErrNotNullableColumnCannotBeNull)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT id FROM orders USE INDEX ON (total) WHERE total > 30", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, int64(2), rows[0].ValuesByPosition[0].RawValue())
+	})
+
+	t.Run("generation expressions are stored in the catalog", func(t *testing.T) {
+		reopened, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+		require.NoError(t, err)
+
+		_, _, err = reopened.Exec(context.Background(), nil, "INSERT INTO orders (price, qty, item) VALUES (3, 3, 'box')", nil)
+		require.NoError(t, err)
+
+		require.Equal(t, []interface{}{int64(4), int64(9), "BOX"}, queryTotals(t, reopened)[3])
+
+		catalog, err := reopened.Catalog(context.Background(), nil)
+		require.NoError(t, err)
+
+		table, err := catalog.GetTableByName("orders")
+		require.NoError(t, err)
+
+		total, err := table.GetColumnByName("total")
+		require.NoError(t, err)
+		require.True(t, total.IsGenerated())
+		require.True(t, total.IsNullable())
+
+		label, err := table.GetColumnByName("label")
+		require.NoError(t, err)
+		require.True(t, label.IsGenerated())
+		require.False(t, label.IsNullable())
+
+		price, err := table.GetColumnByName("price")
+		require.NoError(t, err)
+		require.False(t, price.IsGenerated())
+	})
+
+	t.Run("source columns can not be dropped or renamed", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "ALTER TABLE orders DROP COLUMN price", nil)
+		require.ErrorIs(t, err, ErrCannotDropColumn)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE orders RENAME COLUMN item TO name", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE orders ADD COLUMN double INTEGER GENERATED ALWAYS AS (price * 2) STORED", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+	})
+
+	t.Run("invalid generated columns", func(t *testing.T) {
+		for _, stmt := range []string{
+			"CREATE TABLE t (id INTEGER GENERATED ALWAYS AS (1) STORED, PRIMARY KEY id)",
+			"CREATE TABLE t (id INTEGER, a INTEGER GENERATED ALWAYS AS (missing + 1) STORED, PRIMARY KEY id)",
+			"CREATE TABLE t (id INTEGER, a INTEGER GENERATED ALWAYS AS (id + 1) STORED, b INTEGER GENERATED ALWAYS AS (a + 1) STORED, PRIMARY KEY id)",
+			"CREATE TABLE t (id INTEGER, a INTEGER GENERATED ALWAYS AS (id + @p) STORED, PRIMARY KEY id)",
+		} {
+			_, _, err := engine.Exec(context.Background(), nil, stmt, nil)
+			require.ErrorIs(t, err, ErrIllegalArguments, stmt)
+		}
+
+		_, _, err := engine.Exec(context.Background(), nil, "CREATE TABLE t (id INTEGER, a VARCHAR GENERATED ALWAYS AS (id + 1) STORED, PRIMARY KEY id)", nil)
+		require.ErrorIs(t, err, ErrInvalidTypes)
+
+		// keywords can still be used as identifiers
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE t (id INTEGER, generated INTEGER, stored INTEGER, PRIMARY KEY id)", nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("null operands produce null values", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, `
+			CREATE TABLE readings (
+				"Id" INTEGER AUTO_INCREMENT,
+				"Val" INTEGER,
+				"Gen" INTEGER GENERATED ALWAYS AS ("Val" * 2) STORED,
+				PRIMARY KEY "Id"
+			);
+			INSERT INTO readings ("Val") VALUES (NULL), (3);
+		`, nil)
+		require.NoError(t, err)
+
+		rows, err := engine.queryAll(context.Background(), nil, `SELECT "Gen" FROM readings ORDER BY "Id"`, nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		require.True(t, rows[0].ValuesByPosition[0].IsNull())
+		require.Equal(t, int64(6), rows[1].ValuesByPosition[0].RawValue())
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO orders (price, item) VALUES (1, 'pen')", nil)
+		require.NoError(t, err)
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT total FROM orders WHERE qty IS NULL", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.True(t, rows[0].ValuesByPosition[0].IsNull())
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE gauges (id INTEGER, val INTEGER, gen INTEGER GENERATED ALWAYS AS ((val + 1) * 2) STORED NOT NULL, PRIMARY KEY id)", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO gauges (id) VALUES (1)", nil)
+		require.ErrorIs(t, err, ErrNotNullableColumnCannotBeNull)
+	})
+}
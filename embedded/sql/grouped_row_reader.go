@@ -20,10 +20,15 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"iter"
 
 	"github.com/codenotary/immudb/embedded/store"
 )
 
+// groupedRowReader aggregates the rows of rowReader, which must be sorted on the GROUP BY columns.
+// Each group is emitted as soon as a row of the next group is read, thus only the aggregations of
+// a single group are kept in memory. Rows are sorted beforehand unless the index being scanned
+// already returns them in the grouping order.
 type groupedRowReader struct {
 	rowReader RowReader
 
@@ -34,6 +39,10 @@ type groupedRowReader struct {
 
 	currRow *Row
 	empty   bool
+
+	rowCount *int64 // when set, the number of rows of rowReader, which are then not read
+
+	lookahead rowLookahead
 }
 
 func newGroupedRowReader(rowReader RowReader, allAggregations bool, selectors []*AggColSelector, groupBy []*ColSelector) (*groupedRowReader, error) {
@@ -117,25 +126,49 @@ func (gr *groupedRowReader) colsBySelector(ctx context.Context) (map[string]ColD
 		}
 
 		des := ColDescriptor{
-			AggFn:  aggFn,
-			Table:  table,
-			Column: col,
-			Type:   IntegerType,
+			AggFn:    aggFn,
+			Table:    table,
+			Column:   col,
+			Type:     IntegerType,
+			Nullable: true,
 		}
 
 		encSel := des.Selector()
 
-		if aggFn == COUNT {
+		for _, distinctCol := range sel.distinct {
+			_, ok := colDescriptors[EncodeSelector(distinctCol.resolve(gr.rowReader.TableAlias()))]
+			if !ok {
+				return nil, fmt.Errorf("%w (%s)", ErrColumnDoesNotExist, distinctCol.col)
+			}
+		}
+
+		if sel.isCount() {
+			des.Nullable = false
 			colDescriptors[encSel] = des
 			continue
 		}
 
+		if sel.aggregate != nil && col == "*" {
+			return nil, fmt.Errorf("%w: '%s' aggregate must be applied to a column", ErrIllegalArguments, aggFn)
+		}
+
 		colDesc, ok := colDescriptors[EncodeSelector("", table, col)]
 		if !ok {
 			return nil, fmt.Errorf("%w (%s)", ErrColumnDoesNotExist, col)
 		}
 
 		des.Type = colDesc.Type
+
+		if sel.aggregate != nil {
+			argType := sel.aggregate.spec.ArgType
+			if argType != AnyType && argType != colDesc.Type {
+				return nil, fmt.Errorf("%w: '%s' aggregate expects a column of type %s but %s was provided", ErrInvalidTypes, aggFn, argType, colDesc.Type)
+			}
+			if retType := sel.aggregate.spec.ReturnType; retType != AnyType {
+				des.Type = retType
+			}
+		}
+
 		colDescriptors[encSel] = des
 	}
 	return colDescriptors, nil
@@ -169,6 +202,8 @@ func zeroForType(t SQLValueType) TypedValue {
 		return &Blob{}
 	case TimestampType:
 		return &Timestamp{}
+	case PointType:
+		return &Point{}
 	}
 	return nil
 }
@@ -181,7 +216,23 @@ func (gr *groupedRowReader) Parameters() map[string]interface{} {
 	return gr.rowReader.Parameters()
 }
 
+func (gr *groupedRowReader) All(ctx context.Context) iter.Seq2[*Row, error] {
+	return allRows(ctx, gr)
+}
+
 func (gr *groupedRowReader) Read(ctx context.Context) (*Row, error) {
+	return gr.lookahead.read(ctx, gr.readRow)
+}
+
+func (gr *groupedRowReader) Peek(ctx context.Context) (*Row, error) {
+	return gr.lookahead.peek(ctx, gr.readRow)
+}
+
+func (gr *groupedRowReader) readRow(ctx context.Context) (*Row, error) {
+	if gr.rowCount != nil {
+		return gr.emitRowCount(ctx)
+	}
+
 	for {
 		row, err := gr.rowReader.Read(ctx)
 		if errors.Is(err, store.ErrNoMoreEntries) {
@@ -216,6 +267,11 @@ func (gr *groupedRowReader) Read(ctx context.Context) (*Row, error) {
 			if err != nil {
 				return nil, err
 			}
+
+			err = gr.finalizeAggregations(r)
+			if err != nil {
+				return nil, err
+			}
 			return r, nil
 		}
 
@@ -231,6 +287,14 @@ func updateRow(currRow, newRow *Row) error {
 	for _, v := range currRow.ValuesBySelector {
 		aggV, isAggregatedValue := v.(AggregatedValue)
 
+		if distinctV, ok := v.(*CountDistinctValue); ok {
+			err := distinctV.updateWithRow(newRow)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
 		if isAggregatedValue {
 			if aggV.ColBounded() {
 				val, exists := newRow.ValuesBySelector[aggV.Selector()]
@@ -273,6 +337,32 @@ func (gr *groupedRowReader) emitCurrentRow(ctx context.Context) (*Row, error) {
 	r := gr.currRow
 	gr.currRow = nil
 
+	err := gr.finalizeAggregations(r)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// emitRowCount returns the only row of the aggregations when they all count the rows of rowReader
+func (gr *groupedRowReader) emitRowCount(ctx context.Context) (*Row, error) {
+	if !gr.empty {
+		return nil, ErrNoMoreRows
+	}
+
+	r, err := gr.zeroRow(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, sel := range gr.selectors {
+		count := &Integer{val: *gr.rowCount}
+
+		r.ValuesByPosition[i] = count
+		r.ValuesBySelector[EncodeSelector(sel.resolve(gr.rowReader.TableAlias()))] = count
+	}
+
+	gr.empty = false
 	return r, nil
 }
 
@@ -293,8 +383,13 @@ func (gr *groupedRowReader) zeroRow(ctx context.Context) (*Row, error) {
 		encSel := EncodeSelector(aggFn, table, col)
 
 		var zero TypedValue
-		if aggFn == COUNT {
+		if sel.isCount() {
 			zero = zeroForType(IntegerType)
+		} else if sel.aggregate != nil {
+			zero, err = sel.aggregate.newValue(encSel).finalize()
+			if err != nil {
+				return nil, err
+			}
 		} else {
 			zero = zeroForType(colsBySelector[encSel].Type)
 		}
@@ -309,7 +404,18 @@ func (gr *groupedRowReader) initAggregations(row *Row) error {
 	// augment row with aggregated values
 	for _, sel := range gr.selectors {
 		aggFn, table, col := sel.resolve(gr.rowReader.TableAlias())
-		v, err := initAggValue(aggFn, table, col)
+
+		if sel.distinct != nil {
+			sels := make([]string, len(sel.distinct))
+			for i, distinctCol := range sel.distinct {
+				sels[i] = EncodeSelector(distinctCol.resolve(gr.rowReader.TableAlias()))
+			}
+
+			row.ValuesBySelector[EncodeSelector(aggFn, table, col)] = newCountDistinctValue(gr.Tx(), sels)
+			continue
+		}
+
+		v, err := initAggValue(gr.Tx(), sel, aggFn, table, col)
 		if err != nil {
 			return err
 		}
@@ -335,7 +441,43 @@ func (gr *groupedRowReader) initAggregations(row *Row) error {
 	return updateRow(row, row)
 }
 
-func initAggValue(aggFn, table, col string) (TypedValue, error) {
+// finalizableValue is implemented by aggregations whose result is calculated once all the rows were aggregated
+type finalizableValue interface {
+	finalize() (TypedValue, error)
+}
+
+// finalizeAggregations replaces the state of user-defined aggregations and COUNT(DISTINCT ...) with their results
+func (gr *groupedRowReader) finalizeAggregations(row *Row) error {
+	finalized := false
+
+	for sel, v := range row.ValuesBySelector {
+		aggV, isFinalizable := v.(finalizableValue)
+		if !isFinalizable {
+			continue
+		}
+
+		val, err := aggV.finalize()
+		if err != nil {
+			return err
+		}
+
+		row.ValuesBySelector[sel] = val
+		finalized = true
+	}
+
+	if finalized {
+		for i, col := range gr.cols {
+			row.ValuesByPosition[i] = row.ValuesBySelector[col.Selector()]
+		}
+	}
+	return nil
+}
+
+func initAggValue(tx *SQLTx, sel *AggColSelector, aggFn, table, col string) (TypedValue, error) {
+	if sel.aggregate != nil {
+		return sel.aggregate.newValue(EncodeSelector("", table, col)), nil
+	}
+
 	var v TypedValue
 	switch aggFn {
 	case COUNT:
@@ -349,8 +491,9 @@ func initAggValue(aggFn, table, col string) (TypedValue, error) {
 	case SUM:
 		{
 			v = &SumValue{
-				val: &NullValue{t: AnyType},
-				sel: EncodeSelector("", table, col),
+				val:      &NullValue{t: AnyType},
+				sel:      EncodeSelector("", table, col),
+				overflow: tx.integerOverflow(),
 			}
 		}
 	case MIN:
@@ -370,8 +513,9 @@ func initAggValue(aggFn, table, col string) (TypedValue, error) {
 	case AVG:
 		{
 			v = &AVGValue{
-				s:   &NullValue{t: AnyType},
-				sel: EncodeSelector("", table, col),
+				s:        &NullValue{t: AnyType},
+				sel:      EncodeSelector("", table, col),
+				overflow: tx.integerOverflow(),
 			}
 		}
 	}
@@ -379,5 +523,7 @@ func initAggValue(aggFn, table, col string) (TypedValue, error) {
 }
 
 func (gr *groupedRowReader) Close() error {
+	gr.lookahead.discard()
+
 	return gr.rowReader.Close()
 }
@@ -18,8 +18,10 @@ package sql
 
 import (
 	"context"
+	"errors"
 	"testing"
 
+	"github.com/codenotary/immudb/embedded/logger"
 	"github.com/codenotary/immudb/embedded/store"
 	"github.com/stretchr/testify/require"
 )
@@ -68,3 +70,136 @@ func TestGroupedRowReader(t *testing.T) {
 	require.NotNil(t, scanSpecs.Index)
 	require.True(t, scanSpecs.Index.IsPrimary())
 }
+
+func setupGroupsTest(t testing.TB, groups, rowsPerGroup int) *Engine {
+	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true).WithLogger(logger.NewMemoryLoggerWithLevel(logger.LogError)))
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+
+	engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		CREATE TABLE events (id INTEGER AUTO_INCREMENT, grp INTEGER, other INTEGER, amount INTEGER, PRIMARY KEY id);
+		CREATE INDEX ON events(grp);
+	`, nil)
+	require.NoError(t, err)
+
+	rows := func(yield func(*Row) bool) {
+		for i := 0; i < groups*rowsPerGroup; i++ {
+			// rows of a group are not inserted consecutively
+			grp := int64(i % groups)
+
+			row := &Row{ValuesByPosition: []TypedValue{
+				&Integer{val: grp},
+				&Integer{val: grp},
+				&Integer{val: int64(i)},
+			}}
+			if !yield(row) {
+				return
+			}
+		}
+	}
+
+	opts := DefaultBulkLoadOpts()
+	opts.Columns = []string{"grp", "other", "amount"}
+
+	_, err = engine.BulkLoad(context.Background(), "events", rows, opts)
+	require.NoError(t, err)
+
+	return engine
+}
+
+// groupedReaderOf returns the grouped reader of a query of the form SELECT ... GROUP BY ...
+func groupedReaderOf(t testing.TB, r RowReader) *groupedRowReader {
+	pr, ok := r.(*projectedRowReader)
+	require.True(t, ok)
+
+	gr, ok := pr.rowReader.(*groupedRowReader)
+	require.True(t, ok)
+
+	return gr
+}
+
+func TestGroupedRowReaderStreaming(t *testing.T) {
+	const groups, rowsPerGroup = 100, 10
+
+	engine := setupGroupsTest(t, groups, rowsPerGroup)
+
+	t.Run("groups are emitted incrementally when rows are scanned in grouping order", func(t *testing.T) {
+		r, err := engine.Query(context.Background(), nil, "SELECT grp, COUNT(*), SUM(amount) FROM events GROUP BY grp", nil)
+		require.NoError(t, err)
+		defer r.Close()
+
+		require.Empty(t, r.ScanSpecs().groupBySortExps)
+		require.Equal(t, "events(grp)", r.ScanSpecs().Index.Name())
+
+		gr := groupedReaderOf(t, r)
+
+		_, isRawReader := gr.rowReader.(*rawRowReader)
+		require.True(t, isRawReader)
+
+		counter := &countingRowReader{RowReader: gr.rowReader}
+		gr.rowReader = counter
+
+		for g := 0; g < groups; g++ {
+			row, err := r.Read(context.Background())
+			require.NoError(t, err)
+
+			require.Equal(t, int64(g), row.ValuesByPosition[0].RawValue())
+			require.Equal(t, int64(rowsPerGroup), row.ValuesByPosition[1].RawValue())
+
+			// only the rows of the emitted group plus the first row of the next one were read
+			require.Equal(t, min((g+1)*rowsPerGroup+1, groups*rowsPerGroup), counter.reads)
+		}
+
+		_, err = r.Read(context.Background())
+		require.ErrorIs(t, err, ErrNoMoreRows)
+	})
+
+	t.Run("rows are sorted when no index provides the grouping order", func(t *testing.T) {
+		r, err := engine.Query(context.Background(), nil, "SELECT other, COUNT(*) FROM events GROUP BY other", nil)
+		require.NoError(t, err)
+		defer r.Close()
+
+		require.NotEmpty(t, r.ScanSpecs().groupBySortExps)
+
+		gr := groupedReaderOf(t, r)
+
+		_, isSortReader := gr.rowReader.(*sortRowReader)
+		require.True(t, isSortReader)
+
+		n := 0
+		for {
+			row, err := r.Read(context.Background())
+			if errors.Is(err, ErrNoMoreRows) {
+				break
+			}
+			require.NoError(t, err)
+
+			require.Equal(t, int64(n), row.ValuesByPosition[0].RawValue())
+			require.Equal(t, int64(rowsPerGroup), row.ValuesByPosition[1].RawValue())
+			n++
+		}
+		require.Equal(t, groups, n)
+	})
+}
+
+func BenchmarkGroupBy(b *testing.B) {
+	const groups, rowsPerGroup = 10_000, 2
+
+	engine := setupGroupsTest(b, groups, rowsPerGroup)
+
+	for name, sql := range map[string]string{
+		"index order": "SELECT grp, COUNT(*), SUM(amount) FROM events GROUP BY grp",
+		"sorted":      "SELECT other, COUNT(*), SUM(amount) FROM events GROUP BY other",
+	} {
+		b.Run(name, func(b *testing.B) {
+			for b.Loop() {
+				rows, err := engine.queryAll(context.Background(), nil, sql, nil)
+				require.NoError(b, err)
+				require.Len(b, rows, groups)
+			}
+		})
+	}
+}
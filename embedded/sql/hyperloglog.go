@@ -0,0 +1,163 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/bits"
+)
+
+const (
+	approxCountDistinctFnName = "APPROX_COUNT_DISTINCT"
+
+	MinHLLPrecision     = 4
+	MaxHLLPrecision     = 18
+	DefaultHLLPrecision = 14 // 16K registers, ~0.81% standard error
+)
+
+// hyperLogLog estimates the number of distinct values added to it using 2^precision
+// registers of one byte each. The standard error of the estimate is 1.04/sqrt(2^precision).
+type hyperLogLog struct {
+	precision uint8
+	registers []uint8
+}
+
+func newHyperLogLog(precision uint8) *hyperLogLog {
+	return &hyperLogLog{
+		precision: precision,
+		registers: make([]uint8, 1<<precision),
+	}
+}
+
+func (h *hyperLogLog) add(hash uint64) {
+	idx := hash >> (64 - h.precision)
+
+	// the bit set after the shift bounds the rank when the remaining bits are all zeros
+	rank := uint8(bits.LeadingZeros64(hash<<h.precision|1<<(h.precision-1))) + 1
+
+	if rank > h.registers[idx] {
+		h.registers[idx] = rank
+	}
+}
+
+func (h *hyperLogLog) merge(other *hyperLogLog) error {
+	if h.precision != other.precision {
+		return fmt.Errorf("%w: hyperloglog precisions do not match", ErrIllegalArguments)
+	}
+
+	for i, r := range other.registers {
+		if r > h.registers[i] {
+			h.registers[i] = r
+		}
+	}
+	return nil
+}
+
+func (h *hyperLogLog) estimate() uint64 {
+	m := float64(len(h.registers))
+
+	var sum float64
+	var zeros int
+
+	for _, r := range h.registers {
+		sum += 1 / float64(uint64(1)<<r)
+		if r == 0 {
+			zeros++
+		}
+	}
+
+	e := hllAlpha(len(h.registers)) * m * m / sum
+
+	if e <= 2.5*m && zeros > 0 {
+		// small range correction
+		e = m * math.Log(m/float64(zeros))
+	}
+	return uint64(math.Round(e))
+}
+
+func hllAlpha(m int) float64 {
+	switch m {
+	case 16:
+		return 0.673
+	case 32:
+		return 0.697
+	case 64:
+		return 0.709
+	}
+	return 0.7213 / (1 + 1.079/float64(m))
+}
+
+// hashValue returns a well distributed hash of the encoded value,
+// the output of FNV-1a is mixed with the finalizer of MurmurHash3
+func hashValue(val TypedValue) (uint64, error) {
+	encVal, err := EncodeValue(val, val.Type(), 0)
+	if err != nil {
+		return 0, err
+	}
+
+	h := fnv.New64a()
+	h.Write(encVal)
+
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+
+	return x, nil
+}
+
+// newApproxCountDistinct returns the built-in APPROX_COUNT_DISTINCT aggregation, which
+// estimates the number of distinct non-null values of a column using a fixed amount of memory
+func newApproxCountDistinct(precision uint8) *userAggregate {
+	return &userAggregate{
+		name: approxCountDistinctFnName,
+		spec: AggregateFunc{
+			ArgType:    AnyType,
+			ReturnType: IntegerType,
+			Init: func() interface{} {
+				return newHyperLogLog(precision)
+			},
+			Accumulate: func(state interface{}, val TypedValue) (interface{}, error) {
+				hash, err := hashValue(val)
+				if err != nil {
+					return nil, err
+				}
+
+				hll := state.(*hyperLogLog)
+				hll.add(hash)
+
+				return hll, nil
+			},
+			Merge: func(state, other interface{}) (interface{}, error) {
+				hll := state.(*hyperLogLog)
+
+				err := hll.merge(other.(*hyperLogLog))
+				if err != nil {
+					return nil, err
+				}
+				return hll, nil
+			},
+			Finalize: func(state interface{}) (TypedValue, error) {
+				return &Integer{val: int64(state.(*hyperLogLog).estimate())}, nil
+			},
+		},
+	}
+}
@@ -0,0 +1,235 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"strings"
+)
+
+// The information_schema views expose the catalog of the transaction they're queried from,
+// e.g. SELECT * FROM information_schema.columns WHERE table_name = 'mytable'
+const InformationSchema = "information_schema"
+
+var informationSchemaTablesCols = []ColDescriptor{
+	{
+		Column: "table_name",
+		Type:   VarcharType,
+	},
+	{
+		Column: "table_type",
+		Type:   VarcharType,
+	},
+	{
+		Column:   "comment",
+		Type:     VarcharType,
+		Nullable: true,
+	},
+}
+
+type informationSchemaTablesResolver struct{}
+
+func (r *informationSchemaTablesResolver) Resolve(ctx context.Context, tx *SQLTx, alias string) (RowReader, error) {
+	catalog := tx.Catalog()
+
+	tables := catalog.GetTables()
+	views := catalog.GetViews()
+
+	rows := make([][]ValueExp, 0, len(tables)+len(views))
+
+	for _, t := range tables {
+		rows = append(rows, []ValueExp{
+			NewVarchar(t.Name()),      // table_name
+			NewVarchar("BASE TABLE"),  // table_type
+			commentValue(t.Comment()), // comment
+		})
+	}
+
+	for _, v := range views {
+		rows = append(rows, []ValueExp{
+			NewVarchar(v.Name()), // table_name
+			NewVarchar("VIEW"),   // table_type
+			NewNull(VarcharType), // comment
+		})
+	}
+
+	return NewValuesRowReader(tx, nil, informationSchemaTablesCols, true, alias, rows)
+}
+
+func (r *informationSchemaTablesResolver) Table() string {
+	return InformationSchema + ".tables"
+}
+
+var informationSchemaColumnsCols = []ColDescriptor{
+	{
+		Column: "table_name",
+		Type:   VarcharType,
+	},
+	{
+		Column: "column_name",
+		Type:   VarcharType,
+	},
+	{
+		Column: "ordinal_position",
+		Type:   IntegerType,
+	},
+	{
+		Column: "data_type",
+		Type:   VarcharType,
+	},
+	{
+		Column:   "character_maximum_length",
+		Type:     IntegerType,
+		Nullable: true,
+	},
+	{
+		Column: "is_nullable",
+		Type:   VarcharType,
+	},
+	{
+		Column: "is_auto_increment",
+		Type:   BooleanType,
+	},
+	{
+		Column: "is_primary_key",
+		Type:   BooleanType,
+	},
+	{
+		Column:   "comment",
+		Type:     VarcharType,
+		Nullable: true,
+	},
+}
+
+type informationSchemaColumnsResolver struct{}
+
+func (r *informationSchemaColumnsResolver) Resolve(ctx context.Context, tx *SQLTx, alias string) (RowReader, error) {
+	var rows [][]ValueExp
+
+	for _, t := range tx.Catalog().GetTables() {
+		pk := t.PrimaryIndex()
+
+		for i, c := range t.Cols() {
+			// unbounded lengths and fixed-size types are reported as NULL
+			var maxLen ValueExp = NewNull(IntegerType)
+			if (c.Type() == VarcharType || c.Type() == BLOBType) && c.MaxLen() > 0 {
+				maxLen = NewInteger(int64(c.MaxLen()))
+			}
+
+			isPK := pk.IncludesCol(c.ID())
+
+			// primary key columns can not be NULL, even if not declared NOT NULL
+			isNullable := "YES"
+			if isPK || !c.IsNullable() {
+				isNullable = "NO"
+			}
+
+			rows = append(rows, []ValueExp{
+				NewVarchar(t.Name()),           // table_name
+				NewVarchar(c.Name()),           // column_name
+				NewInteger(int64(i + 1)),       // ordinal_position
+				NewVarchar(string(c.Type())),   // data_type
+				maxLen,                         // character_maximum_length
+				NewVarchar(isNullable),         // is_nullable
+				NewBool(c.IsAutoIncremental()), // is_auto_increment
+				NewBool(isPK),                  // is_primary_key
+				commentValue(c.Comment()),      // comment
+			})
+		}
+	}
+
+	return NewValuesRowReader(tx, nil, informationSchemaColumnsCols, true, alias, rows)
+}
+
+func (r *informationSchemaColumnsResolver) Table() string {
+	return InformationSchema + ".columns"
+}
+
+var informationSchemaIndexesCols = []ColDescriptor{
+	{
+		Column: "table_name",
+		Type:   VarcharType,
+	},
+	{
+		Column: "index_name",
+		Type:   VarcharType,
+	},
+	{
+		Column: "column_names",
+		Type:   VarcharType,
+	},
+	{
+		Column: "is_unique",
+		Type:   BooleanType,
+	},
+	{
+		Column: "is_primary",
+		Type:   BooleanType,
+	},
+	{
+		Column: "is_fulltext",
+		Type:   BooleanType,
+	},
+}
+
+type informationSchemaIndexesResolver struct{}
+
+func (r *informationSchemaIndexesResolver) Resolve(ctx context.Context, tx *SQLTx, alias string) (RowReader, error) {
+	var rows [][]ValueExp
+
+	for _, t := range tx.Catalog().GetTables() {
+		indexes := append(t.GetIndexes(), t.GetFullTextIndexes()...)
+
+		for _, idx := range indexes {
+			colNames := make([]string, len(idx.Cols()))
+			for i, c := range idx.Cols() {
+				colNames[i] = c.Name()
+			}
+
+			rows = append(rows, []ValueExp{
+				NewVarchar(t.Name()),                    // table_name
+				NewVarchar(idx.Name()),                  // index_name
+				NewVarchar(strings.Join(colNames, ",")), // column_names
+				NewBool(idx.IsUnique()),                 // is_unique
+				NewBool(idx.IsPrimary()),                // is_primary
+				NewBool(idx.IsFullText()),               // is_fulltext
+			})
+		}
+	}
+
+	return NewValuesRowReader(tx, nil, informationSchemaIndexesCols, true, alias, rows)
+}
+
+func (r *informationSchemaIndexesResolver) Table() string {
+	return InformationSchema + ".indexes"
+}
+
+// tables and columns without a comment are reported as NULL
+func commentValue(comment string) ValueExp {
+	if comment == "" {
+		return NewNull(VarcharType)
+	}
+	return NewVarchar(comment)
+}
+
+func informationSchemaResolvers() []TableResolver {
+	return []TableResolver{
+		&informationSchemaTablesResolver{},
+		&informationSchemaColumnsResolver{},
+		&informationSchemaIndexesResolver{},
+	}
+}
@@ -19,6 +19,8 @@ package sql
 import (
 	"context"
 	"fmt"
+	"iter"
+	"slices"
 
 	"github.com/codenotary/immudb/embedded/multierr"
 )
@@ -31,6 +33,14 @@ type jointRowReader struct {
 	rowReaders                 []RowReader
 	rowReadersValuesByPosition [][]TypedValue
 	rowReadersValuesBySelector []map[string]TypedValue
+
+	// mergedCols holds, for each join, the positions of the columns merged
+	// with the ones from the left side by a NATURAL join or a USING clause
+	mergedCols []map[int]struct{}
+
+	closed bool
+
+	lookahead rowLookahead
 }
 
 func newJointRowReader(rowReader RowReader, joins []*JoinSpec) (*jointRowReader, error) {
@@ -52,9 +62,143 @@ func newJointRowReader(rowReader RowReader, joins []*JoinSpec) (*jointRowReader,
 		rowReaders:                 []RowReader{rowReader},
 		rowReadersValuesByPosition: make([][]TypedValue, 1+len(joins)),
 		rowReadersValuesBySelector: make([]map[string]TypedValue, 1+len(joins)),
+		mergedCols:                 make([]map[int]struct{}, len(joins)),
 	}, nil
 }
 
+// resolveJoinColumns synthesizes the condition of NATURAL joins and joins with a USING clause
+// by matching the named columns, or all the columns with the same name, from both sides.
+// A NATURAL join with no common columns behaves as a cross join. Merged columns are returned
+// only once, by the left side, but remain accessible through the selector of the right side.
+func (jointr *jointRowReader) resolveJoinColumns(ctx context.Context) error {
+	if !slices.ContainsFunc(jointr.joins, func(jspec *JoinSpec) bool { return jspec.natural || len(jspec.using) > 0 }) {
+		return nil
+	}
+
+	leftCols, err := jointr.rowReader.Columns(ctx)
+	if err != nil {
+		return err
+	}
+
+	joins := make([]*JoinSpec, len(jointr.joins))
+
+	for i, jspec := range jointr.joins {
+		rightCols, err := jointr.joinColumns(ctx, jspec)
+		if err != nil {
+			return err
+		}
+
+		if !jspec.natural && len(jspec.using) == 0 {
+			joins[i] = jspec
+			leftCols = append(leftCols, rightCols...)
+			continue
+		}
+
+		joinCols := jspec.using
+		if jspec.natural {
+			joinCols = commonColumns(leftCols, rightCols)
+		}
+
+		var cond ValueExp = NewBool(true)
+
+		merged := make(map[int]struct{}, len(joinCols))
+
+		for j, col := range joinCols {
+			l := columnPosition(leftCols, col)
+			r := columnPosition(rightCols, col)
+
+			if l < 0 || r < 0 {
+				return fmt.Errorf("%w (%s) in join condition", ErrColumnDoesNotExist, col)
+			}
+
+			if _, exists := merged[r]; exists {
+				return fmt.Errorf("%w (%s) in join condition", ErrDuplicatedColumn, col)
+			}
+			merged[r] = struct{}{}
+
+			eq := &CmpBoolExp{
+				op:    EQ,
+				left:  &ColSelector{table: leftCols[l].Table, col: col},
+				right: &ColSelector{table: rightCols[r].Table, col: col},
+			}
+
+			if j == 0 {
+				cond = eq
+			} else {
+				cond = &BinBoolExp{op: And, left: cond, right: eq}
+			}
+		}
+
+		joins[i] = &JoinSpec{
+			joinType: jspec.joinType,
+			ds:       jspec.ds,
+			cond:     cond,
+			indexOn:  jspec.indexOn,
+			fullScan: jspec.fullScan,
+		}
+		jointr.mergedCols[i] = merged
+
+		for r, col := range rightCols {
+			if _, isMerged := merged[r]; !isMerged {
+				leftCols = append(leftCols, col)
+			}
+		}
+	}
+
+	jointr.joins = joins
+
+	return nil
+}
+
+func commonColumns(leftCols, rightCols []ColDescriptor) []string {
+	var cols []string
+
+	for _, col := range rightCols {
+		if columnPosition(leftCols, col.Column) >= 0 {
+			cols = append(cols, col.Column)
+		}
+	}
+	return cols
+}
+
+func columnPosition(cols []ColDescriptor, col string) int {
+	for i, c := range cols {
+		if c.Column == col {
+			return i
+		}
+	}
+	return -1
+}
+
+// withoutMergedCols returns the values of the i-th join excluding the ones of merged columns
+func (jointr *jointRowReader) withoutMergedCols(i int, values []TypedValue) []TypedValue {
+	merged := jointr.mergedCols[i]
+	if len(merged) == 0 {
+		return values
+	}
+
+	filtered := make([]TypedValue, 0, len(values)-len(merged))
+
+	for pos, v := range values {
+		if _, isMerged := merged[pos]; !isMerged {
+			filtered = append(filtered, v)
+		}
+	}
+	return filtered
+}
+
+func (jointr *jointRowReader) joinColumns(ctx context.Context, jspec *JoinSpec) ([]ColDescriptor, error) {
+	// Note: We're using a dummy ScanSpec object that is only used during read, we're only interested
+	//       in column list though
+	rr, err := jspec.ds.Resolve(ctx, jointr.Tx(), nil, &ScanSpecs{Index: &Index{}})
+	if err != nil {
+		return nil, err
+	}
+	defer rr.Close()
+
+	return rr.Columns(ctx)
+}
+
 func (jointr *jointRowReader) onClose(callback func()) {
 	jointr.rowReader.onClose(callback)
 }
@@ -128,24 +272,19 @@ func (jointr *jointRowReader) colsByPos(ctx context.Context) ([]ColDescriptor, e
 		return nil, err
 	}
 
-	for _, jspec := range jointr.joins {
-
+	for i, jspec := range jointr.joins {
 		// TODO (byo) optimize this by getting selector list only or opening all joint readers
-		//            on jointRowReader creation,
-		// Note: We're using a dummy ScanSpec object that is only used during read, we're only interested
-		//       in column list though
-		rr, err := jspec.ds.Resolve(ctx, jointr.Tx(), nil, &ScanSpecs{Index: &Index{}})
+		//            on jointRowReader creation
+		cd, err := jointr.joinColumns(ctx, jspec)
 		if err != nil {
 			return nil, err
 		}
-		defer rr.Close()
 
-		cd, err := rr.Columns(ctx)
-		if err != nil {
-			return nil, err
+		for pos, col := range cd {
+			if _, isMerged := jointr.mergedCols[i][pos]; !isMerged {
+				colDescriptors = append(colDescriptors, col)
+			}
 		}
-
-		colDescriptors = append(colDescriptors, cd...)
 	}
 
 	return colDescriptors, nil
@@ -180,7 +319,24 @@ func (jointr *jointRowReader) Parameters() map[string]interface{} {
 	return jointr.rowReader.Parameters()
 }
 
-func (jointr *jointRowReader) Read(ctx context.Context) (row *Row, err error) {
+func (jointr *jointRowReader) All(ctx context.Context) iter.Seq2[*Row, error] {
+	return allRows(ctx, jointr)
+}
+
+func (jointr *jointRowReader) Read(ctx context.Context) (*Row, error) {
+	return jointr.lookahead.read(ctx, jointr.readRow)
+}
+
+func (jointr *jointRowReader) Peek(ctx context.Context) (*Row, error) {
+	return jointr.lookahead.peek(ctx, jointr.readRow)
+}
+
+func (jointr *jointRowReader) readRow(ctx context.Context) (row *Row, err error) {
+	// readers are discarded as soon as they are exhausted
+	if jointr.closed {
+		return nil, ErrReaderClosed
+	}
+
 	for {
 		row := &Row{
 			ValuesByPosition: make([]TypedValue, 0),
@@ -206,8 +362,13 @@ func (jointr *jointRowReader) Read(ctx context.Context) (row *Row, err error) {
 				return nil, err
 			}
 
+			valuesByPosition := r.ValuesByPosition
+			if len(jointr.rowReaders) > 1 {
+				valuesByPosition = jointr.withoutMergedCols(len(jointr.rowReaders)-2, valuesByPosition)
+			}
+
 			// override row data
-			jointr.rowReadersValuesByPosition[len(jointr.rowReaders)-1] = r.ValuesByPosition
+			jointr.rowReadersValuesByPosition[len(jointr.rowReaders)-1] = valuesByPosition
 			jointr.rowReadersValuesBySelector[len(jointr.rowReaders)-1] = r.ValuesBySelector
 
 			break
@@ -231,10 +392,16 @@ func (jointr *jointRowReader) Read(ctx context.Context) (row *Row, err error) {
 		for i := len(jointr.rowReaders) - 1; i < len(jointr.joins); i++ {
 			jspec := jointr.joins[i]
 
+			ds := jspec.ds
+			if jspec.lateral {
+				ds = ds.(*SelectStmt).correlatedWith(row)
+			}
+
 			jointq := &SelectStmt{
-				ds:      jspec.ds,
-				where:   jspec.cond.reduceSelectors(row, jointr.TableAlias()),
-				indexOn: jspec.indexOn,
+				ds:       ds,
+				where:    jspec.cond.reduceSelectors(row, jointr.TableAlias()),
+				indexOn:  jspec.indexOn,
+				fullScan: jspec.fullScan,
 			}
 
 			reader, err := jointq.Resolve(ctx, jointr.Tx(), jointr.Parameters(), nil)
@@ -280,10 +447,10 @@ func (jointr *jointRowReader) Read(ctx context.Context) (row *Row, err error) {
 			// progress with the joint readers
 			// append the reader and kept the values for following rows
 			jointr.rowReaders = append(jointr.rowReaders, reader)
-			jointr.rowReadersValuesByPosition[i+1] = r.ValuesByPosition
+			jointr.rowReadersValuesByPosition[i+1] = jointr.withoutMergedCols(i, r.ValuesByPosition)
 			jointr.rowReadersValuesBySelector[i+1] = r.ValuesBySelector
 
-			row.ValuesByPosition = append(row.ValuesByPosition, r.ValuesByPosition...)
+			row.ValuesByPosition = append(row.ValuesByPosition, jointr.rowReadersValuesByPosition[i+1]...)
 
 			for c, v := range r.ValuesBySelector {
 				row.ValuesBySelector[c] = v
@@ -297,7 +464,26 @@ func (jointr *jointRowReader) Read(ctx context.Context) (row *Row, err error) {
 	}
 }
 
+// correlatedWith returns a copy of a LATERAL subquery where the references to the columns of
+// the outer row are replaced by their values. Only qualified references are considered, as
+// unqualified ones refer to the data sources of the subquery itself.
+func (stmt *SelectStmt) correlatedWith(row *Row) *SelectStmt {
+	correlated := *stmt
+
+	if stmt.where != nil {
+		correlated.where = stmt.where.reduceSelectors(row, "")
+	}
+	if stmt.having != nil {
+		correlated.having = stmt.having.reduceSelectors(row, "")
+	}
+	return &correlated
+}
+
 func (jointr *jointRowReader) Close() error {
+	jointr.lookahead.discard()
+
+	jointr.closed = true
+
 	merr := multierr.NewMultiErr()
 
 	// Closing joint readers backwards - the first reader executes the onClose callback
@@ -17,6 +17,7 @@ limitations under the License.
 package sql
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strconv"
@@ -86,6 +87,10 @@ func (v *JSON) substitute(params map[string]interface{}) (ValueExp, error) {
 }
 
 func (v *JSON) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return v.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (v *JSON) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
 	return v, nil
 }
 
@@ -213,7 +218,11 @@ func (v *JSONSelector) String() string {
 }
 
 func (sel *JSONSelector) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
-	val, err := sel.ColSelector.reduce(tx, row, implicitTable)
+	return sel.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (sel *JSONSelector) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	val, err := sel.ColSelector.reduceCtx(ctx, tx, row, implicitTable)
 	if err != nil {
 		return nil, err
 	}
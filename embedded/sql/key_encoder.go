@@ -0,0 +1,94 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+// KeyEncoder determines the store keys under which the rows of tables and the entries of their
+// indexes are kept, following the prefix of the engine:
+//
+//	row key   = {RowPrefix(tableID)}{pkVal}+
+//	index key = {IndexPrefix(tableID, indexID)}{val}*{pkVal}+
+//
+// where each value is encoded as by EncodeValueAsKey, or canonically by the secondary indexes with
+// canonical keys, so the ordering of keys matches the ordering of values. Returned prefixes must
+// not be prefixes of one another, except for TableIndexesPrefix which must be a prefix of the
+// IndexPrefix of every index of the table, and must not start with the prefix of the catalog
+// ("CTL.") nor the one of full-text indexes ("FT."), whose entries keep their layout. The encoder
+// must remain the same for as long as the data is kept, and keys are expected to follow the
+// default layout by callers verifying rows outside the engine, such as the immudb server and
+// client.
+type KeyEncoder interface {
+	// RowPrefix returns the prefix of the keys of the rows of the table
+	RowPrefix(tableID uint32) []byte
+	// IndexPrefix returns the prefix of the keys of the entries of the index
+	IndexPrefix(tableID, indexID uint32) []byte
+	// TableIndexesPrefix returns the prefix shared by the entries of all the indexes of the table
+	TableIndexesPrefix(tableID uint32) []byte
+}
+
+// DefaultKeyEncoder returns the encoder of the keys used since the first release of the engine:
+//
+//	row key   = R.{dbID}{tableID}{pkIndexID}{pkVal}+
+//	index key = M.{tableID}{indexID}{val}*{pkVal}+
+//
+// where ids are encoded as 4-byte big endian integers.
+func DefaultKeyEncoder() KeyEncoder {
+	return defaultKeyEncoder{}
+}
+
+type defaultKeyEncoder struct{}
+
+func (defaultKeyEncoder) RowPrefix(tableID uint32) []byte {
+	return MapKey(nil, RowPrefix, EncodeID(DatabaseID), EncodeID(tableID), EncodeID(PKIndexID))
+}
+
+func (defaultKeyEncoder) IndexPrefix(tableID, indexID uint32) []byte {
+	return MapKey(nil, MappedPrefix, EncodeID(tableID), EncodeID(indexID))
+}
+
+func (defaultKeyEncoder) TableIndexesPrefix(tableID uint32) []byte {
+	return MapKey(nil, MappedPrefix, EncodeID(tableID))
+}
+
+func (t *Table) keyEncoder() KeyEncoder {
+	// system tables are not bound to a catalog and have no rows of their own
+	if t.catalog == nil {
+		return defaultKeyEncoder{}
+	}
+	return t.catalog.keyEncoder
+}
+
+// rowKey returns the key of the row with the given encoded primary key values
+func (t *Table) rowKey(pkEncVals ...[]byte) []byte {
+	return MapKey(t.catalog.enginePrefix, string(t.keyEncoder().RowPrefix(t.id)), pkEncVals...)
+}
+
+func (t *Table) rowsPrefix() []byte {
+	return t.rowKey()
+}
+
+func (t *Table) indexesPrefix() []byte {
+	return MapKey(t.catalog.enginePrefix, string(t.keyEncoder().TableIndexesPrefix(t.id)))
+}
+
+// entryKey returns the key of the index entry made of the given encoded values
+func (i *Index) entryKey(encValues ...[]byte) []byte {
+	return MapKey(i.enginePrefix(), string(i.table.keyEncoder().IndexPrefix(i.table.id, i.id)), encValues...)
+}
+
+func (i *Index) entriesPrefix() []byte {
+	return i.entryKey()
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+)
+
+type prefixedKeyEncoder struct{}
+
+func (prefixedKeyEncoder) RowPrefix(tableID uint32) []byte {
+	return append([]byte("ROW."), EncodeID(tableID)...)
+}
+
+func (prefixedKeyEncoder) IndexPrefix(tableID, indexID uint32) []byte {
+	return append(prefixedKeyEncoder{}.TableIndexesPrefix(tableID), EncodeID(indexID)...)
+}
+
+func (prefixedKeyEncoder) TableIndexesPrefix(tableID uint32) []byte {
+	return append([]byte("IDX."), EncodeID(tableID)...)
+}
+
+func lastTxKeys(t *testing.T, st *store.ImmuStore) [][]byte {
+	tx := store.NewTx(st.MaxTxEntries(), st.MaxKeyLen())
+
+	err := st.ReadTx(st.LastCommittedTxID(), false, tx)
+	require.NoError(t, err)
+
+	var keys [][]byte
+	for _, e := range tx.Entries() {
+		keys = append(keys, e.Key())
+	}
+	return keys
+}
+
+func TestDefaultKeyEncoder(t *testing.T) {
+	enc := DefaultKeyEncoder()
+
+	require.Equal(t, MapKey(nil, RowPrefix, EncodeID(1), EncodeID(3), EncodeID(0)), enc.RowPrefix(3))
+	require.Equal(t, MapKey(nil, MappedPrefix, EncodeID(3), EncodeID(2)), enc.IndexPrefix(3, 2))
+	require.Equal(t, MapKey(nil, MappedPrefix, EncodeID(3)), enc.TableIndexesPrefix(3))
+
+	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(t, err)
+	defer closeStore(t, st)
+
+	engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+	require.NoError(t, err)
+	require.Equal(t, DefaultKeyEncoder(), engine.KeyEncoder())
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		CREATE TABLE people (id INTEGER, name VARCHAR[32], PRIMARY KEY id);
+		CREATE INDEX ON people (name);
+	`, nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO people (id, name) VALUES (1, 'alice')", nil)
+	require.NoError(t, err)
+
+	catalog, err := engine.Catalog(context.Background(), nil)
+	require.NoError(t, err)
+
+	table, err := catalog.GetTableByName("people")
+	require.NoError(t, err)
+
+	pk, _, err := EncodeValueAsKey(NewInteger(1), IntegerType, table.primaryIndex.cols[0].MaxLen())
+	require.NoError(t, err)
+
+	name, _, err := EncodeValueAsKey(NewVarchar("alice"), VarcharType, 32)
+	require.NoError(t, err)
+
+	// keys keep the layout written by previous releases
+	require.Equal(t,
+		MapKey(sqlPrefix, RowPrefix, EncodeID(DatabaseID), EncodeID(table.id), EncodeID(PKIndexID), pk),
+		lastTxKeys(t, st)[0],
+	)
+
+	err = st.WaitForIndexingUpto(context.Background(), st.LastCommittedTxID())
+	require.NoError(t, err)
+
+	for _, key := range [][]byte{
+		MapKey(sqlPrefix, MappedPrefix, EncodeID(table.id), EncodeID(PKIndexID), pk, pk),
+		MapKey(sqlPrefix, MappedPrefix, EncodeID(table.id), EncodeID(table.indexes[1].id), name, pk),
+	} {
+		_, err := st.Get(context.Background(), key)
+		require.NoError(t, err)
+	}
+}
+
+func TestCustomKeyEncoder(t *testing.T) {
+	dir := t.TempDir()
+
+	st, err := store.Open(dir, store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(t, err)
+
+	opts := DefaultOptions().WithPrefix(sqlPrefix).WithKeyEncoder(prefixedKeyEncoder{})
+
+	engine, err := NewEngine(st, opts)
+	require.NoError(t, err)
+	require.Equal(t, prefixedKeyEncoder{}, engine.KeyEncoder())
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		CREATE TABLE people (id INTEGER AUTO_INCREMENT, name VARCHAR[32], age INTEGER, PRIMARY KEY id);
+		CREATE UNIQUE INDEX ON people (name);
+		CREATE INDEX ON people (age);
+		INSERT INTO people (name, age) VALUES ('alice', 30), ('bob', 25), ('carol', 41), ('dave', 25);
+	`, nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		UPDATE people SET age = 26 WHERE name = 'dave';
+		DELETE FROM people WHERE name = 'carol';
+	`, nil)
+	require.NoError(t, err)
+
+	names := func(t *testing.T, e *Engine, sql string) []string {
+		rows, err := e.queryAll(context.Background(), nil, sql, nil)
+		require.NoError(t, err)
+
+		var names []string
+		for _, row := range rows {
+			names = append(names, row.ValuesByPosition[0].RawValue().(string))
+		}
+		return names
+	}
+
+	checkRows := func(t *testing.T, e *Engine) {
+		require.Equal(t, []string{"alice", "bob", "dave"}, names(t, e, "SELECT name FROM people"))
+		require.Equal(t, []string{"bob"}, names(t, e, "SELECT name FROM people WHERE id = 2"))
+		require.Equal(t, []string{"dave", "bob"}, names(t, e, "SELECT name FROM people USE INDEX ON (name) WHERE name > 'alice' ORDER BY name DESC"))
+		require.Equal(t, []string{"bob", "dave", "alice"}, names(t, e, "SELECT name FROM people USE INDEX ON (age) ORDER BY age"))
+		require.Empty(t, names(t, e, "SELECT name FROM people WHERE age = 25 AND name = 'dave'"))
+
+		_, _, err := e.Exec(context.Background(), nil, "INSERT INTO people (name, age) VALUES ('bob', 50)", nil)
+		require.ErrorIs(t, err, store.ErrKeyAlreadyExists)
+	}
+
+	checkRows(t, engine)
+
+	catalog, err := engine.Catalog(context.Background(), nil)
+	require.NoError(t, err)
+
+	table, err := catalog.GetTableByName("people")
+	require.NoError(t, err)
+
+	rowPrefix := append(sqlPrefix[:len(sqlPrefix):len(sqlPrefix)], prefixedKeyEncoder{}.RowPrefix(table.id)...)
+	indexesPrefix := append(sqlPrefix[:len(sqlPrefix):len(sqlPrefix)], prefixedKeyEncoder{}.TableIndexesPrefix(table.id)...)
+
+	rowKeys := 0
+
+	for _, key := range lastTxKeys(t, st) {
+		if bytes.HasPrefix(key, rowPrefix) {
+			rowKeys++
+			continue
+		}
+		require.True(t, bytes.HasPrefix(key, indexesPrefix))
+	}
+	require.Equal(t, 2, rowKeys)
+
+	err = st.WaitForIndexingUpto(context.Background(), st.LastCommittedTxID())
+	require.NoError(t, err)
+
+	for _, prefix := range [][]byte{
+		append(sqlPrefix, prefixedKeyEncoder{}.IndexPrefix(table.id, PKIndexID)...),
+		append(sqlPrefix, prefixedKeyEncoder{}.IndexPrefix(table.id, table.indexes[1].id)...),
+		append(sqlPrefix, prefixedKeyEncoder{}.IndexPrefix(table.id, table.indexes[2].id)...),
+	} {
+		key, _, err := st.GetWithPrefix(context.Background(), prefix, nil)
+		require.NoError(t, err)
+		require.True(t, bytes.HasPrefix(key, prefix))
+	}
+
+	for _, prefix := range [][]byte{
+		MapKey(sqlPrefix, RowPrefix),
+		MapKey(sqlPrefix, MappedPrefix),
+	} {
+		_, _, err := st.GetWithPrefix(context.Background(), prefix, nil)
+		require.ErrorIs(t, err, store.ErrKeyNotFound)
+	}
+
+	require.NoError(t, st.Close())
+
+	t.Run("reopening the engine", func(t *testing.T) {
+		st, err := store.Open(dir, store.DefaultOptions().WithMultiIndexing(true))
+		require.NoError(t, err)
+		defer closeStore(t, st)
+
+		engine, err := NewEngine(st, opts)
+		require.NoError(t, err)
+
+		checkRows(t, engine)
+
+		// automatically assigned primary keys continue after the last row
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO people (name, age) VALUES ('erin', 33)", nil)
+		require.NoError(t, err)
+		require.Equal(t, []string{"erin"}, names(t, engine, "SELECT name FROM people WHERE id = 5"))
+
+		_, _, err = engine.Exec(context.Background(), nil, "DROP INDEX ON people (age)", nil)
+		require.NoError(t, err)
+		require.Len(t, names(t, engine, "SELECT name FROM people"), 4)
+	})
+}
@@ -16,13 +16,24 @@ limitations under the License.
 
 package sql
 
-import "context"
+import (
+	"context"
+	"iter"
+)
 
 type limitRowReader struct {
 	rowReader RowReader
 
 	limit int
 	read  int
+
+	// when set, rows beyond the limit are returned as long as
+	// they tie with the last row within the limit on these expressions
+	tieExps []*OrdExp
+	lastKey Tuple
+	tiesEnd bool
+
+	closed bool
 }
 
 func newLimitRowReader(rowReader RowReader, limit int) *limitRowReader {
@@ -32,6 +43,15 @@ func newLimitRowReader(rowReader RowReader, limit int) *limitRowReader {
 	}
 }
 
+// newLimitWithTiesRowReader requires rows to be returned by rowReader sorted by tieExps
+func newLimitWithTiesRowReader(rowReader RowReader, limit int, tieExps []*OrdExp) *limitRowReader {
+	return &limitRowReader{
+		rowReader: rowReader,
+		limit:     limit,
+		tieExps:   tieExps,
+	}
+}
+
 func (lr *limitRowReader) onClose(callback func()) {
 	lr.rowReader.onClose(callback)
 }
@@ -68,9 +88,17 @@ func (lr *limitRowReader) InferParameters(ctx context.Context, params map[string
 	return lr.rowReader.InferParameters(ctx, params)
 }
 
+func (lr *limitRowReader) All(ctx context.Context) iter.Seq2[*Row, error] {
+	return allRows(ctx, lr)
+}
+
 func (lr *limitRowReader) Read(ctx context.Context) (*Row, error) {
+	if lr.closed {
+		return nil, ErrReaderClosed
+	}
+
 	if lr.read >= lr.limit {
-		return nil, ErrNoMoreRows
+		return lr.readTie(ctx)
 	}
 
 	row, err := lr.rowReader.Read(ctx)
@@ -80,9 +108,75 @@ func (lr *limitRowReader) Read(ctx context.Context) (*Row, error) {
 
 	lr.read++
 
+	if lr.read == lr.limit && len(lr.tieExps) > 0 {
+		lr.lastKey, err = lr.tieKey(row)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return row, nil
+}
+
+func (lr *limitRowReader) readTie(ctx context.Context) (*Row, error) {
+	_, err := lr.peekTie(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return lr.rowReader.Read(ctx)
+}
+
+// Peek doesn't need to buffer rows, as the row about to be returned
+// is the next one of the underlying reader
+func (lr *limitRowReader) Peek(ctx context.Context) (*Row, error) {
+	if lr.closed {
+		return nil, ErrReaderClosed
+	}
+
+	if lr.read >= lr.limit {
+		return lr.peekTie(ctx)
+	}
+	return lr.rowReader.Peek(ctx)
+}
+
+// peekTie returns the next row of the underlying reader when it ties with the last row within the limit,
+// so the first row not tying is left unread
+func (lr *limitRowReader) peekTie(ctx context.Context) (*Row, error) {
+	if len(lr.tieExps) == 0 || lr.tiesEnd {
+		return nil, ErrNoMoreRows
+	}
+
+	row, err := lr.rowReader.Peek(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := lr.tieKey(row)
+	if err != nil {
+		return nil, err
+	}
+
+	cmp, _, err := key.Compare(lr.lastKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if cmp != 0 {
+		lr.tiesEnd = true
+		return nil, ErrNoMoreRows
+	}
+
 	return row, nil
 }
 
+func (lr *limitRowReader) tieKey(row *Row) (Tuple, error) {
+	key := make(Tuple, len(lr.tieExps))
+
+	err := evalOrdExps(lr.Tx(), row, lr.TableAlias(), lr.tieExps, key)
+	return key, err
+}
+
 func (lr *limitRowReader) Close() error {
+	lr.closed = true
 	return lr.rowReader.Close()
 }
@@ -0,0 +1,168 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/codenotary/immudb/embedded/store"
+)
+
+const MigrationsTableName = "_migrations"
+
+// Migration is a schema change identified by its version
+type Migration struct {
+	Version     int64
+	Description string
+	// Up holds the statements applying the migration, they're executed in a single transaction
+	Up string
+}
+
+func validateMigrations(migrations []Migration) ([]Migration, error) {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Version < sorted[j].Version
+	})
+
+	for i, m := range sorted {
+		if m.Version <= 0 {
+			return nil, fmt.Errorf("%w: invalid migration version %d", ErrIllegalArguments, m.Version)
+		}
+
+		if i > 0 && sorted[i-1].Version == m.Version {
+			return nil, fmt.Errorf("%w: duplicated migration version %d", ErrIllegalArguments, m.Version)
+		}
+	}
+
+	return sorted, nil
+}
+
+// Migrate applies, in ascending version order, the migrations not yet recorded in the migrations table.
+// Each migration is applied and recorded within the same transaction, so concurrent calls racing to apply
+// the same migration can not both succeed: the transaction committing last fails due to a conflict and the
+// migration is skipped as long as it has been recorded by the concurrent one.
+// The versions of the migrations applied by this call are returned.
+func (e *Engine) Migrate(ctx context.Context, migrations []Migration) (applied []int64, err error) {
+	sorted, err := validateMigrations(migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	_, _, err = e.ExecWithRetry(
+		ctx,
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+			version INTEGER,
+			description VARCHAR,
+			applied_at TIMESTAMP,
+			PRIMARY KEY version
+		)`, MigrationsTableName),
+		nil,
+		DefaultRetryOpts(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range sorted {
+		ok, err := e.applyMigration(ctx, m)
+		if isRetryableErr(err) {
+			// the migration may have been concurrently applied
+			isApplied, aerr := e.isMigrationApplied(ctx, nil, m.Version)
+			if aerr == nil && isApplied {
+				continue
+			}
+		}
+		if err != nil {
+			return applied, fmt.Errorf("can not apply migration %d: %w", m.Version, err)
+		}
+
+		if ok {
+			applied = append(applied, m.Version)
+		}
+	}
+
+	return applied, nil
+}
+
+// applyMigration returns false when the migration was already applied
+func (e *Engine) applyMigration(ctx context.Context, m Migration) (bool, error) {
+	stmts, err := e.parseSQL(m.Up)
+	if err != nil {
+		return false, fmt.Errorf("%w: %v", ErrParsingError, err)
+	}
+
+	tx, err := e.NewTx(ctx, DefaultTxOptions().WithExplicitClose(true))
+	if err != nil {
+		return false, err
+	}
+
+	isApplied, err := e.isMigrationApplied(ctx, tx, m.Version)
+	if err != nil {
+		tx.Cancel()
+		return false, err
+	}
+
+	if isApplied {
+		return false, tx.Cancel()
+	}
+
+	// failing statements cancel the transaction
+	_, _, err = e.ExecPreparedStmts(ctx, tx, stmts, nil)
+	if err != nil {
+		return false, err
+	}
+
+	_, _, err = e.Exec(
+		ctx,
+		tx,
+		fmt.Sprintf("INSERT INTO %s (version, description, applied_at) VALUES (@version, @description, NOW())", MigrationsTableName),
+		NewParams().SetInt("version", m.Version).SetString("description", m.Description),
+	)
+	if errors.Is(err, store.ErrKeyAlreadyExists) {
+		// recorded by a concurrent transaction
+		return false, fmt.Errorf("%w: %v", store.ErrTxReadConflict, err)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	err = tx.Commit(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (e *Engine) isMigrationApplied(ctx context.Context, tx *SQLTx, version int64) (bool, error) {
+	rows, err := e.queryAll(
+		ctx,
+		tx,
+		fmt.Sprintf("SELECT version FROM %s WHERE version = @version", MigrationsTableName),
+		NewParams().SetInt("version", version),
+	)
+	if err != nil {
+		return false, err
+	}
+
+	return len(rows) > 0, nil
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMigrate(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	migrations := []Migration{
+		{
+			Version:     2,
+			Description: "add email",
+			Up:          "ALTER TABLE users ADD COLUMN email VARCHAR;",
+		},
+		{
+			Version:     1,
+			Description: "create users",
+			Up: `
+				CREATE TABLE users (id INTEGER AUTO_INCREMENT, name VARCHAR, PRIMARY KEY id);
+				INSERT INTO users (name) VALUES ('admin');
+			`,
+		},
+	}
+
+	applied := func(t *testing.T) []int64 {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT version FROM _migrations ORDER BY version", nil)
+		require.NoError(t, err)
+
+		versions := make([]int64, len(rows))
+		for i, row := range rows {
+			versions[i] = row.ValuesByPosition[0].RawValue().(int64)
+		}
+		return versions
+	}
+
+	t.Run("invalid migrations should fail", func(t *testing.T) {
+		_, err := engine.Migrate(context.Background(), []Migration{{Version: 0, Up: "CREATE TABLE t (id INTEGER, PRIMARY KEY id)"}})
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = engine.Migrate(context.Background(), []Migration{{Version: 1, Up: "SELECT 1"}, {Version: 1, Up: "SELECT 2"}})
+		require.ErrorIs(t, err, ErrIllegalArguments)
+	})
+
+	t.Run("new migrations should be applied in version order", func(t *testing.T) {
+		versions, err := engine.Migrate(context.Background(), migrations)
+		require.NoError(t, err)
+		require.Equal(t, []int64{1, 2}, versions)
+		require.Equal(t, []int64{1, 2}, applied(t))
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT id, name, email FROM users", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, "admin", rows[0].ValuesByPosition[1].RawValue())
+		require.True(t, rows[0].ValuesByPosition[2].IsNull())
+	})
+
+	t.Run("applied migrations should be skipped", func(t *testing.T) {
+		versions, err := engine.Migrate(context.Background(), migrations)
+		require.NoError(t, err)
+		require.Empty(t, versions)
+
+		versions, err = engine.Migrate(context.Background(), append(migrations, Migration{
+			Version:     3,
+			Description: "add guest",
+			Up:          "INSERT INTO users (name, email) VALUES ('guest', 'guest@localhost')",
+		}))
+		require.NoError(t, err)
+		require.Equal(t, []int64{3}, versions)
+		require.Equal(t, []int64{1, 2, 3}, applied(t))
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT COUNT(*) FROM users", nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), rows[0].ValuesByPosition[0].RawValue())
+	})
+
+	t.Run("failing migrations should not be recorded", func(t *testing.T) {
+		versions, err := engine.Migrate(context.Background(), []Migration{
+			{
+				Version: 4,
+				Up:      "INSERT INTO users (name) VALUES ('partial'); INSERT INTO users (id, name) VALUES (1, 'duplicated');",
+			},
+			{
+				Version: 5,
+				Up:      "INSERT INTO users (name) VALUES ('never')",
+			},
+		})
+		require.ErrorIs(t, err, store.ErrKeyAlreadyExists)
+		require.Empty(t, versions)
+		require.Equal(t, []int64{1, 2, 3}, applied(t))
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT COUNT(*) FROM users", nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), rows[0].ValuesByPosition[0].RawValue())
+
+		_, err = engine.Migrate(context.Background(), []Migration{{Version: 4, Up: "CREATE TABLE"}})
+		require.ErrorIs(t, err, ErrParsingError)
+	})
+}
+
+func TestMigrateConcurrently(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	migrations := []Migration{
+		{
+			Version: 1,
+			Up: `
+				CREATE TABLE counters (id INTEGER, n INTEGER, PRIMARY KEY id);
+				INSERT INTO counters (id, n) VALUES (1, 0);
+			`,
+		},
+		{
+			Version: 2,
+			Up:      "UPDATE counters SET n = n + 1 WHERE id = 1",
+		},
+		{
+			Version: 3,
+			Up:      "UPDATE counters SET n = n + 10 WHERE id = 1",
+		},
+	}
+
+	const workers = 8
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+
+	appliedBy := make([][]int64, workers)
+	errs := make([]error, workers)
+
+	for i := 0; i < workers; i++ {
+		go func(i int) {
+			defer wg.Done()
+			appliedBy[i], errs[i] = engine.Migrate(context.Background(), migrations)
+		}(i)
+	}
+
+	wg.Wait()
+
+	appliedCount := make(map[int64]int)
+
+	for i := 0; i < workers; i++ {
+		if errs[i] != nil {
+			// losing a race is only possible when the migration was applied by another worker
+			require.ErrorIs(t, errs[i], store.ErrTxReadConflict)
+			continue
+		}
+
+		for _, v := range appliedBy[i] {
+			appliedCount[v]++
+		}
+	}
+
+	// every migration is applied at most once, retrying completes the pending ones
+	for _, n := range appliedCount {
+		require.Equal(t, 1, n)
+	}
+
+	_, err := engine.Migrate(context.Background(), migrations)
+	require.NoError(t, err)
+
+	rows, err := engine.queryAll(context.Background(), nil, "SELECT COUNT(*) FROM _migrations", nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), rows[0].ValuesByPosition[0].RawValue())
+
+	rows, err = engine.queryAll(context.Background(), nil, "SELECT n FROM counters WHERE id = 1", nil)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+	require.Equal(t, int64(11), rows[0].ValuesByPosition[0].RawValue())
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+// minMaxOverIndex rewrites queries whose only target is the MIN or MAX of an indexed column,
+// e.g. SELECT MAX(id) FROM t WHERE ..., into the aggregation of the first row in the order of
+// the index, i.e. SELECT MAX(id) FROM (SELECT id FROM t WHERE ... AND id IS NOT NULL ORDER BY
+// id DESC LIMIT 1), so the index is read from one of its ends instead of scanning the table.
+// It returns nil when the query can not be rewritten.
+func (stmt *SelectStmt) minMaxOverIndex(tx *SQLTx) *SelectStmt {
+	if len(stmt.targets) != 1 || len(stmt.joins) > 0 || len(stmt.groupBy) > 0 ||
+		len(stmt.indexOn) > 0 || stmt.fullScan || stmt.distinct {
+		return nil
+	}
+
+	sel, isAgg := stmt.targets[0].Exp.(*AggColSelector)
+	if !isAgg || (sel.aggFn != MIN && sel.aggFn != MAX) {
+		return nil
+	}
+
+	ref, isTableRef := stmt.ds.(*tableRef)
+	if !isTableRef || ref.history {
+		return nil
+	}
+
+	table, err := ref.referencedTable(tx)
+	if err != nil {
+		return nil
+	}
+
+	if sel.table != "" && sel.table != ref.Alias() {
+		return nil
+	}
+
+	col, err := table.GetColumnByName(sel.col)
+	if err != nil || !leadsAnIndex(table, col) {
+		return nil
+	}
+
+	colSel := &ColSelector{table: sel.table, col: sel.col}
+
+	notNull := &CmpBoolExp{op: NE, left: colSel, right: &NullValue{t: AnyType}}
+
+	where := ValueExp(notNull)
+	if stmt.where != nil {
+		where = &BinBoolExp{op: And, left: stmt.where, right: notNull}
+	}
+
+	rewritten := *stmt
+	rewritten.where = nil
+	rewritten.ds = &SelectStmt{
+		targets:  []TargetEntry{{Exp: colSel}},
+		ds:       ref,
+		where:    where,
+		orderBy:  []*OrdExp{{exp: colSel, descOrder: sel.aggFn == MAX}},
+		limit:    &Integer{val: 1},
+		forShare: stmt.forShare,
+		as:       ref.Alias(),
+	}
+	return &rewritten
+}
+
+func leadsAnIndex(table *Table, col *Column) bool {
+	for _, index := range table.GetIndexes() {
+		if index.cols[0].id == col.id {
+			return true
+		}
+	}
+	return false
+}
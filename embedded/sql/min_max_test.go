@@ -0,0 +1,119 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMinMaxOverIndex(t *testing.T) {
+	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(t, err)
+	defer closeStore(t, st)
+
+	engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		CREATE TABLE events (id INTEGER AUTO_INCREMENT, created INTEGER, kind VARCHAR[16], score INTEGER, PRIMARY KEY id);
+		CREATE INDEX ON events (created, kind);
+	`, nil)
+	require.NoError(t, err)
+
+	values := make([]string, 100)
+	for i := range values {
+		created := fmt.Sprint((i * 37) % 100)
+		if i%10 == 0 {
+			created = "NULL"
+		}
+		values[i] = fmt.Sprintf("(%s, 'k%d', %d)", created, i%3, i)
+	}
+
+	_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO events (created, kind, score) VALUES "+strings.Join(values, ", "), nil)
+	require.NoError(t, err)
+
+	query := func(t *testing.T, maxRowsScanned int64, sql string) (interface{}, error) {
+		ctx := ContextWithResourceLimits(context.Background(), ResourceLimits{MaxRowsScanned: maxRowsScanned})
+
+		rows, err := engine.queryAll(ctx, nil, sql, nil)
+		if err != nil {
+			return nil, err
+		}
+		require.Len(t, rows, 1)
+
+		return rows[0].ValuesByPosition[0].RawValue(), nil
+	}
+
+	t.Run("indexed columns are read from one end of the index", func(t *testing.T) {
+		for _, d := range []struct {
+			sql      string
+			expected interface{}
+		}{
+			{"SELECT MAX(id) FROM events", int64(100)},
+			{"SELECT MIN(id) FROM events", int64(1)},
+			{"SELECT MAX(e.created) AS latest FROM events e", int64(99)},
+			{"SELECT MIN(created) FROM events WHERE created > 50", int64(51)},
+			{"SELECT MAX(id) FROM events WHERE id <= 9", int64(9)},
+		} {
+			v, err := query(t, 1, d.sql)
+			require.NoError(t, err, d.sql)
+			require.Equal(t, d.expected, v, d.sql)
+		}
+
+		// rows before the first one with a value are not aggregated but still read
+		v, err := query(t, 11, "SELECT MIN(created) FROM events")
+		require.NoError(t, err)
+		require.Equal(t, int64(1), v)
+
+		// aggregations of no rows are the same as when rows are scanned
+		v, err = query(t, 1, "SELECT MAX(id) FROM events WHERE id > 100")
+		require.NoError(t, err)
+
+		scanned, err := query(t, 0, "SELECT MAX(id) FROM (SELECT id FROM events WHERE id > 100)")
+		require.NoError(t, err)
+		require.Equal(t, scanned, v)
+	})
+
+	t.Run("other columns are scanned", func(t *testing.T) {
+		_, err := query(t, 1, "SELECT MAX(score) FROM events")
+		require.ErrorIs(t, err, ErrMaxRowsScannedExceeded)
+
+		// only the leading column of an index can be read from its ends
+		_, err = query(t, 1, "SELECT MAX(kind) FROM events")
+		require.ErrorIs(t, err, ErrMaxRowsScannedExceeded)
+
+		v, err := query(t, 0, "SELECT MAX(score) FROM events")
+		require.NoError(t, err)
+		require.Equal(t, int64(99), v)
+	})
+
+	t.Run("queries with other clauses are scanned", func(t *testing.T) {
+		_, err := query(t, 1, "SELECT MAX(id), COUNT(*) FROM events")
+		require.ErrorIs(t, err, ErrMaxRowsScannedExceeded)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT kind, MAX(created) FROM events GROUP BY kind ORDER BY kind", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		require.Equal(t, int64(99), rows[0].ValuesByPosition[1].RawValue())
+	})
+}
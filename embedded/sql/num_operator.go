@@ -19,16 +19,29 @@ package sql
 import (
 	"fmt"
 	"math"
+	"math/bits"
 )
 
-func applyNumOperator(op NumOperator, vl, vr TypedValue) (TypedValue, error) {
+// IntegerOverflow determines the outcome of integer arithmetic whose result doesn't fit in an INTEGER
+type IntegerOverflow int
+
+const (
+	// IntegerOverflowError makes the expression fail with ErrIntegerOverflow
+	IntegerOverflowError IntegerOverflow = iota
+	// IntegerOverflowSaturate replaces the result with the closest INTEGER, either the maximum or the minimum one
+	IntegerOverflowSaturate
+	// IntegerOverflowWrap keeps the lowest 64 bits of the result in two's complement
+	IntegerOverflowWrap
+)
+
+func applyNumOperator(op NumOperator, vl, vr TypedValue, overflow IntegerOverflow) (TypedValue, error) {
 	if vl.Type() == Float64Type || vr.Type() == Float64Type {
 		return applyNumOperatorFloat64(op, vl, vr)
 	}
-	return applyNumOperatorInteger(op, vl, vr)
+	return applyNumOperatorInteger(op, vl, vr, overflow)
 }
 
-func applyNumOperatorInteger(op NumOperator, vl, vr TypedValue) (TypedValue, error) {
+func applyNumOperatorInteger(op NumOperator, vl, vr TypedValue, overflow IntegerOverflow) (TypedValue, error) {
 	convl, err := mayApplyImplicitConversion(vl.RawValue(), IntegerType)
 	if err != nil {
 		return nil, fmt.Errorf("%w (expecting numeric value)", err)
@@ -49,38 +62,62 @@ func applyNumOperatorInteger(op NumOperator, vl, vr TypedValue) (TypedValue, err
 		return nil, fmt.Errorf("%w (expecting numeric value)", ErrInvalidValue)
 	}
 
+	if (op == DIVOP || op == MODOP) && nr == 0 {
+		return nil, ErrDivisionByZero
+	}
+
+	var (
+		res        int64
+		overflowed bool
+		negative   bool // whether the exact result is negative, when it overflows
+	)
+
 	switch op {
 	case ADDOP:
-		{
-			return &Integer{val: nl + nr}, nil
-		}
+		res = nl + nr
+		overflowed = (nl < 0) == (nr < 0) && (res < 0) != (nl < 0)
+		negative = nl < 0
 	case SUBSOP:
-		{
-			return &Integer{val: nl - nr}, nil
-		}
-	case DIVOP:
-		{
-			if nr == 0 {
-				return nil, ErrDivisionByZero
-			}
+		res = nl - nr
+		overflowed = (nl < 0) != (nr < 0) && (res < 0) != (nl < 0)
+		negative = nl < 0
+	case MULTOP:
+		res = nl * nr
+		negative = (nl < 0) != (nr < 0)
 
-			return &Integer{val: nl / nr}, nil
-		}
+		hi, lo := bits.Mul64(absInt64(nl), absInt64(nr))
+		overflowed = hi != 0 || (lo > math.MaxInt64 && !(negative && lo == 1<<63))
+	case DIVOP:
+		res = nl / nr
+		overflowed = nl == math.MinInt64 && nr == -1
 	case MODOP:
-		{
-			if nr == 0 {
-				return nil, ErrDivisionByZero
-			}
+		res = nl % nr
+	default:
+		return nil, ErrUnexpected
+	}
 
-			return &Integer{val: nl % nr}, nil
-		}
-	case MULTOP:
-		{
-			return &Integer{val: nl * nr}, nil
+	if !overflowed {
+		return &Integer{val: res}, nil
+	}
+
+	switch overflow {
+	case IntegerOverflowSaturate:
+		if negative {
+			return &Integer{val: math.MinInt64}, nil
 		}
+		return &Integer{val: math.MaxInt64}, nil
+	case IntegerOverflowWrap:
+		return &Integer{val: res}, nil
 	}
+	return nil, fmt.Errorf("%w: %d %s %d", ErrIntegerOverflow, nl, NumOperatorString(op), nr)
+}
 
-	return nil, ErrUnexpected
+// absInt64 returns the absolute value of n, which fits in an uint64 even for math.MinInt64
+func absInt64(n int64) uint64 {
+	if n < 0 {
+		return uint64(-n)
+	}
+	return uint64(n)
 }
 
 func applyNumOperatorFloat64(op NumOperator, vl, vr TypedValue) (TypedValue, error) {
@@ -137,3 +174,86 @@ func applyNumOperatorFloat64(op NumOperator, vl, vr TypedValue) (TypedValue, err
 
 	return nil, ErrUnexpected
 }
+
+// Numeric values are compared by their exact value, whatever their type: an INTEGER is neither
+// rounded to the closest FLOAT, nor a FLOAT truncated to an INTEGER when compared to each other,
+// so 9007199254740993 > 9007199254740992.0 and 1 < 1.5 both hold, in WHERE clauses as well as
+// when rows are sorted. A VARCHAR compared with a FLOAT is parsed as a FLOAT, e.g. 'NaN' or
+// 'Infinity', failing if it doesn't represent a number, while it's not comparable with an INTEGER.
+// Numbers are not comparable with values of any other type.
+//
+// NaN is unordered in comparison expressions, as mandated by IEEE 754: any comparison involving
+// a NaN is false, except for <> which is true, thus NaN is not even equal to itself, nor IN a list
+// of values holding it. Instead, whenever values are sorted or grouped, as by ORDER BY, GROUP BY,
+// DISTINCT, MIN or MAX, NaN is equal to itself and greater than any other number, as in
+// PostgreSQL, so that floats are totally ordered. Infinities are ordered as any other number and
+// follow the IEEE 754 arithmetic, e.g. the SUM of values holding Infinity is Infinity, unless they
+// hold -Infinity as well, and the AVG of values holding NaN is NaN.
+
+func compareFloats(f1, f2 float64) int {
+	switch {
+	case math.IsNaN(f1) && math.IsNaN(f2):
+		return 0
+	case math.IsNaN(f1):
+		return 1
+	case math.IsNaN(f2):
+		return -1
+	case f1 < f2:
+		return -1
+	case f1 > f2:
+		return 1
+	}
+	return 0
+}
+
+func compareIntegerToFloat(i int64, f float64) int {
+	// any int64 is within [-2^63, 2^63)
+	switch {
+	case math.IsNaN(f) || f >= math.MaxInt64:
+		return -1
+	case f < math.MinInt64:
+		return 1
+	}
+
+	whole, frac := math.Modf(f)
+
+	n := int64(whole)
+	switch {
+	case i < n:
+		return -1
+	case i > n:
+		return 1
+	case frac > 0:
+		return -1
+	case frac < 0:
+		return 1
+	}
+	return 0
+}
+
+// unordered reports whether a comparison of the values involves a NaN
+func unordered(v1, v2 TypedValue) bool {
+	if v1.IsNull() || v2.IsNull() {
+		return false
+	}
+	return isNaN(v1, v2.Type()) || isNaN(v2, v1.Type())
+}
+
+// isNaN reports whether the value is a NaN when compared with a value of the given type
+func isNaN(v TypedValue, cmpType SQLValueType) bool {
+	switch v.Type() {
+	case Float64Type:
+		return math.IsNaN(v.RawValue().(float64))
+	case VarcharType:
+		if cmpType != Float64Type {
+			return false
+		}
+
+		f, err := mayApplyImplicitConversion(v.RawValue(), Float64Type)
+		if err != nil {
+			return false
+		}
+		return math.IsNaN(f.(float64))
+	}
+	return false
+}
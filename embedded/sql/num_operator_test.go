@@ -17,10 +17,12 @@ limitations under the License.
 package sql
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"testing"
 
+	"github.com/codenotary/immudb/embedded/store"
 	"github.com/stretchr/testify/require"
 )
 
@@ -59,7 +61,7 @@ func TestNumOperator(t *testing.T) {
 			{MULTOP, &Float64{val: 10}, &Float64{val: 3}, float64(30)},
 		} {
 			t.Run(fmt.Sprintf("%+v", d), func(t *testing.T) {
-				result, err := applyNumOperator(d.op, d.lv, d.rv)
+				result, err := applyNumOperator(d.op, d.lv, d.rv, IntegerOverflowError)
 				require.NoError(t, err)
 				require.Equal(t, d.ev, result.RawValue())
 			})
@@ -77,7 +79,7 @@ func TestNumOperator(t *testing.T) {
 			{&Float64{val: 100}, &Float64{val: 0}},
 		} {
 			t.Run(fmt.Sprintf("%+v", d), func(t *testing.T) {
-				result, err := applyNumOperator(DIVOP, d.lv, d.rv)
+				result, err := applyNumOperator(DIVOP, d.lv, d.rv, IntegerOverflowError)
 				require.ErrorIs(t, err, ErrDivisionByZero)
 				require.Nil(t, result)
 			})
@@ -95,7 +97,7 @@ func TestNumOperator(t *testing.T) {
 			{&Bool{}, &Float64{val: 100}},
 		} {
 			t.Run(fmt.Sprintf("%+v", d), func(t *testing.T) {
-				result, err := applyNumOperator(ADDOP, d.lv, d.rv)
+				result, err := applyNumOperator(ADDOP, d.lv, d.rv, IntegerOverflowError)
 				require.ErrorIs(t, err, ErrInvalidValue)
 				require.Nil(t, result)
 			})
@@ -111,11 +113,346 @@ func TestNumOperator(t *testing.T) {
 			{&Float64{val: 100}, &Float64{val: 1}},
 		} {
 			t.Run(fmt.Sprintf("%+v", d), func(t *testing.T) {
-				result, err := applyNumOperator(NumOperator(-1), d.lv, d.rv)
+				result, err := applyNumOperator(NumOperator(-1), d.lv, d.rv, IntegerOverflowError)
 				require.ErrorIs(t, err, ErrUnexpected)
 				require.Nil(t, result)
 			})
 		}
 	})
 
+	t.Run("Integer overflow", func(t *testing.T) {
+		for _, d := range []struct {
+			op        NumOperator
+			lv, rv    int64
+			saturated int64
+			wrapped   int64
+		}{
+			{ADDOP, math.MaxInt64, 1, math.MaxInt64, math.MinInt64},
+			{ADDOP, math.MinInt64, -1, math.MinInt64, math.MaxInt64},
+			{SUBSOP, math.MinInt64, 1, math.MinInt64, math.MaxInt64},
+			{SUBSOP, 0, math.MinInt64, math.MaxInt64, math.MinInt64},
+			{MULTOP, math.MaxInt64, 2, math.MaxInt64, -2},
+			{MULTOP, math.MinInt64, -1, math.MaxInt64, math.MinInt64},
+			{MULTOP, 1 << 32, -(1 << 32), math.MinInt64, 0},
+			{DIVOP, math.MinInt64, -1, math.MaxInt64, math.MinInt64},
+		} {
+			t.Run(fmt.Sprintf("%+v", d), func(t *testing.T) {
+				_, err := applyNumOperator(d.op, &Integer{val: d.lv}, &Integer{val: d.rv}, IntegerOverflowError)
+				require.ErrorIs(t, err, ErrIntegerOverflow)
+
+				result, err := applyNumOperator(d.op, &Integer{val: d.lv}, &Integer{val: d.rv}, IntegerOverflowSaturate)
+				require.NoError(t, err)
+				require.Equal(t, d.saturated, result.RawValue())
+
+				result, err = applyNumOperator(d.op, &Integer{val: d.lv}, &Integer{val: d.rv}, IntegerOverflowWrap)
+				require.NoError(t, err)
+				require.Equal(t, d.wrapped, result.RawValue())
+			})
+		}
+
+		// results at the limits do not overflow
+		for _, d := range []struct {
+			op     NumOperator
+			lv, rv int64
+			ev     int64
+		}{
+			{ADDOP, math.MaxInt64 - 1, 1, math.MaxInt64},
+			{SUBSOP, -1, math.MaxInt64, math.MinInt64},
+			{MULTOP, math.MinInt64 / 2, 2, math.MinInt64},
+			{MULTOP, -(1 << 31), 1 << 32, math.MinInt64},
+			{MULTOP, math.MinInt64, 1, math.MinInt64},
+			{MULTOP, math.MinInt64, 0, 0},
+			{MODOP, math.MinInt64, -1, 0},
+		} {
+			t.Run(fmt.Sprintf("%+v", d), func(t *testing.T) {
+				result, err := applyNumOperator(d.op, &Integer{val: d.lv}, &Integer{val: d.rv}, IntegerOverflowError)
+				require.NoError(t, err)
+				require.Equal(t, d.ev, result.RawValue())
+			})
+		}
+	})
+
+}
+
+func TestCompareMixedNumericTypes(t *testing.T) {
+	nan := math.NaN()
+
+	for _, d := range []struct {
+		lv  TypedValue
+		rv  TypedValue
+		res int
+		err error
+	}{
+		{&Integer{val: 1}, &Float64{val: 1}, 0, nil},
+		{&Integer{val: 1}, &Float64{val: 1.5}, -1, nil},
+		{&Integer{val: 2}, &Float64{val: 1.5}, 1, nil},
+		{&Integer{val: -1}, &Float64{val: -1.5}, 1, nil},
+		{&Integer{val: -2}, &Float64{val: -1.5}, -1, nil},
+		{&Integer{val: 0}, &Float64{val: math.Copysign(0, -1)}, 0, nil},
+		{&Integer{val: 1 << 53}, &Float64{val: 1 << 53}, 0, nil},
+		{&Integer{val: 1<<53 + 1}, &Float64{val: 1 << 53}, 1, nil},
+		{&Integer{val: math.MaxInt64}, &Float64{val: math.MaxInt64}, -1, nil},
+		{&Integer{val: math.MinInt64}, &Float64{val: math.MinInt64}, 0, nil},
+		{&Integer{val: math.MinInt64}, &Float64{val: -math.MaxFloat64}, 1, nil},
+		{&Integer{val: math.MaxInt64}, &Float64{val: math.Inf(1)}, -1, nil},
+		{&Integer{val: math.MinInt64}, &Float64{val: math.Inf(-1)}, 1, nil},
+		{&Integer{val: math.MaxInt64}, &Float64{val: nan}, -1, nil},
+
+		{&Float64{val: 1.5}, &Integer{val: 1}, 1, nil},
+		{&Float64{val: 1 << 53}, &Integer{val: 1<<53 + 1}, -1, nil},
+		{&Float64{val: nan}, &Integer{val: math.MaxInt64}, 1, nil},
+
+		{&Float64{val: nan}, &Float64{val: nan}, 0, nil},
+		{&Float64{val: nan}, &Float64{val: math.Inf(1)}, 1, nil},
+		{&Float64{val: math.Inf(1)}, &Float64{val: nan}, -1, nil},
+		{&Float64{val: math.Inf(-1)}, &Float64{val: -math.MaxFloat64}, -1, nil},
+
+		{&Float64{val: 1.5}, &Varchar{val: "1.5"}, 0, nil},
+		{&Float64{val: 1.5}, &Varchar{val: "2"}, -1, nil},
+		{&Varchar{val: "2"}, &Float64{val: 1.5}, 1, nil},
+		{&Float64{val: 1.5}, &Varchar{val: "one"}, 0, ErrInvalidValue},
+		{&Integer{val: 1}, &Varchar{val: "1"}, 0, ErrNotComparableValues},
+		{&Varchar{val: "1"}, &Integer{val: 1}, 0, ErrNotComparableValues},
+
+		{&Float64{val: 1}, &Bool{val: true}, 0, ErrNotComparableValues},
+		{&Integer{val: 1}, &Bool{val: true}, 0, ErrNotComparableValues},
+
+		{&Integer{val: 1}, &NullValue{t: Float64Type}, 1, nil},
+		{&Float64{val: nan}, &NullValue{t: IntegerType}, 1, nil},
+		{&NullValue{t: Float64Type}, &Float64{val: math.Inf(-1)}, -1, nil},
+		{&NullValue{t: Float64Type}, &Integer{val: 2}, -1, nil},
+		{&NullValue{t: IntegerType}, &Float64{val: 10}, -1, nil},
+		{&NullValue{t: IntegerType}, &NullValue{t: Float64Type}, 0, nil},
+		{&NullValue{t: IntegerType}, &Varchar{val: "1"}, 0, ErrNotComparableValues},
+	} {
+		t.Run(fmt.Sprintf("%s cmp %s", d.lv, d.rv), func(t *testing.T) {
+			res, err := d.lv.Compare(d.rv)
+			if d.err != nil {
+				require.ErrorIs(t, err, d.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, d.res, res)
+		})
+	}
+
+	t.Run("NULL values are compared to values of the other numeric type", func(t *testing.T) {
+		engine := setupCommonTest(t)
+
+		_, _, err := engine.Exec(context.Background(), nil, `
+			CREATE TABLE nums (id INTEGER, v INTEGER, f FLOAT, PRIMARY KEY id);
+			INSERT INTO nums (id, v, f) VALUES (1, 10, NULL), (2, NULL, 2.5), (3, 10, 3.0);
+		`, nil)
+		require.NoError(t, err)
+
+		for _, d := range []struct {
+			where    string
+			expected []interface{}
+		}{
+			{"f > 2", []interface{}{int64(2), int64(3)}},
+			{"2 < f", []interface{}{int64(2), int64(3)}},
+			{"v = 10.0", []interface{}{int64(1), int64(3)}},
+			{"10.0 = v", []interface{}{int64(1), int64(3)}},
+			{"v = f", []interface{}{}},
+		} {
+			rows, err := engine.queryAll(context.Background(), nil, "SELECT id FROM nums WHERE "+d.where+" ORDER BY id", nil)
+			require.NoError(t, err, d.where)
+
+			ids := make([]interface{}, len(rows))
+			for i, row := range rows {
+				ids[i] = row.ValuesByPosition[0].RawValue()
+			}
+			require.Equal(t, d.expected, ids, d.where)
+		}
+	})
+}
+
+func TestFloatSpecialValues(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, "CREATE TABLE floats (id INTEGER AUTO_INCREMENT, f FLOAT, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE INDEX ON floats (f)", nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		INSERT INTO floats (f) VALUES ('NaN'), ('Infinity'), (1.5), ('-Infinity'), (CAST('nan' AS FLOAT))
+	`, nil)
+	require.NoError(t, err)
+
+	values := func(t *testing.T, sql string) []interface{} {
+		rows, err := engine.queryAll(context.Background(), nil, sql, nil)
+		require.NoError(t, err)
+
+		values := make([]interface{}, len(rows))
+		for i, row := range rows {
+			values[i] = row.ValuesByPosition[0].RawValue()
+		}
+		return values
+	}
+
+	requireFloats := func(t *testing.T, expected []float64, values []interface{}) {
+		require.Len(t, values, len(expected))
+
+		for i, v := range values {
+			if math.IsNaN(expected[i]) {
+				require.True(t, math.IsNaN(v.(float64)), "position %d", i)
+				continue
+			}
+			require.Equal(t, expected[i], v, "position %d", i)
+		}
+	}
+
+	nan, inf := math.NaN(), math.Inf(1)
+
+	t.Run("NaN is unordered in comparisons", func(t *testing.T) {
+		for _, idx := range []string{"", "USE INDEX ON (id)"} {
+			require.Equal(t, []interface{}{int64(2), int64(3), int64(4)}, values(t, "SELECT id FROM floats "+idx+" WHERE f = f"))
+			require.Equal(t, []interface{}{int64(1), int64(5)}, values(t, "SELECT id FROM floats "+idx+" WHERE f <> f ORDER BY id"))
+			require.Equal(t, []interface{}{int64(2), int64(3)}, values(t, "SELECT id FROM floats "+idx+" WHERE f > 0 ORDER BY id"))
+			require.Equal(t, []interface{}{int64(4)}, values(t, "SELECT id FROM floats "+idx+" WHERE f <= 0"))
+			require.Empty(t, values(t, "SELECT id FROM floats "+idx+" WHERE f = 'NaN'"))
+			require.Empty(t, values(t, "SELECT id FROM floats "+idx+" WHERE f >= CAST('NaN' AS FLOAT) OR f <= CAST('NaN' AS FLOAT)"))
+			require.Equal(t, []interface{}{int64(3)}, values(t, "SELECT id FROM floats "+idx+" WHERE f IN ('NaN', 1.5)"))
+			require.Equal(t, []interface{}{int64(1), int64(5)}, values(t, "SELECT id FROM floats "+idx+" WHERE f NOT IN (1.5, 'Infinity', '-Infinity') ORDER BY id"))
+			require.Equal(t, []interface{}{int64(3)}, values(t, "SELECT id FROM floats "+idx+" WHERE f BETWEEN 0 AND CAST('NaN' AS FLOAT) OR f BETWEEN 1 AND 2"))
+		}
+
+		require.Equal(t, []interface{}{false, true, false}, values(t, `
+			SELECT CAST('NaN' AS FLOAT) = CAST('NaN' AS FLOAT) AS eq
+			UNION ALL SELECT CAST('NaN' AS FLOAT) <> CAST('NaN' AS FLOAT) AS eq
+			UNION ALL SELECT CASE CAST('NaN' AS FLOAT) WHEN CAST('NaN' AS FLOAT) THEN true ELSE false END AS eq
+		`))
+
+		require.Equal(t, []interface{}{int64(1), int64(5)}, values(t, "SELECT id FROM floats WHERE f IS NOT DISTINCT FROM 'NaN' ORDER BY id"))
+	})
+
+	t.Run("NaN is sorted after any other number", func(t *testing.T) {
+		for _, idx := range []string{"", "USE INDEX ON (id)"} {
+			requireFloats(t, []float64{-inf, 1.5, inf, nan, nan}, values(t, "SELECT f FROM floats "+idx+" ORDER BY f"))
+			requireFloats(t, []float64{nan, nan, inf, 1.5, -inf}, values(t, "SELECT f FROM floats "+idx+" ORDER BY f DESC"))
+		}
+
+		requireFloats(t, []float64{-inf, 1.5, inf, nan}, values(t, "SELECT DISTINCT f FROM floats ORDER BY f"))
+		require.Equal(t, []interface{}{int64(1), int64(1), int64(1), int64(2)}, values(t, "SELECT COUNT(*) FROM floats GROUP BY f ORDER BY f"))
+	})
+
+	t.Run("infinity arithmetic", func(t *testing.T) {
+		requireFloats(t, []float64{inf, nan, nan, 0, -inf}, values(t, `
+			SELECT f + 1 FROM floats WHERE id = 2
+			UNION ALL SELECT f - f FROM floats WHERE id = 2
+			UNION ALL SELECT f * 0 FROM floats WHERE id = 2
+			UNION ALL SELECT 1 / f FROM floats WHERE id = 2
+			UNION ALL SELECT -f FROM floats WHERE id = 2
+		`))
+
+		requireFloats(t, []float64{nan, nan}, values(t, "SELECT f + 1 FROM floats WHERE f <> f"))
+	})
+
+	t.Run("aggregations", func(t *testing.T) {
+		requireFloats(t, []float64{inf}, values(t, "SELECT SUM(f) FROM floats WHERE f > 0"))
+		requireFloats(t, []float64{nan}, values(t, "SELECT SUM(f) FROM floats WHERE f = f"))
+		requireFloats(t, []float64{nan}, values(t, "SELECT AVG(f) FROM floats"))
+		requireFloats(t, []float64{inf}, values(t, "SELECT AVG(f) FROM floats WHERE f > 0"))
+		requireFloats(t, []float64{nan}, values(t, "SELECT MAX(f) FROM floats"))
+		requireFloats(t, []float64{-inf}, values(t, "SELECT MIN(f) FROM floats"))
+	})
+}
+
+func TestIntegerOverflow(t *testing.T) {
+	newEngine := func(t *testing.T, opts *Options) *Engine {
+		st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+		require.NoError(t, err)
+		t.Cleanup(func() { closeStore(t, st) })
+
+		engine, err := NewEngine(st, opts.WithPrefix(sqlPrefix))
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, `
+			CREATE TABLE big (id INTEGER AUTO_INCREMENT, n INTEGER, grp VARCHAR, PRIMARY KEY id);
+			INSERT INTO big (n, grp) VALUES (9223372036854775000, 'a'), (1000, 'a'), (-5, 'b'), (7, 'b');
+		`, nil)
+		require.NoError(t, err)
+
+		return engine
+	}
+
+	values := func(t *testing.T, engine *Engine, sql string) []interface{} {
+		rows, err := engine.queryAll(context.Background(), nil, sql, nil)
+		require.NoError(t, err)
+
+		values := make([]interface{}, len(rows))
+		for i, row := range rows {
+			values[i] = row.ValuesByPosition[0].RawValue()
+		}
+		return values
+	}
+
+	t.Run("overflows fail by default", func(t *testing.T) {
+		engine := newEngine(t, DefaultOptions())
+
+		_, err := engine.queryAll(context.Background(), nil, "SELECT n + 1000 FROM big", nil)
+		require.ErrorIs(t, err, ErrIntegerOverflow)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT id FROM big WHERE n * n > 0", nil)
+		require.ErrorIs(t, err, ErrIntegerOverflow)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT SUM(n) FROM big", nil)
+		require.ErrorIs(t, err, ErrIntegerOverflow)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT AVG(n) FROM big", nil)
+		require.ErrorIs(t, err, ErrIntegerOverflow)
+
+		_, _, err = engine.Exec(context.Background(), nil, "UPDATE big SET n = n * 2 WHERE grp = 'a'", nil)
+		require.ErrorIs(t, err, ErrIntegerOverflow)
+
+		// values not overflowing are not affected
+		require.Equal(t, []interface{}{int64(1000), int64(2)}, values(t, engine, "SELECT SUM(n) FROM big WHERE grp = 'b' OR id = 2 GROUP BY grp"))
+		require.Equal(t, []interface{}{int64(9223372036854775807)}, values(t, engine, "SELECT n + 807 FROM big WHERE id = 1"))
+		require.Equal(t, []interface{}{int64(-9223372036854775808)}, values(t, engine, "SELECT -n - 808 FROM big WHERE id = 1"))
+
+		// float arithmetic is not affected
+		require.Equal(t, []interface{}{float64(9223372036854775000) * 2}, values(t, engine, "SELECT n * 2.0 FROM big WHERE id = 1"))
+	})
+
+	t.Run("overflowing rows may be skipped", func(t *testing.T) {
+		engine := newEngine(t, DefaultOptions().WithSkipErroredRows(true))
+
+		require.Equal(t, []interface{}{int64(2), int64(3), int64(4)}, values(t, engine, "SELECT id FROM big WHERE n + 1000 > 0 OR n < 0"))
+	})
+
+	t.Run("saturating overflows", func(t *testing.T) {
+		engine := newEngine(t, DefaultOptions().WithIntegerOverflow(IntegerOverflowSaturate))
+
+		require.Equal(t, []interface{}{int64(math.MaxInt64)}, values(t, engine, "SELECT n + 1000 FROM big WHERE id = 1"))
+		require.Equal(t, []interface{}{int64(math.MinInt64)}, values(t, engine, "SELECT n * -2 FROM big WHERE id = 1"))
+		require.Equal(t, []interface{}{int64(math.MaxInt64)}, values(t, engine, "SELECT SUM(n) FROM big"))
+		require.Equal(t, []interface{}{int64(math.MaxInt64), int64(2)}, values(t, engine, "SELECT SUM(n) FROM big GROUP BY grp"))
+
+		// later values are added to the saturated sum
+		require.Equal(t, []interface{}{int64(math.MaxInt64 - 5)}, values(t, engine, "SELECT SUM(n) FROM big WHERE id != 4"))
+
+		// values equal to a saturated result are found when an index is used
+		_, _, err := engine.Exec(context.Background(), nil, "CREATE INDEX ON big (n)", nil)
+		require.NoError(t, err)
+
+		require.Equal(t, []interface{}{int64(1)}, values(t, engine, "SELECT id FROM big WHERE n + 1000 = 9223372036854775807"))
+	})
+
+	t.Run("wrapping overflows", func(t *testing.T) {
+		engine := newEngine(t, DefaultOptions().WithIntegerOverflow(IntegerOverflowWrap))
+
+		require.Equal(t, []interface{}{int64(9223372036854775000 + 1000 - 1<<64)}, values(t, engine, "SELECT n + 1000 FROM big WHERE id = 1"))
+		require.Equal(t, []interface{}{int64(9223372036854775000 + 1000 + 2 - 1<<64)}, values(t, engine, "SELECT SUM(n) FROM big"))
+	})
+
+	t.Run("invalid option", func(t *testing.T) {
+		st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+		require.NoError(t, err)
+		defer closeStore(t, st)
+
+		_, err = NewEngine(st, DefaultOptions().WithIntegerOverflow(IntegerOverflowWrap+1))
+		require.ErrorIs(t, err, store.ErrInvalidOptions)
+	})
 }
@@ -16,13 +16,23 @@ limitations under the License.
 
 package sql
 
-import "context"
+import (
+	"context"
+	"iter"
+)
+
+// rowSkipper is implemented by the row readers able to skip rows more cheaply than reading them
+type rowSkipper interface {
+	skip(ctx context.Context, n int) (int, error)
+}
 
 type offsetRowReader struct {
 	rowReader RowReader
 
 	offset  int
 	skipped int
+
+	lookahead rowLookahead
 }
 
 func newOffsetRowReader(rowReader RowReader, offset int) *offsetRowReader {
@@ -68,7 +78,27 @@ func (r *offsetRowReader) InferParameters(ctx context.Context, params map[string
 	return r.rowReader.InferParameters(ctx, params)
 }
 
+func (r *offsetRowReader) All(ctx context.Context) iter.Seq2[*Row, error] {
+	return allRows(ctx, r)
+}
+
 func (r *offsetRowReader) Read(ctx context.Context) (*Row, error) {
+	return r.lookahead.read(ctx, r.readRow)
+}
+
+func (r *offsetRowReader) Peek(ctx context.Context) (*Row, error) {
+	return r.lookahead.peek(ctx, r.readRow)
+}
+
+func (r *offsetRowReader) readRow(ctx context.Context) (*Row, error) {
+	if skipper, ok := r.rowReader.(rowSkipper); ok && r.skipped < r.offset {
+		skipped, err := skipper.skip(ctx, r.offset-r.skipped)
+		r.skipped += skipped
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	for {
 		row, err := r.rowReader.Read(ctx)
 		if err != nil {
@@ -85,5 +115,7 @@ func (r *offsetRowReader) Read(ctx context.Context) (*Row, error) {
 }
 
 func (r *offsetRowReader) Close() error {
+	r.lookahead.discard()
+
 	return r.rowReader.Close()
 }
@@ -50,3 +50,63 @@ func TestOffsetRowReader(t *testing.T) {
 	err = rowReader.InferParameters(context.Background(), nil)
 	require.ErrorIs(t, err, errDummy)
 }
+
+// countingRowReader counts the rows read from the wrapped reader
+type countingRowReader struct {
+	RowReader
+	reads int
+}
+
+func (r *countingRowReader) Read(ctx context.Context) (*Row, error) {
+	row, err := r.RowReader.Read(ctx)
+	if err == nil {
+		r.reads++
+	}
+	return row, err
+}
+
+type skippingRowReader struct {
+	countingRowReader
+	rows *mockRowReader
+}
+
+func newSkippingRowReader(rows []*Row) *skippingRowReader {
+	r := &mockRowReader{rows: rows}
+	return &skippingRowReader{countingRowReader: countingRowReader{RowReader: r}, rows: r}
+}
+
+func (r *skippingRowReader) skip(ctx context.Context, n int) (int, error) {
+	skipped := min(n, len(r.rows.rows)-r.rows.curr)
+	r.rows.curr += skipped
+	return skipped, nil
+}
+
+func TestOffsetRowReaderSkipping(t *testing.T) {
+	rows := make([]*Row, 1010)
+	for i := range rows {
+		rows[i] = &Row{ValuesByPosition: []TypedValue{&Integer{val: int64(i)}}}
+	}
+
+	readAll := func(t *testing.T, r RowReader, offset int) []int64 {
+		rowReader := newOffsetRowReader(r, offset)
+
+		var vals []int64
+		for row, err := range rowReader.All(context.Background()) {
+			require.NoError(t, err)
+			vals = append(vals, row.ValuesByPosition[0].RawValue().(int64))
+		}
+		return vals
+	}
+
+	scanning := &countingRowReader{RowReader: &mockRowReader{rows: rows}}
+	require.Equal(t, []int64{1005, 1006, 1007, 1008, 1009}, readAll(t, scanning, 1005))
+	require.Equal(t, 1010, scanning.reads)
+
+	skipping := newSkippingRowReader(rows)
+	require.Equal(t, []int64{1005, 1006, 1007, 1008, 1009}, readAll(t, skipping, 1005))
+	require.Equal(t, 5, skipping.reads)
+
+	skipping = newSkippingRowReader(rows)
+	require.Empty(t, readAll(t, skipping, 5000))
+	require.Equal(t, 0, skipping.reads)
+}
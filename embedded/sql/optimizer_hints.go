@@ -0,0 +1,205 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"fmt"
+	"strings"
+)
+
+// indexHint is an optimizer hint determining the index used to scan the rows of a table.
+// Hints are specified in a comment right after the SELECT keyword, e.g.
+//
+//	SELECT /*+ INDEX(t (val)) FULL(u) */ ...
+//
+// where INDEX forces the use of the index over the listed columns and FULL forces
+// the rows to be scanned over the primary index.
+type indexHint struct {
+	table    string
+	cols     []string
+	fullScan bool
+}
+
+func (h *indexHint) String() string {
+	if h.fullScan {
+		return fmt.Sprintf("FULL(%s)", h.table)
+	}
+	return fmt.Sprintf("INDEX(%s (%s))", h.table, strings.Join(h.cols, ", "))
+}
+
+// parseOptimizerHints parses the content of a hint comment, without the leading '+'
+func parseOptimizerHints(text string) ([]*indexHint, error) {
+	tokens, err := hintTokens(text)
+	if err != nil {
+		return nil, err
+	}
+
+	var hints []*indexHint
+
+	expect := func(tkn string) error {
+		if len(tokens) == 0 || tokens[0] != tkn {
+			return fmt.Errorf("%w: '%s' expected in optimizer hint", ErrIllegalArguments, tkn)
+		}
+		tokens = tokens[1:]
+		return nil
+	}
+
+	identifier := func() (string, error) {
+		if len(tokens) == 0 || tokens[0] == "(" || tokens[0] == ")" || tokens[0] == "," {
+			return "", fmt.Errorf("%w: identifier expected in optimizer hint", ErrIllegalArguments)
+		}
+		id := tokens[0]
+		tokens = tokens[1:]
+		return id, nil
+	}
+
+	for len(tokens) > 0 {
+		name, err := identifier()
+		if err != nil {
+			return nil, err
+		}
+
+		err = expect("(")
+		if err != nil {
+			return nil, err
+		}
+
+		table, err := identifier()
+		if err != nil {
+			return nil, err
+		}
+
+		hint := &indexHint{table: table}
+
+		switch strings.ToUpper(name) {
+		case "FULL":
+			hint.fullScan = true
+		case "INDEX":
+			if len(tokens) == 0 || tokens[0] == ")" {
+				return nil, fmt.Errorf("%w: index columns expected after table '%s' in INDEX hint", ErrIllegalArguments, table)
+			}
+
+			err = expect("(")
+			if err != nil {
+				return nil, err
+			}
+
+			for {
+				col, err := identifier()
+				if err != nil {
+					return nil, err
+				}
+
+				hint.cols = append(hint.cols, col)
+
+				if len(tokens) == 0 || tokens[0] != "," {
+					break
+				}
+				tokens = tokens[1:]
+			}
+
+			err = expect(")")
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("%w: unsupported optimizer hint '%s'", ErrIllegalArguments, name)
+		}
+
+		err = expect(")")
+		if err != nil {
+			return nil, err
+		}
+
+		hints = append(hints, hint)
+	}
+
+	if len(hints) == 0 {
+		return nil, fmt.Errorf("%w: empty optimizer hint", ErrIllegalArguments)
+	}
+	return hints, nil
+}
+
+// hintTokens splits the text into identifiers, parentheses and commas.
+// As in any other part of a statement, unquoted identifiers are case-insensitive.
+func hintTokens(text string) ([]string, error) {
+	var tokens []string
+
+	for i := 0; i < len(text); {
+		ch := text[i]
+
+		switch {
+		case isSpace(ch) || isLineBreak(ch) || ch == '\t':
+			i++
+		case ch == '(' || ch == ')' || ch == ',':
+			tokens = append(tokens, string(ch))
+			i++
+		case isLetter(ch):
+			j := i + 1
+			for j < len(text) && (isLetter(text[j]) || isNumber(text[j])) {
+				j++
+			}
+			tokens = append(tokens, strings.ToLower(text[i:j]))
+			i = j
+		case isDoubleQuote(ch):
+			j := strings.IndexByte(text[i+1:], '"')
+			if j <= 0 {
+				return nil, fmt.Errorf("%w: invalid quoted identifier in optimizer hint", ErrIllegalArguments)
+			}
+			tokens = append(tokens, text[i+1:i+1+j])
+			i += j + 2
+		default:
+			return nil, fmt.Errorf("%w: unexpected character '%c' in optimizer hint", ErrIllegalArguments, ch)
+		}
+	}
+	return tokens, nil
+}
+
+// applyIndexHints sets the index used to scan each of the tables the hints refer to
+func (stmt *SelectStmt) applyIndexHints(hints []*indexHint) error {
+	for _, hint := range hints {
+		var indexOn *[]string
+		var fullScan *bool
+		var ds DataSource
+
+		if stmt.ds != nil && stmt.ds.Alias() == hint.table {
+			ds, indexOn, fullScan = stmt.ds, &stmt.indexOn, &stmt.fullScan
+		}
+
+		for _, jspec := range stmt.joins {
+			if jspec.ds.Alias() == hint.table {
+				ds, indexOn, fullScan = jspec.ds, &jspec.indexOn, &jspec.fullScan
+			}
+		}
+
+		if ds == nil {
+			return fmt.Errorf("%w: optimizer hint %s refers to a table not included in the query", ErrIllegalArguments, hint)
+		}
+
+		if _, isTableRef := ds.(*tableRef); !isTableRef {
+			return fmt.Errorf("%w: optimizer hint %s does not refer to a table", ErrIllegalArguments, hint)
+		}
+
+		if len(*indexOn) > 0 || *fullScan {
+			return fmt.Errorf("%w: index for table '%s' already specified", ErrIllegalArguments, hint.table)
+		}
+
+		*indexOn = hint.cols
+		*fullScan = hint.fullScan
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptimizerHints(t *testing.T) {
+	hints, err := parseOptimizerHints(` INDEX(t (val, "Name"))  full(U) `)
+	require.NoError(t, err)
+	require.Equal(t, []*indexHint{
+		{table: "t", cols: []string{"val", "Name"}},
+		{table: "u", fullScan: true},
+	}, hints)
+
+	for _, text := range []string{
+		"",
+		"INDEX",
+		"INDEX(t)",
+		"INDEX(t ())",
+		"INDEX(t (val)",
+		"FULL(t, u)",
+		"HASH(t)",
+		"FULL(t) ;",
+		`FULL("t)`,
+	} {
+		_, err := parseOptimizerHints(text)
+		require.ErrorIs(t, err, ErrIllegalArguments, text)
+	}
+}
+
+func TestOptimizerHints(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, `
+		CREATE TABLE t (id INTEGER, val INTEGER, name VARCHAR[32], PRIMARY KEY id);
+		CREATE INDEX ON t(val);
+		CREATE INDEX ON t(name);
+		CREATE INDEX ON t(val, name);
+		INSERT INTO t (id, val, name) VALUES (1, 10, 'a'), (2, 20, 'b'), (3, 10, 'c');
+	`, nil)
+	require.NoError(t, err)
+
+	scanIndex := func(t *testing.T, sql string) string {
+		r, err := engine.Query(context.Background(), nil, sql, nil)
+		require.NoError(t, err)
+		defer r.Close()
+
+		return r.ScanSpecs().Index.Name()
+	}
+
+	t.Run("the planner picks an index when no hint is given", func(t *testing.T) {
+		require.Equal(t, "t(val)", scanIndex(t, "SELECT id FROM t WHERE val = 10"))
+	})
+
+	t.Run("hinted indexes are used", func(t *testing.T) {
+		require.Equal(t, "t(name)", scanIndex(t, "SELECT /*+ INDEX(t (name)) */ id FROM t WHERE val = 10"))
+		require.Equal(t, "t(val,name)", scanIndex(t, "SELECT /*+ INDEX(t (val, name)) */ id FROM t WHERE val = 10"))
+		require.Equal(t, "t(id)", scanIndex(t, "SELECT /*+ FULL(t) */ id FROM t WHERE val = 10"))
+		require.Equal(t, "t(name)", scanIndex(t, "SELECT /*+ INDEX(x (name)) */ id FROM t AS x WHERE val = 10"))
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT /*+ FULL(t) */ id FROM t WHERE val = 10 ORDER BY id", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+		require.Equal(t, int64(1), rows[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, int64(3), rows[1].ValuesByPosition[0].RawValue())
+	})
+
+	t.Run("hints may refer to joined tables", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, `
+			SELECT /*+ FULL(t1) INDEX(t2 (val)) */ t1.id, t2.id
+			FROM t AS t1 INNER JOIN t AS t2 ON t1.val = t2.val
+			WHERE t1.id = 1
+			ORDER BY t2.id`, nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 2)
+
+		// the index of the joined table is looked up once rows are read
+		_, err = engine.queryAll(context.Background(), nil, `
+			SELECT /*+ INDEX(t2 (id, val)) */ t1.id
+			FROM t AS t1 INNER JOIN t AS t2 ON t1.val = t2.val`, nil)
+		require.ErrorIs(t, err, ErrIndexNotFound)
+	})
+
+	t.Run("comments not starting with + are not hints", func(t *testing.T) {
+		require.Equal(t, "t(val)", scanIndex(t, "SELECT /* INDEX(t (name)) */ id FROM t WHERE val = 10"))
+		require.Equal(t, "t(val)", scanIndex(t, "SELECT id /*+ INDEX(t (name)) */ FROM t WHERE val = 10"))
+	})
+
+	t.Run("hints that can not be honored", func(t *testing.T) {
+		_, err := engine.Query(context.Background(), nil, "SELECT /*+ INDEX(t (id, val)) */ id FROM t", nil)
+		require.ErrorIs(t, err, ErrIndexNotFound)
+
+		_, err = engine.Query(context.Background(), nil, "SELECT /*+ INDEX(t (missing)) */ id FROM t", nil)
+		require.ErrorIs(t, err, ErrColumnDoesNotExist)
+
+		_, err = engine.Query(context.Background(), nil, "SELECT /*+ INDEX(h (val)) */ id FROM (HISTORY OF t) AS h", nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		for sql, msg := range map[string]string{
+			"SELECT /*+ INDEX(u (val)) */ id FROM t":                     "refers to a table not included in the query",
+			"SELECT /*+ INDEX(t (val)) */ id FROM t USE INDEX ON (name)": "index for table 't' already specified",
+			"SELECT /*+ FULL(t) INDEX(t (val)) */ id FROM t":             "index for table 't' already specified",
+			"SELECT /*+ FULL(s) */ id FROM (SELECT id FROM t) AS s":      "does not refer to a table",
+			"SELECT /*+ SCAN(t) */ id FROM t":                            "unsupported optimizer hint 'scan'",
+			"SELECT /*+ FULL(t) */ 1":                                    "optimizer hints require a FROM clause",
+			"SELECT /*+ INDEX(t) */ id FROM t":                           "index columns expected after table 't' in INDEX hint",
+			"SELECT /*+ INDEX(t */ id FROM t":                            "index columns expected after table 't' in INDEX hint",
+			"SELECT /*+ INDEX(t val) */ id FROM t":                       "'(' expected in optimizer hint",
+		} {
+			_, err := engine.Query(context.Background(), nil, sql, nil)
+			require.ErrorIs(t, err, ErrParsingError, sql)
+			require.ErrorContains(t, err, msg, sql)
+		}
+	})
+}
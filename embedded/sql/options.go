@@ -18,6 +18,8 @@ package sql
 
 import (
 	"fmt"
+	"math/rand"
+	"time"
 
 	"github.com/codenotary/immudb/embedded/store"
 )
@@ -33,7 +35,28 @@ type Options struct {
 	distinctLimit                 int
 	autocommit                    bool
 	lazyIndexConstraintValidation bool
+	readOnly                      bool
+	stableOrdering                bool
+	nullsOrder                    NullsOrder
+	validateIfNotExists           bool
+	hllPrecision                  int
+	clock                         func() time.Time
+	randSource                    rand.Source
 	parseTxMetadata               func([]byte) (map[string]interface{}, error)
+	lazyDecoding                  bool
+	coalescingMaxDelay            time.Duration
+	coalescingMaxBatch            int
+	countDistinctMemoryBudget     int
+	resourceLimits                ResourceLimits
+	keyEncoder                    KeyEncoder
+	rowChecksums                  bool
+	rowChecksumKey                []byte
+	skipErroredRows               bool
+	integerOverflow               IntegerOverflow
+	rowCounts                     bool
+	scanShards                    int
+	workerPoolSize                int
+	caseSensitiveIds              bool
 
 	multidbHandler MultiDBHandler
 	tableResolvers []TableResolver
@@ -41,8 +64,12 @@ type Options struct {
 
 func DefaultOptions() *Options {
 	return &Options{
-		sortBufferSize: defaultSortBufferSize,
-		distinctLimit:  defaultDistinctLimit,
+		sortBufferSize:            defaultSortBufferSize,
+		distinctLimit:             defaultDistinctLimit,
+		hllPrecision:              DefaultHLLPrecision,
+		countDistinctMemoryBudget: defaultCountDistinctMemoryBudget,
+		keyEncoder:                DefaultKeyEncoder(),
+		nullsOrder:                NullsAsSmallest,
 	}
 }
 
@@ -59,6 +86,43 @@ func (opts *Options) Validate() error {
 		return fmt.Errorf("%w: invalid SortBufferSize value", store.ErrInvalidOptions)
 	}
 
+	if opts.hllPrecision < MinHLLPrecision || opts.hllPrecision > MaxHLLPrecision {
+		return fmt.Errorf("%w: invalid HLLPrecision value", store.ErrInvalidOptions)
+	}
+
+	if opts.countDistinctMemoryBudget <= 0 {
+		return fmt.Errorf("%w: invalid CountDistinctMemoryBudget value", store.ErrInvalidOptions)
+	}
+
+	if opts.coalescingMaxDelay < 0 || opts.coalescingMaxBatch < 0 {
+		return fmt.Errorf("%w: invalid CommitCoalescing value", store.ErrInvalidOptions)
+	}
+
+	if opts.keyEncoder == nil {
+		return fmt.Errorf("%w: invalid KeyEncoder value", store.ErrInvalidOptions)
+	}
+
+	if opts.nullsOrder < NullsAsSmallest || opts.nullsOrder > NullsLast {
+		return fmt.Errorf("%w: invalid NullsOrder value", store.ErrInvalidOptions)
+	}
+
+	if opts.integerOverflow < IntegerOverflowError || opts.integerOverflow > IntegerOverflowWrap {
+		return fmt.Errorf("%w: invalid IntegerOverflow value", store.ErrInvalidOptions)
+	}
+
+	if opts.scanShards < 0 {
+		return fmt.Errorf("%w: invalid ScanShards value", store.ErrInvalidOptions)
+	}
+
+	if opts.workerPoolSize < 0 {
+		return fmt.Errorf("%w: invalid WorkerPoolSize value", store.ErrInvalidOptions)
+	}
+
+	err := opts.resourceLimits.Validate()
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -82,6 +146,186 @@ func (opts *Options) WithLazyIndexConstraintValidation(lazyIndexConstraintValida
 	return opts
 }
 
+// WithReadOnly opens the engine in read-only mode. Any statement that is not
+// read-only (DDL or DML) is rejected with ErrReadOnly before being executed,
+// and transactions are created without write capability.
+func (opts *Options) WithReadOnly(readOnly bool) *Options {
+	opts.readOnly = readOnly
+	return opts
+}
+
+// WithStableOrdering makes the results of queries containing an ORDER BY clause
+// totally ordered, by implicitly appending the primary key columns as final
+// tiebreakers when the explicit ordering does not uniquely identify each row.
+// It guarantees a consistent ordering of rows with equal sort keys across runs,
+// as required by keyset pagination.
+func (opts *Options) WithStableOrdering(stableOrdering bool) *Options {
+	opts.stableOrdering = stableOrdering
+	return opts
+}
+
+// WithNullsOrder sets where NULL values are placed by ORDER BY clauses which do not
+// specify NULLS FIRST or NULLS LAST. By default, NULL values are the smallest values.
+func (opts *Options) WithNullsOrder(nullsOrder NullsOrder) *Options {
+	opts.nullsOrder = nullsOrder
+	return opts
+}
+
+// WithIfNotExistsValidation makes CREATE TABLE IF NOT EXISTS and CREATE INDEX IF NOT EXISTS
+// statements fail with ErrSchemaMismatch when the existing table or index does not match
+// the one specified by the statement. By default, such statements are a no-op whenever
+// the table or index already exists.
+func (opts *Options) WithIfNotExistsValidation(validate bool) *Options {
+	opts.validateIfNotExists = validate
+	return opts
+}
+
+// WithHLLPrecision specifies the precision of the HyperLogLog sketches used by APPROX_COUNT_DISTINCT.
+// Each group being aggregated requires 2^precision bytes and the standard error of the estimate
+// is 1.04/sqrt(2^precision), e.g. ~0.81% with the default precision of 14.
+func (opts *Options) WithHLLPrecision(precision int) *Options {
+	opts.hllPrecision = precision
+	return opts
+}
+
+// WithClock specifies the clock used to determine the timestamp of each transaction,
+// as returned by NOW(). When not specified, the timestamp of the underlying store
+// transaction is used.
+func (opts *Options) WithClock(clock func() time.Time) *Options {
+	opts.clock = clock
+	return opts
+}
+
+// WithRandSource specifies the source of pseudo-random values used by functions such as
+// RANDOM() and RANDOM_UUID(). A seeded source makes their results reproducible.
+// When not specified, values are generated from the default sources.
+func (opts *Options) WithRandSource(src rand.Source) *Options {
+	opts.randSource = src
+	return opts
+}
+
+// WithLazyDecoding makes the values of the rows read from tables to be decoded only when accessed,
+// so the columns not referenced by a query are not decoded. It applies to queries whose rows are
+// only filtered, limited and projected, which are the ones benefiting from it on wide tables.
+func (opts *Options) WithLazyDecoding(lazyDecoding bool) *Options {
+	opts.lazyDecoding = lazyDecoding
+	return opts
+}
+
+// WithCommitCoalescing makes concurrent INSERT statements executed outside of a transaction
+// to be committed together, in a single transaction per table. A batch is committed once it
+// holds maxBatch statements or maxDelay elapsed since its first statement was received.
+// Each call returns once its rows are committed, thus durability is the same as without coalescing.
+// When any statement of a batch fails, the statements are executed again in separate
+// transactions so each caller gets its own result. Coalescing is disabled when maxBatch <= 1.
+func (opts *Options) WithCommitCoalescing(maxDelay time.Duration, maxBatch int) *Options {
+	opts.coalescingMaxDelay = maxDelay
+	opts.coalescingMaxBatch = maxBatch
+	return opts
+}
+
+// WithCountDistinctMemoryBudget specifies the approximate number of bytes used to hold the values
+// counted by each COUNT(DISTINCT ...) aggregation. Once exceeded, values are sorted and written
+// to temporary files, then counted by merging them. The default value is 16MB.
+func (opts *Options) WithCountDistinctMemoryBudget(budget int) *Options {
+	opts.countDistinctMemoryBudget = budget
+	return opts
+}
+
+// WithResourceLimits specifies the resources each statement is allowed to use, statements
+// exceeding any of them fail with an error identifying the limit. Limits may be specified for
+// particular statements with ContextWithResourceLimits. By default, resources are not limited.
+func (opts *Options) WithResourceLimits(limits ResourceLimits) *Options {
+	opts.resourceLimits = limits
+	return opts
+}
+
+// WithKeyEncoder specifies how the keys of table rows and index entries are laid out in the store,
+// so they can be read by external tools. The same encoder must be used every time the data is opened.
+func (opts *Options) WithKeyEncoder(keyEncoder KeyEncoder) *Options {
+	opts.keyEncoder = keyEncoder
+	return opts
+}
+
+// WithRowChecksums makes a checksum to be stored along with each row written to a table,
+// which is verified whenever the row is read so that queries fail with ErrRowIntegrity
+// rather than returning corrupted or tampered data. Rows written while checksums were
+// disabled fail the verification as well. Reading rows requires more work when enabled,
+// and the rows written can not be verified by external tools expecting the default layout.
+func (opts *Options) WithRowChecksums(rowChecksums bool) *Options {
+	opts.rowChecksums = rowChecksums
+	return opts
+}
+
+// WithRowChecksumKey specifies the secret used to compute the row checksums as HMACs,
+// so that the rows can not be tampered with by someone who does not know it.
+// The same key must be used every time the data is opened.
+func (opts *Options) WithRowChecksumKey(key []byte) *Options {
+	opts.rowChecksumKey = key
+	return opts
+}
+
+// WithSkipErroredRows makes the rows whose WHERE or HAVING conditions fail to be evaluated
+// because of their values, e.g. due to a division by zero or an invalid cast, to be discarded
+// instead of failing the statement. Discarded rows are counted in Stats.ErrorsSkipped of the
+// transaction, and are neither updated nor deleted by UPDATE and DELETE statements.
+// Any other error, such as the cancellation of the context or a failure reading from the store,
+// still makes the statement to fail.
+func (opts *Options) WithSkipErroredRows(skipErroredRows bool) *Options {
+	opts.skipErroredRows = skipErroredRows
+	return opts
+}
+
+// WithIntegerOverflow sets the outcome of the arithmetic expressions and of the SUM and AVG
+// aggregations whose integer result doesn't fit in an INTEGER. By default, they fail with
+// ErrIntegerOverflow, rather than silently returning a wrong result.
+func (opts *Options) WithIntegerOverflow(integerOverflow IntegerOverflow) *Options {
+	opts.integerOverflow = integerOverflow
+	return opts
+}
+
+// WithRowCounts makes the number of rows of the tables created while enabled to be kept
+// up to date by the statements modifying them, so that queries counting all the rows of
+// a table, e.g. SELECT COUNT(*) FROM t, are answered without scanning it. Transactions
+// inserting or deleting rows of the same table conflict with each other, as the count
+// they update is read as part of the transaction. Counts are exact unless unsafe MVCC
+// is enabled in the store.
+func (opts *Options) WithRowCounts(rowCounts bool) *Options {
+	opts.rowCounts = rowCounts
+	return opts
+}
+
+// WithScanShards makes the scans of tables and indexes within read-only transactions to be split
+// into the given number of key ranges, read in parallel along with the values of their rows.
+// Rows are still returned in the order of the scan, thus the ranges following the one being
+// returned are only read ahead up to a bounded number of rows. Ranges are determined assuming
+// keys to be evenly distributed within the scanned range. Scans are not split when shards <= 1.
+func (opts *Options) WithScanShards(shards int) *Options {
+	opts.scanShards = shards
+	return opts
+}
+
+// WithWorkerPoolSize makes the goroutines started by queries, e.g. the ones reading the shards of
+// a scan, to be taken from a pool of up to size goroutines shared by all the queries of the engine,
+// thus avoiding to start new goroutines for each query. No more goroutines are started when all the
+// ones of the pool are busy, e.g. the shards of a scan are then read as rows are returned, without
+// reading them ahead, and those left idle are eventually stopped. Goroutines are started for each
+// query when size is 0, the default.
+func (opts *Options) WithWorkerPoolSize(size int) *Options {
+	opts.workerPoolSize = size
+	return opts
+}
+
+// WithCaseSensitiveIdentifiers makes double-quoted identifiers to keep their case, e.g. "MyCol"
+// and mycol refer to different columns, instead of being folded to lower case as unquoted ones are.
+// Tables, columns and other objects named with upper case letters while enabled can only be referred
+// to while enabled, and statements parsed outside of the engine must be parsed with the ParseOptions
+// option of the same name.
+func (opts *Options) WithCaseSensitiveIdentifiers(caseSensitive bool) *Options {
+	opts.caseSensitiveIds = caseSensitive
+	return opts
+}
+
 func (opts *Options) WithMultiDBHandler(multidbHandler MultiDBHandler) *Options {
 	opts.multidbHandler = multidbHandler
 	return opts
@@ -17,7 +17,9 @@ limitations under the License.
 package sql
 
 import (
+	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -42,11 +44,51 @@ func TestOptions(t *testing.T) {
 	opts.WithAutocommit(true)
 	require.True(t, opts.autocommit)
 
+	opts.WithReadOnly(true)
+	require.True(t, opts.readOnly)
+
+	opts.WithStableOrdering(true)
+	require.True(t, opts.stableOrdering)
+
+	opts.WithClock(time.Now)
+	require.NotNil(t, opts.clock)
+
+	opts.WithRandSource(rand.NewSource(1))
+	require.NotNil(t, opts.randSource)
+
 	opts.WithSortBufferSize(0)
 	require.Error(t, opts.Validate())
 
 	opts.WithSortBufferSize(defaultSortBufferSize)
 	require.Equal(t, opts.sortBufferSize, defaultSortBufferSize)
 
+	require.Error(t, opts.Validate())
+
+	opts.WithCountDistinctMemoryBudget(defaultCountDistinctMemoryBudget)
+	require.Equal(t, defaultCountDistinctMemoryBudget, opts.countDistinctMemoryBudget)
+
+	require.Error(t, opts.Validate())
+
+	opts.WithHLLPrecision(MaxHLLPrecision + 1)
+	require.Error(t, opts.Validate())
+
+	opts.WithHLLPrecision(DefaultHLLPrecision)
+	require.Equal(t, DefaultHLLPrecision, opts.hllPrecision)
+
+	require.Error(t, opts.Validate())
+
+	opts.WithKeyEncoder(DefaultKeyEncoder())
+	require.Equal(t, DefaultKeyEncoder(), opts.keyEncoder)
+
+	require.Error(t, opts.Validate())
+
+	opts.WithNullsOrder(NullsLast + 1)
+	require.Error(t, opts.Validate())
+
+	opts.WithNullsOrder(NullsLast)
+	require.Equal(t, NullsLast, opts.nullsOrder)
+
 	require.NoError(t, opts.Validate())
+
+	require.Equal(t, NullsAsSmallest, DefaultOptions().nullsOrder)
 }
@@ -0,0 +1,70 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import "fmt"
+
+// resolveOrdinals sets the GROUP BY columns of the statement, replacing the integer literals
+// of the GROUP BY and ORDER BY clauses with the targets they refer to by their 1-based position.
+// Any other constant expression, e.g. 1 + 0, is not an ordinal. When all the columns are selected,
+// ORDER BY ordinals are left to be resolved against the columns of the rows being sorted.
+func (stmt *SelectStmt) resolveOrdinals(groupBy []ValueExp) error {
+	for _, exp := range groupBy {
+		switch e := exp.(type) {
+		case *ColSelector:
+			stmt.groupBy = append(stmt.groupBy, e)
+		case *Integer:
+			if len(stmt.targets) == 0 {
+				return fmt.Errorf("GROUP BY position %d can not refer to the columns selected by *", e.val)
+			}
+
+			target, err := stmt.targetAt(e.val)
+			if err != nil {
+				return err
+			}
+
+			col, isCol := target.(*ColSelector)
+			if !isCol {
+				return fmt.Errorf("GROUP BY position %d does not refer to a column", e.val)
+			}
+
+			stmt.groupBy = append(stmt.groupBy, col)
+		}
+	}
+
+	if len(stmt.targets) == 0 {
+		return nil
+	}
+
+	for _, ord := range stmt.orderBy {
+		if i, isOrdinal := ord.exp.(*Integer); isOrdinal {
+			target, err := stmt.targetAt(i.val)
+			if err != nil {
+				return err
+			}
+			ord.exp = target
+		}
+	}
+	return nil
+}
+
+func (stmt *SelectStmt) targetAt(pos int64) (ValueExp, error) {
+	if pos < 1 || pos > int64(len(stmt.targets)) {
+		return nil, fmt.Errorf("position %d is not in select list", pos)
+	}
+	return stmt.targets[pos-1].Exp, nil
+}
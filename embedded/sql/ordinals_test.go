@@ -0,0 +1,151 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOrdinals(t *testing.T) {
+	stmts, err := ParseSQLString("SELECT name, age + 1 FROM t GROUP BY 1, age ORDER BY 2 DESC, 1 + 0")
+	require.NoError(t, err)
+
+	stmt := stmts[0].(*SelectStmt)
+	require.Equal(t, []*ColSelector{{col: "name"}, {col: "age"}}, stmt.groupBy)
+	require.Same(t, stmt.targets[1].Exp, stmt.orderBy[0].exp)
+	require.True(t, stmt.orderBy[0].descOrder)
+
+	// constant expressions are not ordinals
+	require.Equal(t, "(1 + 0)", stmt.orderBy[1].exp.String())
+
+	// ordinals refer to the columns of the rows being sorted when all columns are selected
+	stmts, err = ParseSQLString("SELECT * FROM t ORDER BY 2")
+	require.NoError(t, err)
+	require.Equal(t, &Integer{val: 2}, stmts[0].(*SelectStmt).orderBy[0].exp)
+
+	for _, d := range []struct {
+		sql string
+		err string
+	}{
+		{"SELECT name, age FROM t ORDER BY 3", "position 3 is not in select list"},
+		{"SELECT name, age FROM t ORDER BY 0", "position 0 is not in select list"},
+		{"SELECT name, age FROM t ORDER BY -1", "position -1 is not in select list"},
+		{"SELECT name, COUNT(*) FROM t GROUP BY 3", "position 3 is not in select list"},
+		{"SELECT name, COUNT(*) FROM t GROUP BY 2", "GROUP BY position 2 does not refer to a column"},
+		{"SELECT * FROM t GROUP BY 1", "GROUP BY position 1 can not refer to the columns selected by *"},
+	} {
+		t.Run(d.sql, func(t *testing.T) {
+			_, err := ParseSQLString(d.sql)
+			require.ErrorContains(t, err, d.err)
+		})
+	}
+}
+
+func TestOrdinals(t *testing.T) {
+	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(t, err)
+	defer closeStore(t, st)
+
+	engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE people (id INTEGER AUTO_INCREMENT, name VARCHAR, city VARCHAR, age INTEGER, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		INSERT INTO people (name, city, age) VALUES
+			('alice', 'rome', 30),
+			('bob', 'paris', 25),
+			('carol', 'rome', 41),
+			('dave', 'oslo', 19),
+			('erin', 'paris', 33)
+	`, nil)
+	require.NoError(t, err)
+
+	rawValues := func(t *testing.T, sql string) [][]interface{} {
+		rows, err := engine.queryAll(context.Background(), nil, sql, nil)
+		require.NoError(t, err)
+
+		values := make([][]interface{}, len(rows))
+		for i, row := range rows {
+			for _, v := range row.ValuesByPosition {
+				values[i] = append(values[i], v.RawValue())
+			}
+		}
+		return values
+	}
+
+	t.Run("ORDER BY ordinal", func(t *testing.T) {
+		require.Equal(t, [][]interface{}{
+			{"carol", int64(41)},
+			{"erin", int64(33)},
+			{"alice", int64(30)},
+			{"bob", int64(25)},
+			{"dave", int64(19)},
+		}, rawValues(t, "SELECT name, age FROM people ORDER BY 2 DESC"))
+
+		// ordinals refer to the selected columns rather than to the columns of the table
+		require.Equal(t,
+			rawValues(t, "SELECT age, name FROM people ORDER BY age"),
+			rawValues(t, "SELECT age, name FROM people ORDER BY 1"),
+		)
+
+		require.Equal(t, [][]interface{}{
+			{"rome", "alice"},
+			{"rome", "carol"},
+			{"paris", "bob"},
+			{"paris", "erin"},
+			{"oslo", "dave"},
+		}, rawValues(t, "SELECT city, name AS n FROM people ORDER BY 1 DESC, 2"))
+
+		require.Equal(t, [][]interface{}{
+			{"dave", int64(-19)},
+			{"bob", int64(-25)},
+		}, rawValues(t, "SELECT name, -age AS neg FROM people ORDER BY 2 DESC LIMIT 2"))
+	})
+
+	t.Run("ORDER BY a constant expression", func(t *testing.T) {
+		require.Len(t, rawValues(t, "SELECT name FROM people ORDER BY 5 + 0"), 5)
+	})
+
+	t.Run("GROUP BY ordinal", func(t *testing.T) {
+		require.Equal(t, [][]interface{}{
+			{"rome", int64(2), int64(41)},
+			{"paris", int64(2), int64(33)},
+			{"oslo", int64(1), int64(19)},
+		}, rawValues(t, "SELECT city, COUNT(*), MAX(age) AS oldest FROM people GROUP BY 1 ORDER BY 3 DESC"))
+
+		require.Equal(t,
+			rawValues(t, "SELECT city, COUNT(*) FROM people GROUP BY city ORDER BY city"),
+			rawValues(t, "SELECT city, COUNT(*) FROM people GROUP BY 1 ORDER BY 1"),
+		)
+	})
+
+	t.Run("ordinals out of range", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, "SELECT name, age FROM people ORDER BY 3", nil)
+		require.ErrorIs(t, err, ErrParsingError)
+		require.ErrorContains(t, err, "position 3 is not in select list")
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT city, COUNT(*) FROM people GROUP BY 0", nil)
+		require.ErrorIs(t, err, ErrParsingError)
+		require.ErrorContains(t, err, "position 0 is not in select list")
+	})
+}
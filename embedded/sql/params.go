@@ -0,0 +1,76 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Params holds the values of named parameters as typed values, thus they are bound
+// as they are, without inspecting the type of each value when statements are executed.
+// Params may be provided wherever a map of parameters is expected, e.g.
+//
+//	engine.Exec(ctx, tx, sql, NewParams().SetInt("id", 5).SetString("name", "x"))
+type Params map[string]interface{}
+
+func NewParams() Params {
+	return make(Params)
+}
+
+// Set binds a typed value, a nil value binds NULL
+func (p Params) Set(name string, val TypedValue) Params {
+	if val == nil {
+		val = &NullValue{t: AnyType}
+	}
+
+	p[name] = val
+	return p
+}
+
+func (p Params) SetNull(name string) Params {
+	return p.Set(name, nil)
+}
+
+func (p Params) SetInt(name string, val int64) Params {
+	return p.Set(name, &Integer{val: val})
+}
+
+func (p Params) SetFloat(name string, val float64) Params {
+	return p.Set(name, &Float64{val: val})
+}
+
+func (p Params) SetBool(name string, val bool) Params {
+	return p.Set(name, &Bool{val: val})
+}
+
+func (p Params) SetString(name string, val string) Params {
+	return p.Set(name, &Varchar{val: val})
+}
+
+func (p Params) SetBlob(name string, val []byte) Params {
+	return p.Set(name, &Blob{val: val})
+}
+
+func (p Params) SetTimestamp(name string, val time.Time) Params {
+	return p.Set(name, &Timestamp{val: val.Truncate(time.Microsecond).UTC()})
+}
+
+func (p Params) SetUUID(name string, val uuid.UUID) Params {
+	return p.Set(name, &UUID{val: val})
+}
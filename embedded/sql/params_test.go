@@ -0,0 +1,179 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypedParams(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(
+		context.Background(),
+		nil,
+		`CREATE TABLE entries (
+			id INTEGER,
+			name VARCHAR,
+			active BOOLEAN,
+			score FLOAT,
+			data BLOB,
+			ts TIMESTAMP,
+			uid UUID,
+			note VARCHAR,
+			PRIMARY KEY id
+		)`,
+		nil,
+	)
+	require.NoError(t, err)
+
+	ts := time.Date(2025, 3, 14, 15, 9, 26, 535897932, time.UTC)
+	uid := uuid.MustParse("a3f2d8a0-3c41-4b9a-8d2e-6f0c1b7e9d55")
+
+	params := NewParams().
+		SetInt("id", 1).
+		SetString("name", "immudb").
+		SetBool("active", true).
+		SetFloat("score", 9.5).
+		SetBlob("data", []byte{1, 2, 3}).
+		SetTimestamp("ts", ts).
+		SetUUID("uid", uid).
+		SetNull("note")
+
+	_, _, err = engine.Exec(
+		context.Background(),
+		nil,
+		"INSERT INTO entries (id, name, active, score, data, ts, uid, note) VALUES (@id, @name, @active, @score, @data, @ts, @uid, @note)",
+		params,
+	)
+	require.NoError(t, err)
+
+	rows, err := engine.queryAll(
+		context.Background(),
+		nil,
+		"SELECT id, name, active, score, data, ts, uid, note FROM entries WHERE id = @ID AND name = @Name",
+		NewParams().SetInt("id", 1).Set("name", NewVarchar("immudb")),
+	)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+
+	vals := rows[0].ValuesByPosition
+	require.Equal(t, int64(1), vals[0].RawValue())
+	require.Equal(t, "immudb", vals[1].RawValue())
+	require.Equal(t, true, vals[2].RawValue())
+	require.Equal(t, 9.5, vals[3].RawValue())
+	require.Equal(t, []byte{1, 2, 3}, vals[4].RawValue())
+	require.Equal(t, ts.Truncate(time.Microsecond), vals[5].RawValue())
+	require.Equal(t, uid, vals[6].RawValue())
+	require.True(t, vals[7].IsNull())
+
+	t.Run("typed params should be type checked", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "INSERT INTO entries (id, name) VALUES (@id, @name)", NewParams().SetInt("id", 2).SetInt("name", 10))
+		require.ErrorIs(t, err, ErrInvalidValue)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT id FROM entries WHERE id = @id", NewParams().SetString("id", "one"))
+		require.ErrorIs(t, err, ErrInvalidValue)
+	})
+
+	t.Run("typed and untyped params may be mixed", func(t *testing.T) {
+		params := NewParams().SetInt("id", 3)
+		params["name"] = "mixed"
+
+		_, _, err := engine.Exec(context.Background(), nil, "INSERT INTO entries (id, name) VALUES (@id, @name)", params)
+		require.NoError(t, err)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT name FROM entries WHERE id = 3", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, "mixed", rows[0].ValuesByPosition[0].RawValue())
+	})
+
+	t.Run("duplicated params should fail", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, "SELECT id FROM entries WHERE id = @id", NewParams().SetInt("id", 1).SetInt("ID", 1))
+		require.ErrorIs(t, err, ErrDuplicatedParameters)
+	})
+}
+
+func BenchmarkParamsBinding(b *testing.B) {
+	const rowCount = 10_000
+
+	setup := func(b *testing.B) *Engine {
+		st, err := store.Open(b.TempDir(), store.DefaultOptions().WithMultiIndexing(true).WithMaxTxEntries(2*rowCount))
+		require.NoError(b, err)
+		b.Cleanup(func() { st.Close() })
+
+		engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+		require.NoError(b, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE bench (id INTEGER, name VARCHAR[32], score FLOAT, PRIMARY KEY id)", nil)
+		require.NoError(b, err)
+
+		return engine
+	}
+
+	insertRows := func(b *testing.B, engine *Engine, offset int, params func(id int) map[string]interface{}) {
+		tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithExplicitClose(true))
+		require.NoError(b, err)
+
+		stmts, err := ParseSQLString("INSERT INTO bench (id, name, score) VALUES (@id, @name, @score)")
+		require.NoError(b, err)
+
+		for id := offset; id < offset+rowCount; id++ {
+			_, _, err = engine.ExecPreparedStmts(context.Background(), tx, stmts, params(id))
+			require.NoError(b, err)
+		}
+
+		err = tx.Commit(context.Background())
+		require.NoError(b, err)
+	}
+
+	b.Run("map", func(b *testing.B) {
+		engine := setup(b)
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			insertRows(b, engine, i*rowCount, func(id int) map[string]interface{} {
+				return map[string]interface{}{
+					"id":    id,
+					"name":  fmt.Sprintf("name%d", id%1000),
+					"score": float64(id) / 2,
+				}
+			})
+		}
+	})
+
+	b.Run("typed", func(b *testing.B) {
+		engine := setup(b)
+		b.ResetTimer()
+
+		for i := 0; i < b.N; i++ {
+			insertRows(b, engine, i*rowCount, func(id int) map[string]interface{} {
+				return NewParams().
+					SetInt("id", int64(id)).
+					SetString("name", fmt.Sprintf("name%d", id%1000)).
+					SetFloat("score", float64(id)/2)
+			})
+		}
+	})
+}
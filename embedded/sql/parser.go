@@ -41,6 +41,7 @@ var keywords = map[string]int{
 	"BEFORE":         BEFORE,
 	"UNTIL":          UNTIL,
 	"TABLE":          TABLE,
+	"VIEW":           VIEW,
 	"PRIMARY":        PRIMARY,
 	"KEY":            KEY,
 	"UNIQUE":         UNIQUE,
@@ -73,12 +74,31 @@ var keywords = map[string]int{
 	"ALL":            ALL,
 	"TX":             TX,
 	"JOIN":           JOIN,
+	"NATURAL":        NATURAL,
+	"LATERAL":        LATERAL,
+	"USING":          USING,
+	"FULLTEXT":       FULLTEXT,
+	"REINDEX":        REINDEX,
+	"MATCH":          MATCH,
 	"HAVING":         HAVING,
 	"WHERE":          WHERE,
 	"GROUP":          GROUP,
 	"BY":             BY,
 	"LIMIT":          LIMIT,
 	"OFFSET":         OFFSET,
+	"FETCH":          FETCH,
+	"FIRST":          FIRST,
+	"NEXT":           NEXT,
+	"ROW":            ROW,
+	"ROWS":           ROWS,
+	"ONLY":           ONLY,
+	"TIES":           TIES,
+	"NULLS":          NULLS,
+	"LAST":           LAST,
+	"COMMENT":        COMMENT,
+	"SHARE":          SHARE,
+	"TRIGGER":        TRIGGER,
+	"EACH":           EACH,
 	"ORDER":          ORDER,
 	"AS":             AS,
 	"ASC":            ASC,
@@ -127,12 +147,24 @@ var keywords = map[string]int{
 	"BLOB":           BLOB_TYPE,
 	"UUID":           UUID_TYPE,
 	"JSON":           JSON_TYPE,
+	"POINT":          POINT_TYPE,
+	"BOX":            BOX,
 	"YEAR":           YEAR,
 	"MONTH":          MONTH,
 	"DAY":            DAY,
 	"HOUR":           HOUR,
 	"MINUTE":         MINUTE,
 	"SECOND":         SECOND,
+
+	"PERCENTILE_CONT":   PERCENTILE_CONT_FN,
+	"PERCENTILE_DISC":   PERCENTILE_DISC_FN,
+	"APPROX_PERCENTILE": APPROX_PERCENTILE_FN,
+	"RESERVOIR_SAMPLE":  RESERVOIR_SAMPLE_FN,
+	"WITHIN":            WITHIN,
+
+	"GENERATED": GENERATED,
+	"ALWAYS":    ALWAYS,
+	"STORED":    STORED,
 }
 
 var joinTypes = map[string]JoinType{
@@ -164,9 +196,10 @@ var cmpOps = map[string]CmpOperator{
 	">=": GE,
 }
 
-var ErrEitherNamedOrUnnamedParams = errors.New("either named or unnamed params")
-var ErrEitherPosOrNonPosParams = errors.New("either positional or non-positional named params")
-var ErrInvalidPositionalParameter = errors.New("invalid positional parameter")
+var ErrEitherNamedOrUnnamedParams = newSQLError(ErrCodeSyntax, "either named or unnamed params")
+var ErrEitherPosOrNonPosParams = newSQLError(ErrCodeSyntax, "either positional or non-positional named params")
+var ErrInvalidPositionalParameter = newSQLError(ErrCodeSyntax, "invalid positional parameter")
+var ErrStatementNotAllowed = newSQLError(ErrCodeAccessDenied, "statement not allowed in safe mode")
 
 type positionalParamType int
 
@@ -182,6 +215,12 @@ type lexer struct {
 	namedParamsType positionalParamType
 	paramsCount     int
 	result          []SQLStmt
+	functions       *functionRegistry
+	tokenOffsets    []int // offsets of the tokens read while recording
+	lastToken       int
+
+	// quoted identifiers keep their case instead of being folded to lower case
+	caseSensitiveIds bool
 }
 
 type aheadByteReader struct {
@@ -189,6 +228,8 @@ type aheadByteReader struct {
 	nextErr   error
 	r         io.ByteReader
 	readCount int
+	recording bool
+	recorded  []byte
 }
 
 func newAheadByteReader(r io.ByteReader) *aheadByteReader {
@@ -206,6 +247,10 @@ func (ar *aheadByteReader) ReadByte() (byte, error) {
 
 	ar.readCount++
 
+	if ar.recording && ar.nextErr == nil {
+		ar.recorded = append(ar.recorded, ar.nextChar)
+	}
+
 	return ar.nextChar, ar.nextErr
 }
 
@@ -222,7 +267,72 @@ func ParseSQLString(sql string) ([]SQLStmt, error) {
 }
 
 func ParseSQL(r io.ByteReader) ([]SQLStmt, error) {
+	return parseSQL(r, nil, false)
+}
+
+// ParseOptions are the options applied when parsing a single SQL text
+type ParseOptions struct {
+	safeMode         bool
+	caseSensitiveIds bool
+}
+
+func DefaultParseOptions() *ParseOptions {
+	return &ParseOptions{}
+}
+
+// WithSafeMode makes parsing fail with ErrStatementNotAllowed unless every statement is a query,
+// i.e. a SELECT, possibly combined with UNION, or a SHOW statement. Unlike an engine in read-only
+// mode, statements are rejected before any of them is executed, such as a transaction being started.
+func (opts *ParseOptions) WithSafeMode(safeMode bool) *ParseOptions {
+	opts.safeMode = safeMode
+	return opts
+}
+
+// WithCaseSensitiveIdentifiers makes double-quoted identifiers to keep their case, as engines
+// created with the option of the same name parse them, instead of being folded to lower case.
+func (opts *ParseOptions) WithCaseSensitiveIdentifiers(caseSensitive bool) *ParseOptions {
+	opts.caseSensitiveIds = caseSensitive
+	return opts
+}
+
+func ParseSQLStringWithOptions(sql string, opts *ParseOptions) ([]SQLStmt, error) {
+	return ParseSQLWithOptions(strings.NewReader(sql), opts)
+}
+
+func ParseSQLWithOptions(r io.ByteReader, opts *ParseOptions) ([]SQLStmt, error) {
+	if opts == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	stmts, err := parseSQL(r, nil, opts.caseSensitiveIds)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.safeMode {
+		for i, stmt := range stmts {
+			if !isQuery(stmt) {
+				return nil, fmt.Errorf("%w: statement %d is not a query", ErrStatementNotAllowed, i+1)
+			}
+		}
+	}
+	return stmts, nil
+}
+
+func isQuery(stmt SQLStmt) bool {
+	switch stmt.(type) {
+	case *SelectStmt, *UnionStmt:
+		return true
+	}
+	return false
+}
+
+// parseSQL binds the parsed function calls to the provided registry so that
+// user-defined functions can be resolved while inferring types.
+func parseSQL(r io.ByteReader, functions *functionRegistry, caseSensitiveIds bool) ([]SQLStmt, error) {
 	lexer := newLexer(r)
+	lexer.functions = functions
+	lexer.caseSensitiveIds = caseSensitiveIds
 
 	yyParse(lexer)
 
@@ -230,9 +340,20 @@ func ParseSQL(r io.ByteReader) ([]SQLStmt, error) {
 }
 
 func ParseExpFromString(exp string) (ValueExp, error) {
+	return parseExpFromString(exp, false)
+}
+
+// parseStoredExp parses an expression kept in the catalog. Expressions are stored as written
+// by their String method, which quotes the identifiers including upper case letters, thus
+// parsing them keeps their case whichever way the engine parses quoted identifiers.
+func parseStoredExp(exp string) (ValueExp, error) {
+	return parseExpFromString(exp, true)
+}
+
+func parseExpFromString(exp string, caseSensitiveIds bool) (ValueExp, error) {
 	stmt := fmt.Sprintf("SELECT * FROM t WHERE %s", exp)
 
-	res, err := ParseSQLString(stmt)
+	res, err := parseSQL(strings.NewReader(stmt), nil, caseSensitiveIds)
 	if err != nil {
 		return nil, err
 	}
@@ -249,12 +370,19 @@ func newLexer(r io.ByteReader) *lexer {
 }
 
 func (l *lexer) Lex(lval *yySymType) int {
+	tkn := l.lex(lval)
+	l.lastToken = tkn
+	return tkn
+}
+
+func (l *lexer) lex(lval *yySymType) int {
 	var ch byte
 	var err error
 
 	for {
 		ch, err = l.r.ReadByte()
 		if err == io.EOF {
+			l.recordTokenOffset(len(l.r.recorded))
 			return 0
 		}
 		if err != nil {
@@ -267,8 +395,15 @@ func (l *lexer) Lex(lval *yySymType) int {
 		}
 
 		if ch == '/' && l.r.nextChar == '*' {
+			offset := len(l.r.recorded) - 1
+
 			l.r.ReadByte()
 
+			// comments starting with '+' right after the SELECT keyword hold optimizer hints
+			isHint := l.lastToken == SELECT && l.r.nextChar == '+'
+
+			var hint bytes.Buffer
+
 			for {
 				ch, err := l.r.ReadByte()
 				if err == io.EOF {
@@ -283,6 +418,16 @@ func (l *lexer) Lex(lval *yySymType) int {
 					l.r.ReadByte() // consume closing slash
 					break
 				}
+
+				if isHint {
+					hint.WriteByte(ch)
+				}
+			}
+
+			if isHint {
+				l.recordTokenOffset(offset)
+				lval.str = hint.String()[1:]
+				return OPTIMIZER_HINTS
 			}
 
 			continue
@@ -300,7 +445,11 @@ func (l *lexer) Lex(lval *yySymType) int {
 		}
 	}
 
+	l.recordTokenOffset(len(l.r.recorded) - 1)
+
 	if isSeparator(ch) {
+		// view and trigger definitions can not span multiple statements
+		l.r.recording = false
 		return STMT_SEPARATOR
 	}
 
@@ -350,6 +499,11 @@ func (l *lexer) Lex(lval *yySymType) int {
 			return AGGREGATE_FUNC
 		}
 
+		if l.functions.aggregate(tid) != nil {
+			lval.aggFn = tid
+			return AGGREGATE_FUNC
+		}
+
 		join, ok := joinTypes[tid]
 		if ok {
 			lval.joinType = join
@@ -358,6 +512,10 @@ func (l *lexer) Lex(lval *yySymType) int {
 
 		tkn, ok := keywords[tid]
 		if ok {
+			if (tkn == VIEW || tkn == TRIGGER) && !l.r.recording {
+				l.startRecording()
+			}
+
 			lval.keyword = w
 			return tkn
 		}
@@ -367,20 +525,18 @@ func (l *lexer) Lex(lval *yySymType) int {
 	}
 
 	if isDoubleQuote(ch) {
-		tail, err := l.readWord()
+		// quoted identifiers may include any character
+		id, err := l.readQuotedIdentifier()
 		if err != nil {
 			lval.err = err
 			return ERROR
 		}
 
-		if !isDoubleQuote(l.r.nextChar) {
-			lval.err = fmt.Errorf("double quote expected")
-			return ERROR
+		if !l.caseSensitiveIds {
+			id = strings.ToLower(id)
 		}
 
-		l.r.ReadByte() // consume ending quote
-
-		lval.id = strings.ToLower(tail)
+		lval.id = id
 		return IDENTIFIER
 	}
 
@@ -572,6 +728,40 @@ func (l *lexer) Lex(lval *yySymType) int {
 	return int(ch)
 }
 
+// startRecording keeps a copy of the input read from now on, so that the
+// source text of a view or trigger definition can be stored as is
+func (l *lexer) startRecording() {
+	l.r.recording = true
+	l.r.recorded = nil
+	l.tokenOffsets = nil
+}
+
+func (l *lexer) stopRecording() {
+	l.r.recording = false
+	l.r.recorded = nil
+	l.tokenOffsets = nil
+}
+
+func (l *lexer) recordTokenOffset(offset int) {
+	if l.r.recording {
+		l.tokenOffsets = append(l.tokenOffsets, offset)
+	}
+}
+
+// recordedText returns the input recorded starting at the i-th token.
+// The lookahead token, when already read by the parser, is not included.
+func (l *lexer) recordedText(i int, lookahead bool) string {
+	if i >= len(l.tokenOffsets) {
+		return ""
+	}
+
+	end := len(l.r.recorded)
+	if lookahead {
+		end = l.tokenOffsets[len(l.tokenOffsets)-1]
+	}
+	return strings.TrimSpace(string(l.r.recorded[l.tokenOffsets[i]:end]))
+}
+
 func (l *lexer) Error(err string) {
 	l.err = fmt.Errorf("%s at position %d", err, l.r.ReadCount())
 }
@@ -611,6 +801,67 @@ func (l *lexer) readString() (string, error) {
 	return b.String(), nil
 }
 
+func (l *lexer) readQuotedIdentifier() (string, error) {
+	var b bytes.Buffer
+
+	for {
+		ch, err := l.r.ReadByte()
+		if errors.Is(err, io.EOF) {
+			return "", fmt.Errorf("double quote expected")
+		}
+		if err != nil {
+			return "", err
+		}
+
+		if isDoubleQuote(ch) {
+			nextCh, err := l.r.NextByte()
+			if err != nil || !isDoubleQuote(nextCh) {
+				break // identifier completely read
+			}
+
+			l.r.ReadByte() // consume escaped double quote
+		}
+
+		b.WriteByte(ch)
+	}
+
+	if b.Len() == 0 {
+		return "", fmt.Errorf("zero-length quoted identifier")
+	}
+
+	return b.String(), nil
+}
+
+// quoteIdentifier returns the identifier as it must be written
+// to be parsed into the same identifier
+func quoteIdentifier(id string) string {
+	if isPlainIdentifier(id) {
+		return id
+	}
+	return `"` + strings.ReplaceAll(id, `"`, `""`) + `"`
+}
+
+func isPlainIdentifier(id string) bool {
+	if id == "" || !isLetter(id[0]) {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		ch := id[i]
+
+		if 'A' <= ch && ch <= 'Z' {
+			return false
+		}
+
+		if !isLetter(ch) && !isNumber(ch) {
+			return false
+		}
+	}
+
+	_, isKeyword := keywords[strings.ToUpper(id)]
+	return !isKeyword
+}
+
 func (l *lexer) readComparison() (string, error) {
 	return l.readWhile(func(ch byte) bool {
 		return isComparison(ch)
@@ -216,6 +216,20 @@ func TestCreateTableStmt(t *testing.T) {
 				}},
 			expectedError: nil,
 		},
+		{
+			input: "CREATE TABLE table1 (id INTEGER, point POINT, PRIMARY KEY id)",
+			expectedOutput: []SQLStmt{
+				&CreateTableStmt{
+					table:       "table1",
+					ifNotExists: false,
+					colsSpec: []*ColSpec{
+						{colName: "id", colType: IntegerType},
+						{colName: "point", colType: PointType},
+					},
+					pkColNames: []string{"id"},
+				}},
+			expectedError: nil,
+		},
 		{
 			input: "CREATE TABLE table1 (id INTEGER AUTO_INCREMENT, PRIMARY KEY id)",
 			expectedOutput: []SQLStmt{
@@ -238,6 +252,27 @@ func TestCreateTableStmt(t *testing.T) {
 				}},
 			expectedError: nil,
 		},
+		{
+			input: "CREATE TABLE \"My Table\" (\"Id\" INTEGER, \"say \"\"hi\"\"\" VARCHAR, PRIMARY KEY \"Id\")",
+			expectedOutput: []SQLStmt{
+				&CreateTableStmt{
+					table:       "my table",
+					ifNotExists: false,
+					colsSpec:    []*ColSpec{{colName: "id", colType: IntegerType}, {colName: "say \"hi\"", colType: VarcharType}},
+					pkColNames:  []string{"id"},
+				}},
+			expectedError: nil,
+		},
+		{
+			input:          "CREATE TABLE \"\" (id INTEGER, PRIMARY KEY id)",
+			expectedOutput: nil,
+			expectedError:  errors.New("syntax error: unexpected ERROR at position 15"),
+		},
+		{
+			input:          "CREATE TABLE \"table1 (id INTEGER, PRIMARY KEY id)",
+			expectedOutput: nil,
+			expectedError:  errors.New("syntax error: unexpected ERROR at position 50"),
+		},
 		{
 			input: "CREATE TABLE xtable1 (xid INTEGER, PRIMARY KEY xid)",
 			expectedOutput: []SQLStmt{
@@ -340,6 +375,15 @@ func TestCreateTableStmt(t *testing.T) {
 				}},
 			expectedError: nil,
 		},
+		{
+			input: "DROP TABLE IF EXISTS table1",
+			expectedOutput: []SQLStmt{
+				&DropTableStmt{
+					table:    "table1",
+					ifExists: true,
+				}},
+			expectedError: nil,
+		},
 	}
 
 	for i, tc := range testCases {
@@ -352,6 +396,27 @@ func TestCreateTableStmt(t *testing.T) {
 	}
 }
 
+func TestParseCaseSensitiveIdentifiers(t *testing.T) {
+	sql := `CREATE TABLE "My Table" ("Id" INTEGER, MyCol VARCHAR, PRIMARY KEY "Id")`
+
+	// quoted identifiers are folded to lower case by default, as unquoted ones
+	stmts, err := ParseSQLString(sql)
+	require.NoError(t, err)
+	require.Equal(t, []SQLStmt{&CreateTableStmt{
+		table:      "my table",
+		colsSpec:   []*ColSpec{{colName: "id", colType: IntegerType}, {colName: "mycol", colType: VarcharType}},
+		pkColNames: []string{"id"},
+	}}, stmts)
+
+	stmts, err = ParseSQLStringWithOptions(sql, DefaultParseOptions().WithCaseSensitiveIdentifiers(true))
+	require.NoError(t, err)
+	require.Equal(t, []SQLStmt{&CreateTableStmt{
+		table:      "My Table",
+		colsSpec:   []*ColSpec{{colName: "Id", colType: IntegerType}, {colName: "mycol", colType: VarcharType}},
+		pkColNames: []string{"Id"},
+	}}, stmts)
+}
+
 func TestCreateIndexStmt(t *testing.T) {
 	testCases := []struct {
 		input          string
@@ -371,7 +436,7 @@ func TestCreateIndexStmt(t *testing.T) {
 		{
 			input:          "CREATE INDEX ON \"table(\"primary\")",
 			expectedOutput: []SQLStmt{&CreateIndexStmt{table: "table", cols: []string{"primary"}}},
-			expectedError:  errors.New("syntax error: unexpected ERROR at position 22"),
+			expectedError:  errors.New("syntax error: unexpected PRIMARY, expecting '(' at position 31"),
 		},
 		{
 			input:          "CREATE INDEX IF NOT EXISTS ON table1(id)",
@@ -388,6 +453,21 @@ func TestCreateIndexStmt(t *testing.T) {
 			expectedOutput: []SQLStmt{&CreateIndexStmt{unique: true, table: "table1", cols: []string{"id", "title"}}},
 			expectedError:  nil,
 		},
+		{
+			input:          "CREATE UNIQUE INDEX ON table1(id, title) NULLS NOT DISTINCT",
+			expectedOutput: []SQLStmt{&CreateIndexStmt{unique: true, nullsNotDistinct: true, table: "table1", cols: []string{"id", "title"}}},
+			expectedError:  nil,
+		},
+		{
+			input:          "CREATE UNIQUE INDEX ON table1(title) NULLS DISTINCT",
+			expectedOutput: []SQLStmt{&CreateIndexStmt{unique: true, table: "table1", cols: []string{"title"}}},
+			expectedError:  nil,
+		},
+		{
+			input:          "CREATE INDEX ON table1(title) NULLS NOT DISTINCT",
+			expectedOutput: nil,
+			expectedError:  errors.New("syntax error: unexpected NULLS at position 35"),
+		},
 		{
 			input: "DROP INDEX ON table1(id, title)",
 			expectedOutput: []SQLStmt{
@@ -397,6 +477,21 @@ func TestCreateIndexStmt(t *testing.T) {
 				}},
 			expectedError: nil,
 		},
+		{
+			input:          "CREATE FULLTEXT INDEX IF NOT EXISTS ON docs(body)",
+			expectedOutput: []SQLStmt{&CreateIndexStmt{fullText: true, ifNotExists: true, table: "docs", cols: []string{"body"}}},
+			expectedError:  nil,
+		},
+		{
+			input:          "CREATE FULLTEXT INDEX ON docs(title, body)",
+			expectedOutput: nil,
+			expectedError:  errors.New("syntax error: unexpected ',', expecting ')' at position 36"),
+		},
+		{
+			input:          "DROP FULLTEXT INDEX ON docs(body)",
+			expectedOutput: []SQLStmt{&DropIndexStmt{fullText: true, table: "docs", cols: []string{"body"}}},
+			expectedError:  nil,
+		},
 	}
 
 	for i, tc := range testCases {
@@ -409,6 +504,51 @@ func TestCreateIndexStmt(t *testing.T) {
 	}
 }
 
+func TestCreateViewStmt(t *testing.T) {
+	testCases := []struct {
+		input       string
+		view        string
+		ifNotExists bool
+		sql         string
+	}{
+		{
+			input: "CREATE VIEW active_users AS SELECT * FROM users WHERE active = true",
+			view:  "active_users",
+			sql:   "SELECT * FROM users WHERE active = true",
+		},
+		{
+			input:       "create view if not exists v as\n\tselect id, name as n from users where name <> 'a;b' order by id limit 10;",
+			view:        "v",
+			ifNotExists: true,
+			sql:         "select id, name as n from users where name <> 'a;b' order by id limit 10",
+		},
+		{
+			input: "CREATE VIEW view AS SELECT id FROM t1 ; SELECT * FROM view",
+			view:  "view",
+			sql:   "SELECT id FROM t1",
+		},
+	}
+
+	for i, tc := range testCases {
+		res, err := ParseSQLString(tc.input)
+		require.NoError(t, err, fmt.Sprintf("failed on iteration %d", i))
+
+		stmt, ok := res[0].(*CreateViewStmt)
+		require.True(t, ok, fmt.Sprintf("failed on iteration %d", i))
+		require.Equal(t, tc.view, stmt.view, fmt.Sprintf("failed on iteration %d", i))
+		require.Equal(t, tc.ifNotExists, stmt.ifNotExists, fmt.Sprintf("failed on iteration %d", i))
+		require.Equal(t, tc.sql, stmt.sql, fmt.Sprintf("failed on iteration %d", i))
+		require.NotNil(t, stmt.query)
+	}
+
+	res, err := ParseSQLString("DROP VIEW active_users")
+	require.NoError(t, err)
+	require.Equal(t, []SQLStmt{&DropViewStmt{view: "active_users"}}, res)
+
+	_, err = ParseSQLString("CREATE VIEW v AS SELECT id FROM t1 UNION SELECT id FROM t2")
+	require.Error(t, err)
+}
+
 func TestAlterTable(t *testing.T) {
 	testCases := []struct {
 		input          string
@@ -932,6 +1072,45 @@ func TestSelectStmt(t *testing.T) {
 				},
 			},
 		},
+		{
+			input: "SELECT @x + 1 AS y WHERE @x > 0 LIMIT 1 OFFSET 0",
+			expectedOutput: []SQLStmt{
+				&SelectStmt{
+					targets: []TargetEntry{
+						{Exp: &NumExp{op: ADDOP, left: &Param{id: "x"}, right: &Integer{1}}, As: "y"},
+					},
+					ds:     &valuesDataSource{rows: []*RowSpec{{}}},
+					where:  &CmpBoolExp{op: GT, left: &Param{id: "x"}, right: &Integer{0}},
+					limit:  &Integer{val: 1},
+					offset: &Integer{val: 0},
+				},
+			},
+		},
+		{
+			input: "VALUES (1, 'a'), (2, 'b')",
+			expectedOutput: []SQLStmt{
+				&SelectStmt{
+					ds: &valuesDataSource{inferTypes: true, rows: []*RowSpec{
+						{Values: []ValueExp{&Integer{1}, &Varchar{"a"}}},
+						{Values: []ValueExp{&Integer{2}, &Varchar{"b"}}},
+					}},
+				},
+			},
+		},
+		{
+			input: "SELECT id FROM (VALUES (1)) AS t(id)",
+			expectedOutput: []SQLStmt{
+				&SelectStmt{
+					targets: []TargetEntry{{Exp: &ColSelector{col: "id"}}},
+					ds: &valuesDataSource{
+						inferTypes: true,
+						rows:       []*RowSpec{{Values: []ValueExp{&Integer{1}}}},
+						as:         "t",
+						colNames:   []string{"id"},
+					},
+				},
+			},
+		},
 		{
 			input: "SELECT id, title FROM table1",
 			expectedOutput: []SQLStmt{
@@ -958,6 +1137,17 @@ func TestSelectStmt(t *testing.T) {
 				}},
 			expectedError: nil,
 		},
+		{
+			input: "SELECT column_name FROM information_schema.columns",
+			expectedOutput: []SQLStmt{
+				&SelectStmt{
+					targets: []TargetEntry{
+						{Exp: &ColSelector{col: "column_name"}},
+					},
+					ds: &tableRef{table: "information_schema.columns"},
+				}},
+			expectedError: nil,
+		},
 		{
 			input: "SELECT t1.id, title FROM table1 t1",
 			expectedOutput: []SQLStmt{
@@ -1207,6 +1397,86 @@ func TestSelectStmt(t *testing.T) {
 				}},
 			expectedError: nil,
 		},
+		{
+			input: "SELECT * FROM table1 LEFT JOIN table2 USING (id, name)",
+			expectedOutput: []SQLStmt{
+				&SelectStmt{
+					ds: &tableRef{table: "table1"},
+					joins: []*JoinSpec{
+						{
+							joinType: LeftJoin,
+							ds:       &tableRef{table: "table2"},
+							using:    []string{"id", "name"},
+						},
+					},
+				}},
+			expectedError: nil,
+		},
+		{
+			input: "SELECT * FROM table1 NATURAL JOIN table2 NATURAL LEFT JOIN table3",
+			expectedOutput: []SQLStmt{
+				&SelectStmt{
+					ds: &tableRef{table: "table1"},
+					joins: []*JoinSpec{
+						{
+							joinType: InnerJoin,
+							ds:       &tableRef{table: "table2"},
+							natural:  true,
+						},
+						{
+							joinType: LeftJoin,
+							ds:       &tableRef{table: "table3"},
+							natural:  true,
+						},
+					},
+				}},
+			expectedError: nil,
+		},
+		{
+			input: "SELECT * FROM table1 t, LATERAL (SELECT name FROM table2 WHERE table2.id = t.id) AS s LEFT JOIN LATERAL (SELECT 1 FROM table3) r ON true",
+			expectedOutput: []SQLStmt{
+				&SelectStmt{
+					ds: &tableRef{table: "table1", as: "t"},
+					joins: []*JoinSpec{
+						{
+							joinType: InnerJoin,
+							ds: &SelectStmt{
+								targets: []TargetEntry{{Exp: &ColSelector{col: "name"}}},
+								ds:      &tableRef{table: "table2"},
+								where: &CmpBoolExp{
+									op:    EQ,
+									left:  &ColSelector{table: "table2", col: "id"},
+									right: &ColSelector{table: "t", col: "id"},
+								},
+								as: "s",
+							},
+							cond:    &Bool{val: true},
+							lateral: true,
+						},
+						{
+							joinType: LeftJoin,
+							ds: &SelectStmt{
+								targets: []TargetEntry{{Exp: &Integer{val: 1}}},
+								ds:      &tableRef{table: "table3"},
+								as:      "r",
+							},
+							cond:    &Bool{val: true},
+							lateral: true,
+						},
+					},
+				}},
+			expectedError: nil,
+		},
+		{
+			input:          "SELECT * FROM table1, table2",
+			expectedOutput: nil,
+			expectedError:  errors.New("syntax error: unexpected IDENTIFIER, expecting LATERAL at position 28"),
+		},
+		{
+			input:          "SELECT * FROM table1 NATURAL JOIN table2 ON table1.id = table2.id",
+			expectedOutput: nil,
+			expectedError:  errors.New("syntax error: unexpected ON at position 43"),
+		},
 		{
 			input: "SELECT id, title FROM (SELECT col1 AS id, col2 AS title FROM table2 LIMIT 100 OFFSET 1) LIMIT 10",
 			expectedOutput: []SQLStmt{
@@ -1315,6 +1585,89 @@ func TestSelectStmt(t *testing.T) {
 				}},
 			expectedError: nil,
 		},
+		{
+			input: "SELECT id FROM table1 ORDER BY score DESC OFFSET 2 FETCH FIRST 3 ROWS WITH TIES",
+			expectedOutput: []SQLStmt{
+				&SelectStmt{
+					targets: []TargetEntry{
+						{Exp: &ColSelector{col: "id"}},
+					},
+					ds: &tableRef{table: "table1"},
+					orderBy: []*OrdExp{
+						{exp: &ColSelector{col: "score"}, descOrder: true},
+					},
+					limit:    &Integer{val: 3},
+					offset:   &Integer{val: 2},
+					withTies: true,
+				}},
+			expectedError: nil,
+		},
+		{
+			input: "SELECT id FROM table1 ORDER BY score DESC LIMIT 3 WITH TIES",
+			expectedOutput: []SQLStmt{
+				&SelectStmt{
+					targets: []TargetEntry{
+						{Exp: &ColSelector{col: "id"}},
+					},
+					ds: &tableRef{table: "table1"},
+					orderBy: []*OrdExp{
+						{exp: &ColSelector{col: "score"}, descOrder: true},
+					},
+					limit:    &Integer{val: 3},
+					withTies: true,
+				}},
+			expectedError: nil,
+		},
+		{
+			input: "SELECT id FROM table1 ORDER BY score DESC LIMIT 3 OFFSET 2 WITH TIES",
+			expectedOutput: []SQLStmt{
+				&SelectStmt{
+					targets: []TargetEntry{
+						{Exp: &ColSelector{col: "id"}},
+					},
+					ds: &tableRef{table: "table1"},
+					orderBy: []*OrdExp{
+						{exp: &ColSelector{col: "score"}, descOrder: true},
+					},
+					limit:    &Integer{val: 3},
+					offset:   &Integer{val: 2},
+					withTies: true,
+				}},
+			expectedError: nil,
+		},
+		{
+			input: "SELECT id FROM table1 ORDER BY score FETCH NEXT ROW ONLY",
+			expectedOutput: []SQLStmt{
+				&SelectStmt{
+					targets: []TargetEntry{
+						{Exp: &ColSelector{col: "id"}},
+					},
+					ds: &tableRef{table: "table1"},
+					orderBy: []*OrdExp{
+						{exp: &ColSelector{col: "score"}},
+					},
+					limit: &Integer{val: 1},
+				}},
+			expectedError: nil,
+		},
+		{
+			input: "SELECT id FROM table1 WHERE id > 1 LIMIT 2 FOR SHARE",
+			expectedOutput: []SQLStmt{
+				&SelectStmt{
+					targets: []TargetEntry{
+						{Exp: &ColSelector{col: "id"}},
+					},
+					ds: &tableRef{table: "table1"},
+					where: &CmpBoolExp{
+						op:    GT,
+						left:  &ColSelector{col: "id"},
+						right: &Integer{val: 1},
+					},
+					limit:    &Integer{val: 2},
+					forShare: true,
+				}},
+			expectedError: nil,
+		},
 	}
 
 	for i, tc := range testCases {
@@ -1325,6 +1678,16 @@ func TestSelectStmt(t *testing.T) {
 			require.Equal(t, tc.expectedOutput, res, fmt.Sprintf("failed on iteration %d", i))
 		}
 	}
+
+	t.Run("limit and fetch can not be combined", func(t *testing.T) {
+		_, err := ParseSQLString("SELECT id FROM table1 ORDER BY id LIMIT 2 FETCH FIRST 3 ROWS ONLY")
+		require.ErrorContains(t, err, "LIMIT and FETCH can not be combined")
+	})
+
+	t.Run("with ties requires a limit", func(t *testing.T) {
+		_, err := ParseSQLString("SELECT id FROM table1 ORDER BY id OFFSET 2 WITH TIES")
+		require.ErrorContains(t, err, "WITH TIES requires LIMIT or FETCH")
+	})
 }
 
 func TestSelectUnionStmt(t *testing.T) {
@@ -1864,6 +2227,58 @@ func TestParseExp(t *testing.T) {
 				},
 			},
 		},
+		{
+			input: "SELECT id FROM docs WHERE body MATCH 'quick brown' AND id > 1",
+			expectedOutput: []SQLStmt{
+				&SelectStmt{
+					ds:      &tableRef{table: "docs"},
+					targets: []TargetEntry{{Exp: &ColSelector{col: "id"}}},
+					where: &BinBoolExp{
+						op:    And,
+						left:  NewMatchBoolExp(NewColSelector("", "body"), NewVarchar("quick brown")),
+						right: &CmpBoolExp{op: GT, left: &ColSelector{col: "id"}, right: &Integer{val: 1}},
+					},
+				},
+			},
+		},
+		{
+			input: "SELECT id FROM t WHERE a IS DISTINCT FROM b OR a IS NOT DISTINCT FROM NULL",
+			expectedOutput: []SQLStmt{
+				&SelectStmt{
+					ds:      &tableRef{table: "t"},
+					targets: []TargetEntry{{Exp: &ColSelector{col: "id"}}},
+					where: &BinBoolExp{
+						op:    Or,
+						left:  NewDistinctBoolExp(NewColSelector("", "a"), NewColSelector("", "b"), false),
+						right: NewDistinctBoolExp(NewColSelector("", "a"), &NullValue{t: AnyType}, true),
+					},
+				},
+			},
+		},
+		{
+			input:         "SELECT id FROM t WHERE a IS DISTINCT b",
+			expectedError: errors.New("syntax error: unexpected IDENTIFIER, expecting FROM at position 38"),
+		},
+		{
+			input: "SELECT POINT(41.9, -12.5) FROM places WHERE loc WITHIN BOX(-10, 170, 10.5, -170)",
+			expectedOutput: []SQLStmt{
+				&SelectStmt{
+					ds:      &tableRef{table: "places"},
+					targets: []TargetEntry{{Exp: NewPointExp(&Float64{val: 41.9}, &NumExp{left: &Integer{val: 0}, op: SUBSOP, right: &Float64{val: 12.5}})}},
+					where: NewWithinBoxExp(
+						NewColSelector("", "loc"),
+						&Integer{val: -10},
+						&Integer{val: 170},
+						&Float64{val: 10.5},
+						&Integer{val: -170},
+					),
+				},
+			},
+		},
+		{
+			input:         "SELECT id FROM places WHERE loc WITHIN BOX(1, 2, 3)",
+			expectedError: errors.New("syntax error: unexpected ')', expecting ',' at position 51"),
+		},
 		{
 			input: "SELECT price FROM items WHERE price BETWEEN 1.5 and 3.9",
 			expectedOutput: []SQLStmt{
@@ -2461,3 +2876,74 @@ func TestParseUnreservedKeywords(t *testing.T) {
 		require.Equal(t, tc.expectedOutput, stmt)
 	}
 }
+
+func TestParseInSafeMode(t *testing.T) {
+	opts := DefaultParseOptions().WithSafeMode(true)
+
+	_, err := ParseSQLStringWithOptions("SELECT 1", nil)
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	t.Run("queries are allowed", func(t *testing.T) {
+		for _, sql := range []string{
+			"SELECT id, name FROM users WHERE id > 10 ORDER BY name",
+			"SELECT * FROM users; SELECT COUNT(*) FROM orders GROUP BY user_id",
+			"SELECT id FROM users UNION SELECT user_id FROM orders",
+			"SELECT * FROM (SELECT * FROM users WHERE name = 'DROP TABLE users') AS u",
+			"SELECT * FROM users WHERE id IN (SELECT user_id FROM orders WHERE total > 100)",
+			"SELECT * FROM users WHERE EXISTS (SELECT 1 FROM orders WHERE orders.user_id = users.id)",
+			"SHOW TABLES",
+		} {
+			t.Run(sql, func(t *testing.T) {
+				stmts, err := ParseSQLStringWithOptions(sql, opts)
+				require.NoError(t, err)
+
+				expected, err := ParseSQLString(sql)
+				require.NoError(t, err)
+				require.Equal(t, expected, stmts)
+			})
+		}
+	})
+
+	t.Run("any other statement is rejected", func(t *testing.T) {
+		for _, sql := range []string{
+			"INSERT INTO users (id) VALUES (1)",
+			"UPSERT INTO users (id) VALUES (1)",
+			"INSERT INTO users (id) SELECT id FROM old_users",
+			"UPDATE users SET name = 'a' WHERE id = 1",
+			"DELETE FROM users WHERE id = 1",
+			"CREATE TABLE t (id INTEGER, PRIMARY KEY id)",
+			"CREATE INDEX ON users (name)",
+			"CREATE VIEW v AS SELECT * FROM users",
+			"ALTER TABLE users ADD COLUMN age INTEGER",
+			"DROP TABLE users",
+			"COMMENT ON TABLE users IS 'users'",
+			"BEGIN TRANSACTION",
+			"COMMIT",
+			"USE DATABASE db1",
+			"CREATE USER u WITH PASSWORD 'pwd' READ",
+			"GRANT ALL PRIVILEGES ON DATABASE db1 TO USER u",
+			"SELECT * FROM users; DELETE FROM users",
+		} {
+			t.Run(sql, func(t *testing.T) {
+				_, err := ParseSQLString(sql)
+				require.NoError(t, err)
+
+				stmts, err := ParseSQLStringWithOptions(sql, opts)
+				require.ErrorIs(t, err, ErrStatementNotAllowed)
+				require.Nil(t, stmts)
+			})
+		}
+	})
+
+	t.Run("statements are allowed when not in safe mode", func(t *testing.T) {
+		stmts, err := ParseSQLStringWithOptions("DELETE FROM users", DefaultParseOptions())
+		require.NoError(t, err)
+		require.Len(t, stmts, 1)
+	})
+
+	t.Run("syntax errors are reported as such", func(t *testing.T) {
+		_, err := ParseSQLStringWithOptions("SELECT FROM", opts)
+		require.Error(t, err)
+		require.NotErrorIs(t, err, ErrStatementNotAllowed)
+	})
+}
@@ -0,0 +1,288 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+)
+
+const (
+	PERCENTILE_CONT   AggregateFn = "PERCENTILE_CONT"
+	PERCENTILE_DISC   AggregateFn = "PERCENTILE_DISC"
+	APPROX_PERCENTILE AggregateFn = "APPROX_PERCENTILE"
+)
+
+// tDigestCompression bounds the number of centroids kept by APPROX_PERCENTILE,
+// the higher the compression the more accurate the estimated percentiles are
+const tDigestCompression = 100
+
+// newPercentileSelector returns the aggregation of the given percentile of the values of col.
+// The fraction is part of the encoded selector, so that aggregations of different
+// percentiles over the same column can be used within the same statement.
+func newPercentileSelector(aggFn AggregateFn, fraction float64, col *ColSelector, descOrder bool) (*AggColSelector, error) {
+	if fraction < 0 || fraction > 1 || math.IsNaN(fraction) {
+		return nil, fmt.Errorf("%w: percentile must be between 0 and 1", ErrIllegalArguments)
+	}
+
+	if descOrder {
+		fraction = 1 - fraction
+	}
+
+	fn := fmt.Sprintf("%s[%s]", aggFn, strconv.FormatFloat(fraction, 'g', -1, 64))
+
+	var spec AggregateFunc
+
+	switch aggFn {
+	case PERCENTILE_CONT, PERCENTILE_DISC:
+		spec = exactPercentile(fraction, aggFn == PERCENTILE_CONT)
+	case APPROX_PERCENTILE:
+		spec = approxPercentile(fraction)
+	default:
+		return nil, fmt.Errorf("%w: unknown percentile aggregation '%s'", ErrIllegalArguments, aggFn)
+	}
+
+	return &AggColSelector{
+		aggFn:     fn,
+		table:     col.table,
+		col:       col.col,
+		aggregate: &userAggregate{name: fn, spec: spec},
+	}, nil
+}
+
+func numericValue(val TypedValue) (float64, error) {
+	switch v := val.RawValue().(type) {
+	case int64:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	}
+	return 0, fmt.Errorf("%w: percentiles can only be calculated over %s or %s values", ErrInvalidTypes, IntegerType, Float64Type)
+}
+
+// exactPercentile buffers all the values of the group. When continuous, the percentile is
+// interpolated between the closest values, otherwise the first value whose position
+// in the sorted group is at or above the percentile is returned.
+func exactPercentile(fraction float64, continuous bool) AggregateFunc {
+	returnType := AnyType // same type as the aggregated column
+	if continuous {
+		returnType = Float64Type
+	}
+
+	return AggregateFunc{
+		ArgType:    AnyType,
+		ReturnType: returnType,
+		Init: func() interface{} {
+			return []TypedValue(nil)
+		},
+		Accumulate: func(state interface{}, val TypedValue) (interface{}, error) {
+			if _, err := numericValue(val); err != nil {
+				return nil, err
+			}
+			return append(state.([]TypedValue), val), nil
+		},
+		Merge: func(state, other interface{}) (interface{}, error) {
+			return append(state.([]TypedValue), other.([]TypedValue)...), nil
+		},
+		Finalize: func(state interface{}) (TypedValue, error) {
+			vals := state.([]TypedValue)
+			if len(vals) == 0 {
+				return nil, nil
+			}
+
+			var err error
+
+			sort.SliceStable(vals, func(i, j int) bool {
+				cmp, cerr := vals[i].Compare(vals[j])
+				if cerr != nil {
+					err = cerr
+				}
+				return cmp < 0
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			if !continuous {
+				i := int(math.Ceil(fraction*float64(len(vals)))) - 1
+				return vals[max(i, 0)], nil
+			}
+
+			pos := fraction * float64(len(vals)-1)
+			lo, hi := int(math.Floor(pos)), int(math.Ceil(pos))
+
+			loVal, err := numericValue(vals[lo])
+			if err != nil {
+				return nil, err
+			}
+
+			hiVal, err := numericValue(vals[hi])
+			if err != nil {
+				return nil, err
+			}
+
+			return &Float64{val: loVal + (hiVal-loVal)*(pos-float64(lo))}, nil
+		},
+	}
+}
+
+func approxPercentile(fraction float64) AggregateFunc {
+	return AggregateFunc{
+		ArgType:    AnyType,
+		ReturnType: Float64Type,
+		Init: func() interface{} {
+			return newTDigest(tDigestCompression)
+		},
+		Accumulate: func(state interface{}, val TypedValue) (interface{}, error) {
+			v, err := numericValue(val)
+			if err != nil {
+				return nil, err
+			}
+
+			td := state.(*tDigest)
+			td.add(v, 1)
+
+			return td, nil
+		},
+		Merge: func(state, other interface{}) (interface{}, error) {
+			td := state.(*tDigest)
+			td.merge(other.(*tDigest))
+			return td, nil
+		},
+		Finalize: func(state interface{}) (TypedValue, error) {
+			td := state.(*tDigest)
+			if td.count == 0 {
+				return nil, nil
+			}
+			return &Float64{val: td.quantile(fraction)}, nil
+		},
+	}
+}
+
+type centroid struct {
+	mean  float64
+	count float64
+}
+
+// tDigest is a merging t-digest, a sketch which approximates the distribution
+// of the values added to it by clustering them into a bounded number of centroids.
+// Centroids are kept smaller at the tails, thus extreme percentiles are more accurate.
+type tDigest struct {
+	compression float64
+
+	centroids []centroid // sorted by mean
+	unmerged  []centroid
+
+	count    float64
+	min, max float64
+}
+
+func newTDigest(compression float64) *tDigest {
+	return &tDigest{
+		compression: compression,
+		min:         math.Inf(1),
+		max:         math.Inf(-1),
+	}
+}
+
+func (td *tDigest) add(val, count float64) {
+	td.unmerged = append(td.unmerged, centroid{mean: val, count: count})
+	td.count += count
+	td.min = math.Min(td.min, val)
+	td.max = math.Max(td.max, val)
+
+	if len(td.unmerged) >= 5*int(td.compression) {
+		td.compress()
+	}
+}
+
+func (td *tDigest) merge(other *tDigest) {
+	other.compress()
+
+	for _, c := range other.centroids {
+		td.add(c.mean, c.count)
+	}
+
+	td.min = math.Min(td.min, other.min)
+	td.max = math.Max(td.max, other.max)
+}
+
+func (td *tDigest) compress() {
+	if len(td.unmerged) == 0 {
+		return
+	}
+
+	all := append(td.centroids, td.unmerged...)
+	sort.Slice(all, func(i, j int) bool { return all[i].mean < all[j].mean })
+
+	merged := make([]centroid, 0, len(td.centroids)+1)
+	curr := all[0]
+	var soFar float64
+
+	for _, c := range all[1:] {
+		proposed := curr.count + c.count
+
+		q0 := soFar / td.count
+		q2 := (soFar + proposed) / td.count
+
+		// centroids are bounded to 4*n*q*(1-q)/compression values
+		if proposed <= 4*td.count*math.Min(q0*(1-q0), q2*(1-q2))/td.compression {
+			curr.mean += (c.mean - curr.mean) * c.count / proposed
+			curr.count = proposed
+			continue
+		}
+
+		merged = append(merged, curr)
+		soFar += curr.count
+		curr = c
+	}
+
+	td.centroids = append(merged, curr)
+	td.unmerged = td.unmerged[:0]
+}
+
+// quantile interpolates between the centers of the centroids surrounding the quantile,
+// the observed minimum and maximum values are used at the edges
+func (td *tDigest) quantile(q float64) float64 {
+	td.compress()
+
+	target := q * td.count
+
+	prevPos, prevMean := 0.0, td.min
+	var cumulative float64
+
+	for _, c := range td.centroids {
+		pos := cumulative + c.count/2
+
+		if target < pos {
+			return interpolate(target, prevPos, pos, prevMean, c.mean)
+		}
+
+		prevPos, prevMean = pos, c.mean
+		cumulative += c.count
+	}
+	return interpolate(target, prevPos, td.count, prevMean, td.max)
+}
+
+func interpolate(x, x0, x1, y0, y1 float64) float64 {
+	if x1 <= x0 {
+		return y1
+	}
+	return y0 + (y1-y0)*(x-x0)/(x1-x0)
+}
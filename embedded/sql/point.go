@@ -0,0 +1,461 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// GeoPoint is a location given by its latitude and longitude in degrees
+type GeoPoint struct {
+	Lat float64
+	Lon float64
+}
+
+func (p GeoPoint) validate() error {
+	if math.IsNaN(p.Lat) || p.Lat < -90 || p.Lat > 90 {
+		return fmt.Errorf("%w: latitude %v is out of range [-90, 90]", ErrInvalidValue, p.Lat)
+	}
+
+	if math.IsNaN(p.Lon) || p.Lon < -180 || p.Lon > 180 {
+		return fmt.Errorf("%w: longitude %v is out of range [-180, 180]", ErrInvalidValue, p.Lon)
+	}
+	return nil
+}
+
+type Point struct {
+	val GeoPoint
+}
+
+func NewPoint(lat, lon float64) (*Point, error) {
+	p := GeoPoint{Lat: lat, Lon: lon}
+
+	err := p.validate()
+	if err != nil {
+		return nil, err
+	}
+	return &Point{val: p}, nil
+}
+
+// ParsePoint parses a point formatted as its literal, e.g. POINT(41.9, 12.5)
+func ParsePoint(s string) (*Point, error) {
+	coords, ok := strings.CutPrefix(strings.ToUpper(strings.TrimSpace(s)), "POINT(")
+	if ok {
+		coords, ok = strings.CutSuffix(coords, ")")
+	}
+
+	lat, lon, found := strings.Cut(coords, ",")
+	if !ok || !found {
+		return nil, fmt.Errorf("%w: invalid point '%s'", ErrInvalidValue, s)
+	}
+
+	latVal, err := strconv.ParseFloat(strings.TrimSpace(lat), 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid point '%s'", ErrInvalidValue, s)
+	}
+
+	lonVal, err := strconv.ParseFloat(strings.TrimSpace(lon), 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid point '%s'", ErrInvalidValue, s)
+	}
+
+	return NewPoint(latVal, lonVal)
+}
+
+func (v *Point) Type() SQLValueType {
+	return PointType
+}
+
+func (v *Point) IsNull() bool {
+	return false
+}
+
+func (v *Point) String() string {
+	return fmt.Sprintf(
+		"POINT(%s, %s)",
+		strconv.FormatFloat(v.val.Lat, 'f', -1, 64),
+		strconv.FormatFloat(v.val.Lon, 'f', -1, 64),
+	)
+}
+
+func (v *Point) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	return PointType, nil
+}
+
+func (v *Point) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != PointType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, PointType, t)
+	}
+	return nil
+}
+
+func (v *Point) selectors() []Selector {
+	return nil
+}
+
+func (v *Point) substitute(params map[string]interface{}) (ValueExp, error) {
+	return v, nil
+}
+
+func (v *Point) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return v.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (v *Point) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return v, nil
+}
+
+func (v *Point) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	return v
+}
+
+func (v *Point) isConstant() bool {
+	return true
+}
+
+func (v *Point) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
+}
+
+func (v *Point) RawValue() interface{} {
+	return v.val
+}
+
+// Compare orders points by latitude and then by longitude
+func (v *Point) Compare(val TypedValue) (int, error) {
+	if val.IsNull() {
+		return 1, nil
+	}
+
+	if val.Type() != PointType {
+		return 0, ErrNotComparableValues
+	}
+
+	rval := val.RawValue().(GeoPoint)
+
+	if c := compareFloats(v.val.Lat, rval.Lat); c != 0 {
+		return c, nil
+	}
+	return compareFloats(v.val.Lon, rval.Lon), nil
+}
+
+// encodePoint returns the bit representation of both coordinates
+func encodePoint(p GeoPoint) [16]byte {
+	var encv [16]byte
+	binary.BigEndian.PutUint64(encv[:], math.Float64bits(p.Lat))
+	binary.BigEndian.PutUint64(encv[8:], math.Float64bits(p.Lon))
+	return encv
+}
+
+func decodePoint(b []byte) (GeoPoint, error) {
+	if len(b) != 16 {
+		return GeoPoint{}, ErrCorruptedData
+	}
+
+	p := GeoPoint{
+		Lat: math.Float64frombits(binary.BigEndian.Uint64(b)),
+		Lon: math.Float64frombits(binary.BigEndian.Uint64(b[8:])),
+	}
+
+	if p.validate() != nil {
+		return GeoPoint{}, ErrCorruptedData
+	}
+	return p, nil
+}
+
+// PointExp builds a point out of the numeric expressions of its coordinates
+type PointExp struct {
+	lat ValueExp
+	lon ValueExp
+}
+
+func NewPointExp(lat, lon ValueExp) *PointExp {
+	return &PointExp{lat: lat, lon: lon}
+}
+
+func requireNumeric(exp ValueExp, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	t, err := exp.inferType(cols, params, implicitTable)
+	if err != nil {
+		return err
+	}
+
+	if t == AnyType {
+		return exp.requiresType(Float64Type, cols, params, implicitTable)
+	}
+
+	if !IsNumericType(t) {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, t, Float64Type)
+	}
+	return nil
+}
+
+func (p *PointExp) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	for _, coord := range []ValueExp{p.lat, p.lon} {
+		err := requireNumeric(coord, cols, params, implicitTable)
+		if err != nil {
+			return AnyType, err
+		}
+	}
+	return PointType, nil
+}
+
+func (p *PointExp) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != PointType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, PointType, t)
+	}
+
+	_, err := p.inferType(cols, params, implicitTable)
+	return err
+}
+
+func (p *PointExp) substitute(params map[string]interface{}) (ValueExp, error) {
+	lat, err := p.lat.substitute(params)
+	if err != nil {
+		return nil, err
+	}
+
+	lon, err := p.lon.substitute(params)
+	if err != nil {
+		return nil, err
+	}
+	return &PointExp{lat: lat, lon: lon}, nil
+}
+
+func reduceCoordinate(ctx context.Context, tx *SQLTx, exp ValueExp, row *Row, implicitTable string) (float64, bool, error) {
+	v, err := exp.reduceCtx(ctx, tx, row, implicitTable)
+	if err != nil {
+		return 0, false, err
+	}
+
+	if v.IsNull() {
+		return 0, true, nil
+	}
+
+	switch rv := v.RawValue().(type) {
+	case int64:
+		return float64(rv), false, nil
+	case float64:
+		return rv, false, nil
+	}
+	return 0, false, fmt.Errorf("%w: coordinates must be of type %s or %s", ErrInvalidTypes, IntegerType, Float64Type)
+}
+
+func (p *PointExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return p.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (p *PointExp) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	lat, latIsNull, err := reduceCoordinate(ctx, tx, p.lat, row, implicitTable)
+	if err != nil {
+		return nil, err
+	}
+
+	lon, lonIsNull, err := reduceCoordinate(ctx, tx, p.lon, row, implicitTable)
+	if err != nil {
+		return nil, err
+	}
+
+	if latIsNull || lonIsNull {
+		return &NullValue{t: PointType}, nil
+	}
+	return NewPoint(lat, lon)
+}
+
+func (p *PointExp) selectors() []Selector {
+	return append(p.lat.selectors(), p.lon.selectors()...)
+}
+
+func (p *PointExp) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	return &PointExp{
+		lat: p.lat.reduceSelectors(row, implicitTable),
+		lon: p.lon.reduceSelectors(row, implicitTable),
+	}
+}
+
+func (p *PointExp) isConstant() bool {
+	return p.lat.isConstant() && p.lon.isConstant()
+}
+
+func (p *PointExp) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
+}
+
+func (p *PointExp) String() string {
+	return fmt.Sprintf("POINT(%s, %s)", p.lat.String(), p.lon.String())
+}
+
+// WithinBoxExp is satisfied by the points contained in a bounding box, boundaries included.
+// A box whose minimum longitude is greater than its maximum longitude crosses the antimeridian,
+// thus it contains the points whose longitude is either above the minimum or below the maximum.
+// NULL points are not contained in any box.
+type WithinBoxExp struct {
+	val    ValueExp
+	minLat ValueExp
+	minLon ValueExp
+	maxLat ValueExp
+	maxLon ValueExp
+}
+
+func NewWithinBoxExp(val, minLat, minLon, maxLat, maxLon ValueExp) *WithinBoxExp {
+	return &WithinBoxExp{
+		val:    val,
+		minLat: minLat,
+		minLon: minLon,
+		maxLat: maxLat,
+		maxLon: maxLon,
+	}
+}
+
+func (bexp *WithinBoxExp) bounds() []ValueExp {
+	return []ValueExp{bexp.minLat, bexp.minLon, bexp.maxLat, bexp.maxLon}
+}
+
+func (bexp *WithinBoxExp) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	err := bexp.val.requiresType(PointType, cols, params, implicitTable)
+	if err != nil {
+		return AnyType, fmt.Errorf("error in 'WITHIN BOX' clause: %w", err)
+	}
+
+	for _, bound := range bexp.bounds() {
+		err := requireNumeric(bound, cols, params, implicitTable)
+		if err != nil {
+			return AnyType, fmt.Errorf("error in 'WITHIN BOX' clause: %w", err)
+		}
+	}
+	return BooleanType, nil
+}
+
+func (bexp *WithinBoxExp) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != BooleanType {
+		return fmt.Errorf("error using the value of the WITHIN BOX operator as %s: %w", t, ErrInvalidTypes)
+	}
+
+	_, err := bexp.inferType(cols, params, implicitTable)
+	return err
+}
+
+func (bexp *WithinBoxExp) substitute(params map[string]interface{}) (ValueExp, error) {
+	exps := append([]ValueExp{bexp.val}, bexp.bounds()...)
+
+	for i, exp := range exps {
+		sexp, err := exp.substitute(params)
+		if err != nil {
+			return nil, fmt.Errorf("error in 'WITHIN BOX' clause: %w", err)
+		}
+		exps[i] = sexp
+	}
+	return NewWithinBoxExp(exps[0], exps[1], exps[2], exps[3], exps[4]), nil
+}
+
+func (bexp *WithinBoxExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return bexp.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (bexp *WithinBoxExp) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	rval, err := bexp.val.reduceCtx(ctx, tx, row, implicitTable)
+	if err != nil {
+		return nil, fmt.Errorf("error in 'WITHIN BOX' clause: %w", err)
+	}
+
+	var coords [4]float64
+
+	for i, bound := range bexp.bounds() {
+		v, isNull, err := reduceCoordinate(ctx, tx, bound, row, implicitTable)
+		if err != nil {
+			return nil, fmt.Errorf("error in 'WITHIN BOX' clause: %w", err)
+		}
+
+		if isNull {
+			return &Bool{val: false}, nil
+		}
+		coords[i] = v
+	}
+
+	min := GeoPoint{Lat: coords[0], Lon: coords[1]}
+	max := GeoPoint{Lat: coords[2], Lon: coords[3]}
+
+	for _, p := range []GeoPoint{min, max} {
+		if err := p.validate(); err != nil {
+			return nil, fmt.Errorf("error in 'WITHIN BOX' clause: %w", err)
+		}
+	}
+
+	if min.Lat > max.Lat {
+		return nil, fmt.Errorf("%w: minimum latitude of the box is greater than its maximum latitude", ErrIllegalArguments)
+	}
+
+	if rval.IsNull() {
+		return &Bool{val: false}, nil
+	}
+
+	if rval.Type() != PointType {
+		return nil, fmt.Errorf("error in 'WITHIN BOX' clause: %w (expecting %s)", ErrInvalidTypes, PointType)
+	}
+
+	p := rval.RawValue().(GeoPoint)
+
+	within := p.Lat >= min.Lat && p.Lat <= max.Lat
+	if min.Lon <= max.Lon {
+		within = within && p.Lon >= min.Lon && p.Lon <= max.Lon
+	} else {
+		within = within && (p.Lon >= min.Lon || p.Lon <= max.Lon)
+	}
+
+	return &Bool{val: within}, nil
+}
+
+func (bexp *WithinBoxExp) selectors() []Selector {
+	sels := bexp.val.selectors()
+	for _, bound := range bexp.bounds() {
+		sels = append(sels, bound.selectors()...)
+	}
+	return sels
+}
+
+func (bexp *WithinBoxExp) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	return NewWithinBoxExp(
+		bexp.val.reduceSelectors(row, implicitTable),
+		bexp.minLat.reduceSelectors(row, implicitTable),
+		bexp.minLon.reduceSelectors(row, implicitTable),
+		bexp.maxLat.reduceSelectors(row, implicitTable),
+		bexp.maxLon.reduceSelectors(row, implicitTable),
+	)
+}
+
+func (bexp *WithinBoxExp) isConstant() bool {
+	return false
+}
+
+func (bexp *WithinBoxExp) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
+}
+
+func (bexp *WithinBoxExp) String() string {
+	return fmt.Sprintf(
+		"(%s WITHIN BOX(%s, %s, %s, %s))",
+		bexp.val.String(),
+		bexp.minLat.String(),
+		bexp.minLon.String(),
+		bexp.maxLat.String(),
+		bexp.maxLon.String(),
+	)
+}
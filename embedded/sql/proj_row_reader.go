@@ -19,6 +19,7 @@ package sql
 import (
 	"context"
 	"fmt"
+	"iter"
 )
 
 type projectedRowReader struct {
@@ -26,6 +27,8 @@ type projectedRowReader struct {
 	tableAlias string
 
 	targets []TargetEntry
+
+	lookahead rowLookahead
 }
 
 func newProjectedRowReader(ctx context.Context, rowReader RowReader, tableAlias string, targets []TargetEntry) (*projectedRowReader, error) {
@@ -106,13 +109,12 @@ func (pr *projectedRowReader) Columns(ctx context.Context) ([]ColDescriptor, err
 		}
 		aggFn = ""
 
-		colsByPos[i] = ColDescriptor{
+		des := ColDescriptor{
 			AggFn:  aggFn,
 			Table:  table,
 			Column: col,
 		}
-		encSel := colsByPos[i].Selector()
-		colsByPos[i].Type = colsBySel[encSel].Type
+		colsByPos[i] = colsBySel[des.Selector()]
 	}
 	return colsByPos, nil
 }
@@ -149,10 +151,21 @@ func (pr *projectedRowReader) colsBySelector(ctx context.Context) (map[string]Co
 		aggFn = ""
 
 		des := ColDescriptor{
-			AggFn:  aggFn,
-			Table:  table,
-			Column: col,
-			Type:   sqlType,
+			AggFn:    aggFn,
+			Table:    table,
+			Column:   col,
+			Type:     sqlType,
+			Nullable: true,
+		}
+
+		// column properties are kept when values are projected as they are
+		if s, ok := t.Exp.(Selector); ok {
+			srcDes, ok := dsColDescriptors[EncodeSelector(s.resolve(pr.rowReader.TableAlias()))]
+			if ok {
+				des.MaxLen = srcDes.MaxLen
+				des.Nullable = srcDes.Nullable
+				des.PrimaryKey = srcDes.PrimaryKey
+			}
 		}
 		colDescriptors[des.Selector()] = des
 	}
@@ -182,7 +195,19 @@ func (pr *projectedRowReader) Parameters() map[string]interface{} {
 	return pr.rowReader.Parameters()
 }
 
+func (pr *projectedRowReader) All(ctx context.Context) iter.Seq2[*Row, error] {
+	return allRows(ctx, pr)
+}
+
 func (pr *projectedRowReader) Read(ctx context.Context) (*Row, error) {
+	return pr.lookahead.read(ctx, pr.readRow)
+}
+
+func (pr *projectedRowReader) Peek(ctx context.Context) (*Row, error) {
+	return pr.lookahead.peek(ctx, pr.readRow)
+}
+
+func (pr *projectedRowReader) readRow(ctx context.Context) (*Row, error) {
 	row, err := pr.rowReader.Read(ctx)
 	if err != nil {
 		return nil, err
@@ -199,7 +224,7 @@ func (pr *projectedRowReader) Read(ctx context.Context) (*Row, error) {
 			return nil, fmt.Errorf("%w: when evaluating WHERE clause", err)
 		}
 
-		v, err := e.reduce(pr.Tx(), row, pr.rowReader.TableAlias())
+		v, err := e.reduceCtx(ctx, pr.Tx(), row, pr.rowReader.TableAlias())
 		if err != nil {
 			return nil, err
 		}
@@ -226,5 +251,7 @@ func (pr *projectedRowReader) Read(ctx context.Context) (*Row, error) {
 }
 
 func (pr *projectedRowReader) Close() error {
+	pr.lookahead.discard()
+
 	return pr.rowReader.Close()
 }
@@ -0,0 +1,92 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+// neededColumns returns the ids of the columns of the table whose values are accessed by the statement,
+// so the values of the remaining columns don't need to be decoded, or nil when any column may be accessed,
+// as when all columns are selected or by LATERAL subqueries. Selectors are matched by column name whatever
+// table they refer to, which at most results in the values of some more columns being decoded.
+func (stmt *SelectStmt) neededColumns(table *Table, sortExps ...[]*OrdExp) map[uint32]struct{} {
+	if len(stmt.targets) == 0 {
+		return nil
+	}
+
+	exps := make([]ValueExp, 0, len(stmt.targets))
+
+	for _, t := range stmt.targets {
+		exps = append(exps, t.Exp)
+	}
+
+	for _, j := range stmt.joins {
+		if j.lateral || j.natural {
+			return nil
+		}
+
+		for _, col := range j.using {
+			exps = append(exps, &ColSelector{col: col})
+		}
+
+		if j.cond != nil {
+			exps = append(exps, j.cond)
+		}
+	}
+
+	for _, sel := range stmt.groupBy {
+		exps = append(exps, sel)
+	}
+
+	for _, exp := range []ValueExp{stmt.where, stmt.having} {
+		if exp != nil {
+			exps = append(exps, exp)
+		}
+	}
+
+	for _, ordExps := range append(sortExps, stmt.orderBy) {
+		for _, e := range ordExps {
+			exps = append(exps, e.exp)
+		}
+	}
+
+	needed := make(map[uint32]struct{})
+
+	for _, exp := range exps {
+		sels := exp.selectors()
+
+		// the columns of COUNT(DISTINCT ...) following the first one are not selectors of the aggregation
+		for _, sel := range sels {
+			if aggSel, isAgg := sel.(*AggColSelector); isAgg {
+				for _, colSel := range aggSel.distinct {
+					sels = append(sels, colSel)
+				}
+			}
+		}
+
+		for _, sel := range sels {
+			if jsonSel, isJSON := sel.(*JSONSelector); isJSON {
+				sel = jsonSel.ColSelector
+			}
+
+			_, _, colName := sel.resolve(table.name)
+
+			col, err := table.GetColumnByName(colName)
+			if err == nil {
+				needed[col.id] = struct{}{}
+			}
+		}
+	}
+	return needed
+}
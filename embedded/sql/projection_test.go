@@ -0,0 +1,266 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNeededColumns(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, `
+		CREATE TABLE t (id INTEGER, a INTEGER, b VARCHAR, c JSON, d INTEGER, PRIMARY KEY id);
+		CREATE TABLE u (id INTEGER, a INTEGER, e INTEGER, PRIMARY KEY id);
+	`, nil)
+	require.NoError(t, err)
+
+	tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithReadOnly(true))
+	require.NoError(t, err)
+	defer tx.Cancel()
+
+	for _, d := range []struct {
+		sql    string
+		needed []string
+	}{
+		{"SELECT * FROM t", nil},
+		{"SELECT id FROM t", []string{"id"}},
+		{"SELECT a FROM t WHERE d > 10", []string{"a", "d"}},
+		{"SELECT t.a + 1 AS x FROM t AS t ORDER BY b, x", []string{"a", "b"}},
+		{"SELECT c->'f' FROM t WHERE c->'g'->'h' = 1", []string{"c"}},
+		{"SELECT id FROM t WHERE 'a' LIKE b", []string{"b", "id"}},
+		{"SELECT d, COUNT(*), SUM(a) FROM t GROUP BY d HAVING MAX(id) > 1", []string{"a", "d", "id"}},
+		{"SELECT COUNT(*) FROM t", []string{}},
+		{"SELECT COUNT(DISTINCT a, b) FROM t", []string{"a", "b"}},
+		{"SELECT t.id, u.e FROM t JOIN u ON t.d = u.id", []string{"d", "id"}},
+		{"SELECT e FROM t JOIN u USING (a)", []string{"a"}},
+		{"SELECT e FROM t NATURAL JOIN u", nil},
+		{"SELECT x.e FROM t, LATERAL (SELECT e FROM u WHERE u.a = t.a) x", nil},
+	} {
+		t.Run(d.sql, func(t *testing.T) {
+			stmts, err := ParseSQLString(d.sql)
+			require.NoError(t, err)
+
+			stmt := stmts[0].(*SelectStmt)
+
+			specs, err := stmt.genScanSpecs(tx, nil)
+			require.NoError(t, err)
+
+			if d.needed == nil {
+				require.Nil(t, specs.neededCols)
+				return
+			}
+
+			table, err := tx.catalog.GetTableByName("t")
+			require.NoError(t, err)
+
+			needed := []string{}
+			for id := range specs.neededCols {
+				col, err := table.GetColumnByID(id)
+				require.NoError(t, err)
+				needed = append(needed, col.colName)
+			}
+			sort.Strings(needed)
+
+			require.Equal(t, d.needed, needed)
+		})
+	}
+}
+
+func TestProjectionPushdown(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, `
+		CREATE TABLE t (id INTEGER AUTO_INCREMENT, a INTEGER, b VARCHAR[16], c JSON, d INTEGER NOT NULL, PRIMARY KEY id);
+		CREATE INDEX ON t (b);
+		CREATE TABLE u (id INTEGER, e VARCHAR, PRIMARY KEY id);
+		INSERT INTO t (a, b, c, d) VALUES
+			(1, 'x', '{"f": 10}', 100),
+			(NULL, 'y', '{"f": 20}', 200),
+			(3, 'x', '{"g": 30}', 300),
+			(4, 'x%', '{"f": 40}', 400);
+		INSERT INTO u (id, e) VALUES (100, 'p'), (300, 'q');
+	`, nil)
+	require.NoError(t, err)
+
+	rawValues := func(t *testing.T, sql string) [][]interface{} {
+		rows, err := engine.queryAll(context.Background(), nil, sql, nil)
+		require.NoError(t, err)
+
+		values := make([][]interface{}, len(rows))
+		for i, row := range rows {
+			for _, v := range row.ValuesByPosition {
+				raw := v.RawValue()
+				if _, isJSON := v.(*JSON); isJSON && !v.IsNull() {
+					raw = v.String()
+				}
+				values[i] = append(values[i], raw)
+			}
+		}
+		return values
+	}
+
+	for _, d := range []struct {
+		sql      string
+		expected [][]interface{}
+	}{
+		{"SELECT id FROM t WHERE d > 150", [][]interface{}{{int64(2)}, {int64(3)}, {int64(4)}}},
+		{"SELECT a FROM t WHERE b = 'x' ORDER BY d DESC", [][]interface{}{{int64(3)}, {int64(1)}}},
+		{"SELECT b, SUM(d) FROM t GROUP BY b ORDER BY b", [][]interface{}{{"x", int64(400)}, {"x%", int64(400)}, {"y", int64(200)}}},
+		{"SELECT c->'f' FROM t WHERE c->'f' > 15 ORDER BY id", [][]interface{}{{"20"}, {"40"}}},
+		{"SELECT id FROM t WHERE 'x%' LIKE b ORDER BY id", [][]interface{}{{int64(1)}, {int64(3)}, {int64(4)}}},
+		{"SELECT t.id, u.e FROM t JOIN u ON t.d = u.id ORDER BY t.id", [][]interface{}{{int64(1), "p"}, {int64(3), "q"}}},
+		{"SELECT t.id, x.e FROM t, LATERAL (SELECT e FROM u WHERE u.id = t.d) x ORDER BY t.id", [][]interface{}{{int64(1), "p"}, {int64(3), "q"}}},
+		{"SELECT DISTINCT b FROM t WHERE a IS NOT NULL ORDER BY b", [][]interface{}{{"x"}, {"x%"}}},
+		{"SELECT id, b FROM (SELECT id, b, d FROM t) AS s WHERE d < 250", [][]interface{}{{int64(1), "x"}, {int64(2), "y"}}},
+		{"SELECT COUNT(DISTINCT a, b) FROM t", [][]interface{}{{int64(3)}}},
+	} {
+		t.Run(d.sql, func(t *testing.T) {
+			require.Equal(t, d.expected, rawValues(t, d.sql))
+		})
+	}
+
+	t.Run("columns not decoded can not be read", func(t *testing.T) {
+		tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithReadOnly(true))
+		require.NoError(t, err)
+		defer tx.Cancel()
+
+		stmts, err := ParseSQLString("SELECT a FROM t")
+		require.NoError(t, err)
+
+		stmt := stmts[0].(*SelectStmt)
+
+		specs, err := stmt.genScanSpecs(tx, nil)
+		require.NoError(t, err)
+
+		rr, err := stmt.ds.Resolve(context.Background(), tx, nil, specs)
+		require.NoError(t, err)
+		defer rr.Close()
+
+		row, err := rr.Read(context.Background())
+		require.NoError(t, err)
+
+		val, err := (&ColSelector{col: "a"}).reduce(tx, row, "t")
+		require.NoError(t, err)
+		require.Equal(t, int64(1), val.RawValue())
+
+		_, err = (&ColSelector{col: "b"}).reduce(tx, row, "t")
+		require.ErrorIs(t, err, ErrColumnDoesNotExist)
+	})
+
+	// the values of the columns not accessed by queries are left untouched by updates and deletes
+	_, _, err = engine.Exec(context.Background(), nil, "UPDATE t SET a = a + 1 WHERE d > 250; DELETE FROM t WHERE b = 'y'", nil)
+	require.NoError(t, err)
+
+	require.Equal(t, [][]interface{}{
+		{int64(1), int64(1), "x", `{"f":10}`, int64(100)},
+		{int64(3), int64(4), "x", `{"g":30}`, int64(300)},
+		{int64(4), int64(5), "x%", `{"f":40}`, int64(400)},
+	}, rawValues(t, "SELECT * FROM t"))
+}
+
+func BenchmarkProjectionPushdown(b *testing.B) {
+	st, err := store.Open(b.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(b, err)
+	defer st.Close()
+
+	engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+	require.NoError(b, err)
+
+	const nCols = 30
+	const nRows = 10_000
+
+	cols := make([]string, nCols)
+	for i := range cols {
+		cols[i] = fmt.Sprintf("c%d", i)
+	}
+
+	_, _, err = engine.Exec(context.Background(), nil,
+		fmt.Sprintf("CREATE TABLE t (id INTEGER AUTO_INCREMENT, %s VARCHAR, PRIMARY KEY id)", strings.Join(cols, " VARCHAR, ")), nil)
+	require.NoError(b, err)
+
+	params := make([]string, nCols)
+	for i := range params {
+		params[i] = "@" + cols[i]
+	}
+	insert := fmt.Sprintf("INSERT INTO t (%s) VALUES (%s)", strings.Join(cols, ", "), strings.Join(params, ", "))
+
+	for i := 0; i < nRows; i += 1000 {
+		tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithExplicitClose(true))
+		require.NoError(b, err)
+
+		for j := i; j < i+1000; j++ {
+			values := make(map[string]interface{}, nCols)
+			for _, col := range cols {
+				values[col] = fmt.Sprintf("%s-value-%05d", col, j)
+			}
+
+			_, _, err = engine.Exec(context.Background(), tx, insert, values)
+			require.NoError(b, err)
+		}
+
+		require.NoError(b, tx.Commit(context.Background()))
+	}
+
+	stmts, err := ParseSQLString("SELECT c0 FROM t WHERE c29 > 'c29-value-05000'")
+	require.NoError(b, err)
+
+	stmt := stmts[0].(*SelectStmt)
+
+	for _, pushdown := range []bool{false, true} {
+		b.Run(fmt.Sprintf("pushdown=%v", pushdown), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithReadOnly(true))
+				require.NoError(b, err)
+
+				specs, err := stmt.genScanSpecs(tx, nil)
+				require.NoError(b, err)
+
+				if !pushdown {
+					specs.neededCols = nil
+				}
+
+				rr, err := stmt.ds.Resolve(context.Background(), tx, nil, specs)
+				require.NoError(b, err)
+
+				r := newConditionalRowReader(rr, stmt.where)
+
+				n := 0
+				for {
+					_, err := r.Read(context.Background())
+					if errors.Is(err, ErrNoMoreRows) {
+						break
+					}
+					require.NoError(b, err)
+					n++
+				}
+				require.Equal(b, nRows/2-1, n)
+
+				r.Close()
+				tx.Cancel()
+			}
+		})
+	}
+}
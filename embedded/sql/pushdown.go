@@ -0,0 +1,213 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+// pushDownPredicates returns the data source of the statement with the conjuncts of
+// its WHERE clause that only refer to columns of a subquery added to the WHERE clause
+// of the subquery itself, so that they can be used to narrow its scan e.g. by using
+// an index. The WHERE clause of the statement is kept unchanged, thus only
+// deterministic conjuncts are pushed down.
+func (stmt *SelectStmt) pushDownPredicates(tx *SQLTx) DataSource {
+	subquery, isSubquery := stmt.ds.(*SelectStmt)
+	if !isSubquery || stmt.where == nil || !subquery.filterCommutes() {
+		return stmt.ds
+	}
+
+	mapSel := subquery.targetMapper(tx)
+	if mapSel == nil {
+		return stmt.ds
+	}
+
+	where := subquery.where
+
+	for _, conjunct := range conjuncts(stmt.where) {
+		exp, ok := rewriteSelectors(conjunct, mapSel)
+		if !ok {
+			continue
+		}
+
+		if where == nil {
+			where = exp
+		} else {
+			where = &BinBoolExp{op: And, left: where, right: exp}
+		}
+	}
+
+	if where == subquery.where {
+		return stmt.ds
+	}
+
+	pushed := *subquery
+	pushed.where = where
+
+	return &pushed
+}
+
+// filterCommutes returns true when filtering the rows returned by the statement
+// is equivalent to filtering them before the statement gets applied
+func (stmt *SelectStmt) filterCommutes() bool {
+	return !stmt.distinct &&
+		len(stmt.joins) == 0 &&
+		len(stmt.groupBy) == 0 &&
+		stmt.having == nil &&
+		!stmt.containsAggregations() &&
+		stmt.limit == nil &&
+		stmt.offset == nil
+}
+
+// targetMapper returns a function mapping the columns returned by the statement,
+// as referred by an outer query, into the columns they are projected from
+func (stmt *SelectStmt) targetMapper(tx *SQLTx) func(*ColSelector) (ValueExp, bool) {
+	alias := stmt.Alias()
+	dsAlias := stmt.ds.Alias()
+
+	// case: SELECT *
+	if len(stmt.targets) == 0 {
+		var exposes func(col string) bool
+
+		switch ds := stmt.ds.(type) {
+		case *tableRef:
+			table, err := ds.referencedTable(tx)
+			if err != nil {
+				return nil
+			}
+
+			exposes = func(col string) bool {
+				_, err := table.GetColumnByName(col)
+				return err == nil
+			}
+		case *SelectStmt:
+			mapSel := ds.targetMapper(tx)
+			if mapSel == nil {
+				return nil
+			}
+
+			exposes = func(col string) bool {
+				_, ok := mapSel(&ColSelector{table: dsAlias, col: col})
+				return ok
+			}
+		default:
+			return nil
+		}
+
+		return func(sel *ColSelector) (ValueExp, bool) {
+			_, t, col := sel.resolve(alias)
+			if t != alias || !exposes(col) {
+				return nil, false
+			}
+			return &ColSelector{table: dsAlias, col: col}, true
+		}
+	}
+
+	return func(sel *ColSelector) (ValueExp, bool) {
+		_, t, col := sel.resolve(alias)
+		if t != alias {
+			return nil, false
+		}
+
+		for _, target := range stmt.targets {
+			colSel, isColSel := target.Exp.(*ColSelector)
+			if !isColSel {
+				continue
+			}
+
+			name := target.As
+			if name == "" {
+				name = colSel.col
+			}
+
+			if name == col {
+				return colSel, true
+			}
+		}
+		return nil, false
+	}
+}
+
+func conjuncts(exp ValueExp) []ValueExp {
+	bexp, isBinBool := exp.(*BinBoolExp)
+	if !isBinBool || bexp.op != And {
+		return []ValueExp{exp}
+	}
+	return append(conjuncts(bexp.left), conjuncts(bexp.right)...)
+}
+
+// rewriteSelectors returns a copy of exp in which column selectors are replaced by mapSel.
+// It fails if any selector can not be mapped or exp includes non-deterministic expressions.
+func rewriteSelectors(exp ValueExp, mapSel func(*ColSelector) (ValueExp, bool)) (ValueExp, bool) {
+	rewriteAll := func(exps ...ValueExp) ([]ValueExp, bool) {
+		rexps := make([]ValueExp, len(exps))
+		for i, e := range exps {
+			re, ok := rewriteSelectors(e, mapSel)
+			if !ok {
+				return nil, false
+			}
+			rexps[i] = re
+		}
+		return rexps, true
+	}
+
+	switch e := exp.(type) {
+	case TypedValue, *Param:
+		return e, true
+	case *ColSelector:
+		return mapSel(e)
+	case *NumExp:
+		rexps, ok := rewriteAll(e.left, e.right)
+		if !ok {
+			return nil, false
+		}
+		return &NumExp{op: e.op, left: rexps[0], right: rexps[1]}, true
+	case *CmpBoolExp:
+		rexps, ok := rewriteAll(e.left, e.right)
+		if !ok {
+			return nil, false
+		}
+		return &CmpBoolExp{op: e.op, left: rexps[0], right: rexps[1]}, true
+	case *BinBoolExp:
+		rexps, ok := rewriteAll(e.left, e.right)
+		if !ok {
+			return nil, false
+		}
+		return &BinBoolExp{op: e.op, left: rexps[0], right: rexps[1]}, true
+	case *NotBoolExp:
+		rexps, ok := rewriteAll(e.exp)
+		if !ok {
+			return nil, false
+		}
+		return &NotBoolExp{exp: rexps[0]}, true
+	case *Cast:
+		rexps, ok := rewriteAll(e.val)
+		if !ok {
+			return nil, false
+		}
+		return &Cast{val: rexps[0], t: e.t}, true
+	case *LikeBoolExp:
+		rexps, ok := rewriteAll(e.val, e.pattern)
+		if !ok {
+			return nil, false
+		}
+		return &LikeBoolExp{val: rexps[0], notLike: e.notLike, pattern: rexps[1]}, true
+	case *InListExp:
+		rexps, ok := rewriteAll(append([]ValueExp{e.val}, e.values...)...)
+		if !ok {
+			return nil, false
+		}
+		return &InListExp{val: rexps[0], notIn: e.notIn, values: rexps[1:]}, true
+	}
+	return nil, false
+}
@@ -0,0 +1,234 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SelectBuilder builds SELECT statements without writing SQL, e.g.
+//
+//	engine.Select("id", "val").From("t").Where(Gt("val", 5000)).OrderBy("id").Query(ctx)
+//
+// Statements are built the same way the parser builds them, and are executed as any other
+// query. Values are bound as they are, thus they don't need to be escaped, while column and
+// table names are identifiers, possibly qualified as in "t.col", that must not come from
+// untrusted input. Errors are returned when the statement is executed.
+type SelectBuilder struct {
+	engine *Engine
+	tx     *SQLTx
+	params map[string]interface{}
+
+	stmt *SelectStmt
+	err  error
+}
+
+// Select starts building a query of the given columns, all of them are selected when none is given
+func (e *Engine) Select(cols ...string) *SelectBuilder {
+	b := &SelectBuilder{engine: e, stmt: &SelectStmt{}}
+
+	for _, col := range cols {
+		b.stmt.targets = append(b.stmt.targets, TargetEntry{Exp: Col(col)})
+	}
+	return b
+}
+
+func (b *SelectBuilder) From(table string) *SelectBuilder {
+	b.stmt.ds = &tableRef{table: strings.ToLower(table)}
+	return b
+}
+
+// Where adds a condition the rows must satisfy, along with the ones previously added
+func (b *SelectBuilder) Where(cond Condition) *SelectBuilder {
+	if cond.err != nil {
+		b.setErr(cond.err)
+		return b
+	}
+
+	if b.stmt.where == nil {
+		b.stmt.where = cond.exp
+	} else {
+		b.stmt.where = &BinBoolExp{op: And, left: b.stmt.where, right: cond.exp}
+	}
+	return b
+}
+
+// OrderBy sorts the rows by the given columns in ascending order, after any previous one
+func (b *SelectBuilder) OrderBy(cols ...string) *SelectBuilder {
+	return b.orderBy(cols, false)
+}
+
+// OrderByDesc sorts the rows by the given columns in descending order, after any previous one
+func (b *SelectBuilder) OrderByDesc(cols ...string) *SelectBuilder {
+	return b.orderBy(cols, true)
+}
+
+func (b *SelectBuilder) orderBy(cols []string, descOrder bool) *SelectBuilder {
+	for _, col := range cols {
+		b.stmt.orderBy = append(b.stmt.orderBy, &OrdExp{exp: Col(col), descOrder: descOrder})
+	}
+	return b
+}
+
+func (b *SelectBuilder) Limit(limit int) *SelectBuilder {
+	b.stmt.limit = &Integer{val: int64(limit)}
+	return b
+}
+
+func (b *SelectBuilder) Offset(offset int) *SelectBuilder {
+	b.stmt.offset = &Integer{val: int64(offset)}
+	return b
+}
+
+// WithParams specifies the values of the parameters referred to by Placeholder
+func (b *SelectBuilder) WithParams(params map[string]interface{}) *SelectBuilder {
+	b.params = params
+	return b
+}
+
+// InTx makes the query to be executed within the given transaction,
+// otherwise it's executed in a new read-only transaction
+func (b *SelectBuilder) InTx(tx *SQLTx) *SelectBuilder {
+	b.tx = tx
+	return b
+}
+
+func (b *SelectBuilder) setErr(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
+
+// Stmt returns the statement built, which may be executed with Engine.QueryPreparedStmt
+func (b *SelectBuilder) Stmt() (*SelectStmt, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	if b.stmt.ds == nil {
+		return nil, fmt.Errorf("%w: no table to select from", ErrIllegalArguments)
+	}
+	return b.stmt, nil
+}
+
+func (b *SelectBuilder) Query(ctx context.Context) (RowReader, error) {
+	stmt, err := b.Stmt()
+	if err != nil {
+		return nil, err
+	}
+	return b.engine.QueryPreparedStmt(ctx, b.tx, stmt, b.params)
+}
+
+// Condition is a predicate over the columns of the rows, as built by Eq, Gt, All, etc.
+type Condition struct {
+	exp ValueExp
+	err error
+}
+
+// Col refers to a column, possibly qualified as in "t.col", so it can be compared against
+// other columns, e.g. Lt("start", Col("end"))
+func Col(name string) *ColSelector {
+	name = strings.ToLower(name)
+
+	if table, col, qualified := strings.Cut(name, "."); qualified {
+		return &ColSelector{table: table, col: col}
+	}
+	return &ColSelector{col: name}
+}
+
+// Placeholder refers to a named parameter, whose value is provided with SelectBuilder.WithParams
+func Placeholder(name string) *Param {
+	return &Param{id: strings.ToLower(name)}
+}
+
+func Eq(col string, val interface{}) Condition { return cmpCondition(EQ, col, val) }
+func Ne(col string, val interface{}) Condition { return cmpCondition(NE, col, val) }
+func Lt(col string, val interface{}) Condition { return cmpCondition(LT, col, val) }
+func Le(col string, val interface{}) Condition { return cmpCondition(LE, col, val) }
+func Gt(col string, val interface{}) Condition { return cmpCondition(GT, col, val) }
+func Ge(col string, val interface{}) Condition { return cmpCondition(GE, col, val) }
+
+// IsNull is satisfied by the rows where the column is NULL
+func IsNull(col string) Condition {
+	return Condition{exp: &CmpBoolExp{op: EQ, left: Col(col), right: &NullValue{t: AnyType}}}
+}
+
+// Like is satisfied by the rows where the column matches the regular expression
+func Like(col string, pattern interface{}) Condition {
+	exp, err := builderValue(pattern)
+	return Condition{exp: &LikeBoolExp{val: Col(col), pattern: exp}, err: err}
+}
+
+// In is satisfied by the rows where the column is equal to any of the values
+func In(col string, vals ...interface{}) Condition {
+	list := make([]ValueExp, len(vals))
+
+	for i, val := range vals {
+		exp, err := builderValue(val)
+		if err != nil {
+			return Condition{err: err}
+		}
+		list[i] = exp
+	}
+	return Condition{exp: &InListExp{val: Col(col), values: list}}
+}
+
+// All is satisfied when all the conditions are
+func All(conds ...Condition) Condition {
+	return logicCondition(And, conds)
+}
+
+// Any is satisfied when any of the conditions is
+func Any(conds ...Condition) Condition {
+	return logicCondition(Or, conds)
+}
+
+func Not(cond Condition) Condition {
+	return Condition{exp: &NotBoolExp{exp: cond.exp}, err: cond.err}
+}
+
+func cmpCondition(op CmpOperator, col string, val interface{}) Condition {
+	exp, err := builderValue(val)
+	return Condition{exp: &CmpBoolExp{op: op, left: Col(col), right: exp}, err: err}
+}
+
+func logicCondition(op LogicOperator, conds []Condition) Condition {
+	if len(conds) == 0 {
+		return Condition{err: fmt.Errorf("%w: no conditions to combine", ErrIllegalArguments)}
+	}
+
+	cond := conds[0]
+
+	for _, c := range conds[1:] {
+		if cond.err == nil {
+			cond.err = c.err
+		}
+		cond.exp = &BinBoolExp{op: op, left: cond.exp, right: c.exp}
+	}
+	return cond
+}
+
+// builderValue returns the expression of a value provided to a builder, values other than
+// expressions are bound as parameters are, e.g. an int becomes an INTEGER
+func builderValue(val interface{}) (ValueExp, error) {
+	if exp, isExp := val.(ValueExp); isExp {
+		return exp, nil
+	}
+	return paramValue(val)
+}
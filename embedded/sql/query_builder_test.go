@@ -0,0 +1,171 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSelectBuilderStmt(t *testing.T) {
+	for _, d := range []struct {
+		builder *SelectBuilder
+		sql     string
+	}{
+		{(&Engine{}).Select().From("t"), "SELECT * FROM t"},
+		{(&Engine{}).Select("id", "t.val").From("T").Where(Gt("val", 5000)).OrderBy("id"), "SELECT id, t.val FROM t WHERE val > 5000 ORDER BY id"},
+		{(&Engine{}).Select("id").From("t").Where(Eq("name", "alice")).Where(Ne("active", false)), "SELECT id FROM t WHERE name = 'alice' AND active != false"},
+		{(&Engine{}).Select("id").From("t").Where(Any(Le("val", 1.5), All(IsNull("name"), Not(Lt("val", Col("lo")))))), "SELECT id FROM t WHERE val <= 1.5 OR (name IS NULL AND NOT val < lo)"},
+		{(&Engine{}).Select("id").From("t").Where(In("id", 1, 2, 3)).Where(Like("name", "^a")), "SELECT id FROM t WHERE id IN (1, 2, 3) AND name LIKE '^a'"},
+		{(&Engine{}).Select("id").From("t").Where(Ge("val", Placeholder("Min"))).OrderByDesc("val").OrderBy("id").Limit(10).Offset(5), "SELECT id FROM t WHERE val >= @min ORDER BY val DESC, id LIMIT 10 OFFSET 5"},
+	} {
+		t.Run(d.sql, func(t *testing.T) {
+			stmt, err := d.builder.Stmt()
+			require.NoError(t, err)
+
+			stmts, err := ParseSQLString(d.sql)
+			require.NoError(t, err)
+			require.Equal(t, stmts[0], stmt)
+		})
+	}
+
+	t.Run("invalid statements", func(t *testing.T) {
+		_, err := (&Engine{}).Select("id").Stmt()
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = (&Engine{}).Select("id").From("t").Where(Any()).Stmt()
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = (&Engine{}).Select("id").From("t").Where(All(Eq("id", 1), Eq("val", struct{}{}))).Stmt()
+		require.ErrorIs(t, err, ErrUnsupportedParameter)
+
+		_, err = (&Engine{}).Select("id").From("t").Where(In("id", 1, []int{2})).Stmt()
+		require.ErrorIs(t, err, ErrUnsupportedParameter)
+	})
+}
+
+func TestSelectBuilderQuery(t *testing.T) {
+	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(t, err)
+	defer closeStore(t, st)
+
+	engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		CREATE TABLE t (id INTEGER AUTO_INCREMENT, name VARCHAR, val INTEGER, PRIMARY KEY id);
+		INSERT INTO t (name, val) VALUES ('alice', 7000), ('bob', 1200), ('carol', 9100), ('dave', NULL), ('erin', 5000);
+	`, nil)
+	require.NoError(t, err)
+
+	rawValues := func(t *testing.T, r RowReader) [][]interface{} {
+		defer r.Close()
+
+		rows, err := ReadAllRows(context.Background(), r)
+		require.NoError(t, err)
+
+		values := make([][]interface{}, len(rows))
+		for i, row := range rows {
+			for _, v := range row.ValuesByPosition {
+				values[i] = append(values[i], v.RawValue())
+			}
+		}
+		return values
+	}
+
+	query := func(t *testing.T, sql string, params map[string]interface{}) [][]interface{} {
+		r, err := engine.Query(context.Background(), nil, sql, params)
+		require.NoError(t, err)
+		return rawValues(t, r)
+	}
+
+	built := func(t *testing.T, b *SelectBuilder) [][]interface{} {
+		r, err := b.Query(context.Background())
+		require.NoError(t, err)
+		return rawValues(t, r)
+	}
+
+	t.Run("same results as SQL", func(t *testing.T) {
+		require.Equal(t,
+			[][]interface{}{{int64(1), int64(7000)}, {int64(3), int64(9100)}},
+			built(t, engine.Select("id", "val").From("t").Where(Gt("val", 5000)).OrderBy("id")),
+		)
+
+		for _, d := range []struct {
+			builder *SelectBuilder
+			sql     string
+			params  map[string]interface{}
+		}{
+			{engine.Select().From("t"), "SELECT * FROM t", nil},
+			{engine.Select("name").From("t").Where(Ge("val", 5000)).OrderByDesc("val").Limit(2), "SELECT name FROM t WHERE val >= 5000 ORDER BY val DESC LIMIT 2", nil},
+			{engine.Select("name").From("t").OrderBy("name").Limit(2).Offset(1), "SELECT name FROM t ORDER BY name LIMIT 2 OFFSET 1", nil},
+			{engine.Select("id").From("t").Where(Any(IsNull("val"), In("name", "bob", "erin"))), "SELECT id FROM t WHERE val IS NULL OR name IN ('bob', 'erin')", nil},
+			{engine.Select("id").From("t").Where(Like("name", "^[a-c]")).Where(Not(Eq("id", 2))), "SELECT id FROM t WHERE name LIKE '^[a-c]' AND NOT id = 2", nil},
+			{
+				engine.Select("id", "name").From("t").Where(All(Gt("val", Placeholder("min")), Lt("val", Placeholder("max")))).WithParams(map[string]interface{}{"min": 1000, "max": 8000}),
+				"SELECT id, name FROM t WHERE val > @min AND val < @max",
+				map[string]interface{}{"min": 1000, "max": 8000},
+			},
+		} {
+			stmt, err := d.builder.Stmt()
+			require.NoError(t, err)
+
+			t.Run(d.sql, func(t *testing.T) {
+				require.Equal(t, query(t, d.sql, d.params), built(t, d.builder))
+			})
+
+			// statements are not modified when executed
+			again, err := d.builder.Stmt()
+			require.NoError(t, err)
+			require.Equal(t, stmt, again)
+		}
+	})
+
+	t.Run("values are not interpreted as SQL", func(t *testing.T) {
+		require.Empty(t, built(t, engine.Select("id").From("t").Where(Eq("name", "alice' OR 'a' = 'a"))))
+	})
+
+	t.Run("within a transaction", func(t *testing.T) {
+		tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithExplicitClose(true))
+		require.NoError(t, err)
+		defer tx.Cancel()
+
+		_, _, err = engine.Exec(context.Background(), tx, "INSERT INTO t (name, val) VALUES ('frank', 9900)", nil)
+		require.NoError(t, err)
+
+		require.Equal(t, [][]interface{}{{"carol"}, {"frank"}}, built(t, engine.Select("name").From("t").Where(Gt("val", 9000)).InTx(tx)))
+		require.Equal(t, [][]interface{}{{"carol"}}, built(t, engine.Select("name").From("t").Where(Gt("val", 9000))))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := engine.Select("id").From("missing").Query(context.Background())
+		require.ErrorIs(t, err, ErrTableDoesNotExist)
+
+		_, err = engine.Select("id").Query(context.Background())
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		r, err := engine.Select("id").From("t").Where(Gt("val", Placeholder("min"))).Query(context.Background())
+		if err == nil {
+			_, err = r.Read(context.Background())
+			r.Close()
+		}
+		require.ErrorIs(t, err, ErrMissingParameter)
+	})
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"github.com/codenotary/immudb/embedded/store"
+)
+
+// primaryKeyRange returns the range of primary key values matched by the WHERE clause
+// of the statement, when the clause is fully captured by a contiguous range over a
+// single-column primary key. Otherwise, it returns false and rows must be deleted one by one.
+func (stmt *DeleteFromStmt) primaryKeyRange(tx *SQLTx, params map[string]interface{}) (*Table, *typedValueRange, bool) {
+	if stmt.where == nil ||
+		len(stmt.indexOn) > 0 ||
+		len(stmt.orderBy) > 0 ||
+		stmt.limit != nil ||
+		stmt.offset != nil ||
+		stmt.tableRef.history ||
+		stmt.tableRef.period.start != nil ||
+		stmt.tableRef.period.end != nil {
+		return nil, nil, false
+	}
+
+	table, err := stmt.tableRef.referencedTable(tx)
+	if err != nil || len(table.primaryIndex.cols) != 1 {
+		return nil, nil, false
+	}
+
+	// entries of full-text indexes are removed based on the decoded row
+	if len(table.fullTextIndexes) > 0 {
+		return nil, nil, false
+	}
+
+	// so are the values of the rows triggers are fired for
+	if len(table.triggersOn(TriggerOnDelete)) > 0 {
+		return nil, nil, false
+	}
+
+	pkCol := table.primaryIndex.cols[0]
+
+	rangesByColID := make(map[uint32]*typedValueRange, 1)
+
+	for _, conjunct := range conjuncts(stmt.where) {
+		cmp, isCmp := conjunct.(*CmpBoolExp)
+		if !isCmp || cmp.op == NE {
+			return nil, nil, false
+		}
+
+		op := cmp.op
+		sel, isSel := cmp.left.(*ColSelector)
+		bound := cmp.right
+
+		if !isSel {
+			sel, isSel = cmp.right.(*ColSelector)
+			bound = cmp.left
+			op = flipCmpOperator(op)
+		}
+
+		if !isSel || !bound.isConstant() {
+			return nil, nil, false
+		}
+
+		aggFn, t, col := sel.resolve(table.name)
+		if aggFn != "" || t != stmt.tableRef.Alias() || col != pkCol.colName {
+			return nil, nil, false
+		}
+
+		exp, err := bound.substitute(params)
+		if err != nil {
+			return nil, nil, false
+		}
+
+		val, err := exp.reduce(tx, nil, table.name)
+		if err != nil || val.IsNull() || val.Type() != pkCol.colType {
+			return nil, nil, false
+		}
+
+		err = updateRangeFor(pkCol.id, val, op, rangesByColID)
+		if err != nil {
+			return nil, nil, false
+		}
+	}
+
+	return table, rangesByColID[pkCol.id], true
+}
+
+func flipCmpOperator(op CmpOperator) CmpOperator {
+	switch op {
+	case LT:
+		return GT
+	case LE:
+		return GE
+	case GT:
+		return LT
+	case GE:
+		return LE
+	}
+	return op
+}
+
+// deleteRange tombstones every row whose primary key is within the given range.
+// Entries are read directly from the primary index and their encoded value is used
+// as is, thus rows are neither decoded nor evaluated against the WHERE clause.
+// Secondary index entries are removed by the indexer based on the tombstone value.
+func (stmt *DeleteFromStmt) deleteRange(ctx context.Context, tx *SQLTx, table *Table, pkRange *typedValueRange) (*SQLTx, error) {
+	if pkRange.lRange != nil && pkRange.hRange != nil {
+		cmp, err := pkRange.lRange.val.Compare(pkRange.hRange.val)
+		if err != nil {
+			return nil, err
+		}
+
+		if cmp > 0 || (cmp == 0 && !(pkRange.lRange.inclusive && pkRange.hRange.inclusive)) {
+			// empty range
+			return tx, nil
+		}
+	}
+
+	pkCol := table.primaryIndex.cols[0]
+
+	scanSpecs := &ScanSpecs{
+		Index:         table.primaryIndex,
+		rangesByColID: map[uint32]*typedValueRange{pkCol.id: pkRange},
+	}
+
+	rSpec, err := keyReaderSpecFrom(tx.engine.prefix, table, scanSpecs)
+	if err != nil {
+		return nil, err
+	}
+
+	if tx.opts.ConflictGranularity == TableConflicts {
+		rSpec.ConflictPrefix = table.indexesPrefix()
+	}
+
+	encBound := func(r *typedValueSemiRange) ([]byte, error) {
+		if r == nil || r.inclusive {
+			return nil, nil
+		}
+		encVal, _, err := table.primaryIndex.encodeValueAsKey(r.val, pkCol)
+		return encVal, err
+	}
+
+	// keys matching an exclusive bound are part of the scanned range
+	excludedLo, err := encBound(pkRange.lRange)
+	if err != nil {
+		return nil, err
+	}
+
+	excludedHi, err := encBound(pkRange.hRange)
+	if err != nil {
+		return nil, err
+	}
+
+	reader, err := tx.newKeyReader(*rSpec)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var deletedRows int64
+
+	for {
+		mkey, vref, err := reader.Read(ctx)
+		if errors.Is(err, store.ErrNoMoreEntries) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// primary index entries are mapped as M.{tableID}{indexID}{pkVal}{pkVal}
+		encVals := mkey[len(rSpec.Prefix):]
+		pkEncVals := encVals[len(encVals)/2:]
+
+		if bytes.Equal(pkEncVals, excludedLo) || bytes.Equal(pkEncVals, excludedHi) {
+			continue
+		}
+
+		encodedRowValue, err := vref.Resolve()
+		if err != nil {
+			return nil, err
+		}
+
+		md := store.NewKVMetadata()
+
+		md.AsDeleted(true)
+
+		err = tx.set(table.rowKey(pkEncVals), md, encodedRowValue)
+		if err != nil {
+			return nil, err
+		}
+
+		tx.updatedRows++
+		deletedRows++
+	}
+
+	if err := tx.addRowCount(ctx, table, -deletedRows); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
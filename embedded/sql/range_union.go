@@ -0,0 +1,399 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/codenotary/immudb/embedded/store"
+)
+
+// maxRangeUnionSize is the maximum number of ranges scanned by a single query
+const maxRangeUnionSize = 1024
+
+// selectorRangeUnions returns, by column, the sorted and disjoint ranges of values held by the rows
+// satisfying the condition. Unlike selectorRanges, disjunctions are not widened into a single range,
+// e.g. id = 1 OR id = 3 results in the ranges [1, 1] and [3, 3] instead of [1, 3].
+// Columns whose ranges can not be determined are not included.
+func selectorRangeUnions(exp ValueExp, table *Table, asTable string, params map[string]interface{}) map[uint32][]*typedValueRange {
+	switch e := exp.(type) {
+	case *BinBoolExp:
+		lUnions := selectorRangeUnions(e.left, table, asTable, params)
+		rUnions := selectorRangeUnions(e.right, table, asTable, params)
+
+		if e.op == And {
+			for colID, rr := range rUnions {
+				lr, ok := lUnions[colID]
+				if !ok {
+					lUnions[colID] = rr
+					continue
+				}
+
+				ranges, ok := intersectRanges(lr, rr)
+				if !ok {
+					delete(lUnions, colID)
+					continue
+				}
+				lUnions[colID] = ranges
+			}
+			return lUnions
+		}
+
+		unions := make(map[uint32][]*typedValueRange)
+
+		// columns not constrained by both sides of a disjunction may hold any value
+		for colID, lr := range lUnions {
+			rr, ok := rUnions[colID]
+			if !ok {
+				continue
+			}
+
+			ranges, ok := mergeRanges(append(lr, rr...))
+			if ok {
+				unions[colID] = ranges
+			}
+		}
+		return unions
+	case *InListExp:
+		unions := make(map[uint32][]*typedValueRange)
+
+		colID, ranges, ok := e.valueRanges(table, asTable, params)
+		if ok {
+			ranges, ok = mergeRanges(ranges)
+		}
+		if ok {
+			unions[colID] = ranges
+		}
+		return unions
+	}
+
+	rangesByColID := make(map[uint32]*typedValueRange)
+
+	err := exp.selectorRanges(table, asTable, params, rangesByColID)
+	if err != nil {
+		return make(map[uint32][]*typedValueRange)
+	}
+
+	unions := make(map[uint32][]*typedValueRange, len(rangesByColID))
+	for colID, r := range rangesByColID {
+		unions[colID] = []*typedValueRange{r}
+	}
+	return unions
+}
+
+// rangeUnionFor returns the ranges to be scanned over the given index, they're only determined when
+// more than one range is needed on a column preceded by columns of a single value in the index,
+// thus rows are read following the order of the index by scanning the ranges in sequence
+func rangeUnionFor(index *Index, rangesByColID map[uint32]*typedValueRange, unions map[uint32][]*typedValueRange) (colID uint32, ranges []*typedValueRange) {
+	for _, col := range index.cols {
+		colRange, ok := rangesByColID[col.id]
+		if !ok {
+			return 0, nil
+		}
+
+		if colRange.unitary() {
+			continue
+		}
+
+		ranges := unions[col.id]
+		if len(ranges) < 2 {
+			return 0, nil
+		}
+
+		return col.id, ranges
+	}
+	return 0, nil
+}
+
+// rangeUnionKeyReaderSpecs returns the specs to read each range of the union in the order of the scan
+func rangeUnionKeyReaderSpecs(sqlPrefix []byte, table *Table, scanSpecs *ScanSpecs) ([]*store.KeyReaderSpec, error) {
+	specs := make([]*store.KeyReaderSpec, len(scanSpecs.rangeUnion))
+
+	for i, r := range scanSpecs.rangeUnion {
+		rangesByColID := make(map[uint32]*typedValueRange, len(scanSpecs.rangesByColID))
+		for colID, colRange := range scanSpecs.rangesByColID {
+			rangesByColID[colID] = colRange
+		}
+		rangesByColID[scanSpecs.rangeUnionColID] = r
+
+		rangeScanSpecs := *scanSpecs
+		rangeScanSpecs.rangesByColID = rangesByColID
+
+		spec, err := keyReaderSpecFrom(sqlPrefix, table, &rangeScanSpecs)
+		if err != nil {
+			return nil, err
+		}
+
+		if scanSpecs.DescOrder {
+			specs[len(specs)-1-i] = spec
+		} else {
+			specs[i] = spec
+		}
+	}
+
+	return specs, nil
+}
+
+// mergeRanges sorts the ranges and merges the overlapping ones,
+// false is returned when the values of the ranges can not be compared
+func mergeRanges(ranges []*typedValueRange) ([]*typedValueRange, bool) {
+	if len(ranges) == 0 || len(ranges) > maxRangeUnionSize {
+		return nil, false
+	}
+
+	sorted := make([]*typedValueRange, len(ranges))
+	copy(sorted, ranges)
+
+	var cmpErr error
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		res, err := compareLowerBounds(sorted[i].lRange, sorted[j].lRange)
+		if err != nil {
+			cmpErr = err
+		}
+		return res < 0
+	})
+	if cmpErr != nil {
+		return nil, false
+	}
+
+	merged := []*typedValueRange{{lRange: sorted[0].lRange, hRange: sorted[0].hRange}}
+
+	for _, r := range sorted[1:] {
+		curr := merged[len(merged)-1]
+
+		overlaps, err := curr.overlapsWith(r)
+		if err != nil {
+			return nil, false
+		}
+
+		if !overlaps {
+			merged = append(merged, &typedValueRange{lRange: r.lRange, hRange: r.hRange})
+			continue
+		}
+
+		if curr.hRange == nil || r.hRange == nil {
+			curr.hRange = nil
+			continue
+		}
+
+		res, err := curr.hRange.val.Compare(r.hRange.val)
+		if err != nil {
+			return nil, false
+		}
+
+		if res < 0 || (res == 0 && r.hRange.inclusive) {
+			curr.hRange = r.hRange
+		}
+	}
+
+	return merged, true
+}
+
+// intersectRanges returns the non-empty intersections between the ranges of both sets,
+// false is returned when the values of the ranges can not be compared or none intersect
+func intersectRanges(ranges1, ranges2 []*typedValueRange) ([]*typedValueRange, bool) {
+	if len(ranges1)*len(ranges2) > maxRangeUnionSize {
+		return nil, false
+	}
+
+	var ranges []*typedValueRange
+
+	for _, r1 := range ranges1 {
+		for _, r2 := range ranges2 {
+			r, err := intersectRange(r1, r2)
+			if err != nil {
+				return nil, false
+			}
+
+			empty, err := r.empty()
+			if err != nil {
+				return nil, false
+			}
+
+			if !empty {
+				ranges = append(ranges, r)
+			}
+		}
+	}
+
+	if len(ranges) == 0 {
+		return nil, false
+	}
+
+	return mergeRanges(ranges)
+}
+
+func intersectRange(r1, r2 *typedValueRange) (*typedValueRange, error) {
+	r := &typedValueRange{lRange: r1.lRange, hRange: r1.hRange}
+
+	if r.lRange == nil {
+		r.lRange = r2.lRange
+	} else if r2.lRange != nil {
+		res, err := compareLowerBounds(r.lRange, r2.lRange)
+		if err != nil {
+			return nil, err
+		}
+		if res < 0 {
+			r.lRange = r2.lRange
+		}
+	}
+
+	if r.hRange == nil {
+		r.hRange = r2.hRange
+	} else if r2.hRange != nil {
+		res, err := r.hRange.val.Compare(r2.hRange.val)
+		if err != nil {
+			return nil, err
+		}
+		if res > 0 || (res == 0 && !r2.hRange.inclusive) {
+			r.hRange = r2.hRange
+		}
+	}
+
+	return r, nil
+}
+
+// compareLowerBounds orders lower bounds, an unbounded one being the lowest
+func compareLowerBounds(l1, l2 *typedValueSemiRange) (int, error) {
+	if l1 == nil || l2 == nil {
+		switch {
+		case l1 == l2:
+			return 0, nil
+		case l1 == nil:
+			return -1, nil
+		default:
+			return 1, nil
+		}
+	}
+
+	res, err := l1.val.Compare(l2.val)
+	if err != nil || res != 0 || l1.inclusive == l2.inclusive {
+		return res, err
+	}
+
+	if l1.inclusive {
+		return -1, nil
+	}
+	return 1, nil
+}
+
+// overlapsWith tells if both ranges share any value or are contiguous,
+// the given range must not start before the receiver does
+func (r *typedValueRange) overlapsWith(r1 *typedValueRange) (bool, error) {
+	if r.hRange == nil || r1.lRange == nil {
+		return true, nil
+	}
+
+	res, err := r1.lRange.val.Compare(r.hRange.val)
+	if err != nil {
+		return false, err
+	}
+
+	return res < 0 || (res == 0 && (r1.lRange.inclusive || r.hRange.inclusive)), nil
+}
+
+func (r *typedValueRange) empty() (bool, error) {
+	if r.lRange == nil || r.hRange == nil {
+		return false, nil
+	}
+
+	res, err := r.lRange.val.Compare(r.hRange.val)
+	if err != nil {
+		return false, err
+	}
+
+	return res > 0 || (res == 0 && !(r.lRange.inclusive && r.hRange.inclusive)), nil
+}
+
+// multiRangeKeyReader reads the entries within each range in sequence,
+// the reader of each range is created once the previous one is exhausted
+type multiRangeKeyReader struct {
+	tx    *SQLTx
+	specs []*store.KeyReaderSpec
+
+	curr   int
+	reader store.KeyReader
+}
+
+func newMultiRangeKeyReader(tx *SQLTx, specs []*store.KeyReaderSpec) *multiRangeKeyReader {
+	return &multiRangeKeyReader{
+		tx:    tx,
+		specs: specs,
+	}
+}
+
+func (r *multiRangeKeyReader) Read(ctx context.Context) (key []byte, val store.ValueRef, err error) {
+	return r.read(func(reader store.KeyReader) ([]byte, store.ValueRef, error) {
+		return reader.Read(ctx)
+	})
+}
+
+func (r *multiRangeKeyReader) ReadBetween(ctx context.Context, initialTxID uint64, finalTxID uint64) (key []byte, val store.ValueRef, err error) {
+	return r.read(func(reader store.KeyReader) ([]byte, store.ValueRef, error) {
+		return reader.ReadBetween(ctx, initialTxID, finalTxID)
+	})
+}
+
+func (r *multiRangeKeyReader) read(readFn func(reader store.KeyReader) ([]byte, store.ValueRef, error)) ([]byte, store.ValueRef, error) {
+	for r.curr < len(r.specs) {
+		if r.reader == nil {
+			reader, err := r.tx.newKeyReader(*r.specs[r.curr])
+			if err != nil {
+				return nil, nil, err
+			}
+			r.reader = reader
+		}
+
+		key, val, err := readFn(r.reader)
+		if !errors.Is(err, store.ErrNoMoreEntries) {
+			return key, val, err
+		}
+
+		err = r.reader.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		r.reader = nil
+		r.curr++
+	}
+
+	return nil, nil, store.ErrNoMoreEntries
+}
+
+func (r *multiRangeKeyReader) Reset() error {
+	err := r.Close()
+	if err != nil {
+		return err
+	}
+
+	r.curr = 0
+	return nil
+}
+
+func (r *multiRangeKeyReader) Close() error {
+	if r.reader == nil {
+		return nil
+	}
+
+	err := r.reader.Close()
+	r.reader = nil
+	return err
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"iter"
+	"time"
+)
+
+// TimeoutRowReader lets rows be waited for up to a given time, e.g. when tailing the results of a query.
+// A read not completed within the timeout keeps going in the background with the context it was started
+// with, and its row is returned by the next call to Read, Peek or ReadWithTimeout.
+type TimeoutRowReader struct {
+	RowReader
+
+	pending chan readResult
+	ready   *readResult
+
+	closed bool
+}
+
+type readResult struct {
+	row *Row
+	err error
+}
+
+// NewTimeoutRowReader wraps the reader, which must not be read from directly afterwards
+func NewTimeoutRowReader(rowReader RowReader) *TimeoutRowReader {
+	return &TimeoutRowReader{RowReader: rowReader}
+}
+
+// ReadWithTimeout returns the next row, or ErrReadTimeout when it isn't read within the timeout,
+// in which case the reader remains usable and the row may be read by a subsequent call
+func (r *TimeoutRowReader) ReadWithTimeout(ctx context.Context, timeout time.Duration) (*Row, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	res, err := r.await(ctx, timer.C)
+	if err != nil {
+		return nil, err
+	}
+
+	r.ready = nil
+	return res.row, res.err
+}
+
+func (r *TimeoutRowReader) Read(ctx context.Context) (*Row, error) {
+	res, err := r.await(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	r.ready = nil
+	return res.row, res.err
+}
+
+func (r *TimeoutRowReader) Peek(ctx context.Context) (*Row, error) {
+	res, err := r.await(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return res.row, res.err
+}
+
+// await waits for the next row to be read, starting to read it when there is no read in progress.
+// A nil timeout channel waits until the row is read or the context is done.
+func (r *TimeoutRowReader) await(ctx context.Context, timeout <-chan time.Time) (*readResult, error) {
+	if r.closed {
+		return nil, ErrReaderClosed
+	}
+
+	if r.ready != nil {
+		return r.ready, nil
+	}
+
+	if r.pending == nil {
+		pending := make(chan readResult, 1)
+
+		go func() {
+			row, err := r.RowReader.Read(ctx)
+			pending <- readResult{row: row, err: err}
+		}()
+
+		r.pending = pending
+	}
+
+	select {
+	case res := <-r.pending:
+		r.pending = nil
+		r.ready = &res
+		return r.ready, nil
+	case <-timeout:
+		return nil, ErrReadTimeout
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (r *TimeoutRowReader) All(ctx context.Context) iter.Seq2[*Row, error] {
+	return allRows(ctx, r)
+}
+
+// Close waits for the read in progress, if any, as readers are not safe for concurrent use
+func (r *TimeoutRowReader) Close() error {
+	if r.closed {
+		return ErrAlreadyClosed
+	}
+
+	if r.pending != nil {
+		<-r.pending
+		r.pending = nil
+	}
+
+	r.closed = true
+	r.ready = nil
+
+	return r.RowReader.Close()
+}
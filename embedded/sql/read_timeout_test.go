@@ -0,0 +1,153 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// slowRowReader returns each row once it gets released
+type slowRowReader struct {
+	mockRowReader
+	release chan struct{}
+	closed  bool
+}
+
+func (r *slowRowReader) Read(ctx context.Context) (*Row, error) {
+	select {
+	case <-r.release:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	return r.mockRowReader.Read(ctx)
+}
+
+func (r *slowRowReader) Close() error {
+	r.closed = true
+	return nil
+}
+
+func TestTimeoutRowReader(t *testing.T) {
+	newReader := func() (*TimeoutRowReader, *slowRowReader) {
+		slow := &slowRowReader{
+			mockRowReader: mockRowReader{rows: []*Row{
+				{ValuesByPosition: []TypedValue{NewInteger(1)}},
+				{ValuesByPosition: []TypedValue{NewInteger(2)}},
+			}},
+			release: make(chan struct{}),
+		}
+		return NewTimeoutRowReader(slow), slow
+	}
+
+	t.Run("rows not read within the timeout are returned by the next read", func(t *testing.T) {
+		r, slow := newReader()
+
+		_, err := r.ReadWithTimeout(context.Background(), 10*time.Millisecond)
+		require.ErrorIs(t, err, ErrReadTimeout)
+		require.Equal(t, ErrCodeNotFound, ErrorCodeOf(err))
+
+		slow.release <- struct{}{}
+
+		row, err := r.ReadWithTimeout(context.Background(), time.Second)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), row.ValuesByPosition[0].RawValue())
+
+		_, err = r.ReadWithTimeout(context.Background(), 10*time.Millisecond)
+		require.ErrorIs(t, err, ErrReadTimeout)
+
+		go func() { slow.release <- struct{}{} }()
+
+		row, err = r.Peek(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, int64(2), row.ValuesByPosition[0].RawValue())
+
+		row, err = r.Read(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, int64(2), row.ValuesByPosition[0].RawValue())
+
+		close(slow.release)
+
+		_, err = r.ReadWithTimeout(context.Background(), time.Second)
+		require.ErrorIs(t, err, ErrNoMoreRows)
+
+		require.NoError(t, r.Close())
+		require.True(t, slow.closed)
+
+		_, err = r.ReadWithTimeout(context.Background(), time.Second)
+		require.ErrorIs(t, err, ErrReaderClosed)
+		require.ErrorIs(t, r.Close(), ErrAlreadyClosed)
+	})
+
+	t.Run("cancelling the wait", func(t *testing.T) {
+		r, slow := newReader()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, err := r.ReadWithTimeout(ctx, time.Second)
+		require.ErrorIs(t, err, context.Canceled)
+
+		// the read was started with the cancelled context
+		_, err = r.Read(context.Background())
+		require.ErrorIs(t, err, context.Canceled)
+
+		_, err = r.ReadWithTimeout(context.Background(), 10*time.Millisecond)
+		require.ErrorIs(t, err, ErrReadTimeout)
+
+		// closing waits for the read in progress
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			slow.release <- struct{}{}
+		}()
+
+		require.NoError(t, r.Close())
+		require.True(t, slow.closed)
+	})
+
+	t.Run("query results", func(t *testing.T) {
+		engine := setupCommonTest(t)
+
+		_, _, err := engine.Exec(context.Background(), nil, `
+			CREATE TABLE t (id INTEGER AUTO_INCREMENT, PRIMARY KEY id);
+			INSERT INTO t (id) VALUES (1), (2), (3);
+		`, nil)
+		require.NoError(t, err)
+
+		qr, err := engine.Query(context.Background(), nil, "SELECT id FROM t", nil)
+		require.NoError(t, err)
+
+		r := NewTimeoutRowReader(qr)
+
+		cols, err := r.Columns(context.Background())
+		require.NoError(t, err)
+		require.Len(t, cols, 1)
+
+		var ids []int64
+		for row, err := range r.All(context.Background()) {
+			require.NoError(t, err)
+			ids = append(ids, row.ValuesByPosition[0].RawValue().(int64))
+		}
+		require.Equal(t, []int64{1, 2, 3}, ids)
+
+		_, err = r.Read(context.Background())
+		require.ErrorIs(t, err, ErrReaderClosed)
+	})
+}
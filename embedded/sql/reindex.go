@@ -0,0 +1,168 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/codenotary/immudb/embedded/store"
+)
+
+// ReindexStmt rebuilds the entries of an index, or of all the indexes of a table,
+// from the rows of the table.
+//
+// Entries of regular indexes are mapped from the rows by the store, thus they are rebuilt once
+// the transaction is committed, by replaying the transactions in which the rows were written.
+// Entries of full-text indexes are written by the engine, thus they are deleted and written
+// again within the transaction.
+type ReindexStmt struct {
+	fullText bool
+	table    string
+	cols     []string
+}
+
+func NewReindexStmt(table string, cols []string) *ReindexStmt {
+	return &ReindexStmt{table: table, cols: cols}
+}
+
+func (stmt *ReindexStmt) readOnly() bool {
+	return false
+}
+
+func (stmt *ReindexStmt) requiredPrivileges() []SQLPrivilege {
+	return []SQLPrivilege{SQLPrivilegeAlter}
+}
+
+func (stmt *ReindexStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
+	return nil
+}
+
+func (stmt *ReindexStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
+	table, err := tx.catalog.GetTableByName(stmt.table)
+	if err != nil {
+		return nil, err
+	}
+
+	indexes, err := stmt.indexes(table)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, index := range indexes {
+		if index.IsFullText() {
+			err = tx.reindexFullText(ctx, index)
+		} else {
+			err = tx.reindex(index)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return tx, nil
+}
+
+// indexes returns the indexes to be rebuilt, all the ones of the table if no column is specified
+func (stmt *ReindexStmt) indexes(table *Table) ([]*Index, error) {
+	if len(stmt.cols) == 0 {
+		return append(table.GetIndexes(), table.GetFullTextIndexes()...), nil
+	}
+
+	cols := make([]*Column, len(stmt.cols))
+
+	for i, colName := range stmt.cols {
+		col, err := table.GetColumnByName(colName)
+		if err != nil {
+			return nil, err
+		}
+		cols[i] = col
+	}
+
+	if stmt.fullText {
+		index := table.fullTextIndexByColID(cols[0].id)
+		if index == nil {
+			return nil, fmt.Errorf("%w (fulltext:%s)", ErrIndexNotFound, indexName(table.name, cols))
+		}
+		return []*Index{index}, nil
+	}
+
+	index, err := table.GetIndexByName(indexName(table.name, cols))
+	if err != nil {
+		return nil, err
+	}
+	return []*Index{index}, nil
+}
+
+func (tx *SQLTx) reindex(index *Index) error {
+	spec := indexSpecFor(index)
+
+	return tx.addOnCommittedCallback(func(sqlTx *SQLTx) error {
+		err := sqlTx.engine.store.RebuildIndex(spec)
+		if errors.Is(err, store.ErrIndexNotFound) {
+			// the index was created within the same transaction,
+			// it's built from scratch as soon as it's initialized
+			return nil
+		}
+		return err
+	})
+}
+
+func (tx *SQLTx) reindexFullText(ctx context.Context, index *Index) error {
+	prefix := MapKey(tx.sqlPrefix(), FullTextPrefix, EncodeID(index.table.id), EncodeID(index.id))
+
+	reader, err := tx.newKeyReader(store.KeyReaderSpec{
+		Prefix:  prefix,
+		Filters: []store.FilterFn{store.IgnoreExpired, store.IgnoreDeleted},
+	})
+	if err != nil {
+		return err
+	}
+
+	var keys [][]byte
+
+	for {
+		key, _, err := reader.Read(ctx)
+		if errors.Is(err, store.ErrNoMoreEntries) {
+			break
+		}
+		if err != nil {
+			reader.Close()
+			return err
+		}
+		keys = append(keys, key)
+	}
+
+	err = reader.Close()
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		md := store.NewKVMetadata()
+
+		md.AsDeleted(true)
+
+		err = tx.set(key, md, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	// entries of terms still present in the rows are written again
+	return tx.indexFullTextRows(ctx, index)
+}
@@ -0,0 +1,216 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReindex(t *testing.T) {
+	for _, d := range []struct {
+		sql  string
+		stmt *ReindexStmt
+	}{
+		{"REINDEX INDEX ON people (age, name)", &ReindexStmt{table: "people", cols: []string{"age", "name"}}},
+		{"REINDEX INDEX people.age", &ReindexStmt{table: "people", cols: []string{"age"}}},
+		{"REINDEX FULLTEXT INDEX ON docs (body)", &ReindexStmt{fullText: true, table: "docs", cols: []string{"body"}}},
+		{"REINDEX TABLE people", &ReindexStmt{table: "people"}},
+	} {
+		t.Run(d.sql, func(t *testing.T) {
+			stmts, err := ParseSQLString(d.sql)
+			require.NoError(t, err)
+			require.Equal(t, []SQLStmt{d.stmt}, stmts)
+		})
+	}
+
+	// REINDEX remains usable as an identifier
+	_, err := ParseSQLString("CREATE TABLE reindex (reindex INTEGER, PRIMARY KEY reindex)")
+	require.NoError(t, err)
+}
+
+func TestReindex(t *testing.T) {
+	dir := t.TempDir()
+
+	st, err := store.Open(dir, store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(t, err)
+
+	engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		CREATE TABLE people (id INTEGER AUTO_INCREMENT, name VARCHAR[32], age INTEGER, bio VARCHAR, PRIMARY KEY id);
+		CREATE FULLTEXT INDEX ON people (bio);
+	`, nil)
+	require.NoError(t, err)
+
+	for i := 0; i < 100; i++ {
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO people (name, age, bio) VALUES (@name, @age, @bio)", map[string]interface{}{
+			"name": fmt.Sprintf("person%02d", i),
+			"age":  i % 10,
+			"bio":  fmt.Sprintf("born in city%d", i%7),
+		})
+		require.NoError(t, err)
+	}
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		UPDATE people SET age = 42 WHERE id = 1;
+		DELETE FROM people WHERE id = 2;
+	`, nil)
+	require.NoError(t, err)
+
+	queryIDs := func(t *testing.T, e *Engine, sql string) []int64 {
+		rows, err := e.queryAll(context.Background(), nil, sql, nil)
+		require.NoError(t, err)
+
+		ids := make([]int64, len(rows))
+		for i, row := range rows {
+			ids[i] = row.ValuesByPosition[0].RawValue().(int64)
+		}
+		return ids
+	}
+
+	lookups := []string{
+		"SELECT id FROM people USE INDEX ON (age) WHERE age = 3 ORDER BY id",
+		"SELECT id FROM people USE INDEX ON (age) WHERE age >= 8 ORDER BY id",
+		"SELECT id FROM people WHERE age = 42",
+		"SELECT id FROM people WHERE age = 1",
+		"SELECT id FROM people WHERE bio MATCH 'city3' ORDER BY id",
+		"SELECT id FROM people ORDER BY id DESC LIMIT 5",
+	}
+
+	// results before the index on age is created are read from the primary index
+	expected := make([][]int64, len(lookups))
+	for i, sql := range lookups {
+		expected[i] = queryIDs(t, engine, strings.Replace(sql, " USE INDEX ON (age)", "", 1))
+	}
+
+	t.Run("creating an index on a populated table", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "CREATE INDEX ON people (age)", nil)
+		require.NoError(t, err)
+
+		r, err := engine.Query(context.Background(), nil, "SELECT id FROM people WHERE age = 3", nil)
+		require.NoError(t, err)
+		require.Equal(t, "age", r.ScanSpecs().Index.cols[0].colName)
+		require.NoError(t, r.Close())
+
+		for i, sql := range lookups {
+			require.Equal(t, expected[i], queryIDs(t, engine, sql), sql)
+		}
+
+		require.Equal(t, []int64{1}, queryIDs(t, engine, "SELECT id FROM people WHERE age = 42"))
+		require.Equal(t, []int64{4, 14, 24, 34, 44, 54, 64, 74, 84, 94}, queryIDs(t, engine, "SELECT id FROM people WHERE age = 3 ORDER BY id"))
+	})
+
+	t.Run("rebuilding an index", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "REINDEX INDEX ON people (age)", nil)
+		require.NoError(t, err)
+
+		for i, sql := range lookups {
+			require.Equal(t, expected[i], queryIDs(t, engine, sql), sql)
+		}
+	})
+
+	t.Run("rebuilding all the indexes of a table", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "REINDEX TABLE people", nil)
+		require.NoError(t, err)
+
+		for i, sql := range lookups {
+			require.Equal(t, expected[i], queryIDs(t, engine, sql), sql)
+		}
+	})
+
+	t.Run("rebuilding a full-text index", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "REINDEX FULLTEXT INDEX ON people (bio)", nil)
+		require.NoError(t, err)
+
+		for i, sql := range lookups {
+			require.Equal(t, expected[i], queryIDs(t, engine, sql), sql)
+		}
+		require.Empty(t, queryIDs(t, engine, "SELECT id FROM people WHERE bio MATCH 'city7'"))
+	})
+
+	t.Run("rebuilding within a transaction", func(t *testing.T) {
+		tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithExplicitClose(true))
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), tx, `
+			INSERT INTO people (name, age, bio) VALUES ('newcomer', 3, 'born in city3');
+			REINDEX INDEX people.age;
+			REINDEX FULLTEXT INDEX ON people (bio);
+		`, nil)
+		require.NoError(t, err)
+
+		// entries written by the transaction are visible before it's committed
+		rows, err := engine.queryAll(context.Background(), tx, "SELECT id FROM people WHERE bio MATCH 'city3' AND name = 'newcomer'", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		require.Equal(t, int64(101), rows[0].ValuesByPosition[0].RawValue())
+
+		require.NoError(t, tx.Commit(context.Background()))
+
+		require.Equal(t, append(expected[0], 101), queryIDs(t, engine, "SELECT id FROM people USE INDEX ON (age) WHERE age = 3 ORDER BY id"))
+		require.Equal(t, append(expected[4], 101), queryIDs(t, engine, "SELECT id FROM people WHERE bio MATCH 'city3' ORDER BY id"))
+
+		_, _, err = engine.Exec(context.Background(), nil, "DELETE FROM people WHERE id = 101", nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("rebuilding an index created within the same transaction", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, `
+			CREATE INDEX ON people (name);
+			REINDEX INDEX ON people (name);
+		`, nil)
+		require.NoError(t, err)
+
+		require.Equal(t, []int64{10}, queryIDs(t, engine, "SELECT id FROM people USE INDEX ON (name) WHERE name = 'person09'"))
+	})
+
+	t.Run("unknown indexes", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "REINDEX INDEX ON people (bio)", nil)
+		require.ErrorIs(t, err, ErrIndexNotFound)
+
+		_, _, err = engine.Exec(context.Background(), nil, "REINDEX FULLTEXT INDEX ON people (name)", nil)
+		require.ErrorIs(t, err, ErrIndexNotFound)
+
+		_, _, err = engine.Exec(context.Background(), nil, "REINDEX INDEX ON people (unknown)", nil)
+		require.ErrorIs(t, err, ErrColumnDoesNotExist)
+
+		_, _, err = engine.Exec(context.Background(), nil, "REINDEX TABLE unknown", nil)
+		require.ErrorIs(t, err, ErrTableDoesNotExist)
+	})
+
+	require.NoError(t, st.Close())
+
+	t.Run("reopening the engine", func(t *testing.T) {
+		st, err := store.Open(dir, store.DefaultOptions().WithMultiIndexing(true))
+		require.NoError(t, err)
+		defer closeStore(t, st)
+
+		engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+		require.NoError(t, err)
+
+		for i, sql := range lookups {
+			require.Equal(t, expected[i], queryIDs(t, engine, sql), sql)
+		}
+	})
+}
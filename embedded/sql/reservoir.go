@@ -0,0 +1,142 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"fmt"
+	"math/rand"
+)
+
+const RESERVOIR_SAMPLE AggregateFn = "RESERVOIR_SAMPLE"
+
+// maxReservoirSize bounds the number of values kept in memory by each sample
+const maxReservoirSize = 1 << 16
+
+// newReservoirSampleSelector returns the aggregation of a uniform random sample of up to n
+// values of col, taken in a single pass over the rows. Samples taken with the same seed
+// over the same rows are the same, otherwise a different sample is taken each time.
+// The size and the seed are part of the encoded selector, as percentiles are.
+func newReservoirSampleSelector(col *ColSelector, n uint64, seed *uint64) (*AggColSelector, error) {
+	if n < 1 || n > uint64(maxReservoirSize) {
+		return nil, fmt.Errorf("%w: sample size must be between 1 and %d", ErrIllegalArguments, maxReservoirSize)
+	}
+
+	fn := fmt.Sprintf("%s[%d]", RESERVOIR_SAMPLE, n)
+	if seed != nil {
+		fn = fmt.Sprintf("%s[%d,%d]", RESERVOIR_SAMPLE, n, *seed)
+	}
+
+	return &AggColSelector{
+		aggFn:     fn,
+		table:     col.table,
+		col:       col.col,
+		aggregate: &userAggregate{name: fn, spec: reservoirSample(int(n), seed)},
+	}, nil
+}
+
+// reservoir holds the values sampled out of the ones seen so far
+type reservoir struct {
+	rnd    *rand.Rand
+	seen   int64
+	values []TypedValue
+}
+
+// add implements Algorithm R: the i-th value replaces a random sampled one with probability n/i
+func (r *reservoir) add(val TypedValue, n int) {
+	r.seen++
+
+	if len(r.values) < n {
+		r.values = append(r.values, val)
+		return
+	}
+
+	if j := r.rnd.Int63n(r.seen); j < int64(n) {
+		r.values[j] = val
+	}
+}
+
+// merge combines two samples by drawing each value from either of them with a probability
+// proportional to the number of values they were taken from
+func (r *reservoir) merge(other *reservoir, n int) {
+	a, b := r.seen, other.seen
+
+	left, right := r.values, other.values
+	merged := make([]TypedValue, 0, min(n, len(left)+len(right)))
+
+	for len(merged) < cap(merged) {
+		from := &right
+		if len(*from) == 0 || (len(left) > 0 && r.rnd.Int63n(a+b) < a) {
+			from = &left
+			a--
+		} else {
+			b--
+		}
+
+		i := r.rnd.Intn(len(*from))
+		merged = append(merged, (*from)[i])
+
+		(*from)[i] = (*from)[len(*from)-1]
+		*from = (*from)[:len(*from)-1]
+	}
+
+	r.seen += other.seen
+	r.values = merged
+}
+
+func reservoirSample(n int, seed *uint64) AggregateFunc {
+	return AggregateFunc{
+		ArgType:    AnyType,
+		ReturnType: JSONType,
+		Init: func() interface{} {
+			src := rand.NewSource(rand.Int63())
+			if seed != nil {
+				src = rand.NewSource(int64(*seed))
+			}
+			return &reservoir{rnd: rand.New(src)}
+		},
+		Accumulate: func(state interface{}, val TypedValue) (interface{}, error) {
+			r := state.(*reservoir)
+			r.add(val, n)
+			return r, nil
+		},
+		Merge: func(state, other interface{}) (interface{}, error) {
+			r := state.(*reservoir)
+			r.merge(other.(*reservoir), n)
+			return r, nil
+		},
+		Finalize: func(state interface{}) (TypedValue, error) {
+			r := state.(*reservoir)
+			if r.seen == 0 {
+				return nil, nil
+			}
+
+			sample := make([]interface{}, len(r.values))
+			for i, val := range r.values {
+				sample[i] = jsonSampleValue(val)
+			}
+			return NewJson(sample), nil
+		},
+	}
+}
+
+func jsonSampleValue(val TypedValue) interface{} {
+	switch val.Type() {
+	case IntegerType, Float64Type, BooleanType, VarcharType, JSONType:
+		return val.RawValue()
+	}
+	return val.String()
+}
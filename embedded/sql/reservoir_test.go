@@ -0,0 +1,88 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestReservoirSample(t *testing.T) {
+	const (
+		values = 50
+		n      = 5
+		runs   = 10000
+	)
+
+	sample := func(t *testing.T, seed uint64, parts int) []interface{} {
+		spec := reservoirSample(n, &seed)
+
+		var state interface{}
+
+		for p := 0; p < parts; p++ {
+			partial := spec.Init()
+
+			for i := p; i < values; i += parts {
+				var err error
+
+				partial, err = spec.Accumulate(partial, &Integer{val: int64(i)})
+				require.NoError(t, err)
+			}
+
+			if state == nil {
+				state = partial
+				continue
+			}
+
+			var err error
+
+			state, err = spec.Merge(state, partial)
+			require.NoError(t, err)
+		}
+
+		val, err := spec.Finalize(state)
+		require.NoError(t, err)
+
+		return val.RawValue().([]interface{})
+	}
+
+	for _, parts := range []int{1, 3} {
+		counts := make(map[int64]int, values)
+
+		for seed := uint64(0); seed < runs; seed++ {
+			s := sample(t, seed, parts)
+			require.Len(t, s, n)
+
+			distinct := make(map[int64]struct{}, n)
+			for _, v := range s {
+				counts[v.(int64)]++
+				distinct[v.(int64)] = struct{}{}
+			}
+			require.Len(t, distinct, n)
+		}
+
+		// each value is expected to be sampled runs*n/values = 1000 times, with a standard deviation of ~30
+		require.Len(t, counts, values)
+		for v, c := range counts {
+			require.InDelta(t, runs*n/values, c, 150, "value %d sampled %d times over %d parts", v, c, parts)
+		}
+	}
+
+	require.Equal(t, sample(t, 1, 1), sample(t, 1, 1))
+	require.NotEqual(t, sample(t, 1, 1), sample(t, 2, 1))
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+)
+
+// ResourceLimits caps the resources used by each statement, zero values mean no limit
+type ResourceLimits struct {
+	// MaxMemory is the approximate number of bytes held by the rows being sorted
+	// and by the rows already returned by DISTINCT clauses
+	MaxMemory int64
+	// MaxRowsScanned is the maximum number of rows read from tables
+	MaxRowsScanned int64
+	// MaxExecutionTime is the maximum time since the statement started. It includes
+	// the time spent by the caller between reads, as rows are produced while being read
+	MaxExecutionTime time.Duration
+}
+
+func (limits ResourceLimits) Validate() error {
+	if limits.MaxMemory < 0 || limits.MaxRowsScanned < 0 || limits.MaxExecutionTime < 0 {
+		return fmt.Errorf("%w: invalid ResourceLimits value", store.ErrInvalidOptions)
+	}
+	return nil
+}
+
+func (limits ResourceLimits) unlimited() bool {
+	return limits == ResourceLimits{}
+}
+
+type resourceLimitsKey struct{}
+
+// ContextWithResourceLimits returns a context making the statements executed with it
+// to be subject to the given limits instead of the ones specified for the engine
+func ContextWithResourceLimits(ctx context.Context, limits ResourceLimits) context.Context {
+	return context.WithValue(ctx, resourceLimitsKey{}, limits)
+}
+
+// resourceTracker accounts for the resources used by a statement. A nil tracker doesn't enforce any limit.
+// Readers get the tracker of the statement they were resolved for, and are read by a single goroutine.
+type resourceTracker struct {
+	limits   ResourceLimits
+	clock    func() time.Time
+	deadline time.Time
+
+	rowsScanned int64
+	memory      int64
+}
+
+func (e *Engine) newResourceTracker(ctx context.Context) (*resourceTracker, error) {
+	limits := e.resourceLimits
+
+	if l, ok := ctx.Value(resourceLimitsKey{}).(ResourceLimits); ok {
+		err := l.Validate()
+		if err != nil {
+			return nil, err
+		}
+		limits = l
+	}
+
+	if limits.unlimited() {
+		return nil, nil
+	}
+
+	clock := e.clock
+	if clock == nil {
+		clock = time.Now
+	}
+
+	rt := &resourceTracker{
+		limits: limits,
+		clock:  clock,
+	}
+
+	if limits.MaxExecutionTime > 0 {
+		rt.deadline = clock().Add(limits.MaxExecutionTime)
+	}
+	return rt, nil
+}
+
+// scanned accounts for a row read from a table
+func (rt *resourceTracker) scanned() error {
+	if rt == nil {
+		return nil
+	}
+
+	rt.rowsScanned++
+
+	if rt.limits.MaxRowsScanned > 0 && rt.rowsScanned > rt.limits.MaxRowsScanned {
+		return fmt.Errorf("%w: more than %d rows scanned", ErrMaxRowsScannedExceeded, rt.limits.MaxRowsScanned)
+	}
+
+	if !rt.deadline.IsZero() && rt.clock().After(rt.deadline) {
+		return fmt.Errorf("%w: statement running for more than %s", ErrMaxExecutionTimeExceeded, rt.limits.MaxExecutionTime)
+	}
+	return nil
+}
+
+func (rt *resourceTracker) allocate(size int64) error {
+	if rt == nil {
+		return nil
+	}
+
+	rt.memory += size
+
+	if rt.limits.MaxMemory > 0 && rt.memory > rt.limits.MaxMemory {
+		return fmt.Errorf("%w: more than %d bytes in use", ErrMaxMemoryExceeded, rt.limits.MaxMemory)
+	}
+	return nil
+}
+
+// allocateRow accounts for a row held in memory, returning the size to be released
+func (rt *resourceTracker) allocateRow(row *Row) (int64, error) {
+	if rt == nil {
+		return 0, nil
+	}
+
+	size := rowMemSize(row)
+	return size, rt.allocate(size)
+}
+
+func (rt *resourceTracker) release(size int64) {
+	if rt != nil {
+		rt.memory -= size
+	}
+}
+
+// distinctEntrySize approximates the memory taken by each digest kept by DISTINCT clauses
+const distinctEntrySize = sha256.Size + 16
+
+// rowMemSize approximates the memory taken by the values of a row
+func rowMemSize(row *Row) int64 {
+	size := int64(0)
+
+	for _, v := range row.ValuesByPosition {
+		size += 16
+
+		switch rv := v.RawValue().(type) {
+		case string:
+			size += int64(len(rv))
+		case []byte:
+			size += int64(len(rv))
+		case int64, float64, bool, time.Time:
+			size += 8
+		default:
+			if v.Type() == JSONType {
+				size += int64(len(v.String()))
+			} else {
+				size += 16
+			}
+		}
+	}
+	return size
+}
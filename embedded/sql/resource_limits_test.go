@@ -0,0 +1,214 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+)
+
+func setupResourceLimitsTest(t *testing.T, opts *Options) *Engine {
+	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(t, err)
+	t.Cleanup(func() { closeStore(t, st) })
+
+	engine, err := NewEngine(st, opts.WithPrefix(sqlPrefix))
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE t (id INTEGER AUTO_INCREMENT, n INTEGER, s VARCHAR, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	for i := 0; i < 30; i++ {
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO t (n, s) VALUES (@n, @s)", map[string]interface{}{
+			"n": i,
+			"s": strings.Repeat("x", 100) + string(rune('a'+i%26)),
+		})
+		require.NoError(t, err)
+	}
+	return engine
+}
+
+// requireLimitExceeded checks the error identifies the breached limit and no other one
+func requireLimitExceeded(t *testing.T, err, limitErr error) {
+	require.ErrorIs(t, err, ErrResourceLimitExceeded)
+	require.Equal(t, ErrCodeInvalid, ErrorCodeOf(err))
+
+	for _, e := range []error{ErrMaxMemoryExceeded, ErrMaxRowsScannedExceeded, ErrMaxExecutionTimeExceeded} {
+		require.Equal(t, e == limitErr, errors.Is(err, e), err.Error())
+	}
+}
+
+func TestResourceLimitsValidation(t *testing.T) {
+	for _, limits := range []ResourceLimits{
+		{MaxMemory: -1},
+		{MaxRowsScanned: -1},
+		{MaxExecutionTime: -time.Second},
+	} {
+		err := DefaultOptions().WithResourceLimits(limits).Validate()
+		require.ErrorIs(t, err, store.ErrInvalidOptions)
+	}
+
+	engine := setupResourceLimitsTest(t, DefaultOptions())
+
+	ctx := ContextWithResourceLimits(context.Background(), ResourceLimits{MaxRowsScanned: -1})
+
+	_, err := engine.Query(ctx, nil, "SELECT id FROM t", nil)
+	require.ErrorIs(t, err, store.ErrInvalidOptions)
+
+	_, _, err = engine.Exec(ctx, nil, "DELETE FROM t WHERE n > 10", nil)
+	require.ErrorIs(t, err, store.ErrInvalidOptions)
+}
+
+func TestMaxRowsScanned(t *testing.T) {
+	engine := setupResourceLimitsTest(t, DefaultOptions().WithResourceLimits(ResourceLimits{MaxRowsScanned: 10}))
+
+	t.Run("scanning stops once the limit is exceeded", func(t *testing.T) {
+		r, err := engine.Query(context.Background(), nil, "SELECT id FROM t WHERE n < 0", nil)
+		require.NoError(t, err)
+		defer r.Close()
+
+		cr := r.(*projectedRowReader).rowReader.(*conditionalRowReader)
+		counter := &countingRowReader{RowReader: cr.rowReader}
+		cr.rowReader = counter
+
+		_, err = r.Read(context.Background())
+		requireLimitExceeded(t, err, ErrMaxRowsScannedExceeded)
+		require.Equal(t, 10, counter.reads)
+
+		_, err = r.Read(context.Background())
+		requireLimitExceeded(t, err, ErrMaxRowsScannedExceeded)
+		require.Equal(t, 10, counter.reads)
+	})
+
+	t.Run("queries within the limit", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT id FROM t LIMIT 10", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 10)
+
+		// only the rows within the range of the primary key are scanned
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT id FROM t WHERE id > 25", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 5)
+	})
+
+	t.Run("rows skipped by OFFSET are scanned", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, "SELECT id FROM t LIMIT 1 OFFSET 15", nil)
+		requireLimitExceeded(t, err, ErrMaxRowsScannedExceeded)
+	})
+
+	t.Run("rows are accounted per statement", func(t *testing.T) {
+		tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithExplicitClose(true))
+		require.NoError(t, err)
+		defer tx.Cancel()
+
+		for i := 0; i < 3; i++ {
+			rows, err := engine.queryAll(context.Background(), tx, "SELECT id FROM t LIMIT 8", nil)
+			require.NoError(t, err)
+			require.Len(t, rows, 8)
+		}
+	})
+
+	t.Run("statements executed by Exec are limited", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "UPDATE t SET n = n + 1 WHERE n > 20", nil)
+		requireLimitExceeded(t, err, ErrMaxRowsScannedExceeded)
+	})
+
+	t.Run("limits are overridden by the context", func(t *testing.T) {
+		ctx := ContextWithResourceLimits(context.Background(), ResourceLimits{})
+
+		rows, err := engine.queryAll(ctx, nil, "SELECT id FROM t WHERE n < 0", nil)
+		require.NoError(t, err)
+		require.Empty(t, rows)
+
+		ctx = ContextWithResourceLimits(context.Background(), ResourceLimits{MaxRowsScanned: 2})
+
+		_, err = engine.queryAll(ctx, nil, "SELECT id FROM t LIMIT 3", nil)
+		requireLimitExceeded(t, err, ErrMaxRowsScannedExceeded)
+	})
+}
+
+func TestMaxMemory(t *testing.T) {
+	limits := ResourceLimits{MaxMemory: 1000}
+
+	t.Run("sorted rows held in memory", func(t *testing.T) {
+		engine := setupResourceLimitsTest(t, DefaultOptions().WithResourceLimits(limits))
+
+		_, err := engine.queryAll(context.Background(), nil, "SELECT id, s FROM t ORDER BY s", nil)
+		requireLimitExceeded(t, err, ErrMaxMemoryExceeded)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT id FROM t ORDER BY id DESC", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 30)
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT id, s FROM t WHERE n < 3 ORDER BY s", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+	})
+
+	t.Run("rows written to temporary files are released", func(t *testing.T) {
+		engine := setupResourceLimitsTest(t, DefaultOptions().WithResourceLimits(limits).WithSortBufferSize(4))
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT id, s FROM t ORDER BY s", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 30)
+	})
+
+	t.Run("rows returned by DISTINCT", func(t *testing.T) {
+		engine := setupResourceLimitsTest(t, DefaultOptions())
+
+		ctx := ContextWithResourceLimits(context.Background(), ResourceLimits{MaxMemory: 10 * distinctEntrySize})
+
+		rows, err := engine.queryAll(ctx, nil, "SELECT DISTINCT n % 10 FROM t", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 10)
+
+		_, err = engine.queryAll(ctx, nil, "SELECT DISTINCT n % 11 FROM t", nil)
+		requireLimitExceeded(t, err, ErrMaxMemoryExceeded)
+	})
+}
+
+func TestMaxExecutionTime(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+
+	engine := setupResourceLimitsTest(t, DefaultOptions().WithClock(clock).WithResourceLimits(ResourceLimits{MaxExecutionTime: time.Minute}))
+
+	r, err := engine.Query(context.Background(), nil, "SELECT id FROM t", nil)
+	require.NoError(t, err)
+	defer r.Close()
+
+	for i := 0; i < 5; i++ {
+		_, err := r.Read(context.Background())
+		require.NoError(t, err)
+	}
+
+	now = now.Add(time.Minute + time.Second)
+
+	_, err = r.Read(context.Background())
+	requireLimitExceeded(t, err, ErrMaxExecutionTimeExceeded)
+
+	// the time of each statement is measured from its start
+	rows, err := engine.queryAll(context.Background(), nil, "SELECT id FROM t", nil)
+	require.NoError(t, err)
+	require.Len(t, rows, 30)
+}
@@ -0,0 +1,322 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+)
+
+// DiffOpts determines how result sets are compared by DiffResultsWithOpts
+type DiffOpts struct {
+	// KeyColumns are the names of the columns identifying each row in both result sets, so rows
+	// with the same key but different values are reported as changed. When not specified, rows
+	// are identified by all their values, thus they are only reported as added or removed
+	KeyColumns []string
+	// IgnoreOrder compares the result sets regardless of the order of their rows, which are then
+	// buffered and sorted by key. Otherwise, rows are compared as they are read, and both result
+	// sets must be ordered by key, as rows out of order are reported as added and removed
+	IgnoreOrder bool
+	// DescOrder is set when the result sets are ordered by key in descending order
+	DescOrder bool
+}
+
+func DefaultDiffOpts() DiffOpts {
+	return DiffOpts{}
+}
+
+// ResultDiff describes the differences between two result sets
+type ResultDiff struct {
+	// Columns are the columns of the first result set
+	Columns []ColDescriptor
+	// Added are the rows of the second result set with no counterpart in the first one
+	Added []*Row
+	// Removed are the rows of the first result set with no counterpart in the second one
+	Removed []*Row
+	// Changed are the rows found in both result sets with different values
+	Changed []RowChange
+}
+
+// RowChange holds the values of a row in each result set
+type RowChange struct {
+	From *Row
+	To   *Row
+}
+
+// Empty reports whether both result sets hold the same rows
+func (d *ResultDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffResults compares the rows of two result sets as they are read, both ordered in ascending
+// order of their values, column by column. Rows are identified by all their values, using the
+// comparison semantics of typed values, under which NULL values are not distinct from each other.
+// Readers are not closed.
+func DiffResults(ctx context.Context, a, b RowReader) (*ResultDiff, error) {
+	return DiffResultsWithOpts(ctx, a, b, DefaultDiffOpts())
+}
+
+// DiffResultsWithOpts compares the rows of two result sets as determined by opts.
+// Both result sets must have the same columns, by name and position. Readers are not closed.
+func DiffResultsWithOpts(ctx context.Context, a, b RowReader, opts DiffOpts) (*ResultDiff, error) {
+	if a == nil || b == nil {
+		return nil, ErrIllegalArguments
+	}
+
+	cols, err := a.Columns(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	bCols, err := b.Columns(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slices.EqualFunc(cols, bCols, func(c1, c2 ColDescriptor) bool { return c1.Column == c2.Column }) {
+		return nil, fmt.Errorf("%w: result sets do not have the same columns", ErrIllegalArguments)
+	}
+
+	keyPositions, err := diffKeyPositions(cols, opts.KeyColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &rowsDiff{
+		keyPositions: keyPositions,
+		descOrder:    opts.DescOrder,
+		diff:         &ResultDiff{Columns: cols},
+	}
+
+	var ca, cb diffCursor
+
+	if opts.IgnoreOrder {
+		ca, err = d.sortedCursor(ctx, a, len(cols))
+		if err != nil {
+			return nil, err
+		}
+
+		cb, err = d.sortedCursor(ctx, b, len(cols))
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		ca = &readerCursor{ctx: ctx, reader: a, cols: len(cols)}
+		cb = &readerCursor{ctx: ctx, reader: b, cols: len(cols)}
+	}
+
+	err = d.merge(ca, cb)
+	if err != nil {
+		return nil, err
+	}
+	return d.diff, nil
+}
+
+func diffKeyPositions(cols []ColDescriptor, keyCols []string) ([]int, error) {
+	if len(keyCols) == 0 {
+		positions := make([]int, len(cols))
+		for i := range cols {
+			positions[i] = i
+		}
+		return positions, nil
+	}
+
+	positions := make([]int, len(keyCols))
+
+	for i, keyCol := range keyCols {
+		pos := slices.IndexFunc(cols, func(c ColDescriptor) bool { return c.Column == keyCol })
+		if pos < 0 {
+			return nil, fmt.Errorf("%w (%s)", ErrColumnDoesNotExist, keyCol)
+		}
+
+		if slices.IndexFunc(cols[pos+1:], func(c ColDescriptor) bool { return c.Column == keyCol }) >= 0 {
+			return nil, fmt.Errorf("%w: key column '%s' is ambiguous", ErrIllegalArguments, keyCol)
+		}
+
+		positions[i] = pos
+	}
+	return positions, nil
+}
+
+// diffCursor iterates over the rows of a result set, peek returns nil once all rows are consumed
+type diffCursor interface {
+	peek() (*Row, error)
+	advance()
+}
+
+type readerCursor struct {
+	ctx    context.Context
+	reader RowReader
+	cols   int
+	row    *Row
+	loaded bool
+}
+
+func (c *readerCursor) peek() (*Row, error) {
+	if c.loaded {
+		return c.row, nil
+	}
+
+	row, err := c.reader.Read(c.ctx)
+	if errors.Is(err, ErrNoMoreRows) {
+		row = nil
+	} else if err != nil {
+		return nil, err
+	} else if len(row.ValuesByPosition) != c.cols {
+		return nil, fmt.Errorf("%w: rows do not match the columns of the reader", ErrUnexpected)
+	}
+
+	c.row = row
+	c.loaded = true
+
+	return row, nil
+}
+
+func (c *readerCursor) advance() {
+	c.loaded = false
+}
+
+type sliceCursor struct {
+	rows []*Row
+}
+
+func (c *sliceCursor) peek() (*Row, error) {
+	if len(c.rows) == 0 {
+		return nil, nil
+	}
+	return c.rows[0], nil
+}
+
+func (c *sliceCursor) advance() {
+	c.rows = c.rows[1:]
+}
+
+type rowsDiff struct {
+	keyPositions []int
+	descOrder    bool
+	diff         *ResultDiff
+}
+
+func (d *rowsDiff) keyOf(row *Row) *Row {
+	vals := make([]TypedValue, len(d.keyPositions))
+	for i, pos := range d.keyPositions {
+		vals[i] = row.ValuesByPosition[pos]
+	}
+	return &Row{ValuesByPosition: vals}
+}
+
+// compareKeys orders the rows by key in the order of the result sets
+func (d *rowsDiff) compareKeys(r1, r2 *Row) (int, error) {
+	cmp, err := RowComparator{}.Compare(d.keyOf(r1), d.keyOf(r2))
+	if d.descOrder {
+		cmp = -cmp
+	}
+	return cmp, err
+}
+
+// sortedCursor reads all the rows of the result set and sorts them by key
+func (d *rowsDiff) sortedCursor(ctx context.Context, reader RowReader, cols int) (diffCursor, error) {
+	rc := &readerCursor{ctx: ctx, reader: reader, cols: cols}
+
+	var rows []*Row
+
+	for {
+		row, err := rc.peek()
+		if err != nil {
+			return nil, err
+		}
+		if row == nil {
+			break
+		}
+
+		rows = append(rows, row)
+		rc.advance()
+	}
+
+	var sortErr error
+
+	slices.SortStableFunc(rows, func(r1, r2 *Row) int {
+		cmp, err := d.compareKeys(r1, r2)
+		if err != nil && sortErr == nil {
+			sortErr = err
+		}
+		return cmp
+	})
+	if sortErr != nil {
+		return nil, sortErr
+	}
+
+	return &sliceCursor{rows: rows}, nil
+}
+
+// merge walks both result sets in key order, pairing the rows with equal keys
+func (d *rowsDiff) merge(ca, cb diffCursor) error {
+	for {
+		aRow, err := ca.peek()
+		if err != nil {
+			return err
+		}
+
+		bRow, err := cb.peek()
+		if err != nil {
+			return err
+		}
+
+		if aRow == nil && bRow == nil {
+			return nil
+		}
+
+		cmp := 0
+
+		if aRow == nil {
+			cmp = 1
+		} else if bRow == nil {
+			cmp = -1
+		} else {
+			cmp, err = d.compareKeys(aRow, bRow)
+			if err != nil {
+				return err
+			}
+		}
+
+		if cmp < 0 {
+			d.diff.Removed = append(d.diff.Removed, aRow)
+			ca.advance()
+			continue
+		}
+
+		if cmp > 0 {
+			d.diff.Added = append(d.diff.Added, bRow)
+			cb.advance()
+			continue
+		}
+
+		eq, err := RowComparator{NullsEqual: true}.Equal(aRow, bRow)
+		if err != nil {
+			return err
+		}
+
+		if !eq {
+			d.diff.Changed = append(d.diff.Changed, RowChange{From: aRow, To: bRow})
+		}
+
+		ca.advance()
+		cb.advance()
+	}
+}
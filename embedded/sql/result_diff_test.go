@@ -0,0 +1,179 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffResults(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, `
+		CREATE TABLE source (id INTEGER, name VARCHAR, score FLOAT, PRIMARY KEY id);
+		CREATE TABLE replica (id INTEGER, name VARCHAR, score FLOAT, PRIMARY KEY id);
+
+		INSERT INTO source (id, name, score) VALUES (1, 'alice', 1.5), (2, 'bob', NULL), (3, 'carol', 3.0), (4, 'dave', 0.5);
+		INSERT INTO replica (id, name, score) VALUES (1, 'alice', 1.5), (2, 'bob', NULL), (3, 'carol', 3.0), (4, 'dave', 0.5);
+	`, nil)
+	require.NoError(t, err)
+
+	diff := func(t *testing.T, sqlA, sqlB string, opts DiffOpts) *ResultDiff {
+		a, err := engine.Query(context.Background(), nil, sqlA, nil)
+		require.NoError(t, err)
+		defer a.Close()
+
+		b, err := engine.Query(context.Background(), nil, sqlB, nil)
+		require.NoError(t, err)
+		defer b.Close()
+
+		d, err := DiffResultsWithOpts(context.Background(), a, b, opts)
+		require.NoError(t, err)
+		return d
+	}
+
+	exec := func(t *testing.T, sql string) {
+		_, _, err := engine.Exec(context.Background(), nil, sql, nil)
+		require.NoError(t, err)
+	}
+
+	byID := DiffOpts{KeyColumns: []string{"id"}}
+
+	t.Run("identical results", func(t *testing.T) {
+		a, err := engine.Query(context.Background(), nil, "SELECT * FROM source", nil)
+		require.NoError(t, err)
+		defer a.Close()
+
+		b, err := engine.Query(context.Background(), nil, "SELECT * FROM replica", nil)
+		require.NoError(t, err)
+		defer b.Close()
+
+		d, err := DiffResults(context.Background(), a, b)
+		require.NoError(t, err)
+		require.True(t, d.Empty())
+		require.Len(t, d.Columns, 3)
+
+		require.True(t, diff(t, "SELECT * FROM source", "SELECT * FROM replica", byID).Empty())
+		require.True(t, diff(t, "SELECT * FROM source ORDER BY id DESC", "SELECT * FROM replica ORDER BY id DESC", DiffOpts{DescOrder: true}).Empty())
+	})
+
+	t.Run("one extra row", func(t *testing.T) {
+		exec(t, "INSERT INTO replica (id, name, score) VALUES (5, 'erin', 2.0)")
+		defer exec(t, "DELETE FROM replica WHERE id = 5")
+
+		for _, opts := range []DiffOpts{{}, byID, {KeyColumns: []string{"id"}, IgnoreOrder: true}} {
+			d := diff(t, "SELECT * FROM source", "SELECT * FROM replica", opts)
+			require.Empty(t, d.Removed)
+			require.Empty(t, d.Changed)
+			require.Len(t, d.Added, 1)
+			require.Equal(t, int64(5), d.Added[0].ValuesByPosition[0].RawValue())
+		}
+
+		d := diff(t, "SELECT * FROM replica ORDER BY id DESC", "SELECT * FROM source ORDER BY id DESC", DiffOpts{KeyColumns: []string{"id"}, DescOrder: true})
+		require.Empty(t, d.Added)
+		require.Empty(t, d.Changed)
+		require.Len(t, d.Removed, 1)
+		require.Equal(t, int64(5), d.Removed[0].ValuesByPosition[0].RawValue())
+	})
+
+	t.Run("one changed value", func(t *testing.T) {
+		exec(t, "UPDATE replica SET score = 2.5 WHERE id = 2")
+		defer exec(t, "UPDATE replica SET score = NULL WHERE id = 2")
+
+		d := diff(t, "SELECT * FROM source", "SELECT * FROM replica", byID)
+		require.Empty(t, d.Added)
+		require.Empty(t, d.Removed)
+		require.Len(t, d.Changed, 1)
+		require.True(t, d.Changed[0].From.ValuesByPosition[2].IsNull())
+		require.Equal(t, 2.5, d.Changed[0].To.ValuesByPosition[2].RawValue())
+
+		// rows identified by all their values are reported as removed and added
+		d = diff(t, "SELECT * FROM source", "SELECT * FROM replica", DiffOpts{})
+		require.Empty(t, d.Changed)
+		require.Len(t, d.Removed, 1)
+		require.Len(t, d.Added, 1)
+		require.Equal(t, int64(2), d.Removed[0].ValuesByPosition[0].RawValue())
+		require.Equal(t, int64(2), d.Added[0].ValuesByPosition[0].RawValue())
+	})
+
+	t.Run("values of different types are compared by value", func(t *testing.T) {
+		require.True(t, diff(t, "SELECT id, score FROM source", "SELECT id, CAST(score AS FLOAT) AS score FROM replica", byID).Empty())
+		require.True(t, diff(t, "SELECT id FROM source", "SELECT CAST(id AS FLOAT) AS id FROM replica", DiffOpts{}).Empty())
+	})
+
+	t.Run("order-sensitive and order-insensitive comparisons", func(t *testing.T) {
+		exec(t, "UPDATE replica SET name = 'zoe' WHERE id = 1")
+		defer exec(t, "UPDATE replica SET name = 'alice' WHERE id = 1")
+
+		// rows out of order are reported as removed and added
+		d := diff(t, "SELECT * FROM source ORDER BY name", "SELECT * FROM replica ORDER BY name", byID)
+		require.False(t, d.Empty())
+		require.Len(t, d.Removed, 1)
+		require.Len(t, d.Added, 1)
+		require.Equal(t, d.Removed[0].ValuesByPosition[0].RawValue(), d.Added[0].ValuesByPosition[0].RawValue())
+
+		d = diff(t, "SELECT * FROM source ORDER BY name", "SELECT * FROM replica ORDER BY name", DiffOpts{KeyColumns: []string{"id"}, IgnoreOrder: true})
+		require.Empty(t, d.Added)
+		require.Empty(t, d.Removed)
+		require.Len(t, d.Changed, 1)
+		require.Equal(t, "alice", d.Changed[0].From.ValuesByPosition[1].RawValue())
+		require.Equal(t, "zoe", d.Changed[0].To.ValuesByPosition[1].RawValue())
+
+		require.False(t, diff(t, "SELECT * FROM source ORDER BY id", "SELECT * FROM source ORDER BY id DESC", byID).Empty())
+		require.True(t, diff(t, "SELECT * FROM source ORDER BY id", "SELECT * FROM source ORDER BY id DESC", DiffOpts{IgnoreOrder: true}).Empty())
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		a, err := engine.Query(context.Background(), nil, "SELECT id, name FROM source", nil)
+		require.NoError(t, err)
+		defer a.Close()
+
+		b, err := engine.Query(context.Background(), nil, "SELECT id, score FROM replica", nil)
+		require.NoError(t, err)
+		defer b.Close()
+
+		_, err = DiffResults(context.Background(), a, nil)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = DiffResults(context.Background(), a, b)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = DiffResultsWithOpts(context.Background(), a, a, DiffOpts{KeyColumns: []string{"unknown"}})
+		require.ErrorIs(t, err, ErrColumnDoesNotExist)
+
+		c, err := engine.Query(context.Background(), nil, "SELECT id, name AS id FROM source", nil)
+		require.NoError(t, err)
+		defer c.Close()
+
+		_, err = DiffResultsWithOpts(context.Background(), c, c, byID)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+	})
+
+	t.Run("rows not matching the columns", func(t *testing.T) {
+		a := &columnsRowReader{
+			cols:          []ColDescriptor{{Column: "x", Type: IntegerType}},
+			mockRowReader: mockRowReader{rows: []*Row{{ValuesByPosition: []TypedValue{NewInteger(1), NewInteger(2)}}}},
+		}
+		b := &columnsRowReader{cols: []ColDescriptor{{Column: "x", Type: IntegerType}}}
+
+		_, err := DiffResults(context.Background(), a, b)
+		require.ErrorIs(t, err, ErrUnexpected)
+	})
+}
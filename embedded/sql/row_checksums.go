@@ -0,0 +1,89 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"hash"
+)
+
+// rowChecksumLen is the length of the checksum appended to the encoded rows
+const rowChecksumLen = sha256.Size
+
+// rowChecksums computes the checksums of the rows written and read when the engine is
+// opened with WithRowChecksums. A checksum covers the id of the table and the encoded
+// row, which includes the primary key, and is either a SHA-256 digest or an HMAC-SHA256
+// when a key is specified with WithRowChecksumKey.
+type rowChecksums struct {
+	key []byte
+}
+
+func (c *rowChecksums) sum(table *Table, encodedRow []byte) []byte {
+	var h hash.Hash
+	if len(c.key) > 0 {
+		h = hmac.New(sha256.New, c.key)
+	} else {
+		h = sha256.New()
+	}
+
+	h.Write(EncodeID(table.id))
+	h.Write(encodedRow)
+
+	return h.Sum(nil)
+}
+
+func (t *Table) rowChecksums() *rowChecksums {
+	// system tables are not bound to a catalog and have no rows of their own
+	if t.catalog == nil {
+		return nil
+	}
+	return t.catalog.rowChecksums
+}
+
+// appendRowChecksum appends the checksum of the encoded row when checksums are enabled
+func (t *Table) appendRowChecksum(encodedRow []byte) []byte {
+	checksums := t.rowChecksums()
+	if checksums == nil {
+		return encodedRow
+	}
+	return append(encodedRow, checksums.sum(t, encodedRow)...)
+}
+
+// verifyRowChecksum verifies the checksum following the values of the row, which are encoded
+// in v[:voff]. Rows written with checksums can still be read when they are disabled, in which
+// case their checksum is not verified.
+func (t *Table) verifyRowChecksum(v []byte, voff int) error {
+	checksum := v[voff:]
+
+	checksums := t.rowChecksums()
+	if checksums == nil {
+		if len(checksum) != 0 && len(checksum) != rowChecksumLen {
+			return ErrCorruptedData
+		}
+		return nil
+	}
+
+	if len(checksum) != rowChecksumLen {
+		return ErrRowIntegrity
+	}
+
+	if !hmac.Equal(checksum, checksums.sum(t, v[:voff])) {
+		return ErrRowIntegrity
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+)
+
+// tamperLastRow rewrites the entries of the last transaction holding the given value,
+// replacing it with another one of the same length
+func tamperLastRow(t *testing.T, st *store.ImmuStore, old, new []byte) {
+	tx := store.NewTx(st.MaxTxEntries(), st.MaxKeyLen())
+
+	err := st.ReadTx(st.LastCommittedTxID(), false, tx)
+	require.NoError(t, err)
+
+	otx, err := st.NewWriteOnlyTx(context.Background())
+	require.NoError(t, err)
+
+	for _, e := range tx.Entries() {
+		v, err := st.ReadValue(e)
+		require.NoError(t, err)
+
+		if bytes.Contains(v, old) {
+			err = otx.Set(e.Key(), nil, bytes.Replace(v, old, new, 1))
+			require.NoError(t, err)
+		}
+	}
+
+	_, err = otx.Commit(context.Background())
+	require.NoError(t, err)
+}
+
+func TestRowChecksums(t *testing.T) {
+	setup := func(t *testing.T, opts *Options) (*store.ImmuStore, *Engine) {
+		st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+		require.NoError(t, err)
+		t.Cleanup(func() { closeStore(t, st) })
+
+		engine, err := NewEngine(st, opts.WithPrefix(sqlPrefix))
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, `
+			CREATE TABLE accounts (id INTEGER, owner VARCHAR[16], balance INTEGER, PRIMARY KEY id);
+			CREATE INDEX ON accounts (owner);
+			INSERT INTO accounts (id, owner, balance) VALUES (1, 'alice', 100), (2, 'bob', 50);
+		`, nil)
+		require.NoError(t, err)
+
+		return st, engine
+	}
+
+	owners := func(t *testing.T, engine *Engine, sql string) ([]string, error) {
+		rows, err := engine.queryAll(context.Background(), nil, sql, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var owners []string
+		for _, row := range rows {
+			owners = append(owners, row.ValuesByPosition[0].RawValue().(string))
+		}
+		return owners, nil
+	}
+
+	for _, d := range []struct {
+		name string
+		opts *Options
+	}{
+		{"checksum", DefaultOptions().WithRowChecksums(true)},
+		{"hmac", DefaultOptions().WithRowChecksums(true).WithRowChecksumKey([]byte("secret"))},
+		{"lazy decoding", DefaultOptions().WithRowChecksums(true).WithLazyDecoding(true)},
+	} {
+		t.Run(d.name, func(t *testing.T) {
+			st, engine := setup(t, d.opts)
+
+			_, _, err := engine.Exec(context.Background(), nil, `
+				UPDATE accounts SET balance = balance - 10 WHERE id = 1;
+				INSERT INTO accounts (id, owner, balance) VALUES (3, 'carol', 70);
+			`, nil)
+			require.NoError(t, err)
+
+			res, err := owners(t, engine, "SELECT owner FROM accounts WHERE balance > 60 ORDER BY id")
+			require.NoError(t, err)
+			require.Equal(t, []string{"alice", "carol"}, res)
+
+			tamperLastRow(t, st, []byte("carol"), []byte("craig"))
+
+			for _, sql := range []string{
+				"SELECT owner FROM accounts",
+				"SELECT owner FROM accounts WHERE id = 3",
+				"SELECT owner FROM accounts USE INDEX ON (owner) WHERE owner > 'c'",
+			} {
+				_, err := owners(t, engine, sql)
+				require.ErrorIs(t, err, ErrRowIntegrity, sql)
+				require.ErrorIs(t, err, ErrCorruptedData, sql)
+			}
+
+			// rows not tampered with are still verified
+			res, err = owners(t, engine, "SELECT owner FROM accounts WHERE id = 2")
+			require.NoError(t, err)
+			require.Equal(t, []string{"bob"}, res)
+
+			_, _, err = engine.Exec(context.Background(), nil, "UPDATE accounts SET balance = 0 WHERE id = 3", nil)
+			require.ErrorIs(t, err, ErrRowIntegrity)
+		})
+	}
+
+	t.Run("checksums computed with another key", func(t *testing.T) {
+		st, _ := setup(t, DefaultOptions().WithRowChecksums(true).WithRowChecksumKey([]byte("secret")))
+
+		engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix).WithRowChecksums(true).WithRowChecksumKey([]byte("other")))
+		require.NoError(t, err)
+
+		_, err = owners(t, engine, "SELECT owner FROM accounts")
+		require.ErrorIs(t, err, ErrRowIntegrity)
+	})
+
+	t.Run("enabling and disabling checksums", func(t *testing.T) {
+		st, _ := setup(t, DefaultOptions())
+
+		engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix).WithRowChecksums(true))
+		require.NoError(t, err)
+
+		// rows written without checksums can not be verified
+		_, err = owners(t, engine, "SELECT owner FROM accounts")
+		require.ErrorIs(t, err, ErrRowIntegrity)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO accounts (id, owner, balance) VALUES (3, 'carol', 70)", nil)
+		require.NoError(t, err)
+
+		res, err := owners(t, engine, "SELECT owner FROM accounts WHERE id = 3")
+		require.NoError(t, err)
+		require.Equal(t, []string{"carol"}, res)
+
+		// rows written with and without checksums are read when disabled
+		engine, err = NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+		require.NoError(t, err)
+
+		res, err = owners(t, engine, "SELECT owner FROM accounts ORDER BY id")
+		require.NoError(t, err)
+		require.Equal(t, []string{"alice", "bob", "carol"}, res)
+	})
+}
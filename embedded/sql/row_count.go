@@ -0,0 +1,114 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+
+	"github.com/codenotary/immudb/embedded/store"
+)
+
+// Tables created while row counts are enabled keep their number of rows under a key of the
+// catalog, which is updated once per statement inserting or deleting their rows. As the count
+// is read before being updated, concurrent transactions modifying the rows of the same table
+// conflict with each other, thus the count always matches the committed rows.
+
+func (t *Table) rowCountKey() []byte {
+	return MapKey(t.catalog.enginePrefix, catalogRowCountPrefix, EncodeID(DatabaseID), EncodeID(t.id))
+}
+
+func (tx *SQLTx) setRowCount(table *Table, n int64) error {
+	var v [8]byte
+	binary.BigEndian.PutUint64(v[:], uint64(n))
+
+	return tx.set(table.rowCountKey(), nil, v[:])
+}
+
+// addRowCount adds delta to the number of rows of the table, when they are counted
+func (tx *SQLTx) addRowCount(ctx context.Context, table *Table, delta int64) error {
+	if !table.countsRows || delta == 0 {
+		return nil
+	}
+
+	n, _, err := tx.rowCount(ctx, table)
+	if err != nil {
+		return err
+	}
+	return tx.setRowCount(table, n+delta)
+}
+
+// rowCount returns the number of rows of the table along with the id of the transaction
+// in which it was last updated, which is zero when updated by the current transaction
+func (tx *SQLTx) rowCount(ctx context.Context, table *Table) (int64, uint64, error) {
+	vref, err := tx.get(ctx, table.rowCountKey())
+	if err != nil {
+		return 0, 0, err
+	}
+
+	v, err := vref.Resolve()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	if len(v) != 8 {
+		return 0, 0, ErrCorruptedData
+	}
+	return int64(binary.BigEndian.Uint64(v)), vref.Tx(), nil
+}
+
+// countedRows returns the number of rows read by rowReader when the statement only counts
+// all the rows of a table whose rows are counted, so they don't need to be scanned, or nil otherwise
+func (stmt *SelectStmt) countedRows(ctx context.Context, tx *SQLTx, rowReader RowReader) (*int64, error) {
+	if tx == nil || len(stmt.targets) == 0 || stmt.where != nil || len(stmt.joins) > 0 ||
+		len(stmt.groupBy) > 0 || stmt.distinct {
+		return nil, nil
+	}
+
+	for _, t := range stmt.targets {
+		sel, isAgg := t.Exp.(*AggColSelector)
+		if !isAgg || !sel.countsAllRows() {
+			return nil, nil
+		}
+	}
+
+	// rows read through middleware, or from a period or the history of the table, are scanned
+	raw, isRaw := rowReader.(*rawRowReader)
+	if !isRaw || !raw.table.countsRows || raw.period.start != nil || raw.period.end != nil ||
+		raw.scanSpecs.IncludeHistory || raw.scanSpecs.fullTextMatch != nil || raw.scanSpecs.resumeAfter != nil {
+		return nil, nil
+	}
+
+	n, txID, err := tx.rowCount(ctx, raw.table)
+	if errors.Is(err, store.ErrKeyNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// the count may be more recent than the snapshot the rows are read from
+	if tx.snapshotTxID > 0 && txID > tx.snapshotTxID {
+		return nil, nil
+	}
+	return &n, nil
+}
+
+func (sel *AggColSelector) countsAllRows() bool {
+	return sel.aggFn == COUNT && sel.col == "*" && sel.distinct == nil && sel.aggregate == nil
+}
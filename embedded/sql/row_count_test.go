@@ -0,0 +1,140 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRowCounts(t *testing.T) {
+	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(t, err)
+	defer closeStore(t, st)
+
+	// tables created before row counts are enabled are not counted
+	plainEngine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+	require.NoError(t, err)
+
+	_, _, err = plainEngine.Exec(context.Background(), nil, `
+		CREATE TABLE plain (id INTEGER, PRIMARY KEY id);
+		INSERT INTO plain (id) VALUES (1), (2), (3);
+	`, nil)
+	require.NoError(t, err)
+
+	engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix).WithRowCounts(true))
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE people (id INTEGER, name VARCHAR, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	// counting more than one row fails unless the rows are not scanned
+	limited := ContextWithResourceLimits(context.Background(), ResourceLimits{MaxRowsScanned: 1})
+
+	count := func(t *testing.T, e *Engine, ctx context.Context, tx *SQLTx, sql string) int64 {
+		rows, err := e.queryAll(ctx, tx, sql, nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+		return rows[0].ValuesByPosition[0].RawValue().(int64)
+	}
+
+	exec := func(t *testing.T, tx *SQLTx, sql string) {
+		_, _, err := engine.Exec(context.Background(), tx, sql, nil)
+		require.NoError(t, err)
+	}
+
+	require.Zero(t, count(t, engine, limited, nil, "SELECT COUNT(*) FROM people"))
+
+	t.Run("counts are kept by inserts and deletes", func(t *testing.T) {
+		exec(t, nil, "INSERT INTO people (id, name) VALUES (1, 'alice'), (2, 'bob'), (3, 'carol'), (4, 'dave')")
+		require.Equal(t, int64(4), count(t, engine, limited, nil, "SELECT COUNT(*) FROM people"))
+
+		// replaced rows and conflicting inserts are not counted
+		exec(t, nil, "UPSERT INTO people (id, name) VALUES (1, 'alice'), (5, 'erin')")
+		exec(t, nil, "INSERT INTO people (id, name) VALUES (2, 'bob'), (6, 'frank') ON CONFLICT DO NOTHING")
+		exec(t, nil, "UPDATE people SET name = 'bobby' WHERE id = 2")
+		require.Equal(t, int64(6), count(t, engine, limited, nil, "SELECT COUNT(*) FROM people"))
+
+		exec(t, nil, "DELETE FROM people WHERE name = 'carol'")
+		exec(t, nil, "DELETE FROM people WHERE id >= 5")
+		require.Equal(t, int64(3), count(t, engine, limited, nil, "SELECT COUNT(*) AS n FROM people AS p"))
+	})
+
+	t.Run("filtered counts scan the rows", func(t *testing.T) {
+		require.Equal(t, int64(2), count(t, engine, context.Background(), nil, "SELECT COUNT(*) FROM people WHERE id > 1"))
+
+		for _, sql := range []string{
+			"SELECT COUNT(*) FROM people WHERE id > 1",
+			"SELECT COUNT(*) FROM people GROUP BY name",
+			"SELECT COUNT(*) FROM plain",
+		} {
+			_, err := engine.queryAll(limited, nil, sql, nil)
+			require.ErrorIs(t, err, ErrMaxRowsScannedExceeded, sql)
+		}
+
+		require.Equal(t, int64(3), count(t, engine, context.Background(), nil, "SELECT COUNT(*) FROM plain"))
+	})
+
+	t.Run("rolled back transactions don't change the count", func(t *testing.T) {
+		tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithExplicitClose(true))
+		require.NoError(t, err)
+
+		exec(t, tx, "INSERT INTO people (id, name) VALUES (10, 'grace'), (11, 'heidi')")
+		exec(t, tx, "DELETE FROM people WHERE id = 1")
+
+		// the transaction counts its own rows
+		require.Equal(t, int64(4), count(t, engine, limited, tx, "SELECT COUNT(*) FROM people"))
+		require.NoError(t, tx.Cancel())
+
+		require.Equal(t, int64(3), count(t, engine, limited, nil, "SELECT COUNT(*) FROM people"))
+	})
+
+	t.Run("concurrent transactions conflict", func(t *testing.T) {
+		tx1, err := engine.NewTx(context.Background(), DefaultTxOptions().WithExplicitClose(true))
+		require.NoError(t, err)
+
+		tx2, err := engine.NewTx(context.Background(), DefaultTxOptions().WithExplicitClose(true))
+		require.NoError(t, err)
+
+		exec(t, tx1, "INSERT INTO people (id, name) VALUES (20, 'ivan')")
+		exec(t, tx2, "INSERT INTO people (id, name) VALUES (21, 'judy')")
+
+		require.NoError(t, tx1.Commit(context.Background()))
+		require.ErrorIs(t, tx2.Commit(context.Background()), store.ErrTxReadConflict)
+
+		require.Equal(t, int64(4), count(t, engine, limited, nil, "SELECT COUNT(*) FROM people"))
+		require.Equal(t, int64(4), count(t, engine, context.Background(), nil, "SELECT COUNT(*) FROM people WHERE id > 0"))
+	})
+
+	t.Run("counts are kept regardless of the options of the engine", func(t *testing.T) {
+		_, _, err := plainEngine.Exec(context.Background(), nil, "DELETE FROM people WHERE id = 20", nil)
+		require.NoError(t, err)
+
+		require.Equal(t, int64(3), count(t, plainEngine, limited, nil, "SELECT COUNT(*) FROM people"))
+		require.Equal(t, int64(3), count(t, engine, limited, nil, "SELECT COUNT(*) FROM people"))
+	})
+
+	t.Run("dropped tables", func(t *testing.T) {
+		exec(t, nil, "DROP TABLE people")
+		exec(t, nil, "CREATE TABLE people (id INTEGER, PRIMARY KEY id)")
+
+		require.Zero(t, count(t, engine, limited, nil, "SELECT COUNT(*) FROM people"))
+	})
+}
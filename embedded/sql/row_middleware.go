@@ -0,0 +1,197 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"sync"
+)
+
+// RowInterceptor is the middleware of the rows of a table, see Engine.UseRowMiddleware.
+// Rows can be observed or transformed in place, replacing their values with Row.Set,
+// and interceptors may be called concurrently by the statements being executed.
+type RowInterceptor struct {
+	// OnRead is called with each row read from the table by queries, before the conditions of the
+	// query are evaluated. Rows read by UPDATE and DELETE statements are instead read as stored
+	OnRead func(ctx context.Context, row *Row) error
+	// OnWrite is called with the values of each row inserted or updated into the table, before
+	// generated columns are computed and the row is validated and stored
+	OnWrite func(ctx context.Context, row *Row) error
+}
+
+type rowMiddleware struct {
+	mutex   sync.RWMutex
+	byTable map[string][]RowInterceptor
+}
+
+func newRowMiddleware() *rowMiddleware {
+	return &rowMiddleware{
+		byTable: make(map[string][]RowInterceptor),
+	}
+}
+
+func (m *rowMiddleware) forTable(table string) []RowInterceptor {
+	if m == nil {
+		return nil
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	return m.byTable[table]
+}
+
+// UseRowMiddleware registers the interceptor of the rows read from and written to the table,
+// which needs not exist yet. Interceptors of the same table are called in the order they were
+// registered, each one observing the changes made by the previous ones.
+func (e *Engine) UseRowMiddleware(table string, interceptor RowInterceptor) error {
+	if table == "" {
+		return fmt.Errorf("%w: empty table name", ErrIllegalArguments)
+	}
+
+	if interceptor.OnRead == nil && interceptor.OnWrite == nil {
+		return fmt.Errorf("%w: the rows to be intercepted must be specified", ErrIllegalArguments)
+	}
+
+	e.rowMiddleware.mutex.Lock()
+	defer e.rowMiddleware.mutex.Unlock()
+
+	// interceptors are appended to a copy, as the current ones may be in use by readers
+	interceptors := make([]RowInterceptor, 0, len(e.rowMiddleware.byTable[table])+1)
+	interceptors = append(interceptors, e.rowMiddleware.byTable[table]...)
+
+	e.rowMiddleware.byTable[table] = append(interceptors, interceptor)
+
+	return nil
+}
+
+// interceptReads wraps the reader of the rows of the table with its middleware, if any
+func (tx *SQLTx) interceptReads(table *Table, rowReader RowReader) RowReader {
+	if tx.storedReads > 0 {
+		return rowReader
+	}
+
+	var onRead []func(context.Context, *Row) error
+
+	for _, interceptor := range tx.engine.rowMiddleware.forTable(table.name) {
+		if interceptor.OnRead != nil {
+			onRead = append(onRead, interceptor.OnRead)
+		}
+	}
+
+	if len(onRead) == 0 {
+		return rowReader
+	}
+
+	return &interceptedRowReader{
+		RowReader: rowReader,
+		onRead:    onRead,
+	}
+}
+
+// interceptWrite applies the middleware of the table to the row being written, whose values
+// are then taken from the row by the selectors of the columns of the table
+func (tx *SQLTx) interceptWrite(ctx context.Context, table *Table, row *Row, valuesByColID map[uint32]TypedValue) error {
+	interceptors := tx.engine.rowMiddleware.forTable(table.name)
+	if len(interceptors) == 0 {
+		return nil
+	}
+
+	for _, interceptor := range interceptors {
+		if interceptor.OnWrite == nil {
+			continue
+		}
+
+		err := interceptor.OnWrite(ctx, row)
+		if err != nil {
+			return err
+		}
+	}
+
+	for i, col := range table.cols {
+		v := row.ValuesBySelector[EncodeSelector("", table.name, col.colName)]
+		if v == nil {
+			v = NewNull(col.colType)
+		}
+
+		if v.IsNull() && (col.notNull || col.autoIncrement) {
+			return fmt.Errorf("%w (%s)", ErrNotNullableColumnCannotBeNull, col.colName)
+		}
+
+		row.ValuesByPosition[i] = v
+		valuesByColID[col.id] = v
+	}
+	return nil
+}
+
+// interceptedRowReader calls the read interceptors of a table with each row read from it
+type interceptedRowReader struct {
+	RowReader
+
+	onRead []func(context.Context, *Row) error
+
+	cols      []ColDescriptor
+	lookahead rowLookahead
+}
+
+func (r *interceptedRowReader) All(ctx context.Context) iter.Seq2[*Row, error] {
+	return allRows(ctx, r)
+}
+
+func (r *interceptedRowReader) Read(ctx context.Context) (*Row, error) {
+	return r.lookahead.read(ctx, r.readRow)
+}
+
+func (r *interceptedRowReader) Peek(ctx context.Context) (*Row, error) {
+	return r.lookahead.peek(ctx, r.readRow)
+}
+
+func (r *interceptedRowReader) readRow(ctx context.Context) (*Row, error) {
+	row, err := r.RowReader.Read(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.cols == nil {
+		r.cols, err = r.RowReader.Columns(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, onRead := range r.onRead {
+		err := onRead(ctx, row)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// values replaced by the interceptors are also set by position
+	for i, col := range r.cols {
+		if v, ok := row.ValuesBySelector[col.Selector()]; ok {
+			row.ValuesByPosition[i] = v
+		}
+	}
+	return row, nil
+}
+
+func (r *interceptedRowReader) Close() error {
+	r.lookahead.discard()
+	return r.RowReader.Close()
+}
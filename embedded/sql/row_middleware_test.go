@@ -0,0 +1,212 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRowMiddleware(t *testing.T) {
+	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(t, err)
+	defer closeStore(t, st)
+
+	engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+	require.NoError(t, err)
+
+	// rows as stored are read by an engine without middleware
+	plainEngine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+	require.NoError(t, err)
+
+	var reads, writes atomic.Int64
+
+	err = engine.UseRowMiddleware("users", RowInterceptor{
+		OnRead: func(ctx context.Context, row *Row) error {
+			reads.Add(1)
+
+			ssn, err := row.Get("ssn")
+			if err != nil || ssn.IsNull() {
+				return err
+			}
+			return row.Set("ssn", NewVarchar("***-**-"+ssn.RawValue().(string)[7:]))
+		},
+	})
+	require.NoError(t, err)
+
+	err = engine.UseRowMiddleware("users", RowInterceptor{
+		OnWrite: func(ctx context.Context, row *Row) error {
+			writes.Add(1)
+
+			email, err := row.Get("email")
+			if err != nil || email.IsNull() {
+				return err
+			}
+			return row.Set("email", NewVarchar(strings.ToLower(email.RawValue().(string))))
+		},
+	})
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		CREATE TABLE users (id INTEGER AUTO_INCREMENT, name VARCHAR, email VARCHAR, ssn VARCHAR[11], PRIMARY KEY id);
+		CREATE INDEX ON users (ssn);
+		CREATE TABLE orders (id INTEGER AUTO_INCREMENT, user_id INTEGER, PRIMARY KEY id);
+	`, nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		INSERT INTO users (name, email, ssn) VALUES
+			('alice', 'Alice@Example.com', '123-45-6789'),
+			('bob', 'BOB@example.com', '987-65-4321'),
+			('carol', NULL, '555-12-3456');
+		INSERT INTO orders (user_id) VALUES (1), (2), (2);
+	`, nil)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), writes.Load())
+
+	query := func(t *testing.T, e *Engine, sql string) [][]interface{} {
+		rows, err := e.queryAll(context.Background(), nil, sql, nil)
+		require.NoError(t, err)
+
+		values := make([][]interface{}, len(rows))
+		for i, row := range rows {
+			for _, v := range row.ValuesByPosition {
+				values[i] = append(values[i], v.RawValue())
+			}
+		}
+		return values
+	}
+
+	t.Run("redaction on read", func(t *testing.T) {
+		require.Equal(t, [][]interface{}{
+			{int64(1), "alice", "alice@example.com", "***-**-6789"},
+			{int64(2), "bob", "bob@example.com", "***-**-4321"},
+			{int64(3), "carol", nil, "***-**-3456"},
+		}, query(t, engine, "SELECT * FROM users"))
+
+		require.Equal(t, [][]interface{}{{"***-**-4321", int64(2)}}, query(t, engine, `
+			SELECT u.ssn, COUNT(*) FROM users AS u INNER JOIN orders AS o ON o.user_id = u.id
+			GROUP BY u.ssn HAVING COUNT(*) > 1`))
+
+		// conditions are evaluated on the transformed rows
+		require.Empty(t, query(t, engine, "SELECT id FROM users WHERE ssn = '123-45-6789'"))
+		require.Equal(t, [][]interface{}{{int64(1)}}, query(t, engine, "SELECT id FROM users USE INDEX ON (id) WHERE ssn = '***-**-6789'"))
+
+		// values are transformed within the tables the middleware is registered for
+		require.Len(t, query(t, engine, "SELECT * FROM orders"), 3)
+
+		require.Equal(t, [][]interface{}{
+			{int64(1), "alice", "alice@example.com", "123-45-6789"},
+			{int64(2), "bob", "bob@example.com", "987-65-4321"},
+			{int64(3), "carol", nil, "555-12-3456"},
+		}, query(t, plainEngine, "SELECT * FROM users"))
+	})
+
+	t.Run("rows are written back as stored", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "UPDATE users SET email = 'Carol@Example.com' WHERE id = 3", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "UPSERT INTO users (id, name, email, ssn) VALUES (2, 'bobby', 'Bobby@Example.com', '987-65-4321')", nil)
+		require.NoError(t, err)
+
+		require.Equal(t, int64(5), writes.Load())
+
+		_, _, err = engine.Exec(context.Background(), nil, "DELETE FROM users WHERE id = 1", nil)
+		require.NoError(t, err)
+
+		require.Equal(t, [][]interface{}{
+			{int64(2), "bobby", "bobby@example.com", "987-65-4321"},
+			{int64(3), "carol", "carol@example.com", "555-12-3456"},
+		}, query(t, plainEngine, "SELECT * FROM users"))
+
+		// index entries were updated from the values as stored
+		require.Equal(t, [][]interface{}{{"555-12-3456"}, {"987-65-4321"}}, query(t, plainEngine, "SELECT ssn FROM users USE INDEX ON (ssn) ORDER BY ssn"))
+	})
+
+	t.Run("concurrent readers", func(t *testing.T) {
+		before := reads.Load()
+
+		var wg sync.WaitGroup
+
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+
+			go func() {
+				defer wg.Done()
+
+				for j := 0; j < 10; j++ {
+					rows, err := engine.queryAll(context.Background(), nil, "SELECT ssn FROM users WHERE name <> 'x'", nil)
+					if !assert.NoError(t, err) {
+						return
+					}
+
+					for _, row := range rows {
+						assert.True(t, strings.HasPrefix(row.ValuesByPosition[0].RawValue().(string), "***-**-"))
+					}
+				}
+			}()
+		}
+
+		wg.Wait()
+
+		require.Equal(t, int64(8*10*2), reads.Load()-before)
+	})
+
+	t.Run("interceptor errors", func(t *testing.T) {
+		errInterceptor := errors.New("intercepted")
+
+		err := engine.UseRowMiddleware("orders", RowInterceptor{
+			OnRead: func(ctx context.Context, row *Row) error {
+				return errInterceptor
+			},
+			OnWrite: func(ctx context.Context, row *Row) error {
+				return row.Set("user_id", NewNull(IntegerType))
+			},
+		})
+		require.NoError(t, err)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT * FROM orders", nil)
+		require.ErrorIs(t, err, errInterceptor)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO orders (user_id) VALUES (3)", nil)
+		require.NoError(t, err)
+		require.Equal(t, [][]interface{}{{nil}}, query(t, plainEngine, "SELECT user_id FROM orders WHERE id = 4"))
+
+		err = engine.UseRowMiddleware("users", RowInterceptor{
+			OnWrite: func(ctx context.Context, row *Row) error {
+				return row.Set("id", NewNull(IntegerType))
+			},
+		})
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO users (name) VALUES ('dave')", nil)
+		require.ErrorIs(t, err, ErrNotNullableColumnCannotBeNull)
+	})
+
+	t.Run("invalid middleware", func(t *testing.T) {
+		require.ErrorIs(t, engine.UseRowMiddleware("", RowInterceptor{OnRead: func(context.Context, *Row) error { return nil }}), ErrIllegalArguments)
+		require.ErrorIs(t, engine.UseRowMiddleware("users", RowInterceptor{}), ErrIllegalArguments)
+	})
+}
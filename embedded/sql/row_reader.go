@@ -22,6 +22,7 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"iter"
 	"math"
 
 	"github.com/codenotary/immudb/embedded/store"
@@ -31,7 +32,15 @@ type RowReader interface {
 	Tx() *SQLTx
 	TableAlias() string
 	Parameters() map[string]interface{}
+	// Read returns the next row, or ErrNoMoreRows once every row was read.
+	// Reading from a closed reader returns ErrReaderClosed instead, even if rows were left.
 	Read(ctx context.Context) (*Row, error)
+	// Peek returns the next row without consuming it, thus the next call to Read returns the same row.
+	// Peeking at a reader with no more rows returns ErrNoMoreRows.
+	Peek(ctx context.Context) (*Row, error)
+	// All returns an iterator over the remaining rows. The reader is closed
+	// once the iteration ends, either because all rows were read or it got interrupted.
+	All(ctx context.Context) iter.Seq2[*Row, error]
 	Close() error
 	Columns(ctx context.Context) ([]ColDescriptor, error)
 	OrderBy() []ColDescriptor
@@ -44,11 +53,26 @@ type RowReader interface {
 type ScanSpecs struct {
 	Index             *Index
 	rangesByColID     map[uint32]*typedValueRange
+	rangeUnionColID   uint32             // column of the index constrained by rangeUnion
+	rangeUnion        []*typedValueRange // disjoint ranges to be scanned, instead of the one in rangesByColID
 	IncludeHistory    bool
 	IncludeTxMetadata bool
 	DescOrder         bool
 	groupBySortExps   []*OrdExp
 	orderBySortExps   []*OrdExp
+	conflictFilter    ValueExp            // conditions satisfied by the rows the query depends on
+	lazyDecoding      bool                // values are only decoded when accessed
+	neededCols        map[uint32]struct{} // when set, the values of the other columns are not decoded
+	fullTextMatch     *fullTextMatch      // when set, rows are read through a full-text index
+	resumeAfter       []byte              // when set, the scan continues after the entry with this key
+}
+
+func (s *ScanSpecs) needsCol(colID uint32) bool {
+	if s.neededCols == nil {
+		return true
+	}
+	_, needed := s.neededCols[colID]
+	return needed
 }
 
 func (s *ScanSpecs) extraCols() int {
@@ -63,9 +87,14 @@ func (s *ScanSpecs) extraCols() int {
 	return n
 }
 
+// Row holds the values of a row by position and by selector. The values of rows read with
+// lazy decoding are decoded once accessed through ValueAt, Get or Scan, thus they're not
+// available in ValuesByPosition nor ValuesBySelector until then.
 type Row struct {
 	ValuesByPosition []TypedValue
 	ValuesBySelector map[string]TypedValue
+
+	lazy *lazyValues
 }
 
 // rows are selector-compatible if both rows have the same assigned value for all specified selectors
@@ -122,6 +151,49 @@ func (row *Row) digest(cols []ColDescriptor) (d [sha256.Size]byte, err error) {
 	return
 }
 
+// RowComparator compares rows by the values at each position, using the comparison semantics of typed values
+type RowComparator struct {
+	// NullsEqual makes two NULL values at the same position to be considered equal, as DISTINCT
+	// and set operations do. Otherwise, as in SQL comparisons, NULL values are not equal to each other.
+	NullsEqual bool
+}
+
+// Compare orders rows lexicographically by their values. NULL values are ordered before any
+// other value and, regardless of NullsEqual, two NULL values are ordered as equal.
+// Rows of different arity or holding values of incompatible types can not be compared.
+func (c RowComparator) Compare(a, b *Row) (int, error) {
+	if len(a.ValuesByPosition) != len(b.ValuesByPosition) {
+		return 0, fmt.Errorf("%w: rows of different number of values", ErrNotComparableValues)
+	}
+
+	res, _, err := Tuple(a.ValuesByPosition).Compare(b.ValuesByPosition)
+	return res, err
+}
+
+// Equal reports whether both rows hold equal values at each position
+func (c RowComparator) Equal(a, b *Row) (bool, error) {
+	res, err := c.Compare(a, b)
+	if err != nil || res != 0 {
+		return false, err
+	}
+
+	if !c.NullsEqual {
+		for _, v := range a.ValuesByPosition {
+			if v.IsNull() {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// RowsEqual reports whether both rows hold, at each position, values which are not distinct
+// from each other. Rows which can not be compared are not equal.
+func RowsEqual(a, b *Row) bool {
+	eq, err := RowComparator{NullsEqual: true}.Equal(a, b)
+	return err == nil && eq
+}
+
 type rawRowReader struct {
 	tx         *SQLTx
 	table      *Table
@@ -141,8 +213,15 @@ type rawRowReader struct {
 
 	params map[string]interface{}
 
+	colPosBySel map[string]int // set when values are lazily decoded
+
 	reader          store.KeyReader
 	onCloseCallback func()
+	closed          bool
+
+	resources *resourceTracker
+
+	lookahead rowLookahead
 }
 
 type txRange struct {
@@ -155,6 +234,12 @@ type ColDescriptor struct {
 	Table  string
 	Column string
 	Type   SQLValueType
+	// MaxLen is the declared maximum length of VARCHAR and BLOB columns, zero if unbounded
+	MaxLen int
+	// Nullable is false when the values of the column are known not to be NULL
+	Nullable bool
+	// PrimaryKey is set for the columns of the primary key of the table the values are read from
+	PrimaryKey bool
 }
 
 func (d *ColDescriptor) Selector() string {
@@ -190,10 +275,51 @@ func newRawRowReader(tx *SQLTx, params map[string]interface{}, table *Table, per
 		return nil, err
 	}
 
+	rSpecs := []*store.KeyReaderSpec{rSpec}
+
+	if len(scanSpecs.rangeUnion) > 1 {
+		// only the keys within each of the ranges are read
+		rSpecs, err = rangeUnionKeyReaderSpecs(tx.engine.prefix, table, scanSpecs)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if tableAlias == "" {
+		tableAlias = table.name
+	}
+
+	rowReader := &rawRowReader{
+		tx:         tx,
+		table:      table,
+		period:     period,
+		tableAlias: tableAlias,
+		scanSpecs:  scanSpecs,
+		params:     params,
+		resources:  tx.resources,
+	}
+
+	for _, rSpec := range rSpecs {
+		switch tx.opts.ConflictGranularity {
+		case TableConflicts:
+			rSpec.ConflictPrefix = MapKey(tx.engine.prefix, string(table.keyEncoder().TableIndexesPrefix(table.id)))
+		case PredicateConflicts:
+			if scanSpecs.conflictFilter != nil && !scanSpecs.IncludeHistory {
+				rSpec.ConflictPredicate = rowReader.satisfiesConflictFilter
+			}
+		}
+	}
+
 	var r store.KeyReader
 
 	if table.name == "pg_type" {
 		r = &emptyKeyReader{}
+	} else if scanSpecs.fullTextMatch != nil {
+		r = newFullTextKeyReader(tx, scanSpecs.fullTextMatch)
+	} else if len(rSpecs) > 1 {
+		r = newMultiRangeKeyReader(tx, rSpecs)
+	} else if tx.engine.scanShards > 1 && tx.tx.IsReadOnly() {
+		r = newShardedKeyReader(tx, *rSpec, tx.engine.scanShards)
 	} else {
 		r, err = tx.newKeyReader(*rSpec)
 		if err != nil {
@@ -201,10 +327,6 @@ func newRawRowReader(tx *SQLTx, params map[string]interface{}, table *Table, per
 		}
 	}
 
-	if tableAlias == "" {
-		tableAlias = table.name
-	}
-
 	nCols := len(table.cols) + scanSpecs.extraCols()
 
 	colsByPos := make([]ColDescriptor, nCols)
@@ -236,31 +358,40 @@ func newRawRowReader(tx *SQLTx, params map[string]interface{}, table *Table, per
 	}
 
 	for i, c := range table.cols {
+		isPK := table.primaryIndex.IncludesCol(c.id)
+
 		colDescriptor := ColDescriptor{
-			Table:  tableAlias,
-			Column: c.colName,
-			Type:   c.colType,
+			Table:      tableAlias,
+			Column:     c.colName,
+			Type:       c.colType,
+			Nullable:   c.IsNullable() && !isPK,
+			PrimaryKey: isPK,
+		}
+
+		if variableSizedType(c.colType) {
+			colDescriptor.MaxLen = c.maxLen
 		}
 
 		colsByPos[off+i] = colDescriptor
 		colsBySel[colDescriptor.Selector()] = colDescriptor
 	}
 
-	return &rawRowReader{
-		tx:         tx,
-		table:      table,
-		period:     period,
-		tableAlias: tableAlias,
-		colsByPos:  colsByPos,
-		colsBySel:  colsBySel,
-		scanSpecs:  scanSpecs,
-		params:     params,
-		reader:     r,
-	}, nil
+	rowReader.colsByPos = colsByPos
+	rowReader.colsBySel = colsBySel
+
+	if scanSpecs.lazyDecoding {
+		rowReader.colPosBySel = make(map[string]int, nCols)
+		for i, col := range colsByPos {
+			rowReader.colPosBySel[col.Selector()] = i
+		}
+	}
+	rowReader.reader = r
+
+	return rowReader, nil
 }
 
 func keyReaderSpecFrom(sqlPrefix []byte, table *Table, scanSpecs *ScanSpecs) (spec *store.KeyReaderSpec, err error) {
-	prefix := MapKey(sqlPrefix, MappedPrefix, EncodeID(table.id), EncodeID(scanSpecs.Index.id))
+	prefix := MapKey(sqlPrefix, string(table.keyEncoder().IndexPrefix(table.id, scanSpecs.Index.id)))
 
 	var loKey []byte
 	var loKeyReady bool
@@ -287,11 +418,12 @@ func keyReaderSpecFrom(sqlPrefix []byte, table *Table, scanSpecs *ScanSpecs) (sp
 			if colRange.hRange == nil {
 				hiKeyReady = true
 			} else {
-				encVal, _, err := EncodeValueAsKey(colRange.hRange.val, col.colType, col.MaxLen())
+				encVal, err := scanSpecs.Index.encodeRangeBound(colRange.hRange.val, col)
 				if err != nil {
 					return nil, err
 				}
 				hiKey = append(hiKey, encVal...)
+				hiKeyReady = encVal == nil
 			}
 		}
 
@@ -299,11 +431,12 @@ func keyReaderSpecFrom(sqlPrefix []byte, table *Table, scanSpecs *ScanSpecs) (sp
 			if colRange.lRange == nil {
 				loKeyReady = true
 			} else {
-				encVal, _, err := EncodeValueAsKey(colRange.lRange.val, col.colType, col.MaxLen())
+				encVal, err := scanSpecs.Index.encodeRangeBound(colRange.lRange.val, col)
 				if err != nil {
 					return nil, err
 				}
 				loKey = append(loKey, encVal...)
+				loKeyReady = encVal == nil
 			}
 		}
 	}
@@ -318,9 +451,16 @@ func keyReaderSpecFrom(sqlPrefix []byte, table *Table, scanSpecs *ScanSpecs) (sp
 		seekKey, endKey = endKey, seekKey
 	}
 
+	inclusiveSeek := true
+
+	if scanSpecs.resumeAfter != nil {
+		seekKey = scanSpecs.resumeAfter
+		inclusiveSeek = false
+	}
+
 	return &store.KeyReaderSpec{
 		SeekKey:        seekKey,
-		InclusiveSeek:  true,
+		InclusiveSeek:  inclusiveSeek,
 		EndKey:         endKey,
 		InclusiveEnd:   true,
 		Prefix:         prefix,
@@ -330,6 +470,24 @@ func keyReaderSpecFrom(sqlPrefix []byte, table *Table, scanSpecs *ScanSpecs) (sp
 	}, nil
 }
 
+// encodeRangeBound returns the key of a bound of the range of values of the column of the index,
+// or nil when the bound can't be a value of the column, i.e. a FLOAT out of the range of INTEGER
+// values or a TIMESTAMP out of the range of indexed ones, thus the range can't be narrowed by it.
+// Bounds are inclusive and rows are filtered by the condition the range was derived from, so the
+// key of a bound converted to the type of the column only needs to preserve the order of values.
+func (i *Index) encodeRangeBound(val TypedValue, col *Column) ([]byte, error) {
+	encVal, _, err := i.encodeValueAsKey(val, col)
+	if errors.Is(err, ErrInvalidValue) && isUnboundedRange(val.Type(), col.colType) {
+		return nil, nil
+	}
+	return encVal, err
+}
+
+func isUnboundedRange(valType, colType SQLValueType) bool {
+	return (valType == Float64Type && colType == IntegerType) ||
+		(valType == TimestampType && colType == TimestampType)
+}
+
 func (r *rawRowReader) onClose(callback func()) {
 	r.onCloseCallback = callback
 }
@@ -402,7 +560,16 @@ func (r *rawRowReader) Parameters() map[string]interface{} {
 }
 
 func (r *rawRowReader) reduceTxRange() (err error) {
-	if r.txRange != nil || (r.period.start == nil && r.period.end == nil) {
+	if r.txRange != nil {
+		return nil
+	}
+
+	// rows are read as of the snapshot of the transaction. Tx ranges yield the last version of each
+	// row within the range, so the versions following the snapshot are instead skipped when reading
+	// the history of rows, while full-text scans read the current state of the table
+	pinned := r.tx.snapshotTxID > 0 && !r.scanSpecs.IncludeHistory && r.scanSpecs.fullTextMatch == nil
+
+	if !pinned && r.period.start == nil && r.period.end == nil {
 		return nil
 	}
 
@@ -411,6 +578,10 @@ func (r *rawRowReader) reduceTxRange() (err error) {
 		finalTxID:   uint64(math.MaxUint64),
 	}
 
+	if pinned {
+		txRange.finalTxID = r.tx.snapshotTxID
+	}
+
 	if r.period.start != nil {
 		txRange.initialTxID, err = r.period.start.instant.resolve(r.tx, r.params, true, r.period.start.inclusive)
 		if err != nil {
@@ -419,10 +590,11 @@ func (r *rawRowReader) reduceTxRange() (err error) {
 	}
 
 	if r.period.end != nil {
-		txRange.finalTxID, err = r.period.end.instant.resolve(r.tx, r.params, false, r.period.end.inclusive)
+		finalTxID, err := r.period.end.instant.resolve(r.tx, r.params, false, r.period.end.inclusive)
 		if err != nil {
 			return err
 		}
+		txRange.finalTxID = min(txRange.finalTxID, finalTxID)
 	}
 
 	r.txRange = txRange
@@ -430,7 +602,23 @@ func (r *rawRowReader) reduceTxRange() (err error) {
 	return nil
 }
 
+func (r *rawRowReader) All(ctx context.Context) iter.Seq2[*Row, error] {
+	return allRows(ctx, r)
+}
+
 func (r *rawRowReader) Read(ctx context.Context) (*Row, error) {
+	return r.lookahead.read(ctx, r.readRow)
+}
+
+func (r *rawRowReader) Peek(ctx context.Context) (*Row, error) {
+	return r.lookahead.peek(ctx, r.readRow)
+}
+
+func (r *rawRowReader) readRow(ctx context.Context) (*Row, error) {
+	if r.closed {
+		return nil, ErrReaderClosed
+	}
+
 	if err := ctx.Err(); err != nil {
 		return nil, err
 	}
@@ -447,20 +635,103 @@ func (r *rawRowReader) Read(ctx context.Context) (*Row, error) {
 		return nil, err
 	}
 
-	if r.txRange == nil {
-		_, vref, err = r.reader.Read(ctx) //mkey
-	} else {
-		_, vref, err = r.reader.ReadBetween(ctx, r.txRange.initialTxID, r.txRange.finalTxID) //mkey
-	}
+	vref, err = r.readEntry(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	return r.decodeRow(vref)
+}
+
+// readEntry reads the next entry within the tx range, skipping the versions of the history of rows
+// which were committed after the snapshot of the transaction
+func (r *rawRowReader) readEntry(ctx context.Context) (vref store.ValueRef, err error) {
+	for {
+		if r.txRange == nil {
+			_, vref, err = r.reader.Read(ctx) //mkey
+		} else {
+			_, vref, err = r.reader.ReadBetween(ctx, r.txRange.initialTxID, r.txRange.finalTxID) //mkey
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		err = r.resources.scanned()
+		if err != nil {
+			return nil, err
+		}
+
+		if r.scanSpecs.IncludeHistory && r.tx.snapshotTxID > 0 && vref.Tx() > r.tx.snapshotTxID {
+			continue
+		}
+		return vref, nil
+	}
+}
+
+// skip discards up to n rows without resolving nor decoding their values,
+// the number of rows actually skipped is returned
+func (r *rawRowReader) skip(ctx context.Context, n int) (int, error) {
+	if r.closed {
+		return 0, ErrReaderClosed
+	}
+
+	err := r.reduceTxRange()
+	if errors.Is(err, store.ErrTxNotFound) {
+		return 0, ErrNoMoreRows
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	for skipped := 0; skipped < n; skipped++ {
+		if err := ctx.Err(); err != nil {
+			return skipped, err
+		}
+
+		_, err = r.readEntry(ctx)
+		if err != nil {
+			return skipped, err
+		}
+	}
+	return n, nil
+}
+
+// satisfiesConflictFilter tells if the row stored in the given entry may be part of the result
+// of the query. Rows whose conditions can not be evaluated are conservatively considered so.
+func (r *rawRowReader) satisfiesConflictFilter(_ []byte, vref store.ValueRef) (bool, error) {
+	row, err := r.decodeRow(vref)
+	if err != nil {
+		return false, err
+	}
+
+	val, err := r.scanSpecs.conflictFilter.reduce(r.tx, row, r.tableAlias)
+	if err != nil {
+		return true, nil
+	}
+
+	if val.IsNull() {
+		return false, nil
+	}
+
+	satisfied, isBool := val.RawValue().(bool)
+	return !isBool || satisfied, nil
+}
+
+// decodeRow decodes the row as of the version of the table in the catalog read by the transaction.
+// Since column ids are never reused, values of columns unknown to that version, dropped or added
+// later on, are skipped, while columns added after the row was written are NULL.
+func (r *rawRowReader) decodeRow(vref store.ValueRef) (*Row, error) {
 	v, err := vref.Resolve()
 	if err != nil {
 		return nil, err
 	}
 
+	if r.colPosBySel != nil {
+		return r.decodeLazyRow(vref, v)
+	}
+
+	extraCols := r.scanSpecs.extraCols()
+
 	valuesByPosition := make([]TypedValue, len(r.colsByPos))
 	valuesBySelector := make(map[string]TypedValue, len(r.colsBySel))
 
@@ -480,6 +751,12 @@ func (r *rawRowReader) Read(ctx context.Context) (*Row, error) {
 		}
 
 		valuesByPosition[i] = val
+
+		// values of columns not accessed by the query are not decoded, thus they fail to be read
+		// by selector instead of being taken as NULL
+		if i >= extraCols && !r.scanSpecs.needsCol(r.table.cols[i-extraCols].id) {
+			continue
+		}
 		valuesBySelector[col.Selector()] = val
 	}
 
@@ -487,8 +764,6 @@ func (r *rawRowReader) Read(ctx context.Context) (*Row, error) {
 		return nil, ErrCorruptedData
 	}
 
-	extraCols := r.scanSpecs.extraCols()
-
 	voff := 0
 
 	cols := int(binary.BigEndian.Uint32(v[voff:]))
@@ -503,8 +778,8 @@ func (r *rawRowReader) Read(ctx context.Context) (*Row, error) {
 		voff += EncIDLen
 
 		col, err := r.table.GetColumnByID(colID)
-		if errors.Is(err, ErrColumnDoesNotExist) && colID <= r.table.maxColID {
-			// Dropped column, skip it
+		if errors.Is(err, ErrColumnDoesNotExist) || (err == nil && !r.scanSpecs.needsCol(colID)) {
+			// Dropped column, added after the catalog was read or not accessed by the query, skip it
 			vlen, n, err := DecodeValueLength(v[voff:])
 			if err != nil {
 				return nil, err
@@ -540,13 +815,105 @@ func (r *rawRowReader) Read(ctx context.Context) (*Row, error) {
 		valuesBySelector[EncodeSelector("", r.tableAlias, col.colName)] = val
 	}
 
-	if len(v)-voff > 0 {
-		return nil, ErrCorruptedData
+	err = r.table.verifyRowChecksum(v, voff)
+	if err != nil {
+		return nil, err
 	}
 
 	return &Row{ValuesByPosition: valuesByPosition, ValuesBySelector: valuesBySelector}, nil
 }
 
+// decodeLazyRow only determines where the value of each column is encoded,
+// values are decoded when accessed
+func (r *rawRowReader) decodeLazyRow(vref store.ValueRef, v []byte) (*Row, error) {
+	if len(v) < EncLenLen {
+		return nil, ErrCorruptedData
+	}
+
+	extraCols := r.scanSpecs.extraCols()
+
+	encVals := make([][]byte, len(r.colsByPos))
+
+	voff := 0
+
+	cols := int(binary.BigEndian.Uint32(v[voff:]))
+	voff += EncLenLen
+
+	for i, pos := 0, 0; i < cols; i++ {
+		if len(v)-voff < EncIDLen {
+			return nil, ErrCorruptedData
+		}
+
+		colID := binary.BigEndian.Uint32(v[voff:])
+		voff += EncIDLen
+
+		vlen, n, err := DecodeValueLength(v[voff:])
+		if err != nil {
+			return nil, err
+		}
+
+		encVal := v[voff : voff+n+vlen]
+		voff += n + vlen
+
+		// make sure value is in the correct position
+		for pos < len(r.table.cols) && r.table.cols[pos].id < colID {
+			pos++
+		}
+
+		if pos == len(r.table.cols) || r.table.cols[pos].id != colID {
+			// Dropped column or added after the catalog was read, skip it
+			continue
+		}
+
+		encVals[pos+extraCols] = encVal
+
+		pos++
+	}
+
+	err := r.table.verifyRowChecksum(v, voff)
+	if err != nil {
+		return nil, err
+	}
+
+	valuesByPosition := make([]TypedValue, len(r.colsByPos))
+	valuesBySelector := make(map[string]TypedValue, len(r.colsBySel))
+
+	for i, col := range r.colsByPos {
+		if encVals[i] != nil {
+			continue
+		}
+
+		var val TypedValue
+
+		switch col.Column {
+		case revCol:
+			val = &Integer{val: int64(vref.HC())}
+		case txMetadataCol:
+			var err error
+
+			val, err = r.parseTxMetadata(vref.TxMetadata())
+			if err != nil {
+				return nil, err
+			}
+		default:
+			val = &NullValue{t: col.Type}
+		}
+
+		valuesByPosition[i] = val
+		valuesBySelector[col.Selector()] = val
+	}
+
+	return &Row{
+		ValuesByPosition: valuesByPosition,
+		ValuesBySelector: valuesBySelector,
+		lazy: &lazyValues{
+			encVals:  encVals,
+			cols:     r.colsByPos,
+			posBySel: r.colPosBySel,
+		},
+	}, nil
+}
+
 func (r *rawRowReader) parseTxMetadata(txmd *store.TxMetadata) (TypedValue, error) {
 	if txmd == nil {
 		return &NullValue{t: JSONType}, nil
@@ -567,6 +934,10 @@ func (r *rawRowReader) parseTxMetadata(txmd *store.TxMetadata) (TypedValue, erro
 }
 
 func (r *rawRowReader) Close() error {
+	r.lookahead.discard()
+
+	r.closed = true
+
 	if r.onCloseCallback != nil {
 		defer r.onCloseCallback()
 	}
@@ -574,6 +945,66 @@ func (r *rawRowReader) Close() error {
 	return r.reader.Close()
 }
 
+// allRows iterates over the rows returned by the reader until no more rows are
+// available or an error occurs, in which case the error is yielded as the last element.
+func allRows(ctx context.Context, r RowReader) iter.Seq2[*Row, error] {
+	return func(yield func(*Row, error) bool) {
+		for {
+			row, err := r.Read(ctx)
+			if errors.Is(err, ErrNoMoreRows) {
+				break
+			}
+			if err != nil {
+				r.Close()
+				yield(nil, err)
+				return
+			}
+
+			if !yield(row, nil) {
+				r.Close()
+				return
+			}
+		}
+
+		err := r.Close()
+		if err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// rowLookahead keeps the row read in advance by Peek until it gets consumed by Read.
+// It's used by the readers which can not look at their next row without reading it.
+type rowLookahead struct {
+	row    *Row
+	err    error
+	peeked bool
+}
+
+func (la *rowLookahead) peek(ctx context.Context, read func(context.Context) (*Row, error)) (*Row, error) {
+	if !la.peeked {
+		la.row, la.err = read(ctx)
+		la.peeked = true
+	}
+	return la.row, la.err
+}
+
+func (la *rowLookahead) read(ctx context.Context, read func(context.Context) (*Row, error)) (*Row, error) {
+	if !la.peeked {
+		return read(ctx)
+	}
+
+	row, err := la.row, la.err
+	la.discard()
+
+	return row, err
+}
+
+// discard drops the peeked row, if any, e.g. when the reader gets closed
+func (la *rowLookahead) discard() {
+	*la = rowLookahead{}
+}
+
 func ReadAllRows(ctx context.Context, reader RowReader) ([]*Row, error) {
 	var rows []*Row
 	err := ReadRowsBatch(ctx, reader, 100, func(rowBatch []*Row) error {
@@ -586,11 +1017,18 @@ func ReadAllRows(ctx context.Context, reader RowReader) ([]*Row, error) {
 	return rows, err
 }
 
+// ReadRowsBatch reads rows in batches of at most batchSize rows, calling onBatch once per batch.
+// The next batch is not read until onBatch returns, so a slow consumer pauses the reader instead
+// of rows being buffered. Reading stops as soon as ctx is cancelled.
 func ReadRowsBatch(ctx context.Context, reader RowReader, batchSize int, onBatch func([]*Row) error) error {
 	rows := make([]*Row, batchSize)
 
 	hasMoreRows := true
 	for hasMoreRows {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
 		n, err := readNRows(ctx, reader, batchSize, rows)
 
 		if n > 0 {
@@ -17,8 +17,11 @@ limitations under the License.
 package sql
 
 import (
+	"context"
+	"errors"
 	"testing"
 
+	"github.com/codenotary/immudb/embedded/store"
 	"github.com/stretchr/testify/require"
 )
 
@@ -70,3 +73,560 @@ func TestKeyReaderSpecFromCornerCases(t *testing.T) {
 		require.ErrorIs(t, err, ErrInvalidValue)
 	})
 }
+
+func TestRowComparison(t *testing.T) {
+	row := func(vals ...TypedValue) *Row {
+		return &Row{ValuesByPosition: vals}
+	}
+
+	nullsEqual := RowComparator{NullsEqual: true}
+	nullsNotEqual := RowComparator{NullsEqual: false}
+
+	t.Run("equal rows", func(t *testing.T) {
+		a := row(NewInteger(1), NewVarchar("immudb"), NewBool(true))
+		b := row(NewInteger(1), NewVarchar("immudb"), NewBool(true))
+
+		require.True(t, RowsEqual(a, b))
+
+		for _, c := range []RowComparator{nullsEqual, nullsNotEqual} {
+			res, err := c.Compare(a, b)
+			require.NoError(t, err)
+			require.Zero(t, res)
+
+			eq, err := c.Equal(a, b)
+			require.NoError(t, err)
+			require.True(t, eq)
+		}
+
+		require.True(t, RowsEqual(row(NewInteger(2)), row(NewFloat64(2))))
+		require.True(t, RowsEqual(row(), row()))
+	})
+
+	t.Run("rows differing in one value", func(t *testing.T) {
+		a := row(NewInteger(1), NewVarchar("immudb"), NewBool(true))
+		b := row(NewInteger(1), NewVarchar("immudb"), NewBool(false))
+
+		require.False(t, RowsEqual(a, b))
+
+		res, err := nullsEqual.Compare(a, b)
+		require.NoError(t, err)
+		require.Equal(t, 1, res)
+
+		res, err = nullsEqual.Compare(b, a)
+		require.NoError(t, err)
+		require.Equal(t, -1, res)
+
+		eq, err := nullsNotEqual.Equal(a, b)
+		require.NoError(t, err)
+		require.False(t, eq)
+	})
+
+	t.Run("null values", func(t *testing.T) {
+		a := row(NewInteger(1), NewNull(VarcharType))
+		b := row(NewInteger(1), NewNull(VarcharType))
+		c := row(NewInteger(1), NewVarchar("immudb"))
+
+		require.True(t, RowsEqual(a, b))
+		require.True(t, RowsEqual(a, row(NewInteger(1), NewNull(AnyType))))
+		require.False(t, RowsEqual(a, c))
+
+		eq, err := nullsEqual.Equal(a, b)
+		require.NoError(t, err)
+		require.True(t, eq)
+
+		eq, err = nullsNotEqual.Equal(a, b)
+		require.NoError(t, err)
+		require.False(t, eq)
+
+		for _, cmp := range []RowComparator{nullsEqual, nullsNotEqual} {
+			// NULL values are ordered first
+			res, err := cmp.Compare(a, c)
+			require.NoError(t, err)
+			require.Equal(t, -1, res)
+
+			res, err = cmp.Compare(c, a)
+			require.NoError(t, err)
+			require.Equal(t, 1, res)
+
+			eq, err := cmp.Equal(a, c)
+			require.NoError(t, err)
+			require.False(t, eq)
+		}
+	})
+
+	t.Run("rows of different arity", func(t *testing.T) {
+		a := row(NewInteger(1), NewVarchar("immudb"))
+		b := row(NewInteger(1))
+
+		require.False(t, RowsEqual(a, b))
+
+		_, err := nullsEqual.Compare(a, b)
+		require.ErrorIs(t, err, ErrNotComparableValues)
+
+		_, err = nullsNotEqual.Equal(b, a)
+		require.ErrorIs(t, err, ErrNotComparableValues)
+	})
+
+	t.Run("values of incompatible types", func(t *testing.T) {
+		a := row(NewInteger(1))
+		b := row(NewVarchar("1"))
+
+		require.False(t, RowsEqual(a, b))
+
+		_, err := nullsEqual.Compare(a, b)
+		require.ErrorIs(t, err, ErrNotComparableValues)
+	})
+}
+
+func TestReadAfterClose(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, `
+		CREATE TABLE t (id INTEGER, n INTEGER, PRIMARY KEY id);
+		INSERT INTO t (id, n) VALUES (1, 1), (2, 2), (3, 3);
+	`, nil)
+	require.NoError(t, err)
+
+	queries := []string{
+		"SELECT id FROM t",
+		"SELECT id FROM t WHERE n > 1",
+		"SELECT id FROM t ORDER BY n DESC",
+		"SELECT n, COUNT(*) FROM t GROUP BY n",
+		"SELECT id FROM t LIMIT 2",
+		"SELECT id FROM t LIMIT 1 OFFSET 1",
+		"SELECT DISTINCT n FROM t",
+		"SELECT id FROM t UNION ALL SELECT n FROM t",
+		"SELECT t1.id FROM t AS t1 INNER JOIN t AS t2 ON t1.id = t2.n",
+		"SELECT * FROM (VALUES (1), (2), (3))",
+	}
+
+	t.Run("fully consumed readers return ErrNoMoreRows", func(t *testing.T) {
+		for _, sql := range queries {
+			r, err := engine.Query(context.Background(), nil, sql, nil)
+			require.NoError(t, err)
+
+			for {
+				_, err = r.Read(context.Background())
+				if err != nil {
+					break
+				}
+			}
+			require.ErrorIs(t, err, ErrNoMoreRows, sql)
+
+			_, err = r.Read(context.Background())
+			require.ErrorIs(t, err, ErrNoMoreRows, sql)
+
+			require.NoError(t, r.Close())
+		}
+	})
+
+	t.Run("readers closed mid-scan return ErrReaderClosed", func(t *testing.T) {
+		for _, sql := range queries {
+			r, err := engine.Query(context.Background(), nil, sql, nil)
+			require.NoError(t, err)
+
+			_, err = r.Read(context.Background())
+			require.NoError(t, err, sql)
+
+			require.NoError(t, r.Close())
+
+			_, err = r.Read(context.Background())
+			require.ErrorIs(t, err, ErrReaderClosed, sql)
+			require.False(t, errors.Is(err, ErrNoMoreRows), sql)
+
+			// closed readers were also reported as already closed
+			require.ErrorIs(t, err, ErrAlreadyClosed, sql)
+		}
+	})
+
+	t.Run("consumed readers return ErrReaderClosed once closed", func(t *testing.T) {
+		for _, sql := range queries {
+			r, err := engine.Query(context.Background(), nil, sql, nil)
+			require.NoError(t, err)
+
+			for {
+				_, err = r.Read(context.Background())
+				if err != nil {
+					break
+				}
+			}
+			require.ErrorIs(t, err, ErrNoMoreRows, sql)
+
+			require.NoError(t, r.Close())
+
+			_, err = r.Read(context.Background())
+			require.ErrorIs(t, err, ErrReaderClosed, sql)
+		}
+	})
+}
+
+func TestPeek(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, `
+		CREATE TABLE t (id INTEGER, n INTEGER, PRIMARY KEY id);
+		INSERT INTO t (id, n) VALUES (1, 1), (2, 2), (3, 2), (4, 3);
+	`, nil)
+	require.NoError(t, err)
+
+	queries := []string{
+		"SELECT id FROM t",
+		"SELECT id FROM t WHERE n > 1",
+		"SELECT id FROM t ORDER BY n DESC",
+		"SELECT n, COUNT(*) FROM t GROUP BY n",
+		"SELECT id FROM t LIMIT 2",
+		"SELECT id, n FROM t ORDER BY n FETCH FIRST 2 ROWS WITH TIES",
+		"SELECT id FROM t LIMIT 1 OFFSET 1",
+		"SELECT DISTINCT n FROM t",
+		"SELECT id FROM t UNION ALL SELECT n FROM t",
+		"SELECT t1.id FROM t AS t1 INNER JOIN t AS t2 ON t1.id = t2.n",
+		"SELECT * FROM (VALUES (1), (2), (3))",
+	}
+
+	t.Run("peeked rows are returned by the next read", func(t *testing.T) {
+		for _, sql := range queries {
+			expected, err := engine.queryAll(context.Background(), nil, sql, nil)
+			require.NoError(t, err)
+			require.NotEmpty(t, expected, sql)
+
+			r, err := engine.Query(context.Background(), nil, sql, nil)
+			require.NoError(t, err)
+
+			for _, expectedRow := range expected {
+				row, err := r.Peek(context.Background())
+				require.NoError(t, err, sql)
+				require.Equal(t, expectedRow.ValuesByPosition, row.ValuesByPosition, sql)
+
+				// repeated peeks don't move the reader forward
+				peekedAgain, err := r.Peek(context.Background())
+				require.NoError(t, err, sql)
+				require.Same(t, row, peekedAgain, sql)
+
+				readRow, err := r.Read(context.Background())
+				require.NoError(t, err, sql)
+				require.Same(t, row, readRow, sql)
+			}
+
+			_, err = r.Peek(context.Background())
+			require.ErrorIs(t, err, ErrNoMoreRows, sql)
+
+			_, err = r.Peek(context.Background())
+			require.ErrorIs(t, err, ErrNoMoreRows, sql)
+
+			_, err = r.Read(context.Background())
+			require.ErrorIs(t, err, ErrNoMoreRows, sql)
+
+			require.NoError(t, r.Close())
+		}
+	})
+
+	t.Run("rows can be read without being peeked", func(t *testing.T) {
+		for _, sql := range queries {
+			expected, err := engine.queryAll(context.Background(), nil, sql, nil)
+			require.NoError(t, err)
+
+			r, err := engine.Query(context.Background(), nil, sql, nil)
+			require.NoError(t, err)
+
+			for i, expectedRow := range expected {
+				if i%2 == 0 {
+					_, err := r.Peek(context.Background())
+					require.NoError(t, err, sql)
+				}
+
+				row, err := r.Read(context.Background())
+				require.NoError(t, err, sql)
+				require.Equal(t, expectedRow.ValuesByPosition, row.ValuesByPosition, sql)
+			}
+
+			_, err = r.Read(context.Background())
+			require.ErrorIs(t, err, ErrNoMoreRows, sql)
+
+			require.NoError(t, r.Close())
+		}
+	})
+
+	t.Run("peeked rows are discarded when the reader is closed", func(t *testing.T) {
+		for _, sql := range queries {
+			r, err := engine.Query(context.Background(), nil, sql, nil)
+			require.NoError(t, err)
+
+			_, err = r.Peek(context.Background())
+			require.NoError(t, err, sql)
+
+			require.NoError(t, r.Close())
+
+			_, err = r.Read(context.Background())
+			require.ErrorIs(t, err, ErrReaderClosed, sql)
+
+			_, err = r.Peek(context.Background())
+			require.ErrorIs(t, err, ErrReaderClosed, sql)
+		}
+	})
+
+	t.Run("conditional reader", func(t *testing.T) {
+		rows := []*Row{
+			{ValuesByPosition: []TypedValue{NewInteger(1)}},
+			{ValuesByPosition: []TypedValue{NewInteger(2)}},
+			{ValuesByPosition: []TypedValue{NewInteger(3)}},
+		}
+
+		r := newConditionalRowReader(&mockRowReader{rows: rows}, &mockValueExp{
+			shouldPass: func(row *Row) bool { return row.ValuesByPosition[0].RawValue().(int64) != 2 },
+		})
+
+		row, err := r.Peek(context.Background())
+		require.NoError(t, err)
+		require.Same(t, rows[0], row)
+
+		row, err = r.Read(context.Background())
+		require.NoError(t, err)
+		require.Same(t, rows[0], row)
+
+		// rows not satisfying the condition are skipped when peeking
+		row, err = r.Peek(context.Background())
+		require.NoError(t, err)
+		require.Same(t, rows[2], row)
+
+		row, err = r.Peek(context.Background())
+		require.NoError(t, err)
+		require.Same(t, rows[2], row)
+
+		row, err = r.Read(context.Background())
+		require.NoError(t, err)
+		require.Same(t, rows[2], row)
+
+		_, err = r.Peek(context.Background())
+		require.ErrorIs(t, err, ErrNoMoreRows)
+
+		_, err = r.Read(context.Background())
+		require.ErrorIs(t, err, ErrNoMoreRows)
+	})
+
+	t.Run("limit with ties leaves the first row not tying unread", func(t *testing.T) {
+		rows := []*Row{
+			{ValuesByPosition: []TypedValue{NewInteger(1)}, ValuesBySelector: map[string]TypedValue{EncodeSelector("", "t", "n"): NewInteger(1)}},
+			{ValuesByPosition: []TypedValue{NewInteger(1)}, ValuesBySelector: map[string]TypedValue{EncodeSelector("", "t", "n"): NewInteger(1)}},
+			{ValuesByPosition: []TypedValue{NewInteger(2)}, ValuesBySelector: map[string]TypedValue{EncodeSelector("", "t", "n"): NewInteger(2)}},
+		}
+
+		inner := &mockRowReader{rows: rows, tableAlias: "t"}
+		r := newLimitWithTiesRowReader(inner, 1, []*OrdExp{{exp: &ColSelector{col: "n"}}})
+
+		for _, expected := range rows[:2] {
+			row, err := r.Peek(context.Background())
+			require.NoError(t, err)
+			require.Same(t, expected, row)
+
+			row, err = r.Read(context.Background())
+			require.NoError(t, err)
+			require.Same(t, expected, row)
+		}
+
+		_, err := r.Peek(context.Background())
+		require.ErrorIs(t, err, ErrNoMoreRows)
+
+		_, err = r.Read(context.Background())
+		require.ErrorIs(t, err, ErrNoMoreRows)
+
+		row, err := inner.Read(context.Background())
+		require.NoError(t, err)
+		require.Same(t, rows[2], row)
+	})
+}
+
+func TestReadsFromTheSnapshotOfTheTx(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, `
+		CREATE TABLE t (id INTEGER, n INTEGER, PRIMARY KEY id);
+		CREATE INDEX ON t (n);
+		CREATE TABLE other (id INTEGER, PRIMARY KEY id);
+		INSERT INTO t (id, n) VALUES (1, 10), (3, 30), (5, 50);
+		INSERT INTO other (id) VALUES (1);
+	`, nil)
+	require.NoError(t, err)
+
+	readAll := func(t *testing.T, r RowReader) [][]interface{} {
+		var values [][]interface{}
+		for {
+			row, err := r.Read(context.Background())
+			if errors.Is(err, ErrNoMoreRows) {
+				return values
+			}
+			require.NoError(t, err)
+
+			var vals []interface{}
+			for _, v := range row.ValuesByPosition {
+				vals = append(vals, v.RawValue())
+			}
+			values = append(values, vals)
+		}
+	}
+
+	exec := func(t *testing.T, sql string) {
+		_, _, err := engine.Exec(context.Background(), nil, sql, nil)
+		require.NoError(t, err)
+	}
+
+	for _, d := range []struct {
+		sql      string
+		expected [][]interface{}
+	}{
+		{"SELECT id, n FROM t", [][]interface{}{{int64(1), int64(10)}, {int64(3), int64(30)}, {int64(5), int64(50)}}},
+		{"SELECT id, n FROM t ORDER BY id DESC", [][]interface{}{{int64(5), int64(50)}, {int64(3), int64(30)}, {int64(1), int64(10)}}},
+		{"SELECT id, n FROM t USE INDEX ON (n) WHERE n >= 10", [][]interface{}{{int64(1), int64(10)}, {int64(3), int64(30)}, {int64(5), int64(50)}}},
+		{"SELECT t.id, other.id FROM t LEFT JOIN other ON t.id = other.id", [][]interface{}{{int64(1), int64(1)}, {int64(3), nil}, {int64(5), nil}}},
+	} {
+		t.Run(d.sql, func(t *testing.T) {
+			r, err := engine.Query(context.Background(), nil, d.sql, nil)
+			require.NoError(t, err)
+			defer r.Close()
+
+			row, err := r.Read(context.Background())
+			require.NoError(t, err)
+
+			// rows are inserted, updated and deleted while the rows are being read
+			exec(t, "INSERT INTO t (id, n) VALUES (2, 20), (4, 40), (6, 60), (0, 0)")
+			exec(t, "UPDATE t SET n = n + 1")
+			exec(t, "DELETE FROM t WHERE id = 3")
+			exec(t, "INSERT INTO other (id) VALUES (3), (5)")
+
+			values := [][]interface{}{nil}
+			for _, v := range row.ValuesByPosition {
+				values[0] = append(values[0], v.RawValue())
+			}
+			require.Equal(t, d.expected, append(values, readAll(t, r)...))
+
+			// the changes are observed by later queries
+			r2, err := engine.Query(context.Background(), nil, d.sql, nil)
+			require.NoError(t, err)
+			defer r2.Close()
+			require.NotEqual(t, d.expected, readAll(t, r2))
+
+			exec(t, "DELETE FROM t WHERE id IN (0, 2, 4, 6)")
+			exec(t, "DELETE FROM other WHERE id > 1")
+			exec(t, "UPDATE t SET n = id * 10")
+			exec(t, "INSERT INTO t (id, n) VALUES (3, 30)")
+		})
+	}
+
+	t.Run("history of rows", func(t *testing.T) {
+		r, err := engine.Query(context.Background(), nil, "SELECT id, n FROM (HISTORY OF t) WHERE id = 1", nil)
+		require.NoError(t, err)
+		defer r.Close()
+
+		expected, err := engine.queryAll(context.Background(), nil, "SELECT id, n FROM (HISTORY OF t) WHERE id = 1", nil)
+		require.NoError(t, err)
+
+		exec(t, "UPDATE t SET n = 11 WHERE id = 1")
+
+		rows := readAll(t, r)
+		require.Len(t, rows, len(expected))
+		require.Equal(t, expected[len(expected)-1].ValuesByPosition[1].RawValue(), rows[len(rows)-1][1])
+	})
+
+	t.Run("read-only transactions", func(t *testing.T) {
+		tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithReadOnly(true).WithExplicitClose(true))
+		require.NoError(t, err)
+		defer tx.Cancel()
+
+		r, err := engine.Query(context.Background(), tx, "SELECT COUNT(*) FROM other", nil)
+		require.NoError(t, err)
+		require.Equal(t, [][]interface{}{{int64(1)}}, readAll(t, r))
+		r.Close()
+
+		exec(t, "INSERT INTO t (id, n) VALUES (7, 70)")
+		exec(t, "INSERT INTO other (id) VALUES (7)")
+
+		// tables read for the first time within the transaction are read as of its beginning too
+		r, err = engine.Query(context.Background(), tx, "SELECT COUNT(*) FROM t", nil)
+		require.NoError(t, err)
+		require.Equal(t, [][]interface{}{{int64(3)}}, readAll(t, r))
+		r.Close()
+
+		r, err = engine.Query(context.Background(), tx, "SELECT COUNT(*) FROM other", nil)
+		require.NoError(t, err)
+		require.Equal(t, [][]interface{}{{int64(1)}}, readAll(t, r))
+		r.Close()
+	})
+
+	t.Run("read-write transactions observe their own changes", func(t *testing.T) {
+		tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithExplicitClose(true))
+		require.NoError(t, err)
+		defer tx.Cancel()
+
+		_, _, err = engine.Exec(context.Background(), tx, "INSERT INTO other (id) VALUES (8)", nil)
+		require.NoError(t, err)
+
+		r, err := engine.Query(context.Background(), tx, "SELECT COUNT(*) FROM other", nil)
+		require.NoError(t, err)
+		require.Equal(t, [][]interface{}{{int64(3)}}, readAll(t, r))
+		r.Close()
+	})
+}
+
+func TestReadRowsOfOtherVersionsOfTheTable(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, `
+		CREATE TABLE t (id INTEGER, name VARCHAR[16], PRIMARY KEY id);
+		CREATE INDEX ON t (name);
+		INSERT INTO t (id, name) VALUES (1, 'a');
+	`, nil)
+	require.NoError(t, err)
+
+	rawValues := func(t *testing.T, tx *SQLTx, sql string) [][]interface{} {
+		rows, err := engine.queryAll(context.Background(), tx, sql, nil)
+		require.NoError(t, err)
+
+		values := make([][]interface{}, len(rows))
+		for i, row := range rows {
+			for _, v := range row.ValuesByPosition {
+				values[i] = append(values[i], v.RawValue())
+			}
+		}
+		return values
+	}
+
+	// the catalog of the transaction is read before the table is altered
+	v1Tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithExplicitClose(true))
+	require.NoError(t, err)
+	defer v1Tx.Cancel()
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		ALTER TABLE t ADD COLUMN age INTEGER;
+		ALTER TABLE t ADD COLUMN city VARCHAR;
+		INSERT INTO t (id, name, age, city) VALUES (2, 'b', 30, 'rome');
+		INSERT INTO t (id, name) VALUES (3, 'c');
+	`, nil)
+	require.NoError(t, err)
+
+	t.Run("columns unknown to the version of the table are ignored", func(t *testing.T) {
+		expected := [][]interface{}{{int64(1), "a"}, {int64(2), "b"}, {int64(3), "c"}}
+
+		require.Equal(t, expected, rawValues(t, v1Tx, "SELECT * FROM t"))
+		require.Equal(t, expected, rawValues(t, v1Tx, "SELECT id, name FROM t USE INDEX ON (name)"))
+		require.Equal(t, [][]interface{}{{"b"}}, rawValues(t, v1Tx, "SELECT name FROM t WHERE id = 2"))
+
+		_, err := engine.queryAll(context.Background(), v1Tx, "SELECT age FROM t", nil)
+		require.ErrorIs(t, err, ErrColumnDoesNotExist)
+	})
+
+	t.Run("columns added after rows were written are NULL", func(t *testing.T) {
+		expected := [][]interface{}{{int64(1), "a", nil, nil}, {int64(2), "b", int64(30), "rome"}, {int64(3), "c", nil, nil}}
+
+		require.Equal(t, expected, rawValues(t, nil, "SELECT * FROM t"))
+		require.Equal(t, [][]interface{}{{int64(1), nil}}, rawValues(t, nil, "SELECT id, city FROM t WHERE age IS NULL AND name = 'a'"))
+	})
+
+	t.Run("values of unknown columns are not lost by updates", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), v1Tx, "UPDATE t SET name = 'bb' WHERE id = 2", nil)
+		require.NoError(t, err)
+
+		err = v1Tx.Commit(context.Background())
+		require.ErrorIs(t, err, store.ErrTxReadConflict)
+
+		require.Equal(t, [][]interface{}{{"b", int64(30), "rome"}}, rawValues(t, nil, "SELECT name, age, city FROM t WHERE id = 2"))
+	})
+}
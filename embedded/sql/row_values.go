@@ -0,0 +1,225 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// lazyValues holds the encoded values of the columns of a row which are decoded once accessed
+type lazyValues struct {
+	encVals  [][]byte        // encoded value by position, nil once decoded or when the value is not encoded
+	cols     []ColDescriptor // shared by all the rows read by the same reader
+	posBySel map[string]int  // shared by all the rows read by the same reader
+}
+
+// valueBySelector returns the value of the column identified by the selector, decoding it if needed
+func (row *Row) valueBySelector(sel string) (TypedValue, bool, error) {
+	v, ok := row.ValuesBySelector[sel]
+	if ok || row.lazy == nil {
+		return v, ok, nil
+	}
+
+	pos, ok := row.lazy.posBySel[sel]
+	if !ok {
+		return nil, false, nil
+	}
+
+	v, err := row.ValueAt(pos)
+	return v, err == nil, err
+}
+
+// ValueAt returns the value at the given position, decoding it if needed
+func (row *Row) ValueAt(pos int) (TypedValue, error) {
+	if pos < 0 || pos >= len(row.ValuesByPosition) {
+		return nil, fmt.Errorf("%w: invalid position %d", ErrIllegalArguments, pos)
+	}
+
+	v := row.ValuesByPosition[pos]
+	if v != nil || row.lazy == nil {
+		return v, nil
+	}
+
+	col := row.lazy.cols[pos]
+
+	v, _, err := DecodeValue(row.lazy.encVals[pos], col.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	row.ValuesByPosition[pos] = v
+	row.ValuesBySelector[col.Selector()] = v
+	row.lazy.encVals[pos] = nil
+
+	return v, nil
+}
+
+// Get returns the value of the column with the given name, which may be either
+// a column name or a selector as returned by ColDescriptor.Selector
+func (row *Row) Get(name string) (TypedValue, error) {
+	v, ok, err := row.valueBySelector(name)
+	if ok || err != nil {
+		return v, err
+	}
+
+	sel, err := row.selectorOf(name)
+	if err != nil {
+		return nil, err
+	}
+
+	v, _, err = row.valueBySelector(sel)
+	return v, err
+}
+
+// Set replaces the value of the column with the given name, resolved as by Get.
+// Only ValuesBySelector is updated, unless the values of the row are lazily decoded.
+func (row *Row) Set(name string, v TypedValue) error {
+	sel := name
+
+	_, ok, err := row.valueBySelector(name)
+	if err != nil {
+		return err
+	}
+
+	if !ok {
+		sel, err = row.selectorOf(name)
+		if err != nil {
+			return err
+		}
+	}
+
+	row.ValuesBySelector[sel] = v
+
+	if row.lazy != nil {
+		if pos, ok := row.lazy.posBySel[sel]; ok {
+			row.ValuesByPosition[pos] = v
+			row.lazy.encVals[pos] = nil
+		}
+	}
+	return nil
+}
+
+// selectorOf returns the selector of the column with the given name
+func (row *Row) selectorOf(name string) (string, error) {
+	sel := ""
+
+	for _, s := range row.selectors() {
+		aggFn, _, col, err := DecodeSelector(s)
+		if err != nil || aggFn != "" || col != name {
+			continue
+		}
+
+		if sel != "" {
+			return "", fmt.Errorf("%w (%s)", ErrAmbiguousSelector, name)
+		}
+		sel = s
+	}
+
+	if sel == "" {
+		return "", fmt.Errorf("%w (%s)", ErrColumnDoesNotExist, name)
+	}
+	return sel, nil
+}
+
+func (row *Row) selectors() []string {
+	sels := make([]string, 0, len(row.ValuesBySelector))
+
+	for sel := range row.ValuesBySelector {
+		sels = append(sels, sel)
+	}
+
+	if row.lazy != nil {
+		for pos, encVal := range row.lazy.encVals {
+			if encVal != nil {
+				sels = append(sels, row.lazy.cols[pos].Selector())
+			}
+		}
+	}
+	return sels
+}
+
+// Scan copies the values of the row, in order, into the values pointed at by dest.
+// Supported destinations are *int64, *int, *float64, *string, *bool, *[]byte, *time.Time,
+// *uuid.UUID, *TypedValue and *interface{}. NULL values can only be copied into the last two.
+func (row *Row) Scan(dest ...interface{}) error {
+	if len(dest) != len(row.ValuesByPosition) {
+		return fmt.Errorf("%w: expected %d destinations but %d were provided", ErrIllegalArguments, len(row.ValuesByPosition), len(dest))
+	}
+
+	for i, d := range dest {
+		v, err := row.ValueAt(i)
+		if err != nil {
+			return err
+		}
+
+		err = scanValue(v, d)
+		if err != nil {
+			return fmt.Errorf("%w: can not scan value at position %d", err, i)
+		}
+	}
+	return nil
+}
+
+func scanValue(v TypedValue, dest interface{}) error {
+	switch d := dest.(type) {
+	case *TypedValue:
+		*d = v
+		return nil
+	case *interface{}:
+		*d = v.RawValue()
+		return nil
+	}
+
+	if v.IsNull() {
+		return fmt.Errorf("%w: NULL can not be scanned into %T", ErrInvalidValue, dest)
+	}
+
+	var ok bool
+
+	switch d := dest.(type) {
+	case *int64:
+		*d, ok = v.RawValue().(int64)
+	case *int:
+		var n int64
+		n, ok = v.RawValue().(int64)
+		*d = int(n)
+	case *float64:
+		*d, ok = v.RawValue().(float64)
+	case *string:
+		*d, ok = v.RawValue().(string)
+	case *bool:
+		*d, ok = v.RawValue().(bool)
+	case *[]byte:
+		var b []byte
+		b, ok = v.RawValue().([]byte)
+		*d = append([]byte(nil), b...)
+	case *time.Time:
+		*d, ok = v.RawValue().(time.Time)
+	case *uuid.UUID:
+		*d, ok = v.RawValue().(uuid.UUID)
+	default:
+		return fmt.Errorf("%w: unsupported destination %T", ErrIllegalArguments, dest)
+	}
+
+	if !ok {
+		return fmt.Errorf("%w: %s value can not be scanned into %T", ErrInvalidValue, v.Type(), dest)
+	}
+	return nil
+}
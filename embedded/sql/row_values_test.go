@@ -0,0 +1,394 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// setupLazyDecodingTest returns two engines over the same store, the second one lazily decoding rows
+func setupLazyDecodingTest(t testing.TB, sopts *store.Options) (*Engine, *Engine) {
+	st, err := store.Open(t.TempDir(), sopts.WithMultiIndexing(true))
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+
+	engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+	require.NoError(t, err)
+
+	lazyEngine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix).WithLazyDecoding(true))
+	require.NoError(t, err)
+
+	return engine, lazyEngine
+}
+
+func TestLazyDecoding(t *testing.T) {
+	engine, lazyEngine := setupLazyDecodingTest(t, store.DefaultOptions())
+
+	_, _, err := engine.Exec(
+		context.Background(),
+		nil,
+		`CREATE TABLE entries (
+			id INTEGER AUTO_INCREMENT,
+			name VARCHAR[16],
+			dropped VARCHAR,
+			active BOOLEAN,
+			score FLOAT,
+			data BLOB,
+			ts TIMESTAMP,
+			uid UUID,
+			doc JSON,
+			PRIMARY KEY id
+		);
+
+		CREATE INDEX ON entries(name);
+
+		CREATE TABLE owners (id INTEGER, name VARCHAR, PRIMARY KEY id);
+		INSERT INTO owners (id, name) VALUES (1, 'owner1'), (2, 'owner2');
+		`,
+		nil,
+	)
+	require.NoError(t, err)
+
+	ts := time.Date(2025, 3, 14, 15, 9, 26, 0, time.UTC)
+
+	for i := 0; i < 20; i++ {
+		params := NewParams().
+			SetString("name", fmt.Sprintf("name%d", i%5)).
+			SetString("dropped", "dropped").
+			SetBool("active", i%2 == 0).
+			SetFloat("score", float64(i)/4).
+			SetBlob("data", []byte{byte(i)}).
+			SetTimestamp("ts", ts.Add(time.Duration(i)*time.Hour)).
+			SetUUID("uid", uuid.New())
+
+		if i%3 == 0 {
+			// some of the values are NULL
+			params.SetNull("name").SetNull("score")
+		}
+
+		_, _, err = engine.Exec(
+			context.Background(),
+			nil,
+			`INSERT INTO entries (name, dropped, active, score, data, ts, uid, doc)
+			VALUES (@name, @dropped, @active, @score, @data, @ts, @uid, '{"n": 1}')`,
+			params,
+		)
+		require.NoError(t, err)
+	}
+
+	_, _, err = engine.Exec(context.Background(), nil, "ALTER TABLE entries DROP COLUMN dropped; ALTER TABLE entries ADD COLUMN added INTEGER", nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "UPDATE entries SET added = id * 2 WHERE id > 15", nil)
+	require.NoError(t, err)
+
+	queries := []struct {
+		sql  string
+		lazy bool
+	}{
+		{sql: "SELECT * FROM entries", lazy: true},
+		{sql: "SELECT id, score FROM entries WHERE active AND score > 1.0", lazy: true},
+		{sql: "SELECT name, added FROM entries WHERE name = 'name2' OR name IS NULL", lazy: true},
+		{sql: "SELECT id, ts, uid, doc FROM entries ORDER BY id DESC LIMIT 5 OFFSET 3", lazy: true},
+		{sql: "SELECT DISTINCT name FROM entries", lazy: true},
+		{sql: "SELECT _rev, id, data FROM (HISTORY OF entries) WHERE id > 10", lazy: true},
+		{sql: "SELECT id, score FROM entries ORDER BY score, id", lazy: false},
+		{sql: "SELECT name, COUNT(*) FROM entries GROUP BY name", lazy: false},
+		{sql: "SELECT entries.id, owners.name FROM entries INNER JOIN owners ON entries.id = owners.id", lazy: false},
+	}
+
+	for _, q := range queries {
+		t.Run(q.sql, func(t *testing.T) {
+			rows, err := engine.queryAll(context.Background(), nil, q.sql, nil)
+			require.NoError(t, err)
+			require.NotEmpty(t, rows)
+
+			reader, err := lazyEngine.Query(context.Background(), nil, q.sql, nil)
+			require.NoError(t, err)
+			require.Equal(t, q.lazy, reader.ScanSpecs().lazyDecoding)
+
+			lazyRows, err := ReadAllRows(context.Background(), reader)
+			require.NoError(t, err)
+
+			require.Equal(t, rows, lazyRows)
+		})
+	}
+
+	t.Run("lazy rows should decode values once accessed", func(t *testing.T) {
+		tx, err := lazyEngine.NewTx(context.Background(), DefaultTxOptions().WithReadOnly(true))
+		require.NoError(t, err)
+		defer tx.Cancel()
+
+		table, err := tx.catalog.GetTableByName("entries")
+		require.NoError(t, err)
+
+		newReader := func(lazy bool) *rawRowReader {
+			reader, err := newRawRowReader(tx, nil, table, period{}, "", &ScanSpecs{Index: table.primaryIndex, lazyDecoding: lazy})
+			require.NoError(t, err)
+			return reader
+		}
+
+		eagerReader := newReader(false)
+		defer eagerReader.Close()
+
+		lazyReader := newReader(true)
+		defer lazyReader.Close()
+
+		cols, err := lazyReader.Columns(context.Background())
+		require.NoError(t, err)
+
+		for {
+			row, err := eagerReader.Read(context.Background())
+			if err == ErrNoMoreRows {
+				break
+			}
+			require.NoError(t, err)
+
+			lazyRow, err := lazyReader.Read(context.Background())
+			require.NoError(t, err)
+
+			id, err := lazyRow.Get("id")
+			require.NoError(t, err)
+			require.Equal(t, row.ValuesByPosition[0], id)
+
+			// NULL values are not encoded
+			require.Equal(t, row.ValuesBySelector[cols[1].Selector()].IsNull(), lazyRow.ValuesByPosition[1] != nil)
+
+			for i := 2; i < len(cols); i++ {
+				if !row.ValuesByPosition[i].IsNull() {
+					require.Nil(t, lazyRow.ValuesByPosition[i])
+					require.NotContains(t, lazyRow.ValuesBySelector, cols[i].Selector())
+				}
+			}
+
+			for i, col := range cols {
+				v, err := lazyRow.Get(col.Selector())
+				require.NoError(t, err)
+				require.Equal(t, row.ValuesByPosition[i], v)
+
+				v, err = lazyRow.ValueAt(i)
+				require.NoError(t, err)
+				require.Equal(t, row.ValuesByPosition[i], v)
+			}
+
+			require.Equal(t, row.ValuesByPosition, lazyRow.ValuesByPosition)
+			require.Equal(t, row.ValuesBySelector, lazyRow.ValuesBySelector)
+		}
+
+		_, err = lazyReader.Read(context.Background())
+		require.ErrorIs(t, err, ErrNoMoreRows)
+	})
+}
+
+func TestRowAccessors(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	uid := uuid.MustParse("a3f2d8a0-3c41-4b9a-8d2e-6f0c1b7e9d55")
+	ts := time.Date(2025, 3, 14, 15, 9, 26, 0, time.UTC)
+
+	_, _, err := engine.Exec(
+		context.Background(),
+		nil,
+		`CREATE TABLE entries (id INTEGER, name VARCHAR, active BOOLEAN, score FLOAT, data BLOB, ts TIMESTAMP, uid UUID, note VARCHAR, PRIMARY KEY id);
+		CREATE TABLE notes (id INTEGER, name VARCHAR, PRIMARY KEY id);
+		INSERT INTO notes (id, name) VALUES (1, 'note');`,
+		nil,
+	)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(
+		context.Background(),
+		nil,
+		"INSERT INTO entries (id, name, active, score, data, ts, uid) VALUES (1, 'immudb', true, 9.5, x'010203', @ts, @uid)",
+		NewParams().SetTimestamp("ts", ts).SetUUID("uid", uid),
+	)
+	require.NoError(t, err)
+
+	rows, err := engine.queryAll(context.Background(), nil, "SELECT id, name, active, score, data, ts, uid, note FROM entries", nil)
+	require.NoError(t, err)
+	require.Len(t, rows, 1)
+
+	row := rows[0]
+
+	t.Run("Scan should copy each value", func(t *testing.T) {
+		var id int
+		var name string
+		var active bool
+		var score float64
+		var data []byte
+		var rts time.Time
+		var ruid uuid.UUID
+		var note interface{}
+
+		err := row.Scan(&id, &name, &active, &score, &data, &rts, &ruid, &note)
+		require.NoError(t, err)
+		require.Equal(t, 1, id)
+		require.Equal(t, "immudb", name)
+		require.True(t, active)
+		require.Equal(t, 9.5, score)
+		require.Equal(t, []byte{1, 2, 3}, data)
+		require.Equal(t, ts, rts)
+		require.Equal(t, uid, ruid)
+		require.Nil(t, note)
+
+		var vals [8]TypedValue
+		err = row.Scan(&vals[0], &vals[1], &vals[2], &vals[3], &vals[4], &vals[5], &vals[6], &vals[7])
+		require.NoError(t, err)
+		require.Equal(t, row.ValuesByPosition, vals[:])
+	})
+
+	t.Run("Scan should fail on invalid destinations", func(t *testing.T) {
+		var id int64
+		var name string
+		var v interface{}
+
+		err := row.Scan(&id, &name)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		err = row.Scan(&name, &v, &v, &v, &v, &v, &v, &v)
+		require.ErrorIs(t, err, ErrInvalidValue)
+
+		err = row.Scan(&id, &v, &v, &v, &v, &v, &v, &name)
+		require.ErrorIs(t, err, ErrInvalidValue)
+
+		err = row.Scan(id, &v, &v, &v, &v, &v, &v, &v)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+	})
+
+	t.Run("Get should find values by column name or selector", func(t *testing.T) {
+		v, err := row.Get("name")
+		require.NoError(t, err)
+		require.Equal(t, "immudb", v.RawValue())
+
+		v, err = row.Get(EncodeSelector("", "entries", "score"))
+		require.NoError(t, err)
+		require.Equal(t, 9.5, v.RawValue())
+
+		_, err = row.Get("missing")
+		require.ErrorIs(t, err, ErrColumnDoesNotExist)
+
+		_, err = row.ValueAt(len(row.ValuesByPosition))
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT e.name, n.name FROM entries e INNER JOIN notes n ON e.id = n.id", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+
+		_, err = rows[0].Get("name")
+		require.ErrorIs(t, err, ErrAmbiguousSelector)
+
+		v, err = rows[0].Get(EncodeSelector("", "n", "name"))
+		require.NoError(t, err)
+		require.Equal(t, "note", v.RawValue())
+	})
+
+	t.Run("Set should replace values by column name or selector", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT e.name, n.name FROM entries e INNER JOIN notes n ON e.id = n.id", nil)
+		require.NoError(t, err)
+
+		row := rows[0]
+
+		err = row.Set(EncodeSelector("", "n", "name"), NewVarchar("other"))
+		require.NoError(t, err)
+
+		v, err := row.Get(EncodeSelector("", "n", "name"))
+		require.NoError(t, err)
+		require.Equal(t, "other", v.RawValue())
+
+		require.ErrorIs(t, row.Set("name", NewVarchar("x")), ErrAmbiguousSelector)
+		require.ErrorIs(t, row.Set("missing", NewVarchar("x")), ErrColumnDoesNotExist)
+
+		rows, err = engine.queryAll(context.Background(), nil, "SELECT name FROM entries", nil)
+		require.NoError(t, err)
+
+		err = rows[0].Set("name", NewVarchar("renamed"))
+		require.NoError(t, err)
+
+		v, err = rows[0].Get(EncodeSelector("", "entries", "name"))
+		require.NoError(t, err)
+		require.Equal(t, "renamed", v.RawValue())
+	})
+}
+
+func BenchmarkLazyDecoding(b *testing.B) {
+	const (
+		rowCount = 1000
+		colCount = 50
+	)
+
+	engine, lazyEngine := setupLazyDecodingTest(b, store.DefaultOptions().WithMaxTxEntries(rowCount))
+
+	colDefs := make([]string, colCount)
+	colNames := make([]string, colCount)
+	colParams := make([]string, colCount)
+
+	for i := 0; i < colCount; i++ {
+		colDefs[i] = fmt.Sprintf("c%d VARCHAR", i)
+		colNames[i] = fmt.Sprintf("c%d", i)
+		colParams[i] = fmt.Sprintf("@c%d", i)
+	}
+
+	_, _, err := engine.Exec(
+		context.Background(),
+		nil,
+		fmt.Sprintf("CREATE TABLE wide (id INTEGER AUTO_INCREMENT, %s, PRIMARY KEY id)", strings.Join(colDefs, ", ")),
+		nil,
+	)
+	require.NoError(b, err)
+
+	tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithExplicitClose(true))
+	require.NoError(b, err)
+
+	insert := fmt.Sprintf("INSERT INTO wide (%s) VALUES (%s)", strings.Join(colNames, ", "), strings.Join(colParams, ", "))
+
+	for i := 0; i < rowCount; i++ {
+		params := NewParams()
+		for j := 0; j < colCount; j++ {
+			params.SetString(colNames[j], fmt.Sprintf("value of column %d at row %d", j, i))
+		}
+
+		_, _, err = engine.Exec(context.Background(), tx, insert, params)
+		require.NoError(b, err)
+	}
+
+	err = tx.Commit(context.Background())
+	require.NoError(b, err)
+
+	bench := func(b *testing.B, engine *Engine) {
+		for i := 0; i < b.N; i++ {
+			rows, err := engine.queryAll(context.Background(), nil, "SELECT id, c1 FROM wide", nil)
+			require.NoError(b, err)
+			require.Len(b, rows, rowCount)
+		}
+	}
+
+	b.Run("eager", func(b *testing.B) {
+		bench(b, engine)
+	})
+
+	b.Run("lazy", func(b *testing.B) {
+		bench(b, lazyEngine)
+	})
+}
@@ -0,0 +1,214 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// colBound is a comparison of a column against a constant value
+type colBound struct {
+	op  CmpOperator
+	val TypedValue
+}
+
+// sargableSelectorRanges narrows the ranges of a column compared through a monotonic function,
+// e.g. DATE_TRUNC('day', ts) = '2025-01-01' is scanned as ts >= '2025-01-01' AND ts < '2025-01-02'.
+// The comparison is still evaluated over the rows being scanned, thus ranges may include rows
+// not satisfying it but never exclude rows that do.
+func (bexp *CmpBoolExp) sargableSelectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	exp, bound, op := bexp.left, bexp.right, bexp.op
+	if !bound.isConstant() {
+		exp, bound, op = bexp.right, bexp.left, flipCmpOperator(bexp.op)
+	}
+
+	if !bound.isConstant() || exp.isConstant() {
+		return nil
+	}
+
+	val, ok := reduceConstant(bound, params)
+	if !ok {
+		return nil
+	}
+
+	sel, bounds, ok := sargableBounds(exp, op, val, params)
+	if !ok {
+		return nil
+	}
+
+	aggFn, t, col := sel.resolve(table.name)
+	if aggFn != "" || t != asTable || col == revCol {
+		return nil
+	}
+
+	column, err := table.GetColumnByName(col)
+	if err != nil {
+		return err
+	}
+
+	for _, b := range bounds {
+		if b.val.Type() != column.colType {
+			return nil
+		}
+	}
+
+	for _, b := range bounds {
+		err := updateRangeFor(column.id, b.val, b.op, rangesByColID)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sargableBounds returns the bounds of the column satisfying every row for which exp op val holds
+func sargableBounds(exp ValueExp, op CmpOperator, val TypedValue, params map[string]interface{}) (*ColSelector, []colBound, bool) {
+	switch e := exp.(type) {
+	case *ColSelector:
+		return e, []colBound{{op: op, val: val}}, true
+	case *NumExp:
+		return shiftedBounds(e, op, val, params)
+	case *FnCall:
+		if strings.ToUpper(e.fn) == DateTruncFnCall && len(e.params) == 2 {
+			return dateTruncBounds(e, op, val, params)
+		}
+	}
+	return nil, nil, false
+}
+
+// shiftedBounds inverts additions and subtractions of integer constants. As integer arithmetic
+// may wrap around on overflow, only equality comparisons are kept when the shift is inverted,
+// and only when neither the shifted value nor the compared one may be the result of an overflow.
+func shiftedBounds(e *NumExp, op CmpOperator, val TypedValue, params map[string]interface{}) (*ColSelector, []colBound, bool) {
+	if (e.op != ADDOP && e.op != SUBSOP) || op != EQ {
+		return nil, nil, false
+	}
+
+	v, ok := val.RawValue().(int64)
+	if !ok || val.Type() != IntegerType {
+		return nil, nil, false
+	}
+
+	inner, c := e.left, e.right
+	constLeft := false
+
+	if !c.isConstant() {
+		inner, c = e.right, e.left
+		constLeft = true
+	}
+
+	if !c.isConstant() || inner.isConstant() {
+		return nil, nil, false
+	}
+
+	cval, ok := reduceConstant(c, params)
+	if !ok || cval.Type() != IntegerType {
+		return nil, nil, false
+	}
+
+	// a saturated result would be matched by all the values beyond it
+	if v == math.MinInt64 || v == math.MaxInt64 {
+		return nil, nil, false
+	}
+
+	var (
+		shifted TypedValue
+		err     error
+	)
+
+	switch {
+	case e.op == ADDOP:
+		shifted, err = applyNumOperator(SUBSOP, val, cval, IntegerOverflowError) // exp + n = v or n + exp = v
+	case constLeft:
+		shifted, err = applyNumOperator(SUBSOP, cval, val, IntegerOverflowError) // n - exp = v
+	default:
+		shifted, err = applyNumOperator(ADDOP, val, cval, IntegerOverflowError) // exp - n = v
+	}
+	if err != nil {
+		return nil, nil, false
+	}
+	return sargableBounds(inner, EQ, shifted, params)
+}
+
+// dateTruncBounds returns the bounds of the timestamps whose truncation compares as requested with val
+func dateTruncBounds(e *FnCall, op CmpOperator, val TypedValue, params map[string]interface{}) (*ColSelector, []colBound, bool) {
+	sel, isSel := e.params[1].(*ColSelector)
+	if !isSel || !e.params[0].isConstant() {
+		return nil, nil, false
+	}
+
+	unitVal, ok := reduceConstant(e.params[0], params)
+	if !ok {
+		return nil, nil, false
+	}
+
+	unit, ok := unitVal.RawValue().(string)
+	if !ok {
+		return nil, nil, false
+	}
+
+	ts, ok := val.RawValue().(time.Time)
+	if !ok {
+		return nil, nil, false
+	}
+
+	// floor is the truncation of val, and ceil the first truncated value not before val
+	floor, err := truncateTimestamp(ts, unit)
+	if err != nil {
+		return nil, nil, false
+	}
+
+	next := nextTruncatedTimestamp(floor, unit)
+
+	ceil := next
+	if floor.Equal(ts.UTC()) {
+		ceil = floor
+	}
+
+	var bounds []colBound
+
+	switch op {
+	case EQ:
+		bounds = []colBound{{op: GE, val: &Timestamp{val: ceil}}, {op: LT, val: &Timestamp{val: next}}}
+	case LT:
+		bounds = []colBound{{op: LT, val: &Timestamp{val: ceil}}}
+	case LE:
+		bounds = []colBound{{op: LT, val: &Timestamp{val: next}}}
+	case GT:
+		bounds = []colBound{{op: GE, val: &Timestamp{val: next}}}
+	case GE:
+		bounds = []colBound{{op: GE, val: &Timestamp{val: ceil}}}
+	default:
+		return nil, nil, false
+	}
+	return sel, bounds, true
+}
+
+func reduceConstant(exp ValueExp, params map[string]interface{}) (TypedValue, bool) {
+	e, err := exp.substitute(params)
+	if err != nil {
+		return nil, false
+	}
+
+	val, err := e.reduce(nil, nil, "")
+	if err != nil || val.IsNull() {
+		return nil, false
+	}
+	return val, true
+}
@@ -0,0 +1,231 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateTruncFn(t *testing.T) {
+	ts := time.Date(2025, 3, 17, 13, 45, 30, 123000, time.UTC)
+
+	for unit, expected := range map[string]time.Time{
+		"year":   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		"MONTH":  time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+		"day":    time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC),
+		"hour":   time.Date(2025, 3, 17, 13, 0, 0, 0, time.UTC),
+		"minute": time.Date(2025, 3, 17, 13, 45, 0, 0, time.UTC),
+		"second": time.Date(2025, 3, 17, 13, 45, 30, 0, time.UTC),
+	} {
+		v, err := (&DateTruncFn{}).Apply(nil, []TypedValue{&Varchar{val: unit}, &Timestamp{val: ts}})
+		require.NoError(t, err)
+		require.Equal(t, expected, v.RawValue(), unit)
+	}
+
+	_, err := (&DateTruncFn{}).Apply(nil, []TypedValue{&Varchar{val: "day"}, &Varchar{val: "2025-03-17 10:00:00"}})
+	require.ErrorIs(t, err, ErrInvalidTypes)
+
+	v, err := (&DateTruncFn{}).Apply(nil, []TypedValue{&Varchar{val: "day"}, NewNull(TimestampType)})
+	require.NoError(t, err)
+	require.True(t, v.IsNull())
+	require.Equal(t, TimestampType, v.Type())
+
+	_, err = (&DateTruncFn{}).Apply(nil, []TypedValue{&Varchar{val: "week"}, &Timestamp{val: ts}})
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	_, err = (&DateTruncFn{}).Apply(nil, []TypedValue{&Timestamp{val: ts}})
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	_, err = (&DateTruncFn{}).Apply(nil, []TypedValue{&Integer{val: 1}, &Timestamp{val: ts}})
+	require.ErrorIs(t, err, ErrInvalidTypes)
+}
+
+func TestSargableRewrite(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, `
+		CREATE TABLE events (id INTEGER AUTO_INCREMENT, ts TIMESTAMP, n INTEGER, PRIMARY KEY id);
+		CREATE INDEX ON events(ts);
+		CREATE INDEX ON events(n);
+	`, nil)
+	require.NoError(t, err)
+
+	// an event every 6 hours during 10 days from 2025-01-01 01:00
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	const nEvents = 40
+
+	eventTs := func(i int) time.Time {
+		return start.Add(time.Hour + time.Duration(i)*6*time.Hour)
+	}
+
+	for i := 0; i < nEvents; i++ {
+		_, _, err := engine.Exec(context.Background(), nil, "INSERT INTO events (ts, n) VALUES (@ts, @n)", map[string]interface{}{
+			"ts": eventTs(i),
+			"n":  i,
+		})
+		require.NoError(t, err)
+	}
+
+	type result struct {
+		ids       []int64
+		rowsRead  int
+		scanSpecs *ScanSpecs
+	}
+
+	query := func(t *testing.T, sql string, params map[string]interface{}) result {
+		r, err := engine.Query(context.Background(), nil, sql, params)
+		require.NoError(t, err)
+		defer r.Close()
+
+		// rows read by the index scan, before evaluating the WHERE clause
+		cr := r.(*projectedRowReader).rowReader.(*conditionalRowReader)
+		counter := &countingRowReader{RowReader: cr.rowReader}
+		cr.rowReader = counter
+
+		var ids []int64
+		for {
+			row, err := r.Read(context.Background())
+			if errors.Is(err, ErrNoMoreRows) {
+				break
+			}
+			require.NoError(t, err)
+
+			ids = append(ids, row.ValuesByPosition[0].RawValue().(int64))
+		}
+		return result{ids: ids, rowsRead: counter.reads, scanSpecs: r.ScanSpecs()}
+	}
+
+	// expected evaluates the predicate over every event, without using any index
+	expected := func(pred func(i int, ts time.Time) bool) []int64 {
+		var ids []int64
+		for i := 0; i < nEvents; i++ {
+			if pred(i, eventTs(i)) {
+				ids = append(ids, int64(i+1))
+			}
+		}
+		return ids
+	}
+
+	day := func(d int) time.Time {
+		return start.AddDate(0, 0, d)
+	}
+
+	t.Run("DATE_TRUNC comparisons are scanned as timestamp ranges", func(t *testing.T) {
+		for _, tc := range []struct {
+			sql      string
+			params   map[string]interface{}
+			pred     func(i int, ts time.Time) bool
+			rowsRead int
+		}{
+			{
+				sql:      "SELECT id FROM events WHERE DATE_TRUNC('day', ts) = CAST('2025-01-03' AS TIMESTAMP)",
+				pred:     func(_ int, ts time.Time) bool { return !ts.Before(day(2)) && ts.Before(day(3)) },
+				rowsRead: 4,
+			},
+			{
+				sql:      "SELECT id FROM events WHERE CAST('2025-01-03' AS TIMESTAMP) = DATE_TRUNC('DAY', ts)",
+				pred:     func(_ int, ts time.Time) bool { return !ts.Before(day(2)) && ts.Before(day(3)) },
+				rowsRead: 4,
+			},
+			{
+				sql:      "SELECT id FROM events WHERE DATE_TRUNC(@unit, ts) = @d",
+				params:   map[string]interface{}{"unit": "day", "d": day(5)},
+				pred:     func(_ int, ts time.Time) bool { return !ts.Before(day(5)) && ts.Before(day(6)) },
+				rowsRead: 4,
+			},
+			{
+				// no truncated timestamp equals a value which is not truncated
+				sql:      "SELECT id FROM events WHERE DATE_TRUNC('day', ts) = CAST('2025-01-03 12:00' AS TIMESTAMP)",
+				pred:     func(_ int, ts time.Time) bool { return false },
+				rowsRead: 0,
+			},
+			{
+				sql:      "SELECT id FROM events WHERE DATE_TRUNC('day', ts) < CAST('2025-01-03 12:00' AS TIMESTAMP)",
+				pred:     func(_ int, ts time.Time) bool { return ts.Before(day(3)) },
+				rowsRead: 12,
+			},
+			{
+				sql:      "SELECT id FROM events WHERE DATE_TRUNC('day', ts) <= CAST('2025-01-02' AS TIMESTAMP)",
+				pred:     func(_ int, ts time.Time) bool { return ts.Before(day(2)) },
+				rowsRead: 8,
+			},
+			{
+				sql:      "SELECT id FROM events WHERE DATE_TRUNC('day', ts) > CAST('2025-01-08' AS TIMESTAMP)",
+				pred:     func(_ int, ts time.Time) bool { return !ts.Before(day(8)) },
+				rowsRead: 8,
+			},
+			{
+				sql:      "SELECT id FROM events WHERE DATE_TRUNC('hour', ts) >= CAST('2025-01-10 17:30' AS TIMESTAMP) AND DATE_TRUNC('month', ts) = CAST('2025-01-01' AS TIMESTAMP)",
+				pred:     func(_ int, ts time.Time) bool { return !ts.Before(day(9).Add(18 * time.Hour)) },
+				rowsRead: 1,
+			},
+			{
+				sql: "SELECT id FROM events WHERE DATE_TRUNC('day', ts) = CAST('2025-01-02' AS TIMESTAMP) OR DATE_TRUNC('day', ts) = CAST('2025-01-09' AS TIMESTAMP)",
+				pred: func(_ int, ts time.Time) bool {
+					return (!ts.Before(day(1)) && ts.Before(day(2))) || (!ts.Before(day(8)) && ts.Before(day(9)))
+				},
+				rowsRead: 8,
+			},
+		} {
+			res := query(t, tc.sql, tc.params)
+			require.Equal(t, "events(ts)", res.scanSpecs.Index.Name(), tc.sql)
+			require.Equal(t, expected(tc.pred), res.ids, tc.sql)
+			require.Equal(t, tc.rowsRead, res.rowsRead, tc.sql)
+		}
+	})
+
+	t.Run("integer shifts are inverted on equality", func(t *testing.T) {
+		for _, tc := range []struct {
+			sql  string
+			pred func(i int, ts time.Time) bool
+		}{
+			{sql: "SELECT id FROM events WHERE n + 5 = 12", pred: func(i int, _ time.Time) bool { return i == 7 }},
+			{sql: "SELECT id FROM events WHERE 5 + n = 12", pred: func(i int, _ time.Time) bool { return i == 7 }},
+			{sql: "SELECT id FROM events WHERE n - 5 = 12", pred: func(i int, _ time.Time) bool { return i == 17 }},
+			{sql: "SELECT id FROM events WHERE 30 - n = 12", pred: func(i int, _ time.Time) bool { return i == 18 }},
+			{sql: "SELECT id FROM events WHERE 12 = (n + 1) + 2", pred: func(i int, _ time.Time) bool { return i == 9 }},
+		} {
+			res := query(t, tc.sql, nil)
+			require.Equal(t, "events(n)", res.scanSpecs.Index.Name(), tc.sql)
+			require.Equal(t, expected(tc.pred), res.ids, tc.sql)
+			require.Equal(t, 1, res.rowsRead, tc.sql)
+		}
+
+		// arithmetic wraps around on overflow, so inequalities are not rewritten
+		res := query(t, "SELECT id FROM events WHERE n + 5 > 40", nil)
+		require.Equal(t, expected(func(i int, _ time.Time) bool { return i+5 > 40 }), res.ids)
+		require.Equal(t, nEvents, res.rowsRead)
+	})
+
+	t.Run("predicates which can not be rewritten are evaluated over every row", func(t *testing.T) {
+		for _, sql := range []string{
+			"SELECT id FROM events WHERE DATE_TRUNC('day', ts) != CAST('2025-01-03' AS TIMESTAMP)",
+			"SELECT id FROM events WHERE DATE_TRUNC('day', ts) = DATE_TRUNC('month', ts)",
+			"SELECT id FROM events WHERE n * 2 = 12",
+			"SELECT id FROM events WHERE n + n = 12",
+		} {
+			res := query(t, sql, nil)
+			require.True(t, res.scanSpecs.Index.IsPrimary(), sql)
+			require.Equal(t, nEvents, res.rowsRead, sql)
+		}
+	})
+}
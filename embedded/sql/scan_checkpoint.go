@@ -0,0 +1,169 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"iter"
+)
+
+const scanCheckpointVersion = 1
+
+// ScanReader reads the rows of a table in the order of its primary key, as of the snapshot of the
+// read-only transaction it was opened with. The position of the scan can be saved by Checkpoint,
+// so that an interrupted scan is continued by Engine.ResumeScan from the row following the last one
+// read, observing the same snapshot.
+type ScanReader struct {
+	*rawRowReader
+
+	snapshotTxID uint64
+	afterPK      []byte // encoded primary key of the row the scan was resumed after
+	last         *Row
+}
+
+// Scan returns a reader of all the rows of the table, sorted by primary key in ascending order
+// or in descending order when descOrder is set. The reader has its own read-only transaction,
+// which is cancelled once the reader is closed.
+func (e *Engine) Scan(ctx context.Context, table string, descOrder bool) (*ScanReader, error) {
+	return e.openScan(ctx, 0, descOrder, nil, func(catlg *Catalog) (*Table, error) {
+		return catlg.GetTableByName(table)
+	})
+}
+
+// ResumeScan returns a reader of the rows of the scan the checkpoint was taken from, which were
+// not read before the checkpoint. Rows are read as of the snapshot of the original scan, thus
+// changes committed after it began are not observed.
+func (e *Engine) ResumeScan(ctx context.Context, checkpoint []byte) (*ScanReader, error) {
+	if len(checkpoint) < 18 || checkpoint[0] != scanCheckpointVersion {
+		return nil, fmt.Errorf("%w: invalid scan checkpoint", ErrCorruptedData)
+	}
+
+	snapshotTxID := binary.BigEndian.Uint64(checkpoint[1:])
+	tableID := binary.BigEndian.Uint32(checkpoint[9:])
+	descOrder := checkpoint[13] == 1
+	pkLen := binary.BigEndian.Uint32(checkpoint[14:])
+
+	if checkpoint[13] > 1 || snapshotTxID == 0 || uint64(len(checkpoint)-18) != uint64(pkLen) {
+		return nil, fmt.Errorf("%w: invalid scan checkpoint", ErrCorruptedData)
+	}
+
+	if snapshotTxID > e.store.LastCommittedTxID() {
+		return nil, fmt.Errorf("%w: scan checkpoint of uncommitted transaction %d", ErrIllegalArguments, snapshotTxID)
+	}
+
+	var after []byte
+	if pkLen > 0 {
+		after = append([]byte(nil), checkpoint[18:]...)
+	}
+
+	return e.openScan(ctx, snapshotTxID, descOrder, after, func(catlg *Catalog) (*Table, error) {
+		return catlg.GetTableByID(tableID)
+	})
+}
+
+func (e *Engine) openScan(ctx context.Context, snapshotTxID uint64, descOrder bool, afterPK []byte, tableFn func(*Catalog) (*Table, error)) (*ScanReader, error) {
+	opts := DefaultTxOptions().WithReadOnly(true)
+
+	if snapshotTxID > 0 {
+		opts.WithSnapshotMustIncludeTxID(func(_ uint64) uint64 { return snapshotTxID })
+	}
+
+	tx, err := e.NewTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if snapshotTxID > 0 {
+		tx.snapshotTxID = snapshotTxID
+	}
+
+	table, err := tableFn(tx.Catalog())
+	if err != nil {
+		tx.Cancel()
+		return nil, err
+	}
+
+	scanSpecs := &ScanSpecs{
+		Index:     table.primaryIndex,
+		DescOrder: descOrder,
+	}
+
+	if afterPK != nil {
+		scanSpecs.resumeAfter = table.primaryIndex.entryKey(afterPK, afterPK)
+	}
+
+	r, err := newRawRowReader(tx, nil, table, period{}, "", scanSpecs)
+	if err != nil {
+		tx.Cancel()
+		return nil, err
+	}
+
+	r.onClose(func() {
+		tx.Cancel()
+	})
+
+	return &ScanReader{
+		rawRowReader: r,
+		snapshotTxID: tx.snapshotTxID,
+		afterPK:      afterPK,
+	}, nil
+}
+
+func (r *ScanReader) Read(ctx context.Context) (*Row, error) {
+	row, err := r.rawRowReader.Read(ctx)
+	if err == nil {
+		r.last = row
+	}
+	return row, err
+}
+
+func (r *ScanReader) All(ctx context.Context) iter.Seq2[*Row, error] {
+	return allRows(ctx, r)
+}
+
+// Checkpoint returns the position of the scan, after the last row read. Rows peeked
+// at but not read are read again once the scan is resumed from the checkpoint.
+func (r *ScanReader) Checkpoint() ([]byte, error) {
+	pk := r.afterPK
+
+	if r.last != nil {
+		valuesByColID := make(map[uint32]TypedValue, len(r.table.cols))
+		for i, col := range r.table.cols {
+			valuesByColID[col.id] = r.last.ValuesByPosition[i]
+		}
+
+		encPK, err := encodedKey(r.table.primaryIndex, valuesByColID)
+		if err != nil {
+			return nil, err
+		}
+		pk = encPK
+	}
+
+	checkpoint := make([]byte, 18+len(pk))
+	checkpoint[0] = scanCheckpointVersion
+	binary.BigEndian.PutUint64(checkpoint[1:], r.snapshotTxID)
+	binary.BigEndian.PutUint32(checkpoint[9:], r.table.id)
+	if r.scanSpecs.DescOrder {
+		checkpoint[13] = 1
+	}
+	binary.BigEndian.PutUint32(checkpoint[14:], uint32(len(pk)))
+	copy(checkpoint[18:], pk)
+
+	return checkpoint, nil
+}
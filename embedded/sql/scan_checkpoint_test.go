@@ -0,0 +1,207 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResumeScan(t *testing.T) {
+	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(t, err)
+	defer closeStore(t, st)
+
+	engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		CREATE TABLE events (tenant VARCHAR[16], seq INTEGER, payload VARCHAR, PRIMARY KEY (tenant, seq));
+	`, nil)
+	require.NoError(t, err)
+
+	tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithExplicitClose(true))
+	require.NoError(t, err)
+
+	for i := 0; i < 100; i++ {
+		_, _, err = engine.Exec(context.Background(), tx, "INSERT INTO events (tenant, seq, payload) VALUES (@tenant, @seq, @payload)", map[string]interface{}{
+			"tenant":  fmt.Sprintf("t%d", i%3),
+			"seq":     i,
+			"payload": fmt.Sprintf("p%d", i),
+		})
+		require.NoError(t, err)
+	}
+
+	err = tx.Commit(context.Background())
+	require.NoError(t, err)
+
+	readKeys := func(t *testing.T, r *ScanReader, n int) []string {
+		var keys []string
+		for n < 0 || len(keys) < n {
+			row, err := r.Read(context.Background())
+			if n < 0 && errors.Is(err, ErrNoMoreRows) {
+				break
+			}
+			require.NoError(t, err)
+
+			keys = append(keys, fmt.Sprintf("%s/%d", row.ValuesByPosition[0].RawValue(), row.ValuesByPosition[1].RawValue()))
+		}
+		return keys
+	}
+
+	scanAll := func(t *testing.T, descOrder bool) []string {
+		r, err := engine.Scan(context.Background(), "events", descOrder)
+		require.NoError(t, err)
+		defer r.Close()
+
+		return readKeys(t, r, -1)
+	}
+
+	for _, descOrder := range []bool{false, true} {
+		t.Run(fmt.Sprintf("descOrder=%v", descOrder), func(t *testing.T) {
+			expected := scanAll(t, descOrder)
+			require.Len(t, expected, 100)
+
+			r, err := engine.Scan(context.Background(), "events", descOrder)
+			require.NoError(t, err)
+
+			firstHalf := readKeys(t, r, 50)
+
+			// peeked rows are not part of the rows read before the checkpoint
+			_, err = r.Peek(context.Background())
+			require.NoError(t, err)
+
+			checkpoint, err := r.Checkpoint()
+			require.NoError(t, err)
+			require.NoError(t, r.Close())
+
+			// changes committed after the scan began are not observed once resumed
+			_, _, err = engine.Exec(context.Background(), nil, `
+				DELETE FROM events WHERE seq >= 90;
+				INSERT INTO events (tenant, seq, payload) VALUES ('t0', 1000, 'new'), ('t9', 0, 'new');
+			`, nil)
+			require.NoError(t, err)
+
+			r, err = engine.ResumeScan(context.Background(), checkpoint)
+			require.NoError(t, err)
+
+			secondHalf := readKeys(t, r, 25)
+
+			// checkpoints of resumed scans continue from the last row read
+			checkpoint, err = r.Checkpoint()
+			require.NoError(t, err)
+			require.NoError(t, r.Close())
+
+			r, err = engine.ResumeScan(context.Background(), checkpoint)
+			require.NoError(t, err)
+
+			// a checkpoint taken before reading any row keeps the position of the scan
+			checkpoint, err = r.Checkpoint()
+			require.NoError(t, err)
+			require.NoError(t, r.Close())
+
+			r, err = engine.ResumeScan(context.Background(), checkpoint)
+			require.NoError(t, err)
+			defer r.Close()
+
+			secondHalf = append(secondHalf, readKeys(t, r, -1)...)
+
+			require.Equal(t, expected, append(firstHalf, secondHalf...))
+
+			// restore the rows of the table for the following run
+			_, _, err = engine.Exec(context.Background(), nil, `
+				DELETE FROM events WHERE payload = 'new';
+				INSERT INTO events (tenant, seq, payload) VALUES
+					('t0', 90, 'p90'), ('t1', 91, 'p91'), ('t2', 92, 'p92'), ('t0', 93, 'p93'), ('t1', 94, 'p94'),
+					('t2', 95, 'p95'), ('t0', 96, 'p96'), ('t1', 97, 'p97'), ('t2', 98, 'p98'), ('t0', 99, 'p99');
+			`, nil)
+			require.NoError(t, err)
+		})
+	}
+
+	t.Run("checkpoint before reading any row", func(t *testing.T) {
+		r, err := engine.Scan(context.Background(), "events", false)
+		require.NoError(t, err)
+
+		checkpoint, err := r.Checkpoint()
+		require.NoError(t, err)
+		require.NoError(t, r.Close())
+
+		r, err = engine.ResumeScan(context.Background(), checkpoint)
+		require.NoError(t, err)
+		defer r.Close()
+
+		require.Equal(t, scanAll(t, false), readKeys(t, r, -1))
+	})
+
+	t.Run("checkpoint after reading every row", func(t *testing.T) {
+		r, err := engine.Scan(context.Background(), "events", false)
+		require.NoError(t, err)
+
+		readKeys(t, r, -1)
+
+		checkpoint, err := r.Checkpoint()
+		require.NoError(t, err)
+		require.NoError(t, r.Close())
+
+		r, err = engine.ResumeScan(context.Background(), checkpoint)
+		require.NoError(t, err)
+		defer r.Close()
+
+		_, err = r.Read(context.Background())
+		require.ErrorIs(t, err, ErrNoMoreRows)
+	})
+
+	t.Run("invalid checkpoints", func(t *testing.T) {
+		r, err := engine.Scan(context.Background(), "events", false)
+		require.NoError(t, err)
+
+		readKeys(t, r, 10)
+
+		checkpoint, err := r.Checkpoint()
+		require.NoError(t, err)
+		require.NoError(t, r.Close())
+
+		for i := 0; i < len(checkpoint); i++ {
+			_, err = engine.ResumeScan(context.Background(), checkpoint[:i])
+			require.ErrorIs(t, err, ErrCorruptedData)
+		}
+
+		_, err = engine.ResumeScan(context.Background(), append([]byte{0xff}, checkpoint[1:]...))
+		require.ErrorIs(t, err, ErrCorruptedData)
+
+		uncommitted := append([]byte(nil), checkpoint...)
+		uncommitted[1] = 0xff
+
+		_, err = engine.ResumeScan(context.Background(), uncommitted)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+
+		_, err = engine.Scan(context.Background(), "unknown", false)
+		require.ErrorIs(t, err, ErrTableDoesNotExist)
+
+		_, _, err = engine.Exec(context.Background(), nil, "DROP TABLE events", nil)
+		require.NoError(t, err)
+
+		_, err = engine.ResumeScan(context.Background(), checkpoint)
+		require.ErrorIs(t, err, ErrTableDoesNotExist)
+	})
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// StmtResult is the outcome of one of the statements of a script
+type StmtResult struct {
+	Stmt SQLStmt
+
+	// UpdatedRows is the number of rows inserted, updated or deleted by the statement
+	UpdatedRows int
+
+	// Created holds the tables, indexes and views created by the statement, e.g. TABLE people
+	// or INDEX people(name), which is none when they already existed and IF NOT EXISTS was specified
+	Created []string
+
+	// Err is the error the statement failed with, in which case the rest of the script is not executed
+	Err error
+}
+
+// ExecScript executes the statements of the script one after another, as done by Exec, and returns
+// the outcome of every executed statement. Each statement is committed on its own, unless enclosed
+// in a transaction by BEGIN and COMMIT, and INSERT statements are not coalesced with the ones of
+// other callers. Execution stops at the first failing statement, whose result holds the error also
+// returned by ExecScript. A transaction left open by the end of the script is rolled back, failing
+// with ErrTxNotClosed. Scripts are executed against the database of the engine, thus selecting a
+// different one is not allowed when the engine has a multi-database handler.
+func (e *Engine) ExecScript(ctx context.Context, script string) ([]StmtResult, error) {
+	stmts, err := e.parseSQL(script)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrParsingError, err)
+	}
+
+	if e.multidbHandler != nil {
+		for _, stmt := range stmts {
+			if _, isDBSelectionStmt := stmt.(*UseDatabaseStmt); isDBSelectionStmt {
+				return nil, fmt.Errorf("%w: scripts can not select a different database", ErrIllegalArguments)
+			}
+		}
+	}
+
+	results := make([]StmtResult, 0, len(stmts))
+
+	var tx *SQLTx
+
+	for _, stmt := range stmts {
+		res, ntx, err := e.execScriptStmt(ctx, tx, stmt)
+		results = append(results, res)
+		if err != nil {
+			return results, err
+		}
+		tx = ntx
+	}
+
+	if tx != nil {
+		tx.Cancel()
+		return results, fmt.Errorf("%w: the script did not commit the transaction it began", ErrTxNotClosed)
+	}
+
+	return results, nil
+}
+
+func (e *Engine) execScriptStmt(ctx context.Context, tx *SQLTx, stmt SQLStmt) (StmtResult, *SQLTx, error) {
+	res := StmtResult{Stmt: stmt}
+
+	var before map[string]struct{}
+	var updatedRows int
+
+	if tx != nil {
+		before = catalogObjects(tx.Catalog())
+		updatedRows = tx.UpdatedRows()
+	} else {
+		catalog, err := e.Catalog(ctx, nil)
+		if err != nil {
+			res.Err = err
+			return res, nil, err
+		}
+		before = catalogObjects(catalog)
+	}
+
+	ntx, committedTxs, _, err := e.execPreparedStmts(ctx, tx, []SQLStmt{stmt}, nil)
+	if err != nil {
+		res.Err = err
+		return res, nil, err
+	}
+
+	// the statement was executed within the ongoing transaction, or in one of its own
+	execTx := tx
+	if execTx == nil && len(committedTxs) > 0 {
+		execTx = committedTxs[len(committedTxs)-1]
+	}
+
+	if execTx != nil {
+		res.UpdatedRows = execTx.UpdatedRows() - updatedRows
+
+		switch stmt.(type) {
+		case *CreateTableStmt, *CreateIndexStmt, *CreateViewStmt:
+			for obj := range catalogObjects(execTx.Catalog()) {
+				if _, existed := before[obj]; !existed {
+					res.Created = append(res.Created, obj)
+				}
+			}
+			sort.Strings(res.Created)
+		}
+	}
+
+	return res, ntx, nil
+}
+
+func catalogObjects(catalog *Catalog) map[string]struct{} {
+	objs := make(map[string]struct{})
+
+	for _, table := range catalog.GetTables() {
+		objs["TABLE "+table.Name()] = struct{}{}
+
+		for _, index := range table.GetIndexes() {
+			objs["INDEX "+index.Name()] = struct{}{}
+		}
+	}
+
+	for _, view := range catalog.GetViews() {
+		objs["VIEW "+view.Name()] = struct{}{}
+	}
+	return objs
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExecScript(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	type outcome struct {
+		updatedRows int
+		created     []string
+	}
+
+	outcomes := func(results []StmtResult) []outcome {
+		outcomes := make([]outcome, len(results))
+		for i, res := range results {
+			outcomes[i] = outcome{updatedRows: res.UpdatedRows, created: res.Created}
+		}
+		return outcomes
+	}
+
+	results, err := engine.ExecScript(context.Background(), `
+		CREATE TABLE people (id INTEGER AUTO_INCREMENT, name VARCHAR, age INTEGER, PRIMARY KEY id);
+		CREATE INDEX ON people (age);
+		INSERT INTO people (name, age) VALUES ('alice', 30), ('bob', 25), ('carol', 41);
+		UPDATE people SET age = age + 1 WHERE age < 35;
+		DELETE FROM people WHERE name = 'carol';
+		CREATE VIEW adults AS SELECT name FROM people WHERE age >= 18;
+		CREATE TABLE IF NOT EXISTS people (id INTEGER AUTO_INCREMENT, name VARCHAR, age INTEGER, PRIMARY KEY id);
+		CREATE INDEX IF NOT EXISTS ON people (age);
+		SELECT * FROM people;
+
+		BEGIN TRANSACTION;
+			INSERT INTO people (name, age) VALUES ('dave', 19), ('erin', 33);
+			UPDATE people SET age = 20 WHERE name = 'alice';
+		COMMIT;
+	`)
+	require.NoError(t, err)
+
+	require.Equal(t, []outcome{
+		{created: []string{"INDEX people(id)", "TABLE people"}},
+		{created: []string{"INDEX people(age)"}},
+		{updatedRows: 3},
+		{updatedRows: 2},
+		{updatedRows: 1},
+		{created: []string{"VIEW adults"}},
+		{},
+		{},
+		{},
+		{},
+		{updatedRows: 2},
+		{updatedRows: 1},
+		{},
+	}, outcomes(results))
+
+	for _, res := range results {
+		require.NoError(t, res.Err)
+	}
+	require.IsType(t, &CreateTableStmt{}, results[0].Stmt)
+	require.IsType(t, &CommitStmt{}, results[len(results)-1].Stmt)
+
+	rows, err := engine.queryAll(context.Background(), nil, "SELECT name FROM adults", nil)
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+
+	t.Run("execution stops at the first failing statement", func(t *testing.T) {
+		results, err := engine.ExecScript(context.Background(), `
+			INSERT INTO people (name, age) VALUES ('frank', 50);
+			INSERT INTO people (id, name, age) VALUES (1, 'grace', 28);
+			DELETE FROM people;
+		`)
+		require.ErrorIs(t, err, store.ErrKeyAlreadyExists)
+		require.Len(t, results, 2)
+
+		require.NoError(t, results[0].Err)
+		require.Equal(t, 1, results[0].UpdatedRows)
+		require.ErrorIs(t, results[1].Err, store.ErrKeyAlreadyExists)
+		require.Zero(t, results[1].UpdatedRows)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT name FROM people", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 5)
+	})
+
+	t.Run("transactions left open are rolled back", func(t *testing.T) {
+		results, err := engine.ExecScript(context.Background(), `
+			BEGIN TRANSACTION;
+			DELETE FROM people;
+		`)
+		require.ErrorIs(t, err, ErrTxNotClosed)
+		require.Len(t, results, 2)
+		require.Equal(t, 5, results[1].UpdatedRows)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT name FROM people", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 5)
+	})
+
+	t.Run("invalid scripts", func(t *testing.T) {
+		results, err := engine.ExecScript(context.Background(), "CREATE TABLE broken (")
+		require.ErrorIs(t, err, ErrParsingError)
+		require.Empty(t, results)
+	})
+}
@@ -0,0 +1,365 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"sync"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/codenotary/immudb/embedded/tbtree"
+)
+
+// each shard reads ahead up to scanShardBuffers batches of scanShardBatchSize entries
+const (
+	scanShardBatchSize = 128
+	scanShardBuffers   = 8
+)
+
+// shardedKeyReader splits the key range of a scan into shards, i.e. contiguous sub-ranges, each
+// one read by its own reader in parallel. Entries are returned in the order of the scan, shard
+// after shard, while the following shards are read ahead, along with the values of their entries,
+// into bounded buffers. Shards are set from the first and last keys within the range, assuming
+// keys to be evenly distributed between them, once the first entry is read.
+// Shards which can not be read ahead, as all the workers of the engine are busy, are read by
+// the goroutine reading the entries once reached. Readers are only created by the goroutine
+// reading the entries, thus it is only used by read-only transactions, whose readers don't keep
+// track of the entries they read.
+type shardedKeyReader struct {
+	tx     *SQLTx
+	spec   store.KeyReaderSpec
+	shards int
+
+	readers []store.KeyReader
+	batches []chan []shardEntry // nil for the shards which are not read ahead
+	curr    int
+	batch   []shardEntry
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+	closed bool
+}
+
+type shardEntry struct {
+	key []byte
+	val store.ValueRef
+	err error
+}
+
+type keyReadFn func(ctx context.Context, reader store.KeyReader) ([]byte, store.ValueRef, error)
+
+// resolvedValueRef is the reference to a value which was already read
+type resolvedValueRef struct {
+	store.ValueRef
+	val []byte
+}
+
+func (v *resolvedValueRef) Resolve() ([]byte, error) {
+	return v.val, nil
+}
+
+func newShardedKeyReader(tx *SQLTx, spec store.KeyReaderSpec, shards int) *shardedKeyReader {
+	return &shardedKeyReader{
+		tx:     tx,
+		spec:   spec,
+		shards: shards,
+	}
+}
+
+func (r *shardedKeyReader) Read(ctx context.Context) (key []byte, val store.ValueRef, err error) {
+	return r.read(ctx, func(ctx context.Context, reader store.KeyReader) ([]byte, store.ValueRef, error) {
+		return reader.Read(ctx)
+	})
+}
+
+func (r *shardedKeyReader) ReadBetween(ctx context.Context, initialTxID uint64, finalTxID uint64) (key []byte, val store.ValueRef, err error) {
+	return r.read(ctx, func(ctx context.Context, reader store.KeyReader) ([]byte, store.ValueRef, error) {
+		return reader.ReadBetween(ctx, initialTxID, finalTxID)
+	})
+}
+
+func (r *shardedKeyReader) read(ctx context.Context, readFn keyReadFn) ([]byte, store.ValueRef, error) {
+	if r.closed {
+		return nil, nil, tbtree.ErrAlreadyClosed
+	}
+
+	if r.batches == nil {
+		err := r.start(ctx, readFn)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for len(r.batch) == 0 {
+		if r.curr == len(r.batches) {
+			return nil, nil, store.ErrNoMoreEntries
+		}
+
+		if r.batches[r.curr] == nil {
+			key, val, err := readFn(ctx, r.readers[r.curr])
+			if errors.Is(err, store.ErrNoMoreEntries) {
+				r.curr++
+				continue
+			}
+			return key, val, err
+		}
+
+		select {
+		case batch, ok := <-r.batches[r.curr]:
+			if !ok {
+				r.curr++
+			}
+			r.batch = batch
+		case <-ctx.Done():
+			return nil, nil, ctx.Err()
+		}
+	}
+
+	e := r.batch[0]
+	r.batch = r.batch[1:]
+
+	return e.key, e.val, e.err
+}
+
+func (r *shardedKeyReader) start(ctx context.Context, readFn keyReadFn) error {
+	specs, err := r.shardSpecs(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, spec := range specs {
+		reader, err := r.tx.newKeyReader(*spec)
+		if err != nil {
+			r.stop()
+			return err
+		}
+		r.readers = append(r.readers, reader)
+	}
+
+	ctx, r.cancel = context.WithCancel(ctx)
+
+	r.batches = make([]chan []shardEntry, len(r.readers))
+
+	for i, reader := range r.readers {
+		batches := make(chan []shardEntry, scanShardBuffers)
+
+		r.wg.Add(1)
+
+		if !r.tx.engine.spawn(func() { r.readShard(ctx, reader, batches, readFn) }) {
+			r.wg.Done()
+			continue
+		}
+
+		r.batches[i] = batches
+	}
+	return nil
+}
+
+// readShard sends the entries of the shard in batches until it is exhausted or an error occurs,
+// which is sent as the last entry. Values failing to be read are left to be resolved by the reader
+// of the entries.
+func (r *shardedKeyReader) readShard(ctx context.Context, reader store.KeyReader, batches chan<- []shardEntry, readFn keyReadFn) {
+	defer r.wg.Done()
+	defer close(batches)
+
+	batch := make([]shardEntry, 0, scanShardBatchSize)
+
+	for {
+		key, val, err := readFn(ctx, reader)
+		if err == nil {
+			if v, err := val.Resolve(); err == nil {
+				val = &resolvedValueRef{ValueRef: val, val: v}
+			}
+		}
+
+		done := err != nil
+		if !errors.Is(err, store.ErrNoMoreEntries) {
+			batch = append(batch, shardEntry{key: key, val: val, err: err})
+		}
+
+		if len(batch) > 0 && (done || len(batch) == scanShardBatchSize) {
+			select {
+			case batches <- batch:
+			case <-ctx.Done():
+				return
+			}
+			batch = make([]shardEntry, 0, scanShardBatchSize)
+		}
+
+		if done {
+			return
+		}
+	}
+}
+
+// shardSpecs splits the range of the scan into shards, in the order of the scan.
+// Fewer shards are returned when the range doesn't hold enough distinct keys.
+func (r *shardedKeyReader) shardSpecs(ctx context.Context) ([]*store.KeyReaderSpec, error) {
+	first, err := r.firstKey(ctx, r.spec)
+	if err != nil {
+		return nil, err
+	}
+
+	last, err := r.firstKey(ctx, reversedKeyReaderSpec(r.spec))
+	if err != nil {
+		return nil, err
+	}
+
+	if first == nil || last == nil {
+		return []*store.KeyReaderSpec{&r.spec}, nil
+	}
+
+	if r.spec.DescOrder {
+		first, last = last, first
+	}
+
+	splits := splitKeyRange(first, last, r.shards)
+
+	specs := make([]*store.KeyReaderSpec, len(splits)+1)
+
+	for i := range specs {
+		spec := r.spec
+
+		if r.spec.DescOrder {
+			// shards are scanned from the greatest keys
+			j := len(splits) - i
+			if j < len(splits) {
+				spec.SeekKey = splits[j]
+				spec.InclusiveSeek = false
+			}
+			if j > 0 {
+				spec.EndKey = splits[j-1]
+				spec.InclusiveEnd = true
+			}
+		} else {
+			if i > 0 {
+				spec.SeekKey = splits[i-1]
+				spec.InclusiveSeek = true
+			}
+			if i < len(splits) {
+				spec.EndKey = splits[i]
+				spec.InclusiveEnd = false
+			}
+		}
+
+		specs[i] = &spec
+	}
+	return specs, nil
+}
+
+// firstKey returns the key of the first entry read with the given spec, or nil when there is none
+func (r *shardedKeyReader) firstKey(ctx context.Context, spec store.KeyReaderSpec) ([]byte, error) {
+	reader, err := r.tx.newKeyReader(spec)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	key, _, err := reader.Read(ctx)
+	if errors.Is(err, store.ErrNoMoreEntries) {
+		return nil, nil
+	}
+	return key, err
+}
+
+func reversedKeyReaderSpec(spec store.KeyReaderSpec) store.KeyReaderSpec {
+	rev := spec
+	rev.SeekKey, rev.EndKey = spec.EndKey, spec.SeekKey
+	rev.InclusiveSeek, rev.InclusiveEnd = spec.InclusiveEnd, spec.InclusiveSeek
+	rev.DescOrder = !spec.DescOrder
+	return rev
+}
+
+// splitKeyRange returns up to n-1 increasing keys splitting the range from lo to hi into n
+// sub-ranges, being the keys greater than lo and not greater than hi. Keys are split on
+// the 8 bytes following their common prefix, taken as big endian integers.
+func splitKeyRange(lo, hi []byte, n int) [][]byte {
+	p := 0
+	for p < len(lo) && p < len(hi) && lo[p] == hi[p] {
+		p++
+	}
+
+	window := func(k []byte) uint64 {
+		var w [8]byte
+		copy(w[:], k[min(p, len(k)):])
+		return binary.BigEndian.Uint64(w[:])
+	}
+
+	a, b := window(lo), window(hi)
+	if a >= b {
+		return nil
+	}
+
+	var splits [][]byte
+
+	width := b - a
+
+	for i := 1; i < n; i++ {
+		s := a + width/uint64(n)*uint64(i) + width%uint64(n)*uint64(i)/uint64(n)
+
+		key := make([]byte, p+8)
+		copy(key, lo[:p])
+		binary.BigEndian.PutUint64(key[p:], s)
+
+		if bytes.Compare(key, lo) <= 0 || (len(splits) > 0 && bytes.Equal(key, splits[len(splits)-1])) {
+			continue
+		}
+		splits = append(splits, key)
+	}
+	return splits
+}
+
+func (r *shardedKeyReader) Reset() error {
+	return r.stop()
+}
+
+// Close stops reading the shards, once closed the reader fails as the readers of the store do
+func (r *shardedKeyReader) Close() error {
+	if r.closed {
+		return tbtree.ErrAlreadyClosed
+	}
+	r.closed = true
+
+	return r.stop()
+}
+
+// stop waits for the shards being read and closes their readers, they are read again by the next read
+func (r *shardedKeyReader) stop() error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.wg.Wait()
+
+	var err error
+
+	for _, reader := range r.readers {
+		if cerr := reader.Close(); err == nil {
+			err = cerr
+		}
+	}
+
+	r.readers = nil
+	r.batches = nil
+	r.batch = nil
+	r.cancel = nil
+	r.curr = 0
+
+	return err
+}
@@ -0,0 +1,237 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitKeyRange(t *testing.T) {
+	requireSplits := func(t *testing.T, lo, hi []byte, n int, expected int) {
+		splits := splitKeyRange(lo, hi, n)
+		require.Len(t, splits, expected)
+
+		prev := lo
+		for _, key := range splits {
+			require.Positive(t, bytes.Compare(key, prev))
+			require.LessOrEqual(t, bytes.Compare(key, hi), 0)
+			prev = key
+		}
+	}
+
+	requireSplits(t, []byte("M.a0000"), []byte("M.z9999"), 4, 3)
+	requireSplits(t, []byte{1, 2}, []byte{1, 3}, 8, 7)
+	requireSplits(t, []byte{1}, []byte{1, 0, 0, 0, 0, 0, 0, 0, 0, 1}, 4, 0)
+	requireSplits(t, []byte{1, 2, 3}, []byte{1, 2, 3, 4}, 4, 3)
+	requireSplits(t, []byte("M.abc"), []byte("M.abc"), 4, 0)
+	requireSplits(t, []byte("M.abc"), []byte("M.abd"), 1, 0)
+}
+
+func TestShardedScan(t *testing.T) {
+	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(t, err)
+	defer closeStore(t, st)
+
+	engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+	require.NoError(t, err)
+
+	shardedEngine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix).WithScanShards(4))
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		CREATE TABLE nums (id INTEGER, v VARCHAR[16], PRIMARY KEY id);
+		CREATE INDEX ON nums (v);
+		CREATE TABLE names (name VARCHAR[16], PRIMARY KEY name);
+	`, nil)
+	require.NoError(t, err)
+
+	for i := -500; i < 500; i += 100 {
+		var nums, names []string
+		for j := i; j < i+100; j++ {
+			nums = append(nums, fmt.Sprintf("(%d, 'v%d')", j, (j*37)%101))
+			names = append(names, fmt.Sprintf("('%c%d')", 'a'+(j+500)%26, j))
+		}
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO nums (id, v) VALUES "+strings.Join(nums, ", "), nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO names (name) VALUES "+strings.Join(names, ", "), nil)
+		require.NoError(t, err)
+	}
+
+	_, _, err = engine.Exec(context.Background(), nil, "DELETE FROM nums WHERE id % 7 = 0", nil)
+	require.NoError(t, err)
+
+	t.Run("shards are merged in the order of the scan", func(t *testing.T) {
+		for _, sql := range []string{
+			"SELECT id, v FROM nums",
+			"SELECT id FROM nums ORDER BY id DESC",
+			"SELECT id FROM nums WHERE id >= -100 AND id < 250",
+			"SELECT id FROM nums WHERE id > -100 AND id <= 250 ORDER BY id DESC",
+			"SELECT v, id FROM nums USE INDEX ON (v) ORDER BY v",
+			"SELECT v, id FROM nums USE INDEX ON (v) ORDER BY v DESC",
+			"SELECT name FROM names",
+			"SELECT name FROM names ORDER BY name DESC",
+			"SELECT COUNT(*), SUM(id) FROM nums",
+			"SELECT id FROM nums LIMIT 10 OFFSET 400",
+		} {
+			expected, err := engine.queryAll(context.Background(), nil, sql, nil)
+			require.NoError(t, err)
+
+			rows, err := shardedEngine.queryAll(context.Background(), nil, sql, nil)
+			require.NoError(t, err)
+			require.Equal(t, expected, rows, sql)
+		}
+
+		rows, err := shardedEngine.queryAll(context.Background(), nil, "SELECT id FROM nums", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 857)
+
+		for i := 1; i < len(rows); i++ {
+			require.Less(t, rows[i-1].ValuesByPosition[0].RawValue(), rows[i].ValuesByPosition[0].RawValue())
+		}
+	})
+
+	t.Run("scans are split into shards", func(t *testing.T) {
+		tx, err := shardedEngine.NewTx(context.Background(), DefaultTxOptions().WithReadOnly(true))
+		require.NoError(t, err)
+		defer tx.Cancel()
+
+		table, err := tx.catalog.GetTableByName("nums")
+		require.NoError(t, err)
+
+		for _, desc := range []bool{false, true} {
+			spec, err := keyReaderSpecFrom(shardedEngine.prefix, table, &ScanSpecs{Index: table.primaryIndex, DescOrder: desc})
+			require.NoError(t, err)
+
+			reader := newShardedKeyReader(tx, *spec, 4)
+
+			specs, err := reader.shardSpecs(context.Background())
+			require.NoError(t, err)
+			require.Len(t, specs, 4)
+
+			// every shard holds some of the rows
+			for _, spec := range specs {
+				key, err := reader.firstKey(context.Background(), *spec)
+				require.NoError(t, err)
+				require.NotNil(t, key)
+			}
+
+			n := 0
+			for {
+				_, _, err := reader.Read(context.Background())
+				if err == store.ErrNoMoreEntries {
+					break
+				}
+				require.NoError(t, err)
+				n++
+			}
+			require.Equal(t, 857, n)
+			require.NoError(t, reader.Close())
+		}
+	})
+
+	t.Run("readers closed before reading all the shards", func(t *testing.T) {
+		r, err := shardedEngine.Query(context.Background(), nil, "SELECT id FROM nums", nil)
+		require.NoError(t, err)
+
+		row, err := r.Read(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, int64(-500), row.ValuesByPosition[0].RawValue())
+
+		require.NoError(t, r.Close())
+	})
+
+	t.Run("scans within read-write transactions are not split", func(t *testing.T) {
+		for _, readOnly := range []bool{true, false} {
+			tx, err := shardedEngine.NewTx(context.Background(), DefaultTxOptions().WithReadOnly(readOnly))
+			require.NoError(t, err)
+
+			table, err := tx.catalog.GetTableByName("nums")
+			require.NoError(t, err)
+
+			r, err := newRawRowReader(tx, nil, table, period{}, "", &ScanSpecs{Index: table.primaryIndex})
+			require.NoError(t, err)
+
+			_, isSharded := r.reader.(*shardedKeyReader)
+			require.Equal(t, readOnly, isSharded)
+
+			require.NoError(t, r.Close())
+			require.NoError(t, tx.Cancel())
+		}
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		require.ErrorIs(t, DefaultOptions().WithScanShards(-1).Validate(), store.ErrInvalidOptions)
+	})
+}
+
+// BenchmarkShardedScan reads all the rows of a table, the speedup of reading more shards
+// is bounded by the number of CPUs available
+func BenchmarkShardedScan(b *testing.B) {
+	const rows = 1_000_000
+	const batchSize = 500
+
+	st, err := store.Open(b.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(b, err)
+	b.Cleanup(func() { st.Close() })
+
+	engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+	require.NoError(b, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE items (id INTEGER, name VARCHAR, qty INTEGER, PRIMARY KEY id)", nil)
+	require.NoError(b, err)
+
+	for i := 0; i < rows; i += batchSize {
+		values := make([]string, batchSize)
+		for j := range values {
+			values[j] = fmt.Sprintf("(%d, 'item-%d', %d)", i+j, i+j, (i+j)%100)
+		}
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO items (id, name, qty) VALUES "+strings.Join(values, ", "), nil)
+		require.NoError(b, err)
+	}
+
+	err = st.WaitForIndexingUpto(context.Background(), st.LastCommittedTxID())
+	require.NoError(b, err)
+
+	for _, shards := range []int{1, 2, 4, 8, 16} {
+		e, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix).WithScanShards(shards))
+		require.NoError(b, err)
+
+		b.Run(fmt.Sprintf("shards=%d", shards), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				r, err := e.Query(context.Background(), nil, "SELECT id, name, qty FROM items", nil)
+				require.NoError(b, err)
+
+				n := 0
+				for _, err := range r.All(context.Background()) {
+					require.NoError(b, err)
+					n++
+				}
+				require.Equal(b, rows, n)
+			}
+		})
+	}
+}
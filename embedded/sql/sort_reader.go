@@ -19,6 +19,7 @@ package sql
 import (
 	"context"
 	"fmt"
+	"iter"
 )
 
 type sortDirection int8
@@ -28,13 +29,35 @@ const (
 	sortDirectionAsc  sortDirection = 1
 )
 
+// NullsOrder determines where NULL values are placed when sorting rows
+type NullsOrder int8
+
+const (
+	nullsOrderUnspecified NullsOrder = iota
+	// NullsAsSmallest sorts NULL values before any other value in ascending order
+	// and after them in descending order, matching the order of index entries
+	NullsAsSmallest
+	// NullsAsLargest sorts NULL values after any other value in ascending order
+	// and before them in descending order
+	NullsAsLargest
+	// NullsFirst places NULL values before any other value regardless of the direction
+	NullsFirst
+	// NullsLast places NULL values after any other value regardless of the direction
+	NullsLast
+)
+
 type sortRowReader struct {
 	rowReader          RowReader
 	ordExps            []*OrdExp
 	orderByDescriptors []ColDescriptor
 	sorter             fileSorter
+	compareKeys        func(t1, t2 Tuple) (int, error)
+	topN               *topNSorter // set when only the first rows are read
 
 	resultReader resultReader
+	closed       bool
+
+	lookahead rowLookahead
 }
 
 func newSortRowReader(rowReader RowReader, ordExps []*OrdExp) (*sortRowReader, error) {
@@ -80,6 +103,7 @@ func newSortRowReader(rowReader RowReader, ordExps []*OrdExp) (*sortRowReader, e
 			tx:               tx,
 			sortBufSize:      tx.engine.sortBufferSize,
 			sortBuf:          make([]*Row, tx.engine.sortBufferSize),
+			resources:        tx.resources,
 		},
 	}
 
@@ -91,6 +115,25 @@ func newSortRowReader(rowReader RowReader, ordExps []*OrdExp) (*sortRowReader, e
 		}
 	}
 
+	sr.compareKeys = func(t1, t2 Tuple) (int, error) {
+		res, idx, err := t1.Compare(t2)
+		if err != nil {
+			return 0, err
+		}
+
+		if idx < 0 {
+			return res, nil
+		}
+
+		if t1[idx].IsNull() != t2[idx].IsNull() {
+			if t1[idx].IsNull() == ordExps[idx].nullsFirst() {
+				return -1, nil
+			}
+			return 1, nil
+		}
+		return res * int(directions[idx]), nil
+	}
+
 	t1 := make(Tuple, len(ordExps))
 	t2 := make(Tuple, len(ordExps))
 
@@ -102,22 +145,17 @@ func newSortRowReader(rowReader RowReader, ordExps []*OrdExp) (*sortRowReader, e
 		if err := sr.evalSortExps(r2, t2); err != nil {
 			return 0, err
 		}
-
-		res, idx, err := t1.Compare(t2)
-		if err != nil {
-			return 0, err
-		}
-
-		if idx >= 0 {
-			return res * int(directions[idx]), nil
-		}
-		return res, nil
+		return sr.compareKeys(t1, t2)
 	}
 	return sr, nil
 }
 
 func (s *sortRowReader) evalSortExps(inRow *Row, out Tuple) error {
-	for i, col := range s.ordExps {
+	return evalOrdExps(s.Tx(), inRow, s.TableAlias(), s.ordExps, out)
+}
+
+func evalOrdExps(tx *SQLTx, inRow *Row, implicitTable string, ordExps []*OrdExp, out Tuple) error {
+	for i, col := range ordExps {
 		colPos, isColRef := col.exp.(*Integer)
 		if isColRef {
 			if colPos.val < 1 || colPos.val > int64(len(inRow.ValuesByPosition)) {
@@ -125,7 +163,7 @@ func (s *sortRowReader) evalSortExps(inRow *Row, out Tuple) error {
 			}
 			out[i] = inRow.ValuesByPosition[colPos.val-1]
 		} else {
-			val, err := col.exp.reduce(s.Tx(), inRow, s.TableAlias())
+			val, err := col.exp.reduce(tx, inRow, implicitTable)
 			if err != nil {
 				return err
 			}
@@ -224,7 +262,24 @@ func (sr *sortRowReader) InferParameters(ctx context.Context, params map[string]
 	return sr.rowReader.InferParameters(ctx, params)
 }
 
+func (sr *sortRowReader) All(ctx context.Context) iter.Seq2[*Row, error] {
+	return allRows(ctx, sr)
+}
+
 func (sr *sortRowReader) Read(ctx context.Context) (*Row, error) {
+	return sr.lookahead.read(ctx, sr.readRow)
+}
+
+func (sr *sortRowReader) Peek(ctx context.Context) (*Row, error) {
+	return sr.lookahead.peek(ctx, sr.readRow)
+}
+
+func (sr *sortRowReader) readRow(ctx context.Context) (*Row, error) {
+	// sorted rows are kept after the underlying reader is closed
+	if sr.closed {
+		return nil, ErrReaderClosed
+	}
+
 	if sr.resultReader == nil {
 		reader, err := sr.readAndSort(ctx)
 		if err != nil {
@@ -236,6 +291,10 @@ func (sr *sortRowReader) Read(ctx context.Context) (*Row, error) {
 }
 
 func (sr *sortRowReader) readAndSort(ctx context.Context) (resultReader, error) {
+	if sr.topN != nil {
+		return sr.readTopN(ctx)
+	}
+
 	err := sr.readAll(ctx)
 	if err != nil {
 		return nil, err
@@ -262,5 +321,8 @@ func (sr *sortRowReader) readAll(ctx context.Context) error {
 }
 
 func (sr *sortRowReader) Close() error {
+	sr.lookahead.discard()
+
+	sr.closed = true
 	return sr.rowReader.Close()
 }
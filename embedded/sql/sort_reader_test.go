@@ -70,3 +70,72 @@ func TestSortRowReader(t *testing.T) {
 	require.NotNil(t, scanSpecs.Index)
 	require.True(t, scanSpecs.Index.IsPrimary())
 }
+
+func TestNullsOrder(t *testing.T) {
+	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(t, err)
+	defer closeStore(t, st)
+
+	newEngine := func(t *testing.T, nullsOrder NullsOrder) *Engine {
+		engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix).WithNullsOrder(nullsOrder))
+		require.NoError(t, err)
+		return engine
+	}
+
+	engine := newEngine(t, NullsAsSmallest)
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		CREATE TABLE people (id INTEGER, age INTEGER, score FLOAT, PRIMARY KEY id);
+		CREATE INDEX ON people (age);
+	`, nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		INSERT INTO people (id, age, score) VALUES
+			(1, 30, 1.5),
+			(2, NULL, NULL),
+			(3, 25, 3.0),
+			(4, 40, NULL)
+	`, nil)
+	require.NoError(t, err)
+
+	ids := func(t *testing.T, e *Engine, sql string) []int64 {
+		rows, err := e.queryAll(context.Background(), nil, sql, nil)
+		require.NoError(t, err)
+
+		var ids []int64
+		for _, row := range rows {
+			ids = append(ids, row.ValuesByPosition[0].RawValue().(int64))
+		}
+		return ids
+	}
+
+	for _, d := range []struct {
+		nullsOrder NullsOrder
+		asc        []int64
+		desc       []int64
+	}{
+		{NullsAsSmallest, []int64{2, 3, 1, 4}, []int64{4, 1, 3, 2}},
+		{NullsAsLargest, []int64{3, 1, 4, 2}, []int64{2, 4, 1, 3}},
+		{NullsFirst, []int64{2, 3, 1, 4}, []int64{2, 4, 1, 3}},
+		{NullsLast, []int64{3, 1, 4, 2}, []int64{4, 1, 3, 2}},
+	} {
+		e := newEngine(t, d.nullsOrder)
+
+		// the sorting index is used only when it places NULL values as required
+		require.Equal(t, d.asc, ids(t, e, "SELECT id FROM people ORDER BY age"))
+		require.Equal(t, d.desc, ids(t, e, "SELECT id FROM people ORDER BY age DESC"))
+
+		require.Equal(t, d.asc, ids(t, e, "SELECT id FROM people USE INDEX ON (id) ORDER BY age"))
+		require.Equal(t, d.desc, ids(t, e, "SELECT id FROM people USE INDEX ON (id) ORDER BY age DESC"))
+
+		// explicit placements override the default one
+		require.Equal(t, []int64{2, 3, 1, 4}, ids(t, e, "SELECT id FROM people ORDER BY age NULLS FIRST"))
+		require.Equal(t, []int64{2, 4, 1, 3}, ids(t, e, "SELECT id FROM people ORDER BY age DESC NULLS FIRST"))
+		require.Equal(t, []int64{3, 1, 4, 2}, ids(t, e, "SELECT id FROM people USE INDEX ON (age) ORDER BY age NULLS LAST"))
+		require.Equal(t, []int64{4, 1, 3, 2}, ids(t, e, "SELECT id FROM people ORDER BY age DESC NULLS LAST"))
+
+		require.Equal(t, []int64{2, 4, 1, 3}, ids(t, e, "SELECT id FROM people ORDER BY score NULLS FIRST, id"))
+		require.Equal(t, []int64{3, 1, 2, 4}, ids(t, e, "SELECT id FROM people ORDER BY score DESC NULLS LAST, id"))
+	}
+}
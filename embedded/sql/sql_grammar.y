@@ -63,6 +63,7 @@ func setResult(l yyLexer, stmts []SQLStmt) {
     err error
     ordexps []*OrdExp
     opt_ord bool
+    nullsOrder NullsOrder
     logicOp LogicOperator
     cmpOp CmpOperator
     pparam int
@@ -76,16 +77,31 @@ func setResult(l yyLexer, stmts []SQLStmt) {
     tableElem TableElem
     tableElems []TableElem
     timestampField TimestampFieldType
+    fetch *fetchClause
+    triggerEvent TriggerEvent
+    hints []*indexHint
 }
 
 %token <keyword> CREATE DROP USE DATABASE USER WITH PASSWORD READ READWRITE ADMIN SNAPSHOT HISTORY SINCE AFTER BEFORE UNTIL TX OF
-%token <keyword> INTEGER_TYPE BOOLEAN_TYPE VARCHAR_TYPE UUID_TYPE BLOB_TYPE TIMESTAMP_TYPE FLOAT_TYPE JSON_TYPE
-%token <keyword> TABLE UNIQUE INDEX ON ALTER ADD RENAME TO COLUMN CONSTRAINT PRIMARY KEY CHECK GRANT REVOKE GRANTS FOR PRIVILEGES
+%token <keyword> INTEGER_TYPE BOOLEAN_TYPE VARCHAR_TYPE UUID_TYPE BLOB_TYPE TIMESTAMP_TYPE FLOAT_TYPE JSON_TYPE POINT_TYPE
+%token <keyword> TABLE VIEW UNIQUE INDEX ON ALTER ADD RENAME TO COLUMN CONSTRAINT PRIMARY KEY CHECK GRANT REVOKE GRANTS FOR PRIVILEGES
 %token <keyword> BEGIN TRANSACTION COMMIT ROLLBACK
 %token <keyword> INSERT UPSERT INTO VALUES DELETE UPDATE SET CONFLICT DO NOTHING RETURNING
 %token <keyword> SELECT DISTINCT FROM JOIN HAVING WHERE GROUP BY LIMIT OFFSET ORDER ASC DESC AS UNION ALL CASE WHEN THEN ELSE END
+%token <keyword> FETCH FIRST NEXT ROW ROWS ONLY TIES
+%token <keyword> NULLS LAST
+%token <keyword> COMMENT
+%token <keyword> SHARE
+%token <keyword> TRIGGER EACH
+%token <keyword> NATURAL USING LATERAL
+%token <keyword> FULLTEXT MATCH
+%token <keyword> REINDEX
+%token <keyword> BOX
+%token <keyword> PERCENTILE_CONT_FN PERCENTILE_DISC_FN APPROX_PERCENTILE_FN WITHIN
+%token <keyword> RESERVOIR_SAMPLE_FN
 %token <keyword> NOT LIKE IF EXISTS IN IS
 %token <keyword> AUTO_INCREMENT NULL CAST SCAST
+%token <keyword> GENERATED ALWAYS STORED
 %token <keyword> SHOW DATABASES TABLES USERS
 %token <keyword> BETWEEN
 %token <keyword> EXTRACT YEAR MONTH DAY HOUR MINUTE SECOND
@@ -100,6 +116,7 @@ func setResult(l yyLexer, stmts []SQLStmt) {
 %token <integer> INTEGER_LIT
 %token <float> FLOAT_LIT
 %token <str> VARCHAR_LIT
+%token <str> OPTIMIZER_HINTS
 %token <boolean> BOOLEAN_LIT
 %token <blob> BLOB_LIT
 %token <aggFn> AGGREGATE_FUNC
@@ -117,7 +134,7 @@ func setResult(l yyLexer, stmts []SQLStmt) {
 
 %right NOT
 
-%nonassoc CMPOP LIKE NOT_MATCHES_OP IS
+%nonassoc CMPOP LIKE NOT_MATCHES_OP IS MATCH
 
 %left '+' '-'
 %left '*' '/' '%'
@@ -126,7 +143,7 @@ func setResult(l yyLexer, stmts []SQLStmt) {
 %right STMT_SEPARATOR
 
 %type <stmts> sql sqlstmts
-%type <stmt> sqlstmt ddlstmt dmlstmt dqlstmt select_stmt
+%type <stmt> sqlstmt ddlstmt dmlstmt dqlstmt values_stmt select_stmt
 %type <colSpec> colSpec
 %type <colNames> col_names insert_cols one_or_more_col_names
 %type <cols> cols
@@ -135,10 +152,12 @@ func setResult(l yyLexer, stmts []SQLStmt) {
 %type <values> values opt_values
 %type <value> val fnCall
 %type <sel> selector
+%type <keyword> percentile_fn
+%type <float> fraction
 %type <jsonFields> jsonFields
 %type <col> col
 %type <distinct> opt_distinct opt_all
-%type <ds> ds values_or_query
+%type <ds> ds values_or_query lateral_ds
 %type <tableRef> tableRef
 %type <period> opt_period
 %type <openPeriod> opt_period_start
@@ -152,15 +171,19 @@ func setResult(l yyLexer, stmts []SQLStmt) {
 %type <tableElems> tableElems
 %type <exp> exp opt_exp opt_where opt_having boundexp opt_else orExp andExp cmpExp primaryBool addExp notExp
 mulExp unaryExp primary
-%type <cols> opt_groupby
-%type <exp> opt_limit opt_offset case_when_exp
+%type <values> opt_groupby groupby_items
+%type <value> groupby_item
+%type <exp> opt_limit opt_offset case_when_exp opt_generated
+%type <fetch> opt_fetch
+%type <hints> opt_hints
 %type <targets> opt_targets targets
 %type <integer> opt_max_len
 %type <id> opt_as
 %type <ordexps> ordexps opt_orderby
 %type <opt_ord> opt_ord
+%type <nullsOrder> opt_nulls
 %type <colNames> opt_indexon
-%type <boolean> opt_if_not_exists opt_auto_increment opt_not_null opt_not opt_primary_key
+%type <boolean> opt_if_not_exists opt_nulls_not_distinct opt_auto_increment opt_not_null opt_not opt_primary_key opt_for_share opt_with_ties
 %type <update> update
 %type <updates> updates
 %type <onConflict> opt_on_conflict
@@ -170,8 +193,9 @@ mulExp unaryExp primary
 %type <whenThenClauses> when_then_clauses
 %type <timestampField> timestamp_field
 %type <sqlType> sql_type
+%type <triggerEvent> trigger_event
 %type <keyword> unreserved_keyword colNameKeyword
-%type <str> qualifiedName tableName col_name
+%type <str> qualifiedName tableName col_name comment_text
 
 %start sql
 
@@ -196,7 +220,13 @@ sqlstmts:
 
 opt_separator: {} | STMT_SEPARATOR
 
-sqlstmt: ddlstmt | dmlstmt | dqlstmt
+sqlstmt: ddlstmt | dmlstmt | dqlstmt | values_stmt
+
+values_stmt:
+    VALUES rows
+    {
+        $$ = &SelectStmt{ds: &valuesDataSource{inferTypes: true, rows: $2}}
+    }
 
 ddlstmt:
     BEGIN TRANSACTION
@@ -253,31 +283,104 @@ ddlstmt:
     {
        $$ = newCreateTableStmt($3, $5, false)
     }
+|
+    DROP TABLE IF EXISTS qualifiedName
+    {
+        $$ = &DropTableStmt{table: $5, ifExists: true}
+    }
 |
     DROP TABLE qualifiedName
     {
         $$ = &DropTableStmt{table: $3}
     }
+|
+    CREATE VIEW IF NOT EXISTS tableName AS select_stmt
+    {
+        // the view query starts at the token following AS
+        $$ = &CreateViewStmt{view: $6, ifNotExists: true, query: $8.(*SelectStmt), sql: yylex.(*lexer).recordedText(5, yyrcvr.char >= 0)}
+        yylex.(*lexer).stopRecording()
+    }
+|
+    CREATE VIEW tableName AS select_stmt
+    {
+        $$ = &CreateViewStmt{view: $3, query: $5.(*SelectStmt), sql: yylex.(*lexer).recordedText(2, yyrcvr.char >= 0)}
+        yylex.(*lexer).stopRecording()
+    }
+|
+    DROP VIEW tableName
+    {
+        $$ = &DropViewStmt{view: $3}
+        yylex.(*lexer).stopRecording()
+    }
+|
+    CREATE TRIGGER IF NOT EXISTS IDENTIFIER AFTER trigger_event ON tableName FOR EACH ROW dmlstmt
+    {
+        // the trigger body starts at the token following ROW
+        $$ = &CreateTriggerStmt{trigger: $6, ifNotExists: true, event: $8, table: $10, body: $14, sql: yylex.(*lexer).recordedText(11, yyrcvr.char >= 0)}
+        yylex.(*lexer).stopRecording()
+    }
+|
+    CREATE TRIGGER IDENTIFIER AFTER trigger_event ON tableName FOR EACH ROW dmlstmt
+    {
+        $$ = &CreateTriggerStmt{trigger: $3, event: $5, table: $7, body: $11, sql: yylex.(*lexer).recordedText(8, yyrcvr.char >= 0)}
+        yylex.(*lexer).stopRecording()
+    }
+|
+    DROP TRIGGER IDENTIFIER ON tableName
+    {
+        $$ = &DropTriggerStmt{trigger: $3, table: $5}
+        yylex.(*lexer).stopRecording()
+    }
 |
     CREATE INDEX opt_if_not_exists ON tableName '(' col_names ')'
     {
         $$ = &CreateIndexStmt{ifNotExists: $3, table: $5, cols: $7}
     }
 |
-    CREATE UNIQUE INDEX opt_if_not_exists ON tableName '(' col_names ')'
+    CREATE UNIQUE INDEX opt_if_not_exists ON tableName '(' col_names ')' opt_nulls_not_distinct
     {
-        $$ = &CreateIndexStmt{unique: true, ifNotExists: $4, table: $6, cols: $8}
+        $$ = &CreateIndexStmt{unique: true, nullsNotDistinct: $10, ifNotExists: $4, table: $6, cols: $8}
+    }
+|
+    CREATE FULLTEXT INDEX opt_if_not_exists ON tableName '(' col_name ')'
+    {
+        $$ = &CreateIndexStmt{fullText: true, ifNotExists: $4, table: $6, cols: []string{$8}}
     }
 |
     DROP INDEX ON tableName '(' col_names ')'
     {
         $$ = &DropIndexStmt{table: $4, cols: $6}
     }
+|
+    DROP FULLTEXT INDEX ON tableName '(' col_name ')'
+    {
+        $$ = &DropIndexStmt{fullText: true, table: $5, cols: []string{$7}}
+    }
 |
     DROP INDEX tableName DOT col_name
     {
         $$ = &DropIndexStmt{table: $3, cols: []string{$5}}
     }
+|
+    REINDEX INDEX ON tableName '(' col_names ')'
+    {
+        $$ = &ReindexStmt{table: $4, cols: $6}
+    }
+|
+    REINDEX FULLTEXT INDEX ON tableName '(' col_name ')'
+    {
+        $$ = &ReindexStmt{fullText: true, table: $5, cols: []string{$7}}
+    }
+|
+    REINDEX INDEX tableName DOT col_name
+    {
+        $$ = &ReindexStmt{table: $3, cols: []string{$5}}
+    }
+|
+    REINDEX TABLE tableName
+    {
+        $$ = &ReindexStmt{table: $3}
+    }
 |
     ALTER TABLE tableName ADD COLUMN colSpec
     {
@@ -303,6 +406,16 @@ ddlstmt:
     {
         $$ = &DropConstraintStmt{table: $3, constraintName: $6}
     }
+|
+    COMMENT ON TABLE tableName IS comment_text
+    {
+        $$ = &CommentStmt{table: $4, comment: $6}
+    }
+|
+    COMMENT ON COLUMN tableName DOT col_name IS comment_text
+    {
+        $$ = &CommentStmt{table: $4, col: $6, comment: $8}
+    }
 |
     CREATE USER IDENTIFIER WITH PASSWORD VARCHAR_LIT permission
     {
@@ -415,6 +528,22 @@ opt_if_not_exists:
     }
 ;
 
+opt_nulls_not_distinct:
+    {
+        $$ = false
+    }
+|
+    NULLS DISTINCT
+    {
+        $$ = false
+    }
+|
+    NULLS NOT DISTINCT
+    {
+        $$ = true
+    }
+;
+
 dmlstmt:
     INSERT INTO tableRef insert_cols values_or_query opt_on_conflict
     {
@@ -448,6 +577,14 @@ values_or_query:
 	}
 
 
+trigger_event:
+    INSERT { $$ = TriggerOnInsert }
+|
+    UPDATE { $$ = TriggerOnUpdate }
+|
+    DELETE { $$ = TriggerOnDelete }
+;
+
 opt_on_conflict:
     {
         $$ = nil
@@ -574,6 +711,11 @@ val:
     {
         $$ = &NullValue{t: AnyType}
     }
+|
+    POINT_TYPE '(' exp ',' exp ')'
+    {
+        $$ = &PointExp{lat: $3, lon: $5}
+    }
 ;
 
 sql_type:
@@ -585,12 +727,13 @@ sql_type:
     | TIMESTAMP_TYPE { $$ = TimestampType }
     | FLOAT_TYPE { $$ = Float64Type }
     | JSON_TYPE { $$ = JSONType }
+    | POINT_TYPE { $$ = PointType }
 ;
 
 fnCall:
     IDENTIFIER '(' opt_values ')'
     {
-        $$ = &FnCall{fn: $1, params: $3}
+        $$ = &FnCall{fn: $1, params: $3, functions: yylex.(*lexer).functions}
     }
 
 tableElems:
@@ -622,19 +765,31 @@ tableElem:
 ;
 
 colSpec:
-    col_name sql_type opt_max_len opt_not_null opt_auto_increment opt_primary_key
+    col_name sql_type opt_max_len opt_generated opt_not_null opt_auto_increment opt_primary_key
     {
         $$ = &ColSpec{
             colName: $1, 
             colType: $2, 
             maxLen: int($3), 
-            notNull: $4 || $6, 
-            autoIncrement: $5,
-            primaryKey: $6,
+            generatedExp: $4,
+            notNull: $5 || $7, 
+            autoIncrement: $6,
+            primaryKey: $7,
         }
     }
 ;
 
+opt_generated:
+    {
+        $$ = nil
+    }
+|
+    GENERATED ALWAYS AS '(' exp ')' STORED
+    {
+        $$ = $5
+    }
+;
+
 opt_primary_key:
     {
         $$ = false
@@ -743,30 +898,82 @@ dqlstmt:
         }
     }
 
-select_stmt: SELECT opt_distinct opt_targets FROM ds opt_indexon opt_joins opt_where opt_groupby opt_having opt_orderby opt_limit opt_offset
-    {
-        $$ = &SelectStmt{
-                distinct: $2,
-                targets: $3,
-                ds: $5,
-                indexOn: $6,
-                joins: $7,
-                where: $8,
-                groupBy: $9,
-                having: $10,
-                orderBy: $11,
-                limit: $12,
-                offset: $13,
+select_stmt: SELECT opt_hints opt_distinct opt_targets FROM ds opt_indexon opt_joins opt_where opt_groupby opt_having opt_orderby opt_limit opt_offset opt_with_ties opt_fetch opt_for_share
+    {
+        stmt := &SelectStmt{
+                distinct: $3,
+                targets: $4,
+                ds: $6,
+                indexOn: $7,
+                joins: $8,
+                where: $9,
+                having: $11,
+                orderBy: $12,
+                limit: $13,
+                offset: $14,
+                withTies: $15,
+                forShare: $17,
             }
+
+        if $15 && $13 == nil {
+            yylex.Error("WITH TIES requires LIMIT or FETCH")
+        }
+
+        if $16 != nil {
+            if $13 != nil {
+                yylex.Error("LIMIT and FETCH can not be combined")
+            }
+
+            stmt.limit = $16.limit
+            stmt.withTies = $16.withTies
+        }
+
+        err := stmt.applyIndexHints($2)
+        if err != nil {
+            yylex.Error(err.Error())
+        }
+
+        err = stmt.resolveOrdinals($10)
+        if err != nil {
+            yylex.Error(err.Error())
+        }
+
+        $$ = stmt
     }
 |
-    SELECT opt_distinct opt_targets
+    SELECT opt_hints opt_distinct opt_targets opt_where opt_limit opt_offset
     {
-        $$ = &SelectStmt{
-            distinct: $2,
-            targets: $3,
+        if $2 != nil {
+            yylex.Error("optimizer hints require a FROM clause")
+        }
+
+        // without a FROM clause, targets are evaluated over a single row with no columns
+        stmt := &SelectStmt{
+            distinct: $3,
+            targets: $4,
             ds: &valuesDataSource{rows: []*RowSpec{{}}},
+            where: $5,
+            limit: $6,
+            offset: $7,
         }
+
+        $$ = stmt
+    }
+;
+
+opt_hints:
+    {
+        $$ = nil
+    }
+|
+    OPTIMIZER_HINTS
+    {
+        hints, err := parseOptimizerHints($1)
+        if err != nil {
+            yylex.Error(err.Error())
+        }
+
+        $$ = hints
     }
 ;
 
@@ -825,14 +1032,71 @@ selector:
 |
     AGGREGATE_FUNC '(' '*' ')'
     {
-        $$ = &AggColSelector{aggFn: $1, col: "*"}
+        $$ = &AggColSelector{aggFn: $1, col: "*", aggregate: yylex.(*lexer).functions.aggregate($1)}
     }
 |
     AGGREGATE_FUNC '(' col ')'
     {
-        $$ = &AggColSelector{aggFn: $1, table: $3.table, col: $3.col}
+        $$ = &AggColSelector{aggFn: $1, table: $3.table, col: $3.col, aggregate: yylex.(*lexer).functions.aggregate($1)}
+    }
+|
+    AGGREGATE_FUNC '(' DISTINCT cols ')'
+    {
+        sel, err := newCountDistinctSelector($1, $4)
+        if err != nil {
+            yylex.Error(err.Error())
+        }
+        $$ = sel
+    }
+|
+    percentile_fn '(' fraction ')' WITHIN GROUP '(' ORDER BY col opt_ord ')'
+    {
+        sel, err := newPercentileSelector($1, $3, $10, $11)
+        if err != nil {
+            yylex.Error(err.Error())
+        }
+        $$ = sel
+    }
+|
+    APPROX_PERCENTILE_FN '(' col ',' fraction ')'
+    {
+        sel, err := newPercentileSelector(APPROX_PERCENTILE, $5, $3, false)
+        if err != nil {
+            yylex.Error(err.Error())
+        }
+        $$ = sel
+    }
+|
+    RESERVOIR_SAMPLE_FN '(' col ',' INTEGER_LIT ')'
+    {
+        sel, err := newReservoirSampleSelector($3, $5, nil)
+        if err != nil {
+            yylex.Error(err.Error())
+        }
+        $$ = sel
+    }
+|
+    RESERVOIR_SAMPLE_FN '(' col ',' INTEGER_LIT ',' INTEGER_LIT ')'
+    {
+        sel, err := newReservoirSampleSelector($3, $5, &$7)
+        if err != nil {
+            yylex.Error(err.Error())
+        }
+        $$ = sel
     }
 
+percentile_fn:
+    PERCENTILE_CONT_FN { $$ = PERCENTILE_CONT }
+|
+    PERCENTILE_DISC_FN { $$ = PERCENTILE_DISC }
+;
+
+fraction:
+    FLOAT_LIT
+|
+    INTEGER_LIT { $$ = float64($1) }
+;
+
 jsonFields:
     ARROW VARCHAR_LIT
     {
@@ -858,6 +1122,12 @@ col:
 
 tableName: qualifiedName;
 
+comment_text:
+    VARCHAR_LIT
+|
+    NULL { $$ = "" }
+;
+
 col_name:
     qualifiedName
 |
@@ -898,6 +1168,7 @@ colNameKeyword:
     | FLOAT_TYPE
     | INTEGER_TYPE
     | JSON_TYPE
+    | POINT_TYPE
     | TIMESTAMP_TYPE
     | VALUES
     | VARCHAR_TYPE
@@ -910,6 +1181,7 @@ qualifiedName:
 
 unreserved_keyword:
     ADMIN
+    | VIEW
     | OF
     | DROP
     | DATABASE
@@ -941,6 +1213,23 @@ unreserved_keyword:
     | MINUTE
     | SECOND
     | USERS
+    | FIRST
+    | NEXT
+    | ONLY
+    | TIES
+    | NULLS
+    | LAST
+    | COMMENT
+    | SHARE
+    | TRIGGER
+    | EACH
+    | LATERAL
+    | FULLTEXT
+    | REINDEX
+    | BOX
+    | GENERATED
+    | ALWAYS
+    | STORED
 ;
 
 ds:
@@ -951,9 +1240,19 @@ ds:
         $$ = $1
     }
 |
-    '(' VALUES rows ')'
+    '(' VALUES rows ')' opt_as
+    {
+        $$ = &valuesDataSource{inferTypes: true, rows: $3, as: $5}
+    }
+|
+    '(' VALUES rows ')' qualifiedName '(' col_names ')'
     {
-        $$ = &valuesDataSource{inferTypes: true, rows: $3}
+        $$ = &valuesDataSource{inferTypes: true, rows: $3, as: $5, colNames: $7}
+    }
+|
+    '(' VALUES rows ')' AS qualifiedName '(' col_names ')'
+    {
+        $$ = &valuesDataSource{inferTypes: true, rows: $3, as: $6, colNames: $8}
     }
 |
     '(' dqlstmt ')' opt_as
@@ -997,6 +1296,11 @@ tableRef:
     {
         $$ = &tableRef{table: $1}
     }
+|
+    qualifiedName DOT qualifiedName
+    {
+        $$ = &tableRef{table: $1 + "." + $3}
+    }
 
 opt_period:
     opt_period_start opt_period_end
@@ -1071,6 +1375,33 @@ join:
     {
         $$ = &JoinSpec{joinType: $1, ds: $3, indexOn: $4, cond: $6}
     }
+|
+    opt_join_type JOIN ds opt_indexon USING '(' col_names ')'
+    {
+        $$ = &JoinSpec{joinType: $1, ds: $3, indexOn: $4, using: $7}
+    }
+|
+    NATURAL opt_join_type JOIN ds opt_indexon
+    {
+        $$ = &JoinSpec{joinType: $2, ds: $4, indexOn: $5, natural: true}
+    }
+|
+    ',' lateral_ds
+    {
+        $$ = &JoinSpec{joinType: InnerJoin, ds: $2, cond: &Bool{val: true}, lateral: true}
+    }
+|
+    opt_join_type JOIN lateral_ds ON exp
+    {
+        $$ = &JoinSpec{joinType: $1, ds: $3, cond: $5, lateral: true}
+    }
+
+lateral_ds:
+    LATERAL '(' dqlstmt ')' opt_as
+    {
+        $3.(*SelectStmt).as = $5
+        $$ = $3.(DataSource)
+    }
 
 opt_join_type:
     {
@@ -1097,11 +1428,33 @@ opt_groupby:
         $$ = nil
     }
 |
-    GROUP BY cols
+    GROUP BY groupby_items
     {
         $$ = $3
     }
 
+groupby_items:
+    groupby_item
+    {
+        $$ = []ValueExp{$1}
+    }
+|
+    groupby_items ',' groupby_item
+    {
+        $$ = append($1, $3)
+    }
+
+groupby_item:
+    col
+    {
+        $$ = $1
+    }
+|
+    INTEGER_LIT
+    {
+        $$ = &Integer{val: int64($1)}
+    }
+
 opt_having:
     {
         $$ = nil
@@ -1132,6 +1485,48 @@ opt_offset:
         $$ = $2
     }
 
+opt_with_ties:
+    {
+        $$ = false
+    }
+|
+    WITH TIES
+    {
+        $$ = true
+    }
+;
+
+opt_fetch:
+    {
+        $$ = nil
+    }
+|
+    FETCH first_or_next opt_exp row_or_rows ONLY
+    {
+        $$ = newFetchClause($3, false)
+    }
+|
+    FETCH first_or_next opt_exp row_or_rows WITH TIES
+    {
+        $$ = newFetchClause($3, true)
+    }
+;
+
+opt_for_share:
+    {
+        $$ = false
+    }
+|
+    FOR SHARE
+    {
+        $$ = true
+    }
+;
+
+first_or_next: FIRST | NEXT;
+
+row_or_rows: ROW | ROWS;
+
 opt_orderby:
     {
         $$ = nil
@@ -1154,14 +1549,14 @@ opt_indexon:
 ;
 
 ordexps:
-    exp opt_ord
+    exp opt_ord opt_nulls
     {
-        $$ = []*OrdExp{{exp: $1, descOrder: $2}}
+        $$ = []*OrdExp{{exp: $1, descOrder: $2, nullsOrder: $3}}
     }
 |
-    ordexps ',' exp opt_ord
+    ordexps ',' exp opt_ord opt_nulls
     {
-        $$ = append($1, &OrdExp{exp: $3, descOrder: $4})
+        $$ = append($1, &OrdExp{exp: $3, descOrder: $4, nullsOrder: $5})
     }
 
 opt_ord:
@@ -1179,6 +1574,21 @@ opt_ord:
         $$ = true
     }
 
+opt_nulls:
+    {
+        $$ = nullsOrderUnspecified
+    }
+|
+    NULLS FIRST
+    {
+        $$ = NullsFirst
+    }
+|
+    NULLS LAST
+    {
+        $$ = NullsLast
+    }
+
 opt_as:
     {
         $$ = ""
@@ -1274,6 +1684,8 @@ cmpExp
     : addExp CMPOP addExp               { $$ = &CmpBoolExp{left: $1, op: $2, right: $3} }
     | addExp IS NULL                    { $$ = &CmpBoolExp{left: $1, op: EQ, right: &NullValue{t: AnyType}} }
     | addExp IS NOT NULL                { $$ = &CmpBoolExp{left: $1, op: NE, right: &NullValue{t: AnyType}} }
+    | addExp IS DISTINCT FROM addExp    { $$ = &DistinctBoolExp{left: $1, right: $5} }
+    | addExp IS NOT DISTINCT FROM addExp { $$ = &DistinctBoolExp{left: $1, right: $6, notDistinct: true} }
     | addExp BETWEEN addExp AND addExp
     {
         $$ = &BinBoolExp{
@@ -1292,6 +1704,11 @@ cmpExp
     }
     | addExp opt_not LIKE addExp    { $$ = &LikeBoolExp{val: $1, notLike: $2, pattern: $4} }
     | addExp NOT_MATCHES_OP addExp  { $$ = &LikeBoolExp{val: $1, notLike: true, pattern: $3} }
+    | addExp MATCH addExp           { $$ = &MatchBoolExp{val: $1, query: $3} }
+    | addExp WITHIN BOX '(' exp ',' exp ',' exp ',' exp ')'
+    {
+        $$ = &WithinBoxExp{val: $1, minLat: $5, minLon: $7, maxLat: $9, maxLon: $11}
+    }
     | primaryBool
     ;
 
@@ -49,6 +49,7 @@ type yySymType struct {
 	err             error
 	ordexps         []*OrdExp
 	opt_ord         bool
+	nullsOrder      NullsOrder
 	logicOp         LogicOperator
 	cmpOp           CmpOperator
 	pparam          int
@@ -62,6 +63,9 @@ type yySymType struct {
 	tableElem       TableElem
 	tableElems      []TableElem
 	timestampField  TimestampFieldType
+	fetch           *fetchClause
+	triggerEvent    TriggerEvent
+	hints           []*indexHint
 }
 
 const CREATE = 57346
@@ -90,100 +94,131 @@ const BLOB_TYPE = 57368
 const TIMESTAMP_TYPE = 57369
 const FLOAT_TYPE = 57370
 const JSON_TYPE = 57371
-const TABLE = 57372
-const UNIQUE = 57373
-const INDEX = 57374
-const ON = 57375
-const ALTER = 57376
-const ADD = 57377
-const RENAME = 57378
-const TO = 57379
-const COLUMN = 57380
-const CONSTRAINT = 57381
-const PRIMARY = 57382
-const KEY = 57383
-const CHECK = 57384
-const GRANT = 57385
-const REVOKE = 57386
-const GRANTS = 57387
-const FOR = 57388
-const PRIVILEGES = 57389
-const BEGIN = 57390
-const TRANSACTION = 57391
-const COMMIT = 57392
-const ROLLBACK = 57393
-const INSERT = 57394
-const UPSERT = 57395
-const INTO = 57396
-const VALUES = 57397
-const DELETE = 57398
-const UPDATE = 57399
-const SET = 57400
-const CONFLICT = 57401
-const DO = 57402
-const NOTHING = 57403
-const RETURNING = 57404
-const SELECT = 57405
-const DISTINCT = 57406
-const FROM = 57407
-const JOIN = 57408
-const HAVING = 57409
-const WHERE = 57410
-const GROUP = 57411
-const BY = 57412
-const LIMIT = 57413
-const OFFSET = 57414
-const ORDER = 57415
-const ASC = 57416
-const DESC = 57417
-const AS = 57418
-const UNION = 57419
-const ALL = 57420
-const CASE = 57421
-const WHEN = 57422
-const THEN = 57423
-const ELSE = 57424
-const END = 57425
-const NOT = 57426
-const LIKE = 57427
-const IF = 57428
-const EXISTS = 57429
-const IN = 57430
-const IS = 57431
-const AUTO_INCREMENT = 57432
-const NULL = 57433
-const CAST = 57434
-const SCAST = 57435
-const SHOW = 57436
-const DATABASES = 57437
-const TABLES = 57438
-const USERS = 57439
-const BETWEEN = 57440
-const EXTRACT = 57441
-const YEAR = 57442
-const MONTH = 57443
-const DAY = 57444
-const HOUR = 57445
-const MINUTE = 57446
-const SECOND = 57447
-const NPARAM = 57448
-const PPARAM = 57449
-const JOINTYPE = 57450
-const AND = 57451
-const OR = 57452
-const CMPOP = 57453
-const NOT_MATCHES_OP = 57454
-const IDENTIFIER = 57455
-const INTEGER_LIT = 57456
-const FLOAT_LIT = 57457
-const VARCHAR_LIT = 57458
-const BOOLEAN_LIT = 57459
-const BLOB_LIT = 57460
-const AGGREGATE_FUNC = 57461
-const ERROR = 57462
-const DOT = 57463
-const ARROW = 57464
-const STMT_SEPARATOR = 57465
+const POINT_TYPE = 57372
+const TABLE = 57373
+const VIEW = 57374
+const UNIQUE = 57375
+const INDEX = 57376
+const ON = 57377
+const ALTER = 57378
+const ADD = 57379
+const RENAME = 57380
+const TO = 57381
+const COLUMN = 57382
+const CONSTRAINT = 57383
+const PRIMARY = 57384
+const KEY = 57385
+const CHECK = 57386
+const GRANT = 57387
+const REVOKE = 57388
+const GRANTS = 57389
+const FOR = 57390
+const PRIVILEGES = 57391
+const BEGIN = 57392
+const TRANSACTION = 57393
+const COMMIT = 57394
+const ROLLBACK = 57395
+const INSERT = 57396
+const UPSERT = 57397
+const INTO = 57398
+const VALUES = 57399
+const DELETE = 57400
+const UPDATE = 57401
+const SET = 57402
+const CONFLICT = 57403
+const DO = 57404
+const NOTHING = 57405
+const RETURNING = 57406
+const SELECT = 57407
+const DISTINCT = 57408
+const FROM = 57409
+const JOIN = 57410
+const HAVING = 57411
+const WHERE = 57412
+const GROUP = 57413
+const BY = 57414
+const LIMIT = 57415
+const OFFSET = 57416
+const ORDER = 57417
+const ASC = 57418
+const DESC = 57419
+const AS = 57420
+const UNION = 57421
+const ALL = 57422
+const CASE = 57423
+const WHEN = 57424
+const THEN = 57425
+const ELSE = 57426
+const END = 57427
+const FETCH = 57428
+const FIRST = 57429
+const NEXT = 57430
+const ROW = 57431
+const ROWS = 57432
+const ONLY = 57433
+const TIES = 57434
+const NULLS = 57435
+const LAST = 57436
+const COMMENT = 57437
+const SHARE = 57438
+const TRIGGER = 57439
+const EACH = 57440
+const NATURAL = 57441
+const USING = 57442
+const LATERAL = 57443
+const FULLTEXT = 57444
+const MATCH = 57445
+const REINDEX = 57446
+const BOX = 57447
+const PERCENTILE_CONT_FN = 57448
+const PERCENTILE_DISC_FN = 57449
+const APPROX_PERCENTILE_FN = 57450
+const WITHIN = 57451
+const RESERVOIR_SAMPLE_FN = 57452
+const NOT = 57453
+const LIKE = 57454
+const IF = 57455
+const EXISTS = 57456
+const IN = 57457
+const IS = 57458
+const AUTO_INCREMENT = 57459
+const NULL = 57460
+const CAST = 57461
+const SCAST = 57462
+const GENERATED = 57463
+const ALWAYS = 57464
+const STORED = 57465
+const SHOW = 57466
+const DATABASES = 57467
+const TABLES = 57468
+const USERS = 57469
+const BETWEEN = 57470
+const EXTRACT = 57471
+const YEAR = 57472
+const MONTH = 57473
+const DAY = 57474
+const HOUR = 57475
+const MINUTE = 57476
+const SECOND = 57477
+const NPARAM = 57478
+const PPARAM = 57479
+const JOINTYPE = 57480
+const AND = 57481
+const OR = 57482
+const CMPOP = 57483
+const NOT_MATCHES_OP = 57484
+const IDENTIFIER = 57485
+const INTEGER_LIT = 57486
+const FLOAT_LIT = 57487
+const VARCHAR_LIT = 57488
+const OPTIMIZER_HINTS = 57489
+const BOOLEAN_LIT = 57490
+const BLOB_LIT = 57491
+const AGGREGATE_FUNC = 57492
+const ERROR = 57493
+const DOT = 57494
+const ARROW = 57495
+const STMT_SEPARATOR = 57496
 
 var yyToknames = [...]string{
 	"$end",
@@ -215,7 +250,9 @@ var yyToknames = [...]string{
 	"TIMESTAMP_TYPE",
 	"FLOAT_TYPE",
 	"JSON_TYPE",
+	"POINT_TYPE",
 	"TABLE",
+	"VIEW",
 	"UNIQUE",
 	"INDEX",
 	"ON",
@@ -269,6 +306,31 @@ var yyToknames = [...]string{
 	"THEN",
 	"ELSE",
 	"END",
+	"FETCH",
+	"FIRST",
+	"NEXT",
+	"ROW",
+	"ROWS",
+	"ONLY",
+	"TIES",
+	"NULLS",
+	"LAST",
+	"COMMENT",
+	"SHARE",
+	"TRIGGER",
+	"EACH",
+	"NATURAL",
+	"USING",
+	"LATERAL",
+	"FULLTEXT",
+	"MATCH",
+	"REINDEX",
+	"BOX",
+	"PERCENTILE_CONT_FN",
+	"PERCENTILE_DISC_FN",
+	"APPROX_PERCENTILE_FN",
+	"WITHIN",
+	"RESERVOIR_SAMPLE_FN",
 	"NOT",
 	"LIKE",
 	"IF",
@@ -279,6 +341,9 @@ var yyToknames = [...]string{
 	"NULL",
 	"CAST",
 	"SCAST",
+	"GENERATED",
+	"ALWAYS",
+	"STORED",
 	"SHOW",
 	"DATABASES",
 	"TABLES",
@@ -302,6 +367,7 @@ var yyToknames = [...]string{
 	"INTEGER_LIT",
 	"FLOAT_LIT",
 	"VARCHAR_LIT",
+	"OPTIMIZER_HINTS",
 	"BOOLEAN_LIT",
 	"BLOB_LIT",
 	"AGGREGATE_FUNC",
@@ -332,467 +398,700 @@ var yyExca = [...]int16{
 	-1, 1,
 	1, -1,
 	-2, 0,
-	-1, 139,
-	85, 277,
-	88, 277,
-	-2, 261,
-	-1, 370,
-	66, 210,
-	-2, 205,
-	-1, 428,
-	66, 210,
-	-2, 207,
+	-1, 190,
+	112, 366,
+	115, 366,
+	-2, 350,
+	-1, 572,
+	68, 277,
+	-2, 267,
+	-1, 623,
+	68, 277,
+	-2, 269,
 }
 
 const yyPrivate = 57344
 
-const yyLast = 1864
+const yyLast = 3247
 
 var yyAct = [...]int16{
-	190, 522, 167, 421, 153, 278, 213, 161, 364, 284,
-	204, 360, 275, 246, 312, 427, 335, 359, 6, 334,
-	408, 399, 54, 247, 108, 207, 248, 101, 136, 272,
-	102, 490, 497, 135, 139, 144, 188, 112, 102, 404,
-	102, 403, 362, 362, 141, 491, 484, 340, 396, 483,
-	418, 492, 486, 54, 54, 54, 485, 480, 165, 472,
-	362, 362, 362, 114, 340, 116, 479, 477, 465, 458,
-	412, 363, 438, 339, 436, 435, 433, 395, 393, 392,
-	385, 59, 311, 60, 361, 407, 397, 384, 378, 57,
-	61, 377, 376, 375, 345, 262, 133, 58, 173, 171,
-	177, 243, 170, 175, 172, 174, 241, 240, 62, 237,
-	63, 64, 65, 230, 202, 66, 102, 67, 180, 68,
-	69, 24, 521, 70, 71, 72, 73, 74, 75, 224,
-	225, 176, 76, 77, 328, 78, 214, 227, 228, 229,
-	382, 192, 205, 201, 515, 209, 232, 418, 191, 235,
-	396, 212, 120, 39, 239, 224, 225, 242, 193, 391,
-	354, 347, 79, 234, 329, 456, 218, 455, 98, 49,
-	80, 474, 81, 88, 169, 254, 82, 83, 84, 85,
-	86, 87, 233, 462, 102, 461, 437, 216, 226, 55,
-	261, 32, 208, 220, 353, 99, 344, 282, 33, 337,
-	210, 270, 221, 271, 128, 117, 280, 401, 115, 255,
-	107, 106, 283, 292, 54, 219, 223, 281, 293, 291,
-	274, 217, 274, 259, 260, 430, 22, 103, 224, 225,
-	236, 454, 273, 277, 298, 489, 381, 104, 453, 251,
-	22, 297, 332, 488, 336, 331, 295, 92, 102, 308,
-	294, 256, 263, 343, 296, 245, 299, 21, 302, 244,
-	102, 276, 94, 305, 306, 307, 342, 203, 102, 303,
-	304, 21, 182, 338, 199, 348, 322, 323, 324, 325,
-	326, 327, 443, 300, 369, 346, 301, 367, 374, 179,
-	370, 349, 178, 350, 214, 214, 481, 31, 379, 380,
-	446, 333, 10, 12, 11, 373, 310, 387, 368, 388,
-	371, 389, 90, 91, 93, 127, 89, 523, 524, 508,
-	394, 276, 422, 251, 365, 351, 352, 514, 372, 503,
-	495, 205, 13, 183, 502, 383, 22, 471, 493, 390,
-	211, 14, 15, 52, 96, 463, 7, 417, 8, 9,
-	16, 17, 125, 51, 18, 19, 50, 25, 406, 119,
-	129, 22, 336, 405, 398, 506, 423, 21, 341, 500,
-	267, 268, 265, 266, 214, 414, 425, 264, 431, 413,
-	356, 419, 285, 355, 512, 336, 36, 424, 444, 445,
-	432, 447, 21, 358, 257, 181, 121, 449, 118, 251,
-	400, 441, 53, 440, 276, 366, 457, 105, 34, 448,
-	35, 450, 434, 269, 451, 187, 186, 439, 196, 258,
-	459, 420, 416, 466, 197, 43, 47, 26, 30, 468,
-	464, 38, 2, 122, 123, 124, 214, 469, 214, 214,
-	473, 214, 475, 476, 470, 478, 467, 482, 194, 195,
-	27, 29, 28, 37, 184, 48, 251, 97, 110, 111,
-	276, 409, 410, 411, 415, 200, 276, 198, 279, 23,
-	168, 56, 460, 44, 54, 321, 309, 46, 45, 291,
-	41, 496, 498, 400, 42, 313, 314, 315, 316, 317,
-	318, 319, 320, 357, 206, 499, 222, 452, 487, 40,
-	214, 507, 504, 509, 505, 518, 402, 132, 511, 130,
-	143, 494, 147, 516, 140, 519, 513, 517, 138, 134,
-	520, 59, 525, 60, 386, 149, 501, 526, 231, 57,
-	61, 249, 429, 428, 426, 185, 109, 58, 173, 171,
-	177, 126, 170, 175, 172, 174, 95, 238, 62, 150,
-	63, 64, 65, 151, 510, 66, 20, 67, 5, 68,
-	69, 4, 3, 70, 71, 72, 73, 74, 75, 1,
-	0, 176, 76, 77, 0, 78, 0, 0, 0, 22,
+	254, 224, 760, 304, 730, 548, 204, 717, 438, 373,
+	220, 5, 624, 657, 477, 507, 622, 127, 215, 520,
+	376, 6, 466, 276, 68, 340, 148, 23, 327, 404,
+	183, 328, 533, 137, 137, 426, 279, 329, 182, 195,
+	187, 370, 252, 153, 137, 136, 137, 186, 192, 137,
+	128, 137, 137, 273, 53, 642, 525, 737, 524, 190,
+	475, 475, 475, 181, 744, 475, 68, 68, 68, 755,
+	750, 739, 659, 63, 646, 475, 444, 618, 139, 743,
+	286, 727, 475, 565, 637, 636, 617, 724, 154, 609,
+	157, 594, 566, 161, 475, 163, 164, 475, 475, 444,
+	687, 676, 664, 539, 295, 647, 537, 476, 443, 643,
+	296, 635, 299, 633, 632, 630, 620, 291, 619, 616,
+	613, 608, 598, 597, 506, 501, 498, 497, 490, 292,
+	184, 403, 285, 726, 704, 683, 690, 651, 129, 580,
+	579, 578, 290, 294, 577, 532, 531, 518, 489, 488,
+	459, 457, 452, 357, 354, 320, 297, 298, 137, 318,
+	317, 316, 137, 486, 315, 314, 313, 310, 137, 137,
+	303, 274, 239, 300, 301, 302, 28, 277, 281, 297,
+	298, 297, 298, 625, 753, 735, 711, 674, 610, 181,
+	505, 503, 502, 437, 255, 286, 170, 305, 421, 282,
+	308, 312, 258, 367, 319, 262, 261, 259, 176, 131,
+	496, 468, 268, 269, 465, 142, 428, 427, 140, 455,
+	422, 39, 627, 652, 589, 271, 588, 275, 40, 289,
+	569, 661, 284, 133, 634, 602, 324, 306, 626, 467,
+	601, 280, 464, 450, 448, 441, 137, 283, 141, 179,
+	332, 165, 159, 155, 351, 147, 146, 381, 352, 137,
+	288, 380, 137, 134, 287, 137, 627, 741, 26, 338,
+	355, 587, 368, 358, 369, 641, 309, 668, 378, 415,
+	416, 417, 418, 419, 420, 374, 388, 382, 667, 640,
+	344, 124, 484, 349, 350, 372, 639, 372, 543, 366,
+	345, 576, 379, 353, 339, 337, 356, 126, 371, 359,
+	390, 326, 143, 391, 325, 256, 26, 375, 431, 432,
+	424, 184, 429, 430, 693, 400, 439, 24, 137, 384,
+	433, 387, 245, 242, 447, 383, 240, 567, 386, 137,
+	397, 398, 399, 574, 483, 436, 395, 396, 137, 137,
+	385, 26, 389, 238, 392, 393, 237, 38, 394, 434,
+	246, 247, 446, 137, 456, 374, 720, 458, 374, 694,
+	658, 332, 442, 462, 463, 24, 49, 709, 469, 47,
+	645, 770, 482, 449, 768, 122, 123, 125, 46, 474,
+	778, 769, 453, 454, 761, 673, 777, 758, 460, 728,
+	480, 774, 775, 494, 765, 766, 757, 461, 671, 560,
+	24, 41, 42, 611, 44, 563, 492, 481, 493, 30,
+	37, 402, 178, 121, 731, 732, 478, 669, 527, 504,
+	241, 721, 500, 714, 675, 549, 734, 68, 516, 699,
+	696, 678, 517, 31, 32, 35, 34, 48, 615, 435,
+	487, 515, 277, 137, 514, 332, 521, 682, 277, 698,
+	655, 553, 530, 374, 495, 485, 66, 710, 538, 236,
+	540, 26, 648, 603, 546, 175, 519, 43, 776, 550,
+	19, 20, 45, 341, 21, 22, 547, 343, 342, 184,
+	559, 65, 64, 561, 562, 552, 564, 529, 542, 29,
+	169, 763, 691, 508, 593, 180, 571, 526, 707, 33,
+	556, 445, 689, 166, 36, 360, 439, 541, 439, 557,
+	471, 582, 167, 581, 266, 583, 67, 590, 572, 332,
+	470, 575, 585, 374, 570, 573, 363, 364, 568, 361,
+	362, 595, 374, 596, 701, 554, 555, 584, 57, 61,
+	604, 644, 599, 551, 592, 591, 264, 265, 606, 473,
+	451, 347, 521, 346, 612, 263, 600, 260, 172, 173,
+	174, 605, 614, 257, 244, 439, 171, 168, 52, 479,
+	62, 162, 628, 405, 406, 407, 408, 409, 410, 411,
+	412, 413, 629, 158, 145, 51, 144, 2, 58, 631,
+	251, 250, 60, 59, 150, 151, 528, 243, 365, 56,
+	348, 649, 650, 607, 534, 535, 536, 752, 50, 267,
+	248, 545, 544, 272, 54, 270, 132, 377, 773, 764,
+	27, 225, 439, 70, 439, 439, 414, 439, 656, 660,
+	654, 662, 663, 401, 665, 653, 137, 55, 472, 278,
+	751, 762, 688, 293, 638, 666, 672, 68, 713, 746,
+	523, 685, 323, 321, 130, 756, 586, 194, 684, 680,
+	716, 679, 677, 198, 514, 695, 191, 189, 185, 491,
+	200, 697, 330, 692, 68, 623, 621, 249, 705, 439,
+	670, 708, 149, 177, 235, 311, 706, 206, 702, 715,
+	700, 514, 722, 712, 201, 703, 718, 202, 499, 7,
+	4, 3, 729, 1, 725, 374, 0, 0, 0, 0,
+	0, 736, 0, 723, 0, 0, 439, 0, 733, 0,
+	0, 0, 0, 738, 0, 747, 740, 374, 0, 745,
+	742, 0, 718, 748, 0, 0, 0, 749, 374, 0,
+	0, 0, 754, 0, 759, 0, 0, 0, 74, 0,
+	75, 0, 0, 0, 767, 305, 71, 76, 771, 0,
+	772, 0, 0, 0, 73, 230, 228, 234, 0, 227,
+	232, 229, 231, 219, 0, 72, 0, 77, 0, 78,
+	79, 80, 0, 0, 81, 0, 82, 0, 83, 84,
+	0, 0, 85, 86, 87, 88, 89, 90, 0, 0,
+	233, 91, 92, 0, 93, 0, 0, 0, 26, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 145, 0, 0, 0, 0,
-	137, 0, 79, 142, 0, 0, 0, 164, 160, 0,
-	442, 0, 81, 88, 169, 152, 82, 83, 84, 85,
-	86, 87, 162, 163, 0, 0, 0, 0, 0, 166,
-	155, 156, 157, 158, 159, 154, 59, 0, 60, 0,
-	0, 146, 0, 0, 57, 61, 0, 148, 0, 0,
-	0, 189, 58, 173, 171, 177, 0, 170, 175, 172,
-	174, 0, 0, 62, 0, 63, 64, 65, 0, 0,
-	66, 0, 67, 0, 68, 69, 0, 0, 70, 71,
-	72, 73, 74, 75, 0, 0, 176, 76, 77, 0,
-	78, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 196, 0, 0, 0, 0, 0,
+	104, 105, 0, 0, 106, 107, 108, 109, 110, 111,
+	112, 113, 0, 0, 114, 115, 0, 116, 117, 221,
+	222, 207, 0, 208, 188, 0, 94, 193, 0, 0,
+	0, 218, 214, 0, 118, 119, 120, 558, 0, 96,
+	103, 226, 203, 97, 98, 99, 100, 101, 102, 216,
+	217, 0, 0, 0, 0, 0, 223, 209, 210, 211,
+	0, 212, 213, 205, 74, 0, 75, 0, 0, 197,
+	0, 0, 71, 76, 0, 199, 0, 0, 0, 253,
+	73, 230, 228, 234, 0, 227, 232, 229, 231, 219,
+	0, 72, 0, 77, 0, 78, 79, 80, 0, 0,
+	81, 0, 82, 0, 83, 84, 0, 0, 85, 86,
+	87, 88, 89, 90, 0, 0, 233, 91, 92, 0,
+	93, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	145, 0, 0, 0, 0, 137, 0, 79, 142, 0,
-	0, 0, 164, 160, 0, 80, 0, 81, 88, 169,
-	152, 82, 83, 84, 85, 86, 87, 162, 163, 0,
-	0, 0, 0, 0, 166, 155, 156, 157, 158, 159,
-	154, 59, 0, 60, 0, 0, 146, 0, 0, 57,
-	61, 0, 148, 0, 0, 0, 0, 58, 173, 171,
-	177, 0, 170, 175, 172, 174, 0, 0, 62, 0,
-	63, 64, 65, 0, 0, 66, 0, 67, 0, 68,
-	69, 0, 0, 70, 71, 72, 73, 74, 75, 0,
-	0, 176, 76, 77, 0, 78, 0, 0, 0, 0,
+	196, 0, 0, 0, 0, 0, 104, 105, 0, 0,
+	106, 107, 108, 109, 110, 111, 112, 113, 0, 0,
+	114, 115, 0, 116, 117, 221, 222, 207, 0, 208,
+	188, 0, 94, 193, 0, 0, 0, 218, 214, 0,
+	118, 119, 120, 95, 0, 96, 103, 226, 203, 97,
+	98, 99, 100, 101, 102, 216, 217, 0, 0, 0,
+	0, 0, 223, 209, 210, 211, 0, 212, 213, 205,
+	74, 0, 75, 0, 0, 197, 0, 0, 71, 76,
+	0, 199, 0, 0, 0, 0, 73, 230, 228, 234,
+	0, 227, 232, 229, 231, 219, 0, 72, 0, 77,
+	0, 78, 79, 80, 0, 0, 81, 0, 82, 0,
+	83, 84, 0, 0, 85, 86, 87, 88, 89, 90,
+	0, 0, 233, 91, 92, 0, 93, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 145, 0, 0, 0, 0,
-	137, 0, 79, 142, 0, 0, 0, 164, 160, 0,
-	80, 0, 81, 88, 169, 152, 82, 83, 84, 85,
-	86, 87, 162, 163, 0, 0, 0, 0, 0, 166,
-	155, 156, 157, 158, 159, 154, 59, 0, 60, 0,
-	0, 146, 131, 0, 57, 61, 0, 148, 0, 0,
-	0, 0, 58, 173, 171, 177, 0, 170, 175, 172,
-	174, 0, 0, 62, 0, 63, 64, 65, 0, 0,
-	66, 0, 67, 0, 68, 69, 0, 0, 70, 71,
-	72, 73, 74, 75, 0, 0, 176, 76, 77, 0,
-	78, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 196, 0, 0, 0,
+	0, 0, 104, 105, 0, 0, 106, 107, 108, 109,
+	110, 111, 112, 113, 0, 0, 114, 115, 0, 116,
+	117, 221, 222, 207, 0, 208, 188, 0, 94, 193,
+	0, 0, 0, 218, 214, 0, 118, 119, 120, 95,
+	0, 96, 103, 226, 203, 97, 98, 99, 100, 101,
+	102, 216, 217, 0, 0, 0, 0, 0, 223, 209,
+	210, 211, 0, 212, 213, 205, 74, 0, 75, 0,
+	0, 197, 322, 0, 71, 76, 0, 199, 0, 0,
+	0, 0, 73, 230, 228, 234, 0, 227, 232, 229,
+	231, 219, 0, 72, 0, 77, 0, 78, 79, 80,
+	0, 0, 81, 0, 82, 0, 83, 84, 0, 0,
+	85, 86, 87, 88, 89, 90, 0, 0, 233, 91,
+	92, 0, 93, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	145, 0, 0, 0, 0, 137, 0, 79, 142, 0,
-	0, 0, 164, 160, 0, 80, 0, 81, 88, 169,
-	152, 82, 83, 84, 85, 86, 87, 162, 163, 0,
-	0, 0, 0, 0, 166, 155, 156, 157, 158, 159,
-	154, 59, 0, 60, 0, 0, 146, 0, 0, 57,
-	61, 0, 148, 0, 0, 0, 0, 58, 173, 171,
-	177, 0, 170, 175, 172, 174, 0, 0, 62, 0,
-	63, 64, 65, 0, 0, 66, 0, 67, 0, 68,
-	69, 0, 0, 70, 71, 72, 73, 74, 75, 0,
-	0, 176, 76, 77, 0, 78, 0, 0, 0, 0,
+	0, 0, 196, 0, 0, 0, 0, 0, 104, 105,
+	0, 0, 106, 107, 108, 109, 110, 111, 112, 113,
+	0, 0, 114, 115, 0, 116, 117, 221, 222, 207,
+	0, 208, 188, 0, 94, 193, 0, 0, 0, 218,
+	214, 0, 118, 119, 120, 95, 0, 96, 103, 226,
+	203, 97, 98, 99, 100, 101, 102, 216, 217, 0,
+	0, 0, 0, 0, 223, 209, 210, 211, 0, 212,
+	213, 205, 74, 0, 75, 0, 0, 197, 0, 0,
+	71, 76, 0, 199, 0, 0, 0, 0, 73, 230,
+	228, 234, 0, 227, 232, 229, 231, 219, 0, 72,
+	0, 77, 0, 78, 79, 80, 0, 0, 81, 0,
+	82, 0, 83, 84, 0, 0, 85, 86, 87, 88,
+	89, 90, 0, 0, 233, 91, 92, 0, 93, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 79, 234, 0, 0, 0, 164, 160, 0,
-	80, 0, 81, 88, 169, 152, 82, 83, 84, 85,
-	86, 87, 162, 163, 0, 0, 0, 0, 0, 166,
-	155, 156, 157, 158, 159, 154, 59, 0, 60, 0,
-	0, 146, 0, 0, 57, 61, 0, 148, 0, 0,
-	0, 0, 58, 173, 171, 177, 0, 170, 175, 172,
-	174, 0, 0, 62, 0, 63, 64, 65, 0, 0,
-	66, 0, 67, 0, 68, 69, 0, 0, 70, 71,
-	72, 73, 74, 75, 0, 0, 176, 76, 77, 0,
-	78, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 104, 105, 0, 0, 106, 107,
+	108, 109, 110, 111, 112, 113, 0, 0, 114, 115,
+	0, 116, 117, 221, 222, 207, 0, 208, 0, 0,
+	94, 307, 0, 0, 0, 218, 214, 0, 118, 119,
+	120, 95, 0, 96, 103, 226, 203, 97, 98, 99,
+	100, 101, 102, 216, 217, 0, 0, 0, 0, 0,
+	223, 209, 210, 211, 0, 212, 213, 205, 74, 0,
+	75, 0, 0, 197, 0, 0, 71, 76, 0, 199,
+	0, 0, 0, 0, 73, 230, 228, 234, 0, 227,
+	232, 229, 231, 336, 0, 72, 0, 77, 0, 78,
+	79, 80, 0, 0, 81, 0, 82, 0, 83, 84,
+	0, 0, 85, 86, 87, 88, 89, 90, 0, 0,
+	233, 91, 92, 0, 93, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 79, 234, 0,
-	0, 0, 0, 0, 0, 80, 0, 81, 88, 169,
-	254, 82, 83, 84, 85, 86, 87, 59, 0, 60,
-	0, 0, 0, 0, 55, 57, 61, 0, 0, 0,
-	0, 0, 0, 58, 0, 0, 0, 330, 0, 0,
-	0, 0, 289, 0, 62, 0, 63, 64, 65, 0,
-	0, 66, 0, 67, 0, 68, 69, 0, 0, 70,
-	71, 72, 73, 74, 75, 0, 0, 0, 76, 77,
-	0, 78, 0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 79, 0,
-	0, 0, 0, 0, 0, 0, 80, 287, 288, 290,
-	0, 0, 82, 83, 84, 85, 86, 87, 59, 0,
-	60, 0, 0, 0, 0, 166, 57, 61, 0, 0,
-	0, 0, 0, 0, 58, 173, 171, 177, 0, 170,
-	175, 172, 174, 286, 0, 62, 0, 63, 64, 65,
-	0, 0, 253, 250, 67, 252, 68, 69, 0, 0,
-	70, 71, 72, 73, 74, 75, 0, 0, 176, 76,
-	77, 0, 78, 0, 0, 0, 0, 0, 0, 0,
+	104, 105, 0, 0, 106, 107, 108, 109, 110, 111,
+	112, 113, 0, 0, 114, 115, 0, 116, 117, 0,
+	0, 0, 0, 0, 0, 0, 94, 307, 0, 0,
+	0, 0, 0, 0, 118, 119, 120, 95, 0, 96,
+	103, 226, 335, 97, 98, 99, 100, 101, 102, 74,
+	0, 75, 0, 0, 0, 0, 69, 71, 76, 0,
+	0, 0, 0, 0, 0, 73, 230, 228, 234, 0,
+	227, 232, 229, 231, 336, 522, 72, 0, 77, 0,
+	78, 79, 80, 0, 0, 81, 0, 82, 0, 83,
+	84, 0, 0, 85, 86, 87, 88, 89, 90, 0,
+	0, 233, 91, 92, 0, 93, 0, 0, 0, 0,
+	425, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 0, 0, 79,
-	234, 0, 0, 0, 0, 0, 0, 80, 0, 81,
-	88, 169, 254, 82, 83, 84, 85, 86, 87, 59,
-	0, 60, 0, 0, 0, 0, 55, 57, 61, 0,
-	0, 0, 0, 0, 0, 58, 173, 171, 177, 0,
-	170, 175, 172, 174, 0, 0, 62, 0, 63, 64,
-	65, 0, 0, 66, 0, 67, 0, 68, 69, 0,
-	0, 70, 71, 72, 73, 74, 75, 0, 0, 176,
-	76, 77, 0, 78, 0, 0, 0, 0, 0, 0,
+	0, 104, 105, 0, 0, 106, 107, 108, 109, 110,
+	111, 112, 113, 0, 0, 114, 115, 0, 116, 117,
+	0, 0, 0, 0, 0, 0, 0, 94, 307, 0,
+	0, 0, 0, 0, 0, 118, 119, 120, 95, 0,
+	96, 103, 226, 335, 97, 98, 99, 100, 101, 102,
+	74, 0, 75, 0, 0, 0, 0, 69, 71, 76,
+	0, 0, 0, 0, 0, 0, 73, 0, 0, 0,
+	0, 423, 0, 11, 13, 12, 512, 72, 0, 77,
+	0, 78, 79, 80, 0, 0, 81, 0, 82, 0,
+	83, 84, 0, 0, 85, 86, 87, 88, 89, 90,
+	0, 0, 0, 91, 92, 15, 93, 0, 0, 0,
+	0, 0, 0, 0, 17, 18, 0, 0, 0, 8,
+	0, 9, 10, 19, 20, 0, 25, 21, 22, 0,
+	0, 0, 104, 105, 26, 0, 106, 107, 108, 109,
+	110, 111, 112, 113, 0, 0, 114, 115, 0, 116,
+	117, 0, 0, 0, 0, 0, 0, 0, 94, 0,
+	0, 0, 0, 0, 16, 0, 118, 119, 120, 95,
+	510, 511, 513, 14, 0, 97, 98, 99, 100, 101,
+	102, 0, 74, 0, 75, 0, 0, 0, 223, 0,
+	71, 76, 0, 24, 0, 0, 0, 0, 73, 0,
+	0, 0, 0, 0, 0, 0, 0, 509, 512, 72,
+	0, 77, 0, 78, 79, 80, 0, 0, 81, 0,
+	82, 0, 83, 84, 0, 0, 85, 86, 87, 88,
+	89, 90, 0, 0, 0, 91, 92, 0, 93, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	79, 234, 0, 0, 0, 0, 0, 0, 80, 0,
-	81, 88, 169, 254, 82, 83, 84, 85, 86, 87,
-	59, 0, 60, 0, 0, 0, 0, 55, 57, 61,
-	0, 0, 0, 0, 0, 0, 58, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 0, 62, 0, 63,
-	64, 65, 0, 0, 66, 0, 67, 0, 68, 69,
-	0, 0, 70, 71, 72, 73, 74, 75, 0, 0,
-	0, 76, 77, 0, 78, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 104, 105, 0, 0, 106, 107,
+	108, 109, 110, 111, 112, 113, 0, 0, 681, 115,
+	0, 116, 117, 0, 0, 0, 0, 0, 0, 0,
+	94, 0, 0, 0, 0, 0, 0, 0, 118, 119,
+	120, 95, 510, 511, 513, 0, 0, 97, 98, 99,
+	100, 101, 102, 74, 0, 75, 0, 0, 0, 0,
+	223, 71, 76, 0, 0, 0, 0, 0, 0, 73,
+	230, 228, 234, 0, 227, 232, 229, 231, 336, 509,
+	72, 0, 77, 0, 78, 79, 80, 0, 0, 81,
+	0, 82, 0, 83, 84, 0, 0, 85, 86, 87,
+	88, 89, 90, 0, 0, 233, 91, 92, 0, 93,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 215, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 79, 0, 0, 0, 59, 0, 60, 0, 80,
-	0, 81, 88, 57, 61, 82, 83, 84, 85, 86,
-	87, 58, 0, 0, 0, 0, 0, 0, 55, 0,
-	0, 0, 62, 113, 63, 64, 65, 0, 0, 66,
-	0, 67, 0, 68, 69, 0, 0, 70, 71, 72,
-	73, 74, 75, 0, 0, 0, 76, 77, 0, 78,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 104, 105, 0, 0, 106,
+	107, 108, 109, 110, 111, 112, 113, 0, 0, 114,
+	115, 0, 116, 117, 0, 0, 0, 0, 0, 0,
+	0, 94, 307, 0, 0, 0, 0, 0, 0, 118,
+	119, 120, 95, 0, 96, 103, 226, 335, 97, 98,
+	99, 100, 101, 102, 0, 74, 0, 75, 0, 0,
+	0, 69, 719, 71, 76, 0, 0, 0, 0, 0,
+	0, 73, 230, 228, 234, 0, 227, 232, 229, 231,
+	336, 0, 72, 0, 77, 0, 78, 79, 80, 0,
+	0, 334, 331, 82, 333, 83, 84, 0, 0, 85,
+	86, 87, 88, 89, 90, 0, 0, 233, 91, 92,
+	0, 93, 0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 79, 0, 0, 0,
-	59, 0, 60, 0, 80, 0, 81, 88, 57, 61,
-	82, 83, 84, 85, 86, 87, 58, 0, 0, 0,
-	0, 0, 0, 55, 0, 0, 0, 62, 0, 63,
-	64, 65, 0, 0, 66, 0, 67, 0, 68, 69,
-	0, 0, 70, 71, 72, 73, 74, 75, 0, 0,
-	0, 76, 77, 0, 78, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 104, 105, 0,
+	0, 106, 107, 108, 109, 110, 111, 112, 113, 0,
+	0, 114, 115, 0, 116, 117, 0, 0, 0, 0,
+	0, 0, 0, 94, 307, 0, 0, 0, 0, 0,
+	0, 118, 119, 120, 95, 0, 96, 103, 226, 335,
+	97, 98, 99, 100, 101, 102, 74, 0, 75, 0,
+	0, 0, 0, 69, 71, 76, 0, 0, 0, 0,
+	0, 0, 73, 230, 228, 234, 0, 227, 232, 229,
+	231, 336, 0, 72, 0, 77, 0, 78, 79, 80,
+	0, 0, 81, 0, 82, 0, 83, 84, 0, 0,
+	85, 86, 87, 88, 89, 90, 0, 0, 233, 91,
+	92, 0, 93, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 104, 105,
+	0, 0, 106, 107, 108, 109, 110, 111, 112, 113,
+	0, 0, 114, 115, 0, 116, 117, 0, 0, 0,
+	74, 0, 75, 0, 94, 307, 0, 0, 71, 76,
+	0, 0, 118, 119, 120, 95, 73, 96, 103, 226,
+	335, 97, 98, 99, 100, 101, 102, 72, 0, 77,
+	0, 78, 79, 80, 69, 0, 81, 0, 82, 0,
+	83, 84, 0, 0, 85, 86, 87, 88, 89, 90,
+	0, 0, 0, 91, 92, 0, 93, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 79, 0, 0, 0, 59, 0, 60, 0, 80,
-	0, 81, 88, 57, 61, 82, 83, 84, 85, 86,
-	87, 58, 0, 0, 0, 0, 0, 0, 55, 0,
-	0, 0, 62, 0, 63, 64, 65, 0, 0, 66,
-	0, 67, 0, 68, 69, 0, 0, 70, 71, 72,
-	73, 74, 75, 0, 0, 0, 76, 77, 0, 78,
+	0, 0, 0, 440, 0, 0, 0, 0, 0, 0,
+	0, 0, 104, 105, 0, 0, 106, 107, 108, 109,
+	110, 111, 112, 113, 0, 0, 114, 115, 0, 116,
+	117, 0, 0, 0, 74, 0, 75, 0, 94, 0,
+	0, 0, 71, 76, 0, 0, 118, 119, 120, 95,
+	73, 96, 103, 0, 0, 97, 98, 99, 100, 101,
+	102, 72, 0, 77, 0, 78, 79, 80, 69, 0,
+	81, 0, 82, 0, 83, 84, 0, 0, 85, 86,
+	87, 88, 89, 90, 0, 0, 0, 91, 92, 0,
+	93, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 686, 0, 0,
+	0, 0, 0, 0, 0, 0, 104, 105, 0, 0,
+	106, 107, 108, 109, 110, 111, 112, 113, 0, 0,
+	114, 115, 0, 116, 117, 0, 0, 0, 74, 0,
+	75, 0, 94, 0, 0, 0, 71, 76, 0, 0,
+	118, 119, 120, 95, 73, 96, 103, 0, 0, 97,
+	98, 99, 100, 101, 102, 72, 0, 77, 160, 78,
+	79, 80, 69, 0, 81, 0, 82, 0, 83, 84,
+	0, 0, 85, 86, 87, 88, 89, 90, 0, 0,
+	0, 91, 92, 0, 93, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	0, 0, 0, 0, 0, 0, 100, 0, 0, 0,
-	0, 0, 0, 0, 80, 0, 81, 88, 0, 0,
-	82, 83, 84, 85, 86, 87, 0, 0, 0, 0,
-	0, 0, 0, 55,
+	104, 105, 0, 0, 106, 107, 108, 109, 110, 111,
+	112, 113, 0, 0, 114, 115, 0, 116, 117, 0,
+	0, 0, 74, 0, 75, 0, 94, 0, 0, 0,
+	71, 76, 0, 0, 118, 119, 120, 95, 73, 96,
+	103, 0, 0, 97, 98, 99, 100, 101, 102, 72,
+	0, 77, 156, 78, 79, 80, 69, 0, 81, 0,
+	82, 0, 83, 84, 0, 0, 85, 86, 87, 88,
+	89, 90, 0, 0, 0, 91, 92, 0, 93, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 104, 105, 0, 0, 106, 107,
+	108, 109, 110, 111, 112, 113, 0, 0, 114, 115,
+	0, 116, 117, 0, 0, 0, 74, 0, 75, 0,
+	94, 0, 0, 0, 71, 76, 0, 0, 118, 119,
+	120, 95, 73, 96, 103, 0, 0, 97, 98, 99,
+	100, 101, 102, 72, 0, 77, 0, 78, 79, 80,
+	69, 0, 81, 0, 82, 0, 83, 84, 0, 0,
+	85, 86, 87, 88, 89, 90, 0, 0, 0, 91,
+	92, 0, 93, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 104, 105,
+	0, 0, 106, 107, 108, 109, 110, 111, 112, 113,
+	0, 0, 114, 115, 0, 116, 117, 0, 0, 0,
+	74, 0, 75, 0, 94, 0, 0, 0, 71, 76,
+	0, 0, 118, 119, 120, 95, 73, 96, 103, 0,
+	0, 97, 98, 99, 100, 101, 102, 72, 0, 77,
+	0, 78, 79, 80, 69, 0, 81, 0, 82, 0,
+	83, 84, 0, 0, 85, 86, 87, 88, 89, 90,
+	0, 0, 0, 91, 92, 0, 93, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 104, 105, 0, 0, 106, 107, 108, 109,
+	110, 111, 112, 113, 0, 0, 114, 115, 0, 116,
+	117, 0, 0, 0, 74, 0, 75, 0, 152, 0,
+	0, 0, 71, 76, 0, 0, 118, 119, 120, 95,
+	73, 96, 103, 0, 0, 97, 98, 99, 100, 101,
+	102, 72, 0, 77, 0, 78, 79, 80, 69, 0,
+	81, 0, 82, 0, 83, 84, 0, 0, 85, 86,
+	87, 88, 89, 90, 0, 0, 0, 91, 92, 0,
+	93, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 104, 105, 0, 0,
+	106, 107, 108, 109, 110, 111, 112, 113, 0, 0,
+	114, 115, 0, 116, 117, 0, 0, 0, 74, 0,
+	75, 0, 138, 0, 0, 0, 71, 76, 0, 0,
+	118, 119, 120, 95, 73, 96, 103, 0, 0, 97,
+	98, 99, 100, 101, 102, 72, 0, 77, 0, 78,
+	79, 80, 69, 0, 81, 0, 82, 0, 83, 84,
+	0, 0, 85, 86, 87, 88, 89, 90, 0, 0,
+	0, 91, 92, 0, 93, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	104, 105, 0, 0, 106, 107, 108, 109, 110, 111,
+	112, 113, 0, 0, 114, 115, 0, 116, 117, 0,
+	0, 0, 0, 0, 0, 0, 135, 0, 0, 0,
+	0, 0, 0, 0, 118, 119, 120, 95, 0, 96,
+	103, 0, 0, 97, 98, 99, 100, 101, 102, 0,
+	0, 0, 0, 0, 0, 0, 69,
 }
 
 var yyPact = [...]int16{
-	298, -1000, -1000, -9, -1000, -1000, -1000, 308, -1000, -1000,
-	420, 184, 378, 423, 421, 421, 302, 299, 278, 1665,
-	239, 217, 280, -1000, 298, -1000, 82, 1750, 151, 375,
-	98, -1000, 97, 442, 1665, 1580, 95, 1665, 92, 365,
-	312, 29, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 363,
-	1665, 1665, 1665, 294, -1000, -1000, -1000, -1000, -1000, -1000,
+	1769, -1000, -1000, 15, -1000, -1000, -1000, -1000, 448, -1000,
+	-1000, 412, 214, 380, 345, 587, 543, 544, 544, 436,
+	435, 399, 2791, 344, 260, -24, 62, -1000, 1769, -1000,
+	120, 3103, 2999, 105, 199, 562, 560, 113, -1000, 112,
+	588, 2895, 2791, 110, 2687, 559, 109, 2583, 547, 2791,
+	2791, 108, 482, 542, 451, 42, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, 541, 2791, 2791, 2791, 415, 56, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
 	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 237,
-	-1000, -1000, 91, -1000, 314, 746, -1000, -1000, 208, -1000,
-	205, -13, -1000, 362, 188, 151, 445, -1000, -1000, 397,
-	631, 631, -1000, 1665, 37, -1000, 413, 415, 460, -1000,
-	421, 458, -17, -17, 263, 79, 163, -1000, -1000, 87,
-	275, -1000, 28, 1495, 77, 112, -1000, 861, -1000, 104,
-	-1000, 11, -18, -1000, -1000, 861, 976, -1000, 861, 137,
-	-1000, -1000, -22, 32, -24, -1000, -1000, -1000, -1000, -1000,
-	-25, -1000, -1000, -1000, -1000, 36, -30, -1000, -1000, -1000,
-	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, 172, 168,
-	1293, 1665, 164, 361, 409, -1000, 631, 631, -1000, 861,
-	-1000, -1000, -36, 1394, 339, 335, 332, 403, 1665, -1000,
-	1665, 177, 1394, 177, 462, 861, 74, -1000, 101, -1000,
-	-1000, 1192, 861, -1000, -1000, 1665, 861, 861, -1000, 976,
-	150, 976, 198, 976, 976, 976, -1000, 976, 976, 976,
-	163, 226, -1000, -1000, -1000, -50, 463, 176, 12, 48,
-	1091, 861, 1394, 861, 86, 1665, -59, -1000, -1000, -1000,
-	327, 463, 861, 83, -1000, -37, -1000, 1665, 45, -1000,
-	-1000, -1000, 1394, -1000, 1394, 1665, 1394, 1394, 81, 44,
-	346, 343, 360, -47, -1000, -61, -1000, -1000, 253, 373,
-	-1000, 462, 79, 861, 462, 442, 273, -38, -39, -40,
-	-43, 1495, 1495, -1000, 112, -1000, 5, -1000, 145, 31,
-	976, -44, 5, 11, 11, -1000, -1000, -1000, -52, 227,
-	861, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
-	-1000, 274, -1000, -1000, -1000, -1000, -1000, -1000, 43, -1000,
-	-53, -54, 244, -1000, -55, 27, -1000, -1000, -45, -1000,
-	1293, 76, -92, -1000, 321, 1394, -46, 450, -62, -1000,
-	-1000, 342, -1000, -1000, 450, 456, 414, -1000, 288, 24,
-	-1000, 861, 1394, -1000, 250, 861, 354, 253, -1000, -1000,
-	117, 1495, -47, -56, 391, -57, -58, 73, -60, -1000,
-	-1000, -1000, 976, 5, 516, -1000, 199, 861, 861, 219,
-	861, -1000, -1000, -1000, 463, -1000, 861, 1293, -1000, -1000,
-	-1000, 1394, 147, 53, 51, 861, -63, 1394, -1000, -1000,
-	-1000, -1000, -1000, 1394, -1000, 72, 70, 285, -47, -64,
-	-1000, -1000, 861, -1000, 76, 250, 263, -1000, 117, 271,
-	-1000, -1000, -73, 1495, 58, 1495, 1495, -65, 1495, 5,
-	-66, -75, 217, -1000, 215, -1000, 861, -83, -86, -1000,
-	-76, -80, 153, -1000, 144, -103, -87, -1000, -1000, -81,
-	-1000, -1000, -1000, 277, -1000, -1000, -1000, -1000, -1000, 261,
-	-1000, 1192, -1000, -1000, -100, -1000, -1000, -1000, -1000, -1000,
-	-1000, 861, -1000, -1000, -1000, -1000, -1000, 329, -1000, -1000,
-	-1000, -1000, -1000, -1000, 267, 259, 462, 1495, -1000, -1000,
-	324, 246, 861, 1394, 351, -1000, -1000, 253, 257, -1000,
-	21, -1000, 861, 250, 861, 1394, -1000, -1000, -1, 243,
-	-1000, 861, -1000, -1000, -1000, 243, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, 342, -1000, -1000, 106, -1000, 457, 35, -1000, 1191,
+	403, -1000, -1000, 245, -1000, 242, 10, -1000, 225, 352,
+	222, 590, 539, 221, 199, 199, 611, -1000, -1000, 582,
+	899, 899, 201, -1000, -1000, 538, 2791, 55, 532, -1000,
+	2791, 53, 530, -1000, 519, 610, 2791, 2791, 618, -1000,
+	544, 616, 9, 9, 388, 98, 2791, 203, -1000, -1000,
+	104, -24, -31, 41, -1000, 124, 121, -1000, 1191, -1000,
+	1, -1000, 16, 8, -1000, -1000, 1191, 1337, -1000, 1191,
+	156, -1000, -1000, 5, 48, 4, 3, 2, -1, -1000,
+	-1000, -1000, -1000, -1000, -2, -1000, -1000, -1000, -1000, -3,
+	52, -1000, -1000, -7, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, -1000, 1045, -1000, 200, 197, 2140,
+	191, 406, 190, 429, 2791, 186, 528, 526, 600, -1000,
+	899, 899, -1000, 1191, -1000, -1000, 2791, 2791, -8, 2271,
+	2791, -9, 2271, 2791, 475, 500, 496, 598, 183, 51,
+	2791, -1000, 2791, 251, 2271, 251, 621, 1191, 107, -1000,
+	116, -1000, -1000, -1000, -1000, -1000, 1191, 1191, 1191, -1000,
+	1337, 220, 1337, 198, 1337, 1337, 253, 1337, 1337, -1000,
+	1337, 1337, 1337, 203, 339, -1000, -1000, -1000, -32, 561,
+	149, 45, 74, 1614, 72, 2271, 2271, 1191, 1191, 2271,
+	1191, 382, -1000, 39, 2375, 102, 2791, -55, -1000, -1000,
+	-1000, 468, 561, 1191, 101, -1000, -1000, 2791, -1000, 100,
+	525, -1000, -1000, -1000, -10, -1000, 2791, 2791, 73, -1000,
+	-1000, -1000, -1000, -1000, 2271, -1000, -11, 2271, -1000, -12,
+	2271, 2791, 2271, 2271, 99, 68, 93, 2271, 491, 481,
+	524, -24, -1000, -56, -1000, -1000, 353, 545, -1000, 621,
+	98, 1191, -1000, 121, -1000, 26, -1000, 226, 398, 24,
+	1337, -13, 26, 26, -14, 16, 16, -1000, -1000, -1000,
+	-35, 334, 1191, -1000, -1000, -1000, -1000, -1000, -1000, -1000,
+	-1000, -1000, -1000, -1000, 397, -1000, -1000, -1000, -1000, -1000,
+	-1000, 64, -1000, -36, -37, 2271, -38, -1000, -1000, 38,
+	37, 351, 36, -1000, -39, 1745, 353, 1191, -1000, -1000,
+	2791, -1000, -15, -1000, 2140, 1483, -106, -1000, 463, 350,
+	589, 2791, 2271, -16, -17, 603, -57, 2271, -60, 2271,
+	-1000, -1000, 478, -1000, -1000, 603, -1000, -1000, -1000, 182,
+	614, 613, -1000, 413, 35, 2271, -1000, 361, 1191, 518,
+	353, -1000, -1000, -1000, 394, 1337, 1337, 26, 753, 1191,
+	-1000, 324, 1191, 1191, 332, 1191, -1000, -1000, -1000, -71,
+	-1000, 228, 72, 86, 561, 1191, -1000, 621, 588, 286,
+	-18, -21, -22, -23, 2375, 361, 2375, -1000, 2140, -1000,
+	-1000, -1000, 2271, 150, 82, 80, 1191, 406, 429, 456,
+	-72, 2271, 2271, -1000, -1000, -1000, -1000, -1000, -40, -1000,
+	-41, 2271, -1000, 93, 97, 92, 411, -1000, -1000, 1191,
+	-1000, 1483, 361, 1337, 26, 26, -42, -74, 260, 34,
+	-1000, 330, -1000, 1191, -43, 2271, -1000, 377, -44, -77,
+	-45, -47, 84, 2375, -24, -48, 578, -49, -50, 91,
+	-52, -1000, -1000, -1000, -78, -79, 178, 153, -110, -54,
+	-1000, -1000, 516, 282, -1000, -89, -58, -1000, -1000, -1000,
+	-1000, -1000, -1000, 409, -1000, -1000, -1000, 26, -1000, -1000,
+	1191, 1191, -1000, -1000, -1000, -25, -1000, -1000, 79, -1000,
+	-1000, 388, -1000, 84, 392, 128, 269, -1000, -1000, -91,
+	2375, 88, 2375, 2375, -61, 2375, -1000, -1000, 171, -1000,
+	159, 349, -1000, -1000, 2791, 319, 302, -1000, -1000, 33,
+	-1000, 359, -62, 370, -1000, 1877, 389, -1000, -27, 2479,
+	-1000, -63, -1000, -1000, -1000, -1000, 470, -1000, -1000, -26,
+	454, 426, -1000, 258, 1191, 368, -1000, 390, 367, 621,
+	509, -27, 1745, 203, -1000, -28, 2791, 2375, -1000, 465,
+	1191, 279, -1000, -1000, 401, 32, 2271, 358, 1191, 2008,
+	331, 1191, 621, -76, 2271, -29, -1000, -1000, -82, 310,
+	-1000, 1191, 348, 353, 364, -1000, 31, -1000, -1000, -1000,
+	1191, -105, -1000, -1000, 2375, -92, 2271, 144, 426, -84,
+	-99, -1000, -1000, 361, 1191, 2008, -1000, 2271, -1000, -1000,
+	-93, -1000, -1000, -1000, -1000, 608, 30, 348, -1000, -94,
+	-1000, 320, 305, 1191, 301, -1000, 453, 317, -1000, 348,
+	-1000, 297, -1000, 285, 1191, -1000, -1000, 301, -1000, -1000,
+	-1000, 312, -1000, 387, -1000, -1000, -1000, 298, -1000,
 }
 
 var yyPgo = [...]int16{
-	0, 569, 432, 562, 561, 558, 18, 556, 26, 12,
-	143, 21, 554, 17, 11, 16, 19, 553, 7, 549,
-	547, 4, 546, 541, 9, 29, 382, 24, 536, 535,
-	36, 534, 15, 533, 532, 531, 23, 13, 0, 528,
-	10, 526, 525, 524, 519, 33, 518, 514, 34, 28,
-	44, 35, 512, 511, 8, 3, 510, 509, 507, 506,
-	6, 505, 501, 1, 5, 227, 498, 497, 496, 495,
-	25, 494, 493, 20, 480, 153, 476, 475, 14, 471,
-	470, 2, 27, 58, 469,
+	0, 713, 597, 711, 710, 11, 21, 709, 27, 37,
+	9, 53, 19, 708, 17, 50, 30, 38, 707, 18,
+	704, 697, 35, 695, 6, 694, 693, 15, 41, 13,
+	503, 26, 692, 687, 42, 686, 16, 685, 12, 682,
+	31, 28, 0, 3, 23, 681, 680, 679, 678, 47,
+	677, 676, 59, 40, 48, 39, 673, 672, 670, 7,
+	14, 5, 667, 666, 665, 664, 663, 662, 660, 8,
+	659, 658, 4, 2, 20, 215, 656, 655, 654, 653,
+	652, 651, 650, 36, 649, 648, 32, 647, 54, 643,
+	636, 29, 25, 633, 631, 1, 45, 10, 22, 630,
+	629, 628,
 }
 
 var yyR1 = [...]int8{
-	0, 1, 2, 2, 84, 84, 3, 3, 3, 4,
+	0, 1, 2, 2, 99, 99, 3, 3, 3, 3,
+	7, 4, 4, 4, 4, 4, 4, 4, 4, 4,
+	4, 4, 4, 4, 4, 4, 4, 4, 4, 4,
 	4, 4, 4, 4, 4, 4, 4, 4, 4, 4,
 	4, 4, 4, 4, 4, 4, 4, 4, 4, 4,
-	4, 4, 4, 4, 4, 75, 75, 75, 74, 74,
-	74, 74, 74, 74, 74, 73, 73, 73, 73, 65,
-	65, 5, 5, 5, 5, 25, 25, 72, 72, 71,
-	71, 70, 13, 13, 14, 12, 12, 16, 16, 15,
-	15, 17, 17, 17, 17, 17, 17, 17, 17, 17,
-	17, 78, 78, 78, 78, 78, 78, 78, 78, 18,
-	37, 37, 36, 36, 36, 8, 69, 69, 59, 59,
-	59, 66, 66, 67, 67, 67, 6, 6, 6, 6,
-	6, 6, 6, 6, 7, 7, 23, 23, 22, 22,
-	57, 57, 58, 58, 19, 19, 19, 19, 20, 20,
-	21, 21, 82, 83, 83, 9, 9, 11, 11, 10,
-	10, 80, 80, 80, 80, 80, 80, 80, 80, 80,
-	80, 80, 81, 81, 79, 79, 79, 79, 79, 79,
-	79, 79, 79, 79, 79, 79, 79, 79, 79, 79,
-	79, 79, 79, 79, 79, 79, 79, 79, 79, 79,
-	79, 79, 79, 79, 79, 79, 24, 24, 24, 24,
-	24, 24, 24, 24, 24, 26, 27, 28, 28, 28,
-	29, 29, 29, 30, 30, 31, 31, 32, 32, 33,
-	34, 34, 40, 40, 53, 53, 41, 41, 54, 54,
-	55, 55, 62, 62, 64, 64, 61, 61, 63, 63,
-	63, 60, 60, 60, 35, 35, 39, 39, 56, 76,
-	76, 43, 43, 38, 44, 44, 45, 45, 49, 49,
-	46, 46, 46, 46, 46, 46, 46, 47, 47, 47,
-	47, 47, 48, 48, 48, 50, 50, 50, 50, 51,
-	51, 52, 52, 42, 42, 42, 42, 68, 68, 77,
-	77, 77, 77, 77, 77,
+	4, 4, 88, 88, 88, 87, 87, 87, 87, 87,
+	87, 87, 86, 86, 86, 86, 75, 75, 76, 76,
+	76, 5, 5, 5, 5, 28, 28, 92, 92, 92,
+	85, 85, 84, 84, 83, 14, 14, 15, 13, 13,
+	17, 17, 16, 16, 18, 18, 18, 18, 18, 18,
+	18, 18, 18, 18, 18, 91, 91, 91, 91, 91,
+	91, 91, 91, 91, 19, 41, 41, 40, 40, 40,
+	9, 63, 63, 80, 80, 68, 68, 68, 77, 77,
+	78, 78, 78, 6, 6, 6, 6, 6, 6, 6,
+	6, 8, 8, 65, 65, 26, 26, 25, 25, 66,
+	66, 67, 67, 20, 20, 20, 20, 20, 20, 20,
+	20, 20, 21, 21, 22, 22, 23, 23, 24, 24,
+	96, 98, 98, 97, 97, 10, 10, 12, 12, 11,
+	11, 94, 94, 94, 94, 94, 94, 94, 94, 94,
+	94, 94, 94, 95, 95, 93, 93, 93, 93, 93,
+	93, 93, 93, 93, 93, 93, 93, 93, 93, 93,
+	93, 93, 93, 93, 93, 93, 93, 93, 93, 93,
+	93, 93, 93, 93, 93, 93, 93, 93, 93, 93,
+	93, 93, 93, 93, 93, 93, 93, 93, 93, 93,
+	93, 93, 93, 93, 93, 27, 27, 27, 27, 27,
+	27, 27, 27, 27, 27, 27, 30, 30, 31, 32,
+	32, 32, 33, 33, 33, 34, 34, 35, 35, 36,
+	36, 37, 37, 37, 37, 37, 29, 38, 38, 44,
+	44, 57, 57, 58, 58, 59, 59, 45, 45, 60,
+	60, 61, 61, 82, 82, 64, 64, 64, 81, 81,
+	100, 100, 101, 101, 71, 71, 74, 74, 70, 70,
+	72, 72, 72, 73, 73, 73, 69, 69, 69, 39,
+	39, 43, 43, 62, 89, 89, 47, 47, 42, 48,
+	48, 49, 49, 53, 53, 50, 50, 50, 50, 50,
+	50, 50, 50, 50, 50, 50, 51, 51, 51, 51,
+	51, 52, 52, 52, 54, 54, 54, 54, 55, 55,
+	56, 56, 46, 46, 46, 46, 79, 79, 90, 90,
+	90, 90, 90, 90,
 }
 
 var yyR2 = [...]int8{
-	0, 1, 2, 3, 0, 1, 1, 1, 1, 2,
-	1, 1, 1, 6, 3, 2, 3, 3, 9, 6,
-	3, 8, 9, 7, 5, 6, 6, 8, 6, 6,
-	7, 7, 3, 8, 8, 2, 1, 3, 1, 1,
-	1, 1, 1, 1, 1, 0, 1, 1, 1, 0,
-	3, 6, 5, 7, 8, 2, 1, 0, 4, 1,
-	3, 3, 1, 3, 3, 1, 3, 0, 1, 1,
-	3, 1, 1, 1, 1, 1, 6, 1, 1, 1,
-	1, 1, 1, 1, 1, 1, 1, 1, 1, 4,
-	1, 3, 1, 1, 3, 6, 0, 2, 0, 3,
-	3, 0, 1, 0, 1, 2, 1, 4, 2, 2,
-	3, 2, 2, 4, 13, 3, 0, 1, 0, 1,
-	1, 1, 2, 4, 1, 2, 4, 4, 2, 3,
-	1, 3, 1, 1, 1, 1, 3, 1, 3, 0,
+	0, 1, 2, 3, 0, 1, 1, 1, 1, 1,
+	2, 2, 1, 1, 1, 6, 3, 2, 3, 3,
+	9, 6, 5, 3, 8, 5, 3, 14, 11, 5,
+	8, 10, 9, 7, 8, 5, 7, 8, 5, 3,
+	6, 6, 8, 6, 6, 6, 8, 7, 7, 3,
+	8, 8, 2, 1, 3, 1, 1, 1, 1, 1,
+	1, 1, 0, 1, 1, 1, 0, 3, 0, 2,
+	3, 6, 5, 7, 8, 2, 1, 1, 1, 1,
+	0, 4, 1, 3, 3, 1, 3, 3, 1, 3,
+	0, 1, 1, 3, 1, 1, 1, 1, 1, 6,
+	1, 1, 1, 1, 6, 1, 1, 1, 1, 1,
+	1, 1, 1, 1, 4, 1, 3, 1, 1, 3,
+	7, 0, 7, 0, 2, 0, 3, 3, 0, 1,
+	0, 1, 2, 1, 4, 2, 2, 3, 2, 2,
+	4, 17, 7, 0, 1, 0, 1, 0, 1, 1,
+	1, 2, 4, 1, 2, 4, 4, 5, 12, 6,
+	6, 8, 1, 1, 1, 1, 2, 3, 1, 3,
+	1, 1, 1, 1, 1, 1, 3, 1, 3, 0,
 	3, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
 	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
-	1, 1, 1, 1, 1, 1, 3, 4, 4, 4,
-	4, 4, 4, 2, 6, 1, 2, 0, 2, 2,
-	0, 2, 2, 2, 1, 0, 1, 1, 2, 6,
-	0, 1, 0, 2, 0, 3, 0, 2, 0, 2,
-	0, 2, 0, 3, 0, 4, 2, 4, 0, 1,
-	1, 0, 1, 2, 2, 4, 0, 1, 5, 4,
-	5, 0, 2, 1, 3, 1, 3, 1, 2, 1,
-	3, 3, 4, 5, 4, 3, 1, 4, 6, 6,
-	1, 1, 3, 3, 1, 3, 3, 3, 1, 2,
-	1, 3, 1, 1, 1, 3, 6, 0, 1, 1,
-	1, 1, 1, 1, 1,
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+	1, 1, 1, 1, 1, 1, 1, 1, 1, 1,
+	1, 1, 1, 1, 1, 3, 5, 8, 9, 4,
+	4, 4, 4, 4, 2, 6, 1, 3, 2, 0,
+	2, 2, 0, 2, 2, 2, 1, 0, 1, 1,
+	2, 6, 8, 5, 2, 5, 5, 0, 1, 0,
+	2, 0, 3, 1, 3, 1, 1, 0, 2, 0,
+	2, 0, 2, 0, 2, 0, 5, 6, 0, 2,
+	1, 1, 1, 1, 0, 3, 0, 4, 3, 5,
+	0, 1, 1, 0, 2, 2, 0, 1, 2, 2,
+	4, 0, 1, 5, 4, 5, 0, 2, 1, 3,
+	1, 3, 1, 2, 1, 3, 3, 4, 5, 6,
+	5, 4, 3, 3, 12, 1, 4, 6, 6, 1,
+	1, 3, 3, 1, 3, 3, 3, 1, 2, 1,
+	3, 1, 1, 1, 3, 6, 0, 1, 1, 1,
+	1, 1, 1, 1,
 }
 
 var yyChk = [...]int16{
-	-1000, -1, -2, -3, -4, -5, -6, 48, 50, 51,
-	4, 6, 5, 34, 43, 44, 52, 53, 56, 57,
-	-7, 94, 63, -84, 130, 49, 7, 30, 32, 31,
-	8, 113, 7, 14, 30, 32, 8, 30, 8, -75,
-	78, -74, 63, 4, 52, 57, 56, 5, 34, -75,
-	54, 54, 65, -26, -81, 113, -79, 13, 21, 5,
-	7, 14, 32, 34, 35, 36, 39, 41, 43, 44,
-	47, 48, 49, 50, 51, 52, 56, 57, 59, 86,
-	94, 96, 100, 101, 102, 103, 104, 105, 97, 77,
-	95, 96, 30, 97, 45, -22, 64, -2, 86, 113,
-	86, -82, -81, -65, 86, 32, 113, 113, -27, -28,
-	16, 17, -81, 33, -82, 113, -82, 113, 33, 47,
-	123, 33, -26, -26, -26, 58, -23, 78, 113, 46,
-	-57, 126, -58, -38, -44, -45, -49, 84, -46, -48,
-	-47, -50, 87, -56, -51, 79, 125, -52, 131, -42,
-	-19, -17, 99, -21, 119, 114, 115, 116, 117, 118,
-	92, -18, 106, 107, 91, -83, 113, -81, -80, 98,
-	26, 23, 28, 22, 29, 27, 55, 24, 84, 84,
-	131, 33, 84, -65, 9, -29, 19, 18, -30, 20,
-	-38, -30, -82, 121, 35, 36, 5, 9, 7, -75,
-	7, -10, 131, -10, -40, 68, -71, -70, 113, -6,
-	113, 65, 123, -60, -81, 76, 110, 109, -49, 111,
-	89, 98, -68, 112, 124, 125, 84, 126, 127, 128,
-	131, -39, -38, -51, 87, -38, 93, 131, -20, 122,
-	131, 131, 121, 131, 87, 87, -37, -36, -8, -35,
-	40, -83, 42, 39, 99, -82, 87, 33, 10, -30,
-	-30, -38, 131, -83, 38, 37, 38, 38, 39, 10,
-	-81, -81, -25, 55, -6, -9, -83, -25, -64, 6,
-	-38, -40, 123, 111, -24, -26, 131, 95, 96, 30,
-	97, -18, -38, -81, -45, -49, -48, 91, 84, -48,
-	85, 88, -48, -50, -50, -51, -51, -51, -6, -76,
-	80, 132, -78, 22, 23, 24, 25, 26, 27, 28,
-	29, -77, 100, 101, 102, 103, 104, 105, 122, 116,
-	126, -21, -38, -83, -16, -15, -38, 113, -82, 132,
-	123, 41, -78, -38, 113, 131, -82, 116, -9, -8,
-	-82, -83, -83, 113, 116, 37, 37, -72, 33, -13,
-	-14, 131, 123, 132, -54, 71, 32, -64, -70, -38,
-	-64, -27, 55, -6, 15, 131, 131, 131, 131, -60,
-	-60, 91, 109, -48, 131, 132, -43, 80, 82, -38,
-	65, 116, 132, 132, 76, 132, 123, 131, -36, -11,
-	-83, 131, -59, 133, 131, 42, -9, 131, -73, 11,
-	12, 13, 132, 37, -73, 8, 8, 59, 123, -16,
-	-83, -55, 72, -38, 33, -54, -31, -32, -33, -34,
-	108, -60, -13, 132, 21, 132, 132, 113, 132, -48,
-	-6, -15, 94, 83, -38, -38, 81, -38, -78, -38,
-	-37, -9, -67, 91, 84, 114, 114, -38, 132, -9,
-	-83, 113, 113, 60, -14, 132, -38, -11, -55, -40,
-	-32, 66, 132, -60, 113, -60, -60, 132, -60, 132,
-	132, 81, -38, 132, 132, 132, 132, -66, 90, 91,
-	134, 132, 132, 61, -53, 69, -24, 132, -38, -69,
-	40, -41, 67, 70, -64, -60, 41, -62, 73, -38,
-	-12, -21, 33, -54, 70, 123, -38, -55, -61, -38,
-	-21, 123, -63, 74, 75, -38, -63,
+	-1000, -1, -2, -3, -4, -5, -6, -7, 50, 52,
+	53, 4, 6, 5, 104, 36, 95, 45, 46, 54,
+	55, 58, 59, -8, 124, 57, 65, -99, 161, 51,
+	7, 31, 32, 97, 34, 33, 102, 8, 143, 7,
+	14, 31, 32, 97, 34, 102, 8, 34, 102, 31,
+	31, 8, 35, -88, 80, -87, 65, 4, 54, 59,
+	58, 5, 36, -88, 56, 56, 67, -30, -95, 143,
+	-93, 13, 32, 21, 5, 7, 14, 34, 36, 37,
+	38, 41, 43, 45, 46, 49, 50, 51, 52, 53,
+	54, 58, 59, 61, 113, 124, 126, 130, 131, 132,
+	133, 134, 135, 127, 87, 88, 91, 92, 93, 94,
+	95, 96, 97, 98, 101, 102, 104, 105, 121, 122,
+	123, 79, 125, 126, 31, 127, 47, -14, -15, 162,
+	-65, 147, -2, 113, 143, 113, -96, -95, 113, -96,
+	113, 143, -75, 113, 34, 34, 143, 143, -31, -32,
+	16, 17, 113, -95, -96, 143, 35, -96, 34, 143,
+	35, -96, 34, -96, -96, 143, 31, 40, 35, 49,
+	154, 35, -30, -30, -30, 60, 152, -26, 80, 143,
+	48, 154, -17, -16, -42, -48, -49, -53, 111, -50,
+	-52, -51, -54, 114, -62, -55, 81, 156, -56, 162,
+	-46, -20, -18, 129, -24, 150, -21, 108, 110, 144,
+	145, 146, 148, 149, 119, -19, 136, 137, 118, 30,
+	-97, 106, 107, 143, -95, -94, 128, 26, 23, 28,
+	22, 29, 27, 57, 24, -25, 66, 111, 111, 162,
+	111, 78, 111, 17, 35, 111, -75, -75, 9, -33,
+	19, 18, -34, 20, -42, -34, 114, 35, -96, 152,
+	35, -96, 152, 35, 37, 38, 5, 9, -96, -96,
+	7, -88, 7, -11, 162, -11, -44, 70, -84, -83,
+	143, -95, -6, 143, -15, 163, 154, 140, 139, -53,
+	141, 116, 128, -79, 142, 103, 109, 155, 156, 111,
+	157, 158, 159, 162, -43, -42, -55, 114, -42, 120,
+	162, -23, 153, 162, 162, 162, 162, 162, 162, 152,
+	162, -66, 157, -67, -42, 114, 114, -41, -40, -9,
+	-39, 42, -97, 44, 41, 129, 30, 114, -8, 114,
+	-92, 54, 59, 58, -96, 114, 35, 35, 10, -34,
+	-34, -42, -95, -96, 162, -97, -96, 162, -97, -96,
+	40, 39, 40, 40, 41, 10, 116, 152, -95, -95,
+	-28, 57, -6, -10, -97, -28, -74, 6, -42, -44,
+	154, 141, -42, -49, -53, -52, 118, 111, 66, -52,
+	112, 115, -52, -52, 105, -54, -54, -55, -55, -55,
+	-6, -89, 82, 163, -91, 22, 23, 24, 25, 26,
+	27, 28, 29, 30, -90, 130, 131, 132, 133, 134,
+	135, 153, 146, 157, -24, 66, -22, 145, 144, -24,
+	-24, -42, -42, -97, -17, 67, -44, 154, -69, -95,
+	78, 143, -96, 163, 154, 43, -91, -42, 143, -96,
+	143, 35, 162, -96, -96, 146, -10, 162, -10, 162,
+	-9, -96, -97, -97, 143, 146, -98, 146, 118, -97,
+	39, 39, -85, 35, -14, 154, 163, -60, 73, 34,
+	-74, -83, -42, 118, 66, 67, 139, -52, 162, 162,
+	163, -47, 82, 84, -42, 67, 146, 163, 163, -13,
+	-24, 163, 154, 154, 78, 154, 163, -27, -30, 162,
+	125, 126, 31, 127, -19, -60, -42, -95, 162, -40,
+	-12, -97, 162, -68, 164, 162, 44, 78, 17, -96,
+	-10, 162, 162, -86, 11, 12, 13, 163, -97, 163,
+	-97, 39, -86, 116, 8, 8, 61, -97, -61, 74,
+	-42, 35, -60, 67, -52, -52, -6, -16, 124, -42,
+	85, -42, -42, 83, -42, 154, 163, 109, -22, 144,
+	-91, -42, -74, -31, 57, -6, 15, 162, 162, 162,
+	162, -69, -61, -69, -41, -10, -63, 121, 144, 144,
+	-42, -8, -92, 48, 163, -10, -97, 163, 163, -97,
+	-98, 143, 143, 62, -42, -12, -61, -52, 163, 163,
+	154, 83, -42, 163, -24, 71, 163, 163, 154, 163,
+	163, -35, -36, -37, -38, 99, 154, 138, -69, -14,
+	163, 21, 163, 163, 143, 163, 163, 163, -78, 118,
+	111, 122, 165, 163, 35, 98, 163, 163, 63, -42,
+	-42, 162, 144, -44, -36, 68, -38, -29, 101, 163,
+	-69, 143, -69, -69, 163, -69, -77, 117, 118, 78,
+	-96, 89, -76, 93, 154, 75, 163, -57, 71, -27,
+	-29, 101, 68, 162, -69, -95, 78, 163, -80, 42,
+	162, 48, -5, 66, 111, -42, 72, -45, 69, 72,
+	-74, 35, -27, -6, 162, -95, -69, 43, -42, 98,
+	66, 154, -24, -71, 75, -42, -58, -59, -24, 144,
+	35, 100, -42, -74, 163, -10, 162, 163, 89, -42,
+	-72, 76, 77, -60, 72, 154, -42, 162, -69, 163,
+	-10, 123, -5, 163, 163, -61, -70, -42, -59, -10,
+	163, -82, 9, 154, -72, 163, -64, 86, 92, -42,
+	-73, 93, -81, 48, -100, 87, 88, -72, 87, 94,
+	96, -43, -73, -101, 89, 90, 91, 9, 92,
 }
 
 var yyDef = [...]int16{
-	0, -2, 1, 4, 6, 7, 8, 10, 11, 12,
+	0, -2, 1, 4, 6, 7, 8, 9, 12, 13,
+	14, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 133, 0, 0, 143, 2, 5, 11,
+	0, 0, 0, 0, 66, 0, 0, 0, 17, 0,
+	259, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 0, 0, 53, 55, 56, 57, 58,
+	59, 60, 61, 0, 0, 0, 0, 0, 256, 193,
+	194, 195, 196, 197, 198, 199, 200, 201, 202, 203,
+	204, 205, 206, 207, 208, 209, 210, 211, 212, 213,
+	214, 215, 216, 217, 218, 219, 220, 221, 222, 223,
+	224, 225, 226, 227, 228, 229, 230, 231, 232, 233,
+	234, 235, 236, 237, 238, 239, 240, 241, 242, 243,
+	244, 145, 135, 136, 0, 138, 139, 10, 85, 90,
+	147, 144, 3, 0, 16, 218, 0, 170, 218, 0,
+	0, 0, 0, 0, 66, 66, 0, 18, 19, 262,
+	0, 0, 218, 23, 26, 0, 0, 0, 0, 49,
+	0, 0, 0, 39, 0, 0, 0, 0, 0, 52,
+	0, 0, 179, 179, 279, 0, 0, 0, 146, 137,
+	0, 0, 0, 91, 92, 328, 330, 332, 0, 334,
+	-2, 345, 353, 184, 349, 357, 321, 0, 359, 0,
+	361, 362, 363, 185, 153, 0, 0, 0, 0, 94,
+	95, 96, 97, 98, 0, 100, 101, 102, 103, 189,
+	168, 162, 163, 193, 173, 174, 181, 182, 183, 186,
+	187, 188, 190, 191, 192, 0, 148, 0, 0, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 258,
+	0, 0, 260, 0, 266, 261, 0, 0, 0, 0,
 	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
-	106, 0, 118, 2, 5, 9, 0, 0, 49, 0,
-	0, 15, 0, 197, 0, 0, 0, 0, 0, 0,
-	0, 36, 38, 39, 40, 41, 42, 43, 44, 0,
-	0, 0, 0, 0, 195, 152, 153, 154, 155, 156,
-	157, 158, 159, 160, 161, 162, 163, 164, 165, 166,
-	167, 168, 169, 170, 171, 172, 173, 174, 175, 176,
-	177, 178, 179, 180, 181, 182, 183, 184, 185, 116,
-	108, 109, 0, 111, 112, 0, 119, 3, 0, 14,
-	176, 0, 132, 0, 0, 49, 0, 16, 17, 200,
-	0, 0, 20, 0, 0, 32, 0, 0, 0, 35,
-	0, 0, 139, 139, 212, 0, 0, 117, 110, 0,
-	115, 120, 121, 231, 243, 245, 247, 0, 249, -2,
-	256, 264, 144, 260, 268, 236, 0, 270, 0, 272,
-	273, 274, 145, 124, 0, 71, 72, 73, 74, 75,
-	0, 77, 78, 79, 80, 130, 152, 133, 134, 141,
-	142, 143, 146, 147, 148, 149, 150, 151, 0, 0,
-	0, 0, 0, 0, 0, 196, 0, 0, 198, 0,
-	204, 199, 0, 0, 0, 0, 0, 0, 0, 37,
-	0, 0, 0, 0, 224, 0, 212, 59, 0, 107,
-	113, 0, 0, 122, 232, 0, 0, 0, 248, 0,
-	0, 0, 0, 0, 0, 0, 278, 0, 0, 0,
-	0, 0, 237, 269, 144, 0, 0, 0, 125, 0,
-	0, 0, 0, 67, 0, 0, 0, 90, 92, 93,
-	0, 0, 0, 163, 145, 0, 50, 0, 0, 201,
-	202, 203, 0, 24, 0, 0, 0, 0, 0, 0,
-	0, 0, 57, 0, 56, 0, 135, 52, 218, 0,
-	213, 224, 0, 0, 224, 197, 0, 0, 178, 0,
-	185, 231, 231, 233, 244, 246, 250, 251, 0, 0,
-	0, 0, 255, 262, 263, 265, 266, 267, 0, 241,
-	0, 271, 275, 81, 82, 83, 84, 85, 86, 87,
-	88, 0, 279, 280, 281, 282, 283, 284, 0, 128,
-	0, 0, 0, 131, 0, 68, 69, 13, 0, 19,
-	0, 0, 98, 234, 0, 0, 0, 45, 0, 25,
-	26, 0, 28, 29, 45, 0, 0, 51, 0, 55,
-	62, 67, 0, 140, 220, 0, 0, 218, 60, 61,
-	-2, 231, 0, 0, 0, 0, 0, 0, 0, 193,
-	123, 252, 0, 254, 0, 257, 0, 0, 0, 0,
-	0, 129, 126, 127, 0, 89, 0, 0, 91, 94,
-	137, 0, 103, 0, 0, 0, 0, 0, 30, 46,
-	47, 48, 23, 0, 31, 0, 0, 0, 0, 0,
-	136, 53, 0, 219, 0, 220, 212, 206, -2, 0,
-	211, 186, 0, 231, 0, 231, 231, 0, 231, 253,
-	0, 0, 177, 238, 0, 242, 0, 0, 0, 70,
-	0, 0, 101, 104, 0, 0, 0, 235, 21, 0,
-	27, 33, 34, 0, 63, 64, 221, 225, 54, 214,
-	208, 0, 187, 188, 0, 189, 190, 191, 192, 258,
-	259, 0, 239, 276, 76, 18, 138, 96, 102, 105,
-	99, 100, 22, 58, 216, 0, 224, 231, 240, 95,
-	0, 222, 0, 0, 0, 194, 97, 218, 0, 217,
-	215, 65, 0, 220, 0, 0, 209, 114, 223, 228,
-	66, 0, 226, 229, 230, 228, 227,
+	0, 54, 0, 0, 0, 0, 306, 0, 279, 82,
+	0, 257, 134, 140, 86, 87, 0, 0, 0, 333,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 367,
+	0, 0, 0, 0, 0, 322, 358, 184, 0, 0,
+	0, 154, 0, 0, 0, 0, 0, 0, 0, 0,
+	90, 279, 149, 150, 316, 0, 0, 0, 115, 117,
+	118, 0, 0, 0, 205, 185, 189, 0, 25, 0,
+	0, 77, 78, 79, 0, 67, 0, 0, 0, 263,
+	264, 265, 22, 29, 0, 35, 0, 0, 38, 0,
+	0, 0, 0, 0, 0, 0, 0, 0, 0, 0,
+	80, 0, 76, 0, 175, 72, 289, 0, 280, 306,
+	0, 0, 93, 329, 331, 335, 336, 0, 0, 0,
+	0, 0, 342, 343, 0, 351, 352, 354, 355, 356,
+	0, 326, 0, 360, 364, 105, 106, 107, 108, 109,
+	110, 111, 112, 113, 0, 368, 369, 370, 371, 372,
+	373, 0, 166, 0, 0, 0, 0, 164, 165, 0,
+	0, 0, 0, 169, 0, 0, 289, 0, 151, 317,
+	0, 15, 0, 21, 0, 0, 125, 319, 0, 0,
+	0, 0, 0, 0, 0, 62, 0, 0, 0, 0,
+	40, 41, 0, 43, 44, 62, 45, 171, 172, 0,
+	0, 0, 71, 0, 75, 0, 180, 291, 0, 0,
+	289, 83, 84, 337, 0, 0, 0, 341, 0, 0,
+	346, 0, 0, 0, 0, 0, 167, 155, 156, 0,
+	88, 0, 0, 0, 0, 0, 114, 306, 259, 0,
+	0, 220, 0, 227, 316, 291, 316, 318, 0, 116,
+	119, 177, 0, 121, 0, 0, 0, 0, 0, 0,
+	0, 0, 0, 47, 63, 64, 65, 33, 0, 36,
+	0, 0, 48, 0, 0, 0, 0, 176, 73, 0,
+	290, 0, 291, 0, 338, 340, 0, 0, 219, 0,
+	323, 0, 327, 0, 0, 0, 157, 0, 0, 0,
+	0, 0, -2, 316, 0, 0, 0, 0, 0, 0,
+	0, 254, 142, 152, 0, 0, 130, 0, 0, 0,
+	320, 24, 0, 0, 30, 0, 0, 34, 37, 42,
+	46, 50, 51, 0, 292, 307, 74, 339, 347, 348,
+	0, 0, 324, 365, 89, 0, 159, 160, 0, 99,
+	104, 279, 268, -2, 0, 277, 0, 278, 245, 0,
+	316, 0, 316, 316, 0, 316, 20, 178, 128, 131,
+	0, 0, 126, 127, 0, 0, 68, 32, 81, 0,
+	325, 0, 0, 281, 270, 0, 0, 274, 0, 316,
+	249, 0, 250, 251, 252, 253, 123, 129, 132, 0,
+	0, 0, 31, 0, 0, 0, 161, 287, 0, 306,
+	0, 238, 0, 0, 246, 317, 0, 316, 120, 0,
+	0, 0, 28, 69, 0, 0, 0, 304, 0, 0,
+	0, 0, 306, 0, 0, 318, 255, 124, 0, 0,
+	70, 0, 310, 289, 0, 288, 282, 283, 285, 286,
+	0, 0, 275, 273, 316, 0, 0, 0, 0, 0,
+	0, 311, 312, 291, 0, 0, 271, 0, 276, 247,
+	0, 122, 27, 344, 158, 293, 305, 310, 284, 0,
+	248, 295, 0, 0, 313, 272, 298, 0, 294, 310,
+	308, 0, 141, 0, 321, 300, 301, 313, 314, 315,
+	299, 0, 309, 0, 302, 303, 296, 0, 297,
 }
 
 var yyTok1 = [...]uint8{
 	1, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 3, 3, 3, 3, 3, 3, 128, 3, 3,
-	131, 132, 126, 124, 123, 125, 129, 127, 3, 3,
+	3, 3, 3, 3, 3, 3, 3, 159, 3, 3,
+	162, 163, 157, 155, 154, 156, 160, 158, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
 	3, 3, 3, 3, 3, 3, 3, 3, 3, 3,
-	3, 133, 3, 134,
+	3, 164, 3, 165,
 }
 
 var yyTok2 = [...]uint8{
@@ -808,7 +1107,10 @@ var yyTok2 = [...]uint8{
 	92, 93, 94, 95, 96, 97, 98, 99, 100, 101,
 	102, 103, 104, 105, 106, 107, 108, 109, 110, 111,
 	112, 113, 114, 115, 116, 117, 118, 119, 120, 121,
-	122, 130,
+	122, 123, 124, 125, 126, 127, 128, 129, 130, 131,
+	132, 133, 134, 135, 136, 137, 138, 139, 140, 141,
+	142, 143, 144, 145, 146, 147, 148, 149, 150, 151,
+	152, 153, 161,
 }
 
 var yyTok3 = [...]int8{
@@ -1170,504 +1472,643 @@ yydefault:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 		}
-	case 9:
+	case 10:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.stmt = &SelectStmt{ds: &valuesDataSource{inferTypes: true, rows: yyDollar[2].rows}}
+		}
+	case 11:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.stmt = &BeginTransactionStmt{}
 		}
-	case 10:
+	case 12:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.stmt = &BeginTransactionStmt{}
 		}
-	case 11:
+	case 13:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.stmt = &CommitStmt{}
 		}
-	case 12:
+	case 14:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.stmt = &RollbackStmt{}
 		}
-	case 13:
+	case 15:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyVAL.stmt = &CreateDatabaseStmt{ifNotExists: true, DB: yyDollar[6].id}
 		}
-	case 14:
+	case 16:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.stmt = &CreateDatabaseStmt{ifNotExists: false, DB: yyDollar[3].id}
 		}
-	case 15:
+	case 17:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.stmt = &UseDatabaseStmt{DB: yyDollar[2].id}
 		}
-	case 16:
+	case 18:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.stmt = &UseDatabaseStmt{DB: yyDollar[3].id}
 		}
-	case 17:
+	case 19:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.stmt = &UseSnapshotStmt{period: yyDollar[3].period}
 		}
-	case 18:
+	case 20:
 		yyDollar = yyS[yypt-9 : yypt+1]
 		{
 			yyVAL.stmt = newCreateTableStmt(yyDollar[6].str, yyDollar[8].tableElems, true)
 		}
-	case 19:
+	case 21:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyVAL.stmt = newCreateTableStmt(yyDollar[3].str, yyDollar[5].tableElems, false)
 		}
-	case 20:
+	case 22:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		{
+			yyVAL.stmt = &DropTableStmt{table: yyDollar[5].str, ifExists: true}
+		}
+	case 23:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.stmt = &DropTableStmt{table: yyDollar[3].str}
 		}
-	case 21:
+	case 24:
+		yyDollar = yyS[yypt-8 : yypt+1]
+		{
+			// the view query starts at the token following AS
+			yyVAL.stmt = &CreateViewStmt{view: yyDollar[6].str, ifNotExists: true, query: yyDollar[8].stmt.(*SelectStmt), sql: yylex.(*lexer).recordedText(5, yyrcvr.char >= 0)}
+			yylex.(*lexer).stopRecording()
+		}
+	case 25:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		{
+			yyVAL.stmt = &CreateViewStmt{view: yyDollar[3].str, query: yyDollar[5].stmt.(*SelectStmt), sql: yylex.(*lexer).recordedText(2, yyrcvr.char >= 0)}
+			yylex.(*lexer).stopRecording()
+		}
+	case 26:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.stmt = &DropViewStmt{view: yyDollar[3].str}
+			yylex.(*lexer).stopRecording()
+		}
+	case 27:
+		yyDollar = yyS[yypt-14 : yypt+1]
+		{
+			// the trigger body starts at the token following ROW
+			yyVAL.stmt = &CreateTriggerStmt{trigger: yyDollar[6].id, ifNotExists: true, event: yyDollar[8].triggerEvent, table: yyDollar[10].str, body: yyDollar[14].stmt, sql: yylex.(*lexer).recordedText(11, yyrcvr.char >= 0)}
+			yylex.(*lexer).stopRecording()
+		}
+	case 28:
+		yyDollar = yyS[yypt-11 : yypt+1]
+		{
+			yyVAL.stmt = &CreateTriggerStmt{trigger: yyDollar[3].id, event: yyDollar[5].triggerEvent, table: yyDollar[7].str, body: yyDollar[11].stmt, sql: yylex.(*lexer).recordedText(8, yyrcvr.char >= 0)}
+			yylex.(*lexer).stopRecording()
+		}
+	case 29:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		{
+			yyVAL.stmt = &DropTriggerStmt{trigger: yyDollar[3].id, table: yyDollar[5].str}
+			yylex.(*lexer).stopRecording()
+		}
+	case 30:
 		yyDollar = yyS[yypt-8 : yypt+1]
 		{
 			yyVAL.stmt = &CreateIndexStmt{ifNotExists: yyDollar[3].boolean, table: yyDollar[5].str, cols: yyDollar[7].colNames}
 		}
-	case 22:
+	case 31:
+		yyDollar = yyS[yypt-10 : yypt+1]
+		{
+			yyVAL.stmt = &CreateIndexStmt{unique: true, nullsNotDistinct: yyDollar[10].boolean, ifNotExists: yyDollar[4].boolean, table: yyDollar[6].str, cols: yyDollar[8].colNames}
+		}
+	case 32:
 		yyDollar = yyS[yypt-9 : yypt+1]
 		{
-			yyVAL.stmt = &CreateIndexStmt{unique: true, ifNotExists: yyDollar[4].boolean, table: yyDollar[6].str, cols: yyDollar[8].colNames}
+			yyVAL.stmt = &CreateIndexStmt{fullText: true, ifNotExists: yyDollar[4].boolean, table: yyDollar[6].str, cols: []string{yyDollar[8].str}}
 		}
-	case 23:
+	case 33:
 		yyDollar = yyS[yypt-7 : yypt+1]
 		{
 			yyVAL.stmt = &DropIndexStmt{table: yyDollar[4].str, cols: yyDollar[6].colNames}
 		}
-	case 24:
+	case 34:
+		yyDollar = yyS[yypt-8 : yypt+1]
+		{
+			yyVAL.stmt = &DropIndexStmt{fullText: true, table: yyDollar[5].str, cols: []string{yyDollar[7].str}}
+		}
+	case 35:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			yyVAL.stmt = &DropIndexStmt{table: yyDollar[3].str, cols: []string{yyDollar[5].str}}
 		}
-	case 25:
+	case 36:
+		yyDollar = yyS[yypt-7 : yypt+1]
+		{
+			yyVAL.stmt = &ReindexStmt{table: yyDollar[4].str, cols: yyDollar[6].colNames}
+		}
+	case 37:
+		yyDollar = yyS[yypt-8 : yypt+1]
+		{
+			yyVAL.stmt = &ReindexStmt{fullText: true, table: yyDollar[5].str, cols: []string{yyDollar[7].str}}
+		}
+	case 38:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		{
+			yyVAL.stmt = &ReindexStmt{table: yyDollar[3].str, cols: []string{yyDollar[5].str}}
+		}
+	case 39:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.stmt = &ReindexStmt{table: yyDollar[3].str}
+		}
+	case 40:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyVAL.stmt = &AddColumnStmt{table: yyDollar[3].str, colSpec: yyDollar[6].colSpec}
 		}
-	case 26:
+	case 41:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyVAL.stmt = &RenameTableStmt{oldName: yyDollar[3].str, newName: yyDollar[6].str}
 		}
-	case 27:
+	case 42:
 		yyDollar = yyS[yypt-8 : yypt+1]
 		{
 			yyVAL.stmt = &RenameColumnStmt{table: yyDollar[3].str, oldName: yyDollar[6].str, newName: yyDollar[8].str}
 		}
-	case 28:
+	case 43:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyVAL.stmt = &DropColumnStmt{table: yyDollar[3].str, colName: yyDollar[6].str}
 		}
-	case 29:
+	case 44:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyVAL.stmt = &DropConstraintStmt{table: yyDollar[3].str, constraintName: yyDollar[6].id}
 		}
-	case 30:
+	case 45:
+		yyDollar = yyS[yypt-6 : yypt+1]
+		{
+			yyVAL.stmt = &CommentStmt{table: yyDollar[4].str, comment: yyDollar[6].str}
+		}
+	case 46:
+		yyDollar = yyS[yypt-8 : yypt+1]
+		{
+			yyVAL.stmt = &CommentStmt{table: yyDollar[4].str, col: yyDollar[6].str, comment: yyDollar[8].str}
+		}
+	case 47:
 		yyDollar = yyS[yypt-7 : yypt+1]
 		{
 			yyVAL.stmt = &CreateUserStmt{username: yyDollar[3].id, password: yyDollar[6].str, permission: yyDollar[7].permission}
 		}
-	case 31:
+	case 48:
 		yyDollar = yyS[yypt-7 : yypt+1]
 		{
 			yyVAL.stmt = &AlterUserStmt{username: yyDollar[3].id, password: yyDollar[6].str, permission: yyDollar[7].permission}
 		}
-	case 32:
+	case 49:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.stmt = &DropUserStmt{username: yyDollar[3].id}
 		}
-	case 33:
+	case 50:
 		yyDollar = yyS[yypt-8 : yypt+1]
 		{
 			yyVAL.stmt = &AlterPrivilegesStmt{database: yyDollar[5].str, user: yyDollar[8].id, privileges: yyDollar[2].sqlPrivileges, isGrant: true}
 		}
-	case 34:
+	case 51:
 		yyDollar = yyS[yypt-8 : yypt+1]
 		{
 			yyVAL.stmt = &AlterPrivilegesStmt{database: yyDollar[5].str, user: yyDollar[8].id, privileges: yyDollar[2].sqlPrivileges}
 		}
-	case 35:
+	case 52:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.sqlPrivileges = allPrivileges
 		}
-	case 36:
+	case 53:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.sqlPrivileges = []SQLPrivilege{yyDollar[1].sqlPrivilege}
 		}
-	case 37:
+	case 54:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.sqlPrivileges = append(yyDollar[3].sqlPrivileges, yyDollar[1].sqlPrivilege)
 		}
-	case 38:
+	case 55:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.sqlPrivilege = SQLPrivilegeSelect
 		}
-	case 39:
+	case 56:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.sqlPrivilege = SQLPrivilegeCreate
 		}
-	case 40:
+	case 57:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.sqlPrivilege = SQLPrivilegeInsert
 		}
-	case 41:
+	case 58:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.sqlPrivilege = SQLPrivilegeUpdate
 		}
-	case 42:
+	case 59:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.sqlPrivilege = SQLPrivilegeDelete
 		}
-	case 43:
+	case 60:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.sqlPrivilege = SQLPrivilegeDrop
 		}
-	case 44:
+	case 61:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.sqlPrivilege = SQLPrivilegeAlter
 		}
-	case 45:
+	case 62:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.permission = PermissionReadWrite
 		}
-	case 46:
+	case 63:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.permission = PermissionReadOnly
 		}
-	case 47:
+	case 64:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.permission = PermissionReadWrite
 		}
-	case 48:
+	case 65:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.permission = PermissionAdmin
 		}
-	case 49:
+	case 66:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.boolean = false
 		}
-	case 50:
+	case 67:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.boolean = true
 		}
-	case 51:
+	case 68:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		{
+			yyVAL.boolean = false
+		}
+	case 69:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.boolean = false
+		}
+	case 70:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.boolean = true
+		}
+	case 71:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyVAL.stmt = &UpsertIntoStmt{isInsert: true, tableRef: yyDollar[3].tableRef, cols: yyDollar[4].colNames, ds: yyDollar[5].ds, onConflict: yyDollar[6].onConflict}
 		}
-	case 52:
+	case 72:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			yyVAL.stmt = &UpsertIntoStmt{tableRef: yyDollar[3].tableRef, cols: yyDollar[4].colNames, ds: yyDollar[5].ds}
 		}
-	case 53:
+	case 73:
 		yyDollar = yyS[yypt-7 : yypt+1]
 		{
 			yyVAL.stmt = &DeleteFromStmt{tableRef: yyDollar[3].tableRef, where: yyDollar[4].exp, indexOn: yyDollar[5].colNames, limit: yyDollar[6].exp, offset: yyDollar[7].exp}
 		}
-	case 54:
+	case 74:
 		yyDollar = yyS[yypt-8 : yypt+1]
 		{
 			yyVAL.stmt = &UpdateStmt{tableRef: yyDollar[2].tableRef, updates: yyDollar[4].updates, where: yyDollar[5].exp, indexOn: yyDollar[6].colNames, limit: yyDollar[7].exp, offset: yyDollar[8].exp}
 		}
-	case 55:
+	case 75:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.ds = &valuesDataSource{rows: yyDollar[2].rows}
 		}
-	case 56:
+	case 76:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.ds = yyDollar[1].stmt.(DataSource)
 		}
-	case 57:
+	case 77:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.triggerEvent = TriggerOnInsert
+		}
+	case 78:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.triggerEvent = TriggerOnUpdate
+		}
+	case 79:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.triggerEvent = TriggerOnDelete
+		}
+	case 80:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.onConflict = nil
 		}
-	case 58:
+	case 81:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.onConflict = &OnConflictDo{}
 		}
-	case 59:
+	case 82:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.updates = []*colUpdate{yyDollar[1].update}
 		}
-	case 60:
+	case 83:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.updates = append(yyDollar[1].updates, yyDollar[3].update)
 		}
-	case 61:
+	case 84:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.update = &colUpdate{col: yyDollar[1].id, op: yyDollar[2].cmpOp, val: yyDollar[3].exp}
 		}
-	case 62:
+	case 85:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.rows = []*RowSpec{yyDollar[1].row}
 		}
-	case 63:
+	case 86:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.rows = append(yyDollar[1].rows, yyDollar[3].row)
 		}
-	case 64:
+	case 87:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.row = &RowSpec{Values: yyDollar[2].values}
 		}
-	case 65:
+	case 88:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.cols = []*ColSelector{yyDollar[1].col}
 		}
-	case 66:
+	case 89:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.cols = append(yyDollar[1].cols, yyDollar[3].col)
 		}
-	case 67:
+	case 90:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.values = nil
 		}
-	case 68:
+	case 91:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.values = yyDollar[1].values
 		}
-	case 69:
+	case 92:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.values = []ValueExp{yyDollar[1].exp}
 		}
-	case 70:
+	case 93:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.values = append(yyDollar[1].values, yyDollar[3].exp)
 		}
-	case 71:
+	case 94:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.value = &Integer{val: int64(yyDollar[1].integer)}
 		}
-	case 72:
+	case 95:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.value = &Float64{val: float64(yyDollar[1].float)}
 		}
-	case 73:
+	case 96:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.value = &Varchar{val: yyDollar[1].str}
 		}
-	case 74:
+	case 97:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.value = &Bool{val: yyDollar[1].boolean}
 		}
-	case 75:
+	case 98:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.value = &Blob{val: yyDollar[1].blob}
 		}
-	case 76:
+	case 99:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyVAL.value = &Cast{val: yyDollar[3].exp, t: yyDollar[5].sqlType}
 		}
-	case 77:
+	case 100:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.value = yyDollar[1].value
 		}
-	case 78:
+	case 101:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.value = &Param{id: yyDollar[1].id}
 		}
-	case 79:
+	case 102:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.value = &Param{id: fmt.Sprintf("param%d", yyDollar[1].pparam), pos: yyDollar[1].pparam}
 		}
-	case 80:
+	case 103:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.value = &NullValue{t: AnyType}
 		}
-	case 81:
+	case 104:
+		yyDollar = yyS[yypt-6 : yypt+1]
+		{
+			yyVAL.value = &PointExp{lat: yyDollar[3].exp, lon: yyDollar[5].exp}
+		}
+	case 105:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.sqlType = IntegerType
 		}
-	case 82:
+	case 106:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.sqlType = BooleanType
 		}
-	case 83:
+	case 107:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.sqlType = VarcharType
 		}
-	case 84:
+	case 108:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.sqlType = UUIDType
 		}
-	case 85:
+	case 109:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.sqlType = BLOBType
 		}
-	case 86:
+	case 110:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.sqlType = TimestampType
 		}
-	case 87:
+	case 111:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.sqlType = Float64Type
 		}
-	case 88:
+	case 112:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.sqlType = JSONType
 		}
-	case 89:
+	case 113:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.sqlType = PointType
+		}
+	case 114:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
-			yyVAL.value = &FnCall{fn: yyDollar[1].id, params: yyDollar[3].values}
+			yyVAL.value = &FnCall{fn: yyDollar[1].id, params: yyDollar[3].values, functions: yylex.(*lexer).functions}
 		}
-	case 90:
+	case 115:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.tableElems = []TableElem{yyDollar[1].tableElem}
 		}
-	case 91:
+	case 116:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.tableElems = append(yyDollar[1].tableElems, yyDollar[3].tableElem)
 		}
-	case 92:
+	case 117:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.tableElem = yyDollar[1].colSpec
 		}
-	case 93:
+	case 118:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.tableElem = yyDollar[1].check
 		}
-	case 94:
+	case 119:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.tableElem = PrimaryKeyConstraint(yyDollar[3].colNames)
 		}
-	case 95:
-		yyDollar = yyS[yypt-6 : yypt+1]
+	case 120:
+		yyDollar = yyS[yypt-7 : yypt+1]
 		{
 			yyVAL.colSpec = &ColSpec{
 				colName:       yyDollar[1].str,
 				colType:       yyDollar[2].sqlType,
 				maxLen:        int(yyDollar[3].integer),
-				notNull:       yyDollar[4].boolean || yyDollar[6].boolean,
-				autoIncrement: yyDollar[5].boolean,
-				primaryKey:    yyDollar[6].boolean,
+				generatedExp:  yyDollar[4].exp,
+				notNull:       yyDollar[5].boolean || yyDollar[7].boolean,
+				autoIncrement: yyDollar[6].boolean,
+				primaryKey:    yyDollar[7].boolean,
 			}
 		}
-	case 96:
+	case 121:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		{
+			yyVAL.exp = nil
+		}
+	case 122:
+		yyDollar = yyS[yypt-7 : yypt+1]
+		{
+			yyVAL.exp = yyDollar[5].exp
+		}
+	case 123:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.boolean = false
 		}
-	case 97:
+	case 124:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.boolean = true
 		}
-	case 98:
+	case 125:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.integer = 0
 		}
-	case 99:
+	case 126:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.integer = yyDollar[2].integer
 		}
-	case 100:
+	case 127:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.integer = yyDollar[2].integer
 		}
-	case 101:
+	case 128:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.boolean = false
 		}
-	case 102:
+	case 129:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.boolean = true
 		}
-	case 103:
+	case 130:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.boolean = false
 		}
-	case 104:
+	case 131:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.boolean = false
 		}
-	case 105:
+	case 132:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.boolean = true
 		}
-	case 106:
+	case 133:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.stmt = yyDollar[1].stmt
 		}
-	case 107:
+	case 134:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.stmt = &UnionStmt{
@@ -1676,463 +2117,690 @@ yydefault:
 				right:    yyDollar[4].stmt.(DataSource),
 			}
 		}
-	case 108:
+	case 135:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.stmt = &SelectStmt{
 				ds: &FnDataSourceStmt{fnCall: &FnCall{fn: "databases"}},
 			}
 		}
-	case 109:
+	case 136:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.stmt = &SelectStmt{
 				ds: &FnDataSourceStmt{fnCall: &FnCall{fn: "tables"}},
 			}
 		}
-	case 110:
+	case 137:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.stmt = &SelectStmt{
 				ds: &FnDataSourceStmt{fnCall: &FnCall{fn: "table", params: []ValueExp{&Varchar{val: yyDollar[3].id}}}},
 			}
 		}
-	case 111:
+	case 138:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.stmt = &SelectStmt{
 				ds: &FnDataSourceStmt{fnCall: &FnCall{fn: "users"}},
 			}
 		}
-	case 112:
+	case 139:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.stmt = &SelectStmt{
 				ds: &FnDataSourceStmt{fnCall: &FnCall{fn: "grants"}},
 			}
 		}
-	case 113:
+	case 140:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.stmt = &SelectStmt{
 				ds: &FnDataSourceStmt{fnCall: &FnCall{fn: "grants", params: []ValueExp{&Varchar{val: yyDollar[4].id}}}},
 			}
 		}
-	case 114:
-		yyDollar = yyS[yypt-13 : yypt+1]
+	case 141:
+		yyDollar = yyS[yypt-17 : yypt+1]
 		{
-			yyVAL.stmt = &SelectStmt{
-				distinct: yyDollar[2].distinct,
-				targets:  yyDollar[3].targets,
-				ds:       yyDollar[5].ds,
-				indexOn:  yyDollar[6].colNames,
-				joins:    yyDollar[7].joins,
-				where:    yyDollar[8].exp,
-				groupBy:  yyDollar[9].cols,
-				having:   yyDollar[10].exp,
-				orderBy:  yyDollar[11].ordexps,
-				limit:    yyDollar[12].exp,
-				offset:   yyDollar[13].exp,
+			stmt := &SelectStmt{
+				distinct: yyDollar[3].distinct,
+				targets:  yyDollar[4].targets,
+				ds:       yyDollar[6].ds,
+				indexOn:  yyDollar[7].colNames,
+				joins:    yyDollar[8].joins,
+				where:    yyDollar[9].exp,
+				having:   yyDollar[11].exp,
+				orderBy:  yyDollar[12].ordexps,
+				limit:    yyDollar[13].exp,
+				offset:   yyDollar[14].exp,
+				withTies: yyDollar[15].boolean,
+				forShare: yyDollar[17].boolean,
+			}
+
+			if yyDollar[15].boolean && yyDollar[13].exp == nil {
+				yylex.Error("WITH TIES requires LIMIT or FETCH")
+			}
+
+			if yyDollar[16].fetch != nil {
+				if yyDollar[13].exp != nil {
+					yylex.Error("LIMIT and FETCH can not be combined")
+				}
+
+				stmt.limit = yyDollar[16].fetch.limit
+				stmt.withTies = yyDollar[16].fetch.withTies
+			}
+
+			err := stmt.applyIndexHints(yyDollar[2].hints)
+			if err != nil {
+				yylex.Error(err.Error())
 			}
+
+			err = stmt.resolveOrdinals(yyDollar[10].values)
+			if err != nil {
+				yylex.Error(err.Error())
+			}
+
+			yyVAL.stmt = stmt
 		}
-	case 115:
-		yyDollar = yyS[yypt-3 : yypt+1]
+	case 142:
+		yyDollar = yyS[yypt-7 : yypt+1]
 		{
-			yyVAL.stmt = &SelectStmt{
-				distinct: yyDollar[2].distinct,
-				targets:  yyDollar[3].targets,
+			if yyDollar[2].hints != nil {
+				yylex.Error("optimizer hints require a FROM clause")
+			}
+
+			// without a FROM clause, targets are evaluated over a single row with no columns
+			stmt := &SelectStmt{
+				distinct: yyDollar[3].distinct,
+				targets:  yyDollar[4].targets,
 				ds:       &valuesDataSource{rows: []*RowSpec{{}}},
+				where:    yyDollar[5].exp,
+				limit:    yyDollar[6].exp,
+				offset:   yyDollar[7].exp,
 			}
+
+			yyVAL.stmt = stmt
 		}
-	case 116:
+	case 143:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		{
+			yyVAL.hints = nil
+		}
+	case 144:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			hints, err := parseOptimizerHints(yyDollar[1].str)
+			if err != nil {
+				yylex.Error(err.Error())
+			}
+
+			yyVAL.hints = hints
+		}
+	case 145:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.distinct = true
 		}
-	case 117:
+	case 146:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.distinct = false
 		}
-	case 118:
+	case 147:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.distinct = false
 		}
-	case 119:
+	case 148:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.distinct = true
 		}
-	case 120:
+	case 149:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.targets = nil
 		}
-	case 121:
+	case 150:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.targets = yyDollar[1].targets
 		}
-	case 122:
+	case 151:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.targets = []TargetEntry{{Exp: yyDollar[1].exp, As: yyDollar[2].id}}
 		}
-	case 123:
+	case 152:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.targets = append(yyDollar[1].targets, TargetEntry{Exp: yyDollar[3].exp, As: yyDollar[4].id})
 		}
-	case 124:
+	case 153:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.sel = yyDollar[1].col
 		}
-	case 125:
+	case 154:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.sel = &JSONSelector{ColSelector: yyDollar[1].col, fields: yyDollar[2].jsonFields}
 		}
-	case 126:
+	case 155:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
-			yyVAL.sel = &AggColSelector{aggFn: yyDollar[1].aggFn, col: "*"}
+			yyVAL.sel = &AggColSelector{aggFn: yyDollar[1].aggFn, col: "*", aggregate: yylex.(*lexer).functions.aggregate(yyDollar[1].aggFn)}
 		}
-	case 127:
+	case 156:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
-			yyVAL.sel = &AggColSelector{aggFn: yyDollar[1].aggFn, table: yyDollar[3].col.table, col: yyDollar[3].col.col}
+			yyVAL.sel = &AggColSelector{aggFn: yyDollar[1].aggFn, table: yyDollar[3].col.table, col: yyDollar[3].col.col, aggregate: yylex.(*lexer).functions.aggregate(yyDollar[1].aggFn)}
 		}
-	case 128:
+	case 157:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		{
+			sel, err := newCountDistinctSelector(yyDollar[1].aggFn, yyDollar[4].cols)
+			if err != nil {
+				yylex.Error(err.Error())
+			}
+			yyVAL.sel = sel
+		}
+	case 158:
+		yyDollar = yyS[yypt-12 : yypt+1]
+		{
+			sel, err := newPercentileSelector(yyDollar[1].keyword, yyDollar[3].float, yyDollar[10].col, yyDollar[11].opt_ord)
+			if err != nil {
+				yylex.Error(err.Error())
+			}
+			yyVAL.sel = sel
+		}
+	case 159:
+		yyDollar = yyS[yypt-6 : yypt+1]
+		{
+			sel, err := newPercentileSelector(APPROX_PERCENTILE, yyDollar[5].float, yyDollar[3].col, false)
+			if err != nil {
+				yylex.Error(err.Error())
+			}
+			yyVAL.sel = sel
+		}
+	case 160:
+		yyDollar = yyS[yypt-6 : yypt+1]
+		{
+			sel, err := newReservoirSampleSelector(yyDollar[3].col, yyDollar[5].integer, nil)
+			if err != nil {
+				yylex.Error(err.Error())
+			}
+			yyVAL.sel = sel
+		}
+	case 161:
+		yyDollar = yyS[yypt-8 : yypt+1]
+		{
+			sel, err := newReservoirSampleSelector(yyDollar[3].col, yyDollar[5].integer, &yyDollar[7].integer)
+			if err != nil {
+				yylex.Error(err.Error())
+			}
+			yyVAL.sel = sel
+		}
+	case 162:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.keyword = PERCENTILE_CONT
+		}
+	case 163:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.keyword = PERCENTILE_DISC
+		}
+	case 165:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.float = float64(yyDollar[1].integer)
+		}
+	case 166:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.jsonFields = []string{yyDollar[2].str}
 		}
-	case 129:
+	case 167:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.jsonFields = append(yyVAL.jsonFields, yyDollar[3].str)
 		}
-	case 130:
+	case 168:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.col = &ColSelector{col: yyDollar[1].str}
 		}
-	case 131:
+	case 169:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.col = &ColSelector{table: yyDollar[1].str, col: yyDollar[3].str}
 		}
-	case 134:
+	case 172:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.str = ""
+		}
+	case 174:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.str = yyDollar[1].keyword
 		}
-	case 135:
+	case 175:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.colNames = []string{yyDollar[1].str}
 		}
-	case 136:
+	case 176:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.colNames = append(yyDollar[1].colNames, yyDollar[3].str)
 		}
-	case 137:
+	case 177:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.colNames = []string{yyDollar[1].str}
 		}
-	case 138:
+	case 178:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.colNames = yyDollar[2].colNames
 		}
-	case 139:
+	case 179:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.colNames = nil
 		}
-	case 140:
+	case 180:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.colNames = yyDollar[2].colNames
 		}
-	case 152:
+	case 193:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.str = yyDollar[1].id
 		}
-	case 153:
+	case 194:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.str = string(yyDollar[1].keyword)
 		}
-	case 186:
+	case 245:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyDollar[1].tableRef.period = yyDollar[2].period
 			yyDollar[1].tableRef.as = yyDollar[3].id
 			yyVAL.ds = yyDollar[1].tableRef
 		}
-	case 187:
-		yyDollar = yyS[yypt-4 : yypt+1]
+	case 246:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		{
+			yyVAL.ds = &valuesDataSource{inferTypes: true, rows: yyDollar[3].rows, as: yyDollar[5].id}
+		}
+	case 247:
+		yyDollar = yyS[yypt-8 : yypt+1]
+		{
+			yyVAL.ds = &valuesDataSource{inferTypes: true, rows: yyDollar[3].rows, as: yyDollar[5].str, colNames: yyDollar[7].colNames}
+		}
+	case 248:
+		yyDollar = yyS[yypt-9 : yypt+1]
 		{
-			yyVAL.ds = &valuesDataSource{inferTypes: true, rows: yyDollar[3].rows}
+			yyVAL.ds = &valuesDataSource{inferTypes: true, rows: yyDollar[3].rows, as: yyDollar[6].str, colNames: yyDollar[8].colNames}
 		}
-	case 188:
+	case 249:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyDollar[2].stmt.(*SelectStmt).as = yyDollar[4].id
 			yyVAL.ds = yyDollar[2].stmt.(DataSource)
 		}
-	case 189:
+	case 250:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.ds = &FnDataSourceStmt{fnCall: &FnCall{fn: "databases"}, as: yyDollar[4].id}
 		}
-	case 190:
+	case 251:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.ds = &FnDataSourceStmt{fnCall: &FnCall{fn: "tables"}, as: yyDollar[4].id}
 		}
-	case 191:
+	case 252:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.ds = &FnDataSourceStmt{fnCall: &FnCall{fn: "table", params: []ValueExp{&Varchar{val: yyDollar[3].id}}}}
 		}
-	case 192:
+	case 253:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.ds = &FnDataSourceStmt{fnCall: &FnCall{fn: "users"}, as: yyDollar[4].id}
 		}
-	case 193:
+	case 254:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.ds = &FnDataSourceStmt{fnCall: yyDollar[1].value.(*FnCall), as: yyDollar[2].id}
 		}
-	case 194:
+	case 255:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyVAL.ds = &tableRef{table: yyDollar[4].id, history: true, as: yyDollar[6].id}
 		}
-	case 195:
+	case 256:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.tableRef = &tableRef{table: yyDollar[1].str}
 		}
-	case 196:
+	case 257:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.tableRef = &tableRef{table: yyDollar[1].str + "." + yyDollar[3].str}
+		}
+	case 258:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.period = period{start: yyDollar[1].openPeriod, end: yyDollar[2].openPeriod}
 		}
-	case 197:
+	case 259:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.openPeriod = nil
 		}
-	case 198:
+	case 260:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.openPeriod = &openPeriod{inclusive: true, instant: yyDollar[2].periodInstant}
 		}
-	case 199:
+	case 261:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.openPeriod = &openPeriod{instant: yyDollar[2].periodInstant}
 		}
-	case 200:
+	case 262:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.openPeriod = nil
 		}
-	case 201:
+	case 263:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.openPeriod = &openPeriod{inclusive: true, instant: yyDollar[2].periodInstant}
 		}
-	case 202:
+	case 264:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.openPeriod = &openPeriod{instant: yyDollar[2].periodInstant}
 		}
-	case 203:
+	case 265:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.periodInstant = periodInstant{instantType: txInstant, exp: yyDollar[2].exp}
 		}
-	case 204:
+	case 266:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.periodInstant = periodInstant{instantType: timeInstant, exp: yyDollar[1].exp}
 		}
-	case 205:
+	case 267:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.joins = nil
 		}
-	case 206:
+	case 268:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.joins = yyDollar[1].joins
 		}
-	case 207:
+	case 269:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.joins = []*JoinSpec{yyDollar[1].join}
 		}
-	case 208:
+	case 270:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.joins = append([]*JoinSpec{yyDollar[1].join}, yyDollar[2].joins...)
 		}
-	case 209:
+	case 271:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyVAL.join = &JoinSpec{joinType: yyDollar[1].joinType, ds: yyDollar[3].ds, indexOn: yyDollar[4].colNames, cond: yyDollar[6].exp}
 		}
-	case 210:
+	case 272:
+		yyDollar = yyS[yypt-8 : yypt+1]
+		{
+			yyVAL.join = &JoinSpec{joinType: yyDollar[1].joinType, ds: yyDollar[3].ds, indexOn: yyDollar[4].colNames, using: yyDollar[7].colNames}
+		}
+	case 273:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		{
+			yyVAL.join = &JoinSpec{joinType: yyDollar[2].joinType, ds: yyDollar[4].ds, indexOn: yyDollar[5].colNames, natural: true}
+		}
+	case 274:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.join = &JoinSpec{joinType: InnerJoin, ds: yyDollar[2].ds, cond: &Bool{val: true}, lateral: true}
+		}
+	case 275:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		{
+			yyVAL.join = &JoinSpec{joinType: yyDollar[1].joinType, ds: yyDollar[3].ds, cond: yyDollar[5].exp, lateral: true}
+		}
+	case 276:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		{
+			yyDollar[3].stmt.(*SelectStmt).as = yyDollar[5].id
+			yyVAL.ds = yyDollar[3].stmt.(DataSource)
+		}
+	case 277:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.joinType = InnerJoin
 		}
-	case 211:
+	case 278:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.joinType = yyDollar[1].joinType
 		}
-	case 212:
+	case 279:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.exp = nil
 		}
-	case 213:
+	case 280:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.exp = yyDollar[2].exp
 		}
-	case 214:
+	case 281:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
-			yyVAL.cols = nil
+			yyVAL.values = nil
 		}
-	case 215:
+	case 282:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
-			yyVAL.cols = yyDollar[3].cols
+			yyVAL.values = yyDollar[3].values
+		}
+	case 283:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.values = []ValueExp{yyDollar[1].value}
 		}
-	case 216:
+	case 284:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.values = append(yyDollar[1].values, yyDollar[3].value)
+		}
+	case 285:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.value = yyDollar[1].col
+		}
+	case 286:
+		yyDollar = yyS[yypt-1 : yypt+1]
+		{
+			yyVAL.value = &Integer{val: int64(yyDollar[1].integer)}
+		}
+	case 287:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.exp = nil
 		}
-	case 217:
+	case 288:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.exp = yyDollar[2].exp
 		}
-	case 218:
+	case 289:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.exp = nil
 		}
-	case 219:
+	case 290:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.exp = yyDollar[2].exp
 		}
-	case 220:
+	case 291:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.exp = nil
 		}
-	case 221:
+	case 292:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.exp = yyDollar[2].exp
 		}
-	case 222:
+	case 293:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		{
+			yyVAL.boolean = false
+		}
+	case 294:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.boolean = true
+		}
+	case 295:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		{
+			yyVAL.fetch = nil
+		}
+	case 296:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		{
+			yyVAL.fetch = newFetchClause(yyDollar[3].exp, false)
+		}
+	case 297:
+		yyDollar = yyS[yypt-6 : yypt+1]
+		{
+			yyVAL.fetch = newFetchClause(yyDollar[3].exp, true)
+		}
+	case 298:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		{
+			yyVAL.boolean = false
+		}
+	case 299:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.boolean = true
+		}
+	case 304:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.ordexps = nil
 		}
-	case 223:
+	case 305:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.ordexps = yyDollar[3].ordexps
 		}
-	case 224:
+	case 306:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.colNames = nil
 		}
-	case 225:
+	case 307:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.colNames = yyDollar[4].colNames
 		}
-	case 226:
-		yyDollar = yyS[yypt-2 : yypt+1]
+	case 308:
+		yyDollar = yyS[yypt-3 : yypt+1]
 		{
-			yyVAL.ordexps = []*OrdExp{{exp: yyDollar[1].exp, descOrder: yyDollar[2].opt_ord}}
+			yyVAL.ordexps = []*OrdExp{{exp: yyDollar[1].exp, descOrder: yyDollar[2].opt_ord, nullsOrder: yyDollar[3].nullsOrder}}
 		}
-	case 227:
-		yyDollar = yyS[yypt-4 : yypt+1]
+	case 309:
+		yyDollar = yyS[yypt-5 : yypt+1]
 		{
-			yyVAL.ordexps = append(yyDollar[1].ordexps, &OrdExp{exp: yyDollar[3].exp, descOrder: yyDollar[4].opt_ord})
+			yyVAL.ordexps = append(yyDollar[1].ordexps, &OrdExp{exp: yyDollar[3].exp, descOrder: yyDollar[4].opt_ord, nullsOrder: yyDollar[5].nullsOrder})
 		}
-	case 228:
+	case 310:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.opt_ord = false
 		}
-	case 229:
+	case 311:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.opt_ord = false
 		}
-	case 230:
+	case 312:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.opt_ord = true
 		}
-	case 231:
+	case 313:
+		yyDollar = yyS[yypt-0 : yypt+1]
+		{
+			yyVAL.nullsOrder = nullsOrderUnspecified
+		}
+	case 314:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.nullsOrder = NullsFirst
+		}
+	case 315:
+		yyDollar = yyS[yypt-2 : yypt+1]
+		{
+			yyVAL.nullsOrder = NullsLast
+		}
+	case 316:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.id = ""
 		}
-	case 232:
+	case 317:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.id = yyDollar[1].str
 		}
-	case 233:
+	case 318:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.id = yyDollar[2].str
 		}
-	case 234:
+	case 319:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.check = CheckConstraint{exp: yyDollar[2].exp}
 		}
-	case 235:
+	case 320:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.check = CheckConstraint{name: yyDollar[2].id, exp: yyDollar[4].exp}
 		}
-	case 236:
+	case 321:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.exp = nil
 		}
-	case 237:
+	case 322:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.exp = yyDollar[1].exp
 		}
-	case 238:
+	case 323:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			yyVAL.exp = &CaseWhenExp{
@@ -2141,62 +2809,72 @@ yydefault:
 				elseExp:  yyDollar[4].exp,
 			}
 		}
-	case 239:
+	case 324:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.whenThenClauses = []whenThenClause{{when: yyDollar[2].exp, then: yyDollar[4].exp}}
 		}
-	case 240:
+	case 325:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			yyVAL.whenThenClauses = append(yyDollar[1].whenThenClauses, whenThenClause{when: yyDollar[3].exp, then: yyDollar[5].exp})
 		}
-	case 241:
+	case 326:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.exp = nil
 		}
-	case 242:
+	case 327:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.exp = yyDollar[2].exp
 		}
-	case 243:
+	case 328:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.exp = yyDollar[1].exp
 		}
-	case 244:
+	case 329:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.exp = &BinBoolExp{left: yyDollar[1].exp, op: Or, right: yyDollar[3].exp}
 		}
-	case 246:
+	case 331:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.exp = &BinBoolExp{left: yyDollar[1].exp, op: And, right: yyDollar[3].exp}
 		}
-	case 248:
+	case 333:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			yyVAL.exp = &NotBoolExp{exp: yyDollar[2].exp}
 		}
-	case 250:
+	case 335:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.exp = &CmpBoolExp{left: yyDollar[1].exp, op: yyDollar[2].cmpOp, right: yyDollar[3].exp}
 		}
-	case 251:
+	case 336:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.exp = &CmpBoolExp{left: yyDollar[1].exp, op: EQ, right: &NullValue{t: AnyType}}
 		}
-	case 252:
+	case 337:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.exp = &CmpBoolExp{left: yyDollar[1].exp, op: NE, right: &NullValue{t: AnyType}}
 		}
-	case 253:
+	case 338:
+		yyDollar = yyS[yypt-5 : yypt+1]
+		{
+			yyVAL.exp = &DistinctBoolExp{left: yyDollar[1].exp, right: yyDollar[5].exp}
+		}
+	case 339:
+		yyDollar = yyS[yypt-6 : yypt+1]
+		{
+			yyVAL.exp = &DistinctBoolExp{left: yyDollar[1].exp, right: yyDollar[6].exp, notDistinct: true}
+		}
+	case 340:
 		yyDollar = yyS[yypt-5 : yypt+1]
 		{
 			yyVAL.exp = &BinBoolExp{
@@ -2213,62 +2891,72 @@ yydefault:
 				},
 			}
 		}
-	case 254:
+	case 341:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.exp = &LikeBoolExp{val: yyDollar[1].exp, notLike: yyDollar[2].boolean, pattern: yyDollar[4].exp}
 		}
-	case 255:
+	case 342:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.exp = &LikeBoolExp{val: yyDollar[1].exp, notLike: true, pattern: yyDollar[3].exp}
 		}
-	case 257:
+	case 343:
+		yyDollar = yyS[yypt-3 : yypt+1]
+		{
+			yyVAL.exp = &MatchBoolExp{val: yyDollar[1].exp, query: yyDollar[3].exp}
+		}
+	case 344:
+		yyDollar = yyS[yypt-12 : yypt+1]
+		{
+			yyVAL.exp = &WithinBoxExp{val: yyDollar[1].exp, minLat: yyDollar[5].exp, minLon: yyDollar[7].exp, maxLat: yyDollar[9].exp, maxLon: yyDollar[11].exp}
+		}
+	case 346:
 		yyDollar = yyS[yypt-4 : yypt+1]
 		{
 			yyVAL.exp = &ExistsBoolExp{q: (yyDollar[3].stmt).(DataSource)}
 		}
-	case 258:
+	case 347:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyVAL.exp = &InSubQueryExp{val: yyDollar[1].exp, notIn: yyDollar[2].boolean, q: yyDollar[5].stmt.(*SelectStmt)}
 		}
-	case 259:
+	case 348:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyVAL.exp = &InListExp{val: yyDollar[1].exp, notIn: yyDollar[2].boolean, values: yyDollar[5].values}
 		}
-	case 260:
+	case 349:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.exp = yyDollar[1].exp
 		}
-	case 262:
+	case 351:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.exp = &NumExp{left: yyDollar[1].exp, op: ADDOP, right: yyDollar[3].exp}
 		}
-	case 263:
+	case 352:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.exp = &NumExp{left: yyDollar[1].exp, op: SUBSOP, right: yyDollar[3].exp}
 		}
-	case 265:
+	case 354:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.exp = &NumExp{left: yyDollar[1].exp, op: MULTOP, right: yyDollar[3].exp}
 		}
-	case 266:
+	case 355:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.exp = &NumExp{left: yyDollar[1].exp, op: DIVOP, right: yyDollar[3].exp}
 		}
-	case 267:
+	case 356:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.exp = &NumExp{left: yyDollar[1].exp, op: MODOP, right: yyDollar[3].exp}
 		}
-	case 269:
+	case 358:
 		yyDollar = yyS[yypt-2 : yypt+1]
 		{
 			i, isInt := yyDollar[2].exp.(*Integer)
@@ -2279,67 +2967,67 @@ yydefault:
 				yyVAL.exp = &NumExp{left: &Integer{val: 0}, op: SUBSOP, right: yyDollar[2].exp}
 			}
 		}
-	case 271:
+	case 360:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.exp = yyDollar[2].exp
 		}
-	case 273:
+	case 362:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.exp = yyDollar[1].sel
 		}
-	case 274:
+	case 363:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.exp = yyDollar[1].value
 		}
-	case 275:
+	case 364:
 		yyDollar = yyS[yypt-3 : yypt+1]
 		{
 			yyVAL.exp = &Cast{val: yyDollar[1].exp, t: yyDollar[3].sqlType}
 		}
-	case 276:
+	case 365:
 		yyDollar = yyS[yypt-6 : yypt+1]
 		{
 			yyVAL.exp = &ExtractFromTimestampExp{Field: yyDollar[3].timestampField, Exp: yyDollar[5].exp}
 		}
-	case 277:
+	case 366:
 		yyDollar = yyS[yypt-0 : yypt+1]
 		{
 			yyVAL.boolean = false
 		}
-	case 278:
+	case 367:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.boolean = true
 		}
-	case 279:
+	case 368:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.timestampField = TimestampFieldTypeYear
 		}
-	case 280:
+	case 369:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.timestampField = TimestampFieldTypeMonth
 		}
-	case 281:
+	case 370:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.timestampField = TimestampFieldTypeDay
 		}
-	case 282:
+	case 371:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.timestampField = TimestampFieldTypeHour
 		}
-	case 283:
+	case 372:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.timestampField = TimestampFieldTypeMinute
 		}
-	case 284:
+	case 373:
 		yyDollar = yyS[yypt-1 : yypt+1]
 		{
 			yyVAL.timestampField = TimestampFieldTypeSecond
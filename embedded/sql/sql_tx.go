@@ -35,10 +35,23 @@ type SQLTx struct {
 	tx        *store.OngoingTx
 	tempFiles []*os.File
 
+	timestamp time.Time // set when the engine is using a custom clock
+
 	catalog *Catalog // in-mem catalog
 
+	// set for read-only transactions, rows are read as they were when the transaction
+	// with such id was committed, so that reads made at different times, e.g. over different
+	// indexes or while scanning a table, don't observe later transactions
+	snapshotTxID uint64
+
 	mutatedCatalog bool // set when a DDL stmt was executed within the current tx
 
+	sharedReads bool // set when rows were read FOR SHARE within the current tx
+
+	viewNesting  int // number of views being expanded
+	triggerDepth int // number of nested triggers being fired
+	storedReads  int // number of statements reading rows as stored, i.e. not through the middleware of their tables
+
 	updatedRows      int
 	lastInsertedPKs  map[string]int64 // last inserted PK by table name
 	firstInsertedPKs map[string]int64 // first inserted PK by table name
@@ -46,6 +59,19 @@ type SQLTx struct {
 	txHeader *store.TxHeader // header is set once tx is committed
 
 	onCommittedCallbacks []onCommittedCallback
+
+	openReaders map[RowReader]struct{} // readers returned by queries and not yet closed
+
+	resources *resourceTracker // accounts for the resources used by the statement being executed
+
+	stats Stats
+}
+
+// Stats holds counters about the statements executed within a transaction
+type Stats struct {
+	// ErrorsSkipped is the number of rows discarded because their conditions
+	// could not be evaluated, see Options.WithSkipErroredRows
+	ErrorsSkipped int64
 }
 
 type onCommittedCallback = func(sqlTx *SQLTx) error
@@ -69,6 +95,9 @@ func (sqlTx *SQLTx) RequireExplicitClose() error {
 }
 
 func (sqlTx *SQLTx) Timestamp() time.Time {
+	if !sqlTx.timestamp.IsZero() {
+		return sqlTx.timestamp
+	}
 	return sqlTx.tx.Timestamp()
 }
 
@@ -76,6 +105,18 @@ func (sqlTx *SQLTx) UpdatedRows() int {
 	return sqlTx.updatedRows
 }
 
+func (sqlTx *SQLTx) Stats() Stats {
+	return sqlTx.stats
+}
+
+// integerOverflow returns how integer overflows are handled, expressions may be evaluated without a transaction
+func (sqlTx *SQLTx) integerOverflow() IntegerOverflow {
+	if sqlTx == nil || sqlTx.engine == nil {
+		return IntegerOverflowError
+	}
+	return sqlTx.engine.integerOverflow
+}
+
 func (sqlTx *SQLTx) LastInsertedPKs() map[string]int64 {
 	return sqlTx.lastInsertedPKs
 }
@@ -88,10 +129,26 @@ func (sqlTx *SQLTx) TxHeader() *store.TxHeader {
 	return sqlTx.txHeader
 }
 
+// currentTxID returns the id of the transaction read by read-only transactions, or the id of
+// the last transaction committed in the store for read-write transactions
+func (sqlTx *SQLTx) currentTxID() uint64 {
+	if sqlTx.snapshotTxID > 0 {
+		return sqlTx.snapshotTxID
+	}
+	return sqlTx.engine.store.LastCommittedTxID()
+}
+
 func (sqlTx *SQLTx) sqlPrefix() []byte {
 	return sqlTx.engine.prefix
 }
 
+func (sqlTx *SQLTx) stmtResources() *resourceTracker {
+	if sqlTx == nil {
+		return nil
+	}
+	return sqlTx.resources
+}
+
 func (sqlTx *SQLTx) distinctLimit() int {
 	return sqlTx.engine.distinctLimit
 }
@@ -119,13 +176,39 @@ func (sqlTx *SQLTx) getWithPrefix(ctx context.Context, prefix, neq []byte) (key
 func (sqlTx *SQLTx) Cancel() error {
 	defer sqlTx.removeTempFiles()
 
-	return sqlTx.tx.Cancel()
+	rerr := sqlTx.closeReaders()
+
+	err := sqlTx.tx.Cancel()
+	if err != nil {
+		return err
+	}
+	return rerr
 }
 
 func (sqlTx *SQLTx) Commit(ctx context.Context) error {
 	defer sqlTx.removeTempFiles()
 
-	err := sqlTx.tx.RequireMVCCOnFollowingTxs(sqlTx.mutatedCatalog)
+	err := sqlTx.closeReaders()
+	if err != nil {
+		return err
+	}
+
+	if sqlTx.engine.readOnly {
+		// writes are rejected in read-only mode, there is nothing to be committed
+		return sqlTx.tx.Cancel()
+	}
+
+	if sqlTx.sharedReads {
+		// rows read FOR SHARE must not have been updated by concurrent transactions,
+		// which is otherwise checked on commit only if the transaction has writes
+		err := sqlTx.tx.ValidateReads(ctx)
+		if err != nil {
+			sqlTx.tx.Cancel()
+			return err
+		}
+	}
+
+	err = sqlTx.tx.RequireMVCCOnFollowingTxs(sqlTx.mutatedCatalog)
 	if err != nil {
 		return err
 	}
@@ -164,6 +247,31 @@ func (sqlTx *SQLTx) addOnCommittedCallback(callback onCommittedCallback) error {
 	return nil
 }
 
+// trackReader registers a reader returned by a query, so that it's closed
+// by the end of the transaction if it was not closed before
+func (sqlTx *SQLTx) trackReader(r RowReader) {
+	if sqlTx.openReaders == nil {
+		sqlTx.openReaders = make(map[RowReader]struct{})
+	}
+
+	sqlTx.openReaders[r] = struct{}{}
+
+	r.onClose(func() {
+		delete(sqlTx.openReaders, r)
+	})
+}
+
+func (sqlTx *SQLTx) closeReaders() error {
+	merr := multierr.NewMultiErr()
+
+	for r := range sqlTx.openReaders {
+		merr.Append(r.Close())
+	}
+	sqlTx.openReaders = nil
+
+	return merr.Reduce()
+}
+
 func (sqlTx *SQLTx) createTempFile() (*os.File, error) {
 	tempFile, err := os.CreateTemp("", "immudb")
 	if err == nil {
@@ -23,12 +23,32 @@ import (
 	"github.com/codenotary/immudb/embedded/store"
 )
 
+// ConflictGranularity determines which concurrent changes to the data read by a
+// read-write transaction make it fail with a read conflict when committing it.
+type ConflictGranularity int
+
+const (
+	// RowConflicts reports a conflict when any of the rows read within the transaction,
+	// or any row within the scanned ranges, was concurrently changed.
+	RowConflicts ConflictGranularity = iota
+
+	// PredicateConflicts only reports a conflict when the concurrently changed rows satisfy,
+	// either before or after the change, the conditions of the query reading them.
+	PredicateConflicts
+
+	// TableConflicts reports a conflict when any row of the tables read within the
+	// transaction was concurrently changed. Reads are not tracked individually.
+	TableConflicts
+)
+
 type TxOptions struct {
 	ReadOnly                bool
 	SnapshotMustIncludeTxID func(lastPrecommittedTxID uint64) uint64
 	SnapshotRenewalPeriod   time.Duration
 	ExplicitClose           bool
 	UnsafeMVCC              bool
+	ConflictGranularity     ConflictGranularity
+	SortedWrites            bool
 	Extra                   []byte
 }
 
@@ -41,6 +61,7 @@ func DefaultTxOptions() *TxOptions {
 		SnapshotRenewalPeriod:   txOpts.SnapshotRenewalPeriod,
 		ExplicitClose:           false, // commit or rollback explicitly required
 		UnsafeMVCC:              false, // mvcc restricted to catalog changes
+		ConflictGranularity:     RowConflicts,
 	}
 }
 
@@ -49,6 +70,10 @@ func (opts *TxOptions) Validate() error {
 		return fmt.Errorf("%w: nil options", store.ErrInvalidOptions)
 	}
 
+	if opts.ConflictGranularity < RowConflicts || opts.ConflictGranularity > TableConflicts {
+		return fmt.Errorf("%w: invalid conflict granularity", store.ErrInvalidOptions)
+	}
+
 	return nil
 }
 
@@ -77,6 +102,18 @@ func (opts *TxOptions) WithUnsafeMVCC(unsafeMVCC bool) *TxOptions {
 	return opts
 }
 
+func (opts *TxOptions) WithConflictGranularity(granularity ConflictGranularity) *TxOptions {
+	opts.ConflictGranularity = granularity
+	return opts
+}
+
+// WithSortedWrites makes the rows and index entries written by the transaction to be committed
+// in key order, thus the committed write set does not depend on the order in which rows were written
+func (opts *TxOptions) WithSortedWrites(sortedWrites bool) *TxOptions {
+	opts.SortedWrites = sortedWrites
+	return opts
+}
+
 func (opts *TxOptions) WithExtra(data []byte) *TxOptions {
 	opts.Extra = data
 	return opts
@@ -0,0 +1,227 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"runtime"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadersClosedByEndOfTx(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, "CREATE TABLE items (id INTEGER AUTO_INCREMENT, n INTEGER, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO items (n) VALUES (3), (1), (4), (1), (5), (9), (2), (6)", nil)
+	require.NoError(t, err)
+
+	queries := []string{
+		"SELECT * FROM items",
+		"SELECT id FROM items WHERE n % 2 = 1",
+		"SELECT n FROM items ORDER BY n DESC",
+		"SELECT n, COUNT(*) FROM items GROUP BY n",
+		"SELECT i1.id FROM items i1 INNER JOIN items i2 ON i1.n = i2.id",
+	}
+
+	openReaders := func(t *testing.T, tx *SQLTx) []RowReader {
+		var readers []RowReader
+
+		for _, q := range queries {
+			r, err := engine.Query(context.Background(), tx, q, nil)
+			require.NoError(t, err)
+
+			// readers being read and untouched ones are both closed
+			if len(readers)%2 == 0 {
+				_, err = r.Read(context.Background())
+				require.NoError(t, err)
+			}
+			readers = append(readers, r)
+		}
+		require.Len(t, tx.openReaders, len(queries))
+
+		return readers
+	}
+
+	requireClosed := func(t *testing.T, readers []RowReader) {
+		for _, r := range readers {
+			_, err := r.Read(context.Background())
+			require.ErrorIs(t, err, ErrReaderClosed)
+
+			require.Error(t, r.Close())
+		}
+	}
+
+	t.Run("rollback", func(t *testing.T) {
+		baseline := runtime.NumGoroutine()
+
+		tx, _, err := engine.Exec(context.Background(), nil, "BEGIN TRANSACTION", nil)
+		require.NoError(t, err)
+
+		readers := openReaders(t, tx)
+
+		_, _, err = engine.Exec(context.Background(), tx, "ROLLBACK", nil)
+		require.NoError(t, err)
+		require.Empty(t, tx.openReaders)
+
+		requireClosed(t, readers)
+
+		// no goroutine is left reading the rows
+		require.LessOrEqual(t, runtime.NumGoroutine(), baseline)
+	})
+
+	t.Run("commit", func(t *testing.T) {
+		tx, _, err := engine.Exec(context.Background(), nil, "BEGIN TRANSACTION", nil)
+		require.NoError(t, err)
+
+		readers := openReaders(t, tx)
+
+		_, _, err = engine.Exec(context.Background(), tx, "INSERT INTO items (n) VALUES (7)", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), tx, "COMMIT", nil)
+		require.NoError(t, err)
+		require.Empty(t, tx.openReaders)
+
+		requireClosed(t, readers)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT id FROM items WHERE n = 7", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+	})
+
+	t.Run("read-only transaction", func(t *testing.T) {
+		tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithReadOnly(true))
+		require.NoError(t, err)
+
+		readers := openReaders(t, tx)
+
+		require.NoError(t, tx.Cancel())
+		requireClosed(t, readers)
+	})
+
+	t.Run("readers closed before the end of the transaction", func(t *testing.T) {
+		tx, err := engine.NewTx(context.Background(), DefaultTxOptions())
+		require.NoError(t, err)
+
+		readers := openReaders(t, tx)
+
+		for _, r := range readers[1:] {
+			require.NoError(t, r.Close())
+		}
+		require.Len(t, tx.openReaders, 1)
+
+		require.NoError(t, tx.Cancel())
+		requireClosed(t, readers)
+	})
+
+	t.Run("readers of implicit transactions", func(t *testing.T) {
+		r, err := engine.Query(context.Background(), nil, queries[0], nil)
+		require.NoError(t, err)
+
+		require.Empty(t, r.Tx().openReaders)
+		require.NoError(t, r.Close())
+	})
+}
+
+func TestSelectForShare(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, "CREATE TABLE accounts (id INTEGER, balance INTEGER, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO accounts (id, balance) VALUES (1, 100), (2, 50)", nil)
+	require.NoError(t, err)
+
+	selectForShare := func(t *testing.T, sql string) *SQLTx {
+		tx, _, err := engine.Exec(context.Background(), nil, "BEGIN TRANSACTION", nil)
+		require.NoError(t, err)
+
+		rows, err := engine.queryAll(context.Background(), tx, sql, nil)
+		require.NoError(t, err)
+		require.NotEmpty(t, rows)
+
+		return tx
+	}
+
+	commit := func(tx *SQLTx) error {
+		_, _, err := engine.Exec(context.Background(), tx, "COMMIT", nil)
+		return err
+	}
+
+	t.Run("concurrent readers do not conflict", func(t *testing.T) {
+		tx1 := selectForShare(t, "SELECT balance FROM accounts WHERE id = 1 FOR SHARE")
+		tx2 := selectForShare(t, "SELECT balance FROM accounts WHERE id = 1 FOR SHARE")
+
+		require.NoError(t, commit(tx1))
+		require.NoError(t, commit(tx2))
+	})
+
+	t.Run("a concurrent writer conflicts", func(t *testing.T) {
+		tx := selectForShare(t, "SELECT balance FROM accounts WHERE id = 1 FOR SHARE")
+
+		_, _, err := engine.Exec(context.Background(), nil, "UPDATE accounts SET balance = 90 WHERE id = 1", nil)
+		require.NoError(t, err)
+
+		require.ErrorIs(t, commit(tx), store.ErrTxReadConflict)
+		require.True(t, tx.Closed())
+	})
+
+	t.Run("writes committed after the reader do not conflict", func(t *testing.T) {
+		tx := selectForShare(t, "SELECT balance FROM accounts WHERE id = 1 FOR SHARE")
+
+		require.NoError(t, commit(tx))
+
+		_, _, err := engine.Exec(context.Background(), nil, "UPDATE accounts SET balance = 80 WHERE id = 1", nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("reads without FOR SHARE do not conflict", func(t *testing.T) {
+		tx := selectForShare(t, "SELECT balance FROM accounts WHERE id = 1")
+
+		_, _, err := engine.Exec(context.Background(), nil, "UPDATE accounts SET balance = 70 WHERE id = 1", nil)
+		require.NoError(t, err)
+
+		require.NoError(t, commit(tx))
+	})
+
+	t.Run("updates to rows which were not read do not conflict", func(t *testing.T) {
+		tx := selectForShare(t, "SELECT balance FROM accounts WHERE id = 1 FOR SHARE")
+
+		_, _, err := engine.Exec(context.Background(), nil, "UPDATE accounts SET balance = 40 WHERE id = 2", nil)
+		require.NoError(t, err)
+
+		require.NoError(t, commit(tx))
+	})
+
+	t.Run("read-only transactions", func(t *testing.T) {
+		tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithReadOnly(true))
+		require.NoError(t, err)
+
+		_, err = engine.queryAll(context.Background(), tx, "SELECT balance FROM accounts FOR SHARE", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "UPDATE accounts SET balance = 60 WHERE id = 1", nil)
+		require.NoError(t, err)
+
+		require.NoError(t, tx.Cancel())
+	})
+}
@@ -24,7 +24,9 @@ import (
 	"errors"
 	"fmt"
 	"math"
+	"reflect"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -36,13 +38,19 @@ import (
 const (
 	catalogPrefix          = "CTL."
 	catalogTablePrefix     = "CTL.TABLE."     // (key=CTL.TABLE.{1}{tableID}, value={tableNAME})
-	catalogColumnPrefix    = "CTL.COLUMN."    // (key=CTL.COLUMN.{1}{tableID}{colID}{colTYPE}, value={(auto_incremental | nullable){maxLen}{colNAME}})
-	catalogIndexPrefix     = "CTL.INDEX."     // (key=CTL.INDEX.{1}{tableID}{indexID}, value={unique {colID1}(ASC|DESC)...{colIDN}(ASC|DESC)})
+	catalogColumnPrefix    = "CTL.COLUMN."    // (key=CTL.COLUMN.{1}{tableID}{colID}{colTYPE}, value={(auto_incremental | nullable | generated){maxLen}({colNAME} | {colNAMELen}{colNAME}{expText})})
+	catalogIndexPrefix     = "CTL.INDEX."     // (key=CTL.INDEX.{1}{tableID}{indexID}, value={(unique | fulltext) {colID1}(ASC|DESC)...{colIDN}(ASC|DESC)})
 	catalogCheckPrefix     = "CTL.CHECK."     // (key=CTL.CHECK.{1}{tableID}{checkID}, value={nameLen}{name}{expText})
+	catalogViewPrefix      = "CTL.VIEW."      // (key=CTL.VIEW.{1}{viewNAME}, value={queryText})
+	catalogCommentPrefix   = "CTL.COMMENT."   // (key=CTL.COMMENT.{1}{tableID}{colID}, value={comment}) colID=0 for the table
+	catalogTriggerPrefix   = "CTL.TRIGGER."   // (key=CTL.TRIGGER.{1}{tableID}{triggerNAME}, value={event}{stmtText})
 	catalogPrivilegePrefix = "CTL.PRIVILEGE." // (key=CTL.COLUMN.{1}{tableID}{colID}{colTYPE}, value={(auto_incremental | nullable){maxLen}{colNAME}})
+	catalogRowCountPrefix  = "CTL.ROWCOUNT."  // (key=CTL.ROWCOUNT.{1}{tableID}, value={rowCount})
 
 	RowPrefix    = "R." // (key=R.{1}{tableID}{0}({null}({pkVal}{padding}{pkValLen})?)+, value={count (colID valLen val)+})
 	MappedPrefix = "M." // (key=M.{tableID}{indexID}({null}({val}{padding}{valLen})?)*({pkVal}{padding}{pkValLen})+, value={count (colID valLen val)+})
+
+	FullTextPrefix = "FT." // (key=FT.{tableID}{indexID}{notnull}{term}{padding}{termLen}({pkVal}{padding}{pkValLen})+, value={})
 )
 
 const (
@@ -53,6 +61,15 @@ const (
 const (
 	nullableFlag      byte = 1 << iota
 	autoIncrementFlag byte = 1 << iota
+	generatedFlag     byte = 1 << iota
+)
+
+const (
+	uniqueIndexFlag        byte = 1 << iota
+	fullTextIndexFlag      byte = 1 << iota
+	rowCountIndexFlag      byte = 1 << iota // set on the primary index of the tables whose rows are counted
+	nullsDistinctIndexFlag byte = 1 << iota // set on the unique indexes whose entries with NULL values never conflict
+	canonicalKeysIndexFlag byte = 1 << iota // set on the secondary indexes whose keys are the same for values comparing as equal
 )
 
 const (
@@ -82,6 +99,7 @@ const (
 	TimestampType SQLValueType = "TIMESTAMP"
 	AnyType       SQLValueType = "ANY"
 	JSONType      SQLValueType = "JSON"
+	PointType     SQLValueType = "POINT"
 )
 
 func IsNumericType(t SQLValueType) bool {
@@ -503,7 +521,16 @@ func (stmt *CreateTableStmt) execAt(ctx context.Context, tx *SQLTx, params map[s
 	}
 
 	if stmt.ifNotExists && tx.catalog.ExistTable(stmt.table) {
-		return tx, nil
+		if !tx.engine.validateIfNotExists {
+			return tx, nil
+		}
+
+		table, err := tx.catalog.GetTableByName(stmt.table)
+		if err != nil {
+			return nil, err
+		}
+
+		return tx, stmt.matches(table)
 	}
 
 	colSpecs := make(map[uint32]*ColSpec, len(stmt.colsSpec))
@@ -541,6 +568,7 @@ func (stmt *CreateTableStmt) execAt(ctx context.Context, tx *SQLTx, params map[s
 	if err != nil {
 		return nil, err
 	}
+	table.countsRows = tx.engine.rowCounts
 
 	createIndexStmt := &CreateIndexStmt{unique: true, table: table.name, cols: stmt.primaryKeyCols()}
 	_, err = createIndexStmt.execAt(ctx, tx, params)
@@ -561,12 +589,23 @@ func (stmt *CreateTableStmt) execAt(ctx context.Context, tx *SQLTx, params map[s
 		}
 	}
 
+	err = validateGeneratedColumns(table)
+	if err != nil {
+		return nil, err
+	}
+
 	for _, check := range checks {
 		if err := persistCheck(tx, table, &check); err != nil {
 			return nil, err
 		}
 	}
 
+	if table.countsRows {
+		if err := tx.setRowCount(table, 0); err != nil {
+			return nil, err
+		}
+	}
+
 	mappedKey := MapKey(tx.sqlPrefix(), catalogTablePrefix, EncodeID(DatabaseID), EncodeID(table.id))
 
 	err = tx.set(mappedKey, nil, []byte(table.name))
@@ -600,6 +639,41 @@ func (stmt *CreateTableStmt) validatePrimaryKey() error {
 	return fmt.Errorf("\"%s\": %w", stmt.table, ErrMultiplePrimaryKeys)
 }
 
+// matches returns ErrSchemaMismatch if the columns or the primary key of the
+// existing table differ from the ones specified by the statement
+func (stmt *CreateTableStmt) matches(table *Table) error {
+	if len(table.cols) != len(stmt.colsSpec) {
+		return fmt.Errorf("%w: table '%s' has %d columns but %d were specified", ErrSchemaMismatch, table.name, len(table.cols), len(stmt.colsSpec))
+	}
+
+	for i, spec := range stmt.colsSpec {
+		col := table.cols[i]
+
+		if col.colName != spec.colName ||
+			col.colType != spec.colType ||
+			col.maxLen != spec.maxLen ||
+			col.autoIncrement != spec.autoIncrement ||
+			col.notNull != spec.notNull ||
+			!sameGeneratedExp(col.generatedExp, spec.generatedExp) {
+			return fmt.Errorf("%w: column '%s' of table '%s' does not match the specified column '%s'", ErrSchemaMismatch, col.colName, table.name, spec.colName)
+		}
+	}
+
+	pkCols := stmt.primaryKeyCols()
+
+	if len(pkCols) != len(table.primaryIndex.cols) {
+		return fmt.Errorf("%w: primary key of table '%s' does not match the specified one", ErrSchemaMismatch, table.name)
+	}
+
+	for i, col := range table.primaryIndex.cols {
+		if col.colName != pkCols[i] {
+			return fmt.Errorf("%w: primary key of table '%s' does not match the specified one", ErrSchemaMismatch, table.name)
+		}
+	}
+
+	return nil
+}
+
 func (stmt *CreateTableStmt) primaryKeyCols() []string {
 	if len(stmt.pkColNames) > 0 {
 		return stmt.pkColNames
@@ -614,7 +688,7 @@ func (stmt *CreateTableStmt) primaryKeyCols() []string {
 }
 
 func persistColumn(tx *SQLTx, col *Column) error {
-	//{auto_incremental | nullable}{maxLen}{colNAME})
+	//{auto_incremental | nullable | generated}{maxLen}{colNAME})
 	v := make([]byte, 1+4+len(col.colName))
 
 	if col.autoIncrement {
@@ -629,6 +703,18 @@ func persistColumn(tx *SQLTx, col *Column) error {
 
 	copy(v[5:], []byte(col.Name()))
 
+	if col.generatedExp != nil {
+		// {generated}{maxLen}{colNAMELen}{colNAME}{expText}
+		v[0] = v[0] | generatedFlag
+
+		expText := col.generatedExp.String()
+
+		v = append(v[:5], make([]byte, 4+len(col.colName)+len(expText))...)
+		binary.BigEndian.PutUint32(v[5:], uint32(len(col.colName)))
+		copy(v[9:], []byte(col.colName))
+		copy(v[9+len(col.colName):], []byte(expText))
+	}
+
 	mappedKey := MapKey(
 		tx.sqlPrefix(),
 		catalogColumnPrefix,
@@ -674,6 +760,7 @@ type ColSpec struct {
 	autoIncrement bool
 	notNull       bool
 	primaryKey    bool
+	generatedExp  ValueExp
 }
 
 func NewColSpec(name string, colType SQLValueType, maxLen int, autoIncrement bool, notNull bool) *ColSpec {
@@ -687,10 +774,12 @@ func NewColSpec(name string, colType SQLValueType, maxLen int, autoIncrement boo
 }
 
 type CreateIndexStmt struct {
-	unique      bool
-	ifNotExists bool
-	table       string
-	cols        []string
+	unique           bool
+	nullsNotDistinct bool
+	fullText         bool
+	ifNotExists      bool
+	table            string
+	cols             []string
 }
 
 func NewCreateIndexStmt(table string, cols []string, isUnique bool) *CreateIndexStmt {
@@ -723,6 +812,11 @@ func (stmt *CreateIndexStmt) execAt(ctx context.Context, tx *SQLTx, params map[s
 		return nil, err
 	}
 
+	if stmt.fullText {
+		return stmt.createFullTextIndex(ctx, tx, table)
+	}
+
+	cols := make([]*Column, len(stmt.cols))
 	colIDs := make([]uint32, len(stmt.cols))
 
 	indexKeyLen := 0
@@ -733,6 +827,8 @@ func (stmt *CreateIndexStmt) execAt(ctx context.Context, tx *SQLTx, params map[s
 			return nil, err
 		}
 
+		cols[i] = col
+
 		if col.Type() == JSONType {
 			return nil, ErrCannotIndexJson
 		}
@@ -752,7 +848,7 @@ func (stmt *CreateIndexStmt) execAt(ctx context.Context, tx *SQLTx, params map[s
 
 	if stmt.unique && table.primaryIndex != nil {
 		// check table is empty
-		pkPrefix := MapKey(tx.sqlPrefix(), MappedPrefix, EncodeID(table.id), EncodeID(table.primaryIndex.id))
+		pkPrefix := table.primaryIndex.entriesPrefix()
 		_, _, err := tx.getWithPrefix(ctx, pkPrefix, nil)
 		if errors.Is(err, store.ErrIndexNotFound) {
 			return nil, ErrTableDoesNotExist
@@ -766,12 +862,35 @@ func (stmt *CreateIndexStmt) execAt(ctx context.Context, tx *SQLTx, params map[s
 
 	index, err := table.newIndex(stmt.unique, colIDs)
 	if errors.Is(err, ErrIndexAlreadyExists) && stmt.ifNotExists {
+		if !tx.engine.validateIfNotExists {
+			return tx, nil
+		}
+
+		existingIndex, err := table.GetIndexByName(indexName(table.name, cols))
+		if err != nil {
+			return nil, err
+		}
+
+		if existingIndex.IsUnique() != stmt.unique {
+			return nil, fmt.Errorf("%w: index '%s' does not match the specified uniqueness", ErrSchemaMismatch, existingIndex.Name())
+		}
+
+		// unique indexes created by previous releases treat NULL values as equal,
+		// thus they also match the specification of unique indexes by default
+		if stmt.nullsNotDistinct && existingIndex.NullsDistinct() {
+			return nil, fmt.Errorf("%w: index '%s' does not match the specified handling of NULL values", ErrSchemaMismatch, existingIndex.Name())
+		}
 		return tx, nil
 	}
 	if err != nil {
 		return nil, err
 	}
 
+	index.nullsDistinct = index.IsUnique() && !index.IsPrimary() && !stmt.nullsNotDistinct
+
+	// primary keys keep their encoding, as clients verifying rows encode them with EncodeRawValueAsKey
+	index.canonicalKeys = !index.IsPrimary()
+
 	// v={unique {colID1}(ASC|DESC)...{colIDN}(ASC|DESC)}
 	// TODO: currently only ASC order is supported
 	colSpecLen := EncIDLen + 1
@@ -779,7 +898,19 @@ func (stmt *CreateIndexStmt) execAt(ctx context.Context, tx *SQLTx, params map[s
 	encodedValues := make([]byte, 1+len(index.cols)*colSpecLen)
 
 	if index.IsUnique() {
-		encodedValues[0] = 1
+		encodedValues[0] = uniqueIndexFlag
+	}
+
+	if index.NullsDistinct() {
+		encodedValues[0] |= nullsDistinctIndexFlag
+	}
+
+	if index.canonicalKeys {
+		encodedValues[0] |= canonicalKeysIndexFlag
+	}
+
+	if index.IsPrimary() && table.countsRows {
+		encodedValues[0] |= rowCountIndexFlag
 	}
 
 	for i, col := range index.cols {
@@ -970,12 +1101,23 @@ func (stmt *DropColumnStmt) execAt(ctx context.Context, tx *SQLTx, params map[st
 		return nil, err
 	}
 
+	if col.comment != "" {
+		err = persistCommentDeletion(ctx, tx, table.id, col.id)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	tx.mutatedCatalog = true
 
 	return tx, nil
 }
 
 func canDropColumn(tx *SQLTx, table *Table, col *Column) error {
+	if genCol := table.generatedColumnUsing(col); genCol != nil {
+		return fmt.Errorf("%w %s because generated column %s requires it", ErrCannotDropColumn, col.Name(), genCol.Name())
+	}
+
 	colSpecs := make([]*ColSpec, 0, len(table.Cols())-1)
 	for _, c := range table.cols {
 		if c.id != col.id {
@@ -1151,6 +1293,10 @@ func (stmt *UpsertIntoStmt) validate(table *Table) (map[uint32]int, error) {
 			return nil, fmt.Errorf("%w (%s)", ErrDuplicatedColumn, col.colName)
 		}
 
+		if col.IsGenerated() {
+			return nil, fmt.Errorf("%w (%s)", ErrGeneratedColumnCanNotBeSet, col.colName)
+		}
+
 		selPosByColID[col.id] = i
 	}
 
@@ -1179,6 +1325,12 @@ func (stmt *UpsertIntoStmt) execAt(ctx context.Context, tx *SQLTx, params map[st
 	}
 	defer reader.Close()
 
+	fireOnInsert := len(table.triggersOn(TriggerOnInsert)) > 0
+	fireOnUpdate := !stmt.isInsert && len(table.triggersOn(TriggerOnUpdate)) > 0
+
+	var inserted, updated []rowChange
+	var newRows int64
+
 	for {
 		row, err := reader.Read(ctx)
 		if errors.Is(err, ErrNoMoreRows) {
@@ -1199,6 +1351,11 @@ func (stmt *UpsertIntoStmt) execAt(ctx context.Context, tx *SQLTx, params map[st
 		for colID, col := range table.colsByID {
 			colPos, specified := selPosByColID[colID]
 			if !specified {
+				if col.IsGenerated() {
+					// computed once the rest of the values are known
+					continue
+				}
+
 				// TODO: Default values
 				if col.notNull && !col.autoIncrement {
 					return nil, fmt.Errorf("%w (%s)", ErrNotNullableColumnCannotBeNull, col.colName)
@@ -1278,6 +1435,14 @@ func (stmt *UpsertIntoStmt) execAt(ctx context.Context, tx *SQLTx, params map[st
 			r.ValuesBySelector[EncodeSelector("", table.name, col.colName)] = v
 		}
 
+		if err := tx.interceptWrite(ctx, table, r, valuesByColID); err != nil {
+			return nil, err
+		}
+
+		if err := computeGeneratedColumns(tx, table, r, valuesByColID); err != nil {
+			return nil, err
+		}
+
 		if err := checkConstraints(tx, table.checkConstraints, r, table.name); err != nil {
 			return nil, err
 		}
@@ -1288,12 +1453,12 @@ func (stmt *UpsertIntoStmt) execAt(ctx context.Context, tx *SQLTx, params map[st
 		}
 
 		// pk entry
-		mappedPKey := MapKey(tx.sqlPrefix(), MappedPrefix, EncodeID(table.id), EncodeID(table.primaryIndex.id), pkEncVals, pkEncVals)
+		mappedPKey := table.primaryIndex.entryKey(pkEncVals, pkEncVals)
 		if len(mappedPKey) > MaxKeyLen {
 			return nil, ErrMaxKeyLengthExceeded
 		}
 
-		_, err = tx.get(ctx, mappedPKey)
+		vref, err := tx.get(ctx, mappedPKey)
 		if err != nil && !errors.Is(err, store.ErrKeyNotFound) {
 			return nil, err
 		}
@@ -1313,11 +1478,47 @@ func (stmt *UpsertIntoStmt) execAt(ctx context.Context, tx *SQLTx, params map[st
 			}
 		}
 
+		// rows replaced by an upsert are updated
+		if err == nil && fireOnUpdate {
+			encodedRow, err := vref.Resolve()
+			if err != nil {
+				return nil, err
+			}
+
+			oldValuesByColID, err := decodeRowValues(table, encodedRow)
+			if err != nil {
+				return nil, err
+			}
+
+			updated = append(updated, rowChange{old: oldValuesByColID, new: valuesByColID})
+		} else if err != nil && fireOnInsert {
+			inserted = append(inserted, rowChange{new: valuesByColID})
+		}
+
+		if err != nil {
+			newRows++
+		}
+
 		err = tx.doUpsert(ctx, pkEncVals, valuesByColID, table, !stmt.isInsert)
 		if err != nil {
 			return nil, err
 		}
 	}
+
+	err = tx.addRowCount(ctx, table, newRows)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.fireTriggers(ctx, table, TriggerOnInsert, inserted)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.fireTriggers(ctx, table, TriggerOnUpdate, updated)
+	if err != nil {
+		return nil, err
+	}
 	return tx, nil
 }
 
@@ -1383,16 +1584,17 @@ func (tx *SQLTx) encodeRowValue(valuesByColID map[uint32]TypedValue, table *Tabl
 		}
 	}
 
-	return valbuf.Bytes(), nil
+	return table.appendRowChecksum(valbuf.Bytes()), nil
 }
 
 func (tx *SQLTx) doUpsert(ctx context.Context, pkEncVals []byte, valuesByColID map[uint32]TypedValue, table *Table, reuseIndex bool) error {
 	var reusableIndexEntries map[uint32]struct{}
+	var currValuesByColID map[uint32]TypedValue
 
-	if reuseIndex && len(table.indexes) > 1 {
+	if reuseIndex && (len(table.indexes) > 1 || len(table.fullTextIndexes) > 0) {
 		currPKRow, err := tx.fetchPKRow(ctx, table, valuesByColID)
 		if err == nil {
-			currValuesByColID := make(map[uint32]TypedValue, len(currPKRow.ValuesBySelector))
+			currValuesByColID = make(map[uint32]TypedValue, len(currPKRow.ValuesBySelector))
 
 			for _, col := range table.cols {
 				encSel := EncodeSelector("", table.name, col.colName)
@@ -1408,7 +1610,7 @@ func (tx *SQLTx) doUpsert(ctx context.Context, pkEncVals []byte, valuesByColID m
 		}
 	}
 
-	rowKey := MapKey(tx.sqlPrefix(), RowPrefix, EncodeID(DatabaseID), EncodeID(table.id), EncodeID(PKIndexID), pkEncVals)
+	rowKey := table.rowKey(pkEncVals)
 
 	encodedRowValue, err := tx.encodeRowValue(valuesByColID, table)
 	if err != nil {
@@ -1433,19 +1635,21 @@ func (tx *SQLTx) doUpsert(ctx context.Context, pkEncVals []byte, valuesByColID m
 			}
 		}
 
-		encodedValues := make([][]byte, 2+len(index.cols))
-		encodedValues[0] = EncodeID(table.id)
-		encodedValues[1] = EncodeID(index.id)
+		encodedValues := make([][]byte, len(index.cols))
 
 		indexKeyLen := 0
 
+		hasNulls := false
+
 		for i, col := range index.cols {
 			rval, specified := valuesByColID[col.id]
 			if !specified {
 				rval = &NullValue{t: col.colType}
 			}
 
-			encVal, n, err := EncodeValueAsKey(rval, col.colType, col.MaxLen())
+			hasNulls = hasNulls || rval.IsNull()
+
+			encVal, n, err := index.encodeValueAsKey(rval, col)
 			if err != nil {
 				return fmt.Errorf("%w: index on '%s' and column '%s'", err, index.Name(), col.colName)
 			}
@@ -1456,17 +1660,17 @@ func (tx *SQLTx) doUpsert(ctx context.Context, pkEncVals []byte, valuesByColID m
 
 			indexKeyLen += n
 
-			encodedValues[i+2] = encVal
+			encodedValues[i] = encVal
 		}
 
 		if indexKeyLen > MaxKeyLen {
 			return fmt.Errorf("%w: can not index entry using columns '%v'. Max key length is %d", ErrLimitedKeyType, index.cols, MaxKeyLen)
 		}
 
-		smkey := MapKey(tx.sqlPrefix(), MappedPrefix, encodedValues...)
+		smkey := index.entryKey(encodedValues...)
 
 		// no other equivalent entry should be already indexed
-		if index.IsUnique() {
+		if index.IsUnique() && !(hasNulls && index.NullsDistinct()) {
 			_, valRef, err := tx.getWithPrefix(ctx, smkey, nil)
 			if err == nil && (valRef.KVMetadata() == nil || !valRef.KVMetadata().Deleted()) {
 				return store.ErrKeyAlreadyExists
@@ -1481,6 +1685,11 @@ func (tx *SQLTx) doUpsert(ctx context.Context, pkEncVals []byte, valuesByColID m
 		}
 	}
 
+	err = tx.updateFullTextEntries(pkEncVals, table, currValuesByColID, valuesByColID)
+	if err != nil {
+		return err
+	}
+
 	tx.updatedRows++
 
 	return nil
@@ -1497,7 +1706,7 @@ func encodedKey(index *Index, valuesByColID map[uint32]TypedValue) ([]byte, erro
 			return nil, ErrPKCanNotBeNull
 		}
 
-		encVal, n, err := EncodeValueAsKey(rval, col.colType, col.MaxLen())
+		encVal, n, err := index.encodeValueAsKey(rval, col)
 		if err != nil {
 			return nil, fmt.Errorf("%w: index of table '%s' and column '%s'", err, index.table.name, col.colName)
 		}
@@ -1568,9 +1777,7 @@ func (tx *SQLTx) deprecateIndexEntries(
 			continue
 		}
 
-		encodedValues := make([][]byte, 2+len(index.cols)+1)
-		encodedValues[0] = EncodeID(table.id)
-		encodedValues[1] = EncodeID(index.id)
+		encodedValues := make([][]byte, len(index.cols)+1)
 		encodedValues[len(encodedValues)-1] = pkEncVals
 
 		// existent index entry is deleted only if it differs from existent one
@@ -1594,9 +1801,9 @@ func (tx *SQLTx) deprecateIndexEntries(
 
 			sameIndexKey = sameIndexKey && r == 0
 
-			encVal, _, _ := EncodeValueAsKey(currVal, col.colType, col.MaxLen())
+			encVal, _, _ := index.encodeValueAsKey(currVal, col)
 
-			encodedValues[i+3] = encVal
+			encodedValues[i+1] = encVal
 		}
 
 		// mark existent index entry as deleted
@@ -1607,7 +1814,7 @@ func (tx *SQLTx) deprecateIndexEntries(
 
 			md.AsDeleted(true)
 
-			err = tx.set(MapKey(tx.sqlPrefix(), MappedPrefix, encodedValues...), md, encodedRowValue)
+			err = tx.set(index.entryKey(encodedValues...), md, encodedRowValue)
 			if err != nil {
 				return nil, err
 			}
@@ -1688,6 +1895,10 @@ func (stmt *UpdateStmt) validate(table *Table) error {
 			return ErrPKCanNotBeUpdated
 		}
 
+		if col.IsGenerated() {
+			return fmt.Errorf("%w (%s)", ErrGeneratedColumnCanNotBeSet, col.colName)
+		}
+
 		_, duplicated := colIDs[col.id]
 		if duplicated {
 			return ErrDuplicatedColumn
@@ -1708,7 +1919,10 @@ func (stmt *UpdateStmt) execAt(ctx context.Context, tx *SQLTx, params map[string
 		offset:  stmt.offset,
 	}
 
+	// rows are written back as they're stored, not as transformed by the middleware of the table
+	tx.storedReads++
 	rowReader, err := selectStmt.Resolve(ctx, tx, params, nil)
+	tx.storedReads--
 	if err != nil {
 		return nil, err
 	}
@@ -1726,6 +1940,10 @@ func (stmt *UpdateStmt) execAt(ctx context.Context, tx *SQLTx, params map[string
 		return nil, err
 	}
 
+	fireOnUpdate := len(table.triggersOn(TriggerOnUpdate)) > 0
+
+	var updated []rowChange
+
 	for {
 		row, err := rowReader.Read(ctx)
 		if errors.Is(err, ErrNoMoreRows) {
@@ -1741,6 +1959,14 @@ func (stmt *UpdateStmt) execAt(ctx context.Context, tx *SQLTx, params map[string
 			valuesByColID[col.id] = row.ValuesBySelector[encSel]
 		}
 
+		var oldValuesByColID map[uint32]TypedValue
+		if fireOnUpdate {
+			oldValuesByColID = make(map[uint32]TypedValue, len(valuesByColID))
+			for id, v := range valuesByColID {
+				oldValuesByColID[id] = v
+			}
+		}
+
 		for _, update := range stmt.updates {
 			col, err := table.GetColumnByName(update.col)
 			if err != nil {
@@ -1772,6 +1998,15 @@ func (stmt *UpdateStmt) execAt(ctx context.Context, tx *SQLTx, params map[string
 			row.ValuesBySelector[EncodeSelector("", table.name, col.colName)] = v
 		}
 
+		if err := tx.interceptWrite(ctx, table, row, valuesByColID); err != nil {
+			return nil, err
+		}
+
+		// generated columns are computed from the updated values
+		if err := computeGeneratedColumns(tx, table, row, valuesByColID); err != nil {
+			return nil, err
+		}
+
 		if err := checkConstraints(tx, table.checkConstraints, row, table.name); err != nil {
 			return nil, err
 		}
@@ -1782,7 +2017,7 @@ func (stmt *UpdateStmt) execAt(ctx context.Context, tx *SQLTx, params map[string
 		}
 
 		// primary index entry
-		mkey := MapKey(tx.sqlPrefix(), MappedPrefix, EncodeID(table.id), EncodeID(table.primaryIndex.id), pkEncVals, pkEncVals)
+		mkey := table.primaryIndex.entryKey(pkEncVals, pkEncVals)
 
 		// mkey must exist
 		_, err = tx.get(ctx, mkey)
@@ -1794,8 +2029,16 @@ func (stmt *UpdateStmt) execAt(ctx context.Context, tx *SQLTx, params map[string
 		if err != nil {
 			return nil, err
 		}
+
+		if fireOnUpdate {
+			updated = append(updated, rowChange{old: oldValuesByColID, new: valuesByColID})
+		}
 	}
 
+	err = tx.fireTriggers(ctx, table, TriggerOnUpdate, updated)
+	if err != nil {
+		return nil, err
+	}
 	return tx, nil
 }
 
@@ -1835,6 +2078,10 @@ func (stmt *DeleteFromStmt) inferParameters(ctx context.Context, tx *SQLTx, para
 }
 
 func (stmt *DeleteFromStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
+	if table, pkRange, ok := stmt.primaryKeyRange(tx, params); ok {
+		return stmt.deleteRange(ctx, tx, table, pkRange)
+	}
+
 	selectStmt := &SelectStmt{
 		ds:      stmt.tableRef,
 		where:   stmt.where,
@@ -1844,7 +2091,10 @@ func (stmt *DeleteFromStmt) execAt(ctx context.Context, tx *SQLTx, params map[st
 		offset:  stmt.offset,
 	}
 
+	// the index entries of deleted rows are found from their values as stored
+	tx.storedReads++
 	rowReader, err := selectStmt.Resolve(ctx, tx, params, nil)
+	tx.storedReads--
 	if err != nil {
 		return nil, err
 	}
@@ -1852,6 +2102,11 @@ func (stmt *DeleteFromStmt) execAt(ctx context.Context, tx *SQLTx, params map[st
 
 	table := rowReader.ScanSpecs().Index.table
 
+	fireOnDelete := len(table.triggersOn(TriggerOnDelete)) > 0
+
+	var deleted []rowChange
+	var deletedRows int64
+
 	for {
 		row, err := rowReader.Read(ctx)
 		if errors.Is(err, ErrNoMoreRows) {
@@ -1879,6 +2134,21 @@ func (stmt *DeleteFromStmt) execAt(ctx context.Context, tx *SQLTx, params map[st
 		}
 
 		tx.updatedRows++
+		deletedRows++
+
+		if fireOnDelete {
+			deleted = append(deleted, rowChange{old: valuesByColID})
+		}
+	}
+
+	err = tx.addRowCount(ctx, table, -deletedRows)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.fireTriggers(ctx, table, TriggerOnDelete, deleted)
+	if err != nil {
+		return nil, err
 	}
 	return tx, nil
 }
@@ -1894,10 +2164,7 @@ func (tx *SQLTx) deleteIndexEntries(pkEncVals []byte, valuesByColID map[uint32]T
 			continue
 		}
 
-		encodedValues := make([][]byte, 3+len(index.cols))
-		encodedValues[0] = EncodeID(DatabaseID)
-		encodedValues[1] = EncodeID(table.id)
-		encodedValues[2] = EncodeID(index.id)
+		encodedValues := make([][]byte, len(index.cols))
 
 		for i, col := range index.cols {
 			val, specified := valuesByColID[col.id]
@@ -1905,22 +2172,22 @@ func (tx *SQLTx) deleteIndexEntries(pkEncVals []byte, valuesByColID map[uint32]T
 				val = &NullValue{t: col.colType}
 			}
 
-			encVal, _, _ := EncodeValueAsKey(val, col.colType, col.MaxLen())
+			encVal, _, _ := index.encodeValueAsKey(val, col)
 
-			encodedValues[i+3] = encVal
+			encodedValues[i] = encVal
 		}
 
 		md := store.NewKVMetadata()
 
 		md.AsDeleted(true)
 
-		err := tx.set(MapKey(tx.sqlPrefix(), RowPrefix, encodedValues...), md, encodedRowValue)
+		err := tx.set(table.rowKey(encodedValues...), md, encodedRowValue)
 		if err != nil {
 			return err
 		}
 	}
 
-	return nil
+	return tx.updateFullTextEntries(pkEncVals, table, valuesByColID, nil)
 }
 
 type ValueExp interface {
@@ -1929,6 +2196,9 @@ type ValueExp interface {
 	substitute(params map[string]interface{}) (ValueExp, error)
 	selectors() []Selector
 	reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error)
+	// reduceCtx evaluates the expression as reduce does, but the evaluation may be interrupted
+	// once the context is done
+	reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error)
 	reduceSelectors(row *Row, implicitTable string) ValueExp
 	isConstant() bool
 	selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error
@@ -2086,7 +2356,11 @@ func (n *NullValue) String() string {
 }
 
 func (n *NullValue) Compare(val TypedValue) (int, error) {
-	if n.t != AnyType && val.Type() != AnyType && n.t != val.Type() {
+	// NULL values of a numeric type are comparable to values of any numeric type, as those are to each other
+	compatible := n.t == AnyType || val.Type() == AnyType || n.t == val.Type() ||
+		(IsNumericType(n.t) && IsNumericType(val.Type()))
+
+	if !compatible {
 		return 0, ErrNotComparableValues
 	}
 
@@ -2123,6 +2397,10 @@ func (v *NullValue) substitute(params map[string]interface{}) (ValueExp, error)
 }
 
 func (v *NullValue) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return v.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (v *NullValue) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
 	return v, nil
 }
 
@@ -2178,6 +2456,10 @@ func (v *Integer) substitute(params map[string]interface{}) (ValueExp, error) {
 }
 
 func (v *Integer) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return v.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (v *Integer) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
 	return v, nil
 }
 
@@ -2208,8 +2490,7 @@ func (v *Integer) Compare(val TypedValue) (int, error) {
 	}
 
 	if val.Type() == Float64Type {
-		r, err := val.Compare(v)
-		return r * -1, err
+		return compareIntegerToFloat(v.val, val.RawValue().(float64)), nil
 	}
 
 	if val.Type() != IntegerType {
@@ -2233,6 +2514,10 @@ type Timestamp struct {
 	val time.Time
 }
 
+func NewTimestamp(val time.Time) *Timestamp {
+	return &Timestamp{val: val}
+}
+
 func (v *Timestamp) Type() SQLValueType {
 	return TimestampType
 }
@@ -2266,6 +2551,10 @@ func (v *Timestamp) substitute(params map[string]interface{}) (ValueExp, error)
 }
 
 func (v *Timestamp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return v.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (v *Timestamp) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
 	return v, nil
 }
 
@@ -2347,6 +2636,10 @@ func (v *Varchar) substitute(params map[string]interface{}) (ValueExp, error) {
 }
 
 func (v *Varchar) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return v.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (v *Varchar) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
 	return v, nil
 }
 
@@ -2371,7 +2664,8 @@ func (v *Varchar) Compare(val TypedValue) (int, error) {
 		return 1, nil
 	}
 
-	if val.Type() == JSONType {
+	switch val.Type() {
+	case JSONType, UUIDType, Float64Type:
 		res, err := val.Compare(v)
 		return -res, err
 	}
@@ -2426,6 +2720,10 @@ func (v *UUID) substitute(params map[string]interface{}) (ValueExp, error) {
 }
 
 func (v *UUID) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return v.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (v *UUID) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
 	return v, nil
 }
 
@@ -2450,6 +2748,15 @@ func (v *UUID) Compare(val TypedValue) (int, error) {
 		return 1, nil
 	}
 
+	// strings are compared once parsed, as when they are assigned to UUID columns
+	if val.Type() == VarcharType {
+		convVal, err := mayApplyImplicitConversion(val.RawValue(), UUIDType)
+		if err != nil {
+			return 0, err
+		}
+		val = &UUID{val: convVal.(uuid.UUID)}
+	}
+
 	if val.Type() != UUIDType {
 		return 0, ErrNotComparableValues
 	}
@@ -2499,6 +2806,10 @@ func (v *Bool) substitute(params map[string]interface{}) (ValueExp, error) {
 }
 
 func (v *Bool) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return v.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (v *Bool) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
 	return v, nil
 }
 
@@ -2586,6 +2897,10 @@ func (v *Blob) substitute(params map[string]interface{}) (ValueExp, error) {
 }
 
 func (v *Blob) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return v.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (v *Blob) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
 	return v, nil
 }
 
@@ -2605,6 +2920,8 @@ func (v *Blob) RawValue() interface{} {
 	return v.val
 }
 
+// Compare orders blobs lexicographically by their bytes, the same order
+// of the keys of the index entries holding them
 func (v *Blob) Compare(val TypedValue) (int, error) {
 	if val.IsNull() {
 		return 1, nil
@@ -2659,6 +2976,10 @@ func (v *Float64) substitute(params map[string]interface{}) (ValueExp, error) {
 }
 
 func (v *Float64) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return v.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (v *Float64) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
 	return v, nil
 }
 
@@ -2679,54 +3000,63 @@ func (v *Float64) RawValue() interface{} {
 }
 
 func (v *Float64) Compare(val TypedValue) (int, error) {
-	if val.Type() == JSONType {
-		res, err := val.Compare(v)
-		return -res, err
-	}
-
-	convVal, err := mayApplyImplicitConversion(val.RawValue(), Float64Type)
-	if err != nil {
-		return 0, err
-	}
-
-	if convVal == nil {
+	if val.IsNull() {
 		return 1, nil
 	}
 
-	rval, ok := convVal.(float64)
-	if !ok {
-		return 0, ErrNotComparableValues
-	}
-
-	if v.val == rval {
-		return 0, nil
-	}
-
-	if v.val > rval {
-		return 1, nil
+	switch val.Type() {
+	case JSONType:
+		res, err := val.Compare(v)
+		return -res, err
+	case IntegerType:
+		return -compareIntegerToFloat(val.RawValue().(int64), v.val), nil
+	case Float64Type:
+		return compareFloats(v.val, val.RawValue().(float64)), nil
+	case VarcharType:
+		convVal, err := mayApplyImplicitConversion(val.RawValue(), Float64Type)
+		if err != nil {
+			return 0, err
+		}
+		return compareFloats(v.val, convVal.(float64)), nil
 	}
-
-	return -1, nil
+	return 0, ErrNotComparableValues
 }
 
 type FnCall struct {
 	fn     string
 	params []ValueExp
+
+	// functions holds the user-defined functions available when the statement was parsed
+	functions *functionRegistry
 }
 
 func (v *FnCall) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
-	fn, err := v.resolveFunc()
+	fn, err := v.resolveFunc(nil)
 	if err != nil {
 		return AnyType, nil
 	}
+
+	if ufn, ok := fn.(*userFunction); ok {
+		err := ufn.inferArgTypes(v.params, cols, params, implicitTable)
+		if err != nil {
+			return AnyType, err
+		}
+	}
 	return fn.InferType(cols, params, implicitTable)
 }
 
 func (v *FnCall) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
-	fn, err := v.resolveFunc()
+	fn, err := v.resolveFunc(nil)
 	if err != nil {
 		return err
 	}
+
+	if ufn, ok := fn.(*userFunction); ok {
+		err := ufn.inferArgTypes(v.params, cols, params, implicitTable)
+		if err != nil {
+			return err
+		}
+	}
 	return fn.RequiresType(t, cols, params, implicitTable)
 }
 
@@ -2748,30 +3078,41 @@ func (v *FnCall) substitute(params map[string]interface{}) (val ValueExp, err er
 	}
 
 	return &FnCall{
-		fn:     v.fn,
-		params: ps,
+		fn:        v.fn,
+		params:    ps,
+		functions: v.functions,
 	}, nil
 }
 
 func (v *FnCall) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
-	fn, err := v.resolveFunc()
+	return v.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (v *FnCall) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	fn, err := v.resolveFunc(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	fnInputs, err := v.reduceParams(ctx, tx, row, implicitTable)
 	if err != nil {
 		return nil, err
 	}
 
-	fnInputs, err := v.reduceParams(tx, row, implicitTable)
+	// functions may be expensive to evaluate, thus they're not applied once the context is done
+	err = ctx.Err()
 	if err != nil {
 		return nil, err
 	}
 	return fn.Apply(tx, fnInputs)
 }
 
-func (v *FnCall) reduceParams(tx *SQLTx, row *Row, implicitTable string) ([]TypedValue, error) {
+func (v *FnCall) reduceParams(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) ([]TypedValue, error) {
 	var values []TypedValue
 	if len(v.params) > 0 {
 		values = make([]TypedValue, len(v.params))
 		for i, p := range v.params {
-			v, err := p.reduce(tx, row, implicitTable)
+			v, err := p.reduceCtx(ctx, tx, row, implicitTable)
 			if err != nil {
 				return nil, err
 			}
@@ -2781,12 +3122,28 @@ func (v *FnCall) reduceParams(tx *SQLTx, row *Row, implicitTable string) ([]Type
 	return values, nil
 }
 
-func (v *FnCall) resolveFunc() (Function, error) {
-	fn, exists := builtinFunctions[strings.ToUpper(v.fn)]
-	if !exists {
-		return nil, fmt.Errorf("%w: unknown function %s", ErrIllegalArguments, v.fn)
+// resolveFunc looks up built-in functions first, then user-defined functions bound
+// at parsing time and finally the ones registered in the engine of the transaction.
+func (v *FnCall) resolveFunc(tx *SQLTx) (Function, error) {
+	fnName := strings.ToUpper(v.fn)
+
+	fn, exists := builtinFunctions[fnName]
+	if exists {
+		return fn, nil
 	}
-	return fn, nil
+
+	fn, exists = v.functions.get(fnName)
+	if exists {
+		return fn, nil
+	}
+
+	if tx != nil && tx.engine != nil {
+		fn, exists = tx.engine.functions.get(fnName)
+		if exists {
+			return fn, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: unknown function %s", ErrIllegalArguments, v.fn)
 }
 
 func (v *FnCall) reduceSelectors(row *Row, implicitTable string) ValueExp {
@@ -2843,7 +3200,11 @@ func (c *Cast) substitute(params map[string]interface{}) (ValueExp, error) {
 }
 
 func (c *Cast) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
-	val, err := c.val.reduce(tx, row, implicitTable)
+	return c.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (c *Cast) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	val, err := c.val.reduceCtx(ctx, tx, row, implicitTable)
 	if err != nil {
 		return nil, err
 	}
@@ -2910,12 +3271,21 @@ func (p *Param) substitute(params map[string]interface{}) (ValueExp, error) {
 	if !ok {
 		return nil, fmt.Errorf("%w(%s)", ErrMissingParameter, p.id)
 	}
+	return paramValue(val)
+}
 
+// paramValue returns the value bound to a parameter as a typed value
+func paramValue(val interface{}) (ValueExp, error) {
 	if val == nil {
 		return &NullValue{t: AnyType}, nil
 	}
 
 	switch v := val.(type) {
+	case TypedValue:
+		{
+			// values bound through Params
+			return v, nil
+		}
 	case bool:
 		{
 			return &Bool{val: v}, nil
@@ -2952,11 +3322,19 @@ func (p *Param) substitute(params map[string]interface{}) (ValueExp, error) {
 		{
 			return &Float64{val: v}, nil
 		}
+	case GeoPoint:
+		{
+			return NewPoint(v.Lat, v.Lon)
+		}
 	}
 	return nil, ErrUnsupportedParameter
 }
 
 func (p *Param) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return p.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (p *Param) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
 	return nil, ErrUnexpected
 }
 
@@ -2997,7 +3375,11 @@ func (ce *CaseWhenExp) inferType(cols map[string]ColDescriptor, params map[strin
 			return "", err
 		}
 
-		if expectedType == AnyType {
+		// untyped NULLs take the type of the other branches
+		if expectedType == AnyType || t == AnyType {
+			if t == AnyType {
+				return expectedType, nil
+			}
 			return t, nil
 		}
 
@@ -3050,7 +3432,7 @@ func (ce *CaseWhenExp) requiresType(t SQLValueType, cols map[string]ColDescripto
 		return err
 	}
 
-	if inferredType != t {
+	if inferredType != t && inferredType != AnyType {
 		return fmt.Errorf("%w: expected type %s but %s found instead", ErrInvalidTypes, t, inferredType)
 	}
 	return nil
@@ -3117,9 +3499,13 @@ func (ce *CaseWhenExp) selectors() []Selector {
 }
 
 func (ce *CaseWhenExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return ce.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (ce *CaseWhenExp) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
 	var searchValue TypedValue
 	if ce.exp != nil {
-		v, err := ce.exp.reduce(tx, row, implicitTable)
+		v, err := ce.exp.reduceCtx(ctx, tx, row, implicitTable)
 		if err != nil {
 			return nil, err
 		}
@@ -3129,7 +3515,7 @@ func (ce *CaseWhenExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedV
 	}
 
 	for _, wt := range ce.whenThen {
-		v, err := wt.when.reduce(tx, row, implicitTable)
+		v, err := wt.when.reduceCtx(ctx, tx, row, implicitTable)
 		if err != nil {
 			return nil, err
 		}
@@ -3142,15 +3528,15 @@ func (ce *CaseWhenExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedV
 		if err != nil {
 			return nil, err
 		}
-		if res == 0 {
-			return wt.then.reduce(tx, row, implicitTable)
+		if res == 0 && !unordered(v, searchValue) {
+			return wt.then.reduceCtx(ctx, tx, row, implicitTable)
 		}
 	}
 
 	if ce.elseExp == nil {
 		return NewNull(AnyType), nil
 	}
-	return ce.elseExp.reduce(tx, row, implicitTable)
+	return ce.elseExp.reduceCtx(ctx, tx, row, implicitTable)
 }
 
 func (ce *CaseWhenExp) reduceSelectors(row *Row, implicitTable string) ValueExp {
@@ -3222,6 +3608,7 @@ type SelectStmt struct {
 	selectors []Selector
 	ds        DataSource
 	indexOn   []string
+	fullScan  bool // rows are scanned over the primary index, as requested by an optimizer hint
 	joins     []*JoinSpec
 	where     ValueExp
 	groupBy   []*ColSelector
@@ -3229,9 +3616,25 @@ type SelectStmt struct {
 	orderBy   []*OrdExp
 	limit     ValueExp
 	offset    ValueExp
+	withTies  bool // rows tying with the last one within the limit are also returned
+	forShare  bool // rows are read FOR SHARE, i.e. the transaction conflicts with concurrent updates to them
 	as        string
 }
 
+// fetchClause holds the row count of a FETCH FIRST clause, which is just
+// another way of expressing a LIMIT, unless rows tying at the limit are requested.
+type fetchClause struct {
+	limit    ValueExp
+	withTies bool
+}
+
+func newFetchClause(limit ValueExp, withTies bool) *fetchClause {
+	if limit == nil {
+		limit = &Integer{val: 1}
+	}
+	return &fetchClause{limit: limit, withTies: withTies}
+}
+
 func NewSelectStmt(
 	targets []TargetEntry,
 	ds DataSource,
@@ -3279,6 +3682,19 @@ func (stmt *SelectStmt) execAt(ctx context.Context, tx *SQLTx, params map[string
 		return nil, ErrHavingClauseRequiresGroupClause
 	}
 
+	if stmt.withTies && len(stmt.orderBy) == 0 {
+		return nil, ErrWithTiesRequiresOrderBy
+	}
+
+	if stmt.withTies && stmt.distinct {
+		return nil, fmt.Errorf("%w: with ties can not be combined with distinct", ErrIllegalArguments)
+	}
+
+	// read-only transactions read from a snapshot and are never invalidated
+	if stmt.forShare && !tx.opts.ReadOnly {
+		tx.sharedReads = true
+	}
+
 	if stmt.containsAggregations() || len(stmt.groupBy) > 0 {
 		for _, sel := range stmt.targetSelectors() {
 			_, isAgg := sel.(*AggColSelector)
@@ -3354,12 +3770,35 @@ func (stmt *SelectStmt) extractSelectors() []Selector {
 }
 
 func (stmt *SelectStmt) Resolve(ctx context.Context, tx *SQLTx, params map[string]interface{}, _ *ScanSpecs) (ret RowReader, err error) {
+	if ref, isTableRef := stmt.ds.(*tableRef); isTableRef && tx != nil && tx.catalog.ExistView(ref.table) {
+		// the view is expanded into a subquery so that the outer
+		// conditions can be merged into the ones of the view
+		query, err := ref.expandView(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		expanded := *stmt
+		expanded.ds = query
+
+		tx.viewNesting++
+		defer func() { tx.viewNesting-- }()
+
+		return expanded.Resolve(ctx, tx, params, nil)
+	}
+
+	if tx != nil {
+		if rewritten := stmt.minMaxOverIndex(tx); rewritten != nil {
+			return rewritten.Resolve(ctx, tx, params, nil)
+		}
+	}
+
 	scanSpecs, err := stmt.genScanSpecs(tx, params)
 	if err != nil {
 		return nil, err
 	}
 
-	rowReader, err := stmt.ds.Resolve(ctx, tx, params, scanSpecs)
+	rowReader, err := stmt.pushDownPredicates(tx).Resolve(ctx, tx, params, scanSpecs)
 	if err != nil {
 		return nil, err
 	}
@@ -3375,6 +3814,11 @@ func (stmt *SelectStmt) Resolve(ctx context.Context, tx *SQLTx, params map[strin
 		if err != nil {
 			return nil, err
 		}
+
+		err = jointRowReader.resolveJoinColumns(ctx)
+		if err != nil {
+			return nil, err
+		}
 		rowReader = jointRowReader
 	}
 
@@ -3397,6 +3841,11 @@ func (stmt *SelectStmt) Resolve(ctx context.Context, tx *SQLTx, params map[strin
 		if err != nil {
 			return nil, err
 		}
+
+		groupedRowReader.rowCount, err = stmt.countedRows(ctx, tx, rowReader)
+		if err != nil {
+			return nil, err
+		}
 		rowReader = groupedRowReader
 
 		if stmt.having != nil {
@@ -3406,13 +3855,41 @@ func (stmt *SelectStmt) Resolve(ctx context.Context, tx *SQLTx, params map[strin
 
 	if len(scanSpecs.orderBySortExps) > 0 {
 		var sortRowReader *sortRowReader
-		sortRowReader, err = newSortRowReader(rowReader, stmt.orderBy)
+		if n := stmt.topN(tx, params); n > 0 {
+			sortRowReader, err = newTopNRowReader(rowReader, scanSpecs.orderBySortExps, n)
+		} else {
+			sortRowReader, err = newSortRowReader(rowReader, scanSpecs.orderBySortExps)
+		}
 		if err != nil {
 			return nil, err
 		}
 		rowReader = sortRowReader
 	}
 
+	if !stmt.distinct {
+		// projection is done row by row, thus rows are limited before being projected
+		// so that skipped rows are not projected. It also allows an OFFSET to be
+		// applied by skipping the entries of the index when rows are directly read from it,
+		// though they still must be scanned as there is no seek by position.
+		// Ties are found by evaluating the ORDER BY expressions, and DISTINCT can not be
+		// combined with WITH TIES.
+		var limitedRowReader RowReader
+		limitedRowReader, err = stmt.limitRows(tx, params, rowReader)
+		if err != nil {
+			return nil, err
+		}
+		rowReader = limitedRowReader
+	}
+
+	// identical rows are read one after the other when sorted by the selected columns
+	var sortedBySelection bool
+	if stmt.distinct {
+		sortedBySelection, err = stmt.sortedBySelection(ctx, rowReader)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	projectedRowReader, err := newProjectedRowReader(ctx, rowReader, stmt.as, stmt.targets)
 	if err != nil {
 		return nil, err
@@ -3421,16 +3898,100 @@ func (stmt *SelectStmt) Resolve(ctx context.Context, tx *SQLTx, params map[strin
 
 	if stmt.distinct {
 		var distinctRowReader *distinctRowReader
-		distinctRowReader, err = newDistinctRowReader(ctx, rowReader)
+		if sortedBySelection {
+			distinctRowReader, err = newSortedDistinctRowReader(ctx, rowReader)
+		} else {
+			distinctRowReader, err = newDistinctRowReader(ctx, rowReader)
+		}
 		if err != nil {
 			return nil, err
 		}
 		rowReader = distinctRowReader
 	}
 
+	if stmt.distinct {
+		var limitedRowReader RowReader
+		limitedRowReader, err = stmt.limitRows(tx, params, rowReader)
+		if err != nil {
+			return nil, err
+		}
+		rowReader = limitedRowReader
+	}
+	return rowReader, nil
+}
+
+// sortedBySelection returns whether the rows read by rowReader are sorted first by the columns
+// selected by the statement, in any order and with no other expression in between, so that
+// rows with the same selected values are read one after the other.
+func (stmt *SelectStmt) sortedBySelection(ctx context.Context, rowReader RowReader) (bool, error) {
+	selected := make(map[string]struct{}, len(stmt.targets))
+
+	if len(stmt.targets) == 0 {
+		cols, err := rowReader.Columns(ctx)
+		if err != nil {
+			return false, err
+		}
+
+		for _, col := range cols {
+			selected[col.Selector()] = struct{}{}
+		}
+	}
+
+	for _, t := range stmt.targets {
+		sel, isCol := t.Exp.(*ColSelector)
+		if !isCol {
+			return false, nil
+		}
+		selected[EncodeSelector(sel.resolve(rowReader.TableAlias()))] = struct{}{}
+	}
+
+	sortedBy := make(map[string]struct{}, len(selected))
+
+	for _, col := range rowReader.OrderBy() {
+		if len(sortedBy) == len(selected) {
+			break
+		}
+
+		if _, isSelected := selected[col.Selector()]; !isSelected {
+			return false, nil
+		}
+		sortedBy[col.Selector()] = struct{}{}
+	}
+	return len(sortedBy) == len(selected), nil
+}
+
+// topN returns the number of sorted rows needed to apply the OFFSET and LIMIT clauses of the
+// statement, when no more than what the sort buffer holds, otherwise 0 for all the rows to be sorted.
+// Rows following the limit may be needed when they are DISTINCT or WITH TIES, as they are when
+// OFFSET and LIMIT can not be evaluated, so that the error is reported as usual.
+func (stmt *SelectStmt) topN(tx *SQLTx, params map[string]interface{}) int {
+	if stmt.limit == nil || stmt.distinct || stmt.withTies {
+		return 0
+	}
+
+	limit, err := evalExpAsInt(tx, stmt.limit, params)
+	if err != nil || limit <= 0 {
+		return 0
+	}
+
+	offset := 0
 	if stmt.offset != nil {
-		var offset int
 		offset, err = evalExpAsInt(tx, stmt.offset, params)
+		if err != nil || offset < 0 {
+			return 0
+		}
+	}
+
+	if limit > tx.engine.sortBufferSize-offset {
+		return 0
+	}
+	return offset + limit
+}
+
+// limitRows applies the OFFSET and LIMIT clauses of the statement to the rows returned by rowReader
+func (stmt *SelectStmt) limitRows(tx *SQLTx, params map[string]interface{}, rowReader RowReader) (RowReader, error) {
+	if stmt.offset != nil {
+		offset, err := evalExpAsInt(tx, stmt.offset, params)
 		if err != nil {
 			return nil, fmt.Errorf("%w: invalid offset", err)
 		}
@@ -3439,8 +4000,7 @@ func (stmt *SelectStmt) Resolve(ctx context.Context, tx *SQLTx, params map[strin
 	}
 
 	if stmt.limit != nil {
-		var limit int
-		limit, err = evalExpAsInt(tx, stmt.limit, params)
+		limit, err := evalExpAsInt(tx, stmt.limit, params)
 		if err != nil {
 			return nil, fmt.Errorf("%w: invalid limit", err)
 		}
@@ -3449,7 +4009,9 @@ func (stmt *SelectStmt) Resolve(ctx context.Context, tx *SQLTx, params map[strin
 			return nil, fmt.Errorf("%w: invalid limit", ErrIllegalArguments)
 		}
 
-		if limit > 0 {
+		if limit > 0 && stmt.withTies {
+			rowReader = newLimitWithTiesRowReader(rowReader, limit, stmt.orderBy)
+		} else if limit > 0 {
 			rowReader = newLimitRowReader(rowReader, limit)
 		}
 	}
@@ -3465,6 +4027,7 @@ func (stmt *SelectStmt) rearrangeOrdExps(groupByCols, orderByExps []*OrdExp) ([]
 		if ordExpsHasPrefix(groupByCols, orderByExps, stmt.Alias()) {
 			for i := range orderByExps {
 				groupByCols[i].descOrder = orderByExps[i].descOrder
+				groupByCols[i].nullsOrder = orderByExps[i].nullsOrder
 			}
 			return groupByCols, nil
 		}
@@ -3575,6 +4138,9 @@ func (stmt *SelectStmt) hasTxMetadata() bool {
 
 func (stmt *SelectStmt) genScanSpecs(tx *SQLTx, params map[string]interface{}) (*ScanSpecs, error) {
 	groupByCols, orderByCols := stmt.groupByOrdExps(), stmt.orderBy
+	if len(orderByCols) > 0 {
+		orderByCols = withNullsOrder(orderByCols, tx.engine.nullsOrder)
+	}
 
 	tableRef, isTableRef := stmt.ds.(*tableRef)
 	if !isTableRef {
@@ -3642,17 +4208,130 @@ func (stmt *SelectStmt) genScanSpecs(tx *SQLTx, params map[string]interface{}) (
 
 	groupByCols, orderByCols = stmt.rearrangeOrdExps(groupByCols, orderByCols)
 
+	if tx.engine.stableOrdering && len(orderByCols) > 0 && len(stmt.groupBy) == 0 && !stmt.containsAggregations() &&
+		len(stmt.joins) == 0 && !tableRef.history {
+		orderByCols = withOrderingTiebreaker(table, tableRef.Alias(), orderByCols)
+	}
+
+	var conflictFilter ValueExp
+	if tx.opts.ConflictGranularity == PredicateConflicts && stmt.where != nil {
+		conflictFilter = conflictFilterFor(stmt.where, tableRef.Alias(), params)
+	}
+
+	// full-text scans return rows in primary key order over the current state of the table
+	var ftMatch *fullTextMatch
+	if preferredIndex == nil && sortingIndex.IsPrimary() && !descOrder && !tableRef.history &&
+		tableRef.period.start == nil && tableRef.period.end == nil &&
+		tx.opts.ConflictGranularity == RowConflicts {
+		ftMatch = stmt.fullTextMatchFor(tx, table, tableRef.Alias(), params)
+	}
+
+	// rows are lazily decoded when their values are only accessed by evaluating expressions
+	// i.e. they're filtered, limited and projected but neither joined, grouped nor sorted
+	lazyDecoding := tx.engine.lazyDecoding && len(stmt.joins) == 0 && len(stmt.groupBy) == 0 &&
+		!stmt.containsAggregations() && len(groupByCols) == 0 && len(orderByCols) == 0
+
+	// disjunctions are scanned as a union of ranges instead of the single range covering all of them
+	var rangeUnionColID uint32
+	var rangeUnion []*typedValueRange
+	if ftMatch == nil && stmt.where != nil {
+		rangeUnionColID, rangeUnion = rangeUnionFor(
+			sortingIndex,
+			rangesByColID,
+			selectorRangeUnions(stmt.where, table, tableRef.Alias(), params),
+		)
+	}
+
 	return &ScanSpecs{
 		Index:             sortingIndex,
 		rangesByColID:     rangesByColID,
+		rangeUnionColID:   rangeUnionColID,
+		rangeUnion:        rangeUnion,
 		IncludeHistory:    tableRef.history,
 		IncludeTxMetadata: stmt.hasTxMetadata(),
 		DescOrder:         descOrder,
 		groupBySortExps:   groupByCols,
 		orderBySortExps:   orderByCols,
+		conflictFilter:    conflictFilter,
+		lazyDecoding:      lazyDecoding,
+		neededCols:        stmt.neededColumns(table, groupByCols, orderByCols),
+		fullTextMatch:     ftMatch,
 	}, nil
 }
 
+// conflictFilterFor returns the conjunction of the conditions in where that only involve
+// columns of the given table, so it's satisfied by every row the query may depend on.
+// It returns nil when there is no such condition.
+func conflictFilterFor(where ValueExp, asTable string, params map[string]interface{}) ValueExp {
+	where, err := where.substitute(params)
+	if err != nil {
+		return nil
+	}
+
+	var filter ValueExp
+
+	for _, conjunct := range conjuncts(where) {
+		exp, ok := rewriteSelectors(conjunct, func(sel *ColSelector) (ValueExp, bool) {
+			_, t, _ := sel.resolve(asTable)
+			return sel, t == asTable
+		})
+		if !ok {
+			continue
+		}
+
+		if filter == nil {
+			filter = exp
+		} else {
+			filter = &BinBoolExp{op: And, left: filter, right: exp}
+		}
+	}
+	return filter
+}
+
+// withOrderingTiebreaker appends the primary key columns not already included in ordExps,
+// unless ordExps already identify each row i.e. they include all the columns of
+// the primary key or of a unique index over non-nullable columns.
+func withOrderingTiebreaker(table *Table, asTable string, ordExps []*OrdExp) []*OrdExp {
+	orderedCols := make(map[string]struct{}, len(ordExps))
+
+	for _, e := range ordExps {
+		sel := e.AsSelector()
+		if sel == nil {
+			continue
+		}
+
+		aggFn, t, col := sel.resolve(asTable)
+		if aggFn == "" && t == asTable {
+			orderedCols[col] = struct{}{}
+		}
+	}
+
+	includesAll := func(idx *Index) bool {
+		for _, col := range idx.cols {
+			if _, ok := orderedCols[col.colName]; !ok || (!idx.IsPrimary() && col.IsNullable()) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, idx := range table.indexes {
+		if idx.IsUnique() && includesAll(idx) {
+			return ordExps
+		}
+	}
+
+	stableOrdExps := make([]*OrdExp, len(ordExps), len(ordExps)+len(table.primaryIndex.cols))
+	copy(stableOrdExps, ordExps)
+
+	for _, col := range table.primaryIndex.cols {
+		if _, ok := orderedCols[col.colName]; !ok {
+			stableOrdExps = append(stableOrdExps, &OrdExp{exp: &ColSelector{table: asTable, col: col.colName}})
+		}
+	}
+	return stableOrdExps
+}
+
 func (stmt *SelectStmt) selectSortingIndex(groupByCols, orderByCols []*OrdExp, table *Table, rangesByColId map[uint32]*typedValueRange) *Index {
 	sortCols := groupByCols
 	if len(sortCols) == 0 {
@@ -3747,6 +4426,10 @@ func (stmt *SelectStmt) selectFilteringIndex(table *Table, rangesByColID map[uin
 }
 
 func (stmt *SelectStmt) getPreferredIndex(table *Table) (*Index, error) {
+	if stmt.fullScan {
+		return table.primaryIndex, nil
+	}
+
 	if len(stmt.indexOn) == 0 {
 		return nil, nil
 	}
@@ -3982,6 +4665,39 @@ func (stmt *tableRef) referencedTable(tx *SQLTx) (*Table, error) {
 	return table, nil
 }
 
+// expandView returns the query of the referenced view, to be used as a subquery
+func (stmt *tableRef) expandView(tx *SQLTx) (*SelectStmt, error) {
+	view, err := tx.catalog.GetViewByName(stmt.table)
+	if err != nil {
+		return nil, err
+	}
+
+	if stmt.history || stmt.period.start != nil || stmt.period.end != nil {
+		return nil, fmt.Errorf("%w: history and periods can not be used with views", ErrIllegalArguments)
+	}
+	return view.expand(tx, stmt.Alias())
+}
+
+func (v *View) expand(tx *SQLTx, as string) (*SelectStmt, error) {
+	if tx.viewNesting >= maxViewNesting {
+		return nil, fmt.Errorf("%w (%s)", ErrMaxViewNestingExceeded, v.name)
+	}
+
+	stmts, err := parseSQL(strings.NewReader(v.sql), tx.engine.functions, tx.engine.caseSensitiveIds)
+	if err != nil {
+		return nil, err
+	}
+
+	query, isSelect := stmts[0].(*SelectStmt)
+	if len(stmts) != 1 || !isSelect {
+		return nil, ErrCorruptedData
+	}
+
+	query.as = as
+
+	return query, nil
+}
+
 func (stmt *tableRef) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
 	return nil
 }
@@ -3997,18 +4713,50 @@ func (stmt *tableRef) Resolve(ctx context.Context, tx *SQLTx, params map[string]
 
 	table, err := stmt.referencedTable(tx)
 	if err == nil {
-		return newRawRowReader(tx, params, table, stmt.period, stmt.as, scanSpecs)
+		rowReader, err := newRawRowReader(tx, params, table, stmt.period, stmt.as, scanSpecs)
+		if err != nil {
+			return nil, err
+		}
+		return tx.interceptReads(table, rowReader), nil
+	}
+
+	if tx.catalog.ExistView(stmt.table) {
+		query, err := stmt.expandView(tx)
+		if err != nil {
+			return nil, err
+		}
+
+		tx.viewNesting++
+		defer func() { tx.viewNesting-- }()
+
+		return query.Resolve(ctx, tx, params, nil)
 	}
 
 	if resolver := tx.engine.tableResolveFor(stmt.table); resolver != nil {
-		return resolver.Resolve(ctx, tx, stmt.Alias())
+		rowReader, err := resolver.Resolve(ctx, tx, stmt.Alias())
+		if err != nil {
+			return nil, err
+		}
+		return &resolvedRowReader{RowReader: rowReader, params: params}, nil
 	}
 	return nil, err
 }
 
+// resolvedRowReader provides the rows of a table resolver with the parameters of the
+// query they're read from, as resolvers are not aware of them
+type resolvedRowReader struct {
+	RowReader
+	params map[string]interface{}
+}
+
+func (r *resolvedRowReader) Parameters() map[string]interface{} {
+	return r.params
+}
+
 func (stmt *tableRef) Alias() string {
 	if stmt.as == "" {
-		return stmt.table
+		// schema-qualified tables are referenced by their unqualified name
+		return stmt.table[strings.LastIndexByte(stmt.table, '.')+1:]
 	}
 	return stmt.as
 }
@@ -4016,6 +4764,11 @@ func (stmt *tableRef) Alias() string {
 type valuesDataSource struct {
 	inferTypes bool
 	rows       []*RowSpec
+
+	// as and colNames name the table constructed by a VALUES list and its columns,
+	// which are otherwise named "values" and col0, col1, ...
+	as       string
+	colNames []string
 }
 
 func NewValuesDataSource(rows []*RowSpec) *valuesDataSource {
@@ -4041,7 +4794,7 @@ func (ds *valuesDataSource) inferParameters(ctx context.Context, tx *SQLTx, para
 }
 
 func (ds *valuesDataSource) Alias() string {
-	return ""
+	return ds.as
 }
 
 func (ds *valuesDataSource) Resolve(ctx context.Context, tx *SQLTx, params map[string]interface{}, scanSpecs *ScanSpecs) (RowReader, error) {
@@ -4050,16 +4803,39 @@ func (ds *valuesDataSource) Resolve(ctx context.Context, tx *SQLTx, params map[s
 	}
 
 	cols := make([]ColDescriptor, len(ds.rows[0].Values))
+
+	if len(ds.colNames) > 0 && len(ds.colNames) != len(cols) {
+		return nil, fmt.Errorf("%w: %d column names specified for %d values", ErrInvalidNumberOfValues, len(ds.colNames), len(cols))
+	}
+
 	for i := range cols {
 		cols[i] = ColDescriptor{
-			Type:   AnyType,
-			Column: fmt.Sprintf("col%d", i),
+			Type:     AnyType,
+			Column:   fmt.Sprintf("col%d", i),
+			Nullable: true,
+		}
+
+		if len(ds.colNames) > 0 {
+			if slices.Contains(ds.colNames[:i], ds.colNames[i]) {
+				return nil, fmt.Errorf("%w (%s)", ErrDuplicatedColumn, ds.colNames[i])
+			}
+			cols[i].Column = ds.colNames[i]
 		}
 	}
 
+	values := make([][]ValueExp, len(ds.rows))
+	for i, rowSpec := range ds.rows {
+		if len(rowSpec.Values) != len(cols) {
+			return nil, ErrInvalidNumberOfValues
+		}
+		values[i] = slices.Clone(rowSpec.Values)
+	}
+
 	emptyColsDesc, emptyParams := map[string]ColDescriptor{}, map[string]string{}
 
 	if ds.inferTypes {
+		types := make([]SQLValueType, len(ds.rows))
+
 		for i := 0; i < len(cols); i++ {
 			t := AnyType
 			for j := 0; j < len(ds.rows); j++ {
@@ -4073,21 +4849,45 @@ func (ds *valuesDataSource) Resolve(ctx context.Context, tx *SQLTx, params map[s
 					return nil, err
 				}
 
-				if t == AnyType {
-					t = it
-				} else if t != it && it != AnyType {
+				ct, ok := commonValuesType(t, it)
+				if !ok {
 					return nil, fmt.Errorf("cannot match types %s and %s", t, it)
 				}
+
+				t = ct
+				types[j] = it
 			}
 			cols[i].Type = t
+
+			// values of a different but compatible type are converted to the type of the column
+			for j := range values {
+				if types[j] != t && types[j] != AnyType {
+					values[j][i] = &Cast{val: values[j][i], t: t}
+				}
+			}
 		}
 	}
 
-	values := make([][]ValueExp, len(ds.rows))
-	for i, rowSpec := range ds.rows {
-		values[i] = rowSpec.Values
+	alias := ds.as
+	if alias == "" {
+		alias = "values"
+	}
+	return NewValuesRowReader(tx, params, cols, ds.inferTypes, alias, values)
+}
+
+// commonValuesType returns the type values of both types are converted to when found in the same
+// column of a VALUES list. NULL values, whose type is AnyType, fit into any column, and integers
+// are converted to floats.
+func commonValuesType(t1, t2 SQLValueType) (SQLValueType, bool) {
+	switch {
+	case t1 == t2 || t2 == AnyType:
+		return t1, true
+	case t1 == AnyType:
+		return t2, true
+	case (t1 == IntegerType && t2 == Float64Type) || (t1 == Float64Type && t2 == IntegerType):
+		return Float64Type, true
 	}
-	return NewValuesRowReader(tx, params, cols, ds.inferTypes, "values", values)
+	return AnyType, false
 }
 
 type JoinSpec struct {
@@ -4095,11 +4895,53 @@ type JoinSpec struct {
 	ds       DataSource
 	cond     ValueExp
 	indexOn  []string
+	fullScan bool
+
+	// using holds the columns of a JOIN ... USING (...) clause, natural is set for
+	// a NATURAL JOIN. In both cases cond is synthesized when the join gets resolved.
+	using   []string
+	natural bool
+
+	// lateral is set for a LATERAL subquery, which may refer to the columns of the
+	// data sources preceding it and thus gets resolved again for every row of them
+	lateral bool
 }
 
 type OrdExp struct {
-	exp       ValueExp
-	descOrder bool
+	exp        ValueExp
+	descOrder  bool
+	nullsOrder NullsOrder
+}
+
+// nullsFirst reports whether NULL values are placed before any other value.
+// When no placement is specified, NULL values are the smallest values.
+func (oc *OrdExp) nullsFirst() bool {
+	switch oc.nullsOrder {
+	case NullsFirst:
+		return true
+	case NullsLast:
+		return false
+	case NullsAsLargest:
+		return oc.descOrder
+	}
+	return !oc.descOrder
+}
+
+// withNullsOrder returns the expressions with no explicit placement of NULL values
+// resolved using the given default
+func withNullsOrder(exps []*OrdExp, nullsOrder NullsOrder) []*OrdExp {
+	if nullsOrder == NullsAsSmallest {
+		return exps
+	}
+
+	resolved := make([]*OrdExp, len(exps))
+	for i, e := range exps {
+		resolved[i] = e
+		if e.nullsOrder == nullsOrderUnspecified {
+			resolved[i] = &OrdExp{exp: e.exp, descOrder: e.descOrder, nullsOrder: nullsOrder}
+		}
+	}
+	return resolved
 }
 
 func (oc *OrdExp) AsSelector() Selector {
@@ -4170,17 +5012,30 @@ func (sel *ColSelector) requiresType(t SQLValueType, cols map[string]ColDescript
 }
 
 func (sel *ColSelector) substitute(params map[string]interface{}) (ValueExp, error) {
+	// values of the row a trigger is fired for, see fireTriggers
+	if sel.table == triggerNewRow || sel.table == triggerOldRow {
+		if v, ok := params[sel.table+"."+sel.col].(TypedValue); ok {
+			return v, nil
+		}
+	}
 	return sel, nil
 }
 
 func (sel *ColSelector) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return sel.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (sel *ColSelector) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
 	if row == nil {
 		return nil, fmt.Errorf("%w: no row to evaluate in current context", ErrInvalidValue)
 	}
 
 	aggFn, table, col := sel.resolve(implicitTable)
 
-	v, ok := row.ValuesBySelector[EncodeSelector(aggFn, table, col)]
+	v, ok, err := row.valueBySelector(EncodeSelector(aggFn, table, col))
+	if err != nil {
+		return nil, err
+	}
 	if !ok {
 		return nil, fmt.Errorf("%w (%s)", ErrColumnDoesNotExist, col)
 	}
@@ -4194,8 +5049,8 @@ func (sel *ColSelector) selectors() []Selector {
 func (sel *ColSelector) reduceSelectors(row *Row, implicitTable string) ValueExp {
 	aggFn, table, col := sel.resolve(implicitTable)
 
-	v, ok := row.ValuesBySelector[EncodeSelector(aggFn, table, col)]
-	if !ok {
+	v, ok, err := row.valueBySelector(EncodeSelector(aggFn, table, col))
+	if err != nil || !ok {
 		return sel
 	}
 
@@ -4211,13 +5066,16 @@ func (sel *ColSelector) selectorRanges(table *Table, asTable string, params map[
 }
 
 func (sel *ColSelector) String() string {
-	return sel.col
+	return quoteIdentifier(sel.col)
 }
 
 type AggColSelector struct {
 	aggFn AggregateFn
 	table string
 	col   string
+
+	aggregate *userAggregate
+	distinct  []*ColSelector // columns of COUNT(DISTINCT ...)
 }
 
 func NewAggColSelector(aggFn AggregateFn, table, col string) *AggColSelector {
@@ -4232,6 +5090,22 @@ func EncodeSelector(aggFn, table, col string) string {
 	return aggFn + "(" + table + "." + col + ")"
 }
 
+// DecodeSelector splits a selector produced by EncodeSelector into its components
+func DecodeSelector(sel string) (aggFn, table, col string, err error) {
+	open := strings.Index(sel, "(")
+	if open < 0 || !strings.HasSuffix(sel, ")") {
+		return "", "", "", fmt.Errorf("%w: invalid selector '%s'", ErrIllegalArguments, sel)
+	}
+
+	tableAndCol := sel[open+1 : len(sel)-1]
+
+	dot := strings.Index(tableAndCol, ".")
+	if dot < 0 {
+		return "", "", "", fmt.Errorf("%w: invalid selector '%s'", ErrIllegalArguments, sel)
+	}
+	return sel[:open], tableAndCol[:dot], tableAndCol[dot+1:], nil
+}
+
 func (sel *AggColSelector) resolve(implicitTable string) (aggFn, table, col string) {
 	table = implicitTable
 	if sel.table != "" {
@@ -4240,13 +5114,32 @@ func (sel *AggColSelector) resolve(implicitTable string) (aggFn, table, col stri
 	return sel.aggFn, table, sel.col
 }
 
+func (sel *AggColSelector) isCount() bool {
+	return sel.aggFn == COUNT || sel.distinct != nil
+}
+
 func (sel *AggColSelector) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
-	if sel.aggFn == COUNT {
+	if sel.isCount() {
 		return IntegerType, nil
 	}
 
 	colSelector := &ColSelector{table: sel.table, col: sel.col}
 
+	if sel.aggregate != nil {
+		if sel.aggregate.spec.ArgType != AnyType {
+			err := colSelector.requiresType(sel.aggregate.spec.ArgType, cols, params, implicitTable)
+			if err != nil {
+				return AnyType, err
+			}
+		}
+
+		if sel.aggregate.spec.ReturnType == AnyType {
+			// results are of the same type as the aggregated column
+			return colSelector.inferType(cols, params, implicitTable)
+		}
+		return sel.aggregate.spec.ReturnType, nil
+	}
+
 	if sel.aggFn == SUM || sel.aggFn == AVG {
 		t, err := colSelector.inferType(cols, params, implicitTable)
 		if err != nil {
@@ -4265,13 +5158,20 @@ func (sel *AggColSelector) inferType(cols map[string]ColDescriptor, params map[s
 }
 
 func (sel *AggColSelector) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
-	if sel.aggFn == COUNT {
+	if sel.isCount() {
 		if t != IntegerType {
 			return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, IntegerType, t)
 		}
 		return nil
 	}
 
+	if sel.aggregate != nil && sel.aggregate.spec.ReturnType != AnyType {
+		if t != sel.aggregate.spec.ReturnType {
+			return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, sel.aggregate.spec.ReturnType, t)
+		}
+		return nil
+	}
+
 	colSelector := &ColSelector{table: sel.table, col: sel.col}
 
 	if sel.aggFn == SUM || sel.aggFn == AVG {
@@ -4288,6 +5188,10 @@ func (sel *AggColSelector) substitute(params map[string]interface{}) (ValueExp,
 }
 
 func (sel *AggColSelector) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return sel.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (sel *AggColSelector) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
 	if row == nil {
 		return nil, fmt.Errorf("%w: no row to evaluate aggregation (%s) in current context", ErrInvalidValue, sel.aggFn)
 	}
@@ -4316,6 +5220,13 @@ func (sel *AggColSelector) selectorRanges(table *Table, asTable string, params m
 }
 
 func (sel *AggColSelector) String() string {
+	if sel.distinct != nil {
+		cols := make([]string, len(sel.distinct))
+		for i, col := range sel.distinct {
+			cols[i] = col.String()
+		}
+		return COUNT + "(DISTINCT " + strings.Join(cols, ", ") + ")"
+	}
 	return sel.aggFn + "(" + sel.col + ")"
 }
 
@@ -4427,12 +5338,16 @@ func (bexp *NumExp) substitute(params map[string]interface{}) (ValueExp, error)
 }
 
 func (bexp *NumExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
-	vl, err := bexp.left.reduce(tx, row, implicitTable)
+	return bexp.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (bexp *NumExp) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	vl, err := bexp.left.reduceCtx(ctx, tx, row, implicitTable)
 	if err != nil {
 		return nil, err
 	}
 
-	vr, err := bexp.right.reduce(tx, row, implicitTable)
+	vr, err := bexp.right.reduceCtx(ctx, tx, row, implicitTable)
 	if err != nil {
 		return nil, err
 	}
@@ -4440,7 +5355,7 @@ func (bexp *NumExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValu
 	vl = unwrapJSON(vl)
 	vr = unwrapJSON(vr)
 
-	return applyNumOperator(bexp.op, vl, vr)
+	return applyNumOperator(bexp.op, vl, vr, tx.integerOverflow())
 }
 
 func unwrapJSON(v TypedValue) TypedValue {
@@ -4509,7 +5424,11 @@ func (bexp *NotBoolExp) substitute(params map[string]interface{}) (ValueExp, err
 }
 
 func (bexp *NotBoolExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
-	v, err := bexp.exp.reduce(tx, row, implicitTable)
+	return bexp.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (bexp *NotBoolExp) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	v, err := bexp.exp.reduceCtx(ctx, tx, row, implicitTable)
 	if err != nil {
 		return nil, err
 	}
@@ -4611,11 +5530,15 @@ func (bexp *LikeBoolExp) substitute(params map[string]interface{}) (ValueExp, er
 }
 
 func (bexp *LikeBoolExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return bexp.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (bexp *LikeBoolExp) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
 	if bexp.val == nil || bexp.pattern == nil {
 		return nil, fmt.Errorf("error in 'LIKE' clause: %w", ErrInvalidCondition)
 	}
 
-	rval, err := bexp.val.reduce(tx, row, implicitTable)
+	rval, err := bexp.val.reduceCtx(ctx, tx, row, implicitTable)
 	if err != nil {
 		return nil, fmt.Errorf("error in 'LIKE' clause: %w", err)
 	}
@@ -4629,7 +5552,7 @@ func (bexp *LikeBoolExp) reduce(tx *SQLTx, row *Row, implicitTable string) (Type
 		return nil, fmt.Errorf("error in 'LIKE' clause: %w (expecting %s)", ErrInvalidTypes, VarcharType)
 	}
 
-	rpattern, err := bexp.pattern.reduce(tx, row, implicitTable)
+	rpattern, err := bexp.pattern.reduceCtx(ctx, tx, row, implicitTable)
 	if err != nil {
 		return nil, fmt.Errorf("error in 'LIKE' clause: %w", err)
 	}
@@ -4647,7 +5570,10 @@ func (bexp *LikeBoolExp) reduce(tx *SQLTx, row *Row, implicitTable string) (Type
 }
 
 func (bexp *LikeBoolExp) selectors() []Selector {
-	return bexp.val.selectors()
+	if bexp.pattern == nil {
+		return bexp.val.selectors()
+	}
+	return append(bexp.val.selectors(), bexp.pattern.selectors()...)
 }
 
 func (bexp *LikeBoolExp) reduceSelectors(row *Row, implicitTable string) ValueExp {
@@ -4775,12 +5701,16 @@ func (bexp *CmpBoolExp) substitute(params map[string]interface{}) (ValueExp, err
 }
 
 func (bexp *CmpBoolExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
-	vl, err := bexp.left.reduce(tx, row, implicitTable)
+	return bexp.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (bexp *CmpBoolExp) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	vl, err := bexp.left.reduceCtx(ctx, tx, row, implicitTable)
 	if err != nil {
 		return nil, err
 	}
 
-	vr, err := bexp.right.reduce(tx, row, implicitTable)
+	vr, err := bexp.right.reduceCtx(ctx, tx, row, implicitTable)
 	if err != nil {
 		return nil, err
 	}
@@ -4790,6 +5720,10 @@ func (bexp *CmpBoolExp) reduce(tx *SQLTx, row *Row, implicitTable string) (Typed
 		return nil, err
 	}
 
+	if unordered(vl, vr) {
+		return &Bool{val: bexp.op == NE}, nil
+	}
+
 	return &Bool{val: cmpSatisfiesOp(r, bexp.op)}, nil
 }
 
@@ -4824,7 +5758,7 @@ func (bexp *CmpBoolExp) selectorRanges(table *Table, asTable string, params map[
 	}
 
 	if !ok {
-		return nil
+		return bexp.sargableSelectorRanges(table, asTable, params, rangesByColID)
 	}
 
 	aggFn, t, col := sel.resolve(table.name)
@@ -4859,6 +5793,107 @@ func (bexp *CmpBoolExp) String() string {
 	return fmt.Sprintf("(%s %s %s)", bexp.left.String(), opStr, bexp.right.String())
 }
 
+// DistinctBoolExp is a NULL-safe comparison: two NULL values are not distinct from each other,
+// while a NULL value is distinct from any other value. Its result is never NULL.
+type DistinctBoolExp struct {
+	left        ValueExp
+	right       ValueExp
+	notDistinct bool
+}
+
+func NewDistinctBoolExp(left, right ValueExp, notDistinct bool) *DistinctBoolExp {
+	return &DistinctBoolExp{
+		left:        left,
+		right:       right,
+		notDistinct: notDistinct,
+	}
+}
+
+func (bexp *DistinctBoolExp) inferType(cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) (SQLValueType, error) {
+	cmp := &CmpBoolExp{op: EQ, left: bexp.left, right: bexp.right}
+	return cmp.inferType(cols, params, implicitTable)
+}
+
+func (bexp *DistinctBoolExp) requiresType(t SQLValueType, cols map[string]ColDescriptor, params map[string]SQLValueType, implicitTable string) error {
+	if t != BooleanType {
+		return fmt.Errorf("%w: %v can not be interpreted as type %v", ErrInvalidTypes, BooleanType, t)
+	}
+
+	_, err := bexp.inferType(cols, params, implicitTable)
+	return err
+}
+
+func (bexp *DistinctBoolExp) substitute(params map[string]interface{}) (ValueExp, error) {
+	rlexp, err := bexp.left.substitute(params)
+	if err != nil {
+		return nil, err
+	}
+
+	rrexp, err := bexp.right.substitute(params)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DistinctBoolExp{left: rlexp, right: rrexp, notDistinct: bexp.notDistinct}, nil
+}
+
+func (bexp *DistinctBoolExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return bexp.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (bexp *DistinctBoolExp) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	vl, err := bexp.left.reduceCtx(ctx, tx, row, implicitTable)
+	if err != nil {
+		return nil, err
+	}
+
+	vr, err := bexp.right.reduceCtx(ctx, tx, row, implicitTable)
+	if err != nil {
+		return nil, err
+	}
+
+	var distinct bool
+
+	if vl.IsNull() || vr.IsNull() {
+		distinct = vl.IsNull() != vr.IsNull()
+	} else {
+		r, err := vl.Compare(vr)
+		if err != nil {
+			return nil, err
+		}
+		distinct = r != 0
+	}
+
+	return &Bool{val: distinct != bexp.notDistinct}, nil
+}
+
+func (bexp *DistinctBoolExp) selectors() []Selector {
+	return append(bexp.left.selectors(), bexp.right.selectors()...)
+}
+
+func (bexp *DistinctBoolExp) reduceSelectors(row *Row, implicitTable string) ValueExp {
+	return &DistinctBoolExp{
+		left:        bexp.left.reduceSelectors(row, implicitTable),
+		right:       bexp.right.reduceSelectors(row, implicitTable),
+		notDistinct: bexp.notDistinct,
+	}
+}
+
+func (bexp *DistinctBoolExp) isConstant() bool {
+	return bexp.left.isConstant() && bexp.right.isConstant()
+}
+
+func (bexp *DistinctBoolExp) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
+	return nil
+}
+
+func (bexp *DistinctBoolExp) String() string {
+	if bexp.notDistinct {
+		return fmt.Sprintf("(%s IS NOT DISTINCT FROM %s)", bexp.left.String(), bexp.right.String())
+	}
+	return fmt.Sprintf("(%s IS DISTINCT FROM %s)", bexp.left.String(), bexp.right.String())
+}
+
 type TimestampFieldType string
 
 const (
@@ -4912,7 +5947,11 @@ func (te *ExtractFromTimestampExp) selectors() []Selector {
 }
 
 func (te *ExtractFromTimestampExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
-	v, err := te.Exp.reduce(tx, row, implicitTable)
+	return te.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (te *ExtractFromTimestampExp) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	v, err := te.Exp.reduceCtx(ctx, tx, row, implicitTable)
 	if err != nil {
 		return nil, err
 	}
@@ -5127,7 +6166,11 @@ func (bexp *BinBoolExp) substitute(params map[string]interface{}) (ValueExp, err
 }
 
 func (bexp *BinBoolExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
-	vl, err := bexp.left.reduce(tx, row, implicitTable)
+	return bexp.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (bexp *BinBoolExp) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	vl, err := bexp.left.reduceCtx(ctx, tx, row, implicitTable)
 	if err != nil {
 		return nil, err
 	}
@@ -5142,7 +6185,7 @@ func (bexp *BinBoolExp) reduce(tx *SQLTx, row *Row, implicitTable string) (Typed
 		return &Bool{val: bl.val}, nil
 	}
 
-	vr, err := bexp.right.reduce(tx, row, implicitTable)
+	vr, err := bexp.right.reduceCtx(ctx, tx, row, implicitTable)
 	if err != nil {
 		return nil, err
 	}
@@ -5243,6 +6286,10 @@ func (bexp *ExistsBoolExp) substitute(params map[string]interface{}) (ValueExp,
 }
 
 func (bexp *ExistsBoolExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return bexp.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (bexp *ExistsBoolExp) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
 	return nil, fmt.Errorf("'EXISTS' clause: %w", ErrNoSupported)
 }
 
@@ -5285,6 +6332,10 @@ func (bexp *InSubQueryExp) substitute(params map[string]interface{}) (ValueExp,
 }
 
 func (bexp *InSubQueryExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	return bexp.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (bexp *InSubQueryExp) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
 	return nil, fmt.Errorf("error inferring type in 'IN' clause: %w", ErrNoSupported)
 }
 
@@ -5350,13 +6401,9 @@ func (bexp *InListExp) substitute(params map[string]interface{}) (ValueExp, erro
 		return nil, fmt.Errorf("error evaluating 'IN' clause: %w", err)
 	}
 
-	values := make([]ValueExp, len(bexp.values))
-
-	for i, val := range bexp.values {
-		values[i], err = val.substitute(params)
-		if err != nil {
-			return nil, fmt.Errorf("error evaluating 'IN' clause: %w", err)
-		}
+	values, err := bexp.substituteValues(params)
+	if err != nil {
+		return nil, fmt.Errorf("error evaluating 'IN' clause: %w", err)
 	}
 
 	return &InListExp{
@@ -5366,8 +6413,59 @@ func (bexp *InListExp) substitute(params map[string]interface{}) (ValueExp, erro
 	}, nil
 }
 
+// substituteValues substitutes the parameters in the list of values. A parameter bound to a slice,
+// e.g. col IN (@ids) with ids bound to []int64{1, 2}, is expanded into a value for each element,
+// so an empty slice results in an empty list, which no value is IN.
+func (bexp *InListExp) substituteValues(params map[string]interface{}) ([]ValueExp, error) {
+	values := make([]ValueExp, 0, len(bexp.values))
+
+	for _, v := range bexp.values {
+		p, isParam := v.(*Param)
+		if !isParam {
+			sv, err := v.substitute(params)
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, sv)
+			continue
+		}
+
+		val, ok := params[p.id]
+		if !ok {
+			return nil, fmt.Errorf("%w(%s)", ErrMissingParameter, p.id)
+		}
+
+		list := reflect.ValueOf(val)
+
+		// byte slices are bound as BLOB values
+		if list.Kind() != reflect.Slice || list.Type().Elem().Kind() == reflect.Uint8 {
+			sv, err := paramValue(val)
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, sv)
+			continue
+		}
+
+		for i := 0; i < list.Len(); i++ {
+			sv, err := paramValue(list.Index(i).Interface())
+			if err != nil {
+				return nil, fmt.Errorf("%w: element %d of parameter %s", err, i, p.id)
+			}
+			values = append(values, sv)
+		}
+	}
+	return values, nil
+}
+
 func (bexp *InListExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
-	rval, err := bexp.val.reduce(tx, row, implicitTable)
+	return bexp.reduceCtx(context.Background(), tx, row, implicitTable)
+}
+
+func (bexp *InListExp) reduceCtx(ctx context.Context, tx *SQLTx, row *Row, implicitTable string) (TypedValue, error) {
+	rval, err := bexp.val.reduceCtx(ctx, tx, row, implicitTable)
 	if err != nil {
 		return nil, fmt.Errorf("error evaluating 'IN' clause: %w", err)
 	}
@@ -5375,7 +6473,7 @@ func (bexp *InListExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedV
 	var found bool
 
 	for _, v := range bexp.values {
-		rv, err := v.reduce(tx, row, implicitTable)
+		rv, err := v.reduceCtx(ctx, tx, row, implicitTable)
 		if err != nil {
 			return nil, fmt.Errorf("error evaluating 'IN' clause: %w", err)
 		}
@@ -5385,7 +6483,7 @@ func (bexp *InListExp) reduce(tx *SQLTx, row *Row, implicitTable string) (TypedV
 			return nil, fmt.Errorf("error evaluating 'IN' clause: %w", err)
 		}
 
-		if r == 0 {
+		if r == 0 && !unordered(rval, rv) {
 			// TODO: short-circuit evaluation may be preferred when upfront static type inference is in place
 			found = found || true
 		}
@@ -5420,8 +6518,73 @@ func (bexp *InListExp) isConstant() bool {
 }
 
 func (bexp *InListExp) selectorRanges(table *Table, asTable string, params map[string]interface{}, rangesByColID map[uint32]*typedValueRange) error {
-	// TODO: may be determiined by smallest and bigggest value in the list
-	return nil
+	colID, ranges, ok := bexp.valueRanges(table, asTable, params)
+	if !ok {
+		return nil
+	}
+
+	// the range is determined by the smallest and biggest values in the list
+	inRange := &typedValueRange{lRange: ranges[0].lRange, hRange: ranges[0].hRange}
+	for _, r := range ranges[1:] {
+		err := inRange.extendWith(r)
+		if err != nil {
+			return err
+		}
+	}
+
+	currRange, ranged := rangesByColID[colID]
+	if !ranged {
+		rangesByColID[colID] = inRange
+		return nil
+	}
+
+	return currRange.refineWith(inRange)
+}
+
+// valueRanges returns a unitary range for each value in the list when the expression
+// is of the form col IN (v1, v2, ...) where each value is a constant of the type of the column
+func (bexp *InListExp) valueRanges(table *Table, asTable string, params map[string]interface{}) (uint32, []*typedValueRange, bool) {
+	sel, isSel := bexp.val.(*ColSelector)
+	if bexp.notIn || !isSel || sel.col == revCol {
+		return 0, nil, false
+	}
+
+	for _, v := range bexp.values {
+		if !v.isConstant() {
+			return 0, nil, false
+		}
+	}
+
+	values, err := bexp.substituteValues(params)
+	if err != nil || len(values) == 0 {
+		return 0, nil, false
+	}
+
+	aggFn, t, col := sel.resolve(table.name)
+	if aggFn != "" || t != asTable {
+		return 0, nil, false
+	}
+
+	column, err := table.GetColumnByName(col)
+	if err != nil {
+		return 0, nil, false
+	}
+
+	ranges := make([]*typedValueRange, len(values))
+
+	for i, val := range values {
+		rval, err := val.reduce(nil, nil, table.name)
+		if err != nil || rval.IsNull() || rval.Type() != column.colType {
+			return 0, nil, false
+		}
+
+		ranges[i] = &typedValueRange{
+			lRange: &typedValueSemiRange{val: rval, inclusive: true},
+			hRange: &typedValueSemiRange{val: rval, inclusive: true},
+		}
+	}
+
+	return column.id, ranges, true
 }
 
 func (bexp *InListExp) String() string {
@@ -5910,7 +7073,8 @@ func (stmt *FnDataSourceStmt) resolveListGrants(ctx context.Context, tx *SQLTx,
 
 // DropTableStmt represents a statement to delete a table.
 type DropTableStmt struct {
-	table string
+	table    string
+	ifExists bool
 }
 
 func NewDropTableStmt(table string) *DropTableStmt {
@@ -5931,13 +7095,21 @@ func (stmt *DropTableStmt) inferParameters(ctx context.Context, tx *SQLTx, param
 
 /*
 Exec executes the delete table statement.
-It the table exists, if not it does nothing.
+If the table does not exist, it fails unless IF EXISTS was specified, in which case it does nothing.
 If the table exists, it deletes all the indexes and the table itself.
 Note that this is a soft delete of the index and table key,
 the data is not deleted, but the metadata is updated.
 */
 func (stmt *DropTableStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
+	// IF EXISTS only skips missing tables, not views named as the table
+	if tx.catalog.ExistView(stmt.table) {
+		return nil, fmt.Errorf("%w: %s is a view", ErrTableDoesNotExist, stmt.table)
+	}
+
 	if !tx.catalog.ExistTable(stmt.table) {
+		if stmt.ifExists {
+			return tx, nil
+		}
 		return nil, ErrTableDoesNotExist
 	}
 
@@ -5946,6 +7118,14 @@ func (stmt *DropTableStmt) execAt(ctx context.Context, tx *SQLTx, params map[str
 		return nil, err
 	}
 
+	writer, trigger, err := table.writingTrigger(tx)
+	if err != nil {
+		return nil, err
+	}
+	if trigger != nil {
+		return nil, fmt.Errorf("%w %s because trigger %s on %s writes into it", ErrCannotDropTable, table.name, trigger.name, writer.name)
+	}
+
 	// delete table
 	mappedKey := MapKey(
 		tx.sqlPrefix(),
@@ -5975,6 +7155,16 @@ func (stmt *DropTableStmt) execAt(ctx context.Context, tx *SQLTx, params map[str
 		}
 	}
 
+	err = table.deleteComments(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = table.deleteTriggers(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+
 	// delete checks
 	for name := range table.checkConstraints {
 		key := MapKey(
@@ -5990,8 +7180,13 @@ func (stmt *DropTableStmt) execAt(ctx context.Context, tx *SQLTx, params map[str
 		}
 	}
 
-	// delete indexes
-	for _, index := range table.indexes {
+	if table.countsRows {
+		if err := tx.delete(ctx, table.rowCountKey()); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, index := range table.fullTextIndexes {
 		mappedKey := MapKey(
 			tx.sqlPrefix(),
 			catalogIndexPrefix,
@@ -6003,13 +7198,23 @@ func (stmt *DropTableStmt) execAt(ctx context.Context, tx *SQLTx, params map[str
 		if err != nil {
 			return nil, err
 		}
+	}
 
-		indexKey := MapKey(
+	// delete indexes
+	for _, index := range table.indexes {
+		mappedKey := MapKey(
 			tx.sqlPrefix(),
-			MappedPrefix,
+			catalogIndexPrefix,
+			EncodeID(DatabaseID),
 			EncodeID(table.id),
 			EncodeID(index.id),
 		)
+		err = tx.delete(ctx, mappedKey)
+		if err != nil {
+			return nil, err
+		}
+
+		indexKey := index.entriesPrefix()
 		err = tx.addOnCommittedCallback(func(sqlTx *SQLTx) error {
 			return sqlTx.engine.store.DeleteIndex(indexKey)
 		})
@@ -6028,10 +7233,117 @@ func (stmt *DropTableStmt) execAt(ctx context.Context, tx *SQLTx, params map[str
 	return tx, nil
 }
 
+// CreateViewStmt represents a statement to store a query under a name so that
+// it can be referenced as a table.
+type CreateViewStmt struct {
+	view        string
+	ifNotExists bool
+	query       *SelectStmt
+	sql         string
+}
+
+func (stmt *CreateViewStmt) readOnly() bool {
+	return false
+}
+
+func (stmt *CreateViewStmt) requiredPrivileges() []SQLPrivilege {
+	return []SQLPrivilege{SQLPrivilegeCreate}
+}
+
+func (stmt *CreateViewStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
+	return nil
+}
+
+func (stmt *CreateViewStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
+	if stmt.ifNotExists && tx.catalog.ExistView(stmt.view) {
+		return tx, nil
+	}
+
+	if tx.engine.tableResolveFor(stmt.view) != nil {
+		return nil, fmt.Errorf("%w (%s)", ErrTableAlreadyExists, stmt.view)
+	}
+
+	view, err := tx.catalog.newView(stmt.view, stmt.sql)
+	if err != nil {
+		return nil, err
+	}
+
+	// the query is resolved once so that invalid definitions, including
+	// the ones making views refer to each other, are rejected upfront
+	query, err := view.expand(tx, view.name)
+	if err == nil {
+		var rowReader RowReader
+
+		tx.viewNesting++
+		rowReader, err = query.Resolve(ctx, tx, params, nil)
+		tx.viewNesting--
+
+		if err == nil {
+			rowReader.Close()
+		}
+	}
+	if err != nil {
+		tx.catalog.deleteView(view)
+		return nil, err
+	}
+
+	mappedKey := MapKey(tx.sqlPrefix(), catalogViewPrefix, EncodeID(DatabaseID), []byte(view.name))
+
+	err = tx.set(mappedKey, nil, []byte(view.sql))
+	if err != nil {
+		return nil, err
+	}
+
+	tx.mutatedCatalog = true
+
+	return tx, nil
+}
+
+// DropViewStmt represents a statement to delete a view.
+type DropViewStmt struct {
+	view string
+}
+
+func (stmt *DropViewStmt) readOnly() bool {
+	return false
+}
+
+func (stmt *DropViewStmt) requiredPrivileges() []SQLPrivilege {
+	return []SQLPrivilege{SQLPrivilegeDrop}
+}
+
+func (stmt *DropViewStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
+	return nil
+}
+
+func (stmt *DropViewStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
+	view, err := tx.catalog.GetViewByName(stmt.view)
+	if err != nil {
+		return nil, err
+	}
+
+	mappedKey := MapKey(tx.sqlPrefix(), catalogViewPrefix, EncodeID(DatabaseID), []byte(view.name))
+
+	err = tx.delete(ctx, mappedKey)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.catalog.deleteView(view)
+	if err != nil {
+		return nil, err
+	}
+
+	tx.mutatedCatalog = true
+
+	return tx, nil
+}
+
 // DropIndexStmt represents a statement to delete a table.
 type DropIndexStmt struct {
-	table string
-	cols  []string
+	fullText bool
+	table    string
+	cols     []string
 }
 
 func NewDropIndexStmt(table string, cols []string) *DropIndexStmt {
@@ -6076,9 +7388,18 @@ func (stmt *DropIndexStmt) execAt(ctx context.Context, tx *SQLTx, params map[str
 		cols[i] = col
 	}
 
-	index, err := table.GetIndexByName(indexName(table.name, cols))
-	if err != nil {
-		return nil, err
+	var index *Index
+
+	if stmt.fullText {
+		index = table.fullTextIndexByColID(cols[0].id)
+		if index == nil {
+			return nil, fmt.Errorf("%w (fulltext:%s)", ErrIndexNotFound, indexName(table.name, cols))
+		}
+	} else {
+		index, err = table.GetIndexByName(indexName(table.name, cols))
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// delete index
@@ -6094,18 +7415,17 @@ func (stmt *DropIndexStmt) execAt(ctx context.Context, tx *SQLTx, params map[str
 		return nil, err
 	}
 
-	indexKey := MapKey(
-		tx.sqlPrefix(),
-		MappedPrefix,
-		EncodeID(table.id),
-		EncodeID(index.id),
-	)
+	// entries of full-text indexes are not kept in a dedicated store index,
+	// they are left in place as index ids are never reused
+	if !index.IsFullText() {
+		indexKey := index.entriesPrefix()
 
-	err = tx.addOnCommittedCallback(func(sqlTx *SQLTx) error {
-		return sqlTx.engine.store.DeleteIndex(indexKey)
-	})
-	if err != nil {
-		return nil, err
+		err = tx.addOnCommittedCallback(func(sqlTx *SQLTx) error {
+			return sqlTx.engine.store.DeleteIndex(indexKey)
+		})
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	err = table.deleteIndex(index)
@@ -1914,3 +1914,20 @@ func TestExprSelectors(t *testing.T) {
 		})
 	}
 }
+
+func TestDecodeSelector(t *testing.T) {
+	for _, sel := range [][3]string{
+		{"", "table1", "id"},
+		{"COUNT", "table1", "*"},
+		{"", "t", "data->'name'"},
+	} {
+		aggFn, table, col, err := DecodeSelector(EncodeSelector(sel[0], sel[1], sel[2]))
+		require.NoError(t, err)
+		require.Equal(t, sel, [3]string{aggFn, table, col})
+	}
+
+	for _, sel := range []string{"", "id", "(id)", "COUNT(table1.*"} {
+		_, _, _, err := DecodeSelector(sel)
+		require.ErrorIs(t, err, ErrIllegalArguments)
+	}
+}
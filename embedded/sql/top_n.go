@@ -0,0 +1,188 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"sort"
+)
+
+// topNSorter keeps the first n rows, in sort order, of the rows it's updated with. They are kept in
+// a heap rooted at the last of them, so that a row read afterwards only replaces the root when it's
+// sorted before it. Rows are thus sorted in O(rows * log n) time holding at most n rows in memory,
+// instead of sorting all the rows before skipping the ones beyond the limit. Rows with equal sort
+// keys are kept in the order they are read.
+type topNSorter struct {
+	n       int
+	entries []topNEntry
+	read    uint64
+
+	compareKeys func(t1, t2 Tuple) (int, error)
+
+	resources *resourceTracker
+}
+
+type topNEntry struct {
+	row  *Row
+	key  Tuple
+	seq  uint64 // position of the row in the order it was read
+	size int64  // memory accounted for the row
+}
+
+// newTopNRowReader returns a reader of the first n rows of rowReader sorted by ordExps,
+// used in place of a sortRowReader when the sorted rows are limited
+func newTopNRowReader(rowReader RowReader, ordExps []*OrdExp, n int) (*sortRowReader, error) {
+	sr, err := newSortRowReader(rowReader, ordExps)
+	if err != nil {
+		return nil, err
+	}
+
+	sr.topN = &topNSorter{
+		n:           n,
+		entries:     make([]topNEntry, 0, n),
+		compareKeys: sr.compareKeys,
+		resources:   rowReader.Tx().resources,
+	}
+	return sr, nil
+}
+
+func (sr *sortRowReader) readTopN(ctx context.Context) (resultReader, error) {
+	key := make(Tuple, len(sr.ordExps))
+
+	for {
+		row, err := sr.rowReader.Read(ctx)
+		if err == ErrNoMoreRows {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		err = sr.evalSortExps(row, key)
+		if err != nil {
+			return nil, err
+		}
+
+		err = sr.topN.update(row, key)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sr.topN.finalize()
+}
+
+// less returns whether e1 is sorted before e2
+func (s *topNSorter) less(e1, e2 *topNEntry) (bool, error) {
+	res, err := s.compareKeys(e1.key, e2.key)
+	if err != nil {
+		return false, err
+	}
+	return res < 0 || (res == 0 && e1.seq < e2.seq), nil
+}
+
+func (s *topNSorter) update(row *Row, key Tuple) error {
+	e := topNEntry{row: row, key: key, seq: s.read}
+	s.read++
+
+	if len(s.entries) == s.n {
+		isBefore, err := s.less(&e, &s.entries[0])
+		if err != nil || !isBefore {
+			return err
+		}
+	}
+
+	size, err := s.resources.allocateRow(row)
+	if err != nil {
+		return err
+	}
+	e.size = size
+	e.key = append(Tuple(nil), key...)
+
+	if len(s.entries) < s.n {
+		s.entries = append(s.entries, e)
+		return s.siftUp(len(s.entries) - 1)
+	}
+
+	s.resources.release(s.entries[0].size)
+	s.entries[0] = e
+	return s.siftDown(0)
+}
+
+// siftUp moves the entry at position i towards the root until no entry under it is sorted after it
+func (s *topNSorter) siftUp(i int) error {
+	for i > 0 {
+		parent := (i - 1) / 2
+
+		isBefore, err := s.less(&s.entries[parent], &s.entries[i])
+		if err != nil || !isBefore {
+			return err
+		}
+
+		s.entries[parent], s.entries[i] = s.entries[i], s.entries[parent]
+		i = parent
+	}
+	return nil
+}
+
+// siftDown moves the entry at position i away from the root until it's sorted after its children
+func (s *topNSorter) siftDown(i int) error {
+	for {
+		last := i
+
+		for _, child := range []int{2*i + 1, 2*i + 2} {
+			if child >= len(s.entries) {
+				break
+			}
+
+			isBefore, err := s.less(&s.entries[last], &s.entries[child])
+			if err != nil {
+				return err
+			}
+			if isBefore {
+				last = child
+			}
+		}
+
+		if last == i {
+			return nil
+		}
+
+		s.entries[i], s.entries[last] = s.entries[last], s.entries[i]
+		i = last
+	}
+}
+
+func (s *topNSorter) finalize() (resultReader, error) {
+	var outErr error
+	sort.Slice(s.entries, func(i, j int) bool {
+		isBefore, err := s.less(&s.entries[i], &s.entries[j])
+		if err != nil {
+			outErr = err
+		}
+		return isBefore
+	})
+	if outErr != nil {
+		return nil, outErr
+	}
+
+	rows := make([]*Row, len(s.entries))
+	for i, e := range s.entries {
+		rows[i] = e.row
+		s.resources.release(e.size)
+	}
+	return &bufferResultReader{sortBuf: rows}, nil
+}
@@ -0,0 +1,264 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+)
+
+func setupTopNTest(t testing.TB, opts *Options, nRows int) *Engine {
+	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(t, err)
+	t.Cleanup(func() { st.Close() })
+
+	engine, err := NewEngine(st, opts.WithPrefix(sqlPrefix))
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE scores (id INTEGER AUTO_INCREMENT, score INTEGER, name VARCHAR, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	rnd := rand.New(rand.NewSource(42))
+
+	for inserted := 0; inserted < nRows; {
+		tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithExplicitClose(true))
+		require.NoError(t, err)
+
+		for i := 0; i < 1000 && inserted < nRows; i++ {
+			// few distinct scores, so that many rows are tied
+			var score interface{}
+			if n := rnd.Intn(nRows/10 + 1); n > 0 {
+				score = n
+			}
+
+			_, _, err := engine.Exec(context.Background(), tx, "INSERT INTO scores (score, name) VALUES (@score, @name)", map[string]interface{}{
+				"score": score,
+				"name":  fmt.Sprintf("name%d", rnd.Intn(100)),
+			})
+			require.NoError(t, err)
+			inserted++
+		}
+
+		err = tx.Commit(context.Background())
+		require.NoError(t, err)
+	}
+	return engine
+}
+
+// sortReaderOf returns the reader sorting the rows returned by r
+func sortReaderOf(t *testing.T, r RowReader) *sortRowReader {
+	for {
+		switch rr := r.(type) {
+		case *sortRowReader:
+			return rr
+		case *projectedRowReader:
+			r = rr.rowReader
+		case *limitRowReader:
+			r = rr.rowReader
+		case *offsetRowReader:
+			r = rr.rowReader
+		case *distinctRowReader:
+			r = rr.rowReader
+		default:
+			require.Fail(t, "no sort reader", "%T", r)
+		}
+	}
+}
+
+func TestTopN(t *testing.T) {
+	engine := setupTopNTest(t, DefaultOptions(), 1000)
+
+	rawValues := func(t *testing.T, e *Engine, sql string) [][]interface{} {
+		rows, err := e.queryAll(context.Background(), nil, sql, nil)
+		require.NoError(t, err)
+
+		values := make([][]interface{}, len(rows))
+		for i, row := range rows {
+			for _, v := range row.ValuesByPosition {
+				values[i] = append(values[i], v.RawValue())
+			}
+		}
+		return values
+	}
+
+	usesTopN := func(t *testing.T, sql string) bool {
+		r, err := engine.Query(context.Background(), nil, sql, nil)
+		require.NoError(t, err)
+		defer r.Close()
+
+		return sortReaderOf(t, r).topN != nil
+	}
+
+	t.Run("same sort keys as sorting all the rows", func(t *testing.T) {
+		for _, d := range []struct {
+			orderBy       string
+			limit, offset int
+			sortedByName  bool // whether rows with the same score are sorted by name, rather than just tied
+		}{
+			{"score DESC", 10, 0, false},
+			{"score", 10, 0, false},
+			{"score DESC", 1, 0, false},
+			{"score DESC", 25, 40, false},
+			{"score NULLS FIRST", 5, 0, false},
+			{"score DESC NULLS LAST", 995, 0, false},
+			{"score DESC", 1024, 0, false},
+			{"score DESC, name", 30, 3, true},
+			{"name, score DESC", 12, 0, true},
+			{"score DESC, -id", 20, 0, true},
+		} {
+			sql := fmt.Sprintf("SELECT score, name FROM scores ORDER BY %s LIMIT %d OFFSET %d", d.orderBy, d.limit, d.offset)
+
+			t.Run(sql, func(t *testing.T) {
+				require.True(t, usesTopN(t, sql))
+
+				all := rawValues(t, engine, fmt.Sprintf("SELECT score, name FROM scores ORDER BY %s", d.orderBy))
+
+				expected := all[min(d.offset, len(all)):min(d.offset+d.limit, len(all))]
+
+				rows := rawValues(t, engine, sql)
+				require.Len(t, rows, len(expected))
+
+				for i := range rows {
+					if d.sortedByName {
+						require.Equal(t, expected[i], rows[i])
+					} else {
+						require.Equal(t, expected[i][0], rows[i][0])
+					}
+				}
+			})
+		}
+	})
+
+	t.Run("rows tied at the limit are taken in the order they are read", func(t *testing.T) {
+		engine := setupTopNTest(t, DefaultOptions(), 0)
+
+		_, _, err := engine.Exec(context.Background(), nil, `
+			INSERT INTO scores (score, name) VALUES (5, 'a'), (9, 'b'), (9, 'c'), (7, 'd'), (9, 'e'), (1, 'f'), (NULL, 'g'), (9, 'h')
+		`, nil)
+		require.NoError(t, err)
+
+		require.Equal(t, [][]interface{}{{"b"}, {"c"}}, rawValues(t, engine, "SELECT name FROM scores ORDER BY score DESC LIMIT 2"))
+		require.Equal(t, [][]interface{}{{"e"}, {"h"}, {"d"}}, rawValues(t, engine, "SELECT name FROM scores ORDER BY score DESC LIMIT 3 OFFSET 2"))
+		require.Equal(t, [][]interface{}{{"g"}, {"f"}, {"a"}}, rawValues(t, engine, "SELECT name FROM scores ORDER BY score LIMIT 3"))
+		require.Equal(t, [][]interface{}{{"b"}, {"c"}, {"e"}, {"h"}}, rawValues(t, engine, "SELECT name FROM scores ORDER BY score DESC FETCH FIRST 2 ROWS WITH TIES"))
+		require.Empty(t, rawValues(t, engine, "SELECT name FROM scores ORDER BY score DESC LIMIT 2 OFFSET 8"))
+	})
+
+	t.Run("same rows as sorting all the rows when ordering is stable", func(t *testing.T) {
+		engine := setupTopNTest(t, DefaultOptions().WithStableOrdering(true), 1000)
+
+		all := rawValues(t, engine, "SELECT id, score FROM scores ORDER BY score DESC")
+		require.Equal(t, all[20:70], rawValues(t, engine, "SELECT id, score FROM scores ORDER BY score DESC LIMIT 50 OFFSET 20"))
+	})
+
+	t.Run("aggregated rows", func(t *testing.T) {
+		sql := "SELECT name, COUNT(*) FROM scores GROUP BY name ORDER BY 2 DESC, name LIMIT 5"
+		require.True(t, usesTopN(t, sql))
+
+		all := rawValues(t, engine, "SELECT name, COUNT(*) FROM scores GROUP BY name ORDER BY 2 DESC, name")
+		require.Equal(t, all[:5], rawValues(t, engine, sql))
+	})
+
+	t.Run("all the rows are sorted when needed", func(t *testing.T) {
+		for _, sql := range []string{
+			"SELECT score FROM scores ORDER BY score DESC",
+			"SELECT score FROM scores ORDER BY score DESC LIMIT 0",
+			"SELECT score FROM scores ORDER BY score DESC FETCH FIRST 10 ROWS WITH TIES",
+			"SELECT DISTINCT score FROM scores ORDER BY score DESC LIMIT 10",
+			"SELECT score FROM scores ORDER BY score DESC LIMIT 1025",
+			"SELECT score FROM scores ORDER BY score DESC LIMIT 10 OFFSET 1015",
+		} {
+			require.False(t, usesTopN(t, sql), sql)
+		}
+
+		// rows are read in the order of the index
+		r, err := engine.Query(context.Background(), nil, "SELECT id FROM scores ORDER BY id DESC LIMIT 10", nil)
+		require.NoError(t, err)
+		defer r.Close()
+
+		require.Empty(t, r.ScanSpecs().orderBySortExps)
+	})
+
+	t.Run("limit given as a parameter", func(t *testing.T) {
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT score FROM scores ORDER BY score DESC LIMIT @n", map[string]interface{}{"n": 3})
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+
+		_, err = engine.queryAll(context.Background(), nil, "SELECT score FROM scores ORDER BY score DESC LIMIT @n", nil)
+		require.ErrorIs(t, err, ErrMissingParameter)
+	})
+
+	t.Run("errors sorting rows", func(t *testing.T) {
+		_, err := engine.queryAll(context.Background(), nil, "SELECT score FROM scores ORDER BY 100 / (score - score) LIMIT 3", nil)
+		require.ErrorIs(t, err, ErrDivisionByZero)
+	})
+
+	t.Run("rows held in memory are bounded", func(t *testing.T) {
+		ctx := ContextWithResourceLimits(context.Background(), ResourceLimits{MaxMemory: 4096})
+
+		rows, err := engine.queryAll(ctx, nil, "SELECT name FROM scores ORDER BY name DESC LIMIT 10", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 10)
+
+		_, err = engine.queryAll(ctx, nil, "SELECT name FROM scores ORDER BY name DESC", nil)
+		require.ErrorIs(t, err, ErrMaxMemoryExceeded)
+	})
+}
+
+func BenchmarkTopN(b *testing.B) {
+	engine := setupTopNTest(b, DefaultOptions(), 100_000)
+
+	ordExps := []*OrdExp{{exp: &ColSelector{col: "score"}, descOrder: true}}
+
+	for name, newReader := range map[string]func(r RowReader) (RowReader, error){
+		"top-n": func(r RowReader) (RowReader, error) {
+			return newTopNRowReader(r, ordExps, 10)
+		},
+		"full sort": func(r RowReader) (RowReader, error) {
+			sr, err := newSortRowReader(r, ordExps)
+			if err != nil {
+				return nil, err
+			}
+			return newLimitRowReader(sr, 10), nil
+		},
+	} {
+		b.Run(name, func(b *testing.B) {
+			for b.Loop() {
+				tx, err := engine.NewTx(context.Background(), DefaultTxOptions().WithReadOnly(true))
+				require.NoError(b, err)
+
+				r, err := NewSelectStmt(nil, NewTableRef("scores", ""), nil, nil, nil, nil).Resolve(context.Background(), tx, nil, nil)
+				require.NoError(b, err)
+
+				r, err = newReader(r)
+				require.NoError(b, err)
+
+				rows, err := ReadAllRows(context.Background(), r)
+				require.NoError(b, err)
+				require.Len(b, rows, 10)
+
+				r.Close()
+				tx.Cancel()
+			}
+		})
+	}
+}
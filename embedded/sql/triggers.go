@@ -0,0 +1,457 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/codenotary/immudb/embedded/store"
+)
+
+// TriggerEvent is the kind of write a trigger is fired after.
+type TriggerEvent byte
+
+const (
+	TriggerOnInsert TriggerEvent = iota + 1
+	TriggerOnUpdate
+	TriggerOnDelete
+)
+
+func (e TriggerEvent) String() string {
+	switch e {
+	case TriggerOnInsert:
+		return "INSERT"
+	case TriggerOnUpdate:
+		return "UPDATE"
+	case TriggerOnDelete:
+		return "DELETE"
+	}
+	return fmt.Sprintf("TriggerEvent(%d)", byte(e))
+}
+
+// names under which the values of the row a trigger is fired for are referenced, e.g. NEW.id
+const (
+	triggerNewRow = "new"
+	triggerOldRow = "old"
+)
+
+// Trigger is a statement executed once for each row written to its table by the statements of
+// its event, within the same transaction and after the triggering statement. The statement can
+// refer to the values of the row as NEW.col and OLD.col, OLD values being NULL for inserted rows
+// and NEW values being NULL for deleted ones.
+type Trigger struct {
+	table *Table
+	name  string
+	event TriggerEvent
+	sql   string
+}
+
+func (t *Trigger) Name() string {
+	return t.name
+}
+
+func (t *Trigger) Event() TriggerEvent {
+	return t.event
+}
+
+// SQL returns the text of the statement executed by the trigger.
+func (t *Trigger) SQL() string {
+	return t.sql
+}
+
+func (t *Table) newTrigger(name string, event TriggerEvent, sql string) (*Trigger, error) {
+	if len(name) == 0 || len(sql) == 0 || event < TriggerOnInsert || event > TriggerOnDelete {
+		return nil, ErrIllegalArguments
+	}
+
+	if _, exists := t.triggersByName[name]; exists {
+		return nil, fmt.Errorf("%w (%s)", ErrTriggerAlreadyExists, name)
+	}
+
+	trigger := &Trigger{table: t, name: name, event: event, sql: sql}
+	t.triggersByName[name] = trigger
+
+	return trigger, nil
+}
+
+func (t *Table) GetTriggerByName(name string) (*Trigger, error) {
+	trigger, exists := t.triggersByName[name]
+	if !exists {
+		return nil, fmt.Errorf("%w (%s)", ErrTriggerDoesNotExist, name)
+	}
+	return trigger, nil
+}
+
+// GetTriggers returns the triggers of the table sorted by name, which is the order they're fired in.
+func (t *Table) GetTriggers() []*Trigger {
+	triggers := make([]*Trigger, 0, len(t.triggersByName))
+	for _, trigger := range t.triggersByName {
+		triggers = append(triggers, trigger)
+	}
+
+	sort.Slice(triggers, func(i, j int) bool {
+		return triggers[i].name < triggers[j].name
+	})
+	return triggers
+}
+
+func (t *Table) triggersOn(event TriggerEvent) []*Trigger {
+	if len(t.triggersByName) == 0 {
+		return nil
+	}
+
+	var triggers []*Trigger
+	for _, trigger := range t.GetTriggers() {
+		if trigger.event == event {
+			triggers = append(triggers, trigger)
+		}
+	}
+	return triggers
+}
+
+// statement parses the statement executed by the trigger. Statements are parsed every time
+// the trigger is fired, as values substituted into them on execution are kept.
+func (t *Trigger) statement(tx *SQLTx) (SQLStmt, error) {
+	stmts, err := parseSQL(strings.NewReader(t.sql), tx.engine.functions, tx.engine.caseSensitiveIds)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(stmts) != 1 || triggerTargetTable(stmts[0]) == nil {
+		return nil, ErrCorruptedData
+	}
+	return stmts[0], nil
+}
+
+// triggerTargetTable returns the table written by the statement when it can be executed by a trigger
+func triggerTargetTable(stmt SQLStmt) *tableRef {
+	switch s := stmt.(type) {
+	case *UpsertIntoStmt:
+		return s.tableRef
+	case *UpdateStmt:
+		return s.tableRef
+	case *DeleteFromStmt:
+		return s.tableRef
+	}
+	return nil
+}
+
+// writingTrigger returns a trigger of another table whose statement writes into the table, if any
+func (t *Table) writingTrigger(tx *SQLTx) (*Table, *Trigger, error) {
+	for _, table := range tx.catalog.GetTables() {
+		if table.id == t.id {
+			continue
+		}
+
+		for _, trigger := range table.GetTriggers() {
+			stmt, err := trigger.statement(tx)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if triggerTargetTable(stmt).table == t.name {
+				return table, trigger, nil
+			}
+		}
+	}
+	return nil, nil, nil
+}
+
+// rowChange holds the values of a row written by a statement, kept for the triggers of the table
+// to be fired once the statement has written all of its rows.
+type rowChange struct {
+	old map[uint32]TypedValue
+	new map[uint32]TypedValue
+}
+
+func (c *rowChange) params(table *Table) map[string]interface{} {
+	params := make(map[string]interface{}, 2*len(table.cols))
+
+	for _, col := range table.cols {
+		params[triggerOldRow+"."+col.colName] = rowChangeValue(c.old, col)
+		params[triggerNewRow+"."+col.colName] = rowChangeValue(c.new, col)
+	}
+	return params
+}
+
+func rowChangeValue(valuesByColID map[uint32]TypedValue, col *Column) TypedValue {
+	v := valuesByColID[col.id]
+	if v == nil {
+		return NewNull(col.colType)
+	}
+	return v
+}
+
+// fireTriggers executes the triggers of the table defined for the event once for each of the
+// written rows, in the order of their names. Triggers are fired after the triggering statement
+// has written all of its rows, so that rows written by triggers are not read by the statement.
+// Rows written by triggers are not counted as updated by the triggering statement.
+func (tx *SQLTx) fireTriggers(ctx context.Context, table *Table, event TriggerEvent, changes []rowChange) error {
+	triggers := table.triggersOn(event)
+	if len(triggers) == 0 || len(changes) == 0 {
+		return nil
+	}
+
+	// triggers writing to tables with triggers of their own may fire each other endlessly
+	if tx.triggerDepth >= maxTriggerDepth {
+		return fmt.Errorf("%w (%s)", ErrMaxTriggerDepthExceeded, triggers[0].name)
+	}
+
+	tx.triggerDepth++
+	defer func() { tx.triggerDepth-- }()
+
+	updatedRows := tx.updatedRows
+	defer func() { tx.updatedRows = updatedRows }()
+
+	for _, change := range changes {
+		params := change.params(table)
+
+		for _, trigger := range triggers {
+			body, err := trigger.statement(tx)
+			if err != nil {
+				return err
+			}
+
+			_, err = body.execAt(ctx, tx, params)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// CreateTriggerStmt represents a statement to execute a write statement after each row
+// written to a table by the statements of the given event.
+type CreateTriggerStmt struct {
+	trigger     string
+	ifNotExists bool
+	event       TriggerEvent
+	table       string
+	body        SQLStmt
+	sql         string
+}
+
+func (stmt *CreateTriggerStmt) readOnly() bool {
+	return false
+}
+
+func (stmt *CreateTriggerStmt) requiredPrivileges() []SQLPrivilege {
+	return []SQLPrivilege{SQLPrivilegeCreate}
+}
+
+func (stmt *CreateTriggerStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
+	return nil
+}
+
+func (stmt *CreateTriggerStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
+	table, err := tx.catalog.GetTableByName(stmt.table)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, exists := table.triggersByName[stmt.trigger]; exists && stmt.ifNotExists {
+		return tx, nil
+	}
+
+	// the written table is resolved when the trigger is fired, so it only needs to exist upfront
+	if _, err := triggerTargetTable(stmt.body).referencedTable(tx); err != nil {
+		return nil, err
+	}
+
+	trigger, err := table.newTrigger(stmt.trigger, stmt.event, stmt.sql)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.set(triggerKey(tx.sqlPrefix(), table.id, trigger.name), nil, encodeTrigger(trigger))
+	if err != nil {
+		return nil, err
+	}
+
+	tx.mutatedCatalog = true
+
+	return tx, nil
+}
+
+// DropTriggerStmt represents a statement to delete a trigger of a table.
+type DropTriggerStmt struct {
+	trigger string
+	table   string
+}
+
+func (stmt *DropTriggerStmt) readOnly() bool {
+	return false
+}
+
+func (stmt *DropTriggerStmt) requiredPrivileges() []SQLPrivilege {
+	return []SQLPrivilege{SQLPrivilegeDrop}
+}
+
+func (stmt *DropTriggerStmt) inferParameters(ctx context.Context, tx *SQLTx, params map[string]SQLValueType) error {
+	return nil
+}
+
+func (stmt *DropTriggerStmt) execAt(ctx context.Context, tx *SQLTx, params map[string]interface{}) (*SQLTx, error) {
+	table, err := tx.catalog.GetTableByName(stmt.table)
+	if err != nil {
+		return nil, err
+	}
+
+	trigger, err := table.GetTriggerByName(stmt.trigger)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.delete(ctx, triggerKey(tx.sqlPrefix(), table.id, trigger.name))
+	if err != nil {
+		return nil, err
+	}
+
+	delete(table.triggersByName, trigger.name)
+
+	tx.mutatedCatalog = true
+
+	return tx, nil
+}
+
+func triggerKey(sqlPrefix []byte, tableID uint32, name string) []byte {
+	return MapKey(
+		sqlPrefix,
+		catalogTriggerPrefix,
+		EncodeID(DatabaseID),
+		EncodeID(tableID),
+		[]byte(name),
+	)
+}
+
+func encodeTrigger(trigger *Trigger) []byte {
+	return append([]byte{byte(trigger.event)}, trigger.sql...)
+}
+
+// deleteTriggers removes the triggers of the table
+func (t *Table) deleteTriggers(ctx context.Context, tx *SQLTx) error {
+	for name := range t.triggersByName {
+		err := tx.delete(ctx, triggerKey(tx.sqlPrefix(), t.id, name))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *Table) loadTriggers(ctx context.Context, sqlPrefix []byte, tx *store.OngoingTx, copyToTx bool) error {
+	prefix := MapKey(sqlPrefix, catalogTriggerPrefix, EncodeID(DatabaseID), EncodeID(t.id))
+
+	return iteratePrefix(ctx, tx, prefix, func(key, value []byte, deleted bool) error {
+		if deleted {
+			return nil
+		}
+
+		name, err := unmapTriggerName(sqlPrefix, key)
+		if err != nil {
+			return err
+		}
+
+		if len(value) < 2 {
+			return ErrCorruptedData
+		}
+
+		_, err = t.newTrigger(name, TriggerEvent(value[0]), string(value[1:]))
+		if errors.Is(err, ErrIllegalArguments) {
+			return fmt.Errorf("%w: invalid trigger %s", ErrCorruptedData, name)
+		}
+		if err != nil {
+			return err
+		}
+
+		if copyToTx {
+			return tx.Set(key, nil, value)
+		}
+		return nil
+	})
+}
+
+func unmapTriggerName(prefix, mkey []byte) (string, error) {
+	enc, err := trimPrefix(prefix, mkey, []byte(catalogTriggerPrefix))
+	if err != nil {
+		return "", err
+	}
+
+	if len(enc) <= 2*EncIDLen || binary.BigEndian.Uint32(enc) != DatabaseID {
+		return "", ErrCorruptedData
+	}
+	return string(enc[2*EncIDLen:]), nil
+}
+
+// decodeRowValues decodes the values of the columns of the table from an encoded row,
+// values of columns unknown to the table are skipped
+func decodeRowValues(table *Table, v []byte) (map[uint32]TypedValue, error) {
+	if len(v) < EncLenLen {
+		return nil, ErrCorruptedData
+	}
+
+	voff := 0
+
+	cols := int(binary.BigEndian.Uint32(v[voff:]))
+	voff += EncLenLen
+
+	valuesByColID := make(map[uint32]TypedValue, cols)
+
+	for i := 0; i < cols; i++ {
+		if len(v)-voff < EncIDLen {
+			return nil, ErrCorruptedData
+		}
+
+		colID := binary.BigEndian.Uint32(v[voff:])
+		voff += EncIDLen
+
+		col, err := table.GetColumnByID(colID)
+		if errors.Is(err, ErrColumnDoesNotExist) {
+			vlen, n, err := DecodeValueLength(v[voff:])
+			if err != nil {
+				return nil, err
+			}
+			voff += n + vlen
+
+			continue
+		}
+		if err != nil {
+			return nil, ErrCorruptedData
+		}
+
+		val, n, err := DecodeValue(v[voff:], col.colType)
+		if err != nil {
+			return nil, err
+		}
+
+		voff += n
+
+		valuesByColID[colID] = val
+	}
+
+	err := table.verifyRowChecksum(v, voff)
+	if err != nil {
+		return nil, err
+	}
+	return valuesByColID, nil
+}
@@ -0,0 +1,286 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseCreateTrigger(t *testing.T) {
+	testCases := []struct {
+		input       string
+		trigger     string
+		ifNotExists bool
+		event       TriggerEvent
+		table       string
+		sql         string
+	}{
+		{
+			input:   "CREATE TRIGGER audit AFTER INSERT ON users FOR EACH ROW INSERT INTO audit_log (user_id) VALUES (NEW.id)",
+			trigger: "audit",
+			event:   TriggerOnInsert,
+			table:   "users",
+			sql:     "INSERT INTO audit_log (user_id) VALUES (NEW.id)",
+		},
+		{
+			input:       "create trigger if not exists t1 after update on users for each row\n\tupdate stats set n = n + 1 where name = 'a;b';",
+			trigger:     "t1",
+			ifNotExists: true,
+			event:       TriggerOnUpdate,
+			table:       "users",
+			sql:         "update stats set n = n + 1 where name = 'a;b'",
+		},
+		{
+			input:   "CREATE TRIGGER t2 AFTER DELETE ON users FOR EACH ROW DELETE FROM sessions WHERE user_id = OLD.id; SELECT * FROM users",
+			trigger: "t2",
+			event:   TriggerOnDelete,
+			table:   "users",
+			sql:     "DELETE FROM sessions WHERE user_id = OLD.id",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.input, func(t *testing.T) {
+			res, err := ParseSQLString(tc.input)
+			require.NoError(t, err)
+
+			stmt, ok := res[0].(*CreateTriggerStmt)
+			require.True(t, ok)
+			require.Equal(t, tc.trigger, stmt.trigger)
+			require.Equal(t, tc.ifNotExists, stmt.ifNotExists)
+			require.Equal(t, tc.event, stmt.event)
+			require.Equal(t, tc.table, stmt.table)
+			require.Equal(t, tc.sql, stmt.sql)
+			require.NotNil(t, stmt.body)
+		})
+	}
+
+	res, err := ParseSQLString("DROP TRIGGER audit ON users")
+	require.NoError(t, err)
+	require.Equal(t, []SQLStmt{&DropTriggerStmt{trigger: "audit", table: "users"}}, res)
+
+	// only write statements can be executed by triggers
+	_, err = ParseSQLString("CREATE TRIGGER audit AFTER INSERT ON users FOR EACH ROW SELECT * FROM users")
+	require.Error(t, err)
+
+	_, err = ParseSQLString("CREATE TRIGGER audit BEFORE INSERT ON users FOR EACH ROW DELETE FROM users")
+	require.Error(t, err)
+}
+
+func TestTriggers(t *testing.T) {
+	dir := t.TempDir()
+
+	st, err := store.Open(dir, store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(t, err)
+
+	engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		CREATE TABLE users (id INTEGER AUTO_INCREMENT, name VARCHAR, balance INTEGER, PRIMARY KEY id);
+		CREATE TABLE audit_log (id INTEGER AUTO_INCREMENT, op VARCHAR, user_id INTEGER, old_balance INTEGER, new_balance INTEGER, PRIMARY KEY id);
+	`, nil)
+	require.NoError(t, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, `
+		CREATE TRIGGER audit_insert AFTER INSERT ON users FOR EACH ROW
+			INSERT INTO audit_log (op, user_id, old_balance, new_balance) VALUES ('insert', NEW.id, OLD.balance, NEW.balance);
+		CREATE TRIGGER audit_update AFTER UPDATE ON users FOR EACH ROW
+			INSERT INTO audit_log (op, user_id, old_balance, new_balance) VALUES ('update', NEW.id, OLD.balance, NEW.balance);
+		CREATE TRIGGER audit_delete AFTER DELETE ON users FOR EACH ROW
+			INSERT INTO audit_log (op, user_id, old_balance, new_balance) VALUES ('delete', OLD.id, OLD.balance, NEW.balance);
+	`, nil)
+	require.NoError(t, err)
+
+	rawValues := func(t *testing.T, e *Engine, sql string) [][]interface{} {
+		rows, err := e.queryAll(context.Background(), nil, sql, nil)
+		require.NoError(t, err)
+
+		values := make([][]interface{}, len(rows))
+		for i, row := range rows {
+			for _, v := range row.ValuesByPosition {
+				values[i] = append(values[i], v.RawValue())
+			}
+		}
+		return values
+	}
+
+	auditLog := func(t *testing.T, e *Engine) [][]interface{} {
+		return rawValues(t, e, "SELECT op, user_id, old_balance, new_balance FROM audit_log ORDER BY id")
+	}
+
+	t.Run("triggers fire after each written row", func(t *testing.T) {
+		_, txs, err := engine.Exec(context.Background(), nil, "INSERT INTO users (name, balance) VALUES ('alice', 10), ('bob', 20)", nil)
+		require.NoError(t, err)
+		require.Len(t, txs, 1)
+
+		// rows written by triggers are not counted as updated by the statement
+		require.Equal(t, 2, txs[0].UpdatedRows())
+
+		_, _, err = engine.Exec(context.Background(), nil, "UPDATE users SET balance = balance + 5 WHERE name = 'bob'", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "UPSERT INTO users (id, name, balance) VALUES (1, 'alice', 0), (3, 'carol', 30)", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "DELETE FROM users WHERE id = 1", nil)
+		require.NoError(t, err)
+
+		require.Equal(t, [][]interface{}{
+			{"insert", int64(1), nil, int64(10)},
+			{"insert", int64(2), nil, int64(20)},
+			{"update", int64(2), int64(20), int64(25)},
+			{"insert", int64(3), nil, int64(30)},
+			{"update", int64(1), int64(10), int64(0)},
+			{"delete", int64(1), int64(0), nil},
+		}, auditLog(t, engine))
+	})
+
+	t.Run("triggers run within the transaction of the triggering statement", func(t *testing.T) {
+		tx, _, err := engine.Exec(context.Background(), nil, "BEGIN; INSERT INTO users (name, balance) VALUES ('dave', 40)", nil)
+		require.NoError(t, err)
+
+		rows, err := engine.queryAll(context.Background(), tx, "SELECT op FROM audit_log", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 7)
+
+		require.Len(t, rawValues(t, engine, "SELECT * FROM audit_log"), 6)
+
+		err = tx.Cancel()
+		require.NoError(t, err)
+
+		require.Len(t, rawValues(t, engine, "SELECT * FROM audit_log"), 6)
+	})
+
+	t.Run("failing triggers fail the triggering statement", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, `
+			CREATE TABLE limits (id INTEGER, PRIMARY KEY id);
+			CREATE TRIGGER one_limit AFTER INSERT ON limits FOR EACH ROW INSERT INTO limits (id) VALUES (0);
+		`, nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO limits (id) VALUES (1), (2)", nil)
+		require.ErrorIs(t, err, store.ErrKeyAlreadyExists)
+
+		require.Empty(t, rawValues(t, engine, "SELECT * FROM limits"))
+	})
+
+	t.Run("recursive triggers are limited", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, `
+			CREATE TABLE chain (id INTEGER, PRIMARY KEY id);
+			CREATE TRIGGER chain_next AFTER INSERT ON chain FOR EACH ROW INSERT INTO chain (id) VALUES (NEW.id + 1);
+		`, nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO chain (id) VALUES (1)", nil)
+		require.ErrorIs(t, err, ErrMaxTriggerDepthExceeded)
+
+		require.Empty(t, rawValues(t, engine, "SELECT * FROM chain"))
+
+		// triggers stop firing each other once they write no rows
+		_, _, err = engine.Exec(context.Background(), nil, `
+			DROP TRIGGER chain_next ON chain;
+			CREATE TRIGGER chain_next AFTER INSERT ON chain FOR EACH ROW
+				INSERT INTO chain (id) SELECT id + 1 FROM chain WHERE id = NEW.id AND id < 5;
+		`, nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO chain (id) VALUES (1)", nil)
+		require.NoError(t, err)
+
+		require.Equal(t, [][]interface{}{{int64(1)}, {int64(2)}, {int64(3)}, {int64(4)}, {int64(5)}}, rawValues(t, engine, "SELECT id FROM chain"))
+	})
+
+	t.Run("trigger definitions", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "CREATE TRIGGER audit_insert AFTER INSERT ON users FOR EACH ROW DELETE FROM audit_log", nil)
+		require.ErrorIs(t, err, ErrTriggerAlreadyExists)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE TRIGGER IF NOT EXISTS audit_insert AFTER INSERT ON users FOR EACH ROW DELETE FROM audit_log", nil)
+		require.NoError(t, err)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE TRIGGER t AFTER INSERT ON unknown FOR EACH ROW DELETE FROM audit_log", nil)
+		require.ErrorIs(t, err, ErrTableDoesNotExist)
+
+		_, _, err = engine.Exec(context.Background(), nil, "CREATE TRIGGER t AFTER INSERT ON users FOR EACH ROW DELETE FROM unknown", nil)
+		require.ErrorIs(t, err, ErrTableDoesNotExist)
+
+		_, _, err = engine.Exec(context.Background(), nil, "DROP TRIGGER unknown ON users", nil)
+		require.ErrorIs(t, err, ErrTriggerDoesNotExist)
+	})
+
+	require.NoError(t, st.Close())
+
+	t.Run("triggers are kept in the catalog", func(t *testing.T) {
+		st, err := store.Open(dir, store.DefaultOptions().WithMultiIndexing(true))
+		require.NoError(t, err)
+		defer closeStore(t, st)
+
+		engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+		require.NoError(t, err)
+
+		catalog, err := engine.Catalog(context.Background(), nil)
+		require.NoError(t, err)
+
+		table, err := catalog.GetTableByName("users")
+		require.NoError(t, err)
+
+		triggers := table.GetTriggers()
+		require.Len(t, triggers, 3)
+		require.Equal(t, "audit_delete", triggers[0].Name())
+		require.Equal(t, TriggerOnDelete, triggers[0].Event())
+		require.Equal(t, "INSERT INTO audit_log (op, user_id, old_balance, new_balance) VALUES ('delete', OLD.id, OLD.balance, NEW.balance)", triggers[0].SQL())
+
+		_, _, err = engine.Exec(context.Background(), nil, "DELETE FROM users WHERE id = 2", nil)
+		require.NoError(t, err)
+		require.Equal(t, []interface{}{"delete", int64(2), int64(25), nil}, auditLog(t, engine)[6])
+
+		_, _, err = engine.Exec(context.Background(), nil, `
+			DROP TRIGGER audit_delete ON users;
+			DELETE FROM users WHERE id = 3;
+		`, nil)
+		require.NoError(t, err)
+		require.Len(t, auditLog(t, engine), 7)
+
+		// tables written by the triggers of other tables can not be dropped
+		_, _, err = engine.Exec(context.Background(), nil, "DROP TABLE audit_log", nil)
+		require.ErrorIs(t, err, ErrCannotDropTable)
+		require.ErrorContains(t, err, "audit_insert")
+
+		_, _, err = engine.Exec(context.Background(), nil, "DROP TABLE IF EXISTS audit_log", nil)
+		require.ErrorIs(t, err, ErrCannotDropTable)
+		require.Len(t, auditLog(t, engine), 7)
+
+		// triggers are dropped along with their table
+		_, _, err = engine.Exec(context.Background(), nil, `
+			DROP TABLE users;
+			CREATE TABLE users (id INTEGER AUTO_INCREMENT, name VARCHAR, balance INTEGER, PRIMARY KEY id);
+			INSERT INTO users (name, balance) VALUES ('erin', 50);
+		`, nil)
+		require.NoError(t, err)
+		require.Len(t, auditLog(t, engine), 7)
+
+		_, _, err = engine.Exec(context.Background(), nil, `
+			CREATE TRIGGER audit_self AFTER INSERT ON audit_log FOR EACH ROW DELETE FROM audit_log WHERE id < 0;
+			DROP TABLE audit_log;
+		`, nil)
+		require.NoError(t, err)
+	})
+}
@@ -19,6 +19,7 @@ package sql
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 	"time"
@@ -182,7 +183,14 @@ func getConverter(src, dst SQLValueType) (converterFunc, error) {
 				if val.RawValue() == nil {
 					return &NullValue{t: IntegerType}, nil
 				}
-				return &Integer{val: int64(val.RawValue().(float64))}, nil
+
+				f := val.RawValue().(float64)
+
+				// float64(math.MaxInt64) is 2^63, which is out of range
+				if math.IsNaN(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+					return nil, fmt.Errorf("%w: %v is out of the range of INTEGER values", ErrInvalidValue, f)
+				}
+				return &Integer{val: int64(f)}, nil
 			}, nil
 		}
 
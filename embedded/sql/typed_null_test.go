@@ -0,0 +1,144 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypedNull(t *testing.T) {
+	engine := setupCommonTest(t)
+
+	_, _, err := engine.Exec(context.Background(), nil, `
+		CREATE TABLE t (id INTEGER AUTO_INCREMENT, a INTEGER, b VARCHAR, PRIMARY KEY id);
+		INSERT INTO t (a, b) VALUES (1, 'x'), (2, 'y');
+	`, nil)
+	require.NoError(t, err)
+
+	query := func(t *testing.T, sql string) (SQLValueType, []TypedValue) {
+		r, err := engine.Query(context.Background(), nil, sql, nil)
+		require.NoError(t, err)
+		defer r.Close()
+
+		cols, err := r.Columns(context.Background())
+		require.NoError(t, err)
+
+		var values []TypedValue
+		for {
+			row, err := r.Read(context.Background())
+			if err == ErrNoMoreRows {
+				break
+			}
+			require.NoError(t, err)
+
+			values = append(values, row.ValuesByPosition[0])
+		}
+		return cols[0].Type, values
+	}
+
+	t.Run("typed NULL literals", func(t *testing.T) {
+		for sql, expectedType := range map[string]SQLValueType{
+			"SELECT CAST(NULL AS INTEGER)": IntegerType,
+			"SELECT NULL::VARCHAR":         VarcharType,
+			"SELECT CAST(NULL AS FLOAT)":   Float64Type,
+			"SELECT NULL":                  AnyType,
+		} {
+			colType, values := query(t, sql)
+			require.Equal(t, expectedType, colType, sql)
+			require.Len(t, values, 1)
+			require.True(t, values[0].IsNull())
+			require.Equal(t, expectedType, values[0].Type(), sql)
+		}
+	})
+
+	t.Run("typed NULL values are inserted", func(t *testing.T) {
+		_, _, err := engine.Exec(context.Background(), nil, "INSERT INTO t (a, b) VALUES (CAST(NULL AS INTEGER), NULL::VARCHAR)", nil)
+		require.NoError(t, err)
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT COUNT(*) FROM t WHERE a IS NULL AND b IS NULL", nil)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), rows[0].ValuesByPosition[0].RawValue())
+
+		_, _, err = engine.Exec(context.Background(), nil, "DELETE FROM t WHERE a IS NULL", nil)
+		require.NoError(t, err)
+	})
+
+	t.Run("NULL in UNION", func(t *testing.T) {
+		colType, values := query(t, "SELECT a FROM t UNION ALL SELECT CAST(NULL AS INTEGER)")
+		require.Equal(t, IntegerType, colType)
+		require.Len(t, values, 3)
+		require.Equal(t, IntegerType, values[2].Type())
+
+		// untyped NULLs take the type of the other subqueries
+		for _, sql := range []string{
+			"SELECT a FROM t UNION ALL SELECT NULL",
+			"SELECT NULL UNION ALL SELECT a FROM t",
+		} {
+			colType, values := query(t, sql)
+			require.Equal(t, IntegerType, colType, sql)
+			require.Len(t, values, 3)
+
+			for _, v := range values {
+				require.Equal(t, IntegerType, v.Type(), sql)
+			}
+		}
+
+		_, err := engine.Query(context.Background(), nil, "SELECT b FROM t UNION SELECT CAST(NULL AS INTEGER)", nil)
+		require.ErrorIs(t, err, ErrColumnMismatchInUnionStmt)
+	})
+
+	t.Run("NULL in CASE", func(t *testing.T) {
+		for sql, expected := range map[string][]interface{}{
+			"SELECT CASE WHEN a > 1 THEN CAST(NULL AS VARCHAR) ELSE b END FROM t": {"x", nil},
+			"SELECT CASE WHEN a > 1 THEN NULL ELSE b END FROM t":                  {"x", nil},
+			"SELECT CASE WHEN a > 1 THEN b ELSE NULL END FROM t":                  {nil, "y"},
+			"SELECT CASE WHEN a > 1 THEN b END FROM t":                            {nil, "y"},
+		} {
+			colType, values := query(t, sql)
+			require.Equal(t, VarcharType, colType, sql)
+			require.Len(t, values, 2)
+			require.Equal(t, expected[0], values[0].RawValue(), sql)
+			require.Equal(t, expected[1], values[1].RawValue(), sql)
+		}
+
+		r, err := engine.Query(context.Background(), nil, "SELECT CASE WHEN a > 1 THEN CAST(NULL AS INTEGER) ELSE b END FROM t", nil)
+		require.NoError(t, err)
+
+		_, err = r.Columns(context.Background())
+		require.ErrorIs(t, err, ErrInferredMultipleTypes)
+		require.NoError(t, r.Close())
+
+		rows, err := engine.queryAll(context.Background(), nil, "SELECT id FROM t WHERE (CASE WHEN a > 1 THEN NULL ELSE b END) = 'x'", nil)
+		require.NoError(t, err)
+		require.Len(t, rows, 1)
+	})
+
+	t.Run("NULL in COALESCE", func(t *testing.T) {
+		_, values := query(t, "SELECT COALESCE(NULL, CAST(NULL AS VARCHAR))")
+		require.True(t, values[0].IsNull())
+		require.Equal(t, VarcharType, values[0].Type())
+
+		_, values = query(t, "SELECT COALESCE(CAST(NULL AS VARCHAR), b) FROM t")
+		require.Equal(t, "x", values[0].RawValue())
+
+		_, err := engine.queryAll(context.Background(), nil, "SELECT COALESCE(CAST(NULL AS BOOLEAN), b) FROM t", nil)
+		require.ErrorIs(t, err, ErrInvalidTypes)
+	})
+}
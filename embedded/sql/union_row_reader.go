@@ -20,6 +20,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"iter"
 
 	"github.com/codenotary/immudb/embedded/multierr"
 	"github.com/codenotary/immudb/embedded/store"
@@ -30,6 +31,8 @@ type unionRowReader struct {
 	currReader int
 
 	cols []ColDescriptor
+
+	lookahead rowLookahead
 }
 
 func newUnionRowReader(ctx context.Context, rowReaders []RowReader) (*unionRowReader, error) {
@@ -53,7 +56,13 @@ func newUnionRowReader(ctx context.Context, rowReaders []RowReader) (*unionRowRe
 		}
 
 		for c := 0; c < len(cols); c++ {
-			if cols[c].Type != cs[c].Type {
+			// columns of untyped NULLs take the type of the columns of the other subqueries
+			if cols[c].Type == AnyType {
+				cols[c].Type = cs[c].Type
+				continue
+			}
+
+			if cs[c].Type != AnyType && cols[c].Type != cs[c].Type {
 				return nil, fmt.Errorf("%w: expecting type '%v' for column '%s'", ErrColumnMismatchInUnionStmt, cols[c].Type, cs[c].Column)
 			}
 		}
@@ -90,11 +99,27 @@ func (ur *unionRowReader) ScanSpecs() *ScanSpecs {
 }
 
 func (ur *unionRowReader) Columns(ctx context.Context) ([]ColDescriptor, error) {
-	return ur.rowReaders[0].Columns(ctx)
+	cols, err := ur.rowReaders[0].Columns(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range cols {
+		cols[i].Type = ur.cols[i].Type
+	}
+	return cols, nil
 }
 
 func (ur *unionRowReader) colsBySelector(ctx context.Context) (map[string]ColDescriptor, error) {
-	return ur.rowReaders[0].colsBySelector(ctx)
+	colsBySel, err := ur.rowReaders[0].colsBySelector(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, col := range ur.cols {
+		colsBySel[col.Selector()] = col
+	}
+	return colsBySel, nil
 }
 
 func (ur *unionRowReader) InferParameters(ctx context.Context, params map[string]SQLValueType) error {
@@ -108,7 +133,19 @@ func (ur *unionRowReader) InferParameters(ctx context.Context, params map[string
 	return nil
 }
 
+func (ur *unionRowReader) All(ctx context.Context) iter.Seq2[*Row, error] {
+	return allRows(ctx, ur)
+}
+
 func (ur *unionRowReader) Read(ctx context.Context) (*Row, error) {
+	return ur.lookahead.read(ctx, ur.readRow)
+}
+
+func (ur *unionRowReader) Peek(ctx context.Context) (*Row, error) {
+	return ur.lookahead.peek(ctx, ur.readRow)
+}
+
+func (ur *unionRowReader) readRow(ctx context.Context) (*Row, error) {
 	for {
 		row, err := ur.rowReaders[ur.currReader].Read(ctx)
 		if errors.Is(err, store.ErrNoMoreEntries) && ur.currReader+1 < len(ur.rowReaders) {
@@ -119,6 +156,18 @@ func (ur *unionRowReader) Read(ctx context.Context) (*Row, error) {
 			return nil, err
 		}
 
+		for i, c := range ur.cols {
+			v := row.ValuesByPosition[i]
+			if v.IsNull() && v.Type() == AnyType && c.Type != AnyType {
+				v = NewNull(c.Type)
+				row.ValuesByPosition[i] = v
+
+				if ur.currReader == 0 {
+					row.ValuesBySelector[c.Selector()] = v
+				}
+			}
+		}
+
 		if ur.currReader > 0 {
 			// overwrite selectors using the ones from the first subquery
 			valuesBySelector := make(map[string]TypedValue, len(ur.cols))
@@ -135,6 +184,8 @@ func (ur *unionRowReader) Read(ctx context.Context) (*Row, error) {
 }
 
 func (ur *unionRowReader) Close() error {
+	ur.lookahead.discard()
+
 	merr := multierr.NewMultiErr()
 
 	// Closing in reverse order to ensure the onClose callback
@@ -19,6 +19,7 @@ package sql
 import (
 	"context"
 	"fmt"
+	"iter"
 )
 
 type valuesRowReader struct {
@@ -35,6 +36,8 @@ type valuesRowReader struct {
 	checkTypes      bool
 	onCloseCallback func()
 	closed          bool
+
+	lookahead rowLookahead
 }
 
 func NewValuesRowReader(tx *SQLTx, params map[string]interface{}, cols []ColDescriptor, checkTypes bool, tableAlias string, values [][]ValueExp) (*valuesRowReader, error) {
@@ -51,9 +54,12 @@ func NewValuesRowReader(tx *SQLTx, params map[string]interface{}, cols []ColDesc
 		}
 
 		col := ColDescriptor{
-			Table:  tableAlias,
-			Column: c.Column,
-			Type:   c.Type,
+			Table:      tableAlias,
+			Column:     c.Column,
+			Type:       c.Type,
+			MaxLen:     c.MaxLen,
+			Nullable:   c.Nullable,
+			PrimaryKey: c.PrimaryKey,
 		}
 
 		colsByPos[i] = col
@@ -121,7 +127,23 @@ func (vr *valuesRowReader) InferParameters(ctx context.Context, params map[strin
 	return nil
 }
 
+func (vr *valuesRowReader) All(ctx context.Context) iter.Seq2[*Row, error] {
+	return allRows(ctx, vr)
+}
+
 func (vr *valuesRowReader) Read(ctx context.Context) (*Row, error) {
+	return vr.lookahead.read(ctx, vr.readRow)
+}
+
+func (vr *valuesRowReader) Peek(ctx context.Context) (*Row, error) {
+	return vr.lookahead.peek(ctx, vr.readRow)
+}
+
+func (vr *valuesRowReader) readRow(ctx context.Context) (*Row, error) {
+	if vr.closed {
+		return nil, ErrReaderClosed
+	}
+
 	if vr.read == len(vr.values) {
 		return nil, ErrNoMoreRows
 	}
@@ -137,7 +159,7 @@ func (vr *valuesRowReader) Read(ctx context.Context) (*Row, error) {
 			return nil, err
 		}
 
-		rv, err := sv.reduce(vr.tx, nil, vr.tableAlias)
+		rv, err := sv.reduceCtx(ctx, vr.tx, nil, vr.tableAlias)
 		if err != nil {
 			return nil, err
 		}
@@ -164,6 +186,8 @@ func (vr *valuesRowReader) Read(ctx context.Context) (*Row, error) {
 }
 
 func (vr *valuesRowReader) Close() error {
+	vr.lookahead.discard()
+
 	if vr.closed {
 		return ErrAlreadyClosed
 	}
@@ -0,0 +1,88 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import "time"
+
+// idle workers of the pool are stopped after workerIdleTimeout without receiving tasks
+const workerIdleTimeout = 10 * time.Second
+
+// workerPool runs the tasks submitted by the queries of an engine, e.g. reading the shards of
+// a scan, on goroutines reused across queries instead of starting goroutines for each of them.
+// Up to size workers are run, tasks submitted while all of them are busy are not run by the pool
+// but left to the submitter, as tasks of the same query may wait on each other, thus tasks never
+// wait for a worker to become available. Workers keep no state between tasks.
+type workerPool struct {
+	tasks       chan func()
+	workers     chan struct{} // holds a token for each running worker
+	idleTimeout time.Duration
+}
+
+func newWorkerPool(size int) *workerPool {
+	return &workerPool{
+		tasks:       make(chan func()),
+		workers:     make(chan struct{}, size),
+		idleTimeout: workerIdleTimeout,
+	}
+}
+
+// spawn runs the task on the worker pool of the engine, if any, or on a new goroutine.
+// It returns false, without running the task, when all the workers of the pool are busy.
+func (e *Engine) spawn(task func()) bool {
+	if e.workers == nil {
+		go task()
+		return true
+	}
+	return e.workers.submit(task)
+}
+
+// submit runs the task on an idle worker, or on a new worker when fewer than size are running.
+// It returns false, without running the task, when all the workers are busy.
+func (p *workerPool) submit(task func()) bool {
+	select {
+	case p.tasks <- task:
+		return true
+	default:
+	}
+
+	select {
+	case p.workers <- struct{}{}:
+		go p.work(task)
+		return true
+	default:
+		return false
+	}
+}
+
+func (p *workerPool) work(task func()) {
+	defer func() { <-p.workers }()
+
+	idle := time.NewTimer(p.idleTimeout)
+	defer idle.Stop()
+
+	for {
+		task()
+
+		idle.Reset(p.idleTimeout)
+
+		select {
+		case task = <-p.tasks:
+		case <-idle.C:
+			return
+		}
+	}
+}
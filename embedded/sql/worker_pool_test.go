@@ -0,0 +1,275 @@
+/*
+Copyright 2025 Codenotary Inc. All rights reserved.
+
+SPDX-License-Identifier: BUSL-1.1
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    https://mariadb.com/bsl11/
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sql
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/codenotary/immudb/embedded/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func goroutineID() string {
+	buf := make([]byte, 64)
+	buf = buf[:runtime.Stack(buf, false)]
+	return string(bytes.Fields(buf)[1])
+}
+
+func TestWorkerPool(t *testing.T) {
+	t.Run("idle workers run the following tasks", func(t *testing.T) {
+		p := newWorkerPool(1)
+
+		ids := make(chan string, 1)
+		task := func() { ids <- goroutineID() }
+
+		require.True(t, p.submit(task))
+		first := <-ids
+
+		for i := 0; i < 100; i++ {
+			// tasks are sent once the worker is waiting for them
+			require.Eventually(t, func() bool {
+				select {
+				case p.tasks <- task:
+					return true
+				default:
+					return false
+				}
+			}, time.Second, time.Millisecond)
+
+			require.Equal(t, first, <-ids)
+		}
+		require.Len(t, p.workers, 1)
+	})
+
+	t.Run("tasks are not run while all the workers are busy", func(t *testing.T) {
+		p := newWorkerPool(2)
+
+		goroutines := runtime.NumGoroutine()
+
+		release := make(chan struct{})
+
+		var wg sync.WaitGroup
+
+		submitted := 0
+
+		for i := 0; i < 100; i++ {
+			wg.Add(1)
+
+			if p.submit(func() { defer wg.Done(); <-release }) {
+				submitted++
+			} else {
+				wg.Done()
+			}
+		}
+
+		require.Equal(t, 2, submitted)
+		require.LessOrEqual(t, runtime.NumGoroutine(), goroutines+2)
+
+		close(release)
+		wg.Wait()
+	})
+
+	t.Run("idle workers are stopped", func(t *testing.T) {
+		p := newWorkerPool(4)
+		p.idleTimeout = 10 * time.Millisecond
+
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+
+			if !p.submit(wg.Done) {
+				wg.Done()
+			}
+		}
+		wg.Wait()
+
+		require.LessOrEqual(t, len(p.workers), 4)
+		require.Eventually(t, func() bool { return len(p.workers) == 0 }, 5*time.Second, time.Millisecond)
+
+		wg.Add(1)
+		require.True(t, p.submit(wg.Done))
+		wg.Wait()
+	})
+}
+
+func TestScansWithWorkerPool(t *testing.T) {
+	st, err := store.Open(t.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(t, err)
+	defer closeStore(t, st)
+
+	engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+	require.NoError(t, err)
+
+	pooledEngine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix).WithScanShards(4).WithWorkerPoolSize(2))
+	require.NoError(t, err)
+	require.NotNil(t, pooledEngine.workers)
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE nums (id INTEGER, v INTEGER, PRIMARY KEY id)", nil)
+	require.NoError(t, err)
+
+	values := make([]string, 1000)
+	for i := range values {
+		values[i] = fmt.Sprintf("(%d, %d)", i, (i*37)%101)
+	}
+
+	_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO nums (id, v) VALUES "+strings.Join(values, ", "), nil)
+	require.NoError(t, err)
+
+	const sql = "SELECT id, v FROM nums WHERE id % @m = @r AND v > @min"
+
+	paramsOf := func(i int) map[string]interface{} {
+		return map[string]interface{}{"m": i%7 + 2, "r": i % 2, "min": i % 50}
+	}
+
+	expected := make([][]*Row, 20)
+	for i := range expected {
+		expected[i], err = engine.queryAll(context.Background(), nil, sql, paramsOf(i))
+		require.NoError(t, err)
+		require.NotEmpty(t, expected[i])
+	}
+
+	t.Run("concurrent queries only read their own rows", func(t *testing.T) {
+		var wg sync.WaitGroup
+
+		errs := make(chan error, 8)
+
+		for g := 0; g < 8; g++ {
+			wg.Add(1)
+
+			go func(g int) {
+				defer wg.Done()
+
+				for i := g; i < g+25; i++ {
+					rows, err := pooledEngine.queryAll(context.Background(), nil, sql, paramsOf(i%len(expected)))
+					if err != nil {
+						errs <- err
+						return
+					}
+
+					if !assert.ObjectsAreEqual(expected[i%len(expected)], rows) {
+						errs <- fmt.Errorf("query %d returned unexpected rows", i)
+						return
+					}
+				}
+			}(g)
+		}
+
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			require.NoError(t, err)
+		}
+	})
+
+	t.Run("queries closed before reading all the rows", func(t *testing.T) {
+		for i := 0; i < 10; i++ {
+			r, err := pooledEngine.Query(context.Background(), nil, "SELECT id FROM nums", nil)
+			require.NoError(t, err)
+
+			row, err := r.Read(context.Background())
+			require.NoError(t, err)
+			require.Equal(t, int64(0), row.ValuesByPosition[0].RawValue())
+
+			require.NoError(t, r.Close())
+		}
+
+		rows, err := pooledEngine.queryAll(context.Background(), nil, sql, paramsOf(0))
+		require.NoError(t, err)
+		require.Equal(t, expected[0], rows)
+	})
+
+	t.Run("shards are read by the query while all the workers are busy", func(t *testing.T) {
+		release := make(chan struct{})
+		defer close(release)
+
+		for pooledEngine.workers.submit(func() { <-release }) {
+		}
+
+		goroutines := runtime.NumGoroutine()
+
+		for i := range expected {
+			rows, err := pooledEngine.queryAll(context.Background(), nil, sql, paramsOf(i))
+			require.NoError(t, err)
+			require.Equal(t, expected[i], rows)
+		}
+
+		require.LessOrEqual(t, runtime.NumGoroutine(), goroutines)
+	})
+
+	t.Run("invalid options", func(t *testing.T) {
+		require.ErrorIs(t, DefaultOptions().WithWorkerPoolSize(-1).Validate(), store.ErrInvalidOptions)
+	})
+}
+
+// BenchmarkWorkerPool runs small filtered queries over sharded scans, whose shards are read
+// either by goroutines started for each query or by the workers of a pool shared by the queries
+func BenchmarkWorkerPool(b *testing.B) {
+	st, err := store.Open(b.TempDir(), store.DefaultOptions().WithMultiIndexing(true))
+	require.NoError(b, err)
+	b.Cleanup(func() { st.Close() })
+
+	engine, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix))
+	require.NoError(b, err)
+
+	_, _, err = engine.Exec(context.Background(), nil, "CREATE TABLE items (id INTEGER, qty INTEGER, PRIMARY KEY id)", nil)
+	require.NoError(b, err)
+
+	values := make([]string, 1000)
+	for i := range values {
+		values[i] = fmt.Sprintf("(%d, %d)", i, i%100)
+	}
+
+	_, _, err = engine.Exec(context.Background(), nil, "INSERT INTO items (id, qty) VALUES "+strings.Join(values, ", "), nil)
+	require.NoError(b, err)
+
+	err = st.WaitForIndexingUpto(context.Background(), st.LastCommittedTxID())
+	require.NoError(b, err)
+
+	for _, poolSize := range []int{0, 16} {
+		e, err := NewEngine(st, DefaultOptions().WithPrefix(sqlPrefix).WithScanShards(4).WithWorkerPoolSize(poolSize))
+		require.NoError(b, err)
+
+		b.Run(fmt.Sprintf("pool=%d", poolSize), func(b *testing.B) {
+			b.ReportAllocs()
+
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					lo := (i * 61) % 900
+
+					rows, err := e.queryAll(context.Background(), nil,
+						"SELECT id FROM items WHERE id >= @lo AND id < @hi AND qty % 2 = 0",
+						map[string]interface{}{"lo": lo, "hi": lo + 20},
+					)
+					require.NoError(b, err)
+					require.Len(b, rows, 10)
+
+					i++
+				}
+			})
+		})
+	}
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/codenotary/immudb/embedded"
 	"github.com/codenotary/immudb/embedded/ahtree"
 	"github.com/codenotary/immudb/embedded/appendable"
+	"github.com/codenotary/immudb/embedded/appendable/memapp"
 	"github.com/codenotary/immudb/embedded/appendable/multiapp"
 	"github.com/codenotary/immudb/embedded/appendable/singleapp"
 	"github.com/codenotary/immudb/embedded/cache"
@@ -242,18 +243,32 @@ func Open(path string, opts *Options) (*ImmuStore, error) {
 		return nil, fmt.Errorf("%w: %v", ErrIllegalArguments, err)
 	}
 
-	finfo, err := os.Stat(path)
-	if err != nil {
-		if !os.IsNotExist(err) {
-			return nil, err
+	if opts.InMemory {
+		if opts.appFactory != nil || opts.appRemove != nil {
+			return nil, fmt.Errorf("%w: InMemory can not be combined with custom app functions", ErrIllegalArguments)
 		}
 
-		err := os.Mkdir(path, opts.FileMode)
+		storage := memapp.NewStorage()
+
+		// options are copied so that the storage is not shared with other stores
+		inMemOpts := *opts
+		inMemOpts.appFactory = storage.Open
+		inMemOpts.appRemove = storage.Remove
+		opts = &inMemOpts
+	} else {
+		finfo, err := os.Stat(path)
 		if err != nil {
-			return nil, err
+			if !os.IsNotExist(err) {
+				return nil, err
+			}
+
+			err := os.Mkdir(path, opts.FileMode)
+			if err != nil {
+				return nil, err
+			}
+		} else if !finfo.IsDir() {
+			return nil, ErrPathIsNotADirectory
 		}
-	} else if !finfo.IsDir() {
-		return nil, ErrPathIsNotADirectory
 	}
 
 	metadata := appendable.NewMetadata(nil)
@@ -596,7 +611,8 @@ func OpenWith(path string, vLogs []appendable.Appendable, txLog, cLog appendable
 		WithRetryableSync(opts.Synced).
 		WithAutoSync(true).
 		WithWriteBufferSize(opts.AHTOpts.WriteBufferSize).
-		WithSyncThld(opts.AHTOpts.SyncThld)
+		WithSyncThld(opts.AHTOpts.SyncThld).
+		WithInMemory(opts.InMemory)
 
 	if opts.appFactory != nil {
 		ahtOpts.WithAppFactory(func(rootPath, subPath string, appOpts *multiapp.Options) (appendable.Appendable, error) {
@@ -672,7 +688,7 @@ func OpenWith(path string, vLogs []appendable.Appendable, txLog, cLog appendable
 
 		opts: opts,
 
-		compactionDisabled: opts.CompactionDisabled,
+		compactionDisabled: opts.CompactionDisabled || opts.InMemory,
 	}
 
 	if store.aht.Size() > precommittedTxID {
@@ -843,6 +859,10 @@ func (s *ImmuStore) InitIndexing(spec *IndexSpec) error {
 	s.indexersMux.Lock()
 	defer s.indexersMux.Unlock()
 
+	return s.initIndexing(spec)
+}
+
+func (s *ImmuStore) initIndexing(spec *IndexSpec) error {
 	indexPrefix := sha256.Sum256(spec.TargetPrefix)
 
 	_, ok := s.indexers[indexPrefix]
@@ -910,6 +930,29 @@ func (s *ImmuStore) DeleteIndex(prefix []byte) error {
 	s.indexersMux.Lock()
 	defer s.indexersMux.Unlock()
 
+	return s.deleteIndex(prefix)
+}
+
+// RebuildIndex deletes the index with the target prefix of the spec and initializes it again,
+// so its entries are rebuilt from the transactions in the log. Readers never observe the index
+// as missing while it's being replaced.
+func (s *ImmuStore) RebuildIndex(spec *IndexSpec) error {
+	if spec == nil {
+		return ErrIllegalArguments
+	}
+
+	s.indexersMux.Lock()
+	defer s.indexersMux.Unlock()
+
+	err := s.deleteIndex(spec.TargetPrefix)
+	if err != nil {
+		return err
+	}
+
+	return s.initIndexing(spec)
+}
+
+func (s *ImmuStore) deleteIndex(prefix []byte) error {
 	indexPrefix := sha256.Sum256(prefix)
 
 	indexer, ok := s.indexers[indexPrefix]
@@ -923,6 +966,10 @@ func (s *ImmuStore) DeleteIndex(prefix []byte) error {
 
 	s.logger.Infof("deleting index path: '%s' ...", indexer.path)
 
+	if s.opts.InMemory {
+		return s.opts.appRemove(indexer.path, "")
+	}
+
 	return os.RemoveAll(indexer.path)
 }
 
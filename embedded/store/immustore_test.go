@@ -1823,6 +1823,62 @@ func TestImmudbStoreCompapactionDisabled(t *testing.T) {
 	require.ErrorIs(t, err, ErrCompactionDisabled)
 }
 
+func TestImmudbStoreInMemory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inmem")
+	opts := DefaultOptions().WithInMemory(true)
+
+	immuStore, err := Open(path, opts)
+	require.NoError(t, err)
+
+	defer immustoreClose(t, immuStore)
+
+	_, err = os.Stat(path)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	for i := 0; i < 10; i++ {
+		tx, err := immuStore.NewWriteOnlyTx(context.Background())
+		require.NoError(t, err)
+
+		err = tx.Set([]byte(fmt.Sprintf("key%d", i)), nil, []byte(fmt.Sprintf("value%d", i)))
+		require.NoError(t, err)
+
+		_, err = tx.Commit(context.Background())
+		require.NoError(t, err)
+	}
+
+	for i := 0; i < 10; i++ {
+		valRef, err := immuStore.Get(context.Background(), []byte(fmt.Sprintf("key%d", i)))
+		require.NoError(t, err)
+
+		val, err := valRef.Resolve()
+		require.NoError(t, err)
+		require.Equal(t, []byte(fmt.Sprintf("value%d", i)), val)
+	}
+
+	err = immuStore.CompactIndexes()
+	require.ErrorIs(t, err, ErrCompactionDisabled)
+
+	t.Run("stores are isolated even when sharing the path", func(t *testing.T) {
+		otherStore, err := Open(path, opts)
+		require.NoError(t, err)
+
+		defer immustoreClose(t, otherStore)
+
+		require.Zero(t, otherStore.LastCommittedTxID())
+
+		_, err = otherStore.Get(context.Background(), []byte("key0"))
+		require.ErrorIs(t, err, ErrKeyNotFound)
+	})
+
+	_, err = os.Stat(path)
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	_, err = Open(path, DefaultOptions().WithInMemory(true).WithAppRemoveFunc(func(rootPath, subPath string) error {
+		return nil
+	}))
+	require.ErrorIs(t, err, ErrIllegalArguments)
+}
+
 func TestImmudbStoreInclusionProof(t *testing.T) {
 	dir := t.TempDir()
 
@@ -5174,3 +5230,54 @@ func TestIndexingChanges(t *testing.T) {
 	require.ErrorIs(t, err, ErrKeyNotFound)
 
 }
+
+func TestRebuildIndex(t *testing.T) {
+	st, err := Open(t.TempDir(), DefaultOptions().WithMultiIndexing(true))
+	require.NoError(t, err)
+
+	defer immustoreClose(t, st)
+
+	spec := &IndexSpec{
+		SourcePrefix: []byte("j"),
+		TargetPrefix: []byte("j"),
+	}
+
+	err = st.RebuildIndex(nil)
+	require.ErrorIs(t, err, ErrIllegalArguments)
+
+	err = st.RebuildIndex(spec)
+	require.ErrorIs(t, err, ErrIndexNotFound)
+
+	err = st.InitIndexing(spec)
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		tx, err := st.NewWriteOnlyTx(context.Background())
+		require.NoError(t, err)
+
+		err = tx.Set([]byte(fmt.Sprintf("j%d", i)), nil, []byte(fmt.Sprintf("val_j%d", i)))
+		require.NoError(t, err)
+
+		_, err = tx.Commit(context.Background())
+		require.NoError(t, err)
+	}
+
+	err = st.RebuildIndex(spec)
+	require.NoError(t, err)
+
+	err = st.InitIndexing(spec)
+	require.ErrorIs(t, err, ErrIndexAlreadyInitialized)
+
+	err = st.WaitForIndexingUpto(context.Background(), st.LastCommittedTxID())
+	require.NoError(t, err)
+
+	for i := 0; i < 10; i++ {
+		valRef, err := st.Get(context.Background(), []byte(fmt.Sprintf("j%d", i)))
+		require.NoError(t, err)
+		require.Equal(t, uint64(i+1), valRef.Tx())
+
+		val, err := valRef.Resolve()
+		require.NoError(t, err)
+		require.Equal(t, []byte(fmt.Sprintf("val_j%d", i)), val)
+	}
+}
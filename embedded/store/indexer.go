@@ -148,6 +148,8 @@ func newIndexer(path string, store *ImmuStore, opts *Options) (*indexer, error)
 		indexOpts.WithAppRemoveFunc(tbtree.AppRemoveFunc(opts.appRemove))
 	}
 
+	indexOpts.WithInMemory(opts.InMemory)
+
 	index, err := tbtree.Open(path, indexOpts)
 	if err != nil {
 		return nil, err
@@ -57,6 +57,10 @@ var (
 	}
 )
 
+// ConflictPredicateFn tells if an entry read within a transaction has to be
+// considered when checking for conflicts at commit time
+type ConflictPredicateFn func(key []byte, valRef ValueRef) (bool, error)
+
 type KeyReader interface {
 	Read(ctx context.Context) (key []byte, val ValueRef, err error)
 	ReadBetween(ctx context.Context, initialTxID uint64, finalTxID uint64) (key []byte, val ValueRef, err error)
@@ -74,6 +78,16 @@ type KeyReaderSpec struct {
 	DescOrder      bool
 	Filters        []FilterFn
 	Offset         uint64
+
+	// ConflictPredicate restricts the entries checked for conflicts, when read
+	// within a transaction, to the ones satisfying it. Entries not satisfying
+	// the predicate may be concurrently updated without causing a conflict.
+	ConflictPredicate ConflictPredicateFn
+
+	// ConflictPrefix, when set, makes read entries not to be tracked
+	// individually within a transaction. Instead, any entry with such prefix
+	// being committed after the transaction snapshot is a conflict.
+	ConflictPrefix []byte
 }
 
 func (s *Snapshot) set(key, value []byte) error {
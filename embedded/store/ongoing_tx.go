@@ -22,6 +22,8 @@ import (
 	"crypto/sha256"
 	"errors"
 	"fmt"
+	"math"
+	"slices"
 	"time"
 )
 
@@ -40,6 +42,8 @@ type OngoingTx struct {
 
 	unsafeMVCC bool
 
+	sortedEntries bool
+
 	requireMVCCOnFollowingTxs bool
 
 	entries          []*EntrySpec
@@ -114,6 +118,7 @@ func newOngoingTx(ctx context.Context, s *ImmuStore, opts *TxOptions) (*OngoingT
 		entriesByKey:     make(map[[sha256.Size]byte]int),
 		ts:               time.Now(),
 		unsafeMVCC:       opts.UnsafeMVCC,
+		sortedEntries:    opts.SortedEntries,
 	}
 
 	tx.mode = opts.Mode
@@ -336,8 +341,9 @@ func (tx *OngoingTx) set(key []byte, md *KVMetadata, value []byte, hashValue [sh
 			if isKeyUpdate {
 				tx.transientEntries[keyRef] = e
 			} else {
-				tx.transientEntries[len(tx.entriesByKey)] = e
-				tx.entriesByKey[kid] = len(tx.entriesByKey)
+				keyRef := tx.nextTransientRef()
+				tx.transientEntries[keyRef] = e
+				tx.entriesByKey[kid] = keyRef
 			}
 		}
 	}
@@ -350,8 +356,9 @@ func (tx *OngoingTx) set(key []byte, md *KVMetadata, value []byte, hashValue [sh
 		}
 	} else {
 		if isTransient {
-			tx.transientEntries[len(tx.entriesByKey)] = e
-			tx.entriesByKey[kid] = len(tx.entriesByKey)
+			keyRef := tx.nextTransientRef()
+			tx.transientEntries[keyRef] = e
+			tx.entriesByKey[kid] = keyRef
 		} else {
 			tx.entries = append(tx.entries, e)
 			tx.entriesByKey[kid] = len(tx.entries) - 1
@@ -362,6 +369,12 @@ func (tx *OngoingTx) set(key []byte, md *KVMetadata, value []byte, hashValue [sh
 	return nil
 }
 
+// nextTransientRef returns the reference of the next transient entry, transient entries are
+// referenced by negative numbers so they are not confused with the positions of the entries
+func (tx *OngoingTx) nextTransientRef() int {
+	return -len(tx.transientEntries) - 1
+}
+
 func mapKey(key []byte, value []byte, mapper EntryMapper) (mappedKey []byte, err error) {
 	if mapper == nil {
 		return key, nil
@@ -608,9 +621,25 @@ func (tx *OngoingTx) commit(ctx context.Context, waitForIndexing bool) (*TxHeade
 		return nil, ctx.Err()
 	}
 
+	if tx.sortedEntries {
+		tx.sortEntries()
+	}
+
 	return tx.st.commit(ctx, tx, nil, false, waitForIndexing)
 }
 
+// sortEntries sorts the entries of the transaction by key, so that the committed
+// transaction does not depend on the order in which entries were set
+func (tx *OngoingTx) sortEntries() {
+	slices.SortFunc(tx.entries, func(e1, e2 *EntrySpec) int {
+		return bytes.Compare(e1.Key, e2.Key)
+	})
+
+	for i, e := range tx.entries {
+		tx.entriesByKey[sha256.Sum256(e.Key)] = i
+	}
+}
+
 func (tx *OngoingTx) Cancel() error {
 	if tx.closed {
 		return ErrAlreadyClosed
@@ -634,6 +663,32 @@ func (tx *OngoingTx) Closed() bool {
 	return tx.closed
 }
 
+// ValidateReads checks the preconditions and the reads of the transaction as done when it's
+// committed, thus returning ErrTxReadConflict if any of the reads would be resolved differently
+// because of a transaction committed after it. Unlike committing, reads are validated even
+// when the transaction has no entries, while the transaction is kept open.
+func (tx *OngoingTx) ValidateReads(ctx context.Context) error {
+	if tx.closed {
+		return ErrAlreadyClosed
+	}
+
+	if !tx.hasPreconditions() {
+		return nil
+	}
+
+	waitForIndexingUpto := tx.st.LastPrecommittedTxID()
+	if tx.unsafeMVCC {
+		waitForIndexingUpto = tx.st.MandatoryMVCCUpToTxID()
+	}
+
+	err := tx.st.WaitForIndexingUpto(ctx, waitForIndexingUpto)
+	if err != nil {
+		return err
+	}
+
+	return tx.checkPreconditions(ctx, tx.st)
+}
+
 func (tx *OngoingTx) hasPreconditions() bool {
 	return len(tx.preconditions) > 0 || (tx.mvccReadSet != nil && !tx.mvccReadSet.isEmpty())
 }
@@ -717,6 +772,22 @@ func (tx *OngoingTx) checkPreconditions(ctx context.Context, st *ImmuStore) erro
 				continue
 			}
 
+			if len(eReader.spec.ConflictPrefix) > 0 {
+				err := checkPrefixUnchangedSince(ctx, snap, eReader.spec.ConflictPrefix, eReader.snapTs)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			if eReader.spec.ConflictPredicate != nil {
+				err := tx.checkPredicateReads(ctx, snap, eReader)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
 			rspec := KeyReaderSpec{
 				SeekKey:       eReader.spec.SeekKey,
 				EndKey:        eReader.spec.EndKey,
@@ -790,6 +861,139 @@ func (tx *OngoingTx) checkPreconditions(ctx context.Context, st *ImmuStore) erro
 	return nil
 }
 
+// checkPrefixUnchangedSince returns a conflict if any entry with the given prefix
+// was committed after txID
+func checkPrefixUnchangedSince(ctx context.Context, snap *Snapshot, prefix []byte, txID uint64) error {
+	reader, err := snap.NewKeyReader(KeyReaderSpec{Prefix: prefix})
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	_, _, err = reader.ReadBetween(ctx, txID+1, math.MaxUint64)
+	if errors.Is(err, ErrNoMoreEntries) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	return fmt.Errorf("%w: entries were updated by a concurrent transaction", ErrTxReadConflict)
+}
+
+// checkPredicateReads validates the entries satisfying the conflict predicate of the reader,
+// within the portion of the key range that was actually read, are the same ones returned
+// by the current state of the database
+func (tx *OngoingTx) checkPredicateReads(ctx context.Context, snap *Snapshot, eReader *expectedReader) error {
+	for i, eReads := range eReader.expectedReads {
+		err := tx.checkPredicateReadPass(ctx, snap, eReader.spec, eReads, eReader.lastKeys[i])
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (tx *OngoingTx) checkPredicateReadPass(ctx context.Context, snap *Snapshot, spec KeyReaderSpec, eReads []expectedRead, lastKey []byte) error {
+	var initialTxID, finalTxID uint64
+	var reachedEnd bool
+
+	expectedTxs := make(map[string]uint64, len(eReads))
+	updatedByTx := make(map[string]struct{})
+
+	for _, eRead := range eReads {
+		initialTxID, finalTxID = eRead.initialTxID, eRead.finalTxID
+
+		if eRead.expectedNoMoreEntries {
+			reachedEnd = true
+		} else if eRead.expectedTx == 0 {
+			updatedByTx[string(eRead.expectedKey)] = struct{}{}
+		} else {
+			expectedTxs[string(eRead.expectedKey)] = eRead.expectedTx
+		}
+	}
+
+	if !reachedEnd && lastKey == nil {
+		// nothing was read
+		return nil
+	}
+
+	reader, err := snap.NewKeyReader(KeyReaderSpec{
+		SeekKey:       spec.SeekKey,
+		EndKey:        spec.EndKey,
+		Prefix:        spec.Prefix,
+		InclusiveSeek: spec.InclusiveSeek,
+		InclusiveEnd:  spec.InclusiveEnd,
+		DescOrder:     spec.DescOrder,
+	})
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	for {
+		var key []byte
+		var valRef ValueRef
+
+		if initialTxID == 0 && finalTxID == 0 {
+			key, valRef, err = reader.Read(ctx)
+		} else {
+			key, valRef, err = reader.ReadBetween(ctx, initialTxID, finalTxID)
+		}
+		if errors.Is(err, ErrNoMoreEntries) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if !reachedEnd {
+			cmp := bytes.Compare(key, lastKey)
+
+			if (!spec.DescOrder && cmp > 0) || (spec.DescOrder && cmp < 0) {
+				// entries beyond this point were not read
+				break
+			}
+		}
+
+		if _, updated := updatedByTx[string(key)]; updated {
+			continue
+		}
+
+		satisfied := true
+
+		for _, filter := range spec.Filters {
+			if filter(valRef, tx.Timestamp()) != nil {
+				satisfied = false
+				break
+			}
+		}
+
+		if satisfied {
+			satisfied, err = spec.ConflictPredicate(key, valRef)
+			if err != nil {
+				return err
+			}
+		}
+
+		expectedTx, wasRead := expectedTxs[string(key)]
+		if wasRead {
+			delete(expectedTxs, string(key))
+
+			if !satisfied || expectedTx != valRef.Tx() {
+				return fmt.Errorf("%w: fetching a different key or an updated one", ErrTxReadConflict)
+			}
+		} else if satisfied {
+			return fmt.Errorf("%w: fetching more entries than expected", ErrTxReadConflict)
+		}
+	}
+
+	if len(expectedTxs) > 0 {
+		return fmt.Errorf("%w: fetching less entries than expected", ErrTxReadConflict)
+	}
+	return nil
+}
+
 func (tx *OngoingTx) validateAgainst(hdr *TxHeader) error {
 	if hdr == nil {
 		return nil
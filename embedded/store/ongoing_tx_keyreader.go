@@ -24,6 +24,8 @@ import (
 type expectedReader struct {
 	spec          KeyReaderSpec
 	expectedReads [][]expectedRead // multiple []expectedRead may be generated if the reader is reset
+	lastKeys      [][]byte         // last key read on each []expectedRead, only tracked when using a conflict predicate
+	snapTs        uint64           // ts of the snapshot when the reader was created, only used with a conflict prefix
 	i             int              // it matches with reset count, used to point to the latest []expectedRead
 }
 
@@ -49,10 +51,12 @@ type ongoingTxKeyReader struct {
 	expectedReader *expectedReader
 }
 
-func newExpectedReader(spec KeyReaderSpec) *expectedReader {
+func newExpectedReader(spec KeyReaderSpec, snapTs uint64) *expectedReader {
 	return &expectedReader{
 		spec:          spec,
+		snapTs:        snapTs,
 		expectedReads: make([][]expectedRead, 1),
+		lastKeys:      make([][]byte, 1),
 	}
 }
 
@@ -80,7 +84,7 @@ func newOngoingTxKeyReader(tx *OngoingTx, spec KeyReaderSpec) (*ongoingTxKeyRead
 		return nil, err
 	}
 
-	expectedReader := newExpectedReader(spec)
+	expectedReader := newExpectedReader(spec, snap.Ts())
 
 	tx.mvccReadSet.expectedReaders = append(tx.mvccReadSet.expectedReaders, expectedReader)
 	tx.mvccReadSet.readsetSize++
@@ -112,40 +116,19 @@ func (r *ongoingTxKeyReader) ReadBetween(ctx context.Context, initialTxID, final
 				expectedNoMoreEntries: true,
 			}
 
-			if r.tx.mvccReadSet.readsetSize == r.tx.st.mvccReadSetLimit {
-				return nil, nil, ErrMVCCReadSetLimitExceeded
+			if terr := r.track(expectedRead); terr != nil {
+				return nil, nil, terr
 			}
-
-			r.expectedReader.expectedReads[r.expectedReader.i] = append(r.expectedReader.expectedReads[r.expectedReader.i], expectedRead)
-			r.tx.mvccReadSet.readsetSize++
 		}
 
 		if err != nil {
 			return nil, nil, err
 		}
 
-		expectedRead := expectedRead{
-			initialTxID: initialTxID,
-			finalTxID:   finalTxID,
-			expectedKey: cp(key),
-			expectedTx:  valRef.Tx(),
-		}
-
-		if r.tx.mvccReadSet.readsetSize == r.tx.st.mvccReadSetLimit {
-			return nil, nil, ErrMVCCReadSetLimitExceeded
-		}
-
-		r.expectedReader.expectedReads[r.expectedReader.i] = append(r.expectedReader.expectedReads[r.expectedReader.i], expectedRead)
-		r.tx.mvccReadSet.readsetSize++
+		filterEntry := r.filtered(valRef)
 
-		filterEntry := false
-
-		for _, filter := range r.expectedReader.spec.Filters {
-			err = filter(valRef, r.tx.Timestamp())
-			if err != nil {
-				filterEntry = true
-				break
-			}
+		if err := r.trackRead(initialTxID, finalTxID, key, valRef, filterEntry); err != nil {
+			return nil, nil, err
 		}
 
 		if filterEntry {
@@ -161,6 +144,63 @@ func (r *ongoingTxKeyReader) ReadBetween(ctx context.Context, initialTxID, final
 	}
 }
 
+func (r *ongoingTxKeyReader) filtered(valRef ValueRef) bool {
+	for _, filter := range r.expectedReader.spec.Filters {
+		if err := filter(valRef, r.tx.Timestamp()); err != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *ongoingTxKeyReader) trackRead(initialTxID, finalTxID uint64, key []byte, valRef ValueRef, filtered bool) error {
+	spec := r.expectedReader.spec
+
+	if spec.ConflictPredicate != nil {
+		r.expectedReader.lastKeys[r.expectedReader.i] = cp(key)
+
+		// entries written by the ongoing transaction are always tracked,
+		// so they can be told apart when validating the read-set
+		if valRef.Tx() > 0 {
+			if filtered {
+				return nil
+			}
+
+			satisfied, err := spec.ConflictPredicate(key, valRef)
+			if err != nil {
+				return err
+			}
+
+			if !satisfied {
+				return nil
+			}
+		}
+	}
+
+	return r.track(expectedRead{
+		initialTxID: initialTxID,
+		finalTxID:   finalTxID,
+		expectedKey: cp(key),
+		expectedTx:  valRef.Tx(),
+	})
+}
+
+func (r *ongoingTxKeyReader) track(expectedRead expectedRead) error {
+	if len(r.expectedReader.spec.ConflictPrefix) > 0 {
+		// conflicts are detected by prefix
+		return nil
+	}
+
+	if r.tx.mvccReadSet.readsetSize == r.tx.st.mvccReadSetLimit {
+		return ErrMVCCReadSetLimitExceeded
+	}
+
+	r.expectedReader.expectedReads[r.expectedReader.i] = append(r.expectedReader.expectedReads[r.expectedReader.i], expectedRead)
+	r.tx.mvccReadSet.readsetSize++
+
+	return nil
+}
+
 func (r *ongoingTxKeyReader) Reset() error {
 	err := r.keyReader.Reset()
 	if err != nil {
@@ -172,6 +212,7 @@ func (r *ongoingTxKeyReader) Reset() error {
 	}
 
 	r.expectedReader.expectedReads = append(r.expectedReader.expectedReads, nil)
+	r.expectedReader.lastKeys = append(r.expectedReader.lastKeys, nil)
 	r.expectedReader.i++
 
 	r.tx.mvccReadSet.readsetSize++
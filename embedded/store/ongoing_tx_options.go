@@ -41,6 +41,10 @@ type TxOptions struct {
 
 	// MVCC does not wait for indexing to be up to date
 	UnsafeMVCC bool
+
+	// SortedEntries makes the entries of the transaction to be committed in key order,
+	// regardless of the order in which they were set
+	SortedEntries bool
 }
 
 func DefaultTxOptions() *TxOptions {
@@ -84,3 +88,8 @@ func (opts *TxOptions) WithUnsafeMVCC(unsafeMVCC bool) *TxOptions {
 	opts.UnsafeMVCC = unsafeMVCC
 	return opts
 }
+
+func (opts *TxOptions) WithSortedEntries(sortedEntries bool) *TxOptions {
+	opts.SortedEntries = sortedEntries
+	return opts
+}
@@ -93,4 +93,260 @@ func TestOngoingTxOptions(t *testing.T) {
 	require.Equal(t, 1*time.Hour, opts.WithSnapshotRenewalPeriod(1*time.Hour).SnapshotRenewalPeriod)
 	require.EqualValues(t, 1, opts.WithSnapshotMustIncludeTxID(func(lastPrecommittedTxID uint64) uint64 { return 1 }).SnapshotMustIncludeTxID(100))
 	require.True(t, opts.WithUnsafeMVCC(true).UnsafeMVCC)
+	require.True(t, opts.WithSortedEntries(true).SortedEntries)
+}
+
+func TestOngoingTxSortedEntries(t *testing.T) {
+	immuStore, err := Open(t.TempDir(), DefaultOptions())
+	require.NoError(t, err)
+
+	defer immustoreClose(t, immuStore)
+
+	committedKeys := func(t *testing.T, sorted bool) []string {
+		tx, err := immuStore.NewTx(context.Background(), DefaultTxOptions().WithSortedEntries(sorted))
+		require.NoError(t, err)
+
+		for _, k := range []string{"c", "a", "d", "b", "a"} {
+			err = tx.Set([]byte(k), nil, []byte("value-"+k))
+			require.NoError(t, err)
+		}
+
+		valRef, err := tx.Get(context.Background(), []byte("d"))
+		require.NoError(t, err)
+
+		val, err := valRef.Resolve()
+		require.NoError(t, err)
+		require.Equal(t, []byte("value-d"), val)
+
+		hdr, err := tx.Commit(context.Background())
+		require.NoError(t, err)
+
+		txHolder := tempTxHolder(t, immuStore)
+
+		err = immuStore.ReadTx(hdr.ID, false, txHolder)
+		require.NoError(t, err)
+
+		var keys []string
+		for _, e := range txHolder.Entries() {
+			keys = append(keys, string(e.Key()))
+
+			val, err := immuStore.ReadValue(e)
+			require.NoError(t, err)
+			require.Equal(t, "value-"+string(e.Key()), string(val))
+		}
+		return keys
+	}
+
+	require.Equal(t, []string{"c", "a", "d", "b"}, committedKeys(t, false))
+	require.Equal(t, []string{"a", "b", "c", "d"}, committedKeys(t, true))
+}
+
+func TestOngoingTxTransientEntries(t *testing.T) {
+	immuStore, err := Open(t.TempDir(), DefaultOptions().WithMultiIndexing(true))
+	require.NoError(t, err)
+
+	defer immustoreClose(t, immuStore)
+
+	for _, prefix := range []string{"k", "t"} {
+		err = immuStore.InitIndexing(&IndexSpec{
+			SourcePrefix: []byte(prefix),
+			TargetPrefix: []byte(prefix),
+		})
+		require.NoError(t, err)
+	}
+
+	tx, err := immuStore.NewTx(context.Background(), DefaultTxOptions())
+	require.NoError(t, err)
+
+	requireValue := func(t *testing.T, key, value string) {
+		valRef, err := tx.Get(context.Background(), []byte(key))
+		require.NoError(t, err)
+
+		val, err := valRef.Resolve()
+		require.NoError(t, err)
+		require.Equal(t, value, string(val))
+	}
+
+	err = tx.SetTransient([]byte("t1"), nil, []byte("val_t1"))
+	require.NoError(t, err)
+
+	err = tx.Set([]byte("k1"), nil, []byte("val_k1"))
+	require.NoError(t, err)
+
+	// transient entries are not confused with the ones set afterwards
+	requireValue(t, "t1", "val_t1")
+	requireValue(t, "k1", "val_k1")
+
+	err = tx.Set([]byte("k1"), nil, []byte("val_k1_updated"))
+	require.NoError(t, err)
+
+	err = tx.SetTransient([]byte("t1"), nil, []byte("val_t1_updated"))
+	require.NoError(t, err)
+
+	err = tx.SetTransient([]byte("k1"), nil, []byte("val_k1_transient"))
+	require.ErrorIs(t, err, ErrCannotUpdateKeyTransiency)
+
+	requireValue(t, "t1", "val_t1_updated")
+	requireValue(t, "k1", "val_k1_updated")
+
+	_, err = tx.Commit(context.Background())
+	require.NoError(t, err)
+
+	valRef, err := immuStore.Get(context.Background(), []byte("k1"))
+	require.NoError(t, err)
+
+	val, err := valRef.Resolve()
+	require.NoError(t, err)
+	require.Equal(t, []byte("val_k1_updated"), val)
+}
+
+func TestOngoingTxConflictPredicate(t *testing.T) {
+	immuStore, err := Open(t.TempDir(), DefaultOptions())
+	require.NoError(t, err)
+
+	defer immustoreClose(t, immuStore)
+
+	setKeys := func(kvs ...string) {
+		tx, err := immuStore.NewWriteOnlyTx(context.Background())
+		require.NoError(t, err)
+
+		for i := 0; i < len(kvs); i += 2 {
+			err = tx.Set([]byte(kvs[i]), nil, []byte(kvs[i+1]))
+			require.NoError(t, err)
+		}
+
+		_, err = tx.Commit(context.Background())
+		require.NoError(t, err)
+	}
+
+	setKeys("p1", "match", "p2", "other", "q1", "other")
+
+	readPrefix := func(spec KeyReaderSpec) *OngoingTx {
+		tx, err := immuStore.NewTx(context.Background(), DefaultTxOptions())
+		require.NoError(t, err)
+
+		reader, err := tx.NewKeyReader(spec)
+		require.NoError(t, err)
+
+		for {
+			_, _, err := reader.Read(context.Background())
+			if err != nil {
+				require.ErrorIs(t, err, ErrNoMoreEntries)
+				break
+			}
+		}
+
+		require.NoError(t, reader.Close())
+
+		err = tx.Set([]byte("r"), nil, []byte("value"))
+		require.NoError(t, err)
+
+		return tx
+	}
+
+	matches := func(key []byte, valRef ValueRef) (bool, error) {
+		val, err := valRef.Resolve()
+		if err != nil {
+			return false, err
+		}
+		return string(val) == "match", nil
+	}
+
+	t.Run("a non-matching update should not conflict", func(t *testing.T) {
+		tx := readPrefix(KeyReaderSpec{Prefix: []byte("p"), ConflictPredicate: matches})
+
+		setKeys("p2", "still-other")
+
+		_, err := tx.Commit(context.Background())
+		require.NoError(t, err)
+	})
+
+	t.Run("a new matching entry should conflict", func(t *testing.T) {
+		tx := readPrefix(KeyReaderSpec{Prefix: []byte("p"), ConflictPredicate: matches})
+
+		setKeys("p3", "match")
+
+		_, err := tx.Commit(context.Background())
+		require.ErrorIs(t, err, ErrTxReadConflict)
+	})
+
+	t.Run("any update under the conflict prefix should conflict", func(t *testing.T) {
+		tx := readPrefix(KeyReaderSpec{Prefix: []byte("p1"), ConflictPrefix: []byte("p")})
+
+		setKeys("p2", "other")
+
+		_, err := tx.Commit(context.Background())
+		require.ErrorIs(t, err, ErrTxReadConflict)
+	})
+
+	t.Run("updates out of the conflict prefix should not conflict", func(t *testing.T) {
+		tx := readPrefix(KeyReaderSpec{Prefix: []byte("p1"), ConflictPrefix: []byte("p")})
+
+		setKeys("q1", "changed")
+
+		_, err := tx.Commit(context.Background())
+		require.NoError(t, err)
+	})
+}
+
+func TestOngoingTxValidateReads(t *testing.T) {
+	immuStore, err := Open(t.TempDir(), DefaultOptions())
+	require.NoError(t, err)
+
+	defer immustoreClose(t, immuStore)
+
+	setKey := func(key, value string) {
+		tx, err := immuStore.NewWriteOnlyTx(context.Background())
+		require.NoError(t, err)
+
+		err = tx.Set([]byte(key), nil, []byte(value))
+		require.NoError(t, err)
+
+		_, err = tx.Commit(context.Background())
+		require.NoError(t, err)
+	}
+
+	setKey("k1", "v1")
+	setKey("k2", "v1")
+
+	readKey := func(key string) *OngoingTx {
+		tx, err := immuStore.NewTx(context.Background(), DefaultTxOptions())
+		require.NoError(t, err)
+
+		_, err = tx.Get(context.Background(), []byte(key))
+		require.NoError(t, err)
+
+		return tx
+	}
+
+	t.Run("reads not updated by other transactions are valid", func(t *testing.T) {
+		tx := readKey("k1")
+		defer tx.Cancel()
+
+		setKey("k2", "v2")
+
+		require.NoError(t, tx.ValidateReads(context.Background()))
+	})
+
+	t.Run("reads updated by other transactions conflict even without entries", func(t *testing.T) {
+		tx := readKey("k1")
+
+		setKey("k1", "v2")
+
+		require.ErrorIs(t, tx.ValidateReads(context.Background()), ErrTxReadConflict)
+		require.False(t, tx.Closed())
+
+		_, err := tx.Commit(context.Background())
+		require.ErrorIs(t, err, ErrNoEntriesProvided)
+	})
+
+	t.Run("transactions without reads are valid", func(t *testing.T) {
+		tx, err := immuStore.NewTx(context.Background(), DefaultTxOptions())
+		require.NoError(t, err)
+
+		require.NoError(t, tx.ValidateReads(context.Background()))
+
+		require.NoError(t, tx.Cancel())
+		require.ErrorIs(t, tx.ValidateReads(context.Background()), ErrAlreadyClosed)
+	})
 }
@@ -80,6 +80,9 @@ type FlushFunc func() error
 type Options struct {
 	ReadOnly bool
 
+	// Hold all the data in memory, nothing is written to the filesystem
+	InMemory bool
+
 	// Fsync during commit process
 	Synced bool
 
@@ -370,6 +373,9 @@ func (opts *Options) Validate() error {
 	if opts.logger == nil {
 		return fmt.Errorf("%w: invalid log", ErrInvalidOptions)
 	}
+	if opts.InMemory && opts.ReadOnly {
+		return fmt.Errorf("%w: InMemory can not be combined with ReadOnly", ErrInvalidOptions)
+	}
 
 	err := opts.IndexOpts.Validate()
 	if err != nil {
@@ -460,6 +466,15 @@ func (opts *Options) WithReadOnly(readOnly bool) *Options {
 	return opts
 }
 
+// WithInMemory makes the store hold all its data in memory instead of writing it into
+// the filesystem. Each store opened with this option gets its own storage which is
+// released upon closing, thus it's meant to be used when durability is not required
+// e.g. in tests. Index compaction is not supported by in-memory stores.
+func (opts *Options) WithInMemory(inMemory bool) *Options {
+	opts.InMemory = inMemory
+	return opts
+}
+
 func (opts *Options) WithSynced(synced bool) *Options {
 	opts.Synced = synced
 	return opts
@@ -55,6 +55,7 @@ func TestInvalidOptions(t *testing.T) {
 		{"MaxValueLen", DefaultOptions().WithMaxValueLen(0)},
 		{"FileSize", DefaultOptions().WithFileSize(0)},
 		{"FileSize-max", DefaultOptions().WithFileSize(MaxFileSize)},
+		{"InMemory-ReadOnly", DefaultOptions().WithInMemory(true).WithReadOnly(true)},
 	} {
 		t.Run(d.n, func(t *testing.T) {
 			require.ErrorIs(t, d.opts.Validate(), ErrInvalidOptions)
@@ -76,6 +76,7 @@ type Options struct {
 	cacheSize           int
 	cache               *cache.Cache
 	readOnly            bool
+	inMemory            bool
 	fileMode            os.FileMode
 
 	nodesLogMaxOpenedFiles   int
@@ -198,6 +199,10 @@ func (opts *Options) Validate() error {
 		return fmt.Errorf("%w: invalid Logger", ErrInvalidOptions)
 	}
 
+	if opts.inMemory && opts.appFactory == nil {
+		return fmt.Errorf("%w: an AppFactory is required when InMemory is set", ErrInvalidOptions)
+	}
+
 	return nil
 }
 
@@ -211,6 +216,14 @@ func (opts *Options) WithAppFactory(appFactory AppFactoryFunc) *Options {
 	return opts
 }
 
+// WithInMemory makes the tree not to access the filesystem by itself i.e. no folder nor
+// timestamp file is created. All the data is held in the appendables provided by the
+// AppFactory, thus snapshots are not recovered and compaction is not supported.
+func (opts *Options) WithInMemory(inMemory bool) *Options {
+	opts.inMemory = inMemory
+	return opts
+}
+
 func (opts *Options) WithAppRemoveFunc(AppRemove AppRemoveFunc) *Options {
 	opts.appRemove = AppRemove
 	return opts
@@ -61,6 +61,7 @@ var (
 	ErrTargetPathAlreadyExists       = errors.New("tbtree: target folder already exists")
 	ErrNoMoreEntries                 = fmt.Errorf("tbtree: %w", embedded.ErrNoMoreEntries)
 	ErrReadersNotClosed              = errors.New("tbtree: readers not closed")
+	ErrCompactionUnsupported         = errors.New("tbtree: compaction is not supported by in-memory trees")
 )
 
 const Version = 3
@@ -194,6 +195,7 @@ type TBtree struct {
 	maxActiveSnapshots         int
 	renewSnapRootAfter         time.Duration
 	readOnly                   bool
+	inMemory                   bool
 	cacheSize                  int
 	fileSize                   int
 	fileMode                   os.FileMode
@@ -304,17 +306,19 @@ func Open(path string, opts *Options) (*TBtree, error) {
 		return nil, err
 	}
 
-	finfo, err := os.Stat(path)
-	if err != nil {
-		if !os.IsNotExist(err) {
-			return nil, err
-		}
-		err = os.Mkdir(path, opts.fileMode)
+	if !opts.inMemory {
+		finfo, err := os.Stat(path)
 		if err != nil {
-			return nil, err
+			if !os.IsNotExist(err) {
+				return nil, err
+			}
+			err = os.Mkdir(path, opts.fileMode)
+			if err != nil {
+				return nil, err
+			}
+		} else if !finfo.IsDir() {
+			return nil, ErrorPathIsNotADirectory
 		}
-	} else if !finfo.IsDir() {
-		return nil, ErrorPathIsNotADirectory
 	}
 
 	metadata := appendable.NewMetadata(nil)
@@ -354,10 +358,14 @@ func Open(path string, opts *Options) (*TBtree, error) {
 		return nil, err
 	}
 
+	var snapIDs []uint64
+
 	// If compaction was not fully completed, a valid or partially written full snapshot may be there
-	snapIDs, err := recoverFullSnapshots(path, commitFolderPrefix, opts.logger)
-	if err != nil {
-		return nil, err
+	if !opts.inMemory {
+		snapIDs, err = recoverFullSnapshots(path, commitFolderPrefix, opts.logger)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	// Try snapshots from newest to older
@@ -601,6 +609,7 @@ func OpenWith(path, tsFile string, nLog, hLog, cLog appendable.Appendable, opts
 		historyLogMaxOpenedFiles: opts.historyLogMaxOpenedFiles,
 		commitLogMaxOpenedFiles:  opts.commitLogMaxOpenedFiles,
 		readOnly:                 opts.readOnly,
+		inMemory:                 opts.inMemory,
 		appFactory:               opts.appFactory,
 		appRemove:                opts.appRemove,
 		snapshots:                make(map[uint64]*Snapshot),
@@ -1302,6 +1311,10 @@ func (t *TBtree) flushTree(cleanupPercentageHint float32, forceSync bool, forceC
 }
 
 func (t *TBtree) readTsFile() uint64 {
+	if t.inMemory {
+		return 0
+	}
+
 	path := filepath.Join(t.path, t.tsFile)
 
 	bs, err := os.ReadFile(path)
@@ -1322,6 +1335,9 @@ func (t *TBtree) readTsFile() uint64 {
 // but haven't resulted in new key insertions that trigger a tree flush, leading to
 // inefficient recovery and prolonged downtime.
 func (t *TBtree) writeTsFile() error {
+	if t.inMemory {
+		return nil
+	}
 	return writeTsFile(t.path, t.tsFile, t.root.ts())
 }
 
@@ -1434,6 +1450,10 @@ func (t *TBtree) Compact() (uint64, error) {
 		return 0, ErrAlreadyClosed
 	}
 
+	if t.inMemory {
+		return 0, ErrCompactionUnsupported
+	}
+
 	if t.compacting {
 		return 0, ErrCompactAlreadyInProgress
 	}
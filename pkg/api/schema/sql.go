@@ -17,6 +17,7 @@ limitations under the License.
 package schema
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/codenotary/immudb/embedded/sql"
@@ -122,6 +123,10 @@ func AsSQLValue(v interface{}) (*SQLValue, error) {
 }
 
 func TypedValueToRowValue(tv sql.TypedValue) *SQLValue {
+	if tv.IsNull() {
+		return &SQLValue{Value: &SQLValue_Null{}}
+	}
+
 	switch tv.Type() {
 	case sql.IntegerType:
 		{
@@ -152,8 +157,122 @@ func TypedValueToRowValue(tv sql.TypedValue) *SQLValue {
 		{
 			return &SQLValue{Value: &SQLValue_F{F: tv.RawValue().(float64)}}
 		}
-	case sql.JSONType:
+	case sql.JSONType, sql.PointType:
 		return &SQLValue{Value: &SQLValue_S{S: tv.String()}}
 	}
 	return nil
 }
+
+// RowValueToTypedValue converts a value received as part of a query result into a typed value
+// of the type of its column. Values of types which are transmitted as strings, such as UUID or
+// JSON, are parsed back.
+func RowValueToTypedValue(v *SQLValue, t sql.SQLValueType) (sql.TypedValue, error) {
+	if v == nil {
+		return nil, fmt.Errorf("%w: missing value", sql.ErrInvalidValue)
+	}
+
+	switch rv := v.Value.(type) {
+	case *SQLValue_Null:
+		return sql.NewNull(t), nil
+	case *SQLValue_N:
+		if t == sql.IntegerType || t == sql.AnyType {
+			return sql.NewInteger(rv.N), nil
+		}
+	case *SQLValue_S:
+		switch t {
+		case sql.VarcharType, sql.AnyType:
+			return sql.NewVarchar(rv.S), nil
+		case sql.UUIDType:
+			u, err := uuid.Parse(rv.S)
+			if err != nil {
+				return nil, fmt.Errorf("%w: %v", sql.ErrInvalidValue, err)
+			}
+			return sql.NewUUID(u), nil
+		case sql.JSONType:
+			return sql.NewJsonFromString(rv.S)
+		case sql.PointType:
+			return sql.ParsePoint(rv.S)
+		}
+	case *SQLValue_B:
+		if t == sql.BooleanType || t == sql.AnyType {
+			return sql.NewBool(rv.B), nil
+		}
+	case *SQLValue_Bs:
+		if t == sql.BLOBType || t == sql.AnyType {
+			return sql.NewBlob(rv.Bs), nil
+		}
+	case *SQLValue_Ts:
+		if t == sql.TimestampType || t == sql.AnyType {
+			return sql.NewTimestamp(sql.TimeFromInt64(rv.Ts)), nil
+		}
+	case *SQLValue_F:
+		if t == sql.Float64Type || t == sql.AnyType {
+			return sql.NewFloat64(rv.F), nil
+		}
+	default:
+		// e.g. a value of a type introduced by a newer version
+		return nil, fmt.Errorf("%w: unsupported value", sql.ErrInvalidValue)
+	}
+	return nil, fmt.Errorf("%w: value can not be interpreted as type %s", sql.ErrInvalidTypes, t)
+}
+
+func ColDescriptorsToProto(descriptors []sql.ColDescriptor) []*Column {
+	cols := make([]*Column, len(descriptors))
+	for i, des := range descriptors {
+		cols[i] = &Column{Name: des.Selector(), Type: des.Type}
+	}
+	return cols
+}
+
+func ColDescriptorsFromProto(cols []*Column) ([]sql.ColDescriptor, error) {
+	descriptors := make([]sql.ColDescriptor, len(cols))
+	for i, col := range cols {
+		aggFn, table, colName, err := sql.DecodeSelector(col.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		descriptors[i] = sql.ColDescriptor{
+			AggFn:  aggFn,
+			Table:  table,
+			Column: colName,
+			Type:   col.Type,
+		}
+	}
+	return descriptors, nil
+}
+
+func RowToProto(descriptors []sql.ColDescriptor, row *sql.Row) *Row {
+	r := &Row{
+		Columns: make([]string, len(descriptors)),
+		Values:  make([]*SQLValue, len(descriptors)),
+	}
+
+	for i := range descriptors {
+		r.Columns[i] = descriptors[i].Selector()
+		r.Values[i] = TypedValueToRowValue(row.ValuesByPosition[i])
+	}
+	return r
+}
+
+func RowFromProto(descriptors []sql.ColDescriptor, r *Row) (*sql.Row, error) {
+	if len(r.Values) != len(descriptors) {
+		return nil, fmt.Errorf("%w: expected %d values but %d were received", sql.ErrInvalidNumberOfValues, len(descriptors), len(r.Values))
+	}
+
+	row := &sql.Row{
+		ValuesByPosition: make([]sql.TypedValue, len(descriptors)),
+		ValuesBySelector: make(map[string]sql.TypedValue, len(descriptors)),
+	}
+
+	for i, des := range descriptors {
+		v, err := RowValueToTypedValue(r.Values[i], des.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%w (%s)", err, des.Selector())
+		}
+
+		row.ValuesByPosition[i] = v
+		row.ValuesBySelector[des.Selector()] = v
+	}
+	return row, nil
+}
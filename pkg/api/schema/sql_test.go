@@ -22,7 +22,10 @@ import (
 	"time"
 
 	"github.com/codenotary/immudb/embedded/sql"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
 )
 
 func TestEncodeParams(t *testing.T) {
@@ -114,3 +117,165 @@ func TestAsSQLValue(t *testing.T) {
 		})
 	}
 }
+
+func TestTypedValueProtoRoundTrip(t *testing.T) {
+	jsonVal, err := sql.NewJsonFromString(`{"name": "immudb", "tags": [1, 2]}`)
+	require.NoError(t, err)
+
+	pointVal, err := sql.NewPoint(41.9028, -12.4964)
+	require.NoError(t, err)
+
+	for _, d := range []struct {
+		n     string
+		t     sql.SQLValueType
+		value sql.TypedValue
+	}{
+		{"integer", sql.IntegerType, sql.NewInteger(-42)},
+		{"varchar", sql.VarcharType, sql.NewVarchar("immudb")},
+		{"empty varchar", sql.VarcharType, sql.NewVarchar("")},
+		{"uuid", sql.UUIDType, sql.NewUUID(uuid.MustParse("a3f2d8a0-3c41-4b9a-8d2e-6f0c1b7e9d55"))},
+		{"boolean", sql.BooleanType, sql.NewBool(true)},
+		{"blob", sql.BLOBType, sql.NewBlob([]byte{0, 1, 2, 255})},
+		{"empty blob", sql.BLOBType, sql.NewBlob([]byte{})},
+		{"timestamp", sql.TimestampType, sql.NewTimestamp(time.Date(2021, 12, 7, 14, 12, 54, 12000, time.UTC))},
+		{"float", sql.Float64Type, sql.NewFloat64(3.25)},
+		{"json", sql.JSONType, jsonVal},
+		{"point", sql.PointType, pointVal},
+		{"null integer", sql.IntegerType, sql.NewNull(sql.IntegerType)},
+		{"null varchar", sql.VarcharType, sql.NewNull(sql.VarcharType)},
+		{"null uuid", sql.UUIDType, sql.NewNull(sql.UUIDType)},
+		{"null boolean", sql.BooleanType, sql.NewNull(sql.BooleanType)},
+		{"null blob", sql.BLOBType, sql.NewNull(sql.BLOBType)},
+		{"null timestamp", sql.TimestampType, sql.NewNull(sql.TimestampType)},
+		{"null float", sql.Float64Type, sql.NewNull(sql.Float64Type)},
+		{"null json", sql.JSONType, sql.NewNull(sql.JSONType)},
+		{"null point", sql.PointType, sql.NewNull(sql.PointType)},
+	} {
+		t.Run(d.n, func(t *testing.T) {
+			encoded, err := proto.Marshal(TypedValueToRowValue(d.value))
+			require.NoError(t, err)
+
+			var sqlVal SQLValue
+			err = proto.Unmarshal(encoded, &sqlVal)
+			require.NoError(t, err)
+
+			v, err := RowValueToTypedValue(&sqlVal, d.t)
+			require.NoError(t, err)
+			require.Equal(t, d.t, v.Type())
+			require.Equal(t, d.value.IsNull(), v.IsNull())
+
+			require.Equal(t, d.value.RawValue(), v.RawValue())
+		})
+	}
+
+	t.Run("mismatching types should fail", func(t *testing.T) {
+		_, err := RowValueToTypedValue(&SQLValue{Value: &SQLValue_N{N: 1}}, sql.VarcharType)
+		require.ErrorIs(t, err, sql.ErrInvalidTypes)
+
+		_, err = RowValueToTypedValue(&SQLValue{Value: &SQLValue_S{S: "not a uuid"}}, sql.UUIDType)
+		require.ErrorIs(t, err, sql.ErrInvalidValue)
+
+		_, err = RowValueToTypedValue(&SQLValue{Value: &SQLValue_S{S: "POINT(91, 0)"}}, sql.PointType)
+		require.ErrorIs(t, err, sql.ErrInvalidValue)
+
+		_, err = RowValueToTypedValue(nil, sql.VarcharType)
+		require.ErrorIs(t, err, sql.ErrInvalidValue)
+	})
+
+	t.Run("untyped columns should keep the wire type", func(t *testing.T) {
+		v, err := RowValueToTypedValue(&SQLValue{Value: &SQLValue_F{F: 1.5}}, sql.AnyType)
+		require.NoError(t, err)
+		require.Equal(t, sql.Float64Type, v.Type())
+	})
+}
+
+func TestRowProtoRoundTrip(t *testing.T) {
+	descriptors := []sql.ColDescriptor{
+		{Table: "table1", Column: "id", Type: sql.IntegerType},
+		{Table: "table1", Column: "title", Type: sql.VarcharType},
+		{Table: "table1", Column: "payload", Type: sql.BLOBType},
+		{AggFn: "COUNT", Table: "table1", Column: "*", Type: sql.IntegerType},
+	}
+
+	row := &sql.Row{
+		ValuesByPosition: []sql.TypedValue{
+			sql.NewInteger(1),
+			sql.NewNull(sql.VarcharType),
+			sql.NewBlob([]byte{1, 2, 3}),
+			sql.NewInteger(10),
+		},
+	}
+
+	encodedCols, err := proto.Marshal(&SQLQueryResult{
+		Columns: ColDescriptorsToProto(descriptors),
+		Rows:    []*Row{RowToProto(descriptors, row)},
+	})
+	require.NoError(t, err)
+
+	var res SQLQueryResult
+	err = proto.Unmarshal(encodedCols, &res)
+	require.NoError(t, err)
+
+	decodedDescriptors, err := ColDescriptorsFromProto(res.Columns)
+	require.NoError(t, err)
+	require.Equal(t, descriptors, decodedDescriptors)
+
+	require.Len(t, res.Rows, 1)
+	require.Equal(t, []string{"(table1.id)", "(table1.title)", "(table1.payload)", "COUNT(table1.*)"}, res.Rows[0].Columns)
+
+	decodedRow, err := RowFromProto(decodedDescriptors, res.Rows[0])
+	require.NoError(t, err)
+	require.Len(t, decodedRow.ValuesByPosition, len(descriptors))
+	require.Equal(t, int64(1), decodedRow.ValuesBySelector["(table1.id)"].RawValue())
+	require.True(t, decodedRow.ValuesBySelector["(table1.title)"].IsNull())
+	require.Equal(t, []byte{1, 2, 3}, decodedRow.ValuesBySelector["(table1.payload)"].RawValue())
+	require.Equal(t, int64(10), decodedRow.ValuesBySelector["COUNT(table1.*)"].RawValue())
+
+	_, err = RowFromProto(decodedDescriptors[:2], res.Rows[0])
+	require.ErrorIs(t, err, sql.ErrInvalidNumberOfValues)
+
+	_, err = ColDescriptorsFromProto([]*Column{{Name: "id", Type: sql.IntegerType}})
+	require.ErrorIs(t, err, sql.ErrIllegalArguments)
+}
+
+func TestRowProtoForwardCompatibility(t *testing.T) {
+	// a value of a type unknown to this version, i.e. encoded using an unused field number
+	var unknownValue []byte
+	unknownValue = protowire.AppendTag(unknownValue, 100, protowire.BytesType)
+	unknownValue = protowire.AppendBytes(unknownValue, []byte{1, 2, 3})
+
+	var sqlVal SQLValue
+	err := proto.Unmarshal(unknownValue, &sqlVal)
+	require.NoError(t, err)
+	require.Nil(t, sqlVal.Value)
+
+	_, err = RowValueToTypedValue(&sqlVal, sql.VarcharType)
+	require.ErrorIs(t, err, sql.ErrInvalidValue)
+
+	// a row including both the unknown value and an additional unknown field
+	knownValue, err := proto.Marshal(&SQLValue{Value: &SQLValue_S{S: "immudb"}})
+	require.NoError(t, err)
+
+	var encodedRow []byte
+	encodedRow = protowire.AppendTag(encodedRow, 1, protowire.BytesType)
+	encodedRow = protowire.AppendString(encodedRow, "(table1.title)")
+	encodedRow = protowire.AppendTag(encodedRow, 2, protowire.BytesType)
+	encodedRow = protowire.AppendBytes(encodedRow, knownValue)
+	encodedRow = protowire.AppendTag(encodedRow, 100, protowire.VarintType)
+	encodedRow = protowire.AppendVarint(encodedRow, 1)
+
+	var r Row
+	err = proto.Unmarshal(encodedRow, &r)
+	require.NoError(t, err)
+
+	descriptors := []sql.ColDescriptor{{Table: "table1", Column: "title", Type: sql.VarcharType}}
+
+	row, err := RowFromProto(descriptors, &r)
+	require.NoError(t, err)
+	require.Equal(t, "immudb", row.ValuesByPosition[0].RawValue())
+
+	r.Values[0] = &sqlVal
+
+	_, err = RowFromProto(descriptors, &r)
+	require.ErrorIs(t, err, sql.ErrInvalidValue)
+}
@@ -72,6 +72,12 @@ func DataRow(rows []*sql.Row, colNumb int, ResultColumnFormatCodes []int16) []by
 							binary.BigEndian.PutUint32(valueLength, uint32(len(jsonStr)))
 							value = []byte(jsonStr)
 						}
+					case sql.PointType:
+						{
+							s := val.String()
+							binary.BigEndian.PutUint32(valueLength, uint32(len(s)))
+							value = []byte(s)
+						}
 					case sql.VarcharType:
 						{
 							s := rv.(string)
@@ -155,7 +155,7 @@ func (s *ImmuServer) streamRows(ctx context.Context, reader sql.RowReader, batch
 
 	rows := make([]*schema.Row, batchSize)
 
-	cols := descriptorsToProtoColumns(descriptors)
+	cols := schema.ColDescriptorsToProto(descriptors)
 
 	columnsSent := false
 	err = sql.ReadRowsBatch(ctx, reader, batchSize, func(rowBatch []*sql.Row) error {
@@ -177,37 +177,13 @@ func (s *ImmuServer) streamRows(ctx context.Context, reader sql.RowReader, batch
 	return err
 }
 
-func descriptorsToProtoColumns(descriptors []sql.ColDescriptor) []*schema.Column {
-	cols := make([]*schema.Column, len(descriptors))
-	for i, des := range descriptors {
-		cols[i] = &schema.Column{Name: des.Selector(), Type: des.Type}
-	}
-	return cols
-}
-
 func sqlRowsToProto(descriptors []sql.ColDescriptor, rows []*sql.Row, outRows []*schema.Row) []*schema.Row {
 	if len(rows) == 0 {
 		return nil
 	}
 
 	for i, sqlRow := range rows {
-		row := &schema.Row{
-			Columns: make([]string, len(descriptors)),
-			Values:  make([]*schema.SQLValue, len(descriptors)),
-		}
-
-		for i := range descriptors {
-			row.Columns[i] = descriptors[i].Selector()
-
-			v := sqlRow.ValuesByPosition[i]
-			_, isNull := v.(*sql.NullValue)
-			if isNull {
-				row.Values[i] = &schema.SQLValue{Value: &schema.SQLValue_Null{}}
-			} else {
-				row.Values[i] = schema.TypedValueToRowValue(v)
-			}
-		}
-		outRows[i] = row
+		outRows[i] = schema.RowToProto(descriptors, sqlRow)
 	}
 	return outRows[:len(rows)]
 }
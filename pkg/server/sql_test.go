@@ -18,7 +18,11 @@ package server
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/codenotary/immudb/embedded/sql"
 	"github.com/codenotary/immudb/pkg/api/schema"
@@ -206,3 +210,141 @@ func (s *ImmuService_SQLQueryServerMock) Send(res *schema.SQLQueryResult) error
 func (s *ImmuService_SQLQueryServerMock) Context() context.Context {
 	return s.ctx
 }
+
+type countingRowReader struct {
+	sql.RowReader
+	reads  int
+	closed bool
+}
+
+func (r *countingRowReader) Read(ctx context.Context) (*sql.Row, error) {
+	row, err := r.RowReader.Read(ctx)
+	if err == nil {
+		r.reads++
+	}
+	return row, err
+}
+
+func (r *countingRowReader) Close() error {
+	r.closed = true
+	return r.RowReader.Close()
+}
+
+func TestSQLQueryStreaming(t *testing.T) {
+	dir := t.TempDir()
+
+	serverOptions := DefaultOptions().
+		WithDir(dir).
+		WithMetricsServer(false)
+
+	s := DefaultServer().WithOptions(serverOptions).(*ImmuServer)
+
+	s.Initialize()
+
+	r := &schema.LoginRequest{
+		User:     []byte(auth.SysAdminUsername),
+		Password: []byte(auth.SysAdminPassword),
+	}
+
+	lr, err := s.Login(context.Background(), r)
+	require.NoError(t, err)
+
+	md := metadata.Pairs("authorization", lr.Token)
+	ctx := metadata.NewIncomingContext(context.Background(), md)
+
+	_, err = s.SQLExec(ctx, &schema.SQLExecRequest{Sql: "CREATE TABLE table1 (id INTEGER AUTO_INCREMENT, title VARCHAR, PRIMARY KEY id)"})
+	require.NoError(t, err)
+
+	const numRows = 100
+
+	values := make([]string, numRows)
+	for i := range values {
+		values[i] = fmt.Sprintf("('title%d')", i)
+	}
+
+	_, err = s.SQLExec(ctx, &schema.SQLExecRequest{Sql: "INSERT INTO table1 (title) VALUES " + strings.Join(values, ",")})
+	require.NoError(t, err)
+
+	db, err := s.getDBFromCtx(ctx, "SQLQuery")
+	require.NoError(t, err)
+
+	newReader := func(t *testing.T, ctx context.Context) *countingRowReader {
+		tx, err := db.NewSQLTx(ctx, sql.DefaultTxOptions().WithReadOnly(true))
+		require.NoError(t, err)
+		t.Cleanup(func() { tx.Cancel() })
+
+		reader, err := db.SQLQuery(ctx, tx, &schema.SQLQueryRequest{Sql: "SELECT id, title FROM table1 WHERE id > 0", AcceptStream: true})
+		require.NoError(t, err)
+
+		return &countingRowReader{RowReader: reader}
+	}
+
+	t.Run("a slow client should pause the reader", func(t *testing.T) {
+		const batchSize = 10
+
+		reader := newReader(t, ctx)
+		defer reader.Close()
+
+		sentRows := 0
+
+		err := s.streamRows(ctx, reader, batchSize, func(res *schema.SQLQueryResult) error {
+			if sentRows == 0 {
+				require.Len(t, res.Columns, 2)
+			} else {
+				require.Empty(t, res.Columns)
+			}
+
+			sentRows += len(res.Rows)
+
+			// rows are not read ahead of what the client already received
+			require.Equal(t, sentRows, reader.reads)
+
+			time.Sleep(time.Millisecond)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Equal(t, numRows, sentRows)
+	})
+
+	t.Run("cancellation should propagate to the reader", func(t *testing.T) {
+		cancellableCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		reader := newReader(t, cancellableCtx)
+
+		batches := 0
+
+		err := s.streamRows(cancellableCtx, reader, 10, func(res *schema.SQLQueryResult) error {
+			batches++
+			if batches == 2 {
+				cancel()
+			}
+			return nil
+		})
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, 2, batches)
+		require.Equal(t, 20, reader.reads)
+
+		err = reader.Close()
+		require.NoError(t, err)
+		require.True(t, reader.closed)
+	})
+
+	t.Run("a failure sending rows should stop the query", func(t *testing.T) {
+		cancellableCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		errSend := errors.New("send error")
+		sends := 0
+
+		err := s.SQLQuery(&schema.SQLQueryRequest{Sql: "SELECT id, title FROM table1", AcceptStream: true}, &ImmuService_SQLQueryServerMock{
+			ctx: cancellableCtx,
+			sendFunc: func(sr *schema.SQLQueryResult) error {
+				sends++
+				return errSend
+			},
+		})
+		require.ErrorIs(t, err, errSend)
+		require.Equal(t, 1, sends)
+	})
+}